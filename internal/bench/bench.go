@@ -0,0 +1,450 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bench runs ingestion, contract generation and alignment on real inputs and reports
+// throughput, memory high-water mark and per-stage latencies (flowspec-cli bench), so
+// performance regressions can be tracked in CI
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/metrics"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/engine"
+	"github.com/flowspec/flowspec-cli/internal/ingestor"
+	"github.com/flowspec/flowspec-cli/internal/ingestor/traffic"
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/parser"
+)
+
+// Stage names, in pipeline order
+const (
+	StageIngest   = "ingest"
+	StageGenerate = "generate"
+	StageLoad     = "load"
+	StageAlign    = "align"
+)
+
+// heapSampleInterval is how often the live heap is sampled for the high-water mark
+const heapSampleInterval = 10 * time.Millisecond
+
+// heapMetric is the runtime metric sampled for the high-water mark
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// Options configures a benchmark run
+type Options struct {
+	Traffic    []string                  // Access log files to ingest; required
+	SpecPath   string                    // Contracts to align; the generated contract when empty
+	TracePath  string                    // Trace to align; built from the traffic records when empty
+	Iterations int                       // Runs of the whole pipeline, 1 when zero
+	Ingest     *traffic.IngestOptions    // DefaultIngestOptions when nil
+	Generation *engine.GenerationOptions // DefaultGenerationOptions when nil
+	Engine     *engine.EngineConfig      // DefaultEngineConfig when nil
+}
+
+// Stage is the measurement of one pipeline stage over all iterations
+type Stage struct {
+	Name           string  `json:"name"`
+	MedianMs       float64 `json:"medianMs"`
+	MinMs          float64 `json:"minMs"`
+	MaxMs          float64 `json:"maxMs"`
+	Items          int64   `json:"items"`                    // Items handled by one iteration
+	Unit           string  `json:"unit"`                     // What Items counts, e.g. records or spans
+	ItemsPerSecond float64 `json:"itemsPerSecond"`           // Items over the median duration
+	Bytes          int64   `json:"bytes,omitempty"`          // Input bytes read by one iteration
+	BytesPerSecond float64 `json:"bytesPerSecond,omitempty"` // Bytes over the median duration
+	PeakHeapBytes  uint64  `json:"peakHeapBytes"`            // Live heap high-water mark during the stage
+}
+
+// Result is the JSON report of a benchmark run
+type Result struct {
+	GoVersion     string  `json:"goVersion"`
+	OS            string  `json:"os"`
+	Arch          string  `json:"arch"`
+	CPUs          int     `json:"cpus"`
+	Iterations    int     `json:"iterations"`
+	Stages        []Stage `json:"stages"`
+	TotalMs       float64 `json:"totalMs"`       // Median duration of a whole iteration
+	PeakHeapBytes uint64  `json:"peakHeapBytes"` // Live heap high-water mark of the run
+	Specs         int     `json:"specs"`
+	Operations    int     `json:"operations"`
+	Spans         int     `json:"spans"`
+}
+
+// Run benchmarks the pipeline on the inputs of options
+func Run(ctx context.Context, options Options) (*Result, error) {
+	if len(options.Traffic) == 0 {
+		return nil, fmt.Errorf("at least one traffic file is required")
+	}
+	iterations := options.Iterations
+	if iterations <= 0 {
+		iterations = 1
+	}
+
+	var trafficBytes int64
+	for _, input := range options.Traffic {
+		info, err := os.Stat(input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read traffic file: %w", err)
+		}
+		trafficBytes += info.Size()
+	}
+
+	sampler := startHeapSampler()
+	defer sampler.stop()
+
+	result := &Result{
+		GoVersion:  runtime.Version(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		CPUs:       runtime.NumCPU(),
+		Iterations: iterations,
+	}
+	durations := make(map[string][]time.Duration)
+	stages := make(map[string]*Stage)
+	var order []string
+	var totals []time.Duration
+
+	measure := func(name, unit string, bytes int64, step func() (int64, error)) error {
+		sampler.reset()
+		started := time.Now()
+		items, err := step()
+		elapsed := time.Since(started)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		stage, ok := stages[name]
+		if !ok {
+			stage = &Stage{Name: name, Unit: unit, Bytes: bytes}
+			stages[name] = stage
+			order = append(order, name)
+		}
+		stage.Items = items
+		if peak := sampler.peak(); peak > stage.PeakHeapBytes {
+			stage.PeakHeapBytes = peak
+		}
+		durations[name] = append(durations[name], elapsed)
+		return nil
+	}
+
+	for iteration := 0; iteration < iterations; iteration++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// Start every iteration from a collected heap so earlier iterations do not skew it
+		runtime.GC()
+		started := time.Now()
+
+		var records []*traffic.NormalizedRecord
+		err := measure(StageIngest, "records", trafficBytes, func() (int64, error) {
+			var err error
+			records, err = ingest(options)
+			return int64(len(records)), err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var generated *models.ServiceSpec
+		err = measure(StageGenerate, "records", 0, func() (int64, error) {
+			generator := engine.NewContractGeneratorLite()
+			if options.Generation != nil {
+				generator.SetOptions(options.Generation)
+			}
+			var err error
+			generated, err = generator.GenerateSpecContext(ctx, ingestor.NewSliceIterator(records))
+			return int64(len(records)), err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		specs := []models.ServiceSpec{*generated}
+		traceData := traceFromRecords(records)
+		if options.SpecPath != "" || options.TracePath != "" {
+			err = measure(StageLoad, "spans", 0, func() (int64, error) {
+				loadedSpecs, loadedTrace, err := load(options, specs, traceData)
+				if err != nil {
+					return 0, err
+				}
+				specs, traceData = loadedSpecs, loadedTrace
+				return int64(len(traceData.Spans)), nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err = measure(StageAlign, "spans", 0, func() (int64, error) {
+			return align(ctx, options, specs, traceData)
+		})
+		if err != nil {
+			return nil, err
+		}
+		totals = append(totals, time.Since(started))
+
+		result.Specs = len(specs)
+		result.Operations = countOperations(specs)
+		result.Spans = len(traceData.Spans)
+	}
+
+	for _, name := range order {
+		stage := stages[name]
+		median, minimum, maximum := summarize(durations[name])
+		stage.MedianMs, stage.MinMs, stage.MaxMs = milliseconds(median), milliseconds(minimum), milliseconds(maximum)
+		if median > 0 {
+			stage.ItemsPerSecond = float64(stage.Items) / median.Seconds()
+			stage.BytesPerSecond = float64(stage.Bytes) / median.Seconds()
+		}
+		result.Stages = append(result.Stages, *stage)
+	}
+	total, _, _ := summarize(totals)
+	result.TotalMs = milliseconds(total)
+	result.PeakHeapBytes = sampler.overall()
+	return result, nil
+}
+
+// WriteJSON writes the result as indented JSON
+func WriteJSON(w io.Writer, result *Result) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// ingest reads all traffic records into memory
+func ingest(options Options) ([]*traffic.NormalizedRecord, error) {
+	ingestOptions := options.Ingest
+	if ingestOptions == nil {
+		ingestOptions = traffic.DefaultIngestOptions()
+	}
+	trafficIngestor, err := traffic.NewIngestor(ingestOptions.Format)
+	if err != nil {
+		return nil, err
+	}
+	defer trafficIngestor.Close()
+
+	iterator, err := trafficIngestor.Ingest(options.Traffic, ingestOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
+	var records []*traffic.NormalizedRecord
+	for iterator.Next() {
+		records = append(records, iterator.Value())
+	}
+	if err := iterator.Err(); err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no records could be parsed from the traffic files")
+	}
+	return records, nil
+}
+
+// load replaces the generated contract and the trace built from the traffic with the files
+// given in options
+func load(options Options, specs []models.ServiceSpec, traceData *models.TraceData) ([]models.ServiceSpec, *models.TraceData, error) {
+	if options.SpecPath != "" {
+		parseResult, err := parser.NewSpecParser().ParseFromSource(options.SpecPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse contracts from %s: %w", options.SpecPath, err)
+		}
+		if len(parseResult.Errors) > 0 {
+			return nil, nil, fmt.Errorf("failed to parse contracts from %s: %s", options.SpecPath, parseResult.Errors[0].Message)
+		}
+		specs = parseResult.Specs
+	}
+	if options.TracePath != "" {
+		var err error
+		traceData, err = ingestor.NewTraceIngestor().IngestFromFile(options.TracePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load trace from %s: %w", options.TracePath, err)
+		}
+	}
+	return specs, traceData, nil
+}
+
+// align aligns specs with the trace and returns the number of spans
+func align(ctx context.Context, options Options, specs []models.ServiceSpec, traceData *models.TraceData) (int64, error) {
+	config := options.Engine
+	if config == nil {
+		config = engine.DefaultEngineConfig()
+	}
+	alignmentEngine := engine.NewAlignmentEngineWithConfig(config)
+	defer alignmentEngine.Close()
+
+	if _, err := alignmentEngine.AlignSpecsWithTraceContext(ctx, specs, traceData); err != nil {
+		return 0, err
+	}
+	return int64(len(traceData.Spans)), nil
+}
+
+// traceFromRecords turns every traffic record into a server span under one root span, so the
+// generated contract can be aligned without a trace file
+func traceFromRecords(records []*traffic.NormalizedRecord) *models.TraceData {
+	const traceID = "0000000000000000000000000000bead"
+	root := &models.Span{
+		SpanID:  "0000000000000000",
+		TraceID: traceID,
+		Name:    "flowspec bench",
+		Status:  models.SpanStatus{Code: "OK"},
+	}
+	traceData := &models.TraceData{
+		TraceID:  traceID,
+		RootSpan: root,
+		Spans:    make(map[string]*models.Span, len(records)+1),
+		SpanTree: &models.SpanNode{Span: root},
+	}
+	traceData.Spans[root.SpanID] = root
+
+	for i, record := range records {
+		status := models.SpanStatus{Code: "OK"}
+		if record.Status >= 500 {
+			status.Code = "ERROR"
+		}
+		span := &models.Span{
+			SpanID:    fmt.Sprintf("%016x", i+1),
+			TraceID:   traceID,
+			ParentID:  root.SpanID,
+			Name:      record.Method + " " + record.Path,
+			StartTime: record.Timestamp.UnixNano(),
+			EndTime:   record.Timestamp.UnixNano(),
+			Status:    status,
+			Attributes: map[string]interface{}{
+				"http.method":      record.Method,
+				"http.target":      record.Path,
+				"http.status_code": record.Status,
+				"span.kind":        "server",
+			},
+		}
+		traceData.Spans[span.SpanID] = span
+		traceData.SpanTree.Children = append(traceData.SpanTree.Children, &models.SpanNode{Span: span})
+	}
+	return traceData
+}
+
+func countOperations(specs []models.ServiceSpec) int {
+	count := 0
+	for _, spec := range specs {
+		if spec.Spec == nil {
+			count++ // A legacy spec is a single operation
+			continue
+		}
+		for _, endpoint := range spec.Spec.Endpoints {
+			count += len(endpoint.Operations)
+		}
+	}
+	return count
+}
+
+// summarize returns the median, minimum and maximum of durations
+func summarize(durations []time.Duration) (time.Duration, time.Duration, time.Duration) {
+	if len(durations) == 0 {
+		return 0, 0, 0
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+	return median, sorted[0], sorted[len(sorted)-1]
+}
+
+func milliseconds(duration time.Duration) float64 {
+	return float64(duration.Microseconds()) / 1000
+}
+
+// heapSampler tracks the live heap high-water mark, overall and since the last reset
+type heapSampler struct {
+	mu         sync.Mutex
+	stagePeak  uint64
+	overallMax uint64
+	done       chan struct{}
+	wg         sync.WaitGroup
+}
+
+func startHeapSampler() *heapSampler {
+	sampler := &heapSampler{done: make(chan struct{})}
+	sampler.sample()
+	sampler.wg.Add(1)
+	go func() {
+		defer sampler.wg.Done()
+		ticker := time.NewTicker(heapSampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				sampler.sample()
+			case <-sampler.done:
+				return
+			}
+		}
+	}()
+	return sampler
+}
+
+// sample reads the live heap without stopping the world
+func (s *heapSampler) sample() {
+	samples := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(samples)
+	if samples[0].Value.Kind() != metrics.KindUint64 {
+		return
+	}
+	heap := samples[0].Value.Uint64()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if heap > s.stagePeak {
+		s.stagePeak = heap
+	}
+	if heap > s.overallMax {
+		s.overallMax = heap
+	}
+}
+
+func (s *heapSampler) reset() {
+	s.mu.Lock()
+	s.stagePeak = 0
+	s.mu.Unlock()
+	s.sample()
+}
+
+// peak returns the high-water mark since the last reset, including the current heap
+func (s *heapSampler) peak() uint64 {
+	s.sample()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stagePeak
+}
+
+func (s *heapSampler) overall() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.overallMax
+}
+
+func (s *heapSampler) stop() {
+	close(s.done)
+	s.wg.Wait()
+}
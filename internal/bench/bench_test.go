@@ -0,0 +1,130 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const contract = `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: users
+  version: v1.0.0
+spec:
+  endpoints:
+    - path: /api/users/{id}
+      operations:
+        - method: GET
+          responses:
+            statusCodes: [200]
+`
+
+func writeTraffic(t *testing.T, dir string) string {
+	var content strings.Builder
+	for i := 0; i < 200; i++ {
+		content.WriteString(fmt.Sprintf(`10.0.0.1 - - [10/Aug/2025:12:%02d:%02d +0000] "GET /api/users/%d HTTP/1.1" 200 512 "-" "curl/7.68.0"`+"\n", i/60, i%60, i))
+	}
+	path := filepath.Join(dir, "access.log")
+	require.NoError(t, os.WriteFile(path, []byte(content.String()), 0644))
+	return path
+}
+
+func stageNames(stages []Stage) []string {
+	names := make([]string, 0, len(stages))
+	for _, stage := range stages {
+		names = append(names, stage.Name)
+	}
+	return names
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	trafficPath := writeTraffic(t, dir)
+
+	result, err := Run(context.Background(), Options{Traffic: []string{trafficPath}, Iterations: 3})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.Iterations)
+	assert.Equal(t, []string{StageIngest, StageGenerate, StageAlign}, stageNames(result.Stages))
+	assert.Equal(t, 1, result.Specs)
+	assert.Equal(t, 201, result.Spans, "one span per record under a root span")
+
+	ingest := result.Stages[0]
+	assert.Equal(t, int64(200), ingest.Items)
+	assert.Equal(t, "records", ingest.Unit)
+	info, err := os.Stat(trafficPath)
+	require.NoError(t, err)
+	assert.Equal(t, info.Size(), ingest.Bytes)
+	for _, stage := range result.Stages {
+		assert.LessOrEqual(t, stage.MinMs, stage.MedianMs, stage.Name)
+		assert.LessOrEqual(t, stage.MedianMs, stage.MaxMs, stage.Name)
+		assert.Positive(t, stage.PeakHeapBytes, stage.Name)
+	}
+	assert.Positive(t, result.PeakHeapBytes)
+
+	var output bytes.Buffer
+	require.NoError(t, WriteJSON(&output, result))
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(output.Bytes(), &decoded))
+	assert.Contains(t, decoded, "peakHeapBytes")
+	assert.Contains(t, decoded, "stages")
+}
+
+func TestRun_WithContracts(t *testing.T) {
+	dir := t.TempDir()
+	trafficPath := writeTraffic(t, dir)
+	specPath := filepath.Join(dir, "users.yaml")
+	require.NoError(t, os.WriteFile(specPath, []byte(contract), 0644))
+
+	result, err := Run(context.Background(), Options{Traffic: []string{trafficPath}, SpecPath: specPath})
+	require.NoError(t, err)
+	assert.Equal(t, []string{StageIngest, StageGenerate, StageLoad, StageAlign}, stageNames(result.Stages))
+	assert.Equal(t, 1, result.Operations)
+}
+
+func TestRun_Errors(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.log")
+	require.NoError(t, os.WriteFile(empty, []byte("not an access log\n"), 0644))
+
+	testCases := []struct {
+		name      string
+		options   Options
+		errorText string
+	}{
+		{"no traffic", Options{}, "at least one traffic file is required"},
+		{"missing traffic", Options{Traffic: []string{filepath.Join(dir, "missing.log")}}, "failed to read traffic file"},
+		{"no records", Options{Traffic: []string{empty}}, "ingest: no records could be parsed"},
+		{"missing trace", Options{Traffic: []string{writeTraffic(t, dir)}, TracePath: filepath.Join(dir, "missing.json")}, "load: failed to load trace"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Run(context.Background(), tc.options)
+			assert.ErrorContains(t, err, tc.errorText)
+		})
+	}
+}
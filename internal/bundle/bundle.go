@@ -0,0 +1,521 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bundle packages contracts, trace fixtures and the project configuration into one
+// archive, so verification runs on an air-gapped machine without fetching anything. Every file
+// of a bundle is listed with its checksum in a manifest that is checked when it is opened.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/config"
+	"github.com/flowspec/flowspec-cli/internal/errcode"
+	"github.com/flowspec/flowspec-cli/internal/parser"
+	"github.com/flowspec/flowspec-cli/internal/remote"
+)
+
+// FormatVersion is the version of the bundle layout; Open refuses other major versions
+const FormatVersion = "1"
+
+// ManifestName is the name of the manifest at the root of a bundle
+const ManifestName = "flowspec-bundle.json"
+
+// Directories of the bundled contracts and traces, and name of the bundled configuration
+const (
+	contractsDir = "contracts"
+	tracesDir    = "traces"
+	configName   = ".flowspec.yaml"
+)
+
+// maxManifestBytes is the largest manifest that is read
+const maxManifestBytes = 16 << 20
+
+// ErrInvalidBundle is matched by errors.Is when an archive is not a bundle, or its content does
+// not match its manifest
+var ErrInvalidBundle = errors.New("invalid bundle")
+
+// Options configures what is bundled
+type Options struct {
+	Config     string         // Project configuration file; its path and trace are bundled unless set below
+	Profile    string         // Profile of the configuration whose path and trace are bundled
+	Contracts  string         // Contract file or directory, or a remote contract, which is fetched into the bundle
+	Trace      string         // Trace file or directory of trace files, such as recorded fixtures
+	Remote     remote.Options // How a remote contract is fetched; the cache serves it in offline mode
+	CLIVersion string         // Version of the CLI creating the bundle, recorded in the manifest
+}
+
+// Manifest describes the content of a bundle
+type Manifest struct {
+	FormatVersion  string    `json:"formatVersion"`
+	CreatedAt      time.Time `json:"createdAt"`
+	CLIVersion     string    `json:"cliVersion,omitempty"`
+	Config         string    `json:"config,omitempty"`         // Bundled configuration file
+	Contracts      string    `json:"contracts"`                // Bundled contracts, for --path
+	Trace          string    `json:"trace,omitempty"`          // Bundled trace file or directory, for --trace
+	ContractSource string    `json:"contractSource,omitempty"` // Remote contract the contracts were fetched from
+	Files          []File    `json:"files"`
+}
+
+// File is a file of a bundle
+type File struct {
+	Path     string `json:"path"` // Slash-separated path in the bundle
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"` // "sha256:<hex>"
+}
+
+// Result describes a created bundle
+type Result struct {
+	Path     string    `json:"path"`  // Archive written
+	Bytes    int64     `json:"bytes"` // Size of the archive
+	Manifest *Manifest `json:"manifest"`
+}
+
+// FormatHuman renders the bundle as human-readable text
+func (r *Result) FormatHuman() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Bundled %d file(s) into %s (%d bytes)\n", len(r.Manifest.Files), r.Path, r.Bytes)
+	fmt.Fprintf(&builder, "  contracts: %s", r.Manifest.Contracts)
+	if r.Manifest.ContractSource != "" {
+		fmt.Fprintf(&builder, " (fetched from %s)", r.Manifest.ContractSource)
+	}
+	builder.WriteString("\n")
+	if r.Manifest.Trace != "" {
+		fmt.Fprintf(&builder, "  trace: %s\n", r.Manifest.Trace)
+	}
+	if r.Manifest.Config != "" {
+		fmt.Fprintf(&builder, "  config: %s\n", r.Manifest.Config)
+	}
+	return builder.String()
+}
+
+// entry is a file to bundle
+type entry struct {
+	source string // Local file
+	path   string // Slash-separated path in the bundle
+}
+
+// Create writes a bundle of the contracts, the trace and the configuration to archivePath as a
+// gzip-compressed tar archive. A remote contract is fetched, or taken from the cache in offline
+// mode, so the bundle never needs the network.
+func Create(ctx context.Context, archivePath string, options Options) (*Result, error) {
+	manifest := &Manifest{FormatVersion: FormatVersion, CreatedAt: time.Now().UTC().Truncate(time.Second), CLIVersion: options.CLIVersion}
+	var entries []entry
+
+	contracts, trace := options.Contracts, options.Trace
+	if options.Config != "" {
+		cfg, err := config.LoadProfile(options.Config, options.Profile)
+		if err != nil {
+			return nil, err
+		}
+		if contracts == "" {
+			contracts = cfg.Path
+		}
+		if trace == "" {
+			trace = cfg.Trace
+		}
+		manifest.Config = configName
+		entries = append(entries, entry{source: options.Config, path: configName})
+	}
+	if contracts == "" {
+		return nil, fmt.Errorf("no contracts to bundle: set the contract path or a configuration file with a path")
+	}
+
+	if remote.IsRemote(contracts) {
+		result, err := remote.Fetch(ctx, contracts, options.Remote)
+		if err != nil {
+			return nil, err
+		}
+		manifest.ContractSource = result.Source
+		contracts = result.Path
+	}
+	contractEntries, bundled, err := collectContracts(contracts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bundle contracts: %w", err)
+	}
+	manifest.Contracts = bundled
+	entries = append(entries, contractEntries...)
+
+	if trace != "" {
+		traceEntries, bundled, err := collect(trace, tracesDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to bundle trace: %w", err)
+		}
+		manifest.Trace = bundled
+		entries = append(entries, traceEntries...)
+	}
+
+	if err := writeArchive(archivePath, manifest, entries); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return &Result{Path: archivePath, Bytes: info.Size(), Manifest: manifest}, nil
+}
+
+// collect lists the regular files of a file or directory, to be bundled under dir, and returns
+// the path of the file or directory in the bundle
+func collect(source, dir string) ([]entry, string, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, "", err
+	}
+	root := path.Join(dir, filepath.Base(filepath.Clean(source)))
+	if !info.IsDir() {
+		return []entry{{source: source, path: root}}, root, nil
+	}
+
+	var entries []entry
+	err = filepath.WalkDir(source, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		relative, err := filepath.Rel(source, file)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry{source: file, path: path.Join(root, filepath.ToSlash(relative))})
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if len(entries) == 0 {
+		return nil, "", fmt.Errorf("%s has no files", source)
+	}
+	return entries, root, nil
+}
+
+// collectContracts lists the contract files like collect, along with the files outside source
+// that their YAML contracts pull in with $ref and include. All of them are bundled at their path
+// relative to the closest directory holding them all, so relative references stay valid.
+func collectContracts(source string) ([]entry, string, error) {
+	absSource, err := filepath.Abs(source)
+	if err != nil {
+		return nil, "", err
+	}
+	entries, bundled, err := collect(absSource, contractsDir)
+	if err != nil {
+		return nil, "", err
+	}
+
+	listed := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		listed[e.source] = true
+	}
+	var referenced []string
+	for _, e := range entries {
+		switch strings.ToLower(filepath.Ext(e.source)) {
+		case ".yaml", ".yml":
+		default:
+			continue
+		}
+		// A contract whose references do not resolve is bundled on its own; parsing it
+		// reports the broken reference
+		targets, err := parser.ReferencedFiles(e.source)
+		if err != nil {
+			continue
+		}
+		for _, target := range targets {
+			if !listed[target] {
+				listed[target] = true
+				referenced = append(referenced, target)
+			}
+		}
+	}
+	if len(referenced) == 0 {
+		return entries, bundled, nil
+	}
+
+	base := filepath.Dir(absSource)
+	for _, file := range referenced {
+		for !within(base, file) && filepath.Dir(base) != base {
+			base = filepath.Dir(base)
+		}
+	}
+	sort.Strings(referenced)
+	for _, file := range referenced {
+		entries = append(entries, entry{source: file})
+	}
+	for i := range entries {
+		relative, err := filepath.Rel(base, entries[i].source)
+		if err != nil {
+			return nil, "", err
+		}
+		entries[i].path = path.Join(contractsDir, filepath.ToSlash(relative))
+	}
+	relative, err := filepath.Rel(base, absSource)
+	if err != nil {
+		return nil, "", err
+	}
+	return entries, path.Join(contractsDir, filepath.ToSlash(relative)), nil
+}
+
+// within reports whether file is inside dir
+func within(dir, file string) bool {
+	relative, err := filepath.Rel(dir, file)
+	return err == nil && filepath.IsLocal(relative)
+}
+
+// writeArchive writes the manifest and the files into a new archive, which replaces archivePath
+// only once it is complete
+func writeArchive(archivePath string, manifest *Manifest, entries []entry) error {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].path < entries[j].path })
+	contents := make([][]byte, len(entries))
+	for i, e := range entries {
+		content, err := os.ReadFile(e.source)
+		if err != nil {
+			return fmt.Errorf("failed to bundle %s: %w", e.source, err)
+		}
+		contents[i] = content
+		manifest.Files = append(manifest.Files, File{Path: e.path, Size: int64(len(content)), Checksum: checksum(content)})
+	}
+	manifestContent, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bundle manifest: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+		return fmt.Errorf("failed to create bundle directory: %w", err)
+	}
+	temp, err := os.CreateTemp(filepath.Dir(archivePath), ".bundle-*")
+	if err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	gzipWriter := gzip.NewWriter(temp)
+	tarWriter := tar.NewWriter(gzipWriter)
+	write := func(name string, content []byte) error {
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), ModTime: manifest.CreatedAt, Typeflag: tar.TypeReg}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := tarWriter.Write(content)
+		return err
+	}
+	err = write(ManifestName, manifestContent)
+	for i := 0; err == nil && i < len(entries); i++ {
+		err = write(entries[i].path, contents[i])
+	}
+	if err == nil {
+		err = tarWriter.Close()
+	}
+	if err == nil {
+		err = gzipWriter.Close()
+	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := os.Rename(temp.Name(), archivePath); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
+
+// Bundle is an extracted bundle
+type Bundle struct {
+	Dir      string // Directory the bundle was extracted into
+	Manifest *Manifest
+}
+
+// Open extracts a bundle into dir, which is created when missing, and checks every file
+// against the manifest: a file that is missing, unlisted, outside the bundle or with another
+// checksum fails with ErrInvalidBundle. Failures have the code errcode.BundleInvalid.
+func Open(archivePath, dir string) (*Bundle, error) {
+	b, err := open(archivePath, dir)
+	if err != nil {
+		return nil, errcode.Wrap(err, errcode.BundleInvalid)
+	}
+	return b, nil
+}
+
+// open extracts and checks a bundle
+func open(archivePath, dir string) (*Bundle, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s is not a gzip archive: %v", ErrInvalidBundle, archivePath, err)
+	}
+	defer gzipReader.Close()
+	reader := tar.NewReader(gzipReader)
+
+	header, err := reader.Next()
+	if err != nil || header.Name != ManifestName {
+		return nil, fmt.Errorf("%w: %s does not start with %s", ErrInvalidBundle, archivePath, ManifestName)
+	}
+	content, err := io.ReadAll(io.LimitReader(reader, maxManifestBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle manifest: %w", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: invalid manifest: %v", ErrInvalidBundle, err)
+	}
+	if major, _, _ := strings.Cut(manifest.FormatVersion, "."); major != FormatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %q, expected %s.x", ErrInvalidBundle, manifest.FormatVersion, FormatVersion)
+	}
+
+	listed := make(map[string]File, len(manifest.Files))
+	for _, f := range manifest.Files {
+		if !isLocal(f.Path) {
+			return nil, fmt.Errorf("%w: manifest lists %q outside the bundle", ErrInvalidBundle, f.Path)
+		}
+		listed[f.Path] = f
+	}
+	// The paths verify reads must name files the checksums cover
+	for _, named := range []struct {
+		field, value string
+		optional     bool
+	}{
+		{"contracts", manifest.Contracts, false},
+		{"trace", manifest.Trace, true},
+		{"config", manifest.Config, true},
+	} {
+		if named.value == "" && named.optional {
+			continue
+		}
+		if !isLocal(named.value) || !coversListed(named.value, listed) {
+			return nil, fmt.Errorf("%w: manifest %s %q is not a file or directory of the bundle", ErrInvalidBundle, named.field, named.value)
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create bundle directory: %w", err)
+	}
+
+	extracted := make(map[string]bool, len(listed))
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		expected, ok := listed[header.Name]
+		if !ok || header.Typeflag != tar.TypeReg || extracted[header.Name] {
+			return nil, fmt.Errorf("%w: %q is not listed in the manifest", ErrInvalidBundle, header.Name)
+		}
+		if err := extract(reader, filepath.Join(dir, filepath.FromSlash(header.Name)), expected); err != nil {
+			return nil, err
+		}
+		extracted[header.Name] = true
+	}
+	for _, f := range manifest.Files {
+		if !extracted[f.Path] {
+			return nil, fmt.Errorf("%w: %s is missing", ErrInvalidBundle, f.Path)
+		}
+	}
+	return &Bundle{Dir: dir, Manifest: &manifest}, nil
+}
+
+// isLocal reports whether a slash-separated path names a file inside the bundle
+func isLocal(name string) bool {
+	return name != ManifestName && path.Clean(name) == name && filepath.IsLocal(filepath.FromSlash(name))
+}
+
+// coversListed reports whether name is a listed file, or a directory holding listed files
+func coversListed(name string, listed map[string]File) bool {
+	if _, ok := listed[name]; ok {
+		return true
+	}
+	for file := range listed {
+		if strings.HasPrefix(file, name+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// extract writes a file of the bundle, checking its size and checksum
+func extract(reader io.Reader, target string, expected File) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to extract bundle: %w", err)
+	}
+	out, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed to extract bundle: %w", err)
+	}
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(out, hash), io.LimitReader(reader, expected.Size+1))
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to extract bundle: %w", err)
+	}
+	if actual := "sha256:" + hex.EncodeToString(hash.Sum(nil)); written != expected.Size || actual != expected.Checksum {
+		return fmt.Errorf("%w: %s does not match its checksum in the manifest", ErrInvalidBundle, expected.Path)
+	}
+	return nil
+}
+
+// checksum returns the "sha256:<hex>" checksum of content
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Contracts returns the local path of the bundled contracts
+func (b *Bundle) Contracts() string {
+	return filepath.Join(b.Dir, filepath.FromSlash(b.Manifest.Contracts))
+}
+
+// Trace returns the local path of the bundled trace, or "" when none was bundled
+func (b *Bundle) Trace() string {
+	if b.Manifest.Trace == "" {
+		return ""
+	}
+	return filepath.Join(b.Dir, filepath.FromSlash(b.Manifest.Trace))
+}
+
+// LoadConfig returns the bundled configuration with the named profile applied, its path and
+// trace pointing at the bundled contracts and trace; a bundle without configuration gives a
+// configuration of only those
+func (b *Bundle) LoadConfig(profile string) (*config.Config, error) {
+	cfg := &config.Config{}
+	if b.Manifest.Config != "" {
+		loaded, err := config.LoadProfile(filepath.Join(b.Dir, filepath.FromSlash(b.Manifest.Config)), profile)
+		if err != nil {
+			return nil, err
+		}
+		cfg = loaded
+	}
+	cfg.Path, cfg.Trace = b.Contracts(), b.Trace()
+	return cfg, nil
+}
@@ -0,0 +1,245 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/errcode"
+	"github.com/flowspec/flowspec-cli/internal/httpclient"
+	"github.com/flowspec/flowspec-cli/internal/parser"
+	"github.com/flowspec/flowspec-cli/internal/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testContract = "apiVersion: flowspec/v1alpha1\nkind: ServiceSpec\n"
+
+// writeProject writes a configuration with a contracts directory and a fixture directory
+func writeProject(t *testing.T) string {
+	dir := t.TempDir()
+	files := map[string]string{
+		".flowspec.yaml":                        "path: contracts\ntrace: fixtures\nvars:\n  tenant: acme\n",
+		"contracts/orders.yaml":                 testContract,
+		"contracts/payments/payments.yaml":      testContract,
+		"fixtures/orders_GET_orders.trace.json": `{"resourceSpans":[]}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestCreateAndOpen(t *testing.T) {
+	project := writeProject(t)
+	archive := filepath.Join(t.TempDir(), "flowspec-bundle.tar.gz")
+
+	result, err := Create(context.Background(), archive, Options{Config: filepath.Join(project, ".flowspec.yaml"), CLIVersion: "0.7.0"})
+	require.NoError(t, err)
+	assert.Equal(t, "contracts/contracts", result.Manifest.Contracts)
+	assert.Equal(t, "traces/fixtures", result.Manifest.Trace)
+	assert.Equal(t, ".flowspec.yaml", result.Manifest.Config)
+	assert.Len(t, result.Manifest.Files, 4)
+	assert.Contains(t, result.FormatHuman(), "Bundled 4 file(s) into "+archive)
+
+	extracted, err := Open(archive, t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, "0.7.0", extracted.Manifest.CLIVersion)
+	content, err := os.ReadFile(filepath.Join(extracted.Contracts(), "payments", "payments.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, testContract, string(content))
+	assert.FileExists(t, filepath.Join(extracted.Trace(), "orders_GET_orders.trace.json"))
+
+	cfg, err := extracted.LoadConfig("")
+	require.NoError(t, err)
+	assert.Equal(t, extracted.Contracts(), cfg.Path)
+	assert.Equal(t, extracted.Trace(), cfg.Trace)
+	assert.Equal(t, "acme", cfg.Vars["tenant"])
+}
+
+func TestCreate_OverridesAndRemoteContract(t *testing.T) {
+	project := writeProject(t)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(testContract))
+	}))
+	defer server.Close()
+
+	options := Options{
+		Contracts: server.URL + "/contracts/orders.yaml",
+		Trace:     filepath.Join(project, "fixtures", "orders_GET_orders.trace.json"),
+		Remote:    remote.Options{CacheDir: t.TempDir()},
+	}
+	result, err := Create(context.Background(), filepath.Join(t.TempDir(), "online.tar.gz"), options)
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/contracts/orders.yaml", result.Manifest.ContractSource)
+	assert.Equal(t, "contracts/orders.yaml", result.Manifest.Contracts)
+	assert.Equal(t, "traces/orders_GET_orders.trace.json", result.Manifest.Trace)
+	assert.Empty(t, result.Manifest.Config)
+
+	// Offline, the remote contract comes from the cache
+	options.Remote.Network = &httpclient.Config{Offline: true}
+	_, err = Create(context.Background(), filepath.Join(t.TempDir(), "offline.tar.gz"), options)
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	extracted, err := Open(result.Path, t.TempDir())
+	require.NoError(t, err)
+	cfg, err := extracted.LoadConfig("")
+	require.NoError(t, err)
+	assert.Equal(t, extracted.Contracts(), cfg.Path)
+
+	_, err = Create(context.Background(), filepath.Join(t.TempDir(), "empty.tar.gz"), Options{})
+	assert.Error(t, err)
+}
+
+func TestCreate_ReferencedFiles(t *testing.T) {
+	project := t.TempDir()
+	index := "apiVersion: flowspec/v1alpha1\nkind: ServiceSpec\nmetadata:\n  name: orders\n  version: v1\nspec:\n  endpoints:\n    - $ref: ../shared/common.yaml#/orders\n"
+	common := "orders:\n  path: /orders\n  operations:\n    - method: GET\n      responses:\n        statusCodes: [200]\n      required:\n        query: []\n        headers: []\n"
+	files := map[string]string{"api/index.yaml": index, "shared/common.yaml": common, "unrelated/notes.yaml": "notes: true\n"}
+	for name, content := range files {
+		path := filepath.Join(project, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	archive := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	result, err := Create(context.Background(), archive, Options{Contracts: filepath.Join(project, "api")})
+	require.NoError(t, err)
+	assert.Equal(t, "contracts/api", result.Manifest.Contracts)
+	paths := make([]string, len(result.Manifest.Files))
+	for i, file := range result.Manifest.Files {
+		paths[i] = file.Path
+	}
+	assert.Equal(t, []string{"contracts/api/index.yaml", "contracts/shared/common.yaml"}, paths)
+
+	// Moved away from the project, the bundled contract still resolves its reference
+	require.NoError(t, os.RemoveAll(project))
+	extracted, err := Open(archive, t.TempDir())
+	require.NoError(t, err)
+	parsed, err := parser.NewSpecParser().ParseFromSource(extracted.Contracts())
+	require.NoError(t, err)
+	require.Empty(t, parsed.Errors)
+	require.Len(t, parsed.Specs, 1)
+	assert.Equal(t, "/orders", parsed.Specs[0].Spec.Endpoints[0].Path)
+}
+
+// rewriteArchive writes an archive of the given manifest and files, in order
+func rewriteArchive(t *testing.T, manifest *Manifest, files map[string]string, order []string) string {
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	content, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	write := func(name string, content []byte) {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tarWriter.Write(content)
+		require.NoError(t, err)
+	}
+	write(ManifestName, content)
+	for _, name := range order {
+		write(name, []byte(files[name]))
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	return path
+}
+
+func TestOpen_Invalid(t *testing.T) {
+	valid := File{Path: "contracts/orders.yaml", Size: int64(len(testContract)), Checksum: checksum([]byte(testContract))}
+	testCases := []struct {
+		name     string
+		manifest Manifest
+		files    map[string]string
+		order    []string
+	}{
+		{
+			name:     "tampered file",
+			manifest: Manifest{FormatVersion: "1", Contracts: "contracts/orders.yaml", Files: []File{valid}},
+			files:    map[string]string{"contracts/orders.yaml": testContract + "# changed\n"},
+			order:    []string{"contracts/orders.yaml"},
+		},
+		{
+			name:     "missing file",
+			manifest: Manifest{FormatVersion: "1", Contracts: "contracts/orders.yaml", Files: []File{valid}},
+		},
+		{
+			name:     "unlisted file",
+			manifest: Manifest{FormatVersion: "1", Contracts: "contracts/orders.yaml", Files: []File{valid}},
+			files:    map[string]string{"contracts/orders.yaml": testContract, "contracts/extra.yaml": testContract},
+			order:    []string{"contracts/orders.yaml", "contracts/extra.yaml"},
+		},
+		{
+			name:     "path outside the bundle",
+			manifest: Manifest{FormatVersion: "1", Contracts: "../orders.yaml", Files: []File{{Path: "../orders.yaml", Size: valid.Size, Checksum: valid.Checksum}}},
+			files:    map[string]string{"../orders.yaml": testContract},
+			order:    []string{"../orders.yaml"},
+		},
+		{
+			name:     "config outside the bundle",
+			manifest: Manifest{FormatVersion: "1", Contracts: "contracts/orders.yaml", Config: "../../home/user/.flowspec.yaml", Files: []File{valid}},
+			files:    map[string]string{"contracts/orders.yaml": testContract},
+			order:    []string{"contracts/orders.yaml"},
+		},
+		{
+			name:     "trace not covered by the checksums",
+			manifest: Manifest{FormatVersion: "1", Contracts: "contracts", Trace: "traces/run.json", Files: []File{valid}},
+			files:    map[string]string{"contracts/orders.yaml": testContract},
+			order:    []string{"contracts/orders.yaml"},
+		},
+		{
+			name:     "contracts missing",
+			manifest: Manifest{FormatVersion: "1", Files: []File{valid}},
+			files:    map[string]string{"contracts/orders.yaml": testContract},
+			order:    []string{"contracts/orders.yaml"},
+		},
+		{
+			name:     "unsupported format",
+			manifest: Manifest{FormatVersion: "2", Contracts: "contracts/orders.yaml"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			_, err := Open(rewriteArchive(t, &tc.manifest, tc.files, tc.order), filepath.Join(dir, "bundle"))
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrInvalidBundle), err.Error())
+			assert.Equal(t, errcode.BundleInvalid, errcode.Of(err))
+			assert.NoFileExists(t, filepath.Join(dir, "orders.yaml"))
+		})
+	}
+
+	notBundle := filepath.Join(t.TempDir(), "notes.txt")
+	require.NoError(t, os.WriteFile(notBundle, []byte("not a bundle"), 0644))
+	_, err := Open(notBundle, t.TempDir())
+	assert.True(t, errors.Is(err, ErrInvalidBundle))
+}
@@ -0,0 +1,63 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package concurrency sizes the worker pools of verification, trace loading, contract
+// parsing, traffic ingestion and report rendering from the processors Go may use, so large
+// machines are used without tuning every stage, and one --concurrency setting overrides them
+// all.
+package concurrency
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Auto is the setting that sizes worker pools from GOMAXPROCS
+const Auto = 0
+
+// Workers returns the workers of a setting: the setting when positive, otherwise GOMAXPROCS,
+// which follows the processors of the machine unless the GOMAXPROCS variable lowers it
+func Workers(setting int) int {
+	if setting > 0 {
+		return setting
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// Bounded returns the workers of a setting, but no more than there are items and at least one
+func Bounded(setting, items int) int {
+	workers := Workers(setting)
+	if workers > items {
+		workers = items
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	return workers
+}
+
+// Parse reads a --concurrency value, "auto" or a positive number of workers
+func Parse(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "auto") {
+		return Auto, nil
+	}
+	workers, err := strconv.Atoi(value)
+	if err != nil || workers < 1 {
+		return 0, fmt.Errorf("invalid concurrency %q, expected auto or a positive number", value)
+	}
+	return workers, nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrency
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkers(t *testing.T) {
+	assert.Equal(t, runtime.GOMAXPROCS(0), Workers(Auto))
+	assert.Equal(t, runtime.GOMAXPROCS(0), Workers(-1))
+	assert.Equal(t, 12, Workers(12))
+
+	previous := runtime.GOMAXPROCS(3)
+	defer runtime.GOMAXPROCS(previous)
+	assert.Equal(t, 3, Workers(Auto), "auto follows GOMAXPROCS")
+}
+
+func TestBounded(t *testing.T) {
+	assert.Equal(t, 2, Bounded(8, 2))
+	assert.Equal(t, 8, Bounded(8, 100))
+	assert.Equal(t, 1, Bounded(8, 0))
+}
+
+func TestParse(t *testing.T) {
+	for value, expected := range map[string]int{"": Auto, "auto": Auto, "AUTO": Auto, "16": 16, " 2 ": 2} {
+		workers, err := Parse(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, workers, value)
+	}
+	for _, value := range []string{"0", "-4", "many"} {
+		_, err := Parse(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,911 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package config loads the project configuration file (.flowspec.yaml). Values from the
+// file replace built-in defaults; command-line flags are applied afterwards and win.
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/concurrency"
+	"github.com/flowspec/flowspec-cli/internal/engine"
+	"github.com/flowspec/flowspec-cli/internal/errcode"
+	"github.com/flowspec/flowspec-cli/internal/httpclient"
+	"github.com/flowspec/flowspec-cli/internal/i18n"
+	"github.com/flowspec/flowspec-cli/internal/ingestor/traffic"
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/plugin"
+	"github.com/flowspec/flowspec-cli/internal/remote"
+	"github.com/flowspec/flowspec-cli/internal/renderer"
+	"github.com/flowspec/flowspec-cli/internal/scrub"
+	"github.com/flowspec/flowspec-cli/internal/selfupdate"
+	"github.com/flowspec/flowspec-cli/internal/signing"
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames lists the project configuration file names, in lookup order
+var FileNames = []string{".flowspec.yaml", ".flowspec.yml"}
+
+// Config is the project configuration
+type Config struct {
+	Path    string                 `yaml:"path,omitempty"`  // Contracts or source directory, or a remote contract URL
+	Trace   string                 `yaml:"trace,omitempty"` // Trace file
+	Lang    string                 `yaml:"lang,omitempty"`  // Report language, e.g. en or zh-CN
+	Vars    map[string]interface{} `yaml:"vars,omitempty"`  // Variables exposed to assertions as vars.*
+	Tags    []string               `yaml:"tags,omitempty"`  // Only operations carrying one of these tags are verified
+	Engine  EngineConfig           `yaml:"engine,omitempty"`
+	Matcher MatcherConfig          `yaml:"matcher,omitempty"`
+	Report  ReportConfig           `yaml:"report,omitempty"`
+	Explore ExploreConfig          `yaml:"explore,omitempty"`
+	Remote  RemoteConfig           `yaml:"remote,omitempty"`
+	Scrub   ScrubConfig            `yaml:"scrub,omitempty"`
+	Network NetworkConfig          `yaml:"network,omitempty"`
+	Plugins PluginConfig           `yaml:"plugins,omitempty"`
+
+	// Signature requires the contracts to carry detached signatures made with a reviewed key
+	Signature SignatureConfig `yaml:"signature,omitempty"`
+
+	// RequireVersion pins the CLI versions the repository expects, e.g. ">=0.6 <0.8"
+	RequireVersion string `yaml:"requireVersion,omitempty"`
+
+	// Concurrency sizes every worker pool, "auto" (GOMAXPROCS) or a number of workers;
+	// engine.maxConcurrency, engine.traceConcurrency and explore.parallelism override it
+	Concurrency string `yaml:"concurrency,omitempty"`
+
+	// AttributeAliases names the span attributes that carry a field for instrumentation that
+	// does not follow the OpenTelemetry conventions, e.g. method: [http.method, custom.verb]
+	AttributeAliases models.AttributeAliases `yaml:"attributeAliases,omitempty"`
+
+	// SpanStatus maps span status codes for instrumentation that does not set them
+	// consistently, e.g. {code: UNSET, as: OK}; the first matching rule applies
+	SpanStatus models.SpanStatusRules `yaml:"spanStatus,omitempty"`
+
+	// Suggestions add organization-specific remediation and runbook links to the failures they
+	// match, e.g. {name: tenant, key: "*x-tenant*", failure: missing, suggestion: "..."}
+	Suggestions models.SuggestionRules `yaml:"suggestions,omitempty"`
+
+	// Profiles override the settings above for a pipeline, e.g. "ci" or "nightly"
+	Profiles map[string]*Config `yaml:"profiles,omitempty"`
+
+	File    string `yaml:"-"` // Path the configuration was loaded from
+	Profile string `yaml:"-"` // Name of the applied profile
+}
+
+// EngineConfig tunes the alignment engine
+type EngineConfig struct {
+	MaxConcurrency   int           `yaml:"maxConcurrency,omitempty"`
+	TraceConcurrency int           `yaml:"traceConcurrency,omitempty"` // Traces of a --trace directory verified at once
+	Timeout          time.Duration `yaml:"timeout,omitempty"`          // Per-spec timeout, e.g. "30s"
+	Strict           *bool         `yaml:"strict,omitempty"`
+	EnforceSunset    *bool         `yaml:"enforceSunset,omitempty"`
+	MaxFlakeRate     float64       `yaml:"maxFlakeRate,omitempty"` // Tolerated share of failing spans per operation, e.g. 0.05
+	TimeZone         string        `yaml:"timeZone,omitempty"`     // IANA zone of the span.start and span.end variables, e.g. "Europe/Berlin"
+	ClockSkew        time.Duration `yaml:"clockSkew,omitempty"`    // Tolerated start of a child span before its parent, e.g. "50ms"
+	FailOnIncomplete *bool         `yaml:"failOnIncompleteTrace,omitempty"`
+	CorrectClockSkew *bool         `yaml:"correctClockSkew,omitempty"`
+	FailFast         *bool         `yaml:"failFast,omitempty"`        // Stop at the first failure of critical priority
+	TraceSampleRate  float64       `yaml:"traceSampleRate,omitempty"` // Share of traces kept by head sampling, e.g. 0.1
+}
+
+// MatcherConfig controls how spans are matched to specs
+type MatcherConfig struct {
+	SkipMissingSpans *bool  `yaml:"skipMissingSpans,omitempty"`
+	ReportUnmatched  *bool  `yaml:"reportUnmatched,omitempty"`
+	Explain          *bool  `yaml:"explain,omitempty"`
+	Ambiguity        string `yaml:"ambiguity,omitempty"` // most-specific-wins, fail or all for spans matching several operations
+}
+
+// ReportConfig holds reporter settings
+type ReportConfig struct {
+	Output             string `yaml:"output,omitempty"`      // human, json or ndjson
+	MinCoverage        string `yaml:"minCoverage,omitempty"` // e.g. "80%" or "0.8"
+	FailOn             string `yaml:"failOn,omitempty"`      // e.g. "failures,skipped"
+	ExitZero           *bool  `yaml:"exitZero,omitempty"`
+	Color              *bool  `yaml:"color,omitempty"`
+	HTML               string `yaml:"html,omitempty"`
+	SARIF              string `yaml:"sarif,omitempty"`
+	JUnit              string `yaml:"junit,omitempty"`
+	CodeQuality        string `yaml:"codeQuality,omitempty"`
+	CSV                string `yaml:"csv,omitempty"`
+	BadgeDir           string `yaml:"badgeDir,omitempty"`
+	Prometheus         string `yaml:"prometheus,omitempty"`
+	Summary            string `yaml:"summary,omitempty"` // flowspec-summary.json of the run
+	TraceUIURLTemplate string `yaml:"traceUIURLTemplate,omitempty"`
+	Scrub              *bool  `yaml:"scrub,omitempty"`       // Redact reports with the scrub rules
+	DetailLevel        string `yaml:"detailLevel,omitempty"` // minimal, normal or full span data in failure messages
+}
+
+// RemoteConfig controls how a remote contract named by path is fetched; pin its checksum
+// with a #sha256=<hex> fragment of the URL
+type RemoteConfig struct {
+	CacheDir   string        `yaml:"cacheDir,omitempty"`
+	Timeout    time.Duration `yaml:"timeout,omitempty"`    // Per-request timeout, e.g. "30s"
+	Attempts   int           `yaml:"attempts,omitempty"`   // Fetch attempts on transient failures; 1 disables retries
+	Backoff    time.Duration `yaml:"backoff,omitempty"`    // Wait before the first retry, doubled before each further retry
+	MaxBackoff time.Duration `yaml:"maxBackoff,omitempty"` // Longest wait between attempts
+}
+
+// SignatureConfig controls the verification of contract signatures written by flowspec-cli sign
+type SignatureConfig struct {
+	Verify    *bool  `yaml:"verify,omitempty"`    // Refuse contracts whose signatures do not verify
+	PublicKey string `yaml:"publicKey,omitempty"` // PEM encoded Ed25519 public key
+}
+
+// NetworkConfig holds the proxy and TLS settings of remote contracts, publishing, webhooks and
+// the other network integrations; the FLOWSPEC_PROXY, FLOWSPEC_CA_FILE, FLOWSPEC_CLIENT_CERT and
+// FLOWSPEC_CLIENT_KEY environment variables take precedence
+type NetworkConfig struct {
+	Proxy    string `yaml:"proxy,omitempty"`    // e.g. "http://proxy.corp.example.com:3128"
+	CAFile   string `yaml:"caFile,omitempty"`   // PEM bundle of private CAs
+	CertFile string `yaml:"certFile,omitempty"` // PEM client certificate for mutual TLS
+	KeyFile  string `yaml:"keyFile,omitempty"`
+	Offline  *bool  `yaml:"offline,omitempty"` // Refuse every request leaving the machine, like --offline
+}
+
+// PluginConfig locates the process plugins providing trace formats and assertion operators
+type PluginConfig struct {
+	Dirs    []string      `yaml:"dirs,omitempty"`    // Searched before FLOWSPEC_PLUGIN_PATH and the default directories
+	Timeout time.Duration `yaml:"timeout,omitempty"` // Bounds a single plugin call, e.g. "10s"
+}
+
+// ScrubConfig holds the redaction rules of the scrub command, which report.scrub applies to
+// verify reports too
+type ScrubConfig struct {
+	Rules   scrub.Rules   `yaml:"rules,omitempty"` // Replace the built-in rules; a rule naming a built-in rule reuses it
+	Secrets SecretsConfig `yaml:"secrets,omitempty"`
+}
+
+// SecretsConfig controls the redaction of credentials from the span data quoted by reports,
+// which applies unless disabled
+type SecretsConfig struct {
+	Redact *bool    `yaml:"redact,omitempty"`
+	Allow  []string `yaml:"allow,omitempty"` // Attribute keys never redacted, as glob patterns, e.g. "*x-csrf-token"
+	Deny   []string `yaml:"deny,omitempty"`  // Attribute keys redacted besides the built-in credential keys
+}
+
+// ExploreConfig holds the defaults of the explore command
+type ExploreConfig struct {
+	Traffic                 string  `yaml:"traffic,omitempty"`
+	Out                     string  `yaml:"out,omitempty"`
+	OutFormat               string  `yaml:"outFormat,omitempty"`  // yaml or json
+	OutDir                  string  `yaml:"outDir,omitempty"`     // One file per endpoint plus an index, instead of out
+	Stats                   string  `yaml:"stats,omitempty"`      // inline, sidecar or omit
+	MetricsOut              string  `yaml:"metricsOut,omitempty"` // JSON artifact of the ingestion metrics
+	Format                  string  `yaml:"format,omitempty"`     // Traffic ingestor, e.g. nginx, or auto to detect per file
+	LogFormat               string  `yaml:"logFormat,omitempty"`
+	NginxLogFormat          string  `yaml:"nginxLogFormat,omitempty"` // nginx log_format template, instead of logFormat
+	SampleRate              float64 `yaml:"sampleRate,omitempty"`
+	MaxErrorRate            float64 `yaml:"maxErrorRate,omitempty"` // Share of unparseable lines of a file that aborts the run
+	MaxLineBytes            int     `yaml:"maxLineBytes,omitempty"`
+	LongLines               string  `yaml:"longLines,omitempty"` // skip or truncate lines over maxLineBytes
+	Encoding                string  `yaml:"encoding,omitempty"`  // utf-8, auto or a charset such as latin1
+	StatusAggregation       string  `yaml:"statusAggregation,omitempty"`
+	RequiredThreshold       float64 `yaml:"requiredThreshold,omitempty"`
+	MinSamples              int     `yaml:"minSamples,omitempty"`
+	PathClusteringThreshold float64 `yaml:"pathClusteringThreshold,omitempty"`
+	MinSampleSize           int     `yaml:"minSampleSize,omitempty"`
+	MaxUniqueValues         int     `yaml:"maxUniqueValues,omitempty"`
+	ServiceName             string  `yaml:"serviceName,omitempty"`
+	ServiceVersion          string  `yaml:"serviceVersion,omitempty"`
+	Parallelism             int     `yaml:"parallelism,omitempty"`
+	Mmap                    string  `yaml:"mmap,omitempty"` // auto or off memory-mapped reads of large uncompressed logs
+}
+
+// Find looks for a configuration file in dir and its parents and returns its path,
+// or an empty string if there is none
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	for {
+		for _, name := range FileNames {
+			candidate := filepath.Join(dir, name)
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				return candidate, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// Load reads a configuration file without applying a profile
+func Load(path string) (*Config, error) {
+	return LoadProfile(path, "")
+}
+
+// LoadProfile reads a configuration file and applies the named profile, if any.
+// Relative paths in the file are resolved against the directory containing it. Failures have
+// the code errcode.ConfigInvalid.
+func LoadProfile(path, profile string) (*Config, error) {
+	config, err := loadProfile(path, profile)
+	if err != nil {
+		return nil, errcode.Wrap(err, errcode.ConfigInvalid)
+	}
+	return config, nil
+}
+
+// loadProfile reads, validates and resolves a configuration file
+func loadProfile(path, profile string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	// Every profile is validated, not only the selected one, so mistakes surface early
+	for _, name := range config.ProfileNames() {
+		if overlay := config.Profiles[name]; overlay != nil {
+			if len(overlay.Profiles) > 0 {
+				return nil, fmt.Errorf("config %s: profile %q cannot define profiles", path, name)
+			}
+			if err := overlay.validate(); err != nil {
+				return nil, fmt.Errorf("config %s: profile %q: %w", path, name, err)
+			}
+		}
+		if err := config.withProfile(name).validate(); err != nil {
+			return nil, fmt.Errorf("config %s: profile %q: %w", path, name, err)
+		}
+	}
+
+	if profile != "" {
+		if _, ok := config.Profiles[profile]; !ok {
+			return nil, fmt.Errorf("config %s: unknown profile %q (available: %s)",
+				path, profile, strings.Join(config.ProfileNames(), ", "))
+		}
+		config = *config.withProfile(profile)
+	}
+
+	config.File = path
+	config.resolvePaths(filepath.Dir(path))
+	return &config, nil
+}
+
+// ProfileNames returns the names of the defined profiles in sorted order
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// withProfile returns a copy of the configuration with the settings of the profile applied
+func (c *Config) withProfile(name string) *Config {
+	merged := *c
+	merged.Profile = name
+	overlay := c.Profiles[name]
+	if overlay == nil {
+		return &merged
+	}
+
+	setString(&merged.Path, overlay.Path)
+	setString(&merged.Trace, overlay.Trace)
+	setString(&merged.Lang, overlay.Lang)
+	setString(&merged.RequireVersion, overlay.RequireVersion)
+	setString(&merged.Concurrency, overlay.Concurrency)
+	if len(overlay.Tags) > 0 {
+		merged.Tags = overlay.Tags
+	}
+	if len(overlay.Vars) > 0 {
+		merged.Vars = make(map[string]interface{}, len(c.Vars)+len(overlay.Vars))
+		for key, value := range c.Vars {
+			merged.Vars[key] = value
+		}
+		for key, value := range overlay.Vars {
+			merged.Vars[key] = value
+		}
+	}
+
+	if len(overlay.AttributeAliases) > 0 {
+		merged.AttributeAliases = make(models.AttributeAliases, len(c.AttributeAliases)+len(overlay.AttributeAliases))
+		for field, attributes := range c.AttributeAliases {
+			merged.AttributeAliases[field] = attributes
+		}
+		for field, attributes := range overlay.AttributeAliases {
+			merged.AttributeAliases[field] = attributes
+		}
+	}
+
+	if len(overlay.SpanStatus) > 0 {
+		merged.SpanStatus = overlay.SpanStatus
+	}
+	if len(overlay.Suggestions) > 0 {
+		merged.Suggestions = overlay.Suggestions
+	}
+
+	if len(overlay.Scrub.Rules) > 0 {
+		merged.Scrub.Rules = overlay.Scrub.Rules
+	}
+	setBoolPointer(&merged.Scrub.Secrets.Redact, overlay.Scrub.Secrets.Redact)
+	if len(overlay.Scrub.Secrets.Allow) > 0 {
+		merged.Scrub.Secrets.Allow = overlay.Scrub.Secrets.Allow
+	}
+	if len(overlay.Scrub.Secrets.Deny) > 0 {
+		merged.Scrub.Secrets.Deny = overlay.Scrub.Secrets.Deny
+	}
+
+	tuning := &merged.Engine
+	if overlay.Engine.MaxConcurrency > 0 {
+		tuning.MaxConcurrency = overlay.Engine.MaxConcurrency
+	}
+	if overlay.Engine.TraceConcurrency > 0 {
+		tuning.TraceConcurrency = overlay.Engine.TraceConcurrency
+	}
+	if overlay.Engine.Timeout > 0 {
+		tuning.Timeout = overlay.Engine.Timeout
+	}
+	setBoolPointer(&tuning.Strict, overlay.Engine.Strict)
+	setBoolPointer(&tuning.EnforceSunset, overlay.Engine.EnforceSunset)
+	if overlay.Engine.MaxFlakeRate > 0 {
+		tuning.MaxFlakeRate = overlay.Engine.MaxFlakeRate
+	}
+	setString(&tuning.TimeZone, overlay.Engine.TimeZone)
+	if overlay.Engine.ClockSkew > 0 {
+		tuning.ClockSkew = overlay.Engine.ClockSkew
+	}
+	setBoolPointer(&tuning.FailOnIncomplete, overlay.Engine.FailOnIncomplete)
+	setBoolPointer(&tuning.CorrectClockSkew, overlay.Engine.CorrectClockSkew)
+	setBoolPointer(&tuning.FailFast, overlay.Engine.FailFast)
+	setFloat(&tuning.TraceSampleRate, overlay.Engine.TraceSampleRate)
+
+	matcher := &merged.Matcher
+	setBoolPointer(&matcher.SkipMissingSpans, overlay.Matcher.SkipMissingSpans)
+	setBoolPointer(&matcher.ReportUnmatched, overlay.Matcher.ReportUnmatched)
+	setBoolPointer(&matcher.Explain, overlay.Matcher.Explain)
+	setString(&matcher.Ambiguity, overlay.Matcher.Ambiguity)
+
+	report := &merged.Report
+	setString(&report.Output, overlay.Report.Output)
+	setString(&report.MinCoverage, overlay.Report.MinCoverage)
+	setString(&report.FailOn, overlay.Report.FailOn)
+	setBoolPointer(&report.ExitZero, overlay.Report.ExitZero)
+	setBoolPointer(&report.Color, overlay.Report.Color)
+	setString(&report.HTML, overlay.Report.HTML)
+	setString(&report.SARIF, overlay.Report.SARIF)
+	setString(&report.JUnit, overlay.Report.JUnit)
+	setString(&report.CodeQuality, overlay.Report.CodeQuality)
+	setString(&report.CSV, overlay.Report.CSV)
+	setString(&report.BadgeDir, overlay.Report.BadgeDir)
+	setString(&report.Prometheus, overlay.Report.Prometheus)
+	setString(&report.Summary, overlay.Report.Summary)
+	setString(&report.TraceUIURLTemplate, overlay.Report.TraceUIURLTemplate)
+	setBoolPointer(&report.Scrub, overlay.Report.Scrub)
+	setString(&report.DetailLevel, overlay.Report.DetailLevel)
+
+	setString(&merged.Remote.CacheDir, overlay.Remote.CacheDir)
+	if overlay.Remote.Timeout > 0 {
+		merged.Remote.Timeout = overlay.Remote.Timeout
+	}
+	if overlay.Remote.Attempts > 0 {
+		merged.Remote.Attempts = overlay.Remote.Attempts
+	}
+	if overlay.Remote.Backoff > 0 {
+		merged.Remote.Backoff = overlay.Remote.Backoff
+	}
+	if overlay.Remote.MaxBackoff > 0 {
+		merged.Remote.MaxBackoff = overlay.Remote.MaxBackoff
+	}
+
+	setBoolPointer(&merged.Signature.Verify, overlay.Signature.Verify)
+	setString(&merged.Signature.PublicKey, overlay.Signature.PublicKey)
+
+	if len(overlay.Plugins.Dirs) > 0 {
+		merged.Plugins.Dirs = overlay.Plugins.Dirs
+	}
+	if overlay.Plugins.Timeout > 0 {
+		merged.Plugins.Timeout = overlay.Plugins.Timeout
+	}
+
+	network := &merged.Network
+	setString(&network.Proxy, overlay.Network.Proxy)
+	setString(&network.CAFile, overlay.Network.CAFile)
+	setString(&network.CertFile, overlay.Network.CertFile)
+	setString(&network.KeyFile, overlay.Network.KeyFile)
+	setBoolPointer(&network.Offline, overlay.Network.Offline)
+
+	explore := &merged.Explore
+	setString(&explore.Traffic, overlay.Explore.Traffic)
+	setString(&explore.Out, overlay.Explore.Out)
+	setString(&explore.OutFormat, overlay.Explore.OutFormat)
+	setString(&explore.OutDir, overlay.Explore.OutDir)
+	setString(&explore.Stats, overlay.Explore.Stats)
+	setString(&explore.MetricsOut, overlay.Explore.MetricsOut)
+	setString(&explore.Format, overlay.Explore.Format)
+	setString(&explore.LogFormat, overlay.Explore.LogFormat)
+	setString(&explore.NginxLogFormat, overlay.Explore.NginxLogFormat)
+	setFloat(&explore.SampleRate, overlay.Explore.SampleRate)
+	setFloat(&explore.MaxErrorRate, overlay.Explore.MaxErrorRate)
+	setInt(&explore.MaxLineBytes, overlay.Explore.MaxLineBytes)
+	setString(&explore.LongLines, overlay.Explore.LongLines)
+	setString(&explore.Encoding, overlay.Explore.Encoding)
+	setString(&explore.StatusAggregation, overlay.Explore.StatusAggregation)
+	setFloat(&explore.RequiredThreshold, overlay.Explore.RequiredThreshold)
+	setInt(&explore.MinSamples, overlay.Explore.MinSamples)
+	setFloat(&explore.PathClusteringThreshold, overlay.Explore.PathClusteringThreshold)
+	setInt(&explore.MinSampleSize, overlay.Explore.MinSampleSize)
+	setInt(&explore.MaxUniqueValues, overlay.Explore.MaxUniqueValues)
+	setString(&explore.ServiceName, overlay.Explore.ServiceName)
+	setString(&explore.ServiceVersion, overlay.Explore.ServiceVersion)
+	setInt(&explore.Parallelism, overlay.Explore.Parallelism)
+	setString(&explore.Mmap, overlay.Explore.Mmap)
+	return &merged
+}
+
+// LoadFromDir finds the configuration file for dir and applies the named profile; it returns
+// an empty configuration when there is no file and no profile was requested
+func LoadFromDir(dir, profile string) (*Config, error) {
+	path, err := Find(dir)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		if profile != "" {
+			return nil, fmt.Errorf("profile %q requested but no %s found", profile, FileNames[0])
+		}
+		return &Config{}, nil
+	}
+	return LoadProfile(path, profile)
+}
+
+// validate checks values that can be verified without running a command
+func (c *Config) validate() error {
+	if _, err := concurrency.Parse(c.Concurrency); err != nil {
+		return fmt.Errorf("concurrency: %w", err)
+	}
+	if c.RequireVersion != "" {
+		if _, err := selfupdate.ParseConstraint(c.RequireVersion); err != nil {
+			return fmt.Errorf("requireVersion: %w", err)
+		}
+	}
+	switch c.Report.Output {
+	case "", "human", "json", "ndjson":
+	default:
+		return fmt.Errorf("report.output must be one of: human, json, ndjson")
+	}
+	if c.Report.MinCoverage != "" {
+		if _, err := models.ParseCoverageThreshold(c.Report.MinCoverage); err != nil {
+			return fmt.Errorf("report.minCoverage: %w", err)
+		}
+	}
+	if _, err := renderer.ParseExitPolicy(c.Report.FailOn); err != nil {
+		return fmt.Errorf("report.failOn: %w", err)
+	}
+	if c.Report.TraceUIURLTemplate != "" {
+		if err := renderer.ValidateTraceUIURLTemplate(c.Report.TraceUIURLTemplate); err != nil {
+			return fmt.Errorf("report.traceUIURLTemplate: %w", err)
+		}
+	}
+	if c.Engine.MaxConcurrency < 0 || c.Engine.TraceConcurrency < 0 || c.Engine.Timeout < 0 || c.Engine.ClockSkew < 0 {
+		return fmt.Errorf("engine.maxConcurrency, engine.traceConcurrency, engine.timeout and engine.clockSkew must not be negative")
+	}
+	if remote.IsRemote(c.Path) {
+		if err := remote.ValidateSource(c.Path); err != nil {
+			return fmt.Errorf("path: %w", err)
+		}
+	}
+	if c.Remote.Timeout < 0 || c.Remote.Attempts < 0 || c.Remote.Backoff < 0 || c.Remote.MaxBackoff < 0 {
+		return fmt.Errorf("remote.timeout, remote.attempts, remote.backoff and remote.maxBackoff must not be negative")
+	}
+	if c.Signature.Verify != nil && *c.Signature.Verify && c.Signature.PublicKey == "" {
+		return fmt.Errorf("signature.publicKey is required to verify signatures")
+	}
+	if c.Network.Proxy != "" {
+		if err := httpclient.ValidateProxy(c.Network.Proxy); err != nil {
+			return fmt.Errorf("network.proxy: %w", err)
+		}
+	}
+	if (c.Network.CertFile == "") != (c.Network.KeyFile == "") {
+		return fmt.Errorf("network.certFile and network.keyFile must be set together")
+	}
+	if c.Engine.MaxFlakeRate < 0 || c.Engine.MaxFlakeRate >= 1 {
+		return fmt.Errorf("engine.maxFlakeRate must be at least 0.0 and below 1.0")
+	}
+	if c.Engine.TraceSampleRate < 0 || c.Engine.TraceSampleRate > 1 {
+		return fmt.Errorf("engine.traceSampleRate must be between 0.0 and 1.0")
+	}
+	if c.Engine.TimeZone != "" {
+		if _, err := time.LoadLocation(c.Engine.TimeZone); err != nil {
+			return fmt.Errorf("engine.timeZone: %w", err)
+		}
+	}
+	if _, ok := i18n.ParseLanguage(c.Lang); c.Lang != "" && !ok {
+		var languages []string
+		for _, language := range i18n.GetSupportedLanguages() {
+			languages = append(languages, string(language))
+		}
+		return fmt.Errorf("lang must be one of %s, got '%s'", strings.Join(languages, ", "), c.Lang)
+	}
+	for _, tag := range c.Tags {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("tags must not be empty")
+		}
+	}
+	if err := c.AttributeAliases.Validate(); err != nil {
+		return fmt.Errorf("attributeAliases: %w", err)
+	}
+	if err := c.SpanStatus.Validate(); err != nil {
+		return fmt.Errorf("spanStatus: %w", err)
+	}
+	if err := c.Suggestions.Validate(); err != nil {
+		return fmt.Errorf("suggestions: %w", err)
+	}
+	if err := engine.ValidateAmbiguityPolicy(c.Matcher.Ambiguity); err != nil {
+		return fmt.Errorf("matcher.ambiguity: %w", err)
+	}
+	if err := engine.ValidateDetailLevel(c.Report.DetailLevel); err != nil {
+		return fmt.Errorf("report.detailLevel: %w", err)
+	}
+	if err := c.Scrub.Rules.Validate(); err != nil {
+		return fmt.Errorf("scrub.rules: %w", err)
+	}
+	if _, err := scrub.NewSecretRedactor(c.Scrub.Secrets.Allow, c.Scrub.Secrets.Deny); err != nil {
+		return fmt.Errorf("scrub.secrets: %w", err)
+	}
+	if err := traffic.ValidateFormat(c.Explore.Format); err != nil {
+		return fmt.Errorf("explore.format: %w", err)
+	}
+	if c.Explore.NginxLogFormat != "" {
+		if err := traffic.ValidateNginxLogFormat(c.Explore.NginxLogFormat); err != nil {
+			return fmt.Errorf("explore.nginxLogFormat: %w", err)
+		}
+	}
+	if err := traffic.ValidateLongLines(c.Explore.LongLines); err != nil {
+		return fmt.Errorf("explore.longLines: %w", err)
+	}
+	if err := traffic.ValidateEncoding(c.Explore.Encoding); err != nil {
+		return fmt.Errorf("explore.encoding: %w", err)
+	}
+	if err := traffic.ValidateMmap(c.Explore.Mmap); err != nil {
+		return fmt.Errorf("explore.mmap: %w", err)
+	}
+	if err := engine.ValidateContractFormat(c.Explore.OutFormat); err != nil {
+		return fmt.Errorf("explore.outFormat: %w", err)
+	}
+	if err := engine.ValidateStatsMode(c.Explore.Stats); err != nil {
+		return fmt.Errorf("explore.stats: %w", err)
+	}
+	if c.Explore.Parallelism < 0 || c.Explore.MaxLineBytes < 0 {
+		return fmt.Errorf("explore.parallelism and explore.maxLineBytes must not be negative")
+	}
+	for name, ratio := range map[string]float64{
+		"explore.sampleRate":              c.Explore.SampleRate,
+		"explore.maxErrorRate":            c.Explore.MaxErrorRate,
+		"explore.requiredThreshold":       c.Explore.RequiredThreshold,
+		"explore.pathClusteringThreshold": c.Explore.PathClusteringThreshold,
+	} {
+		if ratio < 0 || ratio > 1 {
+			return fmt.Errorf("%s must be between 0.0 and 1.0", name)
+		}
+	}
+	return nil
+}
+
+// resolvePaths makes relative file paths relative to dir; a remote contract path is kept
+func (c *Config) resolvePaths(dir string) {
+	for _, path := range []*string{
+		&c.Trace, &c.Remote.CacheDir, &c.Signature.PublicKey,
+		&c.Network.CAFile, &c.Network.CertFile, &c.Network.KeyFile,
+		&c.Report.HTML, &c.Report.SARIF, &c.Report.JUnit, &c.Report.CodeQuality,
+		&c.Report.CSV, &c.Report.BadgeDir, &c.Report.Prometheus, &c.Report.Summary,
+		&c.Explore.Traffic, &c.Explore.Out, &c.Explore.OutDir, &c.Explore.MetricsOut,
+	} {
+		if *path != "" && !filepath.IsAbs(*path) {
+			*path = filepath.Join(dir, *path)
+		}
+	}
+	for i, pluginDir := range c.Plugins.Dirs {
+		if !filepath.IsAbs(pluginDir) {
+			c.Plugins.Dirs[i] = filepath.Join(dir, pluginDir)
+		}
+	}
+	if c.Path != "" && !filepath.IsAbs(c.Path) && !remote.IsRemote(c.Path) {
+		c.Path = filepath.Join(dir, c.Path)
+	}
+}
+
+// CheckVersion fails with the code errcode.VersionMismatch when the running CLI does not
+// satisfy requireVersion. Development builds always pass.
+func (c *Config) CheckVersion(current string) error {
+	if c.RequireVersion == "" {
+		return nil
+	}
+	constraint, err := selfupdate.ParseConstraint(c.RequireVersion)
+	if err != nil {
+		return errcode.Wrap(fmt.Errorf("requireVersion: %w", err), errcode.ConfigInvalid)
+	}
+	if err := constraint.Check(current); err != nil {
+		if c.File != "" {
+			err = fmt.Errorf("config %s: %w", c.File, err)
+		}
+		return errcode.Wrap(err, errcode.VersionMismatch)
+	}
+	return nil
+}
+
+// HTTPClientConfig returns the network settings, those of the environment taking precedence;
+// offline mode is on when either turns it on
+func (c *Config) HTTPClientConfig() *httpclient.Config {
+	return httpclient.ConfigFromEnv().Merge(&httpclient.Config{
+		Proxy:    c.Network.Proxy,
+		CAFile:   c.Network.CAFile,
+		CertFile: c.Network.CertFile,
+		KeyFile:  c.Network.KeyFile,
+		Offline:  c.Network.Offline != nil && *c.Network.Offline,
+	})
+}
+
+// ApplyRemote copies the remote contract and network settings that are set onto options
+func (c *Config) ApplyRemote(options *remote.Options) {
+	if options.Network == nil {
+		options.Network = c.HTTPClientConfig()
+	}
+	setString(&options.CacheDir, c.Remote.CacheDir)
+	if c.Remote.Timeout > 0 {
+		options.Timeout = c.Remote.Timeout
+	}
+	if c.Remote.Attempts > 0 || c.Remote.Backoff > 0 || c.Remote.MaxBackoff > 0 {
+		policy := remote.DefaultRetryPolicy()
+		if options.Retry != nil {
+			policy = *options.Retry
+		}
+		if c.Remote.Attempts > 0 {
+			policy.Attempts = c.Remote.Attempts
+		}
+		if c.Remote.Backoff > 0 {
+			policy.InitialBackoff = c.Remote.Backoff
+		}
+		if c.Remote.MaxBackoff > 0 {
+			policy.MaxBackoff = c.Remote.MaxBackoff
+		}
+		options.Retry = &policy
+	}
+}
+
+// Workers returns the concurrency setting, concurrency.Auto unless a number of workers is set
+func (c *Config) Workers() int {
+	workers, _ := concurrency.Parse(c.Concurrency)
+	return workers
+}
+
+// ApplyEngine copies the engine and matcher settings that are set onto config
+func (c *Config) ApplyEngine(config *engine.EngineConfig) {
+	if workers := c.Workers(); workers > 0 {
+		config.MaxConcurrency, config.TraceConcurrency = workers, workers
+	}
+	if c.Engine.MaxConcurrency > 0 {
+		config.MaxConcurrency = c.Engine.MaxConcurrency
+	}
+	if c.Engine.TraceConcurrency > 0 {
+		config.TraceConcurrency = c.Engine.TraceConcurrency
+	}
+	if c.Engine.Timeout > 0 {
+		config.Timeout = c.Engine.Timeout
+	}
+	setBool(&config.StrictMode, c.Engine.Strict)
+	setBool(&config.EnforceSunset, c.Engine.EnforceSunset)
+	if c.Engine.MaxFlakeRate > 0 {
+		config.MaxFlakeRate = c.Engine.MaxFlakeRate
+	}
+	if c.Engine.TimeZone != "" {
+		if location, err := time.LoadLocation(c.Engine.TimeZone); err == nil {
+			config.TimeZone = location
+		}
+	}
+	if c.Engine.ClockSkew > 0 {
+		config.ClockSkew = c.Engine.ClockSkew
+	}
+	setBool(&config.FailOnIncomplete, c.Engine.FailOnIncomplete)
+	setBool(&config.CorrectClockSkew, c.Engine.CorrectClockSkew)
+	setBool(&config.FailFast, c.Engine.FailFast)
+	setFloat(&config.TraceSampleRate, c.Engine.TraceSampleRate)
+	setBool(&config.SkipMissingSpans, c.Matcher.SkipMissingSpans)
+	setBool(&config.ReportUnmatched, c.Matcher.ReportUnmatched)
+	setBool(&config.Explain, c.Matcher.Explain)
+	setString(&config.AmbiguityPolicy, c.Matcher.Ambiguity)
+	if len(c.AttributeAliases) > 0 {
+		config.AttributeAliases = c.AttributeAliases
+	}
+	if len(c.SpanStatus) > 0 {
+		config.SpanStatus = c.SpanStatus
+	}
+	if len(c.Suggestions) > 0 {
+		config.SuggestionRules = c.Suggestions
+	}
+	if len(c.Tags) > 0 {
+		config.Tags = c.Tags
+	}
+	setString(&config.DetailLevel, c.Report.DetailLevel)
+	if secrets := c.Scrub.Secrets; secrets.Redact != nil && !*secrets.Redact {
+		config.Redactor = nil
+	} else if len(secrets.Allow) > 0 || len(secrets.Deny) > 0 {
+		if redactor, err := scrub.NewSecretRedactor(secrets.Allow, secrets.Deny); err == nil {
+			config.Redactor = redactor
+		}
+	}
+
+	if len(c.Vars) > 0 {
+		if config.Variables == nil {
+			config.Variables = make(map[string]interface{}, len(c.Vars))
+		}
+		for name, value := range c.Vars {
+			config.Variables[name] = value
+		}
+	}
+}
+
+// ApplyRenderer copies the reporter settings that are set onto config
+func (c *Config) ApplyRenderer(config *renderer.RendererConfig) error {
+	report := c.Report
+	if report.MinCoverage != "" {
+		ratio, err := models.ParseCoverageThreshold(report.MinCoverage)
+		if err != nil {
+			return err
+		}
+		config.MinCoverage = ratio
+	}
+	if report.FailOn != "" || report.ExitZero != nil {
+		policy, err := renderer.ParseExitPolicy(report.FailOn)
+		if err != nil {
+			return err
+		}
+		setBool(&policy.ExitZero, report.ExitZero)
+		config.ExitPolicy = &policy
+	}
+	setBool(&config.ColorOutput, report.Color)
+	setInt(&config.Concurrency, c.Workers())
+
+	setString(&config.HTMLReportPath, report.HTML)
+	setString(&config.SARIFReportPath, report.SARIF)
+	setString(&config.JUnitReportPath, report.JUnit)
+	setString(&config.CodeQualityReportPath, report.CodeQuality)
+	setString(&config.CSVReportPath, report.CSV)
+	setString(&config.BadgeDir, report.BadgeDir)
+	setString(&config.PrometheusReportPath, report.Prometheus)
+	setString(&config.SummaryReportPath, report.Summary)
+	setString(&config.TraceUIURLTemplate, report.TraceUIURLTemplate)
+	return nil
+}
+
+// ApplyGeneration copies the explore thresholds that are set onto options
+func (c *Config) ApplyGeneration(options *engine.GenerationOptions) {
+	explore := c.Explore
+	if explore.PathClusteringThreshold > 0 {
+		options.PathClusteringThreshold = explore.PathClusteringThreshold
+	}
+	if explore.RequiredThreshold > 0 {
+		options.RequiredFieldThreshold = explore.RequiredThreshold
+	}
+	if explore.MinSampleSize > 0 {
+		options.MinSampleSize = explore.MinSampleSize
+	}
+	if explore.MinSamples > 0 {
+		options.MinEndpointSamples = explore.MinSamples
+	}
+	if explore.MaxUniqueValues > 0 {
+		options.MaxUniqueValues = explore.MaxUniqueValues
+	}
+	setString(&options.StatusAggregation, explore.StatusAggregation)
+	setString(&options.ServiceName, explore.ServiceName)
+	setString(&options.ServiceVersion, explore.ServiceVersion)
+}
+
+// ApplyIngest copies the explore ingestion settings that are set onto options
+func (c *Config) ApplyIngest(options *traffic.IngestOptions) {
+	explore := c.Explore
+	setString(&options.Format, explore.Format)
+	setString(&options.LogFormat, explore.LogFormat)
+	setString(&options.NginxLogFormat, explore.NginxLogFormat)
+	setFloat(&options.SampleRate, explore.SampleRate)
+	setFloat(&options.MaxErrorRate, explore.MaxErrorRate)
+	setInt(&options.MaxLineBytes, explore.MaxLineBytes)
+	setString(&options.LongLines, explore.LongLines)
+	setString(&options.Encoding, explore.Encoding)
+	setInt(&options.Parallelism, c.Workers())
+	setInt(&options.Parallelism, explore.Parallelism)
+	setString(&options.Mmap, explore.Mmap)
+}
+
+// VerifySignatures checks the signatures of the contract files of path with the configured
+// public key when signature.verify is set, and returns the verified files
+func (c *Config) VerifySignatures(path string) ([]string, error) {
+	if c.Signature.Verify == nil || !*c.Signature.Verify {
+		return nil, nil
+	}
+	key, err := signing.LoadPublicKey(c.Signature.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("signature.publicKey: %w", err)
+	}
+	return signing.VerifyPath(path, key)
+}
+
+// LoadPlugins discovers the process plugins of plugins.dirs and the default directories and
+// returns the registry of what they provide, next to the built-in x-wasm operator whose modules
+// are relative to the directory of the configuration file. Plugins that fail to load are
+// reported in the error while the others stay usable. Apply the registry to the engine with
+// Registry.ApplyEngine.
+func (c *Config) LoadPlugins(ctx context.Context) (*plugin.Registry, []*plugin.Process, error) {
+	baseDir := "."
+	if c.File != "" {
+		baseDir = filepath.Dir(c.File)
+	}
+	registry, err := plugin.NewBuiltinRegistry(baseDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	dirs := append(append([]string{}, c.Plugins.Dirs...), plugin.DefaultDirs()...)
+	processes, err := plugin.Load(ctx, registry, dirs)
+	for _, process := range processes {
+		process.Timeout = c.Plugins.Timeout
+	}
+	return registry, processes, err
+}
+
+// setBool assigns value to target when it is set
+func setBool(target *bool, value *bool) {
+	if value != nil {
+		*target = *value
+	}
+}
+
+// setBoolPointer replaces target when value is set
+func setBoolPointer(target **bool, value *bool) {
+	if value != nil {
+		*target = value
+	}
+}
+
+// setFloat assigns value to target when it is not zero
+func setFloat(target *float64, value float64) {
+	if value != 0 {
+		*target = value
+	}
+}
+
+// setInt assigns value to target when it is not zero
+func setInt(target *int, value int) {
+	if value != 0 {
+		*target = value
+	}
+}
+
+// setString assigns value to target when it is not empty
+func setString(target *string, value string) {
+	if value != "" {
+		*target = value
+	}
+}
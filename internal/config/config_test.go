@@ -0,0 +1,553 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/engine"
+	"github.com/flowspec/flowspec-cli/internal/errcode"
+	"github.com/flowspec/flowspec-cli/internal/httpclient"
+	"github.com/flowspec/flowspec-cli/internal/ingestor/traffic"
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/parser"
+	"github.com/flowspec/flowspec-cli/internal/plugin"
+	"github.com/flowspec/flowspec-cli/internal/remote"
+	"github.com/flowspec/flowspec-cli/internal/renderer"
+	"github.com/flowspec/flowspec-cli/internal/scrub"
+	"github.com/flowspec/flowspec-cli/internal/signing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleConfig = `path: contracts
+trace: traces/run.json
+vars:
+  tenant: acme
+tags: [critical, payments]
+attributeAliases:
+  method: [http.method, custom.verb]
+spanStatus:
+  - code: UNSET
+    as: OK
+suggestions:
+  - name: tenant
+    key: "*x-tenant*"
+    failure: missing
+    suggestion: Set X-Tenant at the gateway
+    runbook: https://runbooks.example.com/tenant
+engine:
+  maxConcurrency: 8
+  traceConcurrency: 16
+  timeout: 45s
+  strict: true
+  maxFlakeRate: 0.05
+  timeZone: UTC
+  clockSkew: 50ms
+  failOnIncompleteTrace: true
+  correctClockSkew: true
+  failFast: true
+  traceSampleRate: 0.1
+matcher:
+  skipMissingSpans: false
+  explain: true
+  ambiguity: fail
+report:
+  output: json
+  minCoverage: 80%
+  failOn: failures,skipped
+  html: artifacts/report.html
+  traceUIURLTemplate: https://jaeger.example.com/trace/{traceId}
+  scrub: true
+  detailLevel: full
+scrub:
+  rules:
+    - name: email
+      action: hash
+    - name: customer
+      keys: [customer.*]
+  secrets:
+    allow: ["*x-csrf-token"]
+    deny: ["*session_id"]
+signature:
+  verify: true
+  publicKey: keys/flowspec.pub
+explore:
+  traffic: /var/log/nginx
+  format: auto
+  requiredThreshold: 0.9
+  minSamples: 10
+  serviceName: orders
+  parallelism: 4
+  maxErrorRate: 0.05
+  longLines: truncate
+  encoding: auto
+  mmap: "off"
+  metricsOut: artifacts/ingest-metrics.json
+  outFormat: json
+  outDir: contracts/generated
+  stats: sidecar
+`
+
+func writeConfig(t *testing.T, dir, content string) string {
+	path := filepath.Join(dir, ".flowspec.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	config, err := Load(writeConfig(t, dir, sampleConfig))
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(dir, "contracts"), config.Path)
+	assert.Equal(t, filepath.Join(dir, "traces/run.json"), config.Trace)
+	assert.Equal(t, filepath.Join(dir, "artifacts/report.html"), config.Report.HTML)
+	assert.Equal(t, "/var/log/nginx", config.Explore.Traffic, "absolute paths are kept")
+	assert.Equal(t, filepath.Join(dir, "artifacts/ingest-metrics.json"), config.Explore.MetricsOut)
+	assert.Equal(t, filepath.Join(dir, "contracts/generated"), config.Explore.OutDir)
+	assert.Equal(t, engine.ContractFormatJSON, config.Explore.OutFormat)
+	assert.Equal(t, engine.StatsSidecar, config.Explore.Stats)
+	assert.Equal(t, 45*time.Second, config.Engine.Timeout)
+	assert.Equal(t, filepath.Join(dir, "keys/flowspec.pub"), config.Signature.PublicKey)
+	require.NotNil(t, config.Report.Scrub)
+	assert.True(t, *config.Report.Scrub)
+	assert.Equal(t, scrub.Rules{{Name: "email", Action: scrub.ActionHash}, {Name: "customer", Keys: []string{"customer.*"}}}, config.Scrub.Rules)
+
+	engineConfig := engine.DefaultEngineConfig()
+	config.ApplyEngine(engineConfig)
+	assert.Equal(t, 8, engineConfig.MaxConcurrency)
+	assert.Equal(t, 16, engineConfig.TraceConcurrency)
+	assert.Equal(t, 45*time.Second, engineConfig.Timeout)
+	assert.True(t, engineConfig.StrictMode)
+	assert.Equal(t, 0.05, engineConfig.MaxFlakeRate)
+	assert.Equal(t, time.UTC, engineConfig.TimeZone)
+	assert.Equal(t, 50*time.Millisecond, engineConfig.ClockSkew)
+	assert.True(t, engineConfig.FailOnIncomplete)
+	assert.True(t, engineConfig.CorrectClockSkew)
+	assert.True(t, engineConfig.FailFast)
+	assert.Equal(t, 0.1, engineConfig.TraceSampleRate)
+	assert.False(t, engineConfig.SkipMissingSpans)
+	assert.True(t, engineConfig.Explain)
+	assert.Equal(t, engine.AmbiguityFail, engineConfig.AmbiguityPolicy)
+	assert.False(t, engineConfig.ReportUnmatched, "unset values keep their defaults")
+	assert.Equal(t, "acme", engineConfig.Variables["tenant"])
+	assert.Equal(t, []string{"critical", "payments"}, engineConfig.Tags)
+	assert.Equal(t, []string{"http.method", "custom.verb"}, engineConfig.AttributeAliases["method"])
+	assert.Equal(t, models.SpanStatusRules{{Code: "UNSET", As: "OK"}}, engineConfig.SpanStatus)
+	assert.Equal(t, models.SuggestionRules{{Name: "tenant", Key: "*x-tenant*", Failure: "missing",
+		Suggestion: "Set X-Tenant at the gateway", Runbook: "https://runbooks.example.com/tenant"}}, engineConfig.SuggestionRules)
+	assert.Equal(t, engine.DetailFull, engineConfig.DetailLevel)
+	require.NotNil(t, engineConfig.Redactor)
+	assert.Equal(t, map[string]interface{}{"app.session_id": "<token>", "http.request.header.x-csrf-token": "c1"},
+		engineConfig.Redactor.Attributes(map[string]interface{}{"app.session_id": "s1", "http.request.header.x-csrf-token": "c1"}))
+
+	rendererConfig := renderer.DefaultRendererConfig()
+	require.NoError(t, config.ApplyRenderer(rendererConfig))
+	assert.Equal(t, 0.8, rendererConfig.MinCoverage)
+	require.NotNil(t, rendererConfig.ExitPolicy)
+	assert.True(t, rendererConfig.ExitPolicy.FailOnSkipped)
+	assert.Equal(t, filepath.Join(dir, "artifacts/report.html"), rendererConfig.HTMLReportPath)
+	assert.True(t, rendererConfig.ColorOutput)
+
+	options := engine.DefaultGenerationOptions()
+	config.ApplyGeneration(options)
+	assert.Equal(t, 0.9, options.RequiredFieldThreshold)
+	assert.Equal(t, 10, options.MinEndpointSamples)
+	assert.Equal(t, "orders", options.ServiceName)
+	assert.Equal(t, 0.8, options.PathClusteringThreshold)
+
+	ingestOptions := traffic.DefaultIngestOptions()
+	config.ApplyIngest(ingestOptions)
+	assert.Equal(t, 4, ingestOptions.Parallelism)
+	assert.Equal(t, traffic.FormatAuto, ingestOptions.Format)
+	assert.Equal(t, "combined", ingestOptions.LogFormat)
+	assert.Equal(t, 0.05, ingestOptions.MaxErrorRate)
+	assert.Equal(t, traffic.LongLinesTruncate, ingestOptions.LongLines)
+	assert.Equal(t, traffic.EncodingAuto, ingestOptions.Encoding)
+	assert.Equal(t, traffic.MmapOff, ingestOptions.Mmap)
+	assert.Equal(t, traffic.DefaultMaxLineBytes, ingestOptions.MaxLineBytes)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{name: "unknown field", content: "paths: contracts\n"},
+		{name: "bad output", content: "report:\n  output: xml\n"},
+		{name: "bad coverage", content: "report:\n  minCoverage: lots\n"},
+		{name: "bad exit condition", content: "report:\n  failOn: flakes\n"},
+		{name: "unknown detail level", content: "report:\n  detailLevel: verbose\n"},
+		{name: "unknown ambiguity policy", content: "matcher:\n  ambiguity: first\n"},
+		{name: "bad trace template", content: "report:\n  traceUIURLTemplate: https://jaeger/search\n"},
+		{name: "bad ratio", content: "explore:\n  sampleRate: 2\n"},
+		{name: "bad error rate", content: "explore:\n  maxErrorRate: -0.1\n"},
+		{name: "bad long-line policy", content: "explore:\n  longLines: wrap\n"},
+		{name: "unknown encoding", content: "explore:\n  encoding: klingon\n"},
+		{name: "bad mmap policy", content: "explore:\n  mmap: always\n"},
+		{name: "unknown contract format", content: "explore:\n  outFormat: toml\n"},
+		{name: "unknown stats placement", content: "explore:\n  stats: footer\n"},
+		{name: "empty tag", content: "tags: [critical, '']\n"},
+		{name: "negative line length", content: "explore:\n  maxLineBytes: -1\n"},
+		{name: "bad duration", content: "engine:\n  timeout: soon\n"},
+		{name: "negative parallelism", content: "explore:\n  parallelism: -1\n"},
+		{name: "bad flake rate", content: "engine:\n  maxFlakeRate: 1\n"},
+		{name: "signature without key", content: "signature:\n  verify: true\n"},
+		{name: "bad trace sample rate", content: "engine:\n  traceSampleRate: 1.5\n"},
+		{name: "unknown time zone", content: "engine:\n  timeZone: Mars/Olympus_Mons\n"},
+		{name: "negative clock skew", content: "engine:\n  clockSkew: -1ms\n"},
+		{name: "negative trace concurrency", content: "engine:\n  traceConcurrency: -1\n"},
+		{name: "unknown alias field", content: "attributeAliases:\n  verb: [custom.verb]\n"},
+		{name: "unknown traffic format", content: "explore:\n  format: envoy\n"},
+		{name: "bad nginx log format", content: "explore:\n  nginxLogFormat: '$remote_addr $status'\n"},
+		{name: "empty alias list", content: "attributeAliases:\n  method: []\n"},
+		{name: "unknown language", content: "lang: tlh\n"},
+		{name: "unknown span status", content: "spanStatus:\n  - code: TIMEOUT\n    as: OK\n"},
+		{name: "bad span status message", content: "spanStatus:\n  - code: ERROR\n    message: '('\n    as: OK\n"},
+		{name: "suggestion without text", content: "suggestions:\n  - name: tenant\n    key: x-tenant\n"},
+		{name: "bad remote checksum", content: "path: https://contracts.example.com/orders.yaml#sha256=abc\n"},
+		{name: "bad OCI reference", content: "path: oci://ghcr.io\n"},
+		{name: "negative remote timeout", content: "remote:\n  timeout: -1s\n"},
+		{name: "negative remote attempts", content: "remote:\n  attempts: -1\n"},
+		{name: "bad proxy", content: "network:\n  proxy: ftp://proxy.example.com\n"},
+		{name: "client certificate without key", content: "network:\n  certFile: client.pem\n"},
+		{name: "scrub rule without keys or pattern", content: "scrub:\n  rules:\n    - name: customer\n"},
+		{name: "unknown scrub action", content: "scrub:\n  rules:\n    - name: email\n      action: erase\n"},
+		{name: "bad secret key pattern", content: "scrub:\n  secrets:\n    deny: ['[']\n"},
+		{name: "bad version constraint", content: "requireVersion: '~0.6'\n"},
+		{name: "bad concurrency", content: "concurrency: 0\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, t.TempDir(), tc.content))
+			assert.Error(t, err)
+			assert.Equal(t, renderer.ExitUsageError, errcode.ExitCode(err))
+		})
+	}
+}
+
+func TestLoad_RemotePath(t *testing.T) {
+	dir := t.TempDir()
+	config, err := Load(writeConfig(t, dir, "path: s3://contracts/orders.yaml\nremote:\n  cacheDir: .cache/contracts\n  timeout: 5s\n  attempts: 5\n  backoff: 2s\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "s3://contracts/orders.yaml", config.Path, "remote paths are not resolved against the config directory")
+
+	options := remote.Options{}
+	config.ApplyRemote(&options)
+	assert.Equal(t, filepath.Join(dir, ".cache/contracts"), options.CacheDir)
+	assert.Equal(t, 5*time.Second, options.Timeout)
+	require.NotNil(t, options.Retry)
+	assert.Equal(t, remote.RetryPolicy{Attempts: 5, InitialBackoff: 2 * time.Second, MaxBackoff: remote.DefaultRetryPolicy().MaxBackoff}, *options.Retry)
+}
+
+func TestVerifySignatures(t *testing.T) {
+	dir := t.TempDir()
+	publicPEM, privatePEM, err := signing.GenerateKeyPair()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "flowspec.pub"), publicPEM, 0644))
+	private, err := signing.ParsePrivateKey(privatePEM)
+	require.NoError(t, err)
+	contracts := filepath.Join(dir, "contracts")
+	require.NoError(t, os.MkdirAll(contracts, 0755))
+	contract := filepath.Join(contracts, "service-spec.yaml")
+	require.NoError(t, os.WriteFile(contract, []byte("kind: ServiceSpec\n"), 0644))
+
+	config, err := Load(writeConfig(t, dir, "path: contracts\nsignature:\n  publicKey: flowspec.pub\n"))
+	require.NoError(t, err)
+	verified, err := config.VerifySignatures(config.Path)
+	require.NoError(t, err)
+	assert.Empty(t, verified, "signatures are only verified when asked to")
+
+	config, err = Load(writeConfig(t, dir, "path: contracts\nsignature:\n  verify: true\n  publicKey: flowspec.pub\n"))
+	require.NoError(t, err)
+	_, err = config.VerifySignatures(config.Path)
+	assert.ErrorIs(t, err, signing.ErrMissingSignature)
+
+	_, err = signing.SignFile(contract, private)
+	require.NoError(t, err)
+	verified, err = config.VerifySignatures(config.Path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{contract}, verified)
+}
+
+func TestLoadPlugins_CustomOperator(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugins need a POSIX shell")
+	}
+	t.Setenv(plugin.PathEnv, "")
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "plugins"), 0755))
+	// The plugin passes spans whose target is under the path given as the operator's argument
+	script := `#!/bin/sh
+case "$1" in
+describe) echo '{"name": "acme", "version": "1.0.0", "protocolVersion": 1, "operators": ["x-acme-prefix"]}' ;;
+evaluate) input=$(cat)
+  case "$input" in
+  *'"arguments":"/users"'*'"http.target":"/users/'*) echo '{"passed": true}' ;;
+  *) echo '{"passed": false, "message": "target outside the prefix"}' ;;
+  esac ;;
+esac
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plugins", plugin.ExecutablePrefix+"acme"), []byte(script), 0755))
+	source := filepath.Join(dir, "users.go")
+	require.NoError(t, os.WriteFile(source, []byte(`package users
+
+// @ServiceSpec
+// operationId: "getUser"
+// description: "Fetch a user"
+// postconditions:
+//   "x-acme-prefix": "/users"
+//   "==": [{"var": "span.attributes.http.status_code"}, 200]
+func GetUser() {}
+`), 0644))
+
+	config, err := Load(writeConfig(t, dir, "plugins:\n  dirs: [plugins]\n  timeout: 5s\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "plugins")}, config.Plugins.Dirs)
+	registry, processes, err := config.LoadPlugins(context.Background())
+	require.NoError(t, err)
+	require.Len(t, processes, 1)
+	assert.Equal(t, 5*time.Second, processes[0].Timeout)
+	assert.Equal(t, []string{"x-acme-prefix", plugin.WASMOperator}, registry.Operators())
+	wasm, ok := registry.Evaluator(plugin.WASMOperator)
+	require.True(t, ok)
+	assert.Equal(t, dir, wasm.(*plugin.WASMEvaluator).BaseDir)
+
+	specs, parseErrors := parser.NewGoFileParser().ParseFile(source)
+	require.Empty(t, parseErrors)
+	engineConfig := engine.DefaultEngineConfig()
+	config.ApplyEngine(engineConfig)
+	registry.ApplyEngine(engineConfig)
+	alignmentEngine := engine.NewAlignmentEngineWithConfig(engineConfig)
+	defer alignmentEngine.Close()
+
+	align := func(target string) models.AlignmentStatus {
+		span := &models.Span{SpanID: "s1", TraceID: "t1", Name: "getUser", StartTime: 1, EndTime: 2,
+			Status:     models.SpanStatus{Code: "OK"},
+			Attributes: map[string]interface{}{"operation.id": "getUser", "http.target": target, "http.status_code": 200}}
+		traceData := &models.TraceData{TraceID: "t1", Spans: map[string]*models.Span{"s1": span}}
+		require.NoError(t, traceData.BuildSpanTree())
+		report, err := alignmentEngine.AlignSpecsWithTraceContext(context.Background(), specs, traceData)
+		require.NoError(t, err)
+		require.Len(t, report.Results, 1)
+		return report.Results[0].Status
+	}
+	assert.Equal(t, models.StatusSuccess, align("/users/42"))
+	assert.Equal(t, models.StatusFailed, align("/accounts/42"), "the plugin operator fails the span")
+}
+
+func TestLoad_Network(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("FLOWSPEC_PROXY", "")
+	t.Setenv("FLOWSPEC_CA_FILE", "")
+	t.Setenv("FLOWSPEC_CLIENT_CERT", "")
+	t.Setenv("FLOWSPEC_CLIENT_KEY", "")
+	config, err := Load(writeConfig(t, dir, "network:\n  proxy: http://proxy.example.com:3128\n  caFile: certs/ca.pem\n  certFile: certs/client.pem\n  keyFile: certs/client-key.pem\n"))
+	require.NoError(t, err)
+
+	options := remote.Options{}
+	config.ApplyRemote(&options)
+	require.NotNil(t, options.Network)
+	assert.Equal(t, httpclient.Config{
+		Proxy:    "http://proxy.example.com:3128",
+		CAFile:   filepath.Join(dir, "certs/ca.pem"),
+		CertFile: filepath.Join(dir, "certs/client.pem"),
+		KeyFile:  filepath.Join(dir, "certs/client-key.pem"),
+	}, *options.Network)
+
+	t.Setenv("FLOWSPEC_PROXY", "socks5://localhost:1080")
+	t.Setenv("FLOWSPEC_CLIENT_CERT", "/etc/flowspec/client.pem")
+	t.Setenv("FLOWSPEC_CLIENT_KEY", "/etc/flowspec/client-key.pem")
+	network := config.HTTPClientConfig()
+	assert.Equal(t, "socks5://localhost:1080", network.Proxy, "the environment takes precedence")
+	assert.Equal(t, filepath.Join(dir, "certs/ca.pem"), network.CAFile)
+	assert.Equal(t, "/etc/flowspec/client.pem", network.CertFile)
+	assert.Equal(t, "/etc/flowspec/client-key.pem", network.KeyFile)
+}
+
+func TestLoad_Offline(t *testing.T) {
+	t.Setenv("FLOWSPEC_OFFLINE", "")
+	config, err := LoadProfile(writeConfig(t, t.TempDir(), "network:\n  offline: true\nprofiles:\n  online:\n    network:\n      offline: false\n"), "")
+	require.NoError(t, err)
+	assert.True(t, config.HTTPClientConfig().Offline)
+	assert.False(t, config.withProfile("online").HTTPClientConfig().Offline)
+
+	t.Setenv("FLOWSPEC_OFFLINE", "1")
+	assert.True(t, config.withProfile("online").HTTPClientConfig().Offline, "the environment turns offline mode on")
+}
+
+func TestLoad_Concurrency(t *testing.T) {
+	config, err := LoadProfile(writeConfig(t, t.TempDir(), "concurrency: 16\nengine:\n  traceConcurrency: 2\nprofiles:\n  auto:\n    concurrency: auto\n"), "")
+	require.NoError(t, err)
+	assert.Equal(t, 16, config.Workers())
+
+	engineConfig := engine.DefaultEngineConfig()
+	config.ApplyEngine(engineConfig)
+	assert.Equal(t, 16, engineConfig.MaxConcurrency)
+	assert.Equal(t, 2, engineConfig.TraceConcurrency, "engine.traceConcurrency overrides concurrency")
+
+	ingest := traffic.DefaultIngestOptions()
+	config.ApplyIngest(ingest)
+	assert.Equal(t, 16, ingest.Parallelism)
+
+	rendererConfig := renderer.DefaultRendererConfig()
+	require.NoError(t, config.ApplyRenderer(rendererConfig))
+	assert.Equal(t, 16, rendererConfig.Concurrency)
+
+	auto := config.withProfile("auto")
+	assert.Equal(t, 0, auto.Workers())
+	engineConfig = engine.DefaultEngineConfig()
+	auto.ApplyEngine(engineConfig)
+	assert.Equal(t, engine.DefaultEngineConfig().MaxConcurrency, engineConfig.MaxConcurrency)
+}
+
+func TestLoad_RequireVersion(t *testing.T) {
+	config, err := LoadProfile(writeConfig(t, t.TempDir(), "requireVersion: '>=0.6 <0.8'\nprofiles:\n  next:\n    requireVersion: '>=0.8'\n"), "")
+	require.NoError(t, err)
+	assert.NoError(t, config.CheckVersion("0.7.2"))
+	assert.NoError(t, config.CheckVersion("0.1.0-dev"), "development builds are not pinned")
+
+	err = config.CheckVersion("0.8.1")
+	require.Error(t, err)
+	assert.Equal(t, errcode.VersionMismatch, errcode.Of(err))
+	assert.Equal(t, renderer.ExitUsageError, errcode.ExitCode(err))
+	assert.Contains(t, err.Error(), config.File)
+
+	next := config.withProfile("next")
+	assert.NoError(t, next.CheckVersion("0.8.1"))
+	assert.NoError(t, (&Config{}).CheckVersion("0.1.0"))
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "services", "orders")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+
+	path, err := Find(nested)
+	require.NoError(t, err)
+	assert.Empty(t, path)
+
+	config, err := LoadFromDir(nested, "")
+	require.NoError(t, err)
+	assert.Empty(t, config.File)
+
+	expected := writeConfig(t, root, "lang: zh\n")
+	path, err = Find(nested)
+	require.NoError(t, err)
+	assert.Equal(t, expected, path)
+
+	config, err = LoadFromDir(nested, "")
+	require.NoError(t, err)
+	assert.Equal(t, "zh", config.Lang)
+
+	// An empty file is a valid configuration
+	_, err = Load(writeConfig(t, nested, ""))
+	assert.NoError(t, err)
+}
+
+func TestLoadProfile(t *testing.T) {
+	content := `trace: traces/local.json
+vars:
+  tenant: acme
+  region: eu
+report:
+  minCoverage: 50%
+  html: artifacts/report.html
+profiles:
+  ci:
+    trace: traces/ci.json
+    report:
+      minCoverage: 80%
+      failOn: failures,skipped
+  nightly:
+    vars:
+      region: us
+    engine:
+      strict: true
+    report:
+      exitZero: true
+    scrub:
+      secrets:
+        redact: false
+  local:
+`
+	dir := t.TempDir()
+	path := writeConfig(t, dir, content)
+
+	base, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ci", "local", "nightly"}, base.ProfileNames())
+	assert.Empty(t, base.Profile)
+	assert.Equal(t, "50%", base.Report.MinCoverage)
+
+	ci, err := LoadProfile(path, "ci")
+	require.NoError(t, err)
+	assert.Equal(t, "ci", ci.Profile)
+	assert.Equal(t, filepath.Join(dir, "traces/ci.json"), ci.Trace)
+	assert.Equal(t, "80%", ci.Report.MinCoverage)
+	assert.Equal(t, filepath.Join(dir, "artifacts/report.html"), ci.Report.HTML, "unset profile values inherit the base")
+
+	nightly, err := LoadProfile(path, "nightly")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"tenant": "acme", "region": "us"}, nightly.Vars)
+	assert.Equal(t, map[string]interface{}{"tenant": "acme", "region": "eu"}, base.Vars, "the base is not modified")
+	rendererConfig := renderer.DefaultRendererConfig()
+	require.NoError(t, nightly.ApplyRenderer(rendererConfig))
+	assert.True(t, rendererConfig.ExitPolicy.ExitZero)
+	require.NotNil(t, nightly.Engine.Strict)
+	assert.True(t, *nightly.Engine.Strict)
+	engineConfig := engine.DefaultEngineConfig()
+	nightly.ApplyEngine(engineConfig)
+	assert.Nil(t, engineConfig.Redactor, "secrets are quoted verbatim")
+	engineConfig = engine.DefaultEngineConfig()
+	ci.ApplyEngine(engineConfig)
+	assert.NotNil(t, engineConfig.Redactor, "secrets are redacted by default")
+
+	local, err := LoadProfile(path, "local")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "traces/local.json"), local.Trace)
+
+	_, err = LoadProfile(path, "staging")
+	assert.ErrorContains(t, err, "available: ci, local, nightly")
+
+	_, err = LoadFromDir(t.TempDir(), "ci")
+	assert.Error(t, err, "a profile without a config file is an error")
+}
+
+func TestLoadProfile_Invalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{name: "invalid value in unused profile", content: "profiles:\n  ci:\n    report:\n      output: xml\n"},
+		{name: "unknown field in profile", content: "profiles:\n  ci:\n    reports:\n      html: report.html\n"},
+		{name: "nested profiles", content: "profiles:\n  ci:\n    profiles:\n      fast: {}\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, t.TempDir(), tc.content))
+			assert.Error(t, err)
+		})
+	}
+}
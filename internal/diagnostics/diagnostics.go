@@ -0,0 +1,137 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diagnostics implements the --pprof, --cpuprofile, --memprofile and --debug-timings
+// flags, so performance problems on customer data can be investigated with a release build
+package diagnostics
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"runtime"
+	rpprof "runtime/pprof"
+)
+
+// Options selects the diagnostics of a run
+type Options struct {
+	PprofAddr    string // Serve net/http/pprof on this address, e.g. ":6060" or "localhost:6060"
+	CPUProfile   string // Write a CPU profile to this file
+	MemProfile   string // Write a heap profile to this file when the run ends
+	DebugTimings bool   // Record per-stage timings
+}
+
+// Session holds the diagnostics started for a run
+type Session struct {
+	options  Options
+	listener net.Listener
+	server   *http.Server
+	cpuFile  *os.File
+	Timings  *Timings // Nil unless DebugTimings is set; a nil Timings ignores all calls
+}
+
+// Start starts the requested diagnostics. Stop must be called when the run ends.
+func Start(options Options) (*Session, error) {
+	session := &Session{options: options}
+	if options.DebugTimings {
+		session.Timings = NewTimings()
+	}
+
+	if options.PprofAddr != "" {
+		listener, err := net.Listen("tcp", options.PprofAddr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen for pprof on %s: %w", options.PprofAddr, err)
+		}
+		session.listener = listener
+		session.server = &http.Server{Handler: Handler()}
+		go session.server.Serve(listener)
+	}
+
+	if options.CPUProfile != "" {
+		file, err := os.Create(options.CPUProfile)
+		if err != nil {
+			session.Stop()
+			return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		if err := rpprof.StartCPUProfile(file); err != nil {
+			file.Close()
+			session.Stop()
+			return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		session.cpuFile = file
+	}
+
+	return session, nil
+}
+
+// PprofAddr returns the address the pprof server listens on, useful with port 0
+func (s *Session) PprofAddr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Stop stops the CPU profile, writes the heap profile and shuts the pprof server down
+func (s *Session) Stop() error {
+	var errs []error
+	if s.cpuFile != nil {
+		rpprof.StopCPUProfile()
+		if err := s.cpuFile.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write CPU profile: %w", err))
+		}
+		s.cpuFile = nil
+	}
+	if s.options.MemProfile != "" {
+		if err := WriteHeapProfile(s.options.MemProfile); err != nil {
+			errs = append(errs, err)
+		}
+		s.options.MemProfile = ""
+	}
+	if s.server != nil {
+		s.server.Close()
+		s.server = nil
+	}
+	return errors.Join(errs...)
+}
+
+// Handler serves the net/http/pprof endpoints under /debug/pprof/ without touching
+// http.DefaultServeMux
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+// WriteHeapProfile writes a heap profile of the live objects after a collection
+func WriteHeapProfile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create memory profile: %w", err)
+	}
+	defer file.Close()
+
+	runtime.GC() // Profile live objects only
+	if err := rpprof.WriteHeapProfile(file); err != nil {
+		return fmt.Errorf("failed to write memory profile: %w", err)
+	}
+	return file.Close()
+}
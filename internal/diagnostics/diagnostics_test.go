@@ -0,0 +1,92 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostics
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession(t *testing.T) {
+	dir := t.TempDir()
+	options := Options{
+		PprofAddr:    "127.0.0.1:0",
+		CPUProfile:   filepath.Join(dir, "cpu.pprof"),
+		MemProfile:   filepath.Join(dir, "mem.pprof"),
+		DebugTimings: true,
+	}
+	session, err := Start(options)
+	require.NoError(t, err)
+	require.NotNil(t, session.Timings)
+
+	response, err := http.Get("http://" + session.PprofAddr() + "/debug/pprof/cmdline")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(response.Body)
+	response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, body)
+
+	require.NoError(t, session.Stop())
+	require.NoError(t, session.Stop(), "stopping twice is harmless")
+	for _, path := range []string{options.CPUProfile, options.MemProfile} {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.NotZero(t, info.Size(), path)
+	}
+}
+
+func TestStart_Errors(t *testing.T) {
+	_, err := Start(Options{CPUProfile: filepath.Join(t.TempDir(), "missing", "cpu.pprof")})
+	assert.ErrorContains(t, err, "failed to create CPU profile")
+
+	_, err = Start(Options{PprofAddr: "not an address"})
+	assert.ErrorContains(t, err, "failed to listen for pprof")
+}
+
+func TestTimings(t *testing.T) {
+	now := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+	timings := &Timings{start: now, now: func() time.Time { return now }}
+
+	done := timings.Start("parse contracts")
+	now = now.Add(250 * time.Millisecond)
+	done()
+	timings.Add("align", 500*time.Millisecond)
+	timings.Add("align", 250*time.Millisecond)
+
+	stages := timings.Stages()
+	require.Len(t, stages, 2)
+	assert.Equal(t, Stage{Name: "align", Duration: 750 * time.Millisecond, Calls: 2}, stages[1])
+
+	now = now.Add(750 * time.Millisecond)
+	var output strings.Builder
+	timings.Render(&output)
+	assert.Equal(t, "⏱️  Timings\n"+
+		"  parse contracts      250ms  25.0%\n"+
+		"  align                750ms  75.0% (2 calls)\n"+
+		"  total                   1s\n", output.String())
+
+	var disabled *Timings
+	disabled.Start("anything")()
+	disabled.Render(&output)
+	assert.Nil(t, disabled.Stages())
+}
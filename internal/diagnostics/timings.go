@@ -0,0 +1,130 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostics
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stage is the measured duration of one stage of a run
+type Stage struct {
+	Name     string
+	Duration time.Duration
+	Calls    int
+}
+
+// Timings records how long each stage of a run takes. A nil Timings ignores all calls.
+type Timings struct {
+	mu     sync.Mutex
+	start  time.Time
+	stages []*Stage
+	now    func() time.Time
+}
+
+// NewTimings starts recording
+func NewTimings() *Timings {
+	return &Timings{start: time.Now(), now: time.Now}
+}
+
+// Start begins timing a stage and returns the function that ends it. Stages with the same name
+// are summed, so a stage can be timed across several calls.
+func (t *Timings) Start(name string) func() {
+	if t == nil {
+		return func() {}
+	}
+	started := t.now()
+	return func() {
+		t.Add(name, t.now().Sub(started))
+	}
+}
+
+// Add records a duration for a stage
+func (t *Timings) Add(name string, duration time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, stage := range t.stages {
+		if stage.Name == name {
+			stage.Duration += duration
+			stage.Calls++
+			return
+		}
+	}
+	t.stages = append(t.stages, &Stage{Name: name, Duration: duration, Calls: 1})
+}
+
+// Stages returns the stages in the order they were first recorded
+func (t *Timings) Stages() []Stage {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stages := make([]Stage, len(t.stages))
+	for i, stage := range t.stages {
+		stages[i] = *stage
+	}
+	return stages
+}
+
+// Render writes the timing breakdown, e.g. for stderr at the end of a run
+func (t *Timings) Render(w io.Writer) {
+	if t == nil {
+		return
+	}
+	stages := t.Stages()
+	total := t.now().Sub(t.start)
+
+	width := len("total")
+	for _, stage := range stages {
+		if len(stage.Name) > width {
+			width = len(stage.Name)
+		}
+	}
+
+	var output strings.Builder
+	output.WriteString("⏱️  Timings\n")
+	for _, stage := range stages {
+		share := 0.0
+		if total > 0 {
+			share = float64(stage.Duration) / float64(total) * 100
+		}
+		calls := ""
+		if stage.Calls > 1 {
+			calls = fmt.Sprintf(" (%d calls)", stage.Calls)
+		}
+		output.WriteString(fmt.Sprintf("  %-*s %10s %5.1f%%%s\n", width, stage.Name, formatDuration(stage.Duration), share, calls))
+	}
+	output.WriteString(fmt.Sprintf("  %-*s %10s\n", width, "total", formatDuration(total)))
+	io.WriteString(w, output.String())
+}
+
+// formatDuration rounds a duration to a readable precision
+func formatDuration(duration time.Duration) string {
+	switch {
+	case duration >= time.Second:
+		return duration.Round(time.Millisecond).String()
+	case duration >= time.Millisecond:
+		return duration.Round(10 * time.Microsecond).String()
+	default:
+		return duration.Round(time.Microsecond).String()
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/renderer"
+)
+
+// IssueKind identifies a way in which a provider contract does not satisfy a consumer contract
+type IssueKind string
+
+const (
+	MissingService        IssueKind = "missing_service"
+	MissingEndpoint       IssueKind = "missing_endpoint"
+	MissingOperation      IssueKind = "missing_operation"
+	UnhandledStatus       IssueKind = "unhandled_status"   // The provider may respond with a status the consumer does not expect
+	UnreachableStatus     IssueKind = "unreachable_status" // The consumer expects a status the provider never responds with
+	MissingRequiredHeader IssueKind = "missing_required_header"
+	MissingRequiredQuery  IssueKind = "missing_required_query"
+)
+
+// pathParameter matches the parameters of a path, whose names do not matter for compatibility
+var pathParameter = regexp.MustCompile(`\{[^}]*\}`)
+
+// Issue describes an expectation of the consumer that the provider does not meet
+type Issue struct {
+	Kind     IssueKind `json:"kind"`
+	Severity Severity  `json:"severity"` // SeverityBreaking for incompatibilities, SeverityWarning otherwise
+	Service  string    `json:"service"`
+	Path     string    `json:"path,omitempty"` // Path as written in the consumer contract
+	Method   string    `json:"method,omitempty"`
+	Value    string    `json:"value,omitempty"` // Status, header or query name affected
+	Message  string    `json:"message"`
+}
+
+// CompatResult is the outcome of checking a provider contract against a consumer contract
+type CompatResult struct {
+	Compatible bool    `json:"compatible"`
+	Issues     []Issue `json:"issues"`
+}
+
+// Incompatibilities returns the issues that make the contracts incompatible
+func (r *CompatResult) Incompatibilities() []Issue {
+	var incompatible []Issue
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityBreaking {
+			incompatible = append(incompatible, issue)
+		}
+	}
+	return incompatible
+}
+
+// ExitCode returns the exit code for CI gating: validation failed when the contracts are incompatible
+func (r *CompatResult) ExitCode() int {
+	if !r.Compatible {
+		return renderer.ExitValidationFailed
+	}
+	return renderer.ExitSuccess
+}
+
+// CheckCompatibilityFiles parses a provider and a consumer contract (files or directories) and
+// checks that the provider satisfies the consumer
+func CheckCompatibilityFiles(providerPath, consumerPath string) (*CompatResult, error) {
+	providerSpecs, err := LoadSpecs(providerPath)
+	if err != nil {
+		return nil, err
+	}
+	consumerSpecs, err := LoadSpecs(consumerPath)
+	if err != nil {
+		return nil, err
+	}
+	return CheckCompatibility(providerSpecs, consumerSpecs), nil
+}
+
+// CheckCompatibility checks both directions of every operation the consumer contract declares:
+// the requests of the consumer carry every field the provider requires, and the consumer
+// expects every status the provider may respond with. Services are matched by name, or
+// directly when each side holds a single service; endpoints by path, whatever the names of
+// their path parameters. Endpoints the consumer does not use are ignored.
+func CheckCompatibility(providerSpecs, consumerSpecs []models.ServiceSpec) *CompatResult {
+	providerSpecs, consumerSpecs = yamlSpecs(providerSpecs), yamlSpecs(consumerSpecs)
+	result := &CompatResult{Issues: []Issue{}}
+
+	if len(providerSpecs) == 1 && len(consumerSpecs) == 1 {
+		result.checkService(providerSpecs[0], consumerSpecs[0])
+	} else {
+		providers := specsByName(providerSpecs)
+		for _, consumer := range consumerSpecs {
+			provider, ok := providers[consumer.Metadata.Name]
+			if !ok {
+				result.add(Issue{Kind: MissingService, Severity: SeverityBreaking, Service: consumer.Metadata.Name,
+					Message: "service not provided"})
+				continue
+			}
+			result.checkService(provider, consumer)
+		}
+	}
+
+	result.Compatible = len(result.Incompatibilities()) == 0
+	return result
+}
+
+// checkService checks the operations the consumer declares against the provider service
+func (r *CompatResult) checkService(provider, consumer models.ServiceSpec) {
+	service := consumer.Metadata.Name
+	providerEndpoints := make(map[string]models.EndpointSpec, len(provider.Spec.Endpoints))
+	for _, endpoint := range provider.Spec.Endpoints {
+		providerEndpoints[pathShape(endpoint.Path)] = endpoint
+	}
+
+	for _, endpoint := range consumer.Spec.Endpoints {
+		providerEndpoint, ok := providerEndpoints[pathShape(endpoint.Path)]
+		if !ok {
+			r.add(Issue{Kind: MissingEndpoint, Severity: SeverityBreaking, Service: service, Path: endpoint.Path,
+				Message: "endpoint not provided"})
+			continue
+		}
+		providerOperations := operationsByMethod(providerEndpoint.Operations)
+		for _, operation := range endpoint.Operations {
+			method := strings.ToUpper(operation.Method)
+			base := Issue{Service: service, Path: endpoint.Path, Method: method}
+			providerOperation, ok := providerOperations[method]
+			if !ok {
+				base.Kind, base.Severity, base.Message = MissingOperation, SeverityBreaking, "operation not provided"
+				r.add(base)
+				continue
+			}
+			r.checkStatuses(base, providerOperation.Responses, operation.Responses)
+			r.checkRequired(base, providerOperation.Required.Headers, operation.Required.Headers,
+				operation.Optional.Headers, MissingRequiredHeader, "header", true)
+			r.checkRequired(base, providerOperation.Required.Query, operation.Required.Query,
+				operation.Optional.Query, MissingRequiredQuery, "query parameter", false)
+		}
+	}
+}
+
+// checkStatuses checks that the consumer expects every status the provider may respond with, and
+// warns about expected statuses the provider never responds with. A side declaring no
+// statuses places no constraint.
+func (r *CompatResult) checkStatuses(base Issue, provider, consumer models.ResponseSpec) {
+	if len(provider.StatusCodes)+len(provider.StatusRanges) == 0 || len(consumer.StatusCodes)+len(consumer.StatusRanges) == 0 {
+		return
+	}
+	providerRanges := toSet(lowerAll(provider.StatusRanges))
+	consumerRanges := toSet(lowerAll(consumer.StatusRanges))
+	providerCodes := toSet(intStrings(provider.StatusCodes))
+	consumerCodes := toSet(intStrings(consumer.StatusCodes))
+
+	for _, code := range intStrings(provider.StatusCodes) {
+		if !consumerCodes[code] && !consumerRanges[statusClass(code)] {
+			r.add(withIssue(base, UnhandledStatus, SeverityBreaking, code,
+				fmt.Sprintf("provider may respond %s, which the consumer does not expect", code)))
+		}
+	}
+	for _, class := range lowerAll(provider.StatusRanges) {
+		if !consumerRanges[class] {
+			r.add(withIssue(base, UnhandledStatus, SeverityBreaking, class,
+				fmt.Sprintf("provider may respond any %s status, which the consumer does not expect", class)))
+		}
+	}
+
+	for _, code := range intStrings(consumer.StatusCodes) {
+		if !providerCodes[code] && !providerRanges[statusClass(code)] {
+			r.add(withIssue(base, UnreachableStatus, SeverityWarning, code,
+				fmt.Sprintf("consumer expects %s, which the provider never responds", code)))
+		}
+	}
+	for _, class := range lowerAll(consumer.StatusRanges) {
+		if providerRanges[class] || hasCodeInClass(provider.StatusCodes, class) {
+			continue
+		}
+		r.add(withIssue(base, UnreachableStatus, SeverityWarning, class,
+			fmt.Sprintf("consumer expects %s statuses, which the provider never responds", class)))
+	}
+}
+
+// checkRequired checks that the consumer always sends every field the provider requires
+func (r *CompatResult) checkRequired(base Issue, required, sent, sometimesSent []string, kind IssueKind, label string, caseInsensitive bool) {
+	if caseInsensitive {
+		required, sent, sometimesSent = lowerAll(required), lowerAll(sent), lowerAll(sometimesSent)
+	}
+	sentSet, sometimesSet := toSet(sent), toSet(sometimesSent)
+	for _, name := range required {
+		if sentSet[name] {
+			continue
+		}
+		message := fmt.Sprintf("provider requires %s %s, which the consumer does not send", label, name)
+		if sometimesSet[name] {
+			message = fmt.Sprintf("provider requires %s %s, which the consumer only sends sometimes", label, name)
+		}
+		r.add(withIssue(base, kind, SeverityBreaking, name, message))
+	}
+}
+
+// FormatHuman renders the compatibility check as human-readable text
+func (r *CompatResult) FormatHuman() string {
+	var output strings.Builder
+	if len(r.Issues) == 0 {
+		output.WriteString("Provider satisfies the consumer contract\n")
+		return output.String()
+	}
+
+	currentService := ""
+	for _, issue := range r.Issues {
+		if issue.Service != currentService {
+			currentService = issue.Service
+			output.WriteString(fmt.Sprintf("%s\n", currentService))
+		}
+		marker := "  ⚠ WARNING "
+		if issue.Severity == SeverityBreaking {
+			marker = "  ✖ INCOMPATIBLE "
+		}
+		location := ""
+		if issue.Method != "" {
+			location = issue.Method + " "
+		}
+		if issue.Path != "" {
+			location += issue.Path + ": "
+		}
+		output.WriteString(fmt.Sprintf("%s%s%s\n", marker, location, issue.Message))
+	}
+
+	incompatible := len(r.Incompatibilities())
+	output.WriteString(fmt.Sprintf("\n%d incompatibility(ies), %d warning(s)\n", incompatible, len(r.Issues)-incompatible))
+	return output.String()
+}
+
+// add records an issue
+func (r *CompatResult) add(issue Issue) {
+	r.Issues = append(r.Issues, issue)
+}
+
+// withIssue completes the location of an issue with its kind, severity, value and message
+func withIssue(base Issue, kind IssueKind, severity Severity, value, message string) Issue {
+	base.Kind, base.Severity, base.Value, base.Message = kind, severity, value, message
+	return base
+}
+
+// pathShape replaces the path parameters of a path, so /users/{id} and /users/{userId} match
+func pathShape(path string) string {
+	return pathParameter.ReplaceAllString(strings.TrimSuffix(path, "/"), "{}")
+}
+
+// statusClass returns the class of a status code, e.g. "4xx" for "404"
+func statusClass(code string) string {
+	return code[:1] + "xx"
+}
+
+// hasCodeInClass reports whether any of codes belongs to a status class such as "2xx"
+func hasCodeInClass(codes []int, class string) bool {
+	for _, code := range codes {
+		if statusClass(strconv.Itoa(code)) == class {
+			return true
+		}
+	}
+	return false
+}
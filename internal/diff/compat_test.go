@@ -0,0 +1,145 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/renderer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	provider := newSpec("v2.0.0",
+		models.EndpointSpec{Path: "/users/{id}", Operations: []models.OperationSpec{
+			{
+				Method:    "GET",
+				Responses: models.ResponseSpec{StatusCodes: []int{200, 404, 500}, StatusRanges: []string{"3XX"}},
+				Required:  models.RequiredFieldsSpec{Headers: []string{"Authorization", "X-Tenant"}, Query: []string{"fields"}},
+			},
+		}},
+		models.EndpointSpec{Path: "/admin", Operations: []models.OperationSpec{{Method: "GET"}}},
+	)
+	consumer := newSpec("v1.0.0",
+		models.EndpointSpec{Path: "/users/{userId}", Operations: []models.OperationSpec{
+			{
+				Method:    "get",
+				Responses: models.ResponseSpec{StatusCodes: []int{200, 410}, StatusRanges: []string{"4xx", "2xx", "1xx"}},
+				Required:  models.RequiredFieldsSpec{Headers: []string{"authorization"}},
+				Optional:  models.OptionalFieldsSpec{Query: []string{"fields"}},
+			},
+			{Method: "DELETE"},
+		}},
+		models.EndpointSpec{Path: "/orders", Operations: []models.OperationSpec{{Method: "GET"}}},
+	)
+
+	result := CheckCompatibility([]models.ServiceSpec{provider}, []models.ServiceSpec{consumer})
+
+	type issue struct {
+		Kind     IssueKind
+		Severity Severity
+		Value    string
+	}
+	var issues []issue
+	for _, found := range result.Issues {
+		issues = append(issues, issue{found.Kind, found.Severity, found.Value})
+	}
+	assert.Equal(t, []issue{
+		{UnhandledStatus, SeverityBreaking, "500"},
+		{UnhandledStatus, SeverityBreaking, "3xx"},
+		{UnreachableStatus, SeverityWarning, "410"},
+		{UnreachableStatus, SeverityWarning, "1xx"},
+		{MissingRequiredHeader, SeverityBreaking, "x-tenant"},
+		{MissingRequiredQuery, SeverityBreaking, "fields"},
+		{MissingOperation, SeverityBreaking, ""},
+		{MissingEndpoint, SeverityBreaking, ""},
+	}, issues)
+	assert.False(t, result.Compatible)
+	assert.Equal(t, renderer.ExitValidationFailed, result.ExitCode())
+	assert.Len(t, result.Incompatibilities(), 6)
+	assert.Equal(t, "/users/{userId}", result.Issues[0].Path, "issues are located in the consumer contract")
+	assert.Equal(t, "provider requires query parameter fields, which the consumer only sends sometimes", result.Issues[5].Message)
+}
+
+func TestCheckCompatibility_Compatible(t *testing.T) {
+	provider := newSpec("v1.0.0",
+		models.EndpointSpec{Path: "/users/", Operations: []models.OperationSpec{
+			{Method: "GET", Responses: models.ResponseSpec{StatusCodes: []int{200, 503}}},
+			{Method: "POST"},
+		}},
+	)
+	consumer := newSpec("v1.0.0",
+		models.EndpointSpec{Path: "/users", Operations: []models.OperationSpec{
+			{Method: "GET", Responses: models.ResponseSpec{StatusCodes: []int{200}, StatusRanges: []string{"5xx"}}},
+		}},
+	)
+
+	result := CheckCompatibility([]models.ServiceSpec{provider}, []models.ServiceSpec{consumer})
+	assert.True(t, result.Compatible)
+	assert.Empty(t, result.Issues)
+	assert.Equal(t, renderer.ExitSuccess, result.ExitCode())
+	assert.Equal(t, "Provider satisfies the consumer contract\n", result.FormatHuman())
+}
+
+func TestCheckCompatibility_MultipleServices(t *testing.T) {
+	users := newSpec("v1.0.0", models.EndpointSpec{Path: "/users", Operations: []models.OperationSpec{{Method: "GET"}}})
+	orders := newSpec("v1.0.0", models.EndpointSpec{Path: "/orders", Operations: []models.OperationSpec{{Method: "GET"}}})
+	orders.Metadata = &models.ServiceSpecMetadata{Name: "order-service", Version: "v1.0.0"}
+	billing := newSpec("v1.0.0")
+	billing.Metadata = &models.ServiceSpecMetadata{Name: "billing-service", Version: "v1.0.0"}
+
+	result := CheckCompatibility([]models.ServiceSpec{users, orders}, []models.ServiceSpec{orders, billing})
+	require.Len(t, result.Issues, 1)
+	assert.Equal(t, MissingService, result.Issues[0].Kind)
+	assert.Equal(t, "billing-service", result.Issues[0].Service)
+}
+
+func TestCheckCompatibilityFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, statusCodes string) string {
+		path := filepath.Join(dir, name)
+		content := `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: user-service
+  version: v1.0.0
+spec:
+  endpoints:
+    - path: /users
+      operations:
+        - method: GET
+          responses:
+            statusCodes: ` + statusCodes + `
+`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	providerPath := write("provider.yaml", "[200, 500]")
+	consumerPath := write("consumer.yaml", "[200]")
+
+	result, err := CheckCompatibilityFiles(providerPath, consumerPath)
+	require.NoError(t, err)
+	output := result.FormatHuman()
+	assert.Contains(t, output, "user-service\n")
+	assert.Contains(t, output, "✖ INCOMPATIBLE GET /users: provider may respond 500, which the consumer does not expect")
+	assert.Contains(t, output, "1 incompatibility(ies), 0 warning(s)")
+
+	_, err = CheckCompatibilityFiles(providerPath, filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
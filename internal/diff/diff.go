@@ -0,0 +1,422 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/parser"
+	"github.com/flowspec/flowspec-cli/internal/renderer"
+)
+
+// ChangeKind identifies the type of a contract change
+type ChangeKind string
+
+const (
+	ServiceAdded          ChangeKind = "service_added"
+	ServiceRemoved        ChangeKind = "service_removed"
+	VersionChanged        ChangeKind = "version_changed"
+	EndpointAdded         ChangeKind = "endpoint_added"
+	EndpointRemoved       ChangeKind = "endpoint_removed"
+	OperationAdded        ChangeKind = "operation_added"
+	OperationRemoved      ChangeKind = "operation_removed"
+	StatusCodeAdded       ChangeKind = "status_code_added"
+	StatusCodeRemoved     ChangeKind = "status_code_removed"
+	StatusCodeWidened     ChangeKind = "status_code_widened" // Replaced by a status range covering it
+	StatusRangeAdded      ChangeKind = "status_range_added"
+	StatusRangeRemoved    ChangeKind = "status_range_removed"
+	AggregationChanged    ChangeKind = "aggregation_changed"
+	RequiredHeaderAdded   ChangeKind = "required_header_added"
+	RequiredHeaderRemoved ChangeKind = "required_header_removed"
+	RequiredQueryAdded    ChangeKind = "required_query_added"
+	RequiredQueryRemoved  ChangeKind = "required_query_removed"
+	OptionalHeaderAdded   ChangeKind = "optional_header_added"
+	OptionalHeaderRemoved ChangeKind = "optional_header_removed"
+	OptionalQueryAdded    ChangeKind = "optional_query_added"
+	OptionalQueryRemoved  ChangeKind = "optional_query_removed"
+)
+
+// Change describes a single difference between two contracts
+type Change struct {
+	Kind     ChangeKind `json:"kind"`
+	Breaking bool       `json:"breaking"`
+	Severity Severity   `json:"severity"`
+	Rule     string     `json:"rule,omitempty"` // Policy rule that classified the change
+	Service  string     `json:"service"`
+	Path     string     `json:"path,omitempty"`
+	Method   string     `json:"method,omitempty"`
+	Value    string     `json:"value,omitempty"` // Status code, header or query name affected
+	Message  string     `json:"message"`
+}
+
+// Result contains all changes between two sets of contracts
+type Result struct {
+	Changes    []Change    `json:"changes"`
+	Violations []Violation `json:"violations"`
+}
+
+// BreakingChanges returns the changes classified as breaking
+func (r *Result) BreakingChanges() []Change {
+	var breaking []Change
+	for _, change := range r.Changes {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		}
+	}
+	return breaking
+}
+
+// HasBreakingChanges returns true if any change is breaking
+func (r *Result) HasBreakingChanges() bool {
+	return len(r.BreakingChanges()) > 0
+}
+
+// ExitCode returns the exit code for CI gating: validation failed when there are breaking changes
+func (r *Result) ExitCode() int {
+	if r.HasBreakingChanges() {
+		return renderer.ExitValidationFailed
+	}
+	return renderer.ExitSuccess
+}
+
+// CompareFiles parses two contract files (or directories) and compares them with the default policy
+func CompareFiles(oldPath, newPath string) (*Result, error) {
+	return CompareFilesWithPolicy(oldPath, newPath, DefaultPolicy())
+}
+
+// CompareFilesWithPolicy parses two contract files (or directories) and compares them
+func CompareFilesWithPolicy(oldPath, newPath string, policy *Policy) (*Result, error) {
+	oldSpecs, err := LoadSpecs(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	newSpecs, err := LoadSpecs(newPath)
+	if err != nil {
+		return nil, err
+	}
+	return CompareSpecsWithPolicy(oldSpecs, newSpecs, policy), nil
+}
+
+// LoadSpecs parses the YAML contracts at path, failing on any parse error
+func LoadSpecs(path string) ([]models.ServiceSpec, error) {
+	result, err := parser.NewSpecParser().ParseFromSource(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load contracts from %s: %w", path, err)
+	}
+	if len(result.Errors) > 0 {
+		first := result.Errors[0]
+		return nil, fmt.Errorf("contract %s is invalid: %s:%d: %s", path, first.File, first.Line, first.Message)
+	}
+
+	specs := yamlSpecs(result.Specs)
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no YAML contracts found in %s", path)
+	}
+	return specs, nil
+}
+
+// CompareSpecs compares two sets of contracts with the default policy
+func CompareSpecs(oldSpecs, newSpecs []models.ServiceSpec) *Result {
+	return CompareSpecsWithPolicy(oldSpecs, newSpecs, DefaultPolicy())
+}
+
+// CompareSpecsWithPolicy compares services by name and classifies the changes with policy.
+// When each side holds a single service they are compared directly so that renamed services
+// still produce an operation-level diff. Legacy format specs are ignored.
+func CompareSpecsWithPolicy(oldSpecs, newSpecs []models.ServiceSpec, policy *Policy) *Result {
+	if policy == nil {
+		policy = DefaultPolicy()
+	}
+	result := &Result{Changes: []Change{}}
+	result.compareSpecs(yamlSpecs(oldSpecs), yamlSpecs(newSpecs))
+	policy.Apply(result)
+	return result
+}
+
+// compareSpecs records the changes between two sets of YAML contracts
+func (r *Result) compareSpecs(oldSpecs, newSpecs []models.ServiceSpec) {
+	if len(oldSpecs) == 1 && len(newSpecs) == 1 {
+		r.compareService(oldSpecs[0], newSpecs[0])
+		return
+	}
+
+	oldByName := specsByName(oldSpecs)
+	newByName := specsByName(newSpecs)
+
+	for _, name := range sortedKeys(oldByName, newByName) {
+		oldSpec, inOld := oldByName[name]
+		newSpec, inNew := newByName[name]
+		switch {
+		case !inNew:
+			r.add(Change{Kind: ServiceRemoved, Service: name, Message: "service removed"})
+		case !inOld:
+			r.add(Change{Kind: ServiceAdded, Service: name, Message: "service added"})
+		default:
+			r.compareService(oldSpec, newSpec)
+		}
+	}
+}
+
+// compareService compares the endpoints and operations of two versions of a service
+func (r *Result) compareService(oldSpec, newSpec models.ServiceSpec) {
+	service := newSpec.Metadata.Name
+	if oldSpec.Metadata.Version != newSpec.Metadata.Version {
+		r.add(Change{Kind: VersionChanged, Service: service, Value: newSpec.Metadata.Version,
+			Message: fmt.Sprintf("version changed from %s to %s", oldSpec.Metadata.Version, newSpec.Metadata.Version)})
+	}
+
+	oldEndpoints := endpointsByPath(oldSpec.Spec.Endpoints)
+	newEndpoints := endpointsByPath(newSpec.Spec.Endpoints)
+
+	for _, path := range sortedKeys(oldEndpoints, newEndpoints) {
+		oldEndpoint, inOld := oldEndpoints[path]
+		newEndpoint, inNew := newEndpoints[path]
+		switch {
+		case !inNew:
+			r.add(Change{Kind: EndpointRemoved, Service: service, Path: path, Message: "endpoint removed"})
+		case !inOld:
+			r.add(Change{Kind: EndpointAdded, Service: service, Path: path, Message: "endpoint added"})
+		default:
+			r.compareEndpoint(service, oldEndpoint, newEndpoint)
+		}
+	}
+}
+
+// compareEndpoint compares the operations of an endpoint present in both versions
+func (r *Result) compareEndpoint(service string, oldEndpoint, newEndpoint models.EndpointSpec) {
+	oldOperations := operationsByMethod(oldEndpoint.Operations)
+	newOperations := operationsByMethod(newEndpoint.Operations)
+	path := newEndpoint.Path
+
+	for _, method := range sortedKeys(oldOperations, newOperations) {
+		oldOperation, inOld := oldOperations[method]
+		newOperation, inNew := newOperations[method]
+		switch {
+		case !inNew:
+			r.add(Change{Kind: OperationRemoved, Service: service, Path: path, Method: method, Message: "operation removed"})
+		case !inOld:
+			r.add(Change{Kind: OperationAdded, Service: service, Path: path, Method: method, Message: "operation added"})
+		default:
+			r.compareOperation(service, path, oldOperation, newOperation)
+		}
+	}
+}
+
+// compareOperation compares response expectations and request fields of an operation
+func (r *Result) compareOperation(service, path string, oldOperation, newOperation models.OperationSpec) {
+	method := strings.ToUpper(newOperation.Method)
+	base := Change{Service: service, Path: path, Method: method}
+
+	oldCodes := intStrings(oldOperation.Responses.StatusCodes)
+	newCodes := intStrings(newOperation.Responses.StatusCodes)
+	oldCodes = r.compareWidenedCodes(base, oldCodes, newCodes, newOperation.Responses.StatusRanges)
+	r.compareSets(base, oldCodes, newCodes, StatusCodeAdded, StatusCodeRemoved, "status code")
+	r.compareSets(base, oldOperation.Responses.StatusRanges, newOperation.Responses.StatusRanges,
+		StatusRangeAdded, StatusRangeRemoved, "status range")
+
+	if oldOperation.Responses.Aggregation != newOperation.Responses.Aggregation {
+		change := base
+		change.Kind = AggregationChanged
+		change.Value = newOperation.Responses.Aggregation
+		change.Message = fmt.Sprintf("response aggregation changed from %q to %q",
+			oldOperation.Responses.Aggregation, newOperation.Responses.Aggregation)
+		r.add(change)
+	}
+
+	r.compareSets(base, lowerAll(oldOperation.Required.Headers), lowerAll(newOperation.Required.Headers),
+		RequiredHeaderAdded, RequiredHeaderRemoved, "required header")
+	r.compareSets(base, oldOperation.Required.Query, newOperation.Required.Query,
+		RequiredQueryAdded, RequiredQueryRemoved, "required query parameter")
+	r.compareSets(base, lowerAll(oldOperation.Optional.Headers), lowerAll(newOperation.Optional.Headers),
+		OptionalHeaderAdded, OptionalHeaderRemoved, "optional header")
+	r.compareSets(base, oldOperation.Optional.Query, newOperation.Optional.Query,
+		OptionalQueryAdded, OptionalQueryRemoved, "optional query parameter")
+}
+
+// compareWidenedCodes records the removed status codes that a status range of the new operation
+// still covers, e.g. 200 replaced by 2xx, and returns the old codes left to compare
+func (r *Result) compareWidenedCodes(base Change, oldCodes, newCodes, newRanges []string) []string {
+	newSet := toSet(newCodes)
+	rangeSet := toSet(lowerAll(newRanges))
+
+	var remaining []string
+	for _, code := range oldCodes {
+		class := code[:1] + "xx"
+		if newSet[code] || len(code) != 3 || !rangeSet[class] {
+			remaining = append(remaining, code)
+			continue
+		}
+		change := base
+		change.Kind = StatusCodeWidened
+		change.Value = code
+		change.Message = fmt.Sprintf("status code %s widened to %s", code, class)
+		r.add(change)
+	}
+	return remaining
+}
+
+// compareSets records added and removed values between two string sets
+func (r *Result) compareSets(base Change, oldValues, newValues []string, added, removed ChangeKind, label string) {
+	oldSet := toSet(oldValues)
+	newSet := toSet(newValues)
+
+	for _, value := range sortedKeys(oldSet, newSet) {
+		change := base
+		change.Value = value
+		switch {
+		case oldSet[value] && !newSet[value]:
+			change.Kind = removed
+			change.Message = fmt.Sprintf("%s %s removed", label, value)
+		case newSet[value] && !oldSet[value]:
+			change.Kind = added
+			change.Message = fmt.Sprintf("%s %s added", label, value)
+		default:
+			continue
+		}
+		r.add(change)
+	}
+}
+
+// add records a change; it is classified later by a policy
+func (r *Result) add(change Change) {
+	r.Changes = append(r.Changes, change)
+}
+
+// FormatHuman renders the diff as human-readable text
+func (r *Result) FormatHuman() string {
+	var output strings.Builder
+	if len(r.Changes) == 0 {
+		output.WriteString("No contract changes\n")
+		return output.String()
+	}
+
+	currentService := ""
+	for _, change := range r.Changes {
+		if change.Service != currentService {
+			currentService = change.Service
+			output.WriteString(fmt.Sprintf("%s\n", currentService))
+		}
+
+		marker := "  ~ "
+		switch {
+		case change.Breaking:
+			marker = "  ✖ BREAKING "
+		case change.Severity == SeverityWarning:
+			marker = "  ⚠ WARNING "
+		case strings.HasSuffix(string(change.Kind), "_added"):
+			marker = "  + "
+		case strings.HasSuffix(string(change.Kind), "_removed"):
+			marker = "  - "
+		}
+
+		location := ""
+		if change.Method != "" {
+			location = change.Method + " "
+		}
+		if change.Path != "" {
+			location += change.Path + ": "
+		}
+		output.WriteString(fmt.Sprintf("%s%s%s\n", marker, location, change.Message))
+	}
+
+	breaking := len(r.BreakingChanges())
+	output.WriteString(fmt.Sprintf("\n%d change(s), %d breaking\n", len(r.Changes), breaking))
+	return output.String()
+}
+
+// yamlSpecs returns the YAML format specs, skipping legacy ones
+func yamlSpecs(specs []models.ServiceSpec) []models.ServiceSpec {
+	filtered := make([]models.ServiceSpec, 0, len(specs))
+	for _, spec := range specs {
+		if spec.IsYAMLFormat() {
+			filtered = append(filtered, spec)
+		}
+	}
+	return filtered
+}
+
+// specsByName indexes specs by service name
+func specsByName(specs []models.ServiceSpec) map[string]models.ServiceSpec {
+	byName := make(map[string]models.ServiceSpec, len(specs))
+	for _, spec := range specs {
+		byName[spec.Metadata.Name] = spec
+	}
+	return byName
+}
+
+// endpointsByPath indexes endpoints by path
+func endpointsByPath(endpoints []models.EndpointSpec) map[string]models.EndpointSpec {
+	byPath := make(map[string]models.EndpointSpec, len(endpoints))
+	for _, endpoint := range endpoints {
+		byPath[endpoint.Path] = endpoint
+	}
+	return byPath
+}
+
+// operationsByMethod indexes operations by upper-case method
+func operationsByMethod(operations []models.OperationSpec) map[string]models.OperationSpec {
+	byMethod := make(map[string]models.OperationSpec, len(operations))
+	for _, operation := range operations {
+		byMethod[strings.ToUpper(operation.Method)] = operation
+	}
+	return byMethod
+}
+
+// sortedKeys returns the union of the keys of two maps in sorted order
+func sortedKeys[V any](first, second map[string]V) []string {
+	seen := make(map[string]bool, len(first)+len(second))
+	keys := make([]string, 0, len(first)+len(second))
+	for _, m := range []map[string]V{first, second} {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// toSet converts values into a set
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+// intStrings converts status codes into strings
+func intStrings(values []int) []string {
+	converted := make([]string, len(values))
+	for i, value := range values {
+		converted[i] = strconv.Itoa(value)
+	}
+	return converted
+}
+
+// lowerAll lower-cases header names, which are case-insensitive
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, value := range values {
+		lowered[i] = strings.ToLower(value)
+	}
+	return lowered
+}
@@ -0,0 +1,295 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/renderer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSpec(version string, endpoints ...models.EndpointSpec) models.ServiceSpec {
+	return models.ServiceSpec{
+		APIVersion: "flowspec/v1alpha1",
+		Kind:       "ServiceSpec",
+		Metadata:   &models.ServiceSpecMetadata{Name: "user-service", Version: version},
+		Spec:       &models.ServiceSpecDefinition{Endpoints: endpoints},
+	}
+}
+
+func TestCompareSpecs(t *testing.T) {
+	oldSpec := newSpec("v1.0.0",
+		models.EndpointSpec{Path: "/users", Operations: []models.OperationSpec{
+			{
+				Method:    "GET",
+				Responses: models.ResponseSpec{StatusCodes: []int{200, 404}},
+				Required:  models.RequiredFieldsSpec{Headers: []string{"Authorization"}},
+			},
+			{Method: "DELETE", Responses: models.ResponseSpec{StatusCodes: []int{204}}},
+		}},
+		models.EndpointSpec{Path: "/legacy", Operations: []models.OperationSpec{
+			{Method: "GET", Responses: models.ResponseSpec{StatusCodes: []int{200}}},
+		}},
+	)
+	newSpec := newSpec("v2.0.0",
+		models.EndpointSpec{Path: "/users", Operations: []models.OperationSpec{
+			{
+				Method:    "get",
+				Responses: models.ResponseSpec{StatusCodes: []int{200, 500}},
+				Required:  models.RequiredFieldsSpec{Headers: []string{"authorization", "X-Tenant"}},
+				Optional:  models.OptionalFieldsSpec{Query: []string{"page"}},
+			},
+		}},
+		models.EndpointSpec{Path: "/orders", Operations: []models.OperationSpec{
+			{Method: "GET", Responses: models.ResponseSpec{StatusCodes: []int{200}}},
+		}},
+	)
+
+	result := CompareSpecs([]models.ServiceSpec{oldSpec}, []models.ServiceSpec{newSpec})
+
+	kinds := make([]ChangeKind, len(result.Changes))
+	for i, change := range result.Changes {
+		kinds[i] = change.Kind
+	}
+	assert.Equal(t, []ChangeKind{
+		VersionChanged,
+		EndpointRemoved,
+		EndpointAdded,
+		OperationRemoved,
+		StatusCodeRemoved,
+		StatusCodeAdded,
+		RequiredHeaderAdded,
+		OptionalQueryAdded,
+	}, kinds)
+
+	breaking := result.BreakingChanges()
+	require.Len(t, breaking, 4)
+	assert.Equal(t, "/legacy", breaking[0].Path)
+	assert.Equal(t, "DELETE", breaking[1].Method)
+	assert.Equal(t, "404", breaking[2].Value)
+	assert.Equal(t, "x-tenant", breaking[3].Value)
+
+	assert.True(t, result.HasBreakingChanges())
+	assert.Equal(t, renderer.ExitValidationFailed, result.ExitCode())
+}
+
+func TestCompareSpecs_NonBreaking(t *testing.T) {
+	testCases := []struct {
+		name         string
+		oldOperation models.OperationSpec
+		newOperation models.OperationSpec
+		expectedKind ChangeKind
+	}{
+		{
+			name:         "required header removed",
+			oldOperation: models.OperationSpec{Method: "GET", Required: models.RequiredFieldsSpec{Headers: []string{"x-api-key"}}},
+			newOperation: models.OperationSpec{Method: "GET"},
+			expectedKind: RequiredHeaderRemoved,
+		},
+		{
+			name:         "status range added",
+			oldOperation: models.OperationSpec{Method: "GET", Responses: models.ResponseSpec{StatusRanges: []string{"2xx"}}},
+			newOperation: models.OperationSpec{Method: "GET", Responses: models.ResponseSpec{StatusRanges: []string{"2xx", "4xx"}}},
+			expectedKind: StatusRangeAdded,
+		},
+		{
+			name:         "aggregation changed",
+			oldOperation: models.OperationSpec{Method: "GET", Responses: models.ResponseSpec{Aggregation: "auto"}},
+			newOperation: models.OperationSpec{Method: "GET", Responses: models.ResponseSpec{Aggregation: "exact"}},
+			expectedKind: AggregationChanged,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			oldSpec := newSpec("v1", models.EndpointSpec{Path: "/users", Operations: []models.OperationSpec{tc.oldOperation}})
+			newSpec := newSpec("v1", models.EndpointSpec{Path: "/users", Operations: []models.OperationSpec{tc.newOperation}})
+
+			result := CompareSpecs([]models.ServiceSpec{oldSpec}, []models.ServiceSpec{newSpec})
+			require.Len(t, result.Changes, 1)
+			assert.Equal(t, tc.expectedKind, result.Changes[0].Kind)
+			assert.False(t, result.HasBreakingChanges())
+			assert.Equal(t, renderer.ExitSuccess, result.ExitCode())
+		})
+	}
+}
+
+func TestCompareSpecs_StatusWidenedToRange(t *testing.T) {
+	compare := func(oldResponses, newResponses models.ResponseSpec) *Result {
+		oldSpec := newSpec("v1", models.EndpointSpec{Path: "/users", Operations: []models.OperationSpec{{Method: "GET", Responses: oldResponses}}})
+		newSpec := newSpec("v1", models.EndpointSpec{Path: "/users", Operations: []models.OperationSpec{{Method: "GET", Responses: newResponses}}})
+		return CompareSpecs([]models.ServiceSpec{oldSpec}, []models.ServiceSpec{newSpec})
+	}
+
+	widened := compare(models.ResponseSpec{StatusCodes: []int{200}}, models.ResponseSpec{StatusRanges: []string{"2XX"}})
+	require.Len(t, widened.Changes, 2)
+	assert.Equal(t, StatusCodeWidened, widened.Changes[0].Kind)
+	assert.Equal(t, "200", widened.Changes[0].Value)
+	assert.Equal(t, "status code 200 widened to 2xx", widened.Changes[0].Message)
+	assert.Equal(t, StatusRangeAdded, widened.Changes[1].Kind)
+	assert.False(t, widened.HasBreakingChanges())
+	assert.Equal(t, renderer.ExitSuccess, widened.ExitCode())
+
+	// A range of another class does not cover the removed code
+	moved := compare(models.ResponseSpec{StatusCodes: []int{200, 404}}, models.ResponseSpec{StatusRanges: []string{"2xx"}})
+	breaking := moved.BreakingChanges()
+	require.Len(t, breaking, 1)
+	assert.Equal(t, StatusCodeRemoved, breaking[0].Kind)
+	assert.Equal(t, "404", breaking[0].Value)
+
+	// Narrowing a range to one of its codes still removes the range
+	narrowed := compare(models.ResponseSpec{StatusRanges: []string{"2xx"}}, models.ResponseSpec{StatusCodes: []int{200}})
+	assert.True(t, narrowed.HasBreakingChanges())
+}
+
+func TestCompareSpecs_MultipleServices(t *testing.T) {
+	endpoint := models.EndpointSpec{Path: "/ping", Operations: []models.OperationSpec{{Method: "GET"}}}
+	users := newSpec("v1", endpoint)
+	orders := newSpec("v1", endpoint)
+	orders.Metadata = &models.ServiceSpecMetadata{Name: "order-service", Version: "v1"}
+	billing := newSpec("v1", endpoint)
+	billing.Metadata = &models.ServiceSpecMetadata{Name: "billing-service", Version: "v1"}
+
+	result := CompareSpecs([]models.ServiceSpec{users, orders}, []models.ServiceSpec{users, billing})
+	require.Len(t, result.Changes, 2)
+	assert.Equal(t, ServiceAdded, result.Changes[0].Kind)
+	assert.Equal(t, "billing-service", result.Changes[0].Service)
+	assert.Equal(t, ServiceRemoved, result.Changes[1].Kind)
+	assert.True(t, result.Changes[1].Breaking)
+}
+
+func TestCompareFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, statusCodes string) string {
+		path := filepath.Join(dir, name)
+		content := `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: user-service
+  version: v1.0.0
+spec:
+  endpoints:
+    - path: /users
+      operations:
+        - method: GET
+          responses:
+            statusCodes: ` + statusCodes + `
+`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	oldPath := write("old.yaml", "[200, 404]")
+	newPath := write("new.yaml", "[200]")
+
+	result, err := CompareFiles(oldPath, newPath)
+	require.NoError(t, err)
+	require.Len(t, result.Changes, 1)
+	assert.Equal(t, StatusCodeRemoved, result.Changes[0].Kind)
+
+	output := result.FormatHuman()
+	assert.Contains(t, output, "user-service\n")
+	assert.Contains(t, output, "✖ BREAKING GET /users: status code 404 removed")
+	assert.Contains(t, output, "1 change(s), 1 breaking")
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"kind":"status_code_removed"`)
+
+	same, err := CompareFiles(oldPath, oldPath)
+	require.NoError(t, err)
+	assert.Empty(t, same.Changes)
+	assert.Equal(t, "No contract changes\n", same.FormatHuman())
+
+	_, err = CompareFiles(filepath.Join(dir, "missing.yaml"), newPath)
+	assert.Error(t, err)
+}
+
+func TestPolicy(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(policyPath, []byte(`rules:
+  - name: removed-success-status
+    change: status_code_removed
+    value: 2xx
+    severity: breaking
+  - name: removed-error-status
+    change: status_code_removed
+    severity: warning
+  - change: optional_query_added
+    severity: allowed
+`), 0644))
+
+	policy, err := LoadPolicy(policyPath)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		change           Change
+		expectedSeverity Severity
+		expectedRule     string
+	}{
+		{Change{Kind: StatusCodeRemoved, Value: "201"}, SeverityBreaking, "removed-success-status"},
+		{Change{Kind: StatusCodeRemoved, Value: "404"}, SeverityWarning, "removed-error-status"},
+		{Change{Kind: OptionalQueryAdded, Value: "page"}, SeverityAllowed, "rule-3"},
+		{Change{Kind: RequiredHeaderAdded, Value: "x-tenant"}, SeverityBreaking, "added-required-header"},
+		{Change{Kind: EndpointAdded, Path: "/orders"}, SeverityAllowed, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.change.Kind)+" "+tc.change.Value, func(t *testing.T) {
+			severity, rule := policy.Classify(tc.change)
+			assert.Equal(t, tc.expectedSeverity, severity)
+			assert.Equal(t, tc.expectedRule, rule)
+		})
+	}
+
+	oldSpec := newSpec("v1", models.EndpointSpec{Path: "/users", Operations: []models.OperationSpec{
+		{Method: "GET", Responses: models.ResponseSpec{StatusCodes: []int{200, 404}}},
+	}})
+	newSpec := newSpec("v1", models.EndpointSpec{Path: "/users", Operations: []models.OperationSpec{
+		{Method: "GET", Responses: models.ResponseSpec{StatusCodes: []int{200}}},
+	}})
+	result := CompareSpecsWithPolicy([]models.ServiceSpec{oldSpec}, []models.ServiceSpec{newSpec}, policy)
+	require.Len(t, result.Violations, 1)
+	assert.Equal(t, "removed-error-status", result.Violations[0].Rule)
+	assert.Equal(t, SeverityWarning, result.Violations[0].Severity)
+	assert.False(t, result.HasBreakingChanges())
+	assert.Contains(t, result.FormatHuman(), "⚠ WARNING GET /users: status code 404 removed")
+}
+
+func TestLoadPolicy_Invalid(t *testing.T) {
+	testCases := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{name: "unknown change", content: "rules:\n  - change: header_renamed\n    severity: breaking\n", expectedError: `unknown change "header_renamed"`},
+		{name: "unknown severity", content: "rules:\n  - change: endpoint_added\n    severity: fatal\n", expectedError: `unknown severity "fatal"`},
+		{name: "unknown field", content: "rule: []\n", expectedError: "field rule not found"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tc.content), 0644))
+			_, err := LoadPolicy(path)
+			assert.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
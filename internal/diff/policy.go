@@ -0,0 +1,175 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity is the classification a policy assigns to a change
+type Severity string
+
+const (
+	SeverityBreaking Severity = "breaking"
+	SeverityWarning  Severity = "warning"
+	SeverityAllowed  Severity = "allowed"
+)
+
+// knownKinds lists every change kind a rule may refer to
+var knownKinds = map[ChangeKind]bool{
+	ServiceAdded:          true,
+	ServiceRemoved:        true,
+	VersionChanged:        true,
+	EndpointAdded:         true,
+	EndpointRemoved:       true,
+	OperationAdded:        true,
+	OperationRemoved:      true,
+	StatusCodeAdded:       true,
+	StatusCodeRemoved:     true,
+	StatusCodeWidened:     true,
+	StatusRangeAdded:      true,
+	StatusRangeRemoved:    true,
+	AggregationChanged:    true,
+	RequiredHeaderAdded:   true,
+	RequiredHeaderRemoved: true,
+	RequiredQueryAdded:    true,
+	RequiredQueryRemoved:  true,
+	OptionalHeaderAdded:   true,
+	OptionalHeaderRemoved: true,
+	OptionalQueryAdded:    true,
+	OptionalQueryRemoved:  true,
+}
+
+// Rule classifies the changes of one kind, optionally limited to a value
+type Rule struct {
+	Name     string     `yaml:"name" json:"name"`
+	Change   ChangeKind `yaml:"change" json:"change"`
+	Value    string     `yaml:"value,omitempty" json:"value,omitempty"` // Exact value, or a status class such as "2xx"
+	Severity Severity   `yaml:"severity" json:"severity"`
+}
+
+// Matches returns true if the rule applies to the change
+func (r Rule) Matches(change Change) bool {
+	if r.Change != change.Kind {
+		return false
+	}
+	if r.Value == "" {
+		return true
+	}
+	if strings.EqualFold(r.Value, change.Value) {
+		return true
+	}
+	// A status class matches every status code in that class
+	class := strings.ToLower(r.Value)
+	return len(class) == 3 && strings.HasSuffix(class, "xx") && len(change.Value) == 3 &&
+		(change.Kind == StatusCodeAdded || change.Kind == StatusCodeRemoved || change.Kind == StatusCodeWidened) &&
+		change.Value[0] == class[0]
+}
+
+// Violation is a change that a policy classifies as breaking or as a warning
+type Violation struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Change   Change   `json:"change"`
+}
+
+// Policy classifies contract changes; the first matching rule wins and unmatched changes are allowed
+type Policy struct {
+	Rules []Rule `yaml:"rules" json:"rules"`
+}
+
+// DefaultPolicy returns the built-in breaking-change rules
+func DefaultPolicy() *Policy {
+	return &Policy{Rules: defaultRules()}
+}
+
+// defaultRules flags changes that can break existing clients of a contract
+func defaultRules() []Rule {
+	return []Rule{
+		{Name: "removed-service", Change: ServiceRemoved, Severity: SeverityBreaking},
+		{Name: "removed-endpoint", Change: EndpointRemoved, Severity: SeverityBreaking},
+		{Name: "removed-operation", Change: OperationRemoved, Severity: SeverityBreaking},
+		{Name: "removed-status-code", Change: StatusCodeRemoved, Severity: SeverityBreaking},
+		{Name: "removed-status-range", Change: StatusRangeRemoved, Severity: SeverityBreaking},
+		{Name: "added-required-header", Change: RequiredHeaderAdded, Severity: SeverityBreaking},
+		{Name: "added-required-query", Change: RequiredQueryAdded, Severity: SeverityBreaking},
+	}
+}
+
+// LoadPolicy reads a policy file. Its rules are evaluated before the built-in rules,
+// so a file only needs to list the rules it adds or overrides.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy %s: %w", path, err)
+	}
+
+	var policy Policy
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy %s: %w", path, err)
+	}
+
+	for i, rule := range policy.Rules {
+		if !knownKinds[rule.Change] {
+			return nil, fmt.Errorf("policy %s: rules[%d] has unknown change %q", path, i, rule.Change)
+		}
+		switch rule.Severity {
+		case SeverityBreaking, SeverityWarning, SeverityAllowed:
+		default:
+			return nil, fmt.Errorf("policy %s: rules[%d] has unknown severity %q, must be one of: breaking, warning, allowed", path, i, rule.Severity)
+		}
+		if rule.Name == "" {
+			policy.Rules[i].Name = fmt.Sprintf("rule-%d", i+1)
+		}
+	}
+
+	policy.Rules = append(policy.Rules, defaultRules()...)
+	return &policy, nil
+}
+
+// Classify returns the severity of a change and the name of the rule that decided it
+func (p *Policy) Classify(change Change) (Severity, string) {
+	for _, rule := range p.Rules {
+		if rule.Matches(change) {
+			return rule.Severity, rule.Name
+		}
+	}
+	return SeverityAllowed, ""
+}
+
+// Apply classifies every change of a diff result and records the violations
+func (p *Policy) Apply(result *Result) {
+	result.Violations = []Violation{}
+	for i := range result.Changes {
+		change := &result.Changes[i]
+		change.Severity, change.Rule = p.Classify(*change)
+		change.Breaking = change.Severity == SeverityBreaking
+
+		if change.Severity != SeverityAllowed {
+			result.Violations = append(result.Violations, Violation{
+				Rule:     change.Rule,
+				Severity: change.Severity,
+				Change:   *change,
+			})
+		}
+	}
+}
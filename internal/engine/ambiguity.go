@@ -0,0 +1,217 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// Policies for a span matching several operations of a spec, such as GET /users/{id} and
+// GET /users/profile for a request to /users/profile
+const (
+	AmbiguityMostSpecificWins = "most-specific-wins" // Only the operation with the most literal path evaluates the span
+	AmbiguityFail             = "fail"               // Every matched operation evaluates the span and fails
+	AmbiguityAll              = "all"                // Every matched operation evaluates the span
+)
+
+// ValidateAmbiguityPolicy checks that an ambiguity policy is empty, most-specific-wins, fail or all
+func ValidateAmbiguityPolicy(policy string) error {
+	switch policy {
+	case "", AmbiguityMostSpecificWins, AmbiguityFail, AmbiguityAll:
+		return nil
+	}
+	return fmt.Errorf("unsupported ambiguity policy %q, must be %s, %s or %s",
+		policy, AmbiguityMostSpecificWins, AmbiguityFail, AmbiguityAll)
+}
+
+// operationMatch is an operation of a spec with the spans it evaluates
+type operationMatch struct {
+	endpoint   models.EndpointSpec
+	operation  models.OperationSpec
+	key        string
+	spans      []*models.Span
+	duplicates int                     // Spans sharing the ID of one of spans that the ingestor dropped
+	ambiguous  []models.MatchAmbiguity // Ambiguities failing the operation under the fail policy
+}
+
+// matchOperations finds the spans of every operation of a spec and resolves the spans that
+// matched several operations with the ambiguity policy, recording them in result
+func (engine *DefaultAlignmentEngine) matchOperations(
+	spec models.ServiceSpec,
+	traceData *models.TraceData,
+	result *models.AlignmentResult,
+) []*operationMatch {
+	var matches []*operationMatch
+	matchedBy := make(map[string][]*operationMatch)
+	var spanOrder []*models.Span
+	for _, endpoint := range spec.Spec.Endpoints {
+		for _, operation := range endpoint.Operations {
+			match := &operationMatch{
+				endpoint:  endpoint,
+				operation: operation,
+				key:       fmt.Sprintf("%s %s", operation.Method, endpoint.Path),
+			}
+			match.spans = engine.findMatchingSpansForOperation(endpoint, operation, traceData)
+			match.duplicates = droppedDuplicates(match.spans, traceData)
+			for _, span := range match.spans {
+				if len(matchedBy[span.SpanID]) == 0 {
+					spanOrder = append(spanOrder, span)
+				}
+				matchedBy[span.SpanID] = append(matchedBy[span.SpanID], match)
+			}
+			matches = append(matches, match)
+		}
+	}
+
+	policy := engine.ambiguityPolicy()
+	for _, span := range spanOrder {
+		candidates := matchedBy[span.SpanID]
+		if len(candidates) < 2 {
+			continue
+		}
+		ambiguity := models.MatchAmbiguity{SpanID: span.SpanID, SpanName: span.Name, Policy: policy}
+		for _, candidate := range candidates {
+			ambiguity.Operations = append(ambiguity.Operations, candidate.key)
+		}
+
+		switch policy {
+		case AmbiguityMostSpecificWins:
+			if winner := mostSpecificOperation(candidates); winner != nil {
+				ambiguity.ResolvedTo = winner.key
+				for _, candidate := range candidates {
+					if candidate != winner {
+						candidate.spans = removeSpan(candidate.spans, span.SpanID)
+					}
+				}
+			}
+		case AmbiguityFail:
+			for _, candidate := range candidates {
+				candidate.ambiguous = append(candidate.ambiguous, ambiguity)
+			}
+		}
+		result.Ambiguities = append(result.Ambiguities, ambiguity)
+	}
+	return matches
+}
+
+// ambiguityPolicy returns the configured ambiguity policy, most-specific-wins when unset
+func (engine *DefaultAlignmentEngine) ambiguityPolicy() string {
+	if engine.config.AmbiguityPolicy == "" {
+		return AmbiguityMostSpecificWins
+	}
+	return engine.config.AmbiguityPolicy
+}
+
+// mostSpecificOperation returns the operation whose path is more specific than the paths of
+// all the others, or nil when no single path is
+func mostSpecificOperation(candidates []*operationMatch) *operationMatch {
+	best := candidates[0]
+	tied := false
+	for _, candidate := range candidates[1:] {
+		switch comparePathSpecificity(candidate.endpoint.Path, best.endpoint.Path) {
+		case 1:
+			best, tied = candidate, false
+		case 0:
+			tied = true
+		}
+	}
+	if tied {
+		return nil
+	}
+	return best
+}
+
+// comparePathSpecificity returns 1 when path pattern a is more specific than b, -1 when it is
+// less specific and 0 when neither is. Of patterns with as many segments, the first to have a
+// literal segment where the other has a parameter wins, so /users/profile beats /users/{id};
+// otherwise the pattern with more literal segments wins.
+func comparePathSpecificity(a, b string) int {
+	segmentsA := strings.Split(strings.Trim(a, "/"), "/")
+	segmentsB := strings.Split(strings.Trim(b, "/"), "/")
+	if len(segmentsA) == len(segmentsB) {
+		for i := range segmentsA {
+			parameterA, parameterB := isPathParameter(segmentsA[i]), isPathParameter(segmentsB[i])
+			if parameterA != parameterB {
+				if parameterB {
+					return 1
+				}
+				return -1
+			}
+		}
+		return 0
+	}
+
+	literalsA, literalsB := countLiteralSegments(segmentsA), countLiteralSegments(segmentsB)
+	switch {
+	case literalsA > literalsB:
+		return 1
+	case literalsA < literalsB:
+		return -1
+	}
+	return 0
+}
+
+// isPathParameter reports whether a path pattern segment is a parameter such as {id}
+func isPathParameter(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// countLiteralSegments counts the path pattern segments that are not parameters
+func countLiteralSegments(segments []string) int {
+	literals := 0
+	for _, segment := range segments {
+		if !isPathParameter(segment) {
+			literals++
+		}
+	}
+	return literals
+}
+
+// removeSpan returns spans without the span of an ID, leaving spans unchanged
+func removeSpan(spans []*models.Span, spanID string) []*models.Span {
+	kept := make([]*models.Span, 0, len(spans))
+	for _, span := range spans {
+		if span.SpanID != spanID {
+			kept = append(kept, span)
+		}
+	}
+	return kept
+}
+
+// addAmbiguityFailures fails an operation for each span it shares with other operations,
+// under the fail ambiguity policy
+func addAmbiguityFailures(match *operationMatch, result *models.AlignmentResult, operationResult *models.OperationResult) {
+	for _, ambiguity := range match.ambiguous {
+		detail := models.NewValidationDetail(
+			"ambiguity", "operation_match",
+			"one matching operation",
+			strings.Join(ambiguity.Operations, ", "),
+			fmt.Sprintf("Span %s (%s) matched %d operations: %s",
+				ambiguity.SpanID, ambiguity.SpanName, len(ambiguity.Operations), strings.Join(ambiguity.Operations, ", ")))
+		detail.Operation = match.key
+		detail.Suggestions = []string{
+			"Make the path patterns of these operations disjoint",
+			"Or set the ambiguity policy to most-specific-wins to let the most literal path evaluate the span",
+		}
+
+		operationResult.Details = append(operationResult.Details, *detail)
+		operationResult.AssertionsTotal++
+		operationResult.AssertionsFailed++
+		result.AddValidationDetail(*detail)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAmbiguousTestData returns a spec whose GET /users/{id} and GET /users/profile both match
+// a request to /users/profile, and a trace with such a request and one to /users/42
+func newAmbiguousTestData(paths ...string) (models.ServiceSpec, *models.TraceData) {
+	spec := models.ServiceSpec{
+		APIVersion: "flowspec/v1alpha1",
+		Kind:       "ServiceSpec",
+		Metadata:   &models.ServiceSpecMetadata{Name: "user-service", Version: "v1"},
+		Spec:       &models.ServiceSpecDefinition{},
+	}
+	for _, path := range paths {
+		spec.Spec.Endpoints = append(spec.Spec.Endpoints, models.EndpointSpec{
+			Path: path,
+			Operations: []models.OperationSpec{
+				{Method: "GET", Responses: models.ResponseSpec{StatusRanges: []string{"2xx"}}},
+			},
+		})
+	}
+
+	traceData := &models.TraceData{
+		TraceID: "trace1",
+		Spans: map[string]*models.Span{
+			"profile": {
+				SpanID: "profile", TraceID: "trace1", Name: "GET /users/profile",
+				Attributes: map[string]interface{}{"http.method": "GET", "http.target": "/users/profile", "http.status_code": 200},
+			},
+			"user": {
+				SpanID: "user", TraceID: "trace1", Name: "GET /users/{id}",
+				Attributes: map[string]interface{}{"http.method": "GET", "http.target": "/users/42", "http.status_code": 200},
+			},
+		},
+	}
+	return spec, traceData
+}
+
+// alignWithAmbiguityPolicy aligns the spec with the trace under an ambiguity policy
+func alignWithAmbiguityPolicy(t *testing.T, policy string, spec models.ServiceSpec, traceData *models.TraceData) *models.AlignmentReport {
+	t.Helper()
+	config := DefaultEngineConfig()
+	config.AmbiguityPolicy = policy
+	report, err := NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	return report
+}
+
+func TestAlignmentEngine_AmbiguityMostSpecificWins(t *testing.T) {
+	spec, traceData := newAmbiguousTestData("/users/{id}", "/users/profile")
+
+	for _, policy := range []string{"", AmbiguityMostSpecificWins} {
+		report := alignWithAmbiguityPolicy(t, policy, spec, traceData)
+		result := report.Results[0]
+		assert.Equal(t, []models.MatchAmbiguity{{
+			SpanID:     "profile",
+			SpanName:   "GET /users/profile",
+			Operations: []string{"GET /users/{id}", "GET /users/profile"},
+			ResolvedTo: "GET /users/profile",
+			Policy:     AmbiguityMostSpecificWins,
+		}}, result.Ambiguities)
+		assert.Equal(t, 1, report.Summary.AmbiguousSpans)
+
+		byID := result.OperationResults["GET /users/{id}"]
+		assert.Equal(t, []string{"user"}, byID.MatchedSpans, "the literal path takes the span")
+		assert.Equal(t, 1, byID.SampleCount)
+		assert.Equal(t, []string{"profile"}, result.OperationResults["GET /users/profile"].MatchedSpans)
+		assert.Equal(t, models.StatusSuccess, result.Status)
+		assert.Equal(t, 2, result.AssertionsTotal, "one status check per span")
+	}
+}
+
+func TestAlignmentEngine_AmbiguityAll(t *testing.T) {
+	spec, traceData := newAmbiguousTestData("/users/{id}", "/users/profile")
+
+	report := alignWithAmbiguityPolicy(t, AmbiguityAll, spec, traceData)
+	result := report.Results[0]
+	require.Len(t, result.Ambiguities, 1)
+	assert.Empty(t, result.Ambiguities[0].ResolvedTo)
+	assert.Equal(t, AmbiguityAll, result.Ambiguities[0].Policy)
+	assert.Equal(t, 2, result.OperationResults["GET /users/{id}"].SampleCount)
+	assert.Equal(t, 1, result.OperationResults["GET /users/profile"].SampleCount)
+	assert.Equal(t, models.StatusSuccess, result.Status)
+	assert.Equal(t, 3, result.AssertionsTotal, "the ambiguous span is evaluated by both operations")
+}
+
+func TestAlignmentEngine_AmbiguityFail(t *testing.T) {
+	spec, traceData := newAmbiguousTestData("/users/{id}", "/users/profile")
+
+	report := alignWithAmbiguityPolicy(t, AmbiguityFail, spec, traceData)
+	result := report.Results[0]
+	require.Len(t, result.Ambiguities, 1)
+	assert.Equal(t, models.StatusFailed, result.Status)
+	for _, key := range []string{"GET /users/{id}", "GET /users/profile"} {
+		operationResult := result.OperationResults[key]
+		assert.Equal(t, models.StatusFailed, operationResult.Status, key)
+		assert.Equal(t, 1, operationResult.AssertionsFailed, key)
+
+		var ambiguity *models.ValidationDetail
+		for i := range operationResult.Details {
+			if operationResult.Details[i].Type == "ambiguity" {
+				ambiguity = &operationResult.Details[i]
+			}
+		}
+		require.NotNil(t, ambiguity, key)
+		assert.Equal(t, key, ambiguity.Operation)
+		assert.Equal(t, "GET /users/{id}, GET /users/profile", ambiguity.Actual)
+		assert.Contains(t, ambiguity.Message, "Span profile (GET /users/profile) matched 2 operations")
+	}
+}
+
+func TestAlignmentEngine_AmbiguityWithoutMostSpecific(t *testing.T) {
+	spec, traceData := newAmbiguousTestData("/users/{id}", "/users/{name}")
+
+	report := alignWithAmbiguityPolicy(t, AmbiguityMostSpecificWins, spec, traceData)
+	result := report.Results[0]
+	require.Len(t, result.Ambiguities, 2, "both spans match both operations")
+	for _, ambiguity := range result.Ambiguities {
+		assert.Empty(t, ambiguity.ResolvedTo, "neither path is more specific")
+	}
+	assert.Equal(t, 2, result.OperationResults["GET /users/{id}"].SampleCount)
+	assert.Equal(t, 2, result.OperationResults["GET /users/{name}"].SampleCount)
+}
+
+func TestAlignmentEngine_NoAmbiguity(t *testing.T) {
+	spec, traceData := newAmbiguousTestData("/users/{id}")
+
+	report := alignWithAmbiguityPolicy(t, AmbiguityFail, spec, traceData)
+	assert.Empty(t, report.Results[0].Ambiguities)
+	assert.Zero(t, report.Summary.AmbiguousSpans)
+	assert.Equal(t, models.StatusSuccess, report.Results[0].Status)
+}
+
+func TestComparePathSpecificity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"/users/profile", "/users/{id}", 1},
+		{"/users/{id}", "/users/profile", -1},
+		{"/users/{id}", "/users/{name}", 0},
+		{"/users/me/orders", "/users/{id}/orders", 1},
+		{"/{tenant}/users/me", "/acme/users/{id}", -1},
+		{"/users/profile", "/users/profile", 0},
+		{"/users/{id}/orders", "/users", 1},
+		{"/users", "/{resource}/{id}", 1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, comparePathSpecificity(tt.a, tt.b), "%s vs %s", tt.a, tt.b)
+	}
+}
+
+func TestValidateAmbiguityPolicy(t *testing.T) {
+	for _, policy := range []string{"", AmbiguityMostSpecificWins, AmbiguityFail, AmbiguityAll} {
+		assert.NoError(t, ValidateAmbiguityPolicy(policy), policy)
+	}
+	assert.EqualError(t, ValidateAmbiguityPolicy("first"),
+		`unsupported ambiguity policy "first", must be most-specific-wins, fail or all`)
+
+	config := DefaultEngineConfig()
+	config.AmbiguityPolicy = "first"
+	assert.ErrorContains(t, ValidateEngineConfig(config), "AmbiguityPolicy")
+}
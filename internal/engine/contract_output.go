@@ -0,0 +1,216 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats of generated contracts
+const (
+	ContractFormatYAML = "yaml"
+	ContractFormatJSON = "json"
+)
+
+const (
+	// contractIndexName is the base name of the index file of a directory layout
+	contractIndexName = "index"
+	// contractEndpointsDir is the directory of the endpoint files of a directory layout
+	contractEndpointsDir = "endpoints"
+)
+
+// nonSlugCharacters matches the runs of characters replaced in endpoint file names
+var nonSlugCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// contractDocument is the serialized form of a generated contract, without the legacy fields
+// of ServiceSpec
+type contractDocument struct {
+	APIVersion string                      `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string                      `json:"kind" yaml:"kind"`
+	Metadata   *models.ServiceSpecMetadata `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Spec       interface{}                 `json:"spec" yaml:"spec"`
+}
+
+// endpointRef refers to an endpoint file from the index of a directory layout
+type endpointRef struct {
+	Ref string `json:"$ref" yaml:"$ref"`
+}
+
+// ValidateContractFormat checks that an output format is empty, yaml or json
+func ValidateContractFormat(format string) error {
+	switch format {
+	case "", ContractFormatYAML, ContractFormatJSON:
+		return nil
+	}
+	return fmt.Errorf("unsupported contract format %q, must be %s or %s", format, ContractFormatYAML, ContractFormatJSON)
+}
+
+// EncodeContract writes a contract as YAML, the default, or JSON
+func EncodeContract(w io.Writer, spec *models.ServiceSpec, format string) error {
+	return encodeContractValue(w, newContractDocument(spec, spec.Spec), format)
+}
+
+// WriteContract writes a contract to a file, creating its directory
+func WriteContract(path string, spec *models.ServiceSpec, format string) error {
+	return writeContractFile(path, newContractDocument(spec, spec.Spec), format)
+}
+
+// WriteContractDir writes a contract as one file per endpoint under dir/endpoints and an index
+// file that refers to them with $ref, so a large contract can be reviewed endpoint by endpoint;
+// the YAML index loads as the whole contract. Endpoint files left from an earlier layout are
+// removed. It returns the files written, the index first.
+func WriteContractDir(dir string, spec *models.ServiceSpec, format string) ([]string, error) {
+	if err := ValidateContractFormat(format); err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = ContractFormatYAML
+	}
+	extension := "." + format
+
+	var endpoints []models.EndpointSpec
+	if spec.Spec != nil {
+		endpoints = spec.Spec.Endpoints
+	}
+	names := endpointFileNames(endpoints)
+
+	refs := make([]endpointRef, len(endpoints))
+	written := []string{filepath.Join(dir, contractIndexName+extension)}
+	for i, endpoint := range endpoints {
+		relative := contractEndpointsDir + "/" + names[i] + extension
+		refs[i] = endpointRef{Ref: relative}
+		path := filepath.Join(dir, filepath.FromSlash(relative))
+		if err := writeContractFile(path, endpoint, format); err != nil {
+			return nil, err
+		}
+		written = append(written, path)
+	}
+	if err := removeStaleEndpointFiles(filepath.Join(dir, contractEndpointsDir), extension, written[1:]); err != nil {
+		return nil, err
+	}
+
+	index := newContractDocument(spec, struct {
+		Endpoints []endpointRef `json:"endpoints" yaml:"endpoints"`
+	}{Endpoints: refs})
+	if err := writeContractFile(written[0], index, format); err != nil {
+		return nil, err
+	}
+	return written, nil
+}
+
+// newContractDocument wraps the body of a contract with its header
+func newContractDocument(spec *models.ServiceSpec, body interface{}) contractDocument {
+	document := contractDocument{APIVersion: spec.APIVersion, Kind: spec.Kind, Metadata: spec.Metadata, Spec: body}
+	if document.APIVersion == "" {
+		document.APIVersion = "flowspec/v1alpha1"
+	}
+	if document.Kind == "" {
+		document.Kind = "ServiceSpec"
+	}
+	return document
+}
+
+// encodeContractValue writes a value as indented YAML or JSON
+func encodeContractValue(w io.Writer, value interface{}, format string) error {
+	switch format {
+	case "", ContractFormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(value); err != nil {
+			return fmt.Errorf("failed to encode contract as YAML: %w", err)
+		}
+		return encoder.Close()
+	case ContractFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(value); err != nil {
+			return fmt.Errorf("failed to encode contract as JSON: %w", err)
+		}
+		return nil
+	}
+	return ValidateContractFormat(format)
+}
+
+// writeContractFile encodes a value into a file, creating its directory
+func writeContractFile(path string, value interface{}, format string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := encodeContractValue(file, value, format); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// endpointFileNames derives a file name from each endpoint path, e.g. /api/users/{id} becomes
+// api-users-id; paths that reduce to the same name are numbered
+func endpointFileNames(endpoints []models.EndpointSpec) []string {
+	names := make([]string, len(endpoints))
+	used := make(map[string]bool)
+	for i, endpoint := range endpoints {
+		base := strings.Trim(nonSlugCharacters.ReplaceAllString(strings.ToLower(endpoint.Path), "-"), "-")
+		if base == "" {
+			base = "root"
+		}
+		name := base
+		for suffix := 2; used[name]; suffix++ {
+			name = fmt.Sprintf("%s-%d", base, suffix)
+		}
+		used[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+// removeStaleEndpointFiles removes the endpoint files of the format that were not just written
+func removeStaleEndpointFiles(dir, extension string, written []string) error {
+	keep := make(map[string]bool, len(written))
+	for _, path := range written {
+		keep[filepath.Base(path)] = true
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != extension || keep[entry.Name()] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to remove stale endpoint file: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOutputTestSpec() *models.ServiceSpec {
+	operation := func(method string, status int) models.OperationSpec {
+		return models.OperationSpec{
+			Method:    method,
+			Responses: models.ResponseSpec{StatusCodes: []int{status}, Aggregation: "exact"},
+			Required:  models.RequiredFieldsSpec{Query: []string{}, Headers: []string{}},
+		}
+	}
+	return &models.ServiceSpec{
+		APIVersion:  "flowspec/v1alpha1",
+		Kind:        "ServiceSpec",
+		Metadata:    &models.ServiceSpecMetadata{Name: "orders", Version: "v1.0.0"},
+		Description: "legacy field that must not be written",
+		Spec: &models.ServiceSpecDefinition{Endpoints: []models.EndpointSpec{
+			{Path: "/", Operations: []models.OperationSpec{operation("GET", 200)}},
+			{Path: "/api/orders/{id}", Operations: []models.OperationSpec{operation("GET", 200), operation("DELETE", 204)}},
+			{Path: "/api/orders/{ID}", Operations: []models.OperationSpec{operation("PUT", 200)}},
+		}},
+	}
+}
+
+func TestValidateContractFormat(t *testing.T) {
+	testCases := []struct {
+		format  string
+		wantErr bool
+	}{
+		{"", false},
+		{ContractFormatYAML, false},
+		{ContractFormatJSON, false},
+		{"toml", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			err := ValidateContractFormat(tc.format)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEncodeContract(t *testing.T) {
+	spec := newOutputTestSpec()
+
+	var yamlOutput bytes.Buffer
+	require.NoError(t, EncodeContract(&yamlOutput, spec, ContractFormatYAML))
+	assert.Contains(t, yamlOutput.String(), "apiVersion: flowspec/v1alpha1\nkind: ServiceSpec\n")
+	assert.Contains(t, yamlOutput.String(), "  endpoints:\n    - path: /\n")
+	assert.NotContains(t, yamlOutput.String(), "legacy field")
+
+	var jsonOutput bytes.Buffer
+	require.NoError(t, EncodeContract(&jsonOutput, spec, ContractFormatJSON))
+	var decoded models.ServiceSpec
+	require.NoError(t, json.Unmarshal(jsonOutput.Bytes(), &decoded))
+	assert.Equal(t, spec.Spec, decoded.Spec)
+	assert.Empty(t, decoded.Description)
+
+	assert.Error(t, EncodeContract(&bytes.Buffer{}, spec, "toml"))
+}
+
+func TestWriteContractDir(t *testing.T) {
+	dir := t.TempDir()
+	spec := newOutputTestSpec()
+
+	stale := filepath.Join(dir, "endpoints", "api-removed.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(stale), 0755))
+	require.NoError(t, os.WriteFile(stale, []byte("path: /api/removed\n"), 0644))
+
+	files, err := WriteContractDir(dir, spec, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "index.yaml"),
+		filepath.Join(dir, "endpoints", "root.yaml"),
+		filepath.Join(dir, "endpoints", "api-orders-id.yaml"),
+		filepath.Join(dir, "endpoints", "api-orders-id-2.yaml"),
+	}, files)
+	assert.NoFileExists(t, stale)
+
+	index, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(index), "$ref: endpoints/api-orders-id-2.yaml")
+
+	// The index loads as the whole contract
+	specs, parseErrors := parser.NewYAMLFileParser().ParseFile(files[0])
+	require.Empty(t, parseErrors)
+	require.Len(t, specs, 1)
+	assert.Equal(t, spec.Metadata, specs[0].Metadata)
+	require.Len(t, specs[0].Spec.Endpoints, 3)
+	for i, endpoint := range specs[0].Spec.Endpoints {
+		assert.Equal(t, spec.Spec.Endpoints[i].Path, endpoint.Path)
+		assert.Len(t, endpoint.Operations, len(spec.Spec.Endpoints[i].Operations))
+	}
+}
+
+func TestWriteContractDir_JSON(t *testing.T) {
+	dir := t.TempDir()
+
+	files, err := WriteContractDir(dir, newOutputTestSpec(), ContractFormatJSON)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "index.json"), files[0])
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	var index struct {
+		Spec struct {
+			Endpoints []map[string]string `json:"endpoints"`
+		} `json:"spec"`
+	}
+	require.NoError(t, json.Unmarshal(data, &index))
+	assert.Equal(t, []map[string]string{
+		{"$ref": "endpoints/root.json"},
+		{"$ref": "endpoints/api-orders-id.json"},
+		{"$ref": "endpoints/api-orders-id-2.json"},
+	}, index.Spec.Endpoints)
+
+	data, err = os.ReadFile(files[2])
+	require.NoError(t, err)
+	var endpoint models.EndpointSpec
+	require.NoError(t, json.Unmarshal(data, &endpoint))
+	assert.Equal(t, "/api/orders/{id}", endpoint.Path)
+	assert.Len(t, endpoint.Operations, 2)
+}
@@ -0,0 +1,126 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// Placement of the traffic statistics of generated contracts
+const (
+	StatsInline  = "inline"  // In the contract, next to each endpoint and operation
+	StatsSidecar = "sidecar" // In a <contract>.stats.<format> file next to the contract
+	StatsOmit    = "omit"    // Not written
+)
+
+// ContractStatsKind is the kind of the sidecar file holding the statistics of a contract;
+// directory scans ignore it as it is not a ServiceSpec
+const ContractStatsKind = "ServiceSpecStats"
+
+// contractStatsDocument is the serialized form of the sidecar statistics of a contract
+type contractStatsDocument struct {
+	APIVersion string                      `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string                      `json:"kind" yaml:"kind"`
+	Metadata   *models.ServiceSpecMetadata `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Endpoints  []endpointStatsEntry        `json:"endpoints" yaml:"endpoints"`
+}
+
+// endpointStatsEntry holds the statistics of an endpoint and of its operations
+type endpointStatsEntry struct {
+	Path       string                `json:"path" yaml:"path"`
+	Stats      *models.EndpointStats `json:"stats,omitempty" yaml:"stats,omitempty"`
+	Operations []operationStatsEntry `json:"operations,omitempty" yaml:"operations,omitempty"`
+}
+
+// operationStatsEntry holds the statistics of an operation
+type operationStatsEntry struct {
+	Method string                 `json:"method" yaml:"method"`
+	Stats  *models.OperationStats `json:"stats" yaml:"stats"`
+}
+
+// ValidateStatsMode checks that a stats placement is empty, inline, sidecar or omit
+func ValidateStatsMode(mode string) error {
+	switch mode {
+	case "", StatsInline, StatsSidecar, StatsOmit:
+		return nil
+	}
+	return fmt.Errorf("unsupported stats placement %q, must be %s, %s or %s", mode, StatsInline, StatsSidecar, StatsOmit)
+}
+
+// StatsPath returns the sidecar statistics file of a contract file, e.g. service-spec.stats.yaml
+// for service-spec.yaml
+func StatsPath(contractPath string) string {
+	extension := filepath.Ext(contractPath)
+	return strings.TrimSuffix(contractPath, extension) + ".stats" + extension
+}
+
+// PlaceStats prepares a generated contract to be written to contractPath, the index file of a
+// directory layout, with its statistics placed according to mode. Statistics change with every
+// run over new traffic, so keeping them out of the contract keeps its diffs to real changes.
+// It returns the contract to write, without statistics unless they stay inline, and the
+// sidecar file written for StatsSidecar; a sidecar left by an earlier run is removed otherwise.
+func PlaceStats(contractPath string, spec *models.ServiceSpec, format, mode string) (*models.ServiceSpec, string, error) {
+	if err := ValidateStatsMode(mode); err != nil {
+		return nil, "", err
+	}
+	sidecar := StatsPath(contractPath)
+	if mode != StatsSidecar {
+		if err := os.Remove(sidecar); err != nil && !os.IsNotExist(err) {
+			return nil, "", fmt.Errorf("failed to remove stale stats file: %w", err)
+		}
+	}
+	if mode == "" || mode == StatsInline || spec.Spec == nil {
+		return spec, "", nil
+	}
+
+	stripped := *spec
+	definition := &models.ServiceSpecDefinition{Endpoints: make([]models.EndpointSpec, len(spec.Spec.Endpoints))}
+	stripped.Spec = definition
+	document := contractStatsDocument{APIVersion: spec.APIVersion, Kind: ContractStatsKind, Metadata: spec.Metadata}
+	if document.APIVersion == "" {
+		document.APIVersion = "flowspec/v1alpha1"
+	}
+	for i, endpoint := range spec.Spec.Endpoints {
+		entry := endpointStatsEntry{Path: endpoint.Path, Stats: endpoint.Stats}
+		endpoint.Stats = nil
+		endpoint.Operations = append([]models.OperationSpec(nil), endpoint.Operations...)
+		for j := range endpoint.Operations {
+			if endpoint.Operations[j].Stats != nil {
+				entry.Operations = append(entry.Operations, operationStatsEntry{Method: endpoint.Operations[j].Method, Stats: endpoint.Operations[j].Stats})
+				endpoint.Operations[j].Stats = nil
+			}
+		}
+		definition.Endpoints[i] = endpoint
+		if entry.Stats != nil || len(entry.Operations) > 0 {
+			document.Endpoints = append(document.Endpoints, entry)
+		}
+	}
+
+	if mode == StatsOmit {
+		return &stripped, "", nil
+	}
+	if document.Endpoints == nil {
+		document.Endpoints = []endpointStatsEntry{}
+	}
+	if err := writeContractFile(sidecar, document, format); err != nil {
+		return nil, "", err
+	}
+	return &stripped, sidecar, nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStatsTestSpec() *models.ServiceSpec {
+	spec := newOutputTestSpec()
+	seen := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	endpoint := &spec.Spec.Endpoints[1]
+	endpoint.Stats = &models.EndpointStats{SupportCount: 12, FirstSeen: seen, LastSeen: seen.Add(time.Hour)}
+	endpoint.Operations[0].Stats = &models.OperationStats{SupportCount: 10, FirstSeen: seen, LastSeen: seen.Add(time.Hour)}
+	endpoint.Operations[1].Stats = &models.OperationStats{SupportCount: 2, FirstSeen: seen, LastSeen: seen}
+	return spec
+}
+
+func TestStatsPath(t *testing.T) {
+	assert.Equal(t, filepath.Join("contracts", "service-spec.stats.yaml"), StatsPath(filepath.Join("contracts", "service-spec.yaml")))
+	assert.Equal(t, "index.stats.json", StatsPath("index.json"))
+}
+
+func TestPlaceStats(t *testing.T) {
+	testCases := []struct {
+		mode        string
+		wantStats   bool
+		wantSidecar bool
+	}{
+		{"", true, false},
+		{StatsInline, true, false},
+		{StatsSidecar, false, true},
+		{StatsOmit, false, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run("mode "+tc.mode, func(t *testing.T) {
+			contractPath := filepath.Join(t.TempDir(), "service-spec.yaml")
+			spec := newStatsTestSpec()
+
+			placed, sidecar, err := PlaceStats(contractPath, spec, ContractFormatYAML, tc.mode)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantStats, placed.Spec.Endpoints[1].Stats != nil)
+			assert.Equal(t, tc.wantStats, placed.Spec.Endpoints[1].Operations[0].Stats != nil)
+			assert.NotNil(t, spec.Spec.Endpoints[1].Operations[0].Stats, "the contract passed in is unchanged")
+			if !tc.wantSidecar {
+				assert.Empty(t, sidecar)
+				assert.NoFileExists(t, StatsPath(contractPath))
+				return
+			}
+
+			assert.Equal(t, StatsPath(contractPath), sidecar)
+			data, err := os.ReadFile(sidecar)
+			require.NoError(t, err)
+			assert.Contains(t, string(data), "kind: ServiceSpecStats\n")
+			assert.Contains(t, string(data), "  - path: /api/orders/{id}\n    stats:\n      supportCount: 12\n")
+			assert.Contains(t, string(data), "      - method: DELETE\n        stats:\n          supportCount: 2\n")
+			assert.NotContains(t, string(data), "path: /\n", "endpoints without stats are left out")
+		})
+	}
+}
+
+func TestPlaceStats_StableContract(t *testing.T) {
+	dir := t.TempDir()
+	contractPath := filepath.Join(dir, "service-spec.yaml")
+
+	write := func(spec *models.ServiceSpec, mode string) string {
+		placed, _, err := PlaceStats(contractPath, spec, ContractFormatYAML, mode)
+		require.NoError(t, err)
+		require.NoError(t, WriteContract(contractPath, placed, ContractFormatYAML))
+		data, err := os.ReadFile(contractPath)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	first := write(newStatsTestSpec(), StatsSidecar)
+	later := newStatsTestSpec()
+	later.Spec.Endpoints[1].Operations[0].Stats.LastSeen = time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, first, write(later, StatsSidecar), "new traffic only changes the sidecar")
+	assert.NotContains(t, first, "supportCount")
+
+	// A directory scan loads the contract and ignores the sidecar
+	result, err := parser.NewSpecParser().ParseFromSource(dir)
+	require.NoError(t, err)
+	require.Len(t, result.Specs, 1)
+	assert.Equal(t, "orders", result.Specs[0].Metadata.Name)
+
+	write(newStatsTestSpec(), StatsOmit)
+	assert.NoFileExists(t, StatsPath(contractPath), "a stale sidecar is removed")
+
+	_, _, err = PlaceStats(contractPath, newStatsTestSpec(), ContractFormatYAML, "footer")
+	assert.Error(t, err)
+}
@@ -15,6 +15,7 @@
 package engine
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -22,6 +23,7 @@ import (
 
 	"github.com/flowspec/flowspec-cli/internal/ingestor"
 	"github.com/flowspec/flowspec-cli/internal/ingestor/traffic"
+	"github.com/flowspec/flowspec-cli/internal/membudget"
 	"github.com/flowspec/flowspec-cli/internal/models"
 )
 
@@ -59,6 +61,9 @@ type GenerationOptions struct {
 	
 	// ServiceVersion defines the version for the generated service spec
 	ServiceVersion string `json:"serviceVersion"`
+	
+	// MemoryBudget is checked while records are collected; nil for no limit
+	MemoryBudget *membudget.Budget `json:"-"`
 }
 
 // DefaultGenerationOptions returns default generation options
@@ -98,6 +103,7 @@ type OperationPattern struct {
 	// Internal tracking for field analysis
 	queryFieldCounts   map[string]int `json:"-"`
 	headerFieldCounts  map[string]int `json:"-"`
+	durationsMs        []float64      `json:"-"` // Durations of the records that logged one
 }
 
 // NewOperationPattern creates a new operation pattern
@@ -127,6 +133,10 @@ func (op *OperationPattern) AddRecord(record *traffic.NormalizedRecord) {
 		op.LastSeen = record.Timestamp
 	}
 	
+	if record.DurationMs > 0 {
+		op.durationsMs = append(op.durationsMs, record.DurationMs)
+	}
+	
 	// Track status codes
 	statusExists := false
 	for _, code := range op.StatusCodes {
@@ -186,6 +196,9 @@ func (op *OperationPattern) FinalizeStatusCodes(generator *ContractGeneratorLite
 	op.StatusRanges = ranges
 }
 
+// budgetCheckInterval is the number of records collected between memory budget checks
+const budgetCheckInterval = 10000
+
 // ContractGeneratorLite implements the ContractGenerator interface
 type ContractGeneratorLite struct {
 	options *GenerationOptions
@@ -207,16 +220,38 @@ func (c *ContractGeneratorLite) SetOptions(options *GenerationOptions) {
 
 // GenerateSpec processes traffic records and generates a ServiceSpec
 func (c *ContractGeneratorLite) GenerateSpec(it ingestor.Iterator[*traffic.NormalizedRecord]) (*models.ServiceSpec, error) {
+	return c.GenerateSpecContext(context.Background(), it)
+}
+
+// GenerateSpecContext generates a ServiceSpec, stopping early when ctx is cancelled. The spec
+// built from the records read so far is returned together with the cancellation error.
+func (c *ContractGeneratorLite) GenerateSpecContext(ctx context.Context, it ingestor.Iterator[*traffic.NormalizedRecord]) (*models.ServiceSpec, error) {
 	// Collect all records for analysis
 	var records []*traffic.NormalizedRecord
-	for it.Next() {
+	for ctx.Err() == nil && it.Next() {
 		records = append(records, it.Value())
+		if len(records)%budgetCheckInterval == 0 {
+			if err := c.options.MemoryBudget.Check(fmt.Sprintf("collecting traffic records (%d so far)", len(records))); err != nil {
+				it.Close()
+				return nil, err
+			}
+		}
+	}
+	
+	if err := ctx.Err(); err != nil {
+		it.Close()
+		return c.generateFromRecords(records), fmt.Errorf("contract generation cancelled after %d records: %w", len(records), err)
 	}
 	
 	if err := it.Err(); err != nil {
 		return nil, err
 	}
 	
+	return c.generateFromRecords(records), nil
+}
+
+// generateFromRecords clusters records into endpoint patterns and converts them to a ServiceSpec
+func (c *ContractGeneratorLite) generateFromRecords(records []*traffic.NormalizedRecord) *models.ServiceSpec {
 	// Cluster paths and generate patterns
 	patterns := c.clusterPaths(records)
 	
@@ -229,7 +264,7 @@ func (c *ContractGeneratorLite) GenerateSpec(it ingestor.Iterator[*traffic.Norma
 	}
 	
 	// Convert patterns to ServiceSpec
-	return c.patternsToServiceSpec(filteredPatterns), nil
+	return c.patternsToServiceSpec(filteredPatterns)
 }
 
 // clusterPaths analyzes traffic records and clusters similar paths into parameterized patterns
@@ -710,6 +745,7 @@ func (c *ContractGeneratorLite) patternsToServiceSpec(patterns map[string]*Endpo
 					SupportCount: op.SampleCount,
 					FirstSeen:    op.FirstSeen,
 					LastSeen:     op.LastSeen,
+					Latency:      models.NewLatencyStats(op.durationsMs),
 				},
 			}
 			
@@ -15,6 +15,7 @@
 package engine
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -483,4 +484,43 @@ func TestContractGeneratorLite_PathSegmentAnalysis_EdgeCases(t *testing.T) {
 			assert.Equal(t, tc.expected, result)
 		})
 	}
-}
\ No newline at end of file
+}
+// cancellingIterator cancels the generation context after a number of records, like a signal
+// arriving during a long explore run
+type cancellingIterator struct {
+	*ingestor.SliceIterator[*traffic.NormalizedRecord]
+	cancel func()
+	after  int
+	read   int
+}
+
+func (c *cancellingIterator) Next() bool {
+	if c.read == c.after {
+		c.cancel()
+	}
+	c.read++
+	return c.SliceIterator.Next()
+}
+
+func TestContractGeneratorLite_EdgeCases_Cancelled(t *testing.T) {
+	generator := NewContractGeneratorLite()
+	options := DefaultGenerationOptions()
+	options.MinEndpointSamples = 1
+	generator.SetOptions(options)
+
+	var records []*traffic.NormalizedRecord
+	for i := 0; i < 10; i++ {
+		records = append(records, &traffic.NormalizedRecord{Method: "GET", Path: fmt.Sprintf("/api/items%d", i), Status: 200, Timestamp: time.Now()})
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	iterator := &cancellingIterator{SliceIterator: ingestor.NewSliceIterator(records), cancel: cancel, after: 3}
+
+	spec, err := generator.GenerateSpecContext(ctx, iterator)
+
+	// The record returned by the call that cancelled is still kept
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "after 4 records")
+	require.NotNil(t, spec)
+	assert.Len(t, spec.Spec.Endpoints, 4)
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/flowspec/flowspec-cli/internal/ingestor"
 	"github.com/flowspec/flowspec-cli/internal/ingestor/traffic"
+	"github.com/flowspec/flowspec-cli/internal/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -724,4 +725,38 @@ func TestContractGeneratorLite_GenerateSpec_Integration(t *testing.T) {
 			assert.Greater(t, operation.Stats.SupportCount, 0, "Operation should have support count > 0")
 		}
 	}
-}
\ No newline at end of file
+}
+func TestContractGeneratorLite_GenerateSpec_Latency(t *testing.T) {
+	generator := NewContractGeneratorLite()
+	generator.SetOptions(&GenerationOptions{
+		PathClusteringThreshold: 0.8,
+		MinSampleSize:           2,
+		RequiredFieldThreshold:  0.8,
+		MinEndpointSamples:      2,
+		StatusAggregation:       "auto",
+		ServiceName:             "test-service",
+		ServiceVersion:          "v1.0.0",
+	})
+
+	baseTime := time.Now()
+	records := []*traffic.NormalizedRecord{
+		{Method: "GET", Path: "/api/posts", Status: 200, Timestamp: baseTime, DurationMs: 30},
+		{Method: "GET", Path: "/api/posts", Status: 200, Timestamp: baseTime.Add(time.Minute), DurationMs: 10},
+		{Method: "GET", Path: "/api/posts", Status: 200, Timestamp: baseTime.Add(2 * time.Minute)}, // No duration logged
+		{Method: "POST", Path: "/api/posts", Status: 201, Timestamp: baseTime.Add(3 * time.Minute)},
+		{Method: "POST", Path: "/api/posts", Status: 201, Timestamp: baseTime.Add(4 * time.Minute)},
+	}
+
+	spec, err := generator.GenerateSpec(ingestor.NewSliceIterator(records))
+	require.NoError(t, err)
+	require.Len(t, spec.Spec.Endpoints, 1)
+
+	operations := make(map[string]models.OperationSpec)
+	for _, operation := range spec.Spec.Endpoints[0].Operations {
+		operations[operation.Method] = operation
+	}
+	require.Contains(t, operations, "GET")
+	assert.Equal(t, &models.LatencyStats{Samples: 2, P50Ms: 10, P95Ms: 30, P99Ms: 30, MaxMs: 30}, operations["GET"].Stats.Latency)
+	require.Contains(t, operations, "POST")
+	assert.Nil(t, operations["POST"].Stats.Latency)
+}
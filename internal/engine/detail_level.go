@@ -0,0 +1,83 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Detail levels of failure messages; reports keep the full span context in their contextInfo
+// and spanContext fields whatever the level
+const (
+	DetailMinimal = "minimal" // The failed assertion with its expected and actual values
+	DetailNormal  = "normal"  // Also the span status, its first attributes and the trace IDs
+	DetailFull    = "full"    // Also every span attribute, however long
+)
+
+// Caps of the span attributes quoted by failure messages at the normal level
+const (
+	maxQuotedAttributes  = 10
+	maxQuotedValueLength = 120
+)
+
+// ValidateDetailLevel checks that a detail level is empty, minimal, normal or full
+func ValidateDetailLevel(level string) error {
+	switch level {
+	case "", DetailMinimal, DetailNormal, DetailFull:
+		return nil
+	}
+	return fmt.Errorf("unsupported detail level %q, must be %s, %s or %s", level, DetailMinimal, DetailNormal, DetailFull)
+}
+
+// writeSpanAttributes quotes span attributes in a failure message, sorted by key; unless full,
+// only the first maxQuotedAttributes are quoted and long values are truncated
+func writeSpanAttributes(message *strings.Builder, attributes map[string]interface{}, full bool) {
+	if len(attributes) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	omitted := 0
+	if !full && len(keys) > maxQuotedAttributes {
+		omitted = len(keys) - maxQuotedAttributes
+		keys = keys[:maxQuotedAttributes]
+	}
+	message.WriteString("Relevant Span Attributes:\n")
+	for _, key := range keys {
+		value := fmt.Sprint(attributes[key])
+		if !full && len(value) > maxQuotedValueLength {
+			value = truncateUTF8(value, maxQuotedValueLength) + "…"
+		}
+		message.WriteString(fmt.Sprintf("  %s: %s\n", key, value))
+	}
+	if omitted > 0 {
+		message.WriteString(fmt.Sprintf("  … %d more in the contextInfo of the JSON report\n", omitted))
+	}
+}
+
+// truncateUTF8 cuts a string to at most limit bytes without splitting a character
+func truncateUTF8(value string, limit int) string {
+	for limit > 0 && limit < len(value) && !utf8.RuneStart(value[limit]) {
+		limit--
+	}
+	return value[:limit]
+}
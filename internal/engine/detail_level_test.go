@@ -0,0 +1,79 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateActionableErrorMessage_DetailLevels(t *testing.T) {
+	attributes := map[string]interface{}{"http.request.body": strings.Repeat("é", 200)}
+	for i := 0; i < 14; i++ {
+		attributes[fmt.Sprintf("app.attribute_%02d", i)] = i
+	}
+	span := &models.Span{SpanID: "s1", TraceID: "t1", Name: "POST /orders", Status: models.SpanStatus{Code: "ERROR"}, Attributes: attributes}
+	context := NewEvaluationContext(span, nil)
+	assertion := map[string]interface{}{"==": []interface{}{map[string]interface{}{"var": "span.status.code"}, "OK"}}
+	failed := &AssertionResult{Passed: false, Expected: "OK", Actual: "ERROR", Expression: `{"==":[{"var":"span.status.code"},"OK"]}`}
+	message := func(level string) string {
+		engine := NewAlignmentEngineWithConfig(&EngineConfig{DetailLevel: level})
+		return engine.generateActionableErrorMessage("postcondition", assertion, failed, span, context)
+	}
+
+	minimal := message(DetailMinimal)
+	assert.Contains(t, minimal, "Postcondition assertion failed in span 'POST /orders' (ID: s1)")
+	assert.Contains(t, minimal, "Actual: ")
+	assert.NotContains(t, minimal, "Span Status")
+	assert.NotContains(t, minimal, "app.attribute_00")
+
+	normal := message("")
+	assert.Equal(t, normal, message(DetailNormal))
+	assert.Contains(t, normal, "Span Status: ERROR")
+	assert.Contains(t, normal, "  app.attribute_00: 0\n  app.attribute_01: 1\n", "attributes are sorted")
+	assert.Contains(t, normal, "  app.attribute_09: 9\n  … 5 more in the contextInfo of the JSON report\n")
+	assert.NotContains(t, normal, "app.attribute_10")
+	assert.Contains(t, normal, "Trace ID: t1")
+
+	full := message(DetailFull)
+	assert.Contains(t, full, "app.attribute_13: 13")
+	assert.Contains(t, full, "http.request.body: "+strings.Repeat("é", 200))
+	assert.NotContains(t, full, "more in the contextInfo")
+}
+
+func TestWriteSpanAttributes_TruncatesLongValues(t *testing.T) {
+	var message strings.Builder
+	writeSpanAttributes(&message, map[string]interface{}{"http.request.body": strings.Repeat("é", 100)}, false)
+	assert.Equal(t, "Relevant Span Attributes:\n  http.request.body: "+strings.Repeat("é", maxQuotedValueLength/2)+"…\n", message.String())
+
+	message.Reset()
+	writeSpanAttributes(&message, map[string]interface{}{"http.target": "/a" + strings.Repeat("b", 200)}, false)
+	assert.Equal(t, maxQuotedValueLength, len(strings.TrimSuffix(strings.TrimPrefix(message.String(), "Relevant Span Attributes:\n  http.target: "), "…\n")))
+
+	message.Reset()
+	writeSpanAttributes(&message, nil, true)
+	assert.Empty(t, message.String())
+}
+
+func TestValidateDetailLevel(t *testing.T) {
+	for _, level := range []string{"", DetailMinimal, DetailNormal, DetailFull} {
+		assert.NoError(t, ValidateDetailLevel(level))
+	}
+	assert.EqualError(t, ValidateDetailLevel("verbose"), `unsupported detail level "verbose", must be minimal, normal or full`)
+}
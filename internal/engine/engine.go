@@ -15,14 +15,21 @@
 package engine
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/flowspec/flowspec-cli/internal/concurrency"
+	"github.com/flowspec/flowspec-cli/internal/membudget"
 	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/scrub"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -30,7 +37,9 @@ import (
 // AlignmentEngine defines the interface for aligning ServiceSpecs with trace data
 type AlignmentEngine interface {
 	AlignSpecsWithTrace(specs []models.ServiceSpec, traceData *models.TraceData) (*models.AlignmentReport, error)
+	AlignSpecsWithTraceContext(ctx context.Context, specs []models.ServiceSpec, traceData *models.TraceData) (*models.AlignmentReport, error)
 	AlignSingleSpec(spec models.ServiceSpec, traceData *models.TraceData) (*models.AlignmentResult, error)
+	AlignSingleSpecContext(ctx context.Context, spec models.ServiceSpec, traceData *models.TraceData) (*models.AlignmentResult, error)
 	SetEvaluator(evaluator AssertionEvaluator)
 	GetEvaluator() AssertionEvaluator
 }
@@ -47,6 +56,7 @@ type EvaluationContext struct {
 	TraceData *models.TraceData
 	Variables map[string]interface{}
 	Timestamp time.Time
+	Location  *time.Location // Zone of the span.start and span.end time variables; UTC when nil
 	mu        sync.RWMutex
 }
 
@@ -64,16 +74,41 @@ type AssertionResult struct {
 type DefaultAlignmentEngine struct {
 	evaluator AssertionEvaluator
 	config    *EngineConfig
+	pool      *WorkerPool // Created on first use with MaxConcurrency workers
 	mu        sync.RWMutex
 }
 
 // EngineConfig holds configuration for the alignment engine
 type EngineConfig struct {
-	MaxConcurrency   int           // Maximum number of concurrent alignments
-	Timeout          time.Duration // Timeout for individual spec alignment
-	EnableMetrics    bool          // Enable performance metrics
-	StrictMode       bool          // Strict mode for validation
-	SkipMissingSpans bool          // Skip specs when corresponding spans are not found
+	MaxConcurrency   int                          // Maximum number of concurrent alignments
+	TraceConcurrency int                          // Maximum number of traces aligned at once by AlignSpecsWithTraces
+	Timeout          time.Duration                // Timeout for individual spec alignment
+	EnableMetrics    bool                         // Enable performance metrics
+	StrictMode       bool                         // Strict mode for validation
+	SkipMissingSpans bool                         // Skip specs when corresponding spans are not found
+	Variables        map[string]interface{}       // External variables exposed to assertions as vars.*
+	ReportUnmatched  bool                         // Include a report section listing spans that matched no spec
+	Explain          bool                         // Record why each candidate span was accepted or rejected
+	EnforceSunset    bool                         // Fail deprecated operations that receive traffic after their sunset date
+	MaxFlakeRate     float64                      // Operations whose share of failing spans is at most this are FLAKY instead of FAILED; 0 disables
+	TimeWindow       *models.TimeWindow           // Only spans started within the window are verified; nil verifies every span
+	AttributeAliases models.AttributeAliases      // Span attributes read in place of http.method, http.route and the like, by field
+	SpanStatus       models.SpanStatusRules       // Rules mapping span status codes before assertions see them; rules of a spec take precedence
+	TimeZone         *time.Location               // Zone of the span.start and span.end time variables; UTC when nil
+	ClockSkew        time.Duration                // How much earlier than its parent a child span may start before the trace is reported skewed
+	FailOnIncomplete bool                         // Refuse to align traces with structural issues instead of reporting them
+	CorrectClockSkew bool                         // Move child spans starting before their parent or ending after their root by more than ClockSkew
+	Tags             []string                     // Only operations carrying one of these tags are verified; all when empty
+	Now              func() time.Time             // Clock used for sunset checks; time.Now when nil
+	OnResult         func(models.AlignmentResult) // Called with each spec result as soon as it completes, from a single goroutine
+	MemoryBudget     *membudget.Budget            // Heap budget checked before each spec; nil for no limit
+	Redactor         *scrub.Scrubber              // Redacts the span data quoted by validation details; nil quotes it verbatim
+	DetailLevel      string                       // How much span data failure messages quote: DetailMinimal, DetailNormal or DetailFull; normal when empty
+	SuggestionRules  models.SuggestionRules       // Organization-specific remediation added to the failed details the rules match
+	FailFast         bool                         // Stop aligning at the first failed operation or spec of critical priority
+	AmbiguityPolicy  string                       // How a span matching several operations of a spec is evaluated: AmbiguityMostSpecificWins, AmbiguityFail or AmbiguityAll; most-specific-wins when empty
+	TraceSampleRate  float64                      // Share of traces the backend kept by head sampling; 0 reads it from span attributes, 1 takes every trace as kept
+	Evaluator        AssertionEvaluator           // Evaluates assertions, e.g. dispatching custom operators to plugins; JSONLogic when nil
 }
 
 // SpecMatcher handles matching ServiceSpecs to spans
@@ -89,6 +124,11 @@ type MatchStrategy interface {
 	GetPriority() int
 }
 
+// ExplainingMatchStrategy is implemented by strategies that can explain a single span decision
+type ExplainingMatchStrategy interface {
+	Explain(spec models.ServiceSpec, span *models.Span) (bool, string)
+}
+
 // OperationIDMatcher matches specs to spans by operation ID
 type OperationIDMatcher struct{}
 
@@ -116,14 +156,17 @@ type ValidationContext struct {
 	mu        sync.RWMutex
 }
 
-// DefaultEngineConfig returns a default engine configuration
+// DefaultEngineConfig returns a default engine configuration, with as many workers and traces
+// aligned at once as GOMAXPROCS
 func DefaultEngineConfig() *EngineConfig {
 	return &EngineConfig{
-		MaxConcurrency:   4,
+		MaxConcurrency:   concurrency.Workers(concurrency.Auto),
+		TraceConcurrency: concurrency.Workers(concurrency.Auto),
 		Timeout:          30 * time.Second,
 		EnableMetrics:    true,
 		StrictMode:       false,
 		SkipMissingSpans: true,
+		Redactor:         scrub.DefaultSecretRedactor(),
 	}
 }
 
@@ -138,8 +181,11 @@ func NewAlignmentEngineWithConfig(config *EngineConfig) *DefaultAlignmentEngine
 		config: config,
 	}
 
-	// Set default JSONLogic evaluator
-	engine.evaluator = NewJSONLogicEvaluator()
+	// Set the configured evaluator, JSONLogic by default
+	engine.evaluator = config.Evaluator
+	if engine.evaluator == nil {
+		engine.evaluator = NewJSONLogicEvaluator()
+	}
 
 	return engine
 }
@@ -186,6 +232,15 @@ func NewValidationContext(spec models.ServiceSpec, span *models.Span, traceData
 func (engine *DefaultAlignmentEngine) AlignSpecsWithTrace(
 	specs []models.ServiceSpec,
 	traceData *models.TraceData,
+) (*models.AlignmentReport, error) {
+	return engine.AlignSpecsWithTraceContext(context.Background(), specs, traceData)
+}
+
+// AlignSpecsWithTraceContext aligns specs with trace data, stopping early when ctx is cancelled
+func (engine *DefaultAlignmentEngine) AlignSpecsWithTraceContext(
+	ctx context.Context,
+	specs []models.ServiceSpec,
+	traceData *models.TraceData,
 ) (*models.AlignmentReport, error) {
 	if len(specs) == 0 {
 		return models.NewAlignmentReport(), nil
@@ -195,10 +250,53 @@ func (engine *DefaultAlignmentEngine) AlignSpecsWithTrace(
 		return nil, fmt.Errorf("trace data is empty or nil")
 	}
 
+	if len(engine.config.Tags) > 0 {
+		if specs = models.FilterSpecsByTags(specs, engine.config.Tags); len(specs) == 0 {
+			return nil, fmt.Errorf("no contract operation is tagged %s", strings.Join(engine.config.Tags, " or "))
+		}
+	}
+
+	// Skew is corrected before the structure is checked, so corrected spans are not reported
+	var skewCorrection *models.ClockSkewCorrection
+	if engine.config.CorrectClockSkew {
+		traceData, skewCorrection = traceData.WithClockSkewCorrection(engine.config.ClockSkew)
+	}
+
+	// A broken trace structure makes alignment results misleading, so it is checked first
+	completeness := traceData.CheckCompleteness(engine.config.ClockSkew)
+	if engine.config.FailOnIncomplete {
+		if err := completeness.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	traceData = traceData.WithAttributeAliases(engine.config.AttributeAliases).WithSpanStatusRules(engine.config.SpanStatus)
+	if window := engine.config.TimeWindow; window != nil {
+		traceData = traceData.WithinTimeWindow(window)
+		if len(traceData.Spans) == 0 {
+			return nil, fmt.Errorf("no spans in the trace started %s", window)
+		}
+	}
+
+	// Workers stop the run with a budget error as the cause when memory runs out
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
 	// Initialize report with timing information
 	startTime := time.Now()
 	report := models.NewAlignmentReport()
 	report.StartTime = startTime.UnixNano()
+	report.TimeWindow = engine.config.TimeWindow
+	if !completeness.Complete() {
+		report.Completeness = completeness
+	}
+	report.ClockSkew = skewCorrection
+
+	// Counts of sampled traces are extrapolated to the traffic they stand for
+	sampling := engine.traceSampling(traceData)
+	if sampling != nil {
+		report.Sampling = sampling.info
+	}
 
 	// Initialize performance monitoring if enabled
 	var performanceInfo models.PerformanceInfo
@@ -213,75 +311,103 @@ func (engine *DefaultAlignmentEngine) AlignSpecsWithTrace(
 		}
 	}
 
-	// Create channels for concurrent processing
-	specChan := make(chan models.ServiceSpec, len(specs))
-	resultChan := make(chan *models.AlignmentResult, len(specs))
-	errorChan := make(chan error, len(specs))
-
-	// Determine number of workers
-	numWorkers := engine.config.MaxConcurrency
-	if numWorkers > len(specs) {
-		numWorkers = len(specs)
-	}
-
-	// Update performance info with worker count
+	// Dispatch specs to the shared worker pool, critical first and longest first within a
+	// priority; a worker takes the next spec as soon as it is free
+	pool := engine.workerPool()
 	if engine.config.EnableMetrics {
-		performanceInfo.ConcurrentWorkers = numWorkers
-	}
-
-	// Start workers
-	var wg sync.WaitGroup
-	for i := 0; i < numWorkers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			engine.alignmentWorker(specChan, resultChan, errorChan, traceData)
-		}()
+		performanceInfo.ConcurrentWorkers = pool.Size()
+		if performanceInfo.ConcurrentWorkers > len(specs) {
+			performanceInfo.ConcurrentWorkers = len(specs)
+		}
 	}
 
-	// Send specs to workers
-	for _, spec := range specs {
-		specChan <- spec
+	type specOutcome struct {
+		position int
+		result   *models.AlignmentResult
+		err      error
 	}
-	close(specChan)
-
-	// Wait for workers to complete
+	outcomes := make(chan specOutcome, len(specs))
 	go func() {
-		wg.Wait()
-		close(resultChan)
-		close(errorChan)
+		var wg sync.WaitGroup
+		defer close(outcomes)
+		defer wg.Wait()
+		for _, position := range scheduleSpecs(specs) {
+			spec := specs[position]
+			if err := engine.config.MemoryBudget.Check("aligning " + specOperationID(spec)); err != nil {
+				cancel(err)
+				return
+			}
+			position := position
+			wg.Add(1)
+			task := func() {
+				defer wg.Done()
+				result, err := engine.alignSingleSpecContext(ctx, spec, traceData)
+				outcomes <- specOutcome{position: position, result: result, err: err}
+			}
+			if err := pool.Submit(ctx, task); err != nil {
+				wg.Done()
+				return
+			}
+		}
 	}()
 
-	// Collect results and update performance metrics
-	var errors []error
+	// Collect results and update performance metrics. Results are streamed as they complete
+	// but added to the report in the order of the specs by priority, so reports of identical runs
+	// are identical.
+	var errs []error
+	var failFast *models.FailFastStop
 	spansMatched := 0
 	assertionsEvaluated := 0
+	results := make([]*models.AlignmentResult, len(specs))
 
-	for {
-		select {
-		case result, ok := <-resultChan:
-			if !ok {
-				resultChan = nil
-			} else {
-				report.AddResult(*result)
-
-				// Update performance metrics
-				if engine.config.EnableMetrics {
-					performanceInfo.SpecsProcessed++
-					spansMatched += len(result.MatchedSpans)
-					assertionsEvaluated += result.AssertionsTotal
-				}
-			}
-		case err, ok := <-errorChan:
-			if !ok {
-				errorChan = nil
-			} else {
-				errors = append(errors, err)
+	for outcome := range outcomes {
+		if outcome.err != nil {
+			errs = append(errs, outcome.err)
+			continue
+		}
+		result := outcome.result
+		result.Normalize()
+		sampling.applySampling(result, traceData)
+		results[outcome.position] = result
+		if engine.config.OnResult != nil {
+			engine.config.OnResult(*result)
+		}
+		if engine.config.FailFast && failFast == nil {
+			if failFast = criticalFailure(result); failFast != nil {
+				cancel(errFailFast)
 			}
 		}
 
-		if resultChan == nil && errorChan == nil {
-			break
+		// Update performance metrics
+		if engine.config.EnableMetrics {
+			performanceInfo.SpecsProcessed++
+			spansMatched += len(result.MatchedSpans)
+			assertionsEvaluated += result.AssertionsTotal
+		}
+	}
+
+	for _, position := range prioritizeSpecs(specs) {
+		if result := results[position]; result != nil {
+			report.AddResult(*result)
+		}
+	}
+
+	// Capture matched spans before strict mode adds its own result
+	if engine.config.ReportUnmatched && ctx.Err() == nil {
+		report.Unmatched = buildUnmatchedSpanReport(collectUnmatchedSpans(report, traceData, nil))
+	}
+
+	// In strict mode, traffic not covered by any spec operation is a failure, unless only the
+	// operations of some tags are verified
+	if engine.config.StrictMode && len(engine.config.Tags) == 0 && ctx.Err() == nil {
+		if uncovered := engine.buildUncoveredTrafficResult(report, traceData); uncovered != nil {
+			uncovered.Normalize()
+			engine.redactSpanContexts(uncovered)
+			engine.applySuggestionRules(uncovered)
+			report.AddResult(*uncovered)
+			if engine.config.OnResult != nil {
+				engine.config.OnResult(*uncovered)
+			}
 		}
 	}
 
@@ -307,9 +433,30 @@ func (engine *DefaultAlignmentEngine) AlignSpecsWithTrace(
 		report.PerformanceInfo = performanceInfo
 	}
 
+	// A stop at a critical failure, here or in another trace, is an outcome of the run, not an
+	// interruption
+	if errors.Is(context.Cause(ctx), errFailFast) {
+		if failFast != nil {
+			failFast.NotAligned = len(specs) - len(report.Results)
+			report.FailFast = failFast
+		}
+		return report, nil
+	}
+
+	// Report cancellation of the whole run along with whatever completed
+	if err := ctx.Err(); err != nil {
+		cause := context.Cause(ctx)
+		report.Incomplete = true
+		report.IncompleteReason = cause.Error()
+		if errors.Is(cause, membudget.ErrExceeded) {
+			return report, cause
+		}
+		return report, fmt.Errorf("alignment cancelled: %w", err)
+	}
+
 	// Return error if any critical errors occurred
-	if len(errors) > 0 && len(report.Results) == 0 {
-		return nil, fmt.Errorf("alignment failed with %d errors: %v", len(errors), errors[0])
+	if len(errs) > 0 && len(report.Results) == 0 {
+		return nil, fmt.Errorf("alignment failed with %d errors: %v", len(errs), errs[0])
 	}
 
 	return report, nil
@@ -319,31 +466,173 @@ func (engine *DefaultAlignmentEngine) AlignSpecsWithTrace(
 func (engine *DefaultAlignmentEngine) AlignSingleSpec(
 	spec models.ServiceSpec,
 	traceData *models.TraceData,
+) (*models.AlignmentResult, error) {
+	return engine.AlignSingleSpecContext(context.Background(), spec, traceData)
+}
+
+// AlignSingleSpecContext aligns a single spec, enforcing the configured per-spec timeout.
+// A spec exceeding the timeout yields a TIMEOUT result; cancellation of ctx yields an error.
+// Attribute aliases and span status rules apply as they do when aligning several specs.
+func (engine *DefaultAlignmentEngine) AlignSingleSpecContext(
+	ctx context.Context,
+	spec models.ServiceSpec,
+	traceData *models.TraceData,
+) (*models.AlignmentResult, error) {
+	if traceData != nil {
+		traceData = traceData.WithAttributeAliases(engine.config.AttributeAliases).WithSpanStatusRules(engine.config.SpanStatus)
+	}
+	return engine.alignSingleSpecContext(ctx, spec, traceData)
+}
+
+// alignSingleSpecContext aligns a single spec with a trace whose aliases and status rules are
+// already applied
+func (engine *DefaultAlignmentEngine) alignSingleSpecContext(
+	ctx context.Context,
+	spec models.ServiceSpec,
+	traceData *models.TraceData,
 ) (*models.AlignmentResult, error) {
 	if engine.evaluator == nil {
 		return nil, fmt.Errorf("no assertion evaluator configured")
 	}
 
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("alignment of %s cancelled: %w", specOperationID(spec), err)
+	}
+
+	specCtx := ctx
+	if engine.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		specCtx, cancel = context.WithTimeout(ctx, engine.config.Timeout)
+		defer cancel()
+	}
+
 	startTime := time.Now()
-	
-	// Handle both legacy and YAML formats
-	var operationID string
+
+	// Run the alignment in its own goroutine so a runaway spec cannot block the caller
+	type alignOutcome struct {
+		result *models.AlignmentResult
+		err    error
+	}
+	done := make(chan alignOutcome, 1)
+	go func() {
+		// A panicking evaluator or matcher fails this spec only, not the whole run
+		defer func() {
+			if r := recover(); r != nil {
+				done <- alignOutcome{result: engine.newPanicResult(spec, startTime, r)}
+			}
+		}()
+		result, err := engine.alignSpec(specCtx, spec, traceData, startTime)
+		done <- alignOutcome{result: result, err: err}
+	}()
+
+	select {
+	case outcome := <-done:
+		if outcome.err != nil && errors.Is(outcome.err, context.DeadlineExceeded) && ctx.Err() == nil {
+			return engine.newTimeoutResult(spec, startTime), nil
+		}
+		return outcome.result, outcome.err
+	case <-specCtx.Done():
+		// The parent context was cancelled rather than the spec timing out
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("alignment of %s cancelled: %w", specOperationID(spec), err)
+		}
+		return engine.newTimeoutResult(spec, startTime), nil
+	}
+}
+
+// alignSpec dispatches alignment to the YAML or legacy implementation
+func (engine *DefaultAlignmentEngine) alignSpec(
+	ctx context.Context,
+	spec models.ServiceSpec,
+	traceData *models.TraceData,
+	startTime time.Time,
+) (*models.AlignmentResult, error) {
+	result := newSpecResult(spec)
+	result.StartTime = startTime.UnixNano()
+
+	var err error
 	if spec.IsYAMLFormat() {
-		operationID = fmt.Sprintf("%s-%s", spec.Metadata.Name, spec.Metadata.Version)
+		// Handle YAML format with operations
+		result, err = engine.alignYAMLSpec(ctx, spec, traceData, result, startTime)
 	} else {
-		operationID = spec.OperationID
+		// Handle legacy format
+		result, err = engine.alignLegacySpec(ctx, spec, traceData, result, startTime)
 	}
-	
-	result := models.NewAlignmentResult(operationID)
+
+	if result != nil {
+		result.RollUpOperations()
+		result.Normalize()
+		engine.redactSpanContexts(result)
+		engine.applySuggestionRules(result)
+		result.FailureGroups = models.GroupFailures(result.Details)
+	}
+	return result, err
+}
+
+// newPanicResult creates a FAILED result for a spec whose alignment panicked
+func (engine *DefaultAlignmentEngine) newPanicResult(spec models.ServiceSpec, startTime time.Time, recovered interface{}) *models.AlignmentResult {
+	result := newSpecResult(spec)
+	endTime := time.Now()
+	result.Status = models.StatusFailed
+	result.StartTime = startTime.UnixNano()
+	result.EndTime = endTime.UnixNano()
+	result.ExecutionTime = endTime.Sub(startTime).Nanoseconds()
+	result.ErrorMessage = fmt.Sprintf("alignment panicked: %v", recovered)
+	return result
+}
+
+// newTimeoutResult creates a TIMEOUT result for a spec that exceeded the configured timeout
+func (engine *DefaultAlignmentEngine) newTimeoutResult(spec models.ServiceSpec, startTime time.Time) *models.AlignmentResult {
+	result := newSpecResult(spec)
+	endTime := time.Now()
+	result.Status = models.StatusTimeout
 	result.StartTime = startTime.UnixNano()
+	result.EndTime = endTime.UnixNano()
+	result.ExecutionTime = endTime.Sub(startTime).Nanoseconds()
+	result.ErrorMessage = fmt.Sprintf("alignment exceeded timeout of %s and was aborted", engine.config.Timeout)
+	return result
+}
+
+// newSpecResult creates an alignment result for a spec, recording its location and, for YAML specs, its service
+func newSpecResult(spec models.ServiceSpec) *models.AlignmentResult {
+	result := models.NewAlignmentResult(specOperationID(spec))
+	result.SourceFile = spec.SourceFile
+	result.LineNumber = spec.LineNumber
+	result.Priority = spec.SpecPriority()
+	if spec.IsYAMLFormat() {
+		result.Service = spec.Metadata.Name
+	}
+	return result
+}
 
-	// Handle YAML format with operations
+// specOperationID returns the identifier used for a spec in alignment results
+func specOperationID(spec models.ServiceSpec) string {
+	// Handle both legacy and YAML formats
 	if spec.IsYAMLFormat() {
-		return engine.alignYAMLSpec(spec, traceData, result, startTime)
+		return fmt.Sprintf("%s-%s", spec.Metadata.Name, spec.Metadata.Version)
+	}
+	return spec.OperationID
+}
+
+// workerPool returns the engine's worker pool, starting it on first use
+func (engine *DefaultAlignmentEngine) workerPool() *WorkerPool {
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+	if engine.pool == nil {
+		engine.pool = NewWorkerPool(engine.config.MaxConcurrency)
 	}
+	return engine.pool
+}
 
-	// Handle legacy format
-	return engine.alignLegacySpec(spec, traceData, result, startTime)
+// Close stops the engine's worker pool. The engine starts a new pool if it is used again.
+func (engine *DefaultAlignmentEngine) Close() {
+	engine.mu.Lock()
+	pool := engine.pool
+	engine.pool = nil
+	engine.mu.Unlock()
+	if pool != nil {
+		pool.Close()
+	}
 }
 
 // SetEvaluator implements the AlignmentEngine interface
@@ -362,17 +651,26 @@ func (engine *DefaultAlignmentEngine) GetEvaluator() AssertionEvaluator {
 
 // alignYAMLSpec handles alignment for YAML format specs
 func (engine *DefaultAlignmentEngine) alignYAMLSpec(
+	ctx context.Context,
 	spec models.ServiceSpec,
 	traceData *models.TraceData,
 	result *models.AlignmentResult,
 	startTime time.Time,
 ) (*models.AlignmentResult, error) {
-	// Process each endpoint and its operations
-	for _, endpoint := range spec.Spec.Endpoints {
-		for _, operation := range endpoint.Operations {
-			if err := engine.alignOperation(endpoint, operation, traceData, result); err != nil {
-				return nil, fmt.Errorf("failed to align operation %s %s: %w", operation.Method, endpoint.Path, err)
-			}
+	// Process each endpoint and its operations, with the spans left to them by the ambiguity
+	// policy, critical operations first
+	matches := engine.matchOperations(spec, traceData, result)
+	prioritizeOperations(matches)
+	for _, match := range matches {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := engine.alignOperation(ctx, match, traceData, result); err != nil {
+			return nil, fmt.Errorf("failed to align operation %s: %w", match.key, err)
+		}
+		// The run stops here; the remaining operations are left unaligned
+		if engine.config.FailFast && isCriticalFailure(match.operation.Priority, result.OperationResults[match.key].Status) {
+			break
 		}
 	}
 
@@ -385,6 +683,7 @@ func (engine *DefaultAlignmentEngine) alignYAMLSpec(
 
 // alignLegacySpec handles alignment for legacy format specs
 func (engine *DefaultAlignmentEngine) alignLegacySpec(
+	ctx context.Context,
 	spec models.ServiceSpec,
 	traceData *models.TraceData,
 	result *models.AlignmentResult,
@@ -396,9 +695,14 @@ func (engine *DefaultAlignmentEngine) alignLegacySpec(
 	if err != nil {
 		return nil, fmt.Errorf("failed to find matching spans: %w", err)
 	}
+	result.DuplicateSpans = droppedDuplicates(matchingSpans, traceData)
+
+	if engine.config.Explain {
+		result.Explanations = matcher.ExplainMatching(spec, traceData)
+	}
 
 	if len(matchingSpans) == 0 {
-		if engine.config.SkipMissingSpans {
+		if engine.skipMissingSpans() {
 			result.AddValidationDetail(*models.NewValidationDetail(
 				"matching", "span_match", "found", "found",
 				"No matching spans found for operation: "+spec.OperationID))
@@ -425,10 +729,17 @@ func (engine *DefaultAlignmentEngine) alignLegacySpec(
 
 	// Evaluate assertions for each matching span
 	for _, span := range matchingSpans {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		if err := engine.evaluateSpecForSpan(spec, span, traceData, result); err != nil {
 			return nil, fmt.Errorf("failed to evaluate spec for span %s: %w", span.SpanID, err)
 		}
 	}
+	if failed := len(result.GetFailedDetails()); failed > 0 && spec.Severity == models.SeverityWarning {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("%d assertion(s) of warning severity failed for operation %s", failed, spec.OperationID))
+	}
 
 	// Finalize timing
 	endTime := time.Now()
@@ -437,14 +748,14 @@ func (engine *DefaultAlignmentEngine) alignLegacySpec(
 	return result, nil
 }
 
-// alignOperation aligns a specific operation within an endpoint
+// alignOperation aligns a specific operation within an endpoint with its matched spans
 func (engine *DefaultAlignmentEngine) alignOperation(
-	endpoint models.EndpointSpec,
-	operation models.OperationSpec,
+	ctx context.Context,
+	match *operationMatch,
 	traceData *models.TraceData,
 	result *models.AlignmentResult,
 ) error {
-	operationKey := fmt.Sprintf("%s %s", operation.Method, endpoint.Path)
+	endpoint, operation, operationKey := match.endpoint, match.operation, match.key
 	
 	// Initialize operation result if not exists
 	if result.OperationResults == nil {
@@ -461,25 +772,38 @@ func (engine *DefaultAlignmentEngine) alignOperation(
 		AssertionsPassed: 0,
 		AssertionsFailed: 0,
 		SampleCount:      0,
+		SourceFile:       operation.SourceFile,
+		LineNumber:       operation.LineNumber,
+		Tags:             endpoint.OperationTags(operation),
+		Priority:         operation.Priority,
 	}
 	
 	result.OperationResults[operationKey] = operationResult
 
-	// Find matching spans for this specific operation
-	matchingSpans := engine.findMatchingSpansForOperation(endpoint, operation, traceData)
-	operationResult.SampleCount = len(matchingSpans)
+	if engine.config.Explain {
+		operationResult.Explanations = engine.explainOperationMatching(endpoint, operation, traceData)
+	}
 
+	matchingSpans := match.spans
 	if len(matchingSpans) == 0 {
 		detail := models.NewValidationDetail(
 			"matching", "span_match", "found", "not_found",
 			fmt.Sprintf("No matching spans found for operation: %s %s", operation.Method, endpoint.Path))
 		detail.Operation = operationKey
+		detail.Severity = operation.Severity
 		
-		if engine.config.SkipMissingSpans {
+		if engine.skipMissingSpans() {
 			detail.Actual = "found" // Mark as found to indicate skipped
 			operationResult.Status = models.StatusSkipped
-		} else {
+		} else if operation.Severity.Blocks() {
 			operationResult.Status = models.StatusFailed
+		} else {
+			operationResult.Status = models.StatusSkipped
+			if operation.Severity == models.SeverityWarning {
+				message := fmt.Sprintf("No matching spans found for operation %s of warning severity", operationKey)
+				operationResult.Warnings = append(operationResult.Warnings, message)
+				result.Warnings = append(result.Warnings, message)
+			}
 		}
 		
 		operationResult.Details = append(operationResult.Details, *detail)
@@ -487,40 +811,106 @@ func (engine *DefaultAlignmentEngine) alignOperation(
 		return nil
 	}
 
-	// Record matched span IDs
+	// Only the spans the operation's when guard holds for are matched and checked; an operation
+	// whose guard admits none of them is skipped as not applicable
+	firstDetail := len(result.Details)
+	admitted := make([]*models.Span, 0, len(matchingSpans))
+	contexts := make([]*EvaluationContext, 0, len(matchingSpans))
 	for _, span := range matchingSpans {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		context := engine.newOperationContext(endpoint, span, traceData)
+		if engine.guardHolds("operation", operation.When, context, span, result, operationResult, operationKey) {
+			admitted = append(admitted, span)
+			contexts = append(contexts, context)
+		}
+	}
+
+	duplicates := match.duplicates
+	if len(admitted) < len(matchingSpans) {
+		duplicates = droppedDuplicates(admitted, traceData)
+	}
+	operationResult.DuplicateSpans = duplicates
+	result.DuplicateSpans += duplicates
+	operationResult.SampleCount = len(admitted)
+
+	// Record matched span IDs
+	for _, span := range admitted {
 		operationResult.MatchedSpans = append(operationResult.MatchedSpans, span.SpanID)
 		result.MatchedSpans = append(result.MatchedSpans, span.SpanID)
 	}
 
-	// Evaluate operation-level validations for each matching span
-	for _, span := range matchingSpans {
-		if err := engine.evaluateOperationForSpan(endpoint, operation, span, traceData, result, operationResult, operationKey); err != nil {
+	// Evaluate operation-level validations for each admitted span
+	for i, span := range admitted {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := engine.evaluateOperationForSpan(endpoint, operation, span, contexts[i], result, operationResult, operationKey); err != nil {
 			return fmt.Errorf("failed to evaluate operation for span %s: %w", span.SpanID, err)
 		}
 	}
 
+	if operation.Deprecated && len(admitted) > 0 {
+		engine.checkDeprecation(operation, operationKey, len(admitted), result, operationResult)
+	}
+	addAmbiguityFailures(match, result, operationResult)
+
+	if operation.Severity != "" {
+		for _, details := range [][]models.ValidationDetail{operationResult.Details, result.Details[firstDetail:]} {
+			for i := range details {
+				details[i].Severity = operation.Severity
+			}
+		}
+	}
+
 	// Update operation status based on validation results
-	engine.updateOperationStatus(operationResult)
+	engine.updateOperationStatus(operationKey, result, operationResult)
 
 	return nil
 }
 
-// alignmentWorker processes specs concurrently
-func (engine *DefaultAlignmentEngine) alignmentWorker(
-	specChan <-chan models.ServiceSpec,
-	resultChan chan<- *models.AlignmentResult,
-	errorChan chan<- error,
-	traceData *models.TraceData,
+// checkDeprecation warns about traffic to a deprecated operation, or fails it when sunset
+// enforcement is on and the sunset date has passed
+func (engine *DefaultAlignmentEngine) checkDeprecation(
+	operation models.OperationSpec,
+	operationKey string,
+	requests int,
+	result *models.AlignmentResult,
+	operationResult *models.OperationResult,
 ) {
-	for spec := range specChan {
-		result, err := engine.AlignSingleSpec(spec, traceData)
-		if err != nil {
-			errorChan <- err
-		} else {
-			resultChan <- result
+	message := fmt.Sprintf("Deprecated operation %s still received %d request(s)", operationKey, requests)
+
+	if operation.Sunset != "" {
+		sunset, err := operation.SunsetTime()
+		if err == nil && engine.config.EnforceSunset && !engine.now().Before(sunset) {
+			detail := models.NewValidationDetail(
+				"deprecation", "sunset",
+				fmt.Sprintf("no traffic after %s", operation.Sunset),
+				fmt.Sprintf("%d request(s)", requests),
+				fmt.Sprintf("%s after its sunset date %s", message, operation.Sunset))
+			detail.Operation = operationKey
+			detail.Suggestions = []string{"Migrate the remaining clients or move the sunset date"}
+
+			operationResult.Details = append(operationResult.Details, *detail)
+			operationResult.AssertionsTotal++
+			operationResult.AssertionsFailed++
+			result.AddValidationDetail(*detail)
+			return
 		}
+		message = fmt.Sprintf("%s (sunset %s)", message, operation.Sunset)
+	}
+
+	operationResult.Warnings = append(operationResult.Warnings, message)
+	result.Warnings = append(result.Warnings, message)
+}
+
+// now returns the current time from the configured clock
+func (engine *DefaultAlignmentEngine) now() time.Time {
+	if engine.config.Now != nil {
+		return engine.config.Now()
 	}
+	return time.Now()
 }
 
 // findMatchingSpansForOperation finds spans that match a specific operation
@@ -531,7 +921,8 @@ func (engine *DefaultAlignmentEngine) findMatchingSpansForOperation(
 ) []*models.Span {
 	var matchingSpans []*models.Span
 
-	for _, span := range traceData.Spans {
+	// The index narrows the trace to spans with a compatible method and path shape
+	for _, span := range traceData.Index().OperationCandidates(operation.Method, endpoint.Path) {
 		if engine.spanMatchesOperation(span, endpoint, operation) {
 			matchingSpans = append(matchingSpans, span)
 		}
@@ -545,37 +936,135 @@ func (engine *DefaultAlignmentEngine) spanMatchesOperation(
 	span *models.Span,
 	endpoint models.EndpointSpec,
 	operation models.OperationSpec,
+) bool {
+	return engine.matchSpanToOperation(span, endpoint, operation, nil)
+}
+
+// matchSpanToOperation checks if a span matches the given operation, recording the
+// matcher decisions in explanation when it is non-nil
+func (engine *DefaultAlignmentEngine) matchSpanToOperation(
+	span *models.Span,
+	endpoint models.EndpointSpec,
+	operation models.OperationSpec,
+	explanation *models.MatchExplanation,
 ) bool {
 	// Check HTTP method
 	if method, ok := span.Attributes["http.method"].(string); ok {
 		if method != operation.Method {
+			explanation.Reject("http_method", fmt.Sprintf("method mismatch: span has %s, operation expects %s", method, operation.Method))
 			return false
 		}
+	} else {
+		explanation.Note("http_method", "attribute http.method missing, method not checked")
 	}
 
 	// Check path pattern matching
 	if path, ok := span.Attributes["http.target"].(string); ok {
 		if engine.pathMatches(path, endpoint.Path) {
+			explanation.Accept("http_target", fmt.Sprintf("http.target %s matches %s", path, endpoint.Path))
 			return true
 		}
+		explanation.Reject("http_target", explainPathMismatch("http.target", path, endpoint.Path))
+	} else {
+		explanation.Reject("http_target", "attribute http.target missing")
 	}
 
 	// Also check http.route attribute
 	if route, ok := span.Attributes["http.route"].(string); ok {
+		route = models.NormalizeRoute(route)
 		if engine.pathMatches(route, endpoint.Path) {
+			explanation.Accept("http_route", fmt.Sprintf("http.route %s matches %s", route, endpoint.Path))
 			return true
 		}
+		explanation.Reject("http_route", explainPathMismatch("http.route", route, endpoint.Path))
+	} else {
+		explanation.Reject("http_route", "attribute http.route missing")
 	}
 
 	// Check span name for operation matching
 	operationName := fmt.Sprintf("%s %s", operation.Method, endpoint.Path)
 	if span.Name == operationName {
+		explanation.Accept("span_name", fmt.Sprintf("span name equals %q", operationName))
 		return true
 	}
+	explanation.Reject("span_name", fmt.Sprintf("span name %q does not equal %q", span.Name, operationName))
 
 	return false
 }
 
+// explainOperationMatching records the matching decision for every span in the trace
+func (engine *DefaultAlignmentEngine) explainOperationMatching(
+	endpoint models.EndpointSpec,
+	operation models.OperationSpec,
+	traceData *models.TraceData,
+) []models.MatchExplanation {
+	explanations := make([]models.MatchExplanation, 0, len(traceData.Spans))
+	for _, span := range sortedSpans(traceData) {
+		explanation := models.NewMatchExplanation(span)
+		engine.matchSpanToOperation(span, endpoint, operation, explanation)
+		explanations = append(explanations, *explanation)
+	}
+	return explanations
+}
+
+// explainPathMismatch describes why a request path does not match an endpoint pattern
+func explainPathMismatch(attribute, requestPath, pattern string) string {
+	requestSegments := strings.Split(strings.Trim(requestPath, "/"), "/")
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+
+	if len(requestSegments) != len(patternSegments) {
+		return fmt.Sprintf("%s %s has %d path segments, %s has %d",
+			attribute, requestPath, len(requestSegments), pattern, len(patternSegments))
+	}
+
+	for i, patternSegment := range patternSegments {
+		if strings.HasPrefix(patternSegment, "{") && strings.HasSuffix(patternSegment, "}") {
+			continue
+		}
+		if requestSegments[i] != patternSegment {
+			return fmt.Sprintf("%s %s: path segment %d is %q, expected %q",
+				attribute, requestPath, i+1, requestSegments[i], patternSegment)
+		}
+	}
+
+	return fmt.Sprintf("%s %s does not match %s", attribute, requestPath, pattern)
+}
+
+// sortedSpans returns the spans of a trace ordered by start time and span ID
+func sortedSpans(traceData *models.TraceData) []*models.Span {
+	return traceData.Index().Spans()
+}
+
+// droppedDuplicates returns how many spans the ingestor dropped for sharing the ID of one of
+// spans, see TraceData.AddSpan; only the last span with an ID is evaluated, so a span exported
+// twice does not count its assertions twice
+func droppedDuplicates(spans []*models.Span, traceData *models.TraceData) int {
+	if len(traceData.DuplicateSpanIDs) == 0 {
+		return 0
+	}
+	matched := make(map[string]bool, len(spans))
+	for _, span := range spans {
+		matched[span.SpanID] = true
+	}
+	dropped := 0
+	for _, id := range traceData.DuplicateSpanIDs {
+		if matched[id] {
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// sortSpansByStart orders spans by start time and span ID, like the trace index
+func sortSpansByStart(spans []*models.Span) {
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i].StartTime != spans[j].StartTime {
+			return spans[i].StartTime < spans[j].StartTime
+		}
+		return spans[i].SpanID < spans[j].SpanID
+	})
+}
+
 // pathMatches checks if a request path matches an endpoint path pattern
 func (engine *DefaultAlignmentEngine) pathMatches(requestPath, endpointPath string) bool {
 	// Simple exact match for now
@@ -622,29 +1111,53 @@ func (engine *DefaultAlignmentEngine) matchPathPattern(requestPath, pattern stri
 	return true
 }
 
-// evaluateOperationForSpan evaluates an operation against a specific span
+// newOperationContext returns the evaluation context of a span for the checks of an endpoint's
+// operations, its path parameters named after the contract
+func (engine *DefaultAlignmentEngine) newOperationContext(
+	endpoint models.EndpointSpec,
+	span *models.Span,
+	traceData *models.TraceData,
+) *EvaluationContext {
+	context := NewEvaluationContext(span, traceData)
+	engine.populateEvaluationContext(context, span)
+	context.mu.Lock()
+	engine.populatePathParameters(context, span, endpoint.Path)
+	context.mu.Unlock()
+	return context
+}
+
+// evaluateOperationForSpan evaluates an operation against a span its when guard admits
 func (engine *DefaultAlignmentEngine) evaluateOperationForSpan(
 	endpoint models.EndpointSpec,
 	operation models.OperationSpec,
 	span *models.Span,
-	traceData *models.TraceData,
+	context *EvaluationContext,
 	result *models.AlignmentResult,
 	operationResult *models.OperationResult,
 	operationKey string,
 ) error {
-	context := NewEvaluationContext(span, traceData)
-
-	// Populate context with span data
-	engine.populateEvaluationContext(context, span)
+	// Checks guarded by a when expression that does not hold for the span do not apply to it
+	guardHolds := func(name string, when map[string]interface{}) bool {
+		return engine.guardHolds(name, when, context, span, result, operationResult, operationKey)
+	}
 
 	// Validate status codes
-	if err := engine.validateStatusCodes(operation, span, result, operationResult, operationKey); err != nil {
-		return fmt.Errorf("failed to validate status codes: %w", err)
+	if guardHolds("responses", operation.Responses.When) {
+		if err := engine.validateStatusCodes(operation, span, result, operationResult, operationKey); err != nil {
+			return fmt.Errorf("failed to validate status codes: %w", err)
+		}
 	}
 
 	// Validate required fields
-	if err := engine.validateRequiredFields(operation, span, result, operationResult, operationKey); err != nil {
-		return fmt.Errorf("failed to validate required fields: %w", err)
+	if guardHolds("required", operation.Required.When) {
+		if err := engine.validateRequiredFields(operation, span, result, operationResult, operationKey); err != nil {
+			return fmt.Errorf("failed to validate required fields: %w", err)
+		}
+	}
+
+	engine.validatePathParameters(endpoint, operation, span, result, operationResult, operationKey)
+	if operation.Request != nil && guardHolds("request", operation.Request.When) {
+		engine.validateRequestBody(operation, span, result, operationResult, operationKey)
 	}
 
 	return nil
@@ -726,6 +1239,8 @@ func (engine *DefaultAlignmentEngine) validateStatusCodes(
 
 	detail.Operation = operationKey
 	detail.SpanContext = span
+	// The expected value describes the accepted codes, so it never equals the actual code
+	detail.Passed = &matched
 	
 	operationResult.Details = append(operationResult.Details, *detail)
 	operationResult.AssertionsTotal++
@@ -846,6 +1361,9 @@ func (engine *DefaultAlignmentEngine) validateRequiredFields(
 			fmt.Sprintf("Required header '%s' is %s", requiredHeader, map[bool]string{true: "present", false: "missing"}[headerFound]))
 		detail.Operation = operationKey
 		detail.SpanContext = span
+		if !headerFound {
+			detail.ContextInfo = map[string]interface{}{"failure": models.FailureMissing, "keys": []string{requiredHeader}}
+		}
 		
 		operationResult.Details = append(operationResult.Details, *detail)
 		operationResult.AssertionsTotal++
@@ -888,6 +1406,9 @@ func (engine *DefaultAlignmentEngine) validateRequiredFields(
 			fmt.Sprintf("Required query parameter '%s' is %s", requiredQuery, map[bool]string{true: "present", false: "missing"}[queryFound]))
 		detail.Operation = operationKey
 		detail.SpanContext = span
+		if !queryFound {
+			detail.ContextInfo = map[string]interface{}{"failure": models.FailureMissing, "keys": []string{requiredQuery}}
+		}
 		
 		operationResult.Details = append(operationResult.Details, *detail)
 		operationResult.AssertionsTotal++
@@ -902,6 +1423,149 @@ func (engine *DefaultAlignmentEngine) validateRequiredFields(
 	return nil
 }
 
+// validatePathParameters checks the observed path parameter values of the span against the
+// operation's pathParams constraints. Parameters the request path does not reveal, e.g. when
+// the span only carries http.route, are not checked.
+func (engine *DefaultAlignmentEngine) validatePathParameters(
+	endpoint models.EndpointSpec,
+	operation models.OperationSpec,
+	span *models.Span,
+	result *models.AlignmentResult,
+	operationResult *models.OperationResult,
+	operationKey string,
+) {
+	if len(operation.PathParams) == 0 {
+		return
+	}
+
+	values := models.SpanPathParameters(span, endpoint.Path)
+	for _, name := range models.SortedPathParamNames(operation.PathParams) {
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+		constraint := operation.PathParams[name]
+		passed, reason := constraint.Check(value)
+
+		var detail *models.ValidationDetail
+		if passed {
+			detail = models.NewValidationDetail("path_param", name, constraint.String(), value,
+				fmt.Sprintf("Path parameter '%s' value %q is %s", name, value, constraint))
+		} else {
+			detail = models.NewValidationDetail("path_param", name, constraint.String(), value,
+				fmt.Sprintf("Path parameter '%s' value %q is %s", name, value, reason))
+			detail.FailureReason = fmt.Sprintf("expected %s", constraint)
+		}
+		addOperationDetail(detail, passed, span, result, operationResult, operationKey)
+	}
+}
+
+// validateRequestBody checks the request Content-Type of the span against the operation's
+// accepted media types and a recorded JSON body against its schema. Spans that do not record
+// the header or the body are not checked.
+func (engine *DefaultAlignmentEngine) validateRequestBody(
+	operation models.OperationSpec,
+	span *models.Span,
+	result *models.AlignmentResult,
+	operationResult *models.OperationResult,
+	operationKey string,
+) {
+	if operation.Request == nil {
+		return
+	}
+
+	contentType := models.SpanRequestContentType(span)
+	if len(operation.Request.ContentTypes) > 0 && contentType != "" {
+		expected := strings.Join(operation.Request.ContentTypes, ", ")
+		passed := operation.Request.AcceptsContentType(contentType)
+		detail := models.NewValidationDetail("request_content_type", "content-type", expected, contentType,
+			fmt.Sprintf("Request content type '%s' is %s", contentType, map[bool]string{true: "accepted", false: "not accepted"}[passed]))
+		if !passed {
+			detail.FailureReason = fmt.Sprintf("expected one of %s", expected)
+		}
+		addOperationDetail(detail, passed, span, result, operationResult, operationKey)
+	}
+
+	body, recorded := models.SpanRequestBody(span)
+	if operation.Request.Schema == nil || !recorded || (contentType != "" && !models.IsJSONMediaType(contentType)) {
+		return
+	}
+
+	var value interface{}
+	var detail *models.ValidationDetail
+	passed := false
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		detail = models.NewValidationDetail("request_schema", "body", "JSON matching schema", "invalid JSON",
+			fmt.Sprintf("Request body is not valid JSON: %v", err))
+		detail.FailureReason = "request body is not valid JSON"
+	} else if pointer, violation := models.ValidateAgainstSchema(operation.Request.Schema, value); violation != "" {
+		if pointer == "" {
+			pointer = "/"
+		}
+		detail = models.NewValidationDetail("request_schema", "body", "JSON matching schema", violation,
+			fmt.Sprintf("Request body at %s does not match the schema: %s", pointer, violation))
+		detail.FailureReason = fmt.Sprintf("%s: %s", pointer, violation)
+	} else {
+		passed = true
+		detail = models.NewValidationDetail("request_schema", "body", "JSON matching schema", "JSON matching schema",
+			"Request body matches the schema")
+	}
+	addOperationDetail(detail, passed, span, result, operationResult, operationKey)
+}
+
+// guardHolds evaluates the when guard of a group of checks against a span; checks without a
+// guard always apply. A guard that cannot be evaluated is reported as a failed check, and the
+// checks it guards are not applied.
+func (engine *DefaultAlignmentEngine) guardHolds(
+	name string,
+	when map[string]interface{},
+	context *EvaluationContext,
+	span *models.Span,
+	result *models.AlignmentResult,
+	operationResult *models.OperationResult,
+	operationKey string,
+) bool {
+	if len(when) == 0 {
+		return true
+	}
+
+	guard, err := engine.evaluator.EvaluateAssertion(when, context)
+	if err == nil && guard.Error != nil {
+		err = guard.Error
+	}
+	if err != nil {
+		detail := models.NewValidationDetail("when", name, "evaluable", "error",
+			fmt.Sprintf("The when guard of %s could not be evaluated: %v", name, err))
+		detail.FailureReason = err.Error()
+		addOperationDetail(detail, false, span, result, operationResult, operationKey)
+		return false
+	}
+	return guard.Passed
+}
+
+// addOperationDetail records a span check on the operation and the result and counts it
+func addOperationDetail(
+	detail *models.ValidationDetail,
+	passed bool,
+	span *models.Span,
+	result *models.AlignmentResult,
+	operationResult *models.OperationResult,
+	operationKey string,
+) {
+	detail.Operation = operationKey
+	detail.SpanContext = span
+	detail.Passed = &passed
+
+	operationResult.Details = append(operationResult.Details, *detail)
+	operationResult.AssertionsTotal++
+	if passed {
+		operationResult.AssertionsPassed++
+	} else {
+		operationResult.AssertionsFailed++
+	}
+	result.AddValidationDetail(*detail)
+}
+
 // evaluateSpecForSpan evaluates a spec against a specific span
 func (engine *DefaultAlignmentEngine) evaluateSpecForSpan(
 	spec models.ServiceSpec,
@@ -909,6 +1573,7 @@ func (engine *DefaultAlignmentEngine) evaluateSpecForSpan(
 	traceData *models.TraceData,
 	result *models.AlignmentResult,
 ) error {
+	span = spec.SpanStatus.Apply(span)
 	context := NewEvaluationContext(span, traceData)
 
 	// Populate context with span data
@@ -928,6 +1593,7 @@ func (engine *DefaultAlignmentEngine) evaluateSpecForSpan(
 			span,
 			context,
 		)
+		detail.Severity = spec.Severity
 		result.AddValidationDetail(*detail)
 	}
 
@@ -945,6 +1611,7 @@ func (engine *DefaultAlignmentEngine) evaluateSpecForSpan(
 			span,
 			context,
 		)
+		detail.Severity = spec.Severity
 		result.AddValidationDetail(*detail)
 	}
 
@@ -959,21 +1626,40 @@ func (engine *DefaultAlignmentEngine) createDetailedValidationDetail(
 	span *models.Span,
 	context *EvaluationContext,
 ) *models.ValidationDetail {
+	// Failure messages quote the span attributes, with their credentials redacted so reports
+	// can be shared; SpanContext is redacted along with the whole result
+	redactor := engine.config.Redactor
+	quoted := span
+	if redactor != nil && !assertionResult.Passed {
+		quoted = redactor.Span(span)
+	}
+
 	// Create enhanced validation detail
 	detail := &models.ValidationDetail{
 		Type:        detailType,
 		Expression:  assertionResult.Expression,
 		Expected:    assertionResult.Expected,
 		Actual:      assertionResult.Actual,
-		Message:     engine.generateActionableErrorMessage(detailType, assertion, assertionResult, span, context),
+		Message:     engine.generateActionableErrorMessage(detailType, assertion, assertionResult, quoted, context),
 		SpanContext: span,
 	}
 
 	// Add failure analysis if assertion failed
 	if !assertionResult.Passed {
 		detail.FailureReason = engine.analyzeFailureReason(assertion, assertionResult, context)
-		detail.ContextInfo = engine.extractContextInfo(span, context)
-		detail.Suggestions = engine.generateSuggestions(detailType, assertion, assertionResult, span)
+		detail.ContextInfo = engine.extractContextInfo(quoted, context)
+		detail.Suggestions = engine.generateSuggestions(detailType, assertion, assertionResult, quoted)
+	}
+
+	if redactor != nil {
+		detail.Actual = redactor.Value(detail.Actual)
+		detail.Message = redactor.Text(detail.Message)
+		detail.FailureReason = redactor.Text(detail.FailureReason)
+		detail.ContextInfo = redactor.Attributes(detail.ContextInfo)
+	}
+	if !assertionResult.Passed {
+		expected, actual := engine.extractMeaningfulValues(assertion, assertionResult, context)
+		recordFailureFacts(detail, assertion, expected, actual, context)
 	}
 
 	return detail
@@ -1005,6 +1691,9 @@ func (engine *DefaultAlignmentEngine) generateActionableErrorMessage(
 	expectedVal, actualVal := engine.extractMeaningfulValues(assertion, result, context)
 	msgBuilder.WriteString(fmt.Sprintf("Expected: %v (type: %T)\n", expectedVal, expectedVal))
 	msgBuilder.WriteString(fmt.Sprintf("Actual: %v (type: %T)\n", actualVal, actualVal))
+	if engine.config.DetailLevel == DetailMinimal {
+		return msgBuilder.String()
+	}
 
 	// Add JSONLogic evaluation result for reference
 	msgBuilder.WriteString(fmt.Sprintf("JSONLogic Result: Expected %v, Got %v\n", result.Expected, result.Actual))
@@ -1017,12 +1706,7 @@ func (engine *DefaultAlignmentEngine) generateActionableErrorMessage(
 	msgBuilder.WriteString("\n")
 
 	// Add relevant span attributes
-	if len(span.Attributes) > 0 {
-		msgBuilder.WriteString("Relevant Span Attributes:\n")
-		for key, value := range span.Attributes {
-			msgBuilder.WriteString(fmt.Sprintf("  %s: %v\n", key, value))
-		}
-	}
+	writeSpanAttributes(&msgBuilder, span.Attributes, engine.config.DetailLevel == DetailFull)
 
 	// Add trace context
 	msgBuilder.WriteString(fmt.Sprintf("Trace ID: %s\n", span.TraceID))
@@ -1371,6 +2055,13 @@ func (engine *DefaultAlignmentEngine) generateSuggestions(
 	return suggestions
 }
 
+// NewSpanEvaluationContext creates an evaluation context populated with the span, trace and external variables
+func (engine *DefaultAlignmentEngine) NewSpanEvaluationContext(span *models.Span, traceData *models.TraceData) *EvaluationContext {
+	context := NewEvaluationContext(span, traceData)
+	engine.populateEvaluationContext(context, span)
+	return context
+}
+
 // populateEvaluationContext populates the evaluation context with span data
 func (engine *DefaultAlignmentEngine) populateEvaluationContext(context *EvaluationContext, span *models.Span) {
 	context.mu.Lock()
@@ -1417,6 +2108,25 @@ func (engine *DefaultAlignmentEngine) populateEvaluationContext(context *Evaluat
 			context.Variables["trace.root_span.id"] = context.TraceData.RootSpan.SpanID
 		}
 	}
+
+	// Add external variables under the vars namespace
+	if engine.config != nil {
+		context.Location = engine.config.TimeZone
+		for key, value := range engine.config.Variables {
+			context.Variables[VariablePrefix+key] = value
+		}
+	}
+
+	// Add path parameters extracted with the span's route template
+	engine.populatePathParameters(context, span, "")
+}
+
+// populatePathParameters adds the path parameters of the span under the path namespace, named
+// after the given contract path or, when empty, the span's http.route
+func (engine *DefaultAlignmentEngine) populatePathParameters(context *EvaluationContext, span *models.Span, template string) {
+	for name, value := range models.SpanPathParameters(span, template) {
+		context.Variables[PathParameterPrefix+name] = value
+	}
 }
 
 // EvaluationContext methods
@@ -1540,6 +2250,30 @@ func (sm *SpecMatcher) FindMatchingSpans(spec models.ServiceSpec, traceData *mod
 	return sm.findMatchingSpansForLegacySpec(spec, traceData)
 }
 
+// ExplainMatching records, for every span, each strategy's decision for a legacy spec
+func (sm *SpecMatcher) ExplainMatching(spec models.ServiceSpec, traceData *models.TraceData) []models.MatchExplanation {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	explanations := make([]models.MatchExplanation, 0, len(traceData.Spans))
+	for _, span := range sortedSpans(traceData) {
+		explanation := models.NewMatchExplanation(span)
+		for _, strategy := range sm.matchStrategies {
+			explainer, ok := strategy.(ExplainingMatchStrategy)
+			if !ok {
+				continue
+			}
+			if matched, reason := explainer.Explain(spec, span); matched {
+				explanation.Accept(strategy.GetName(), reason)
+			} else {
+				explanation.Reject(strategy.GetName(), reason)
+			}
+		}
+		explanations = append(explanations, *explanation)
+	}
+	return explanations
+}
+
 // findMatchingSpansForYAMLSpec finds spans for YAML format specs
 func (sm *SpecMatcher) findMatchingSpansForYAMLSpec(spec models.ServiceSpec, traceData *models.TraceData) ([]*models.Span, error) {
 	var allMatchingSpans []*models.Span
@@ -1548,7 +2282,7 @@ func (sm *SpecMatcher) findMatchingSpansForYAMLSpec(spec models.ServiceSpec, tra
 	// Match spans for each endpoint and operation
 	for _, endpoint := range spec.Spec.Endpoints {
 		for _, operation := range endpoint.Operations {
-			for _, span := range traceData.Spans {
+			for _, span := range traceData.Index().OperationCandidates(operation.Method, endpoint.Path) {
 				if sm.spanMatchesEndpointOperation(span, endpoint, operation) {
 					spanSet[span.SpanID] = span
 				}
@@ -1556,10 +2290,11 @@ func (sm *SpecMatcher) findMatchingSpansForYAMLSpec(spec models.ServiceSpec, tra
 		}
 	}
 
-	// Convert map to slice
+	// Convert map to slice, in trace order so results do not depend on map iteration
 	for _, span := range spanSet {
 		allMatchingSpans = append(allMatchingSpans, span)
 	}
+	sortSpansByStart(allMatchingSpans)
 
 	return allMatchingSpans, nil
 }
@@ -1604,7 +2339,7 @@ func (sm *SpecMatcher) spanMatchesEndpointOperation(
 
 	// Also check http.route attribute
 	if route, ok := span.Attributes["http.route"].(string); ok {
-		if sm.pathMatches(route, endpoint.Path) {
+		if sm.pathMatches(models.NormalizeRoute(route), endpoint.Path) {
 			return true
 		}
 	}
@@ -1662,19 +2397,24 @@ func (matcher *OperationIDMatcher) Match(spec models.ServiceSpec, traceData *mod
 		return []*models.Span{}, nil
 	}
 
-	var matchingSpans []*models.Span
-
-	for _, span := range traceData.Spans {
-		if operationID, ok := span.Attributes["operation.id"].(string); ok {
-			if operationID == spec.OperationID {
-				matchingSpans = append(matchingSpans, span)
-			}
-		}
-	}
+	// Copy so that callers cannot modify the index
+	matchingSpans := append([]*models.Span(nil), traceData.Index().WithAttributeValue("operation.id", spec.OperationID)...)
 
 	return matchingSpans, nil
 }
 
+// Explain implements the ExplainingMatchStrategy interface
+func (matcher *OperationIDMatcher) Explain(spec models.ServiceSpec, span *models.Span) (bool, string) {
+	operationID, ok := span.Attributes["operation.id"].(string)
+	if !ok {
+		return false, "attribute operation.id missing"
+	}
+	if operationID != spec.OperationID {
+		return false, fmt.Sprintf("operation.id %q does not equal %q", operationID, spec.OperationID)
+	}
+	return true, fmt.Sprintf("operation.id equals %q", spec.OperationID)
+}
+
 // GetName implements the MatchStrategy interface
 func (matcher *OperationIDMatcher) GetName() string {
 	return "operation_id"
@@ -1695,14 +2435,8 @@ func (matcher *SpanNameMatcher) Match(spec models.ServiceSpec, traceData *models
 	}
 
 	// Legacy format matching
-	var matchingSpans []*models.Span
-
 	// Try to match by span name (use operation ID as span name)
-	for _, span := range traceData.Spans {
-		if span.Name == spec.OperationID {
-			matchingSpans = append(matchingSpans, span)
-		}
-	}
+	matchingSpans := append([]*models.Span(nil), traceData.Index().ByName(spec.OperationID)...)
 
 	return matchingSpans, nil
 }
@@ -1717,22 +2451,29 @@ func (matcher *SpanNameMatcher) matchYAMLFormat(spec models.ServiceSpec, traceDa
 		for _, operation := range endpoint.Operations {
 			operationName := fmt.Sprintf("%s %s", operation.Method, endpoint.Path)
 			
-			for _, span := range traceData.Spans {
-				if span.Name == operationName {
-					spanSet[span.SpanID] = span
-				}
+			for _, span := range traceData.Index().ByName(operationName) {
+				spanSet[span.SpanID] = span
 			}
 		}
 	}
 
-	// Convert map to slice
+	// Convert map to slice, in trace order so results do not depend on map iteration
 	for _, span := range spanSet {
 		matchingSpans = append(matchingSpans, span)
 	}
+	sortSpansByStart(matchingSpans)
 
 	return matchingSpans, nil
 }
 
+// Explain implements the ExplainingMatchStrategy interface
+func (matcher *SpanNameMatcher) Explain(spec models.ServiceSpec, span *models.Span) (bool, string) {
+	if span.Name != spec.OperationID {
+		return false, fmt.Sprintf("span name %q does not equal %q", span.Name, spec.OperationID)
+	}
+	return true, fmt.Sprintf("span name equals %q", spec.OperationID)
+}
+
 // GetName implements the MatchStrategy interface
 func (matcher *SpanNameMatcher) GetName() string {
 	return "span_name"
@@ -1753,15 +2494,7 @@ func (matcher *AttributeMatcher) Match(spec models.ServiceSpec, traceData *model
 	}
 
 	// Legacy format matching
-	var matchingSpans []*models.Span
-
-	for _, span := range traceData.Spans {
-		if value, ok := span.Attributes[matcher.attributeKey].(string); ok {
-			if value == spec.OperationID {
-				matchingSpans = append(matchingSpans, span)
-			}
-		}
-	}
+	matchingSpans := append([]*models.Span(nil), traceData.Index().WithAttributeValue(matcher.attributeKey, spec.OperationID)...)
 
 	return matchingSpans, nil
 }
@@ -1776,24 +2509,33 @@ func (matcher *AttributeMatcher) matchYAMLFormat(spec models.ServiceSpec, traceD
 		for _, operation := range endpoint.Operations {
 			operationName := fmt.Sprintf("%s %s", operation.Method, endpoint.Path)
 			
-			for _, span := range traceData.Spans {
-				if value, ok := span.Attributes[matcher.attributeKey].(string); ok {
-					if value == operationName {
-						spanSet[span.SpanID] = span
-					}
-				}
+			for _, span := range traceData.Index().WithAttributeValue(matcher.attributeKey, operationName) {
+				spanSet[span.SpanID] = span
 			}
 		}
 	}
 
-	// Convert map to slice
+	// Convert map to slice, in trace order so results do not depend on map iteration
 	for _, span := range spanSet {
 		matchingSpans = append(matchingSpans, span)
 	}
+	sortSpansByStart(matchingSpans)
 
 	return matchingSpans, nil
 }
 
+// Explain implements the ExplainingMatchStrategy interface
+func (matcher *AttributeMatcher) Explain(spec models.ServiceSpec, span *models.Span) (bool, string) {
+	value, ok := span.Attributes[matcher.attributeKey].(string)
+	if !ok {
+		return false, fmt.Sprintf("attribute %s missing", matcher.attributeKey)
+	}
+	if value != spec.OperationID {
+		return false, fmt.Sprintf("%s %q does not equal %q", matcher.attributeKey, value, spec.OperationID)
+	}
+	return true, fmt.Sprintf("%s equals %q", matcher.attributeKey, spec.OperationID)
+}
+
 // GetName implements the MatchStrategy interface
 func (matcher *AttributeMatcher) GetName() string {
 	return fmt.Sprintf("attribute_%s", matcher.attributeKey)
@@ -1925,25 +2667,42 @@ func (engine *DefaultAlignmentEngine) getMemoryUsageMB() float64 {
 
 // Match implements the MatchStrategy interface for YAML format specs
 func (matcher *EndpointMatcher) Match(spec models.ServiceSpec, traceData *models.TraceData) ([]*models.Span, error) {
+	matchingSpans, _, err := matcher.MatchWithOverlaps(spec, traceData)
+	return matchingSpans, err
+}
+
+// MatchWithOverlaps is Match that also returns how many of the spans matched several operations
+// of the spec, e.g. /users/{id} and /users/me; each span is returned once
+func (matcher *EndpointMatcher) MatchWithOverlaps(spec models.ServiceSpec, traceData *models.TraceData) ([]*models.Span, int, error) {
 	// Only handle YAML format specs
 	if !spec.IsYAMLFormat() {
-		return []*models.Span{}, nil
+		return []*models.Span{}, 0, nil
 	}
 
 	var matchingSpans []*models.Span
+	matches := make(map[string]int)
 
 	// Match spans for each endpoint and operation
 	for _, endpoint := range spec.Spec.Endpoints {
 		for _, operation := range endpoint.Operations {
-			for _, span := range traceData.Spans {
+			for _, span := range traceData.Index().OperationCandidates(operation.Method, endpoint.Path) {
 				if matcher.spanMatchesEndpointOperation(span, endpoint, operation) {
-					matchingSpans = append(matchingSpans, span)
+					if matches[span.SpanID] == 0 {
+						matchingSpans = append(matchingSpans, span)
+					}
+					matches[span.SpanID]++
 				}
 			}
 		}
 	}
 
-	return matchingSpans, nil
+	overlaps := 0
+	for _, count := range matches {
+		if count > 1 {
+			overlaps++
+		}
+	}
+	return matchingSpans, overlaps, nil
 }
 
 // spanMatchesEndpointOperation checks if a span matches a specific endpoint operation
@@ -1968,7 +2727,7 @@ func (matcher *EndpointMatcher) spanMatchesEndpointOperation(
 
 	// Also check http.route attribute
 	if route, ok := span.Attributes["http.route"].(string); ok {
-		if matcher.pathMatches(route, endpoint.Path) {
+		if matcher.pathMatches(models.NormalizeRoute(route), endpoint.Path) {
 			return true
 		}
 	}
@@ -2098,18 +2857,250 @@ func (engine *DefaultAlignmentEngine) shouldUseRangeAggregation(statusCodes []in
 	return false
 }
 
-// updateOperationStatus updates the operation status based on validation results
-func (engine *DefaultAlignmentEngine) updateOperationStatus(operationResult *models.OperationResult) {
+// updateOperationStatus updates the operation status based on validation results. An operation
+// whose failing spans stay within the configured flake rate is FLAKY rather than FAILED, and one
+// whose failed checks are all below error severity passes, with a warning for warning severity.
+func (engine *DefaultAlignmentEngine) updateOperationStatus(
+	operationKey string,
+	result *models.AlignmentResult,
+	operationResult *models.OperationResult,
+) {
 	if operationResult.AssertionsTotal == 0 {
 		operationResult.Status = models.StatusSkipped
 		return
 	}
 
-	if operationResult.AssertionsFailed > 0 {
-		operationResult.Status = models.StatusFailed
-	} else {
+	if operationResult.AssertionsFailed == 0 {
+		operationResult.Status = models.StatusSuccess
+		return
+	}
+
+	failingSpans, spanless := failingSamples(operationResult.Details)
+	if failingSpans == 0 && !spanless {
+		// Only checks below error severity failed
 		operationResult.Status = models.StatusSuccess
+		if warnings := countFailed(operationResult.Details, models.SeverityWarning); warnings > 0 {
+			message := fmt.Sprintf("Operation %s: %d check(s) of warning severity failed", operationKey, warnings)
+			operationResult.Warnings = append(operationResult.Warnings, message)
+			result.Warnings = append(result.Warnings, message)
+		}
+		return
+	}
+	if operationResult.SampleCount > 0 {
+		operationResult.FailureRate = float64(failingSpans) / float64(operationResult.SampleCount)
+	}
+	// Failures not tied to a span, e.g. traffic after a sunset date, are never flaky
+	if engine.config.MaxFlakeRate <= 0 || spanless || operationResult.FailureRate > engine.config.MaxFlakeRate {
+		operationResult.Status = models.StatusFailed
+		return
+	}
+
+	operationResult.Status = models.StatusFlaky
+	message := fmt.Sprintf("Operation %s is flaky: %d of %d span(s) failed (%.1f%%, tolerated %.1f%%)",
+		operationKey, failingSpans, operationResult.SampleCount,
+		operationResult.FailureRate*100, engine.config.MaxFlakeRate*100)
+	operationResult.Warnings = append(operationResult.Warnings, message)
+	result.Warnings = append(result.Warnings, message)
+}
+
+// failingSamples counts the spans with at least one failed detail of error severity and reports
+// whether such a detail is not tied to any span
+func failingSamples(details []models.ValidationDetail) (int, bool) {
+	failing := make(map[string]bool)
+	spanless := false
+	for _, detail := range details {
+		if !detail.IsBlocking() {
+			continue
+		}
+		if detail.SpanContext == nil {
+			spanless = true
+			continue
+		}
+		failing[detail.SpanContext.SpanID] = true
+	}
+	return len(failing), spanless
+}
+
+// countFailed counts the failed details of a severity
+func countFailed(details []models.ValidationDetail, severity models.Severity) int {
+	failed := 0
+	for _, detail := range details {
+		if !detail.IsPassed() && detail.Severity == severity {
+			failed++
+		}
+	}
+	return failed
+}
+
+// skipMissingSpans reports whether specs without matching spans are skipped; strict mode forces this off
+func (engine *DefaultAlignmentEngine) skipMissingSpans() bool {
+	return engine.config.SkipMissingSpans && !engine.config.StrictMode
+}
+
+// UncoveredTrafficOperationID identifies the strict mode result listing spans not covered by any spec
+const UncoveredTrafficOperationID = "uncovered-traffic"
+
+// buildUncoveredTrafficResult reports request spans that matched no spec operation, or nil if all are covered
+func (engine *DefaultAlignmentEngine) buildUncoveredTrafficResult(
+	report *models.AlignmentReport,
+	traceData *models.TraceData,
+) *models.AlignmentResult {
+	uncovered := collectUnmatchedSpans(report, traceData, isTrafficSpan)
+	if len(uncovered) == 0 {
+		return nil
+	}
+
+	startTime := time.Now()
+	result := models.NewAlignmentResult(UncoveredTrafficOperationID)
+	result.StartTime = startTime.UnixNano()
+	for _, span := range uncovered {
+		detail := models.NewValidationDetail(
+			"uncovered_span", "span_match", "covered", "uncovered",
+			fmt.Sprintf("Span %s (%s) is not covered by any spec operation", span.SpanID, describeTrafficSpan(span)))
+		detail.SpanContext = span
+		detail.Suggestions = []string{
+			"Add an operation for this endpoint to the contract",
+			"Disable strict mode if this traffic is intentionally undocumented",
+		}
+		result.AddValidationDetail(*detail)
+	}
+
+	endTime := time.Now()
+	result.EndTime = endTime.UnixNano()
+	result.ExecutionTime = endTime.Sub(startTime).Nanoseconds()
+	return result
+}
+
+// collectUnmatchedSpans returns spans not matched by any result, ordered by start time.
+// If filter is non-nil only spans it accepts are returned.
+func collectUnmatchedSpans(
+	report *models.AlignmentReport,
+	traceData *models.TraceData,
+	filter func(span *models.Span) bool,
+) []*models.Span {
+	covered := make(map[string]bool)
+	for _, result := range report.Results {
+		for _, spanID := range result.MatchedSpans {
+			covered[spanID] = true
+		}
+	}
+
+	var unmatched []*models.Span
+	for spanID, span := range traceData.Spans {
+		if covered[spanID] {
+			continue
+		}
+		if filter == nil || filter(span) {
+			unmatched = append(unmatched, span)
+		}
+	}
+
+	// Keep the report stable regardless of map iteration order
+	sort.Slice(unmatched, func(i, j int) bool {
+		if unmatched[i].StartTime != unmatched[j].StartTime {
+			return unmatched[i].StartTime < unmatched[j].StartTime
+		}
+		return unmatched[i].SpanID < unmatched[j].SpanID
+	})
+
+	return unmatched
+}
+
+// maxUnmatchedSampleSpans limits the span IDs recorded per unmatched span group
+const maxUnmatchedSampleSpans = 5
+
+// buildUnmatchedSpanReport groups unmatched spans by name, method, route and status
+func buildUnmatchedSpanReport(spans []*models.Span) *models.UnmatchedSpanReport {
+	unmatchedReport := &models.UnmatchedSpanReport{
+		TotalSpans: len(spans),
+		Groups:     []models.UnmatchedSpanGroup{},
+	}
+
+	groupIndex := make(map[string]int)
+	for _, span := range spans {
+		group := models.UnmatchedSpanGroup{
+			Name:   span.Name,
+			Method: spanMethod(span),
+			Route:  spanRoute(span),
+			Status: spanStatus(span),
+		}
+		key := strings.Join([]string{group.Name, group.Method, group.Route, group.Status}, "\x00")
+
+		index, exists := groupIndex[key]
+		if !exists {
+			index = len(unmatchedReport.Groups)
+			groupIndex[key] = index
+			unmatchedReport.Groups = append(unmatchedReport.Groups, group)
+		}
+
+		existing := &unmatchedReport.Groups[index]
+		existing.Count++
+		if len(existing.SampleSpanIDs) < maxUnmatchedSampleSpans {
+			existing.SampleSpanIDs = append(existing.SampleSpanIDs, span.SpanID)
+		}
+	}
+
+	// Most frequent groups first, ties broken by name and route
+	sort.SliceStable(unmatchedReport.Groups, func(i, j int) bool {
+		a, b := unmatchedReport.Groups[i], unmatchedReport.Groups[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Route < b.Route
+	})
+
+	return unmatchedReport
+}
+
+// spanMethod returns the HTTP method of a span, if any
+func spanMethod(span *models.Span) string {
+	method, _ := span.Attributes["http.method"].(string)
+	if method == "" {
+		method, _ = span.Attributes["http.request.method"].(string)
+	}
+	return method
+}
+
+// spanRoute returns the route or request path (without query string) of a span, if any
+func spanRoute(span *models.Span) string {
+	route, _ := span.Attributes["http.route"].(string)
+	if route == "" {
+		route, _ = span.Attributes["http.target"].(string)
+	}
+	return models.NormalizeRoute(route)
+}
+
+// spanStatus returns the HTTP status code of a span, falling back to the span status code
+func spanStatus(span *models.Span) string {
+	for _, key := range []string{"http.status_code", "http.response.status_code"} {
+		if value, exists := span.Attributes[key]; exists {
+			return fmt.Sprintf("%v", value)
+		}
+	}
+	return span.Status.Code
+}
+
+// isTrafficSpan reports whether a span represents an inbound operation that a spec is expected to cover
+func isTrafficSpan(span *models.Span) bool {
+	for _, key := range []string{"http.method", "http.request.method", "operation.id"} {
+		if _, exists := span.Attributes[key]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// describeTrafficSpan returns a short "METHOD /path" style description of a span
+func describeTrafficSpan(span *models.Span) string {
+	method := spanMethod(span)
+	target := spanRoute(span)
+	if method != "" && target != "" {
+		return method + " " + target
 	}
+	return span.Name
 }
 
 // ValidateEngineConfig validates the engine configuration
@@ -2122,5 +3113,29 @@ func ValidateEngineConfig(config *EngineConfig) error {
 		return fmt.Errorf("Timeout must be positive, got %s", config.Timeout)
 	}
 
+	if config.MaxFlakeRate < 0 || config.MaxFlakeRate >= 1 {
+		return fmt.Errorf("MaxFlakeRate must be at least 0 and below 1, got %g", config.MaxFlakeRate)
+	}
+
+	if config.ClockSkew < 0 {
+		return fmt.Errorf("ClockSkew must not be negative, got %s", config.ClockSkew)
+	}
+
+	if config.TraceSampleRate < 0 || config.TraceSampleRate > 1 {
+		return fmt.Errorf("TraceSampleRate must be between 0 and 1, got %g", config.TraceSampleRate)
+	}
+
+	if err := config.AttributeAliases.Validate(); err != nil {
+		return fmt.Errorf("AttributeAliases: %w", err)
+	}
+
+	if err := config.SpanStatus.Validate(); err != nil {
+		return fmt.Errorf("SpanStatus: %w", err)
+	}
+
+	if err := ValidateAmbiguityPolicy(config.AmbiguityPolicy); err != nil {
+		return fmt.Errorf("AmbiguityPolicy: %w", err)
+	}
+
 	return nil
 }
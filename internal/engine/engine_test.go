@@ -15,11 +15,19 @@
 package engine
 
 import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/flowspec/flowspec-cli/internal/ingestor"
+	"github.com/flowspec/flowspec-cli/internal/membudget"
 	"github.com/flowspec/flowspec-cli/internal/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // MockAssertionEvaluator for testing
@@ -53,7 +61,7 @@ func TestNewAlignmentEngine(t *testing.T) {
 
 	assert.NotNil(t, engine)
 	assert.NotNil(t, engine.config)
-	assert.Equal(t, 4, engine.config.MaxConcurrency)
+	assert.Equal(t, runtime.GOMAXPROCS(0), engine.config.MaxConcurrency)
 	assert.Equal(t, 30*time.Second, engine.config.Timeout)
 	assert.True(t, engine.config.EnableMetrics)
 	assert.False(t, engine.config.StrictMode)
@@ -84,7 +92,8 @@ func TestDefaultEngineConfig(t *testing.T) {
 	config := DefaultEngineConfig()
 
 	assert.NotNil(t, config)
-	assert.Equal(t, 4, config.MaxConcurrency)
+	assert.Equal(t, runtime.GOMAXPROCS(0), config.MaxConcurrency)
+	assert.Equal(t, runtime.GOMAXPROCS(0), config.TraceConcurrency)
 	assert.Equal(t, 30*time.Second, config.Timeout)
 	assert.True(t, config.EnableMetrics)
 	assert.False(t, config.StrictMode)
@@ -121,6 +130,16 @@ func TestValidateEngineConfig(t *testing.T) {
 			expectError: true,
 			errorMsg:    "Timeout must be positive",
 		},
+		{
+			name: "Invalid MaxFlakeRate",
+			config: &EngineConfig{
+				MaxConcurrency: 4,
+				Timeout:        30 * time.Second,
+				MaxFlakeRate:   1,
+			},
+			expectError: true,
+			errorMsg:    "MaxFlakeRate must be at least 0 and below 1",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -671,3 +690,1151 @@ func TestNewValidationContext(t *testing.T) {
 	assert.True(t, exists)
 	assert.Equal(t, "test_value", value)
 }
+
+func newSlowEngine(timeout time.Duration, delay time.Duration) *DefaultAlignmentEngine {
+	config := DefaultEngineConfig()
+	config.Timeout = timeout
+	engine := NewAlignmentEngineWithConfig(config)
+	engine.SetEvaluator(&MockAssertionEvaluator{
+		evaluateFunc: func(assertion map[string]interface{}, context *EvaluationContext) (*AssertionResult, error) {
+			time.Sleep(delay)
+			return &AssertionResult{Passed: true, Expected: true, Actual: true}, nil
+		},
+	})
+	return engine
+}
+
+func newTimeoutTestData() (models.ServiceSpec, *models.TraceData) {
+	spec := models.ServiceSpec{
+		OperationID:   "slowOp",
+		Preconditions: map[string]interface{}{"test": true},
+	}
+
+	span := &models.Span{
+		SpanID:     "span1",
+		TraceID:    "trace1",
+		Name:       "slow-operation",
+		Attributes: map[string]interface{}{"operation.id": "slowOp"},
+	}
+
+	traceData := &models.TraceData{
+		TraceID: "trace1",
+		Spans:   map[string]*models.Span{"span1": span},
+	}
+
+	return spec, traceData
+}
+
+func TestAlignmentEngine_AlignSingleSpec_Timeout(t *testing.T) {
+	engine := newSlowEngine(20*time.Millisecond, 500*time.Millisecond)
+	spec, traceData := newTimeoutTestData()
+
+	start := time.Now()
+	result, err := engine.AlignSingleSpec(spec, traceData)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, models.StatusTimeout, result.Status)
+	assert.Equal(t, "slowOp", result.SpecOperationID)
+	assert.Contains(t, result.ErrorMessage, "timeout")
+	assert.Less(t, time.Since(start), 400*time.Millisecond, "timeout should abort the spec early")
+}
+
+func TestAlignmentEngine_AlignSingleSpec_WithinTimeout(t *testing.T) {
+	engine := newSlowEngine(time.Second, time.Millisecond)
+	spec, traceData := newTimeoutTestData()
+
+	result, err := engine.AlignSingleSpec(spec, traceData)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusSuccess, result.Status)
+}
+
+func TestAlignmentEngine_AlignSingleSpecContext_Cancelled(t *testing.T) {
+	engine := newSlowEngine(time.Second, 500*time.Millisecond)
+	spec, traceData := newTimeoutTestData()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	result, err := engine.AlignSingleSpecContext(ctx, spec, traceData)
+
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, result)
+}
+
+func TestAlignmentEngine_AlignSpecsWithTrace_TimeoutCountsAsFailure(t *testing.T) {
+	engine := newSlowEngine(20*time.Millisecond, 500*time.Millisecond)
+	spec, traceData := newTimeoutTestData()
+
+	report, err := engine.AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+
+	assert.NoError(t, err)
+	assert.Len(t, report.Results, 1)
+	assert.Equal(t, models.StatusTimeout, report.Results[0].Status)
+	assert.Equal(t, 1, report.Summary.Failed)
+	assert.Equal(t, 1, report.Summary.TimedOut)
+	assert.True(t, report.HasFailures())
+}
+
+func TestAlignmentEngine_AlignSpecsWithTraceContext_Cancelled(t *testing.T) {
+	engine := newSlowEngine(time.Second, time.Millisecond)
+	spec, traceData := newTimeoutTestData()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report, err := engine.AlignSpecsWithTraceContext(ctx, []models.ServiceSpec{spec}, traceData)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotNil(t, report)
+	assert.Empty(t, report.Results)
+	assert.True(t, report.Incomplete)
+	assert.Equal(t, "context canceled", report.IncompleteReason)
+}
+
+func TestAlignmentEngine_AlignSpecsWithTraceContext_MemoryBudget(t *testing.T) {
+	config := DefaultEngineConfig()
+	config.MemoryBudget = membudget.New(1)
+	engine := NewAlignmentEngineWithConfig(config)
+	evaluated := 0
+	engine.SetEvaluator(&MockAssertionEvaluator{
+		evaluateFunc: func(assertion map[string]interface{}, context *EvaluationContext) (*AssertionResult, error) {
+			evaluated++
+			return &AssertionResult{Passed: true, Expected: true, Actual: true}, nil
+		},
+	})
+	spec, traceData := newTimeoutTestData()
+
+	// Keep the heap over the 1 MB budget however much the collector reclaims
+	retained := make([]byte, 4<<20)
+	report, err := engine.AlignSpecsWithTraceContext(context.Background(), []models.ServiceSpec{spec}, traceData)
+	runtime.KeepAlive(retained)
+
+	assert.ErrorIs(t, err, membudget.ErrExceeded)
+	assert.Zero(t, evaluated, "no spec is aligned once the budget is exceeded")
+	require.NotNil(t, report)
+	assert.Empty(t, report.Results)
+	assert.True(t, report.Incomplete)
+	assert.Contains(t, report.IncompleteReason, "while aligning slowOp")
+}
+
+func newStrictModeTestData() (models.ServiceSpec, *models.TraceData) {
+	spec := models.ServiceSpec{
+		APIVersion: "flowspec/v1alpha1",
+		Kind:       "ServiceSpec",
+		Metadata:   &models.ServiceSpecMetadata{Name: "user-service", Version: "v1"},
+		Spec: &models.ServiceSpecDefinition{
+			Endpoints: []models.EndpointSpec{
+				{
+					Path: "/users/{id}",
+					Operations: []models.OperationSpec{
+						{Method: "GET", Responses: models.ResponseSpec{StatusRanges: []string{"2xx"}}},
+					},
+				},
+				{
+					Path: "/health",
+					Operations: []models.OperationSpec{
+						{Method: "GET", Responses: models.ResponseSpec{StatusCodes: []int{200}}},
+					},
+				},
+			},
+		},
+	}
+
+	traceData := &models.TraceData{
+		TraceID: "trace1",
+		Spans: map[string]*models.Span{
+			"covered": {
+				SpanID: "covered", TraceID: "trace1", Name: "GET /users/{id}",
+				Attributes: map[string]interface{}{"http.method": "GET", "http.target": "/users/42", "http.status_code": 200},
+			},
+			"uncovered": {
+				SpanID: "uncovered", TraceID: "trace1", Name: "POST /orders",
+				Attributes: map[string]interface{}{"http.method": "POST", "http.target": "/orders", "http.status_code": 201},
+			},
+			"internal": {
+				SpanID: "internal", TraceID: "trace1", Name: "db.query",
+				Attributes: map[string]interface{}{"db.system": "postgresql"},
+			},
+		},
+	}
+
+	return spec, traceData
+}
+
+func TestAlignmentEngine_StrictMode_UncoveredTraffic(t *testing.T) {
+	config := DefaultEngineConfig()
+	config.StrictMode = true
+	engine := NewAlignmentEngineWithConfig(config)
+	spec, traceData := newStrictModeTestData()
+
+	report, err := engine.AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	assert.NoError(t, err)
+
+	var uncovered *models.AlignmentResult
+	for i := range report.Results {
+		if report.Results[i].SpecOperationID == UncoveredTrafficOperationID {
+			uncovered = &report.Results[i]
+		}
+	}
+	if assert.NotNil(t, uncovered, "strict mode should report uncovered traffic") {
+		assert.Equal(t, models.StatusFailed, uncovered.Status)
+		assert.Len(t, uncovered.Details, 1, "only request spans should count as traffic")
+		assert.Equal(t, "uncovered_span", uncovered.Details[0].Type)
+		assert.Equal(t, "uncovered", uncovered.Details[0].SpanContext.SpanID)
+		assert.Contains(t, uncovered.Details[0].Message, "POST /orders")
+	}
+
+	// The missing /health operation must fail even though SkipMissingSpans is set
+	assert.True(t, config.SkipMissingSpans)
+	operation := report.Results[0].OperationResults["GET /health"]
+	if assert.NotNil(t, operation) {
+		assert.Equal(t, models.StatusFailed, operation.Status)
+	}
+	assert.True(t, report.HasFailures())
+}
+
+func TestAlignmentEngine_NonStrictMode_IgnoresUncoveredTraffic(t *testing.T) {
+	engine := NewAlignmentEngine()
+	spec, traceData := newStrictModeTestData()
+
+	report, err := engine.AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	assert.NoError(t, err)
+
+	assert.Len(t, report.Results, 1)
+	operation := report.Results[0].OperationResults["GET /health"]
+	if assert.NotNil(t, operation) {
+		assert.Equal(t, models.StatusSkipped, operation.Status)
+	}
+}
+
+func TestAlignmentEngine_OnResult(t *testing.T) {
+	var streamed []string
+	config := DefaultEngineConfig()
+	config.StrictMode = true
+	config.OnResult = func(result models.AlignmentResult) {
+		streamed = append(streamed, result.SpecOperationID)
+	}
+	spec, traceData := newStrictModeTestData()
+
+	report, err := NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	require.NoError(t, err)
+
+	require.Len(t, streamed, len(report.Results), "every result is streamed, including uncovered traffic")
+	for i, result := range report.Results {
+		assert.Equal(t, result.SpecOperationID, streamed[i], "results are streamed in report order")
+	}
+}
+
+func TestAlignmentEngine_ReportUnmatched(t *testing.T) {
+	config := DefaultEngineConfig()
+	config.ReportUnmatched = true
+	engine := NewAlignmentEngineWithConfig(config)
+	spec, traceData := newStrictModeTestData()
+	traceData.Spans["uncovered2"] = &models.Span{
+		SpanID: "uncovered2", TraceID: "trace1", Name: "POST /orders", StartTime: 10,
+		Attributes: map[string]interface{}{"http.method": "POST", "http.target": "/orders?retry=1", "http.status_code": 201},
+	}
+
+	report, err := engine.AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	assert.NoError(t, err)
+
+	if assert.NotNil(t, report.Unmatched) {
+		assert.Equal(t, 3, report.Unmatched.TotalSpans)
+		assert.Len(t, report.Unmatched.Groups, 2)
+
+		orders := report.Unmatched.Groups[0]
+		assert.Equal(t, 2, orders.Count)
+		assert.Equal(t, "POST", orders.Method)
+		assert.Equal(t, "/orders", orders.Route, "query string should not split groups")
+		assert.Equal(t, "201", orders.Status)
+		assert.ElementsMatch(t, []string{"uncovered", "uncovered2"}, orders.SampleSpanIDs)
+
+		assert.Equal(t, "db.query", report.Unmatched.Groups[1].Name)
+		assert.Equal(t, 1, report.Unmatched.Groups[1].Count)
+	}
+
+	// Unmatched spans are informational and do not add failing results
+	assert.Len(t, report.Results, 1)
+}
+
+func TestAlignmentEngine_ReportUnmatched_Disabled(t *testing.T) {
+	engine := NewAlignmentEngine()
+	spec, traceData := newStrictModeTestData()
+
+	report, err := engine.AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	assert.NoError(t, err)
+	assert.Nil(t, report.Unmatched)
+}
+
+func TestAlignmentEngine_DeprecatedOperations(t *testing.T) {
+	now := func() time.Time { return time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC) }
+
+	testCases := []struct {
+		name            string
+		sunset          string
+		enforceSunset   bool
+		expectedStatus  models.AlignmentStatus
+		expectedWarning string
+	}{
+		{
+			name:            "deprecated without sunset",
+			expectedStatus:  models.StatusSuccess,
+			expectedWarning: "Deprecated operation GET /users/{id} still received 1 request(s)",
+		},
+		{
+			name:            "sunset passed without enforcement",
+			sunset:          "2025-06-30",
+			expectedStatus:  models.StatusSuccess,
+			expectedWarning: "still received 1 request(s) (sunset 2025-06-30)",
+		},
+		{
+			name:            "sunset not reached",
+			sunset:          "2025-12-31",
+			enforceSunset:   true,
+			expectedStatus:  models.StatusSuccess,
+			expectedWarning: "(sunset 2025-12-31)",
+		},
+		{
+			name:           "sunset passed with enforcement",
+			sunset:         "2025-07-01T00:00:00Z",
+			enforceSunset:  true,
+			expectedStatus: models.StatusFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec, traceData := newStrictModeTestData()
+			operation := &spec.Spec.Endpoints[0].Operations[0]
+			operation.Deprecated = true
+			operation.Sunset = tc.sunset
+
+			config := DefaultEngineConfig()
+			config.EnforceSunset = tc.enforceSunset
+			config.Now = now
+			report, err := NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+			require.NoError(t, err)
+
+			result := report.Results[0]
+			operationResult := result.OperationResults["GET /users/{id}"]
+			require.NotNil(t, operationResult)
+			assert.Equal(t, tc.expectedStatus, operationResult.Status)
+
+			if tc.expectedWarning != "" {
+				require.Len(t, result.Warnings, 1)
+				assert.Contains(t, result.Warnings[0], tc.expectedWarning)
+				assert.Equal(t, result.Warnings, operationResult.Warnings)
+				return
+			}
+
+			assert.Empty(t, result.Warnings)
+			assert.Equal(t, models.StatusFailed, result.Status)
+			last := operationResult.Details[len(operationResult.Details)-1]
+			assert.Equal(t, "deprecation", last.Type)
+			assert.Contains(t, last.Message, "after its sunset date 2025-07-01T00:00:00Z")
+		})
+	}
+}
+
+func TestAlignmentEngine_YAMLResultRollsUpOperations(t *testing.T) {
+	testCases := []struct {
+		name             string
+		skipMissingSpans bool
+		expectedStatus   models.AlignmentStatus
+		expectedHealth   models.AlignmentStatus
+	}{
+		{"missing operation skipped", true, models.StatusSuccess, models.StatusSkipped},
+		{"missing operation fails", false, models.StatusFailed, models.StatusFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec, traceData := newStrictModeTestData()
+			config := DefaultEngineConfig()
+			config.SkipMissingSpans = tc.skipMissingSpans
+			report, err := NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+			require.NoError(t, err)
+
+			result := report.Results[0]
+			users := result.OperationResults["GET /users/{id}"]
+			require.NotNil(t, users)
+			assert.Equal(t, models.StatusSuccess, users.Status)
+			assert.Equal(t, tc.expectedHealth, result.OperationResults["GET /health"].Status)
+
+			// A status code within the expected range passes even though it differs from the expected value
+			require.Len(t, users.Details, 1)
+			assert.True(t, users.Details[0].IsPassed())
+
+			assert.Equal(t, tc.expectedStatus, result.Status)
+			assert.Equal(t, 1, result.AssertionsTotal)
+			assert.Equal(t, 1, result.AssertionsPassed)
+			assert.Equal(t, 0, result.AssertionsFailed)
+			assert.Equal(t, 1, report.Summary.TotalAssertions)
+			assert.Equal(t, 0, report.Summary.FailedAssertions)
+			if tc.expectedStatus == models.StatusFailed {
+				assert.Equal(t, 1, report.Summary.Failed)
+			} else {
+				assert.Equal(t, 1, report.Summary.Success)
+			}
+		})
+	}
+}
+
+func TestAlignmentEngine_FlakyOperations(t *testing.T) {
+	newTrace := func(failing int) *models.TraceData {
+		traceData := &models.TraceData{TraceID: "trace1", Spans: make(map[string]*models.Span)}
+		for i := 0; i < 20; i++ {
+			statusCode := 200
+			if i < failing {
+				statusCode = 500
+			}
+			id := fmt.Sprintf("span-%02d", i)
+			traceData.Spans[id] = &models.Span{
+				SpanID: id, TraceID: "trace1", Name: "GET /users/{id}", StartTime: int64(i),
+				Attributes: map[string]interface{}{"http.method": "GET", "http.target": fmt.Sprintf("/users/%d", i), "http.status_code": statusCode},
+			}
+		}
+		return traceData
+	}
+
+	testCases := []struct {
+		name           string
+		failing        int
+		maxFlakeRate   float64
+		expectedStatus models.AlignmentStatus
+		expectedResult models.AlignmentStatus
+	}{
+		{"flake detection disabled", 1, 0, models.StatusFailed, models.StatusFailed},
+		{"within flake rate", 1, 0.1, models.StatusFlaky, models.StatusSuccess},
+		{"at flake rate", 2, 0.1, models.StatusFlaky, models.StatusSuccess},
+		{"above flake rate", 3, 0.1, models.StatusFailed, models.StatusFailed},
+		{"no failures", 0, 0.1, models.StatusSuccess, models.StatusSuccess},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec, _ := newStrictModeTestData()
+			spec.Spec.Endpoints = spec.Spec.Endpoints[:1]
+
+			config := DefaultEngineConfig()
+			config.MaxFlakeRate = tc.maxFlakeRate
+			report, err := NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace([]models.ServiceSpec{spec}, newTrace(tc.failing))
+			require.NoError(t, err)
+
+			result := report.Results[0]
+			operation := result.OperationResults["GET /users/{id}"]
+			require.NotNil(t, operation)
+			assert.Equal(t, tc.expectedStatus, operation.Status)
+			assert.Equal(t, tc.expectedResult, result.Status)
+			assert.InDelta(t, float64(tc.failing)/20, operation.FailureRate, 1e-9)
+
+			if tc.expectedStatus != models.StatusFlaky {
+				assert.Empty(t, operation.Warnings)
+				return
+			}
+			require.Len(t, operation.Warnings, 1)
+			assert.Contains(t, operation.Warnings[0], fmt.Sprintf("%d of 20 span(s) failed", tc.failing))
+			assert.Equal(t, operation.Warnings, result.Warnings)
+			assert.Equal(t, 1, report.Summary.OperationSummary.FlakyOperations)
+			assert.False(t, report.HasFailures())
+		})
+	}
+}
+
+func TestAlignmentEngine_TimeWindow(t *testing.T) {
+	spec, traceData := newStrictModeTestData()
+	spec.Spec.Endpoints = spec.Spec.Endpoints[:1]
+	base := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	traceData.Spans["covered"].StartTime = base.UnixNano()
+	traceData.Spans["before"] = &models.Span{
+		SpanID: "before", TraceID: "trace1", Name: "GET /users/{id}", StartTime: base.Add(-time.Hour).UnixNano(),
+		Attributes: map[string]interface{}{"http.method": "GET", "http.target": "/users/7", "http.status_code": 500},
+	}
+
+	// Without a window the failing span from before the deployment is verified too
+	report, err := NewAlignmentEngine().AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, report.Results[0].Status)
+	assert.Nil(t, report.TimeWindow)
+
+	window, err := models.ParseTimeWindow("2025-08-01T11:30:00Z", "")
+	require.NoError(t, err)
+	config := DefaultEngineConfig()
+	config.TimeWindow = window
+	report, err = NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusSuccess, report.Results[0].Status)
+	assert.Equal(t, []string{"covered"}, report.Results[0].OperationResults["GET /users/{id}"].MatchedSpans)
+	assert.Equal(t, window, report.TimeWindow)
+	assert.Len(t, traceData.Spans, 4, "the caller's trace is not modified")
+
+	window, err = models.ParseTimeWindow("2025-08-01T09:00:00Z", "2025-08-01T10:00:00Z")
+	require.NoError(t, err)
+	config.TimeWindow = window
+	_, err = NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	assert.ErrorContains(t, err, "no spans in the trace started since 2025-08-01T09:00:00Z until 2025-08-01T10:00:00Z")
+}
+
+func TestAlignmentEngine_Explain_YAMLOperations(t *testing.T) {
+	config := DefaultEngineConfig()
+	config.Explain = true
+	engine := NewAlignmentEngineWithConfig(config)
+	spec, traceData := newStrictModeTestData()
+
+	report, err := engine.AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	assert.NoError(t, err)
+
+	operation := report.Results[0].OperationResults["GET /users/{id}"]
+	if !assert.NotNil(t, operation) {
+		return
+	}
+	assert.Len(t, operation.Explanations, len(traceData.Spans), "every span should be explained")
+
+	byID := make(map[string]models.MatchExplanation)
+	for _, explanation := range operation.Explanations {
+		byID[explanation.SpanID] = explanation
+	}
+
+	covered := byID["covered"]
+	assert.True(t, covered.Accepted)
+	assert.Equal(t, "http_target", covered.AcceptedBy)
+
+	methodMismatch := byID["uncovered"]
+	assert.False(t, methodMismatch.Accepted)
+	if assert.NotEmpty(t, methodMismatch.Decisions) {
+		assert.Equal(t, "http_method", methodMismatch.Decisions[0].Matcher)
+		assert.Contains(t, methodMismatch.Decisions[0].Reason, "method mismatch: span has POST, operation expects GET")
+	}
+
+	missingAttributes := byID["internal"]
+	assert.False(t, missingAttributes.Accepted)
+	reasons := make([]string, 0)
+	for _, decision := range missingAttributes.Decisions {
+		reasons = append(reasons, decision.Reason)
+	}
+	assert.Contains(t, reasons, "attribute http.target missing")
+}
+
+func TestExplainPathMismatch(t *testing.T) {
+	assert.Equal(t, `http.target /users/42/orders has 3 path segments, /users/{id} has 2`,
+		explainPathMismatch("http.target", "/users/42/orders", "/users/{id}"))
+	assert.Equal(t, `http.route /accounts/42: path segment 1 is "accounts", expected "users"`,
+		explainPathMismatch("http.route", "/accounts/42", "/users/{id}"))
+}
+
+func TestAlignmentEngine_Explain_LegacySpec(t *testing.T) {
+	config := DefaultEngineConfig()
+	config.Explain = true
+	engine := NewAlignmentEngineWithConfig(config)
+	spec, traceData := newTimeoutTestData()
+	traceData.Spans["other"] = &models.Span{SpanID: "other", TraceID: "trace1", Name: "other-operation"}
+
+	result, err := engine.AlignSingleSpec(spec, traceData)
+	assert.NoError(t, err)
+	assert.Len(t, result.Explanations, 2)
+
+	for _, explanation := range result.Explanations {
+		switch explanation.SpanID {
+		case "span1":
+			assert.True(t, explanation.Accepted)
+			assert.Equal(t, "operation_id", explanation.AcceptedBy)
+		case "other":
+			assert.False(t, explanation.Accepted)
+			assert.Len(t, explanation.Decisions, 3)
+		}
+	}
+
+	// Explanations are not recorded unless requested
+	result, err = NewAlignmentEngine().AlignSingleSpec(spec, traceData)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Explanations)
+}
+
+func TestAlignmentEngine_PathParameterConstraints(t *testing.T) {
+	spec, _ := newStrictModeTestData()
+	spec.Spec.Endpoints[0].Operations[0].PathParams = map[string]models.PathParamSpec{
+		"id": {Type: models.PathParamInteger},
+	}
+	traceData := &models.TraceData{
+		TraceID: "trace1",
+		Spans: map[string]*models.Span{
+			"valid": {
+				SpanID: "valid", TraceID: "trace1", Name: "GET /users/{id}", StartTime: 1,
+				Attributes: map[string]interface{}{"http.method": "GET", "http.target": "/users/42", "http.status_code": 200},
+			},
+			"invalid": {
+				SpanID: "invalid", TraceID: "trace1", Name: "GET /users/{id}", StartTime: 2,
+				Attributes: map[string]interface{}{"http.method": "GET", "http.target": "/users/abc?verbose=1", "http.status_code": 200},
+			},
+		},
+	}
+
+	config := DefaultEngineConfig()
+	config.SkipMissingSpans = true
+	report, err := NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	require.NoError(t, err)
+
+	users := report.Results[0].OperationResults["GET /users/{id}"]
+	require.NotNil(t, users)
+	assert.Equal(t, models.StatusFailed, users.Status)
+
+	var pathDetails []models.ValidationDetail
+	for _, detail := range users.Details {
+		if detail.Type == "path_param" {
+			pathDetails = append(pathDetails, detail)
+		}
+	}
+	require.Len(t, pathDetails, 2)
+	assert.True(t, pathDetails[0].IsPassed())
+	assert.Equal(t, "42", pathDetails[0].Actual)
+	assert.False(t, pathDetails[1].IsPassed())
+	assert.Equal(t, "abc", pathDetails[1].Actual)
+	assert.Equal(t, "integer", pathDetails[1].Expected)
+	assert.Contains(t, pathDetails[1].Message, "not an integer")
+	assert.Equal(t, "expected integer", pathDetails[1].FailureReason)
+}
+
+func TestAlignmentEngine_RequestBodyChecks(t *testing.T) {
+	spec := models.ServiceSpec{
+		APIVersion: "flowspec/v1alpha1",
+		Kind:       "ServiceSpec",
+		Metadata:   &models.ServiceSpecMetadata{Name: "user-service", Version: "v1"},
+		Spec: &models.ServiceSpecDefinition{
+			Endpoints: []models.EndpointSpec{{
+				Path: "/users",
+				Operations: []models.OperationSpec{{
+					Method:    "POST",
+					Responses: models.ResponseSpec{StatusCodes: []int{201}},
+					Request: &models.RequestSpec{
+						ContentTypes: []string{"application/json"},
+						Schema: map[string]interface{}{
+							"type":       "object",
+							"required":   []interface{}{"name"},
+							"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}},
+						},
+					},
+				}},
+			}},
+		},
+	}
+	newSpan := func(id string, startTime int64, contentType, body string) *models.Span {
+		attributes := map[string]interface{}{"http.method": "POST", "http.target": "/users", "http.status_code": 201}
+		if contentType != "" {
+			attributes["http.request.header.content-type"] = contentType
+		}
+		if body != "" {
+			attributes["http.request.body"] = body
+		}
+		return &models.Span{SpanID: id, TraceID: "trace1", Name: "POST /users", StartTime: startTime, Attributes: attributes}
+	}
+	traceData := &models.TraceData{
+		TraceID: "trace1",
+		Spans: map[string]*models.Span{
+			"valid":      newSpan("valid", 1, "application/json; charset=utf-8", `{"name": "alice"}`),
+			"unrecorded": newSpan("unrecorded", 2, "", ""),
+			"form":       newSpan("form", 3, "application/x-www-form-urlencoded", "name=alice"),
+			"bad-schema": newSpan("bad-schema", 4, "application/json", `{"name": 42}`),
+			"bad-json":   newSpan("bad-json", 5, "application/json", `{"name":`),
+		},
+	}
+
+	report, err := NewAlignmentEngine().AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	require.NoError(t, err)
+	operation := report.Results[0].OperationResults["POST /users"]
+	require.NotNil(t, operation)
+	assert.Equal(t, models.StatusFailed, operation.Status)
+
+	outcomes := make(map[string][]bool)
+	var schemaFailures []string
+	for _, detail := range operation.Details {
+		if detail.Type != "request_content_type" && detail.Type != "request_schema" {
+			continue
+		}
+		key := detail.SpanContext.SpanID + " " + detail.Type
+		outcomes[key] = append(outcomes[key], detail.IsPassed())
+		if detail.Type == "request_schema" && !detail.IsPassed() {
+			schemaFailures = append(schemaFailures, detail.FailureReason)
+		}
+	}
+	assert.Equal(t, map[string][]bool{
+		"valid request_content_type":      {true},
+		"valid request_schema":            {true},
+		"form request_content_type":       {false},
+		"bad-schema request_content_type": {true},
+		"bad-schema request_schema":       {false},
+		"bad-json request_content_type":   {true},
+		"bad-json request_schema":         {false},
+	}, outcomes)
+	assert.ElementsMatch(t, []string{"/name: expected string, got number", "request body is not valid JSON"}, schemaFailures)
+}
+
+func TestAlignmentEngine_AttributeAliases(t *testing.T) {
+	spec, _ := newStrictModeTestData()
+	traceData := &models.TraceData{
+		TraceID: "trace1",
+		Spans: map[string]*models.Span{
+			"custom": {
+				SpanID: "custom", TraceID: "trace1", Name: "users.get",
+				Attributes: map[string]interface{}{"custom.verb": "GET", "custom.path": "/users/42", "custom.status": 200},
+			},
+		},
+	}
+	aliases := models.AttributeAliases{
+		"method":     {"http.method", "custom.verb"},
+		"target":     {"custom.path"},
+		"statusCode": {"custom.status"},
+	}
+
+	config := DefaultEngineConfig()
+	config.SkipMissingSpans = true
+	report, err := NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusSkipped, report.Results[0].OperationResults["GET /users/{id}"].Status, "without aliases the span is not recognized")
+
+	config.AttributeAliases = aliases
+	require.NoError(t, ValidateEngineConfig(config))
+	report, err = NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	require.NoError(t, err)
+	users := report.Results[0].OperationResults["GET /users/{id}"]
+	require.NotNil(t, users)
+	assert.Equal(t, models.StatusSuccess, users.Status)
+	assert.Equal(t, []string{"custom"}, users.MatchedSpans)
+
+	result, err := NewAlignmentEngineWithConfig(config).AlignSingleSpec(spec, traceData)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusSuccess, result.OperationResults["GET /users/{id}"].Status, "single specs resolve aliases too")
+
+	config.AttributeAliases = models.AttributeAliases{"verb": {"custom.verb"}}
+	assert.ErrorContains(t, ValidateEngineConfig(config), "AttributeAliases: unknown field 'verb'")
+}
+
+func TestAlignmentEngine_OperationSeverity(t *testing.T) {
+	testCases := []struct {
+		name             string
+		severity         models.Severity
+		expectedStatus   models.AlignmentStatus
+		expectedWarnings int
+	}{
+		{name: "error fails the operation", severity: models.SeverityError, expectedStatus: models.StatusFailed},
+		{name: "default is error", severity: "", expectedStatus: models.StatusFailed},
+		{name: "warning reports a warning", severity: models.SeverityWarning, expectedStatus: models.StatusSuccess, expectedWarnings: 1},
+		{name: "info is only listed in details", severity: models.SeverityInfo, expectedStatus: models.StatusSuccess},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec, traceData := newStrictModeTestData()
+			operation := &spec.Spec.Endpoints[0].Operations[0]
+			operation.Responses = models.ResponseSpec{StatusCodes: []int{204}}
+			operation.Severity = tc.severity
+
+			config := DefaultEngineConfig()
+			config.SkipMissingSpans = true
+			report, err := NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+			require.NoError(t, err)
+
+			users := report.Results[0].OperationResults["GET /users/{id}"]
+			require.NotNil(t, users)
+			assert.Equal(t, tc.expectedStatus, users.Status)
+			assert.Equal(t, tc.expectedStatus, report.Results[0].Status)
+			assert.Equal(t, 1, users.AssertionsFailed, "the failed check is reported whatever its severity")
+			assert.Len(t, users.Warnings, tc.expectedWarnings)
+			assert.Equal(t, tc.expectedWarnings, report.Summary.Warnings)
+			for _, detail := range report.Results[0].GetFailedDetails() {
+				assert.Equal(t, tc.severity, detail.Severity)
+			}
+		})
+	}
+}
+
+func TestAlignmentEngine_LegacySeverity(t *testing.T) {
+	spec := models.ServiceSpec{
+		OperationID: "listOrders",
+		Description: "List orders",
+		Severity:    models.SeverityWarning,
+		Postconditions: map[string]interface{}{
+			"==": []interface{}{map[string]interface{}{"var": "span.status.code"}, "OK"},
+		},
+	}
+	traceData := &models.TraceData{
+		TraceID: "trace1",
+		Spans: map[string]*models.Span{
+			"list": {
+				SpanID: "list", TraceID: "trace1", Name: "listOrders",
+				Status:     models.SpanStatus{Code: "ERROR"},
+				Attributes: map[string]interface{}{"operation.id": "listOrders"},
+			},
+		},
+	}
+
+	report, err := NewAlignmentEngine().AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusSuccess, report.Results[0].Status)
+	assert.Equal(t, 1, report.Results[0].AssertionsFailed)
+	assert.Equal(t, []string{"1 assertion(s) of warning severity failed for operation listOrders"}, report.Results[0].Warnings)
+	assert.False(t, report.HasFailures())
+	assert.True(t, report.HasWarnings())
+}
+
+func TestAlignmentEngine_WhenGuards(t *testing.T) {
+	notMe := map[string]interface{}{"!=": []interface{}{map[string]interface{}{"var": "span.attributes.http.target"}, "/users/me"}}
+	newSpec := func(operation models.OperationSpec) models.ServiceSpec {
+		operation.Method = "GET"
+		return models.ServiceSpec{
+			APIVersion: "flowspec/v1alpha1",
+			Kind:       "ServiceSpec",
+			Metadata:   &models.ServiceSpecMetadata{Name: "user-service", Version: "v1"},
+			Spec: &models.ServiceSpecDefinition{Endpoints: []models.EndpointSpec{
+				{Path: "/users/{id}", Operations: []models.OperationSpec{operation}},
+			}},
+		}
+	}
+	traceData := &models.TraceData{
+		TraceID: "trace1",
+		Spans: map[string]*models.Span{
+			"user": {
+				SpanID: "user", TraceID: "trace1", Name: "GET /users/{id}",
+				Attributes: map[string]interface{}{"http.method": "GET", "http.target": "/users/42", "http.status_code": 200},
+			},
+			"me": {
+				SpanID: "me", TraceID: "trace1", Name: "GET /users/{id}",
+				Attributes: map[string]interface{}{"http.method": "GET", "http.target": "/users/me", "http.status_code": 302},
+			},
+		},
+	}
+	align := func(spec models.ServiceSpec) *models.OperationResult {
+		report, err := NewAlignmentEngine().AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+		require.NoError(t, err)
+		operation := report.Results[0].OperationResults["GET /users/{id}"]
+		require.NotNil(t, operation)
+		return operation
+	}
+	failedSpans := func(operation *models.OperationResult) []string {
+		var spans []string
+		for _, detail := range operation.Details {
+			if !detail.IsPassed() {
+				spans = append(spans, detail.SpanContext.SpanID)
+			}
+		}
+		return spans
+	}
+
+	testCases := []struct {
+		name           string
+		operation      models.OperationSpec
+		expectedTotal  int
+		expectedFailed []string
+		expectedSpans  []string // Spans the operation's own guard admits
+	}{
+		{
+			name: "unguarded checks apply to every span",
+			operation: models.OperationSpec{
+				Responses: models.ResponseSpec{StatusCodes: []int{200}},
+				Required:  models.RequiredFieldsSpec{Headers: []string{"authorization"}},
+			},
+			expectedTotal:  4,
+			expectedFailed: []string{"me", "me", "user"},
+			expectedSpans:  []string{"me", "user"},
+		},
+		{
+			name: "guarded required headers",
+			operation: models.OperationSpec{
+				Responses: models.ResponseSpec{StatusCodes: []int{200, 302}},
+				Required:  models.RequiredFieldsSpec{Headers: []string{"authorization"}, When: notMe},
+			},
+			expectedTotal:  3,
+			expectedFailed: []string{"user"},
+			expectedSpans:  []string{"me", "user"},
+		},
+		{
+			name: "guarded responses",
+			operation: models.OperationSpec{
+				Responses: models.ResponseSpec{StatusCodes: []int{200}, When: notMe},
+			},
+			expectedTotal: 1,
+			expectedSpans: []string{"me", "user"},
+		},
+		{
+			name: "guarded operation",
+			operation: models.OperationSpec{
+				Responses: models.ResponseSpec{StatusCodes: []int{200}},
+				Required:  models.RequiredFieldsSpec{Headers: []string{"authorization"}},
+				When:      notMe,
+			},
+			expectedTotal:  2,
+			expectedFailed: []string{"user"},
+			expectedSpans:  []string{"user"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			operation := align(newSpec(tc.operation))
+			assert.Equal(t, tc.expectedTotal, operation.AssertionsTotal)
+			failed := failedSpans(operation)
+			sort.Strings(failed)
+			assert.Equal(t, tc.expectedFailed, failed)
+			matched := append([]string{}, operation.MatchedSpans...)
+			sort.Strings(matched)
+			assert.Equal(t, tc.expectedSpans, matched, "only the operation guard changes which spans match")
+			assert.Equal(t, len(tc.expectedSpans), operation.SampleCount)
+		})
+	}
+
+	// An operation whose guard admits none of its spans does not apply to the trace
+	none := align(newSpec(models.OperationSpec{
+		Responses:  models.ResponseSpec{StatusCodes: []int{404}},
+		Deprecated: true,
+		When:       map[string]interface{}{"==": []interface{}{map[string]interface{}{"var": "span.attributes.http.target"}, "/users/none"}},
+	}))
+	assert.Equal(t, models.StatusSkipped, none.Status)
+	assert.Empty(t, none.MatchedSpans)
+	assert.Zero(t, none.SampleCount)
+	assert.Zero(t, none.AssertionsTotal)
+	assert.Empty(t, none.Warnings, "guarded out spans are no traffic to a deprecated operation")
+
+	engine := NewAlignmentEngine()
+	engine.SetEvaluator(&MockAssertionEvaluator{
+		evaluateFunc: func(assertion map[string]interface{}, context *EvaluationContext) (*AssertionResult, error) {
+			return nil, fmt.Errorf("unsupported operator")
+		},
+	})
+	spec := newSpec(models.OperationSpec{Responses: models.ResponseSpec{StatusCodes: []int{200, 302}, When: notMe}})
+	report, err := engine.AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	require.NoError(t, err)
+	broken := report.Results[0].OperationResults["GET /users/{id}"]
+	assert.Equal(t, models.StatusFailed, broken.Status)
+	assert.Equal(t, 2, broken.AssertionsFailed)
+	assert.Equal(t, "when", broken.Details[0].Type)
+	assert.Equal(t, "The when guard of responses could not be evaluated: unsupported operator", broken.Details[0].Message)
+}
+
+func TestAlignmentEngine_SpanStatusRules(t *testing.T) {
+	statusOK := map[string]interface{}{"==": []interface{}{map[string]interface{}{"var": "span.status.code"}, "OK"}}
+	specs := []models.ServiceSpec{
+		{OperationID: "listOrders", Description: "List orders", Postconditions: statusOK},
+		{OperationID: "streamOrders", Description: "Stream orders", Postconditions: statusOK},
+	}
+	traceData := &models.TraceData{
+		TraceID: "trace1",
+		Spans: map[string]*models.Span{
+			"list": {
+				SpanID: "list", TraceID: "trace1", Name: "listOrders",
+				Status:     models.SpanStatus{Code: "UNSET"},
+				Attributes: map[string]interface{}{"operation.id": "listOrders"},
+			},
+			"stream": {
+				SpanID: "stream", TraceID: "trace1", Name: "streamOrders",
+				Status:     models.SpanStatus{Code: "ERROR", Message: "rpc error: context canceled"},
+				Attributes: map[string]interface{}{"operation.id": "streamOrders"},
+			},
+		},
+	}
+	statuses := func(config *EngineConfig, specs []models.ServiceSpec) []models.AlignmentStatus {
+		report, err := NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace(specs, traceData)
+		require.NoError(t, err)
+		results := make([]models.AlignmentStatus, len(report.Results))
+		for i, result := range report.Results {
+			results[i] = result.Status
+		}
+		return results
+	}
+
+	config := DefaultEngineConfig()
+	assert.Equal(t, []models.AlignmentStatus{models.StatusFailed, models.StatusFailed}, statuses(config, specs))
+
+	config.SpanStatus = models.SpanStatusRules{{Code: "UNSET", As: "OK"}}
+	require.NoError(t, ValidateEngineConfig(config))
+	assert.Equal(t, []models.AlignmentStatus{models.StatusSuccess, models.StatusFailed}, statuses(config, specs))
+
+	withSpecRule := append([]models.ServiceSpec(nil), specs...)
+	withSpecRule[1].SpanStatus = models.SpanStatusRules{{Code: "ERROR", Message: "context canceled$", As: "OK"}}
+	assert.Equal(t, []models.AlignmentStatus{models.StatusSuccess, models.StatusSuccess}, statuses(config, withSpecRule))
+
+	config.SpanStatus = models.SpanStatusRules{{Code: "TIMEOUT", As: "OK"}}
+	assert.ErrorContains(t, ValidateEngineConfig(config), "SpanStatus: rule 1: code must be one of UNSET, OK, ERROR")
+}
+
+func TestAlignmentEngine_TraceCompleteness(t *testing.T) {
+	noError := map[string]interface{}{"==": []interface{}{map[string]interface{}{"var": "span.has_error"}, false}}
+	specs := []models.ServiceSpec{{OperationID: "listOrders", Description: "List orders", Postconditions: noError}}
+	traceData := &models.TraceData{
+		TraceID: "trace1",
+		Spans: map[string]*models.Span{
+			"root": {SpanID: "root", TraceID: "trace1", Name: "gateway", StartTime: 100, EndTime: 900},
+			"list": {
+				SpanID: "list", TraceID: "trace1", ParentID: "lost", Name: "listOrders", StartTime: 200, EndTime: 800,
+				Attributes: map[string]interface{}{"operation.id": "listOrders"},
+			},
+		},
+	}
+
+	report, err := NewAlignmentEngine().AlignSpecsWithTrace(specs, traceData)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusSuccess, report.Results[0].Status, "issues are reported, not failed")
+	require.NotNil(t, report.Completeness)
+	require.Len(t, report.Completeness.Issues, 1)
+	assert.Equal(t, models.TraceIssueOrphanSpan, report.Completeness.Issues[0].Kind)
+
+	config := DefaultEngineConfig()
+	config.FailOnIncomplete = true
+	report, err = NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace(specs, traceData)
+	assert.Nil(t, report)
+	assert.ErrorIs(t, err, models.ErrIncompleteTrace)
+	assert.ErrorContains(t, err, "1 orphan_span")
+
+	traceData.Spans["list"].ParentID = "root"
+	report, err = NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace(specs, traceData)
+	require.NoError(t, err)
+	assert.Nil(t, report.Completeness)
+
+	config.ClockSkew = -time.Second
+	assert.ErrorContains(t, ValidateEngineConfig(config), "ClockSkew must not be negative")
+}
+
+func TestAlignmentEngine_ClockSkewCorrection(t *testing.T) {
+	nonNegative := map[string]interface{}{">=": []interface{}{map[string]interface{}{"var": "span.duration"}, 0}}
+	specs := []models.ServiceSpec{{OperationID: "listOrders", Description: "List orders", Postconditions: nonNegative}}
+	traceData := &models.TraceData{
+		TraceID: "trace1",
+		Spans: map[string]*models.Span{
+			"root": {SpanID: "root", TraceID: "trace1", Name: "gateway", StartTime: 1000, EndTime: 2000},
+			"list": {
+				SpanID: "list", TraceID: "trace1", ParentID: "root", Name: "listOrders", StartTime: 700, EndTime: 600,
+				Attributes: map[string]interface{}{"operation.id": "listOrders"},
+			},
+		},
+	}
+
+	report, err := NewAlignmentEngine().AlignSpecsWithTrace(specs, traceData)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusFailed, report.Results[0].Status, "negative duration without correction")
+	assert.Nil(t, report.ClockSkew)
+	require.NotNil(t, report.Completeness)
+
+	config := DefaultEngineConfig()
+	config.CorrectClockSkew = true
+	report, err = NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace(specs, traceData)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusSuccess, report.Results[0].Status)
+	assert.Equal(t, &models.ClockSkewCorrection{Spans: 1, MaxShift: 300}, report.ClockSkew)
+	assert.Nil(t, report.Completeness, "corrected trace is complete")
+	assert.Equal(t, int64(700), traceData.Spans["list"].StartTime, "input trace is left unchanged")
+}
+
+func TestAlignmentEngine_Tags(t *testing.T) {
+	spec, traceData := newStrictModeTestData()
+	spec.Spec.Endpoints[0].Tags = []string{"critical"}
+	spec.Spec.Endpoints[0].Operations[0].Tags = []string{"users"}
+	spec.Spec.Endpoints[1].Operations[0].Tags = []string{"ops"}
+
+	report, err := NewAlignmentEngine().AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	assert.Len(t, report.Results[0].OperationResults, 2, "all operations without a tag filter")
+	assert.Equal(t, []string{"critical", "users"}, report.Results[0].OperationResults["GET /users/{id}"].Tags)
+	assert.Equal(t, []models.TagSummary{
+		{Tag: "critical", TotalOperations: 1, SuccessOperations: 1, CoveredOperations: 1},
+		{Tag: "ops", TotalOperations: 1, SkippedOperations: 1},
+		{Tag: "users", TotalOperations: 1, SuccessOperations: 1, CoveredOperations: 1},
+	}, report.Tags)
+
+	config := DefaultEngineConfig()
+	config.StrictMode = true
+	config.Tags = []string{"critical"}
+	report, err = NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1, "no uncovered traffic result for a tag slice")
+	assert.Equal(t, models.StatusSuccess, report.Results[0].Status)
+	assert.Len(t, report.Results[0].OperationResults, 1)
+	assert.Len(t, spec.Spec.Endpoints, 2, "the specs passed in are unchanged")
+
+	config.Tags = []string{"payments"}
+	_, err = NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	assert.ErrorContains(t, err, "no contract operation is tagged payments")
+}
+
+func TestEndpointMatcher_DedupesSpansMatchingSeveralOperations(t *testing.T) {
+	spec, traceData := newStrictModeTestData()
+	spec.Spec.Endpoints = append(spec.Spec.Endpoints, models.EndpointSpec{
+		Path:       "/users/{name}",
+		Operations: []models.OperationSpec{{Method: "GET"}},
+	})
+
+	spans, err := (&EndpointMatcher{}).Match(spec, traceData)
+	require.NoError(t, err)
+	require.Len(t, spans, 1, "GET /users/42 matches both /users/{id} and /users/{name}")
+	assert.Equal(t, "covered", spans[0].SpanID)
+}
+
+func TestAlignmentEngine_DuplicateSpans(t *testing.T) {
+	// The span "get" is exported twice, as the OTLP exporters of some SDKs do on retries
+	span := func(id, name string) string {
+		return `{"traceId": "1234567890abcdef1234567890abcdef", "spanId": "` + id + `", "name": "` + name + `",
+		  "kind": "SPAN_KIND_SERVER", "startTimeUnixNano": "1000", "endTimeUnixNano": "2000", "status": {"code": "STATUS_CODE_OK"},
+		  "attributes": [
+		    {"key": "operation.id", "value": {"stringValue": "` + name + `"}},
+		    {"key": "http.method", "value": {"stringValue": "GET"}},
+		    {"key": "http.target", "value": {"stringValue": "/orders/1"}},
+		    {"key": "http.status_code", "value": {"intValue": 200}}]}`
+	}
+	otlp := `{"resourceSpans": [{"scopeSpans": [{"spans": [` +
+		span("aaaaaaaaaaaaaaaa", "getOrder") + "," + span("aaaaaaaaaaaaaaaa", "getOrder") + "," +
+		span("bbbbbbbbbbbbbbbb", "listOrders") + `]}]}]}`
+	traceData, err := ingestor.NewTraceIngestor().IngestFromReader(strings.NewReader(otlp))
+	require.NoError(t, err)
+	require.Equal(t, []string{"aaaaaaaaaaaaaaaa"}, traceData.DuplicateSpanIDs)
+
+	nonNegative := map[string]interface{}{">=": []interface{}{map[string]interface{}{"var": "span.duration"}, 0}}
+	specs := []models.ServiceSpec{
+		{OperationID: "getOrder", Description: "Get an order", Postconditions: nonNegative},
+		{OperationID: "listOrders", Description: "List orders", Postconditions: nonNegative},
+		{
+			APIVersion: "flowspec/v1alpha1", Kind: "ServiceSpec",
+			Metadata: &models.ServiceSpecMetadata{Name: "orders", Version: "v1"},
+			Spec: &models.ServiceSpecDefinition{Endpoints: []models.EndpointSpec{{
+				Path:       "/orders/{id}",
+				Operations: []models.OperationSpec{{Method: "GET", Responses: models.ResponseSpec{StatusCodes: []int{200}}}},
+			}}},
+		},
+	}
+
+	report, err := NewAlignmentEngine().AlignSpecsWithTrace(specs, traceData)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 3)
+	get, list, orders := report.Results[0], report.Results[1], report.Results[2]
+	assert.Equal(t, models.StatusSuccess, get.Status)
+	assert.Equal(t, 1, get.AssertionsTotal, "the duplicate is evaluated once")
+	assert.Equal(t, 1, get.DuplicateSpans)
+	assert.Zero(t, list.DuplicateSpans)
+	assert.Equal(t, 1, orders.DuplicateSpans)
+	assert.Equal(t, 1, orders.OperationResults["GET /orders/{id}"].DuplicateSpans)
+	assert.Equal(t, 2, report.Summary.DuplicateSpans)
+}
+
+func TestEndpointMatcher_MatchWithOverlaps(t *testing.T) {
+	spec := models.ServiceSpec{
+		APIVersion: "flowspec/v1alpha1", Kind: "ServiceSpec",
+		Metadata: &models.ServiceSpecMetadata{Name: "users", Version: "v1"},
+		Spec: &models.ServiceSpecDefinition{Endpoints: []models.EndpointSpec{
+			{Path: "/users/{id}", Operations: []models.OperationSpec{{Method: "GET"}}},
+			{Path: "/users/me", Operations: []models.OperationSpec{{Method: "GET"}}},
+		}},
+	}
+	traceData := &models.TraceData{TraceID: "t1", Spans: map[string]*models.Span{}}
+	for id, target := range map[string]string{"me": "/users/me", "42": "/users/42"} {
+		traceData.AddSpan(&models.Span{SpanID: id, TraceID: "t1", Name: "GET " + target,
+			Attributes: map[string]interface{}{"http.method": "GET", "http.target": target}})
+	}
+
+	spans, overlaps, err := (&EndpointMatcher{}).MatchWithOverlaps(spec, traceData)
+	require.NoError(t, err)
+	assert.Len(t, spans, 2, "each span is returned once")
+	assert.Equal(t, 1, overlaps, "/users/me matches both operations")
+}
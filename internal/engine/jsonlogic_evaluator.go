@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
@@ -190,6 +191,14 @@ func (evaluator *JSONLogicEvaluator) buildEvaluationData(context *EvaluationCont
 			"is_root":   span.IsRoot(),
 		}
 
+		// Break the timestamps down for time-based assertions, e.g. span.start.hour
+		if span.StartTime > 0 {
+			spanData["start"] = timeVariables(span.StartTime, context.Location)
+		}
+		if span.EndTime > 0 {
+			spanData["end"] = timeVariables(span.EndTime, context.Location)
+		}
+
 		// Expand dot-notation keys from attributes and merge them into spanData
 		expandedAttrs := expandDotKeys(span.Attributes)
 		for key, value := range expandedAttrs {
@@ -280,8 +289,29 @@ func (evaluator *JSONLogicEvaluator) buildEvaluationData(context *EvaluationCont
 		}
 	}
 
-	// Add variables under "vars" namespace as well
-	data["vars"] = allVars
+	// Add variables under "vars" namespace as well, exposing external
+	// variables (stored as "vars.<key>") by their bare key
+	vars := make(map[string]interface{}, len(allVars))
+	for key, value := range allVars {
+		vars[key] = value
+	}
+	for key, value := range allVars {
+		if strings.HasPrefix(key, VariablePrefix) {
+			vars[strings.TrimPrefix(key, VariablePrefix)] = value
+		}
+	}
+	data["vars"] = vars
+
+	// Expose path parameters (stored as "path.<name>") as the "path" namespace
+	pathParameters := make(map[string]interface{})
+	for key, value := range allVars {
+		if strings.HasPrefix(key, PathParameterPrefix) {
+			pathParameters[strings.TrimPrefix(key, PathParameterPrefix)] = value
+		}
+	}
+	if len(pathParameters) > 0 {
+		data["path"] = pathParameters
+	}
 
 	// Add evaluation metadata
 	data["_meta"] = map[string]interface{}{
@@ -295,6 +325,76 @@ func (evaluator *JSONLogicEvaluator) buildEvaluationData(context *EvaluationCont
 	return data, nil
 }
 
+// ReferencedVariables returns the sorted, de-duplicated variable paths an assertion reads.
+// Variables declared with a default value ({"var": ["path", default]}) are not included.
+func (evaluator *JSONLogicEvaluator) ReferencedVariables(assertion map[string]interface{}) []string {
+	if len(assertion) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	collectReferencedVariables(evaluator.preprocessAssertion(assertion), seen)
+
+	variables := make([]string, 0, len(seen))
+	for variable := range seen {
+		variables = append(variables, variable)
+	}
+	sort.Strings(variables)
+	return variables
+}
+
+// collectReferencedVariables walks a JSONLogic rule collecting "var" paths
+func collectReferencedVariables(rule interface{}, seen map[string]bool) {
+	switch v := rule.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if key != "var" {
+				collectReferencedVariables(value, seen)
+				continue
+			}
+			switch ref := value.(type) {
+			case string:
+				if ref != "" {
+					seen[ref] = true
+				}
+			case []interface{}:
+				// A second element is a default value, so the variable always resolves
+				if len(ref) == 1 {
+					if path, ok := ref[0].(string); ok && path != "" {
+						seen[path] = true
+					}
+				}
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectReferencedVariables(item, seen)
+		}
+	}
+}
+
+// ResolveVariable looks up a variable path in evaluation data, trying the flat key
+// first and then walking nested maps segment by segment
+func ResolveVariable(data map[string]interface{}, path string) (interface{}, bool) {
+	if value, exists := data[path]; exists {
+		return value, true
+	}
+
+	var current interface{} = data
+	for _, segment := range strings.Split(path, ".") {
+		currentMap, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value, exists := currentMap[segment]
+		if !exists {
+			return nil, false
+		}
+		current = value
+	}
+	return current, true
+}
+
 // expandDotKeys converts a flat map with dot-notation keys to a nested map
 func expandDotKeys(flat map[string]interface{}) map[string]interface{} {
 	if flat == nil {
@@ -579,6 +679,7 @@ func (evaluator *JSONLogicEvaluator) preprocessAssertion(assertion map[string]in
 		"==": true, "!=": true, ">": true, "<": true, ">=": true, "<=": true,
 		"and": true, "or": true, "not": true, "if": true, "in": true,
 		"var": true, "missing": true, "missing_some": true,
+		InTimeWindowOperator: true, InPeriodOperator: true,
 	}
 
 	// Check if this is already a proper JSONLogic expression
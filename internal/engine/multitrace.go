@@ -0,0 +1,276 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/concurrency"
+	"github.com/flowspec/flowspec-cli/internal/membudget"
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// TraceErrorOperationID identifies the result of a trace that could not be aligned when several
+// traces are verified
+const TraceErrorOperationID = "trace-error"
+
+// AlignSpecsWithTraces aligns specs with each of several traces, up to TraceConcurrency traces
+// at a time, and combines the reports into one whose results carry the ID of their trace. The
+// specs of all traces share the engine's worker pool. A trace that cannot be aligned, e.g. one
+// refused as incomplete, fails with a result of its own instead of stopping the other traces.
+func (engine *DefaultAlignmentEngine) AlignSpecsWithTraces(
+	ctx context.Context,
+	specs []models.ServiceSpec,
+	traces []*models.TraceData,
+) (*models.AlignmentReport, error) {
+	if len(specs) == 0 {
+		return models.NewAlignmentReport(), nil
+	}
+	if len(traces) == 0 {
+		return nil, fmt.Errorf("no traces to align")
+	}
+
+	// Each trace is aligned by an engine sharing this one's evaluator and pool; results are
+	// streamed to OnResult here, once their trace ID is known
+	config := *engine.config
+	config.OnResult = nil
+	traceEngine := &DefaultAlignmentEngine{evaluator: engine.GetEvaluator(), config: &config, pool: engine.workerPool()}
+
+	workers := concurrency.Bounded(engine.config.TraceConcurrency, len(traces))
+
+	// A critical failure in one trace stops the others with FailFast
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	type traceOutcome struct {
+		position int
+		report   *models.AlignmentReport
+		err      error
+	}
+	outcomes := make(chan traceOutcome, len(traces))
+	go func() {
+		var wg sync.WaitGroup
+		defer close(outcomes)
+		defer wg.Wait()
+		slots := make(chan struct{}, workers)
+		for position, trace := range traces {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			wg.Add(1)
+			go func(position int, trace *models.TraceData) {
+				defer wg.Done()
+				defer func() { <-slots }()
+				report, err := traceEngine.AlignSpecsWithTraceContext(ctx, specs, trace)
+				outcomes <- traceOutcome{position: position, report: report, err: err}
+			}(position, trace)
+		}
+	}()
+
+	// Reports are combined in the order of the traces, so reports of identical runs are identical
+	startTime := time.Now()
+	var budgetErr error
+	reports := make([]*models.AlignmentReport, len(traces))
+	summaries := make([]*models.TraceSummary, len(traces))
+	for outcome := range outcomes {
+		trace := traces[outcome.position]
+		report := outcome.report
+		summary := &models.TraceSummary{TraceID: trace.TraceID, Spans: len(trace.Spans)}
+		if outcome.err != nil {
+			summary.Error = outcome.err.Error()
+			if errors.Is(outcome.err, membudget.ErrExceeded) && budgetErr == nil {
+				budgetErr = outcome.err
+			}
+			if report == nil {
+				report = models.NewAlignmentReport()
+				report.AddResult(*newTraceErrorResult(outcome.err))
+			}
+		}
+		for i := range report.Results {
+			report.Results[i].TraceID = trace.TraceID
+			if engine.config.OnResult != nil {
+				engine.config.OnResult(report.Results[i])
+			}
+		}
+		summary.Total, summary.Success = report.Summary.Total, report.Summary.Success
+		summary.Failed, summary.Skipped = report.Summary.Failed, report.Summary.Skipped
+		summary.Incomplete = report.Incomplete
+		reports[outcome.position], summaries[outcome.position] = report, summary
+		if report.FailFast != nil {
+			cancel(errFailFast)
+		}
+	}
+
+	combined := combineTraceReports(reports, summaries)
+	if combined.StartTime == 0 {
+		combined.StartTime = startTime.UnixNano()
+	}
+	combined.TimeWindow = engine.config.TimeWindow
+	endTime := time.Now()
+	combined.EndTime = endTime.UnixNano()
+	combined.ExecutionTime = endTime.Sub(startTime).Nanoseconds()
+
+	if combined.FailFast != nil && errors.Is(context.Cause(ctx), errFailFast) {
+		return combined, budgetErr
+	}
+	if err := ctx.Err(); err != nil {
+		combined.Incomplete = true
+		combined.IncompleteReason = context.Cause(ctx).Error()
+		return combined, fmt.Errorf("alignment cancelled: %w", err)
+	}
+	// Memory running out is reported like it is for a single trace, along with the results
+	return combined, budgetErr
+}
+
+// mergeSampling combines the sampling of two traces; rates declared by span attributes are
+// averaged over the traces declaring them
+func mergeSampling(combined, sampling *models.Sampling) *models.Sampling {
+	if combined == nil {
+		merged := *sampling
+		return &merged
+	}
+	if traces := combined.Traces + sampling.Traces; traces > 0 {
+		combined.Rate = (combined.Rate*float64(combined.Traces) + sampling.Rate*float64(sampling.Traces)) / float64(traces)
+		combined.Traces = traces
+	}
+	return combined
+}
+
+// newTraceErrorResult returns the failed result of a trace that could not be aligned
+func newTraceErrorResult(err error) *models.AlignmentResult {
+	now := time.Now().UnixNano()
+	result := models.NewAlignmentResult(TraceErrorOperationID)
+	result.Status = models.StatusFailed
+	result.ErrorMessage = err.Error()
+	result.StartTime, result.EndTime = now, now
+	return result
+}
+
+// combineTraceReports merges the reports of several traces; traces that were not aligned, on
+// cancellation, are left out
+func combineTraceReports(reports []*models.AlignmentReport, summaries []*models.TraceSummary) *models.AlignmentReport {
+	combined := models.NewAlignmentReport()
+	var results []models.AlignmentResult
+	for position, report := range reports {
+		if report == nil {
+			continue
+		}
+		results = append(results, report.Results...)
+		combined.Traces = append(combined.Traces, *summaries[position])
+
+		if report.StartTime != 0 && (combined.StartTime == 0 || report.StartTime < combined.StartTime) {
+			combined.StartTime = report.StartTime
+		}
+		if report.Incomplete {
+			combined.Incomplete = true
+			if combined.IncompleteReason == "" {
+				combined.IncompleteReason = report.IncompleteReason
+			}
+		}
+		if report.Completeness != nil {
+			if combined.Completeness == nil {
+				combined.Completeness = &models.TraceCompleteness{Issues: []models.TraceIssue{}}
+			}
+			combined.Completeness.Spans += report.Completeness.Spans
+			combined.Completeness.Traces += report.Completeness.Traces
+			combined.Completeness.Issues = append(combined.Completeness.Issues, report.Completeness.Issues...)
+		}
+		if report.ClockSkew != nil {
+			if combined.ClockSkew == nil {
+				combined.ClockSkew = &models.ClockSkewCorrection{}
+			}
+			combined.ClockSkew.Spans += report.ClockSkew.Spans
+			if report.ClockSkew.MaxShift > combined.ClockSkew.MaxShift {
+				combined.ClockSkew.MaxShift = report.ClockSkew.MaxShift
+			}
+		}
+		if report.Unmatched != nil {
+			combined.Unmatched = mergeUnmatchedSpanReports(combined.Unmatched, report.Unmatched)
+		}
+		if report.Sampling != nil {
+			combined.Sampling = mergeSampling(combined.Sampling, report.Sampling)
+		}
+		if report.FailFast != nil && combined.FailFast == nil {
+			failFast := *report.FailFast
+			combined.FailFast = &failFast
+		}
+
+		performance := &combined.PerformanceInfo
+		performance.SpecsProcessed += report.PerformanceInfo.SpecsProcessed
+		performance.SpansMatched += report.PerformanceInfo.SpansMatched
+		performance.AssertionsEvaluated += report.PerformanceInfo.AssertionsEvaluated
+		if report.PerformanceInfo.ConcurrentWorkers > performance.ConcurrentWorkers {
+			performance.ConcurrentWorkers = report.PerformanceInfo.ConcurrentWorkers
+		}
+		if report.PerformanceInfo.MemoryUsageMB > performance.MemoryUsageMB {
+			performance.MemoryUsageMB = report.PerformanceInfo.MemoryUsageMB
+		}
+	}
+	combined.AddResults(results)
+	return combined
+}
+
+// mergeUnmatchedSpanReports adds the groups of next to those of merged, which may be nil
+func mergeUnmatchedSpanReports(merged, next *models.UnmatchedSpanReport) *models.UnmatchedSpanReport {
+	if merged == nil {
+		merged = &models.UnmatchedSpanReport{Groups: []models.UnmatchedSpanGroup{}}
+	}
+	merged.TotalSpans += next.TotalSpans
+
+	groupIndex := make(map[string]int, len(merged.Groups))
+	groupKey := func(group models.UnmatchedSpanGroup) string {
+		return strings.Join([]string{group.Name, group.Method, group.Route, group.Status}, "\x00")
+	}
+	for index, group := range merged.Groups {
+		groupIndex[groupKey(group)] = index
+	}
+	for _, group := range next.Groups {
+		index, exists := groupIndex[groupKey(group)]
+		if !exists {
+			groupIndex[groupKey(group)] = len(merged.Groups)
+			group.SampleSpanIDs = append([]string(nil), group.SampleSpanIDs...)
+			merged.Groups = append(merged.Groups, group)
+			continue
+		}
+		existing := &merged.Groups[index]
+		existing.Count += group.Count
+		for _, spanID := range group.SampleSpanIDs {
+			if len(existing.SampleSpanIDs) < maxUnmatchedSampleSpans {
+				existing.SampleSpanIDs = append(existing.SampleSpanIDs, spanID)
+			}
+		}
+	}
+
+	// Most frequent groups first, ties broken by name and route
+	sort.SliceStable(merged.Groups, func(i, j int) bool {
+		a, b := merged.Groups[i], merged.Groups[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Route < b.Route
+	})
+	return merged
+}
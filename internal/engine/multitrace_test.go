@@ -0,0 +1,99 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlignmentEngine_AlignSpecsWithTraces(t *testing.T) {
+	spec, passing := newStrictModeTestData()
+	failing := &models.TraceData{
+		TraceID: "trace2",
+		Spans: map[string]*models.Span{
+			"error": {
+				SpanID: "error", TraceID: "trace2", Name: "GET /users/{id}",
+				Attributes: map[string]interface{}{"http.method": "GET", "http.target": "/users/7", "http.status_code": 500},
+			},
+		},
+	}
+	empty := &models.TraceData{TraceID: "trace3", Spans: map[string]*models.Span{}}
+
+	for _, workers := range []int{0, 1, 3} {
+		config := DefaultEngineConfig()
+		config.TraceConcurrency = workers
+		config.ReportUnmatched = true
+		var streamed []string
+		config.OnResult = func(result models.AlignmentResult) {
+			streamed = append(streamed, result.TraceID)
+		}
+		alignmentEngine := NewAlignmentEngineWithConfig(config)
+
+		report, err := alignmentEngine.AlignSpecsWithTraces(context.Background(), []models.ServiceSpec{spec},
+			[]*models.TraceData{passing, failing, empty})
+		alignmentEngine.Close()
+		require.NoError(t, err)
+
+		require.Len(t, report.Results, 3)
+		assert.Equal(t, "trace1", report.Results[0].TraceID)
+		assert.Equal(t, models.StatusSuccess, report.Results[0].Status)
+		assert.Equal(t, "trace2", report.Results[1].TraceID)
+		assert.Equal(t, models.StatusFailed, report.Results[1].Status)
+		assert.Equal(t, "trace3", report.Results[2].TraceID)
+		assert.Equal(t, TraceErrorOperationID, report.Results[2].SpecOperationID)
+		assert.Equal(t, models.StatusFailed, report.Results[2].Status)
+		assert.Contains(t, report.Results[2].ErrorMessage, "trace data is empty")
+		assert.ElementsMatch(t, []string{"trace1", "trace2", "trace3"}, streamed)
+
+		assert.Equal(t, 1, report.Summary.Success)
+		assert.Equal(t, 2, report.Summary.Failed)
+		assert.Equal(t, []models.TraceSummary{
+			{TraceID: "trace1", Spans: 3, Total: 1, Success: 1},
+			{TraceID: "trace2", Spans: 1, Total: 1, Failed: 1},
+			{TraceID: "trace3", Total: 1, Failed: 1, Error: "trace data is empty or nil"},
+		}, report.Traces)
+
+		operations := report.Summary.OperationSummary.OperationDetails
+		assert.Equal(t, models.StatusFailed, operations["GET /users/{id}"].Status, "the worst status across traces")
+		assert.Equal(t, 2, operations["GET /users/{id}"].SampleCount)
+		require.NotNil(t, report.Coverage)
+		assert.Equal(t, 1, report.Coverage.CoveredOperations)
+
+		require.NotNil(t, report.Unmatched)
+		assert.Equal(t, 2, report.Unmatched.TotalSpans)
+		assert.False(t, report.Incomplete)
+	}
+}
+
+func TestAlignmentEngine_AlignSpecsWithTraces_Cancelled(t *testing.T) {
+	spec, traceData := newStrictModeTestData()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	alignmentEngine := NewAlignmentEngine()
+	defer alignmentEngine.Close()
+	report, err := alignmentEngine.AlignSpecsWithTraces(ctx, []models.ServiceSpec{spec}, []*models.TraceData{traceData})
+	assert.ErrorIs(t, err, context.Canceled)
+	require.NotNil(t, report)
+	assert.True(t, report.Incomplete)
+
+	_, err = alignmentEngine.AlignSpecsWithTraces(context.Background(), []models.ServiceSpec{spec}, nil)
+	assert.ErrorContains(t, err, "no traces to align")
+}
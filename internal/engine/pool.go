@@ -0,0 +1,132 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// ErrPoolClosed is returned when submitting to a closed worker pool
+var ErrPoolClosed = errors.New("worker pool is closed")
+
+// WorkerPool runs tasks on a fixed set of goroutines that are reused across alignment runs.
+// Tasks are handed to whichever worker is free next, so a slow task never holds up tasks queued
+// behind it.
+type WorkerPool struct {
+	size    int
+	tasks   chan func()
+	closed  chan struct{}
+	once    sync.Once
+	workers sync.WaitGroup
+}
+
+// NewWorkerPool starts a pool of size workers (at least one)
+func NewWorkerPool(size int) *WorkerPool {
+	if size < 1 {
+		size = 1
+	}
+	pool := &WorkerPool{
+		size:   size,
+		tasks:  make(chan func()),
+		closed: make(chan struct{}),
+	}
+	pool.workers.Add(size)
+	for i := 0; i < size; i++ {
+		go pool.work()
+	}
+	return pool
+}
+
+// Size returns the number of workers
+func (p *WorkerPool) Size() int {
+	return p.size
+}
+
+// Submit blocks until a worker accepts task, ctx is done or the pool is closed
+func (p *WorkerPool) Submit(ctx context.Context, task func()) error {
+	select {
+	case <-p.closed:
+		return ErrPoolClosed
+	default:
+	}
+	select {
+	case p.tasks <- task:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.closed:
+		return ErrPoolClosed
+	}
+}
+
+// Close stops the workers after their current tasks
+func (p *WorkerPool) Close() {
+	p.once.Do(func() { close(p.closed) })
+	p.workers.Wait()
+}
+
+func (p *WorkerPool) work() {
+	defer p.workers.Done()
+	for {
+		select {
+		case task := <-p.tasks:
+			runTask(task)
+		case <-p.closed:
+			return
+		}
+	}
+}
+
+// runTask runs a task, keeping the worker alive if it panics. Tasks report their own panics;
+// this is the last line of defence.
+func runTask(task func()) {
+	defer func() { _ = recover() }()
+	task()
+}
+
+// scheduleSpecs returns the positions of specs by priority, critical first, and within a
+// priority longest expected first so the slowest specs start early and the run is not left
+// waiting on one big spec started last. The order is otherwise preserved.
+func scheduleSpecs(specs []models.ServiceSpec) []int {
+	ordered := make([]int, len(specs))
+	for i := range ordered {
+		ordered[i] = i
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := specs[ordered[i]], specs[ordered[j]]
+		if rankA, rankB := a.SpecPriority().Rank(), b.SpecPriority().Rank(); rankA != rankB {
+			return rankA < rankB
+		}
+		return specCost(a) > specCost(b)
+	})
+	return ordered
+}
+
+// specCost estimates the alignment work of a spec from the checks it declares
+func specCost(spec models.ServiceSpec) int {
+	if !spec.IsYAMLFormat() {
+		return 1 + len(spec.Preconditions) + len(spec.Postconditions)
+	}
+	cost := 0
+	for _, endpoint := range spec.Spec.Endpoints {
+		cost += len(endpoint.Operations)
+	}
+	return cost
+}
@@ -0,0 +1,163 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerPool_Bounded(t *testing.T) {
+	pool := NewWorkerPool(2)
+	defer pool.Close()
+
+	var running, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		require.NoError(t, pool.Submit(context.Background(), func() {
+			defer wg.Done()
+			current := atomic.AddInt32(&running, 1)
+			for {
+				previous := atomic.LoadInt32(&peak)
+				if current <= previous || atomic.CompareAndSwapInt32(&peak, previous, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		}))
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&peak))
+}
+
+func TestWorkerPool_SurvivesPanicsAndCancellation(t *testing.T) {
+	pool := NewWorkerPool(1)
+
+	require.NoError(t, pool.Submit(context.Background(), func() { panic("boom") }))
+	done := make(chan struct{})
+	require.NoError(t, pool.Submit(context.Background(), func() { close(done) }))
+	<-done
+
+	// The only worker is busy, so a cancelled submission gives up
+	block := make(chan struct{})
+	require.NoError(t, pool.Submit(context.Background(), func() { <-block }))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, pool.Submit(ctx, func() {}), context.Canceled)
+	close(block)
+
+	pool.Close()
+	assert.ErrorIs(t, pool.Submit(context.Background(), func() {}), ErrPoolClosed)
+}
+
+func TestScheduleSpecs_LongestFirst(t *testing.T) {
+	yamlSpec := func(name string, operations int) models.ServiceSpec {
+		endpoint := models.EndpointSpec{Path: "/" + name}
+		for i := 0; i < operations; i++ {
+			endpoint.Operations = append(endpoint.Operations, models.OperationSpec{Method: "GET"})
+		}
+		return models.ServiceSpec{
+			APIVersion: "flowspec/v1alpha1",
+			Kind:       "ServiceSpec",
+			Metadata:   &models.ServiceSpecMetadata{Name: name, Version: "v1"},
+			Spec:       &models.ServiceSpecDefinition{Endpoints: []models.EndpointSpec{endpoint}},
+		}
+	}
+	specs := []models.ServiceSpec{yamlSpec("small", 1), yamlSpec("big", 5), yamlSpec("medium", 3), yamlSpec("small-too", 1)}
+
+	var names []string
+	for _, position := range scheduleSpecs(specs) {
+		names = append(names, specs[position].Metadata.Name)
+	}
+	assert.Equal(t, []string{"big", "medium", "small", "small-too"}, names)
+	assert.Equal(t, "small", specs[0].Metadata.Name, "the input is not reordered")
+}
+
+func TestAlignmentEngine_PanickingEvaluator(t *testing.T) {
+	engine := NewAlignmentEngine()
+	defer engine.Close()
+	engine.SetEvaluator(&MockAssertionEvaluator{
+		evaluateFunc: func(assertion map[string]interface{}, context *EvaluationContext) (*AssertionResult, error) {
+			if assertion["panic"] == true {
+				panic("evaluator bug")
+			}
+			return &AssertionResult{Passed: true, Expected: true, Actual: true}, nil
+		},
+	})
+
+	span := &models.Span{SpanID: "span1", TraceID: "trace1", Name: "op", Attributes: map[string]interface{}{"operation.id": "op"}}
+	traceData := &models.TraceData{TraceID: "trace1", Spans: map[string]*models.Span{"span1": span}}
+	specs := []models.ServiceSpec{
+		{OperationID: "op", Preconditions: map[string]interface{}{"panic": true}},
+		{OperationID: "op", Preconditions: map[string]interface{}{"panic": false}},
+	}
+
+	report, err := engine.AlignSpecsWithTrace(specs, traceData)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 2)
+
+	var messages []string
+	for _, result := range report.Results {
+		messages = append(messages, result.ErrorMessage)
+	}
+	assert.Contains(t, messages, "alignment panicked: evaluator bug")
+	assert.Contains(t, messages, "")
+}
+
+func TestAlignmentEngine_DeterministicResultOrder(t *testing.T) {
+	spans := make(map[string]*models.Span)
+	var specs []models.ServiceSpec
+	for i := 0; i < 12; i++ {
+		id := string(rune('a' + i))
+		spans[id] = &models.Span{SpanID: id, TraceID: "trace1", Name: id, StartTime: int64(i), Attributes: map[string]interface{}{"operation.id": id}}
+		spec := models.ServiceSpec{OperationID: id, Preconditions: map[string]interface{}{"==": []interface{}{1, 1}}}
+		if i%3 == 0 {
+			// More checks make these specs scheduled first
+			spec.Postconditions = map[string]interface{}{"==": []interface{}{1, 1}}
+		}
+		specs = append(specs, spec)
+	}
+	traceData := &models.TraceData{TraceID: "trace1", Spans: spans}
+
+	config := DefaultEngineConfig()
+	config.MaxConcurrency = 4
+	engine := NewAlignmentEngineWithConfig(config)
+	defer engine.Close()
+
+	var previous []string
+	for run := 0; run < 5; run++ {
+		report, err := engine.AlignSpecsWithTrace(specs, traceData)
+		require.NoError(t, err)
+
+		var ids []string
+		for i, result := range report.Results {
+			assert.Equal(t, specs[i].OperationID, result.SpecOperationID, "results follow the spec order")
+			ids = append(ids, result.ID)
+		}
+		if previous != nil {
+			assert.Equal(t, previous, ids, "result IDs are stable across runs")
+		}
+		previous = ids
+	}
+}
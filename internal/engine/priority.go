@@ -0,0 +1,75 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// errFailFast stops a run with FailFast at the first failure of critical priority
+var errFailFast = errors.New("stopped at the first critical failure")
+
+// prioritizeSpecs returns the positions of specs ordered by priority, critical first, keeping
+// the order of specs of the same priority
+func prioritizeSpecs(specs []models.ServiceSpec) []int {
+	ordered := make([]int, len(specs))
+	for i := range ordered {
+		ordered[i] = i
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return specs[ordered[i]].SpecPriority().Rank() < specs[ordered[j]].SpecPriority().Rank()
+	})
+	return ordered
+}
+
+// prioritizeOperations orders the operations of a spec by priority, critical first, keeping
+// the order of operations of the same priority
+func prioritizeOperations(matches []*operationMatch) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].operation.Priority.Rank() < matches[j].operation.Priority.Rank()
+	})
+}
+
+// isCriticalFailure reports whether a failed operation or spec of critical priority stops a
+// run with FailFast; flaky ones do not
+func isCriticalFailure(priority models.Priority, status models.AlignmentStatus) bool {
+	return priority.IsCritical() && (status == models.StatusFailed || status == models.StatusTimeout)
+}
+
+// criticalFailure returns where a result failed with critical priority: the first failed
+// critical operation for a spec with operations, the spec itself otherwise, or nil
+func criticalFailure(result *models.AlignmentResult) *models.FailFastStop {
+	if len(result.OperationResults) == 0 || result.Status == models.StatusTimeout {
+		if isCriticalFailure(result.Priority, result.Status) {
+			return &models.FailFastStop{Spec: result.SpecOperationID}
+		}
+		return nil
+	}
+
+	keys := make([]string, 0, len(result.OperationResults))
+	for key := range result.OperationResults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if operation := result.OperationResults[key]; isCriticalFailure(operation.Priority, operation.Status) {
+			return &models.FailFastStop{Spec: result.SpecOperationID, Operation: key}
+		}
+	}
+	return nil
+}
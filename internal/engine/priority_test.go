@@ -0,0 +1,161 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPriorityTestData returns annotated specs of the given priorities, each matching a span of
+// its own; specs named fail-* fail
+func newPriorityTestData(priorities map[string]models.Priority, names ...string) ([]models.ServiceSpec, *models.TraceData) {
+	passes := map[string]interface{}{">=": []interface{}{map[string]interface{}{"var": "span.duration"}, 0}}
+	fails := map[string]interface{}{"<": []interface{}{map[string]interface{}{"var": "span.duration"}, 0}}
+	traceData := &models.TraceData{TraceID: "trace1", Spans: map[string]*models.Span{}}
+	var specs []models.ServiceSpec
+	for i, name := range names {
+		postconditions := passes
+		if len(name) > 5 && name[:5] == "fail-" {
+			postconditions = fails
+		}
+		specs = append(specs, models.ServiceSpec{
+			OperationID: name, Description: name, Postconditions: postconditions, Priority: priorities[name],
+		})
+		spanID := fmt.Sprintf("span%d", i)
+		traceData.Spans[spanID] = &models.Span{
+			SpanID: spanID, TraceID: "trace1", Name: name, StartTime: 100, EndTime: 200,
+			Attributes: map[string]interface{}{"operation.id": name},
+		}
+	}
+	return specs, traceData
+}
+
+func TestAlignmentEngine_ReportsByPriority(t *testing.T) {
+	specs, traceData := newPriorityTestData(map[string]models.Priority{
+		"checkout": models.PriorityCritical, "search": models.PriorityLow, "login": models.PriorityHigh,
+	}, "search", "profile", "checkout", "login", "settings")
+
+	report, err := NewAlignmentEngine().AlignSpecsWithTrace(specs, traceData)
+	require.NoError(t, err)
+	var order []string
+	for _, result := range report.Results {
+		order = append(order, result.SpecOperationID)
+	}
+	assert.Equal(t, []string{"checkout", "login", "profile", "settings", "search"}, order,
+		"by priority, in the order of the specs within a priority")
+	assert.Equal(t, models.PriorityCritical, report.Results[0].Priority)
+	assert.Nil(t, report.FailFast)
+}
+
+func TestScheduleSpecs_Priority(t *testing.T) {
+	specs, _ := newPriorityTestData(map[string]models.Priority{"b": models.PriorityCritical, "c": models.PriorityLow}, "a", "b", "c", "d")
+	specs[3].Postconditions = map[string]interface{}{"x": 1, "y": 2}
+
+	var names []string
+	for _, position := range scheduleSpecs(specs) {
+		names = append(names, specs[position].OperationID)
+	}
+	assert.Equal(t, []string{"b", "d", "a", "c"}, names, "critical first, then the longest within a priority")
+}
+
+func TestAlignmentEngine_FailFast(t *testing.T) {
+	names := []string{"profile", "fail-checkout", "settings", "search", "login", "orders", "cart"}
+	specs, traceData := newPriorityTestData(map[string]models.Priority{"fail-checkout": models.PriorityCritical}, names...)
+
+	config := DefaultEngineConfig()
+	config.MaxConcurrency = 1
+	config.FailFast = true
+	report, err := NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace(specs, traceData)
+	require.NoError(t, err, "a stop at a critical failure is not an error")
+	require.NotNil(t, report.FailFast)
+	assert.Equal(t, "fail-checkout", report.FailFast.Spec)
+	assert.Empty(t, report.FailFast.Operation)
+	assert.Positive(t, report.FailFast.NotAligned)
+	assert.Equal(t, len(specs), len(report.Results)+report.FailFast.NotAligned)
+	assert.False(t, report.Incomplete)
+	assert.Equal(t, "fail-checkout", report.Results[0].SpecOperationID)
+	assert.Equal(t, models.StatusFailed, report.Results[0].Status)
+
+	config.FailFast = false
+	report, err = NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace(specs, traceData)
+	require.NoError(t, err)
+	assert.Nil(t, report.FailFast)
+	assert.Len(t, report.Results, len(specs))
+}
+
+func TestAlignmentEngine_FailFastIgnoresOtherFailures(t *testing.T) {
+	specs, traceData := newPriorityTestData(map[string]models.Priority{
+		"checkout": models.PriorityCritical, "fail-search": models.PriorityHigh,
+	}, "checkout", "fail-search", "fail-profile")
+
+	config := DefaultEngineConfig()
+	config.FailFast = true
+	report, err := NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace(specs, traceData)
+	require.NoError(t, err)
+	assert.Nil(t, report.FailFast, "no critical spec failed")
+	assert.Len(t, report.Results, 3)
+	assert.Equal(t, 2, report.Summary.Failed)
+}
+
+func TestAlignmentEngine_FailFastOperations(t *testing.T) {
+	spec, traceData := newStrictModeTestData()
+	// GET /health matches no span and is declared after GET /users/{id}
+	spec.Spec.Endpoints[1].Operations[0].Priority = models.PriorityCritical
+
+	config := DefaultEngineConfig()
+	config.SkipMissingSpans = false
+	config.FailFast = true
+	report, err := NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	result := report.Results[0]
+	assert.Equal(t, models.PriorityCritical, result.Priority)
+	assert.Equal(t, models.PriorityCritical, result.OperationResults["GET /health"].Priority)
+	assert.Equal(t, models.StatusFailed, result.OperationResults["GET /health"].Status)
+	assert.NotContains(t, result.OperationResults, "GET /users/{id}", "aligned after the critical operation")
+	assert.Equal(t, &models.FailFastStop{Spec: "user-service-v1", Operation: "GET /health"}, report.FailFast)
+
+	config.FailFast = false
+	report, err = NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	require.NoError(t, err)
+	assert.Len(t, report.Results[0].OperationResults, 2)
+	assert.Equal(t, "GET /health", report.Results[0].Details[0].Operation, "critical operations are reported first")
+}
+
+func TestAlignmentEngine_FailFastAcrossTraces(t *testing.T) {
+	specs, traceData := newPriorityTestData(map[string]models.Priority{"fail-checkout": models.PriorityCritical}, "fail-checkout", "profile")
+	second := &models.TraceData{TraceID: "trace2", Spans: map[string]*models.Span{}}
+	for id, span := range traceData.Spans {
+		copied := *span
+		copied.TraceID = "trace2"
+		second.Spans[id] = &copied
+	}
+
+	config := DefaultEngineConfig()
+	config.FailFast = true
+	config.TraceConcurrency = 1
+	config.MaxConcurrency = 1
+	report, err := NewAlignmentEngineWithConfig(config).AlignSpecsWithTraces(context.Background(), specs, []*models.TraceData{traceData, second})
+	require.NoError(t, err)
+	require.NotNil(t, report.FailFast)
+	assert.Equal(t, "fail-checkout", report.FailFast.Spec)
+	assert.False(t, report.Incomplete)
+}
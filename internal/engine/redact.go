@@ -0,0 +1,46 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import "github.com/flowspec/flowspec-cli/internal/models"
+
+// redactSpanContexts replaces the spans quoted by the details of a result with copies whose
+// credentials are redacted, copying each span once however many details quote it
+func (engine *DefaultAlignmentEngine) redactSpanContexts(result *models.AlignmentResult) {
+	redactor := engine.config.Redactor
+	if redactor == nil || result == nil {
+		return
+	}
+
+	redacted := make(map[*models.Span]*models.Span)
+	redact := func(details []models.ValidationDetail) {
+		for i := range details {
+			span := details[i].SpanContext
+			if span == nil {
+				continue
+			}
+			if _, ok := redacted[span]; !ok {
+				redacted[span] = redactor.Span(span)
+			}
+			details[i].SpanContext = redacted[span]
+		}
+	}
+	redact(result.Details)
+	for _, operation := range result.OperationResults {
+		if operation != nil {
+			redact(operation.Details)
+		}
+	}
+}
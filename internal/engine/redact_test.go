@@ -0,0 +1,91 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/scrub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// secretSpan returns a span carrying credentials in its attributes
+func secretSpan() *models.Span {
+	return &models.Span{
+		SpanID: "s1", TraceID: "t1", Name: "POST /login",
+		Status: models.SpanStatus{Code: "ERROR"},
+		Attributes: map[string]interface{}{
+			"http.method":                       "POST",
+			"http.request.header.authorization": "Basic dXNlcjpodW50ZXIy",
+			"http.response.header.set-cookie":   "sid=s3ss10n",
+			"app.session_id":                    "sess-42",
+		},
+	}
+}
+
+func TestCreateDetailedValidationDetail_RedactsSecrets(t *testing.T) {
+	engine := NewAlignmentEngine()
+	span := secretSpan()
+	context := NewEvaluationContext(span, nil)
+	engine.populateEvaluationContext(context, span)
+	assertion := map[string]interface{}{"==": []interface{}{map[string]interface{}{"var": "span.status.code"}, "OK"}}
+	failed := &AssertionResult{Passed: false, Expected: "OK", Actual: "ERROR", Expression: `{"==":[{"var":"span.status.code"},"OK"]}`}
+
+	detail := engine.createDetailedValidationDetail("postcondition", assertion, failed, span, context)
+	quoted, err := json.Marshal(detail.ContextInfo)
+	require.NoError(t, err)
+	for _, secret := range []string{"dXNlcjpodW50ZXIy", "s3ss10n"} {
+		assert.NotContains(t, detail.Message, secret)
+		assert.NotContains(t, string(quoted), secret)
+	}
+	assert.Contains(t, detail.Message, "http.request.header.authorization: <token>")
+	assert.Contains(t, detail.Message, "app.session_id: sess-42", "only credentials are redacted")
+	assert.Same(t, span, detail.SpanContext, "the span is redacted with the whole result")
+	assert.Equal(t, "Basic dXNlcjpodW50ZXIy", span.Attributes["http.request.header.authorization"], "the trace is left unchanged")
+
+	redactor, err := scrub.NewSecretRedactor([]string{"*set-cookie"}, []string{"*session_id"})
+	require.NoError(t, err)
+	engine.config.Redactor = redactor
+	detail = engine.createDetailedValidationDetail("postcondition", assertion, failed, span, context)
+	assert.Contains(t, detail.Message, "app.session_id: <token>")
+	assert.Contains(t, detail.Message, "http.response.header.set-cookie: sid=s3ss10n")
+
+	engine.config.Redactor = nil
+	detail = engine.createDetailedValidationDetail("postcondition", assertion, failed, span, context)
+	assert.Contains(t, detail.Message, "http.request.header.authorization: Basic dXNlcjpodW50ZXIy")
+}
+
+func TestRedactSpanContexts(t *testing.T) {
+	span := secretSpan()
+	result := models.NewAlignmentResult("login")
+	status := models.NewValidationDetail("status_code", "status", 200, 500, "Status code 500 does not match any expected values")
+	status.SpanContext = span
+	header := models.NewValidationDetail("required_header", "presence", "present", "present", "Required header 'authorization' is present")
+	header.SpanContext = span
+	result.Details = []models.ValidationDetail{*status, *header}
+	result.OperationResults = map[string]*models.OperationResult{"POST /login": {Details: []models.ValidationDetail{*status}}}
+
+	NewAlignmentEngine().redactSpanContexts(result)
+	redacted := result.Details[0].SpanContext
+	assert.Equal(t, "<token>", redacted.Attributes["http.request.header.authorization"])
+	assert.Equal(t, "<token>", redacted.Attributes["http.response.header.set-cookie"])
+	assert.Equal(t, "POST", redacted.Attributes["http.method"])
+	assert.Same(t, redacted, result.Details[1].SpanContext, "a span is copied once")
+	assert.Same(t, redacted, result.OperationResults["POST /login"].Details[0].SpanContext)
+	assert.Equal(t, "sid=s3ss10n", span.Attributes["http.response.header.set-cookie"], "the trace is left unchanged")
+}
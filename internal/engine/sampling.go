@@ -0,0 +1,88 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import "github.com/flowspec/flowspec-cli/internal/models"
+
+// traceSampling holds the head sampling of the traces of one run
+type traceSampling struct {
+	info  *models.Sampling
+	rates map[string]float64 // Declared probability by trace ID; other traces are kept at info.Rate
+}
+
+// traceSampling returns the head sampling of the trace data, or nil when every trace was kept.
+// TraceSampleRate takes precedence over span attributes; a rate of 1 ignores them.
+func (engine *DefaultAlignmentEngine) traceSampling(traceData *models.TraceData) *traceSampling {
+	if rate := engine.config.TraceSampleRate; rate > 0 {
+		if rate >= 1 {
+			return nil
+		}
+		return &traceSampling{info: &models.Sampling{
+			Rate: rate, Source: models.SamplingSourceConfig, Confidence: models.SamplingConfidence,
+		}}
+	}
+
+	rates := traceData.SampleRates()
+	if len(rates) == 0 {
+		return nil
+	}
+	total := 0.0
+	for _, rate := range rates {
+		total += rate
+	}
+	mean := total / float64(len(rates))
+	if mean >= 1 {
+		return nil
+	}
+	return &traceSampling{
+		info: &models.Sampling{
+			Rate: mean, Source: models.SamplingSourceAttributes, Traces: len(rates), Confidence: models.SamplingConfidence,
+		},
+		rates: rates,
+	}
+}
+
+// rate returns the probability with which the trace of a span was kept
+func (sampling *traceSampling) rate(span *models.Span) float64 {
+	if rate, ok := sampling.rates[span.TraceID]; ok {
+		return rate
+	}
+	return sampling.info.Rate
+}
+
+// applySampling extrapolates the operation results of a spec to the traffic the sampled traces
+// stand for: each matched span counts for the inverse of the probability its trace was kept
+// with, the failure rate gets a confidence interval, and operations no span matched get the
+// most requests that may have been sampled out. Statuses are left to the observed counts.
+func (sampling *traceSampling) applySampling(result *models.AlignmentResult, traceData *models.TraceData) {
+	if sampling == nil {
+		return
+	}
+	for _, operationResult := range result.OperationResults {
+		if operationResult.SampleCount == 0 {
+			operationResult.UnseenRequests = models.UnseenRequestsBound(sampling.info.Rate)
+			continue
+		}
+		estimated := 0.0
+		for _, spanID := range operationResult.MatchedSpans {
+			if span, ok := traceData.Spans[spanID]; ok {
+				estimated += 1 / sampling.rate(span)
+			}
+		}
+		operationResult.EstimatedRequests = estimated
+		failing, _ := failingSamples(operationResult.Details)
+		operationResult.FailureRateInterval = models.FailureRateInterval(failing, operationResult.SampleCount)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSamplingTestData returns the strict mode test data with a failing GET /users/{id} span in
+// a second trace
+func newSamplingTestData() (models.ServiceSpec, *models.TraceData) {
+	spec, traceData := newStrictModeTestData()
+	traceData.Spans["failing"] = &models.Span{
+		SpanID: "failing", TraceID: "trace2", Name: "GET /users/{id}",
+		Attributes: map[string]interface{}{"http.method": "GET", "http.target": "/users/7", "http.status_code": 500},
+	}
+	return spec, traceData
+}
+
+func TestAlignmentEngine_TraceSampleRate(t *testing.T) {
+	spec, traceData := newSamplingTestData()
+	config := DefaultEngineConfig()
+	config.TraceSampleRate = 0.1
+	report, err := NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	require.NoError(t, err)
+
+	assert.Equal(t, &models.Sampling{Rate: 0.1, Source: models.SamplingSourceConfig, Confidence: 0.95}, report.Sampling)
+	users := report.Results[0].OperationResults["GET /users/{id}"]
+	assert.Equal(t, 2, users.SampleCount, "counts stay those of the kept traces")
+	assert.InDelta(t, 20, users.EstimatedRequests, 1e-9)
+	assert.Equal(t, 0.5, users.FailureRate)
+	assert.Equal(t, models.FailureRateInterval(1, 2), users.FailureRateInterval)
+	assert.Zero(t, users.UnseenRequests)
+
+	health := report.Results[0].OperationResults["GET /health"]
+	assert.Zero(t, health.EstimatedRequests)
+	assert.Nil(t, health.FailureRateInterval)
+	assert.Equal(t, 28, health.UnseenRequests)
+
+	require.NotNil(t, report.Coverage)
+	for _, operation := range report.Coverage.Operations {
+		switch operation.Operation {
+		case "GET /users/{id}":
+			assert.InDelta(t, 20, operation.EstimatedRequests, 1e-9)
+		case "GET /health":
+			assert.False(t, operation.Covered)
+			assert.Equal(t, 28, operation.UnseenRequests)
+		}
+	}
+}
+
+func TestAlignmentEngine_SamplingAttributes(t *testing.T) {
+	spec, traceData := newSamplingTestData()
+	traceData.Spans["internal"].Attributes[models.SamplingProbabilityAttribute] = 0.25
+	traceData.Spans["failing"].Attributes[models.SampleRateAttribute] = 2
+
+	report, err := NewAlignmentEngine().AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	require.NoError(t, err)
+	require.NotNil(t, report.Sampling)
+	assert.Equal(t, models.SamplingSourceAttributes, report.Sampling.Source)
+	assert.Equal(t, 2, report.Sampling.Traces)
+	assert.InDelta(t, 0.375, report.Sampling.Rate, 1e-9)
+	users := report.Results[0].OperationResults["GET /users/{id}"]
+	assert.InDelta(t, 4+2, users.EstimatedRequests, 1e-9, "each span counts for the rate of its own trace")
+
+	config := DefaultEngineConfig()
+	config.TraceSampleRate = 1
+	report, err = NewAlignmentEngineWithConfig(config).AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	require.NoError(t, err)
+	assert.Nil(t, report.Sampling, "a rate of 1 ignores span attributes")
+	assert.Zero(t, report.Results[0].OperationResults["GET /users/{id}"].EstimatedRequests)
+}
+
+func TestAlignmentEngine_Unsampled(t *testing.T) {
+	spec, traceData := newSamplingTestData()
+	report, err := NewAlignmentEngine().AlignSpecsWithTrace([]models.ServiceSpec{spec}, traceData)
+	require.NoError(t, err)
+	assert.Nil(t, report.Sampling)
+	assert.Nil(t, report.Results[0].OperationResults["GET /users/{id}"].FailureRateInterval)
+	assert.Zero(t, report.Results[0].OperationResults["GET /health"].UnseenRequests)
+}
+
+func TestValidateEngineConfig_TraceSampleRate(t *testing.T) {
+	config := DefaultEngineConfig()
+	for _, rate := range []float64{-0.1, 1.5} {
+		config.TraceSampleRate = rate
+		assert.Error(t, ValidateEngineConfig(config), "rate %g", rate)
+	}
+	config.TraceSampleRate = 0.01
+	assert.NoError(t, ValidateEngineConfig(config))
+}
+
+func TestMergeSampling(t *testing.T) {
+	merged := mergeSampling(nil, &models.Sampling{Rate: 0.5, Source: models.SamplingSourceAttributes, Traces: 1, Confidence: 0.95})
+	merged = mergeSampling(merged, &models.Sampling{Rate: 0.2, Source: models.SamplingSourceAttributes, Traces: 3, Confidence: 0.95})
+	assert.InDelta(t, 0.275, merged.Rate, 1e-9)
+	assert.Equal(t, 4, merged.Traces)
+
+	configured := mergeSampling(nil, &models.Sampling{Rate: 0.1, Source: models.SamplingSourceConfig, Confidence: 0.95})
+	assert.Equal(t, 0.1, mergeSampling(configured, &models.Sampling{Rate: 0.1, Source: models.SamplingSourceConfig, Confidence: 0.95}).Rate)
+}
@@ -0,0 +1,115 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadSuggestionRules reads a YAML file with a top-level rules list of suggestion rules
+func LoadSuggestionRules(path string) (models.SuggestionRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suggestion rules %s: %w", path, err)
+	}
+
+	var file struct {
+		Rules models.SuggestionRules `yaml:"rules"`
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse suggestion rules %s: %w", path, err)
+	}
+	if len(file.Rules) == 0 {
+		return nil, fmt.Errorf("suggestion rules %s lists no rules", path)
+	}
+	if err := file.Rules.Validate(); err != nil {
+		return nil, fmt.Errorf("suggestion rules %s: %w", path, err)
+	}
+	return file.Rules, nil
+}
+
+// recordFailureFacts records in the contextInfo of a failed assertion the variables it reads
+// and whether it failed on a missing value, on values of different types or on their values,
+// for suggestion rules to match
+func recordFailureFacts(detail *models.ValidationDetail, assertion map[string]interface{}, expected, actual interface{}, context *EvaluationContext) {
+	keys := models.ExpressionVariables(assertion)
+	failure := models.FailureValueMismatch
+	for _, key := range keys {
+		if _, exists := lookupVariable(context, key); !exists {
+			failure = models.FailureMissing
+			break
+		}
+	}
+	if failure != models.FailureMissing && expected != nil && actual != nil && valueKind(expected) != valueKind(actual) {
+		failure = models.FailureTypeMismatch
+	}
+
+	if detail.ContextInfo == nil {
+		detail.ContextInfo = make(map[string]interface{})
+	}
+	detail.ContextInfo["failure"] = failure
+	if len(keys) > 0 {
+		detail.ContextInfo["keys"] = keys
+	}
+}
+
+// lookupVariable returns a context variable by name, or by its JSONLogic-safe name
+func lookupVariable(context *EvaluationContext, name string) (interface{}, bool) {
+	if value, exists := context.GetVariable(name); exists {
+		return value, true
+	}
+	return context.GetVariable(strings.ReplaceAll(name, ".", "_"))
+}
+
+// valueKind classifies a value for type comparisons, every numeric type being a number
+func valueKind(value interface{}) string {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map:
+		return "object"
+	}
+	return reflect.TypeOf(value).String()
+}
+
+// applySuggestionRules adds the suggestions of the configured rules to the failed details of a
+// result and of its operation results
+func (engine *DefaultAlignmentEngine) applySuggestionRules(result *models.AlignmentResult) {
+	rules := engine.config.SuggestionRules
+	if len(rules) == 0 {
+		return
+	}
+	for i := range result.Details {
+		rules.Apply(&result.Details[i])
+	}
+	for _, operation := range result.OperationResults {
+		for i := range operation.Details {
+			rules.Apply(&operation.Details[i])
+		}
+	}
+}
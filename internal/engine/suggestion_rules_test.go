@@ -0,0 +1,119 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSuggestionRules(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	rules, err := LoadSuggestionRules(write("rules.yaml", `rules:
+  - name: tenant
+    failure: missing
+    key: "*x-tenant*"
+    suggestion: Set X-Tenant at the gateway
+    runbook: https://runbooks.example.com/tenant
+`))
+	require.NoError(t, err)
+	assert.Equal(t, models.SuggestionRules{{Name: "tenant", Failure: "missing", Key: "*x-tenant*",
+		Suggestion: "Set X-Tenant at the gateway", Runbook: "https://runbooks.example.com/tenant"}}, rules)
+
+	_, err = LoadSuggestionRules(write("empty.yaml", "rules: []\n"))
+	assert.ErrorContains(t, err, "lists no rules")
+	_, err = LoadSuggestionRules(write("unknown.yaml", "rules:\n  - name: tenant\n    text: x\n"))
+	assert.ErrorContains(t, err, "field text not found")
+	_, err = LoadSuggestionRules(write("invalid.yaml", "rules:\n  - name: tenant\n"))
+	assert.ErrorContains(t, err, "rule 1 (tenant): suggestion is required")
+	_, err = LoadSuggestionRules(filepath.Join(dir, "absent.yaml"))
+	assert.ErrorContains(t, err, "failed to read suggestion rules")
+}
+
+func TestCreateDetailedValidationDetail_RecordsFailureFacts(t *testing.T) {
+	engine := NewAlignmentEngine()
+	span := &models.Span{SpanID: "s1", TraceID: "t1", Name: "GET /orders",
+		Attributes: map[string]interface{}{"http.status_code": "200"}}
+	context := NewEvaluationContext(span, nil)
+	engine.populateEvaluationContext(context, span)
+	failed := &AssertionResult{Passed: false, Expected: true, Actual: false}
+
+	testCases := []struct {
+		name      string
+		assertion map[string]interface{}
+		failure   string
+		keys      []string
+	}{
+		{
+			name:      "missing attribute",
+			assertion: map[string]interface{}{"==": []interface{}{map[string]interface{}{"var": "span.attributes.x-tenant"}, "acme"}},
+			failure:   models.FailureMissing,
+			keys:      []string{"span.attributes.x-tenant"},
+		},
+		{
+			name:      "type mismatch",
+			assertion: map[string]interface{}{"==": []interface{}{map[string]interface{}{"var": "span.attributes.http.status_code"}, 200}},
+			failure:   models.FailureTypeMismatch,
+			keys:      []string{"span.attributes.http.status_code"},
+		},
+		{
+			name:      "value mismatch",
+			assertion: map[string]interface{}{"==": []interface{}{map[string]interface{}{"var": "span.attributes.http.status_code"}, "201"}},
+			failure:   models.FailureValueMismatch,
+			keys:      []string{"span.attributes.http.status_code"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			detail := engine.createDetailedValidationDetail("postcondition", tc.assertion, failed, span, context)
+			assert.Equal(t, tc.failure, detail.ContextInfo["failure"])
+			assert.Equal(t, tc.keys, detail.ContextInfo["keys"])
+		})
+	}
+}
+
+func TestApplySuggestionRules(t *testing.T) {
+	result := models.NewAlignmentResult("orders")
+	header := models.NewValidationDetail("required_header", "presence", "present", "missing", "Required header 'X-Tenant' is missing")
+	header.ContextInfo = map[string]interface{}{"failure": models.FailureMissing, "keys": []string{"X-Tenant"}}
+	header.Suggestions = []string{"Add the header"}
+	passed := models.NewValidationDetail("required_header", "presence", "present", "present", "Required header 'X-Tenant' is present")
+	result.Details = []models.ValidationDetail{*header, *passed}
+	result.OperationResults = map[string]*models.OperationResult{"GET /orders": {Details: []models.ValidationDetail{*header}}}
+
+	engine := NewAlignmentEngine()
+	engine.applySuggestionRules(result)
+	assert.Equal(t, []string{"Add the header"}, result.Details[0].Suggestions, "no rules are configured")
+
+	engine.config.SuggestionRules = models.SuggestionRules{{Name: "tenant", Key: "x-tenant", Suggestion: "Set X-Tenant at the gateway",
+		Runbook: "https://runbooks.example.com/tenant"}}
+	engine.applySuggestionRules(result)
+	expected := []string{"Set X-Tenant at the gateway (runbook: https://runbooks.example.com/tenant)", "Add the header"}
+	assert.Equal(t, expected, result.Details[0].Suggestions)
+	assert.Empty(t, result.Details[1].Suggestions)
+	assert.Equal(t, expected, result.OperationResults["GET /orders"].Details[0].Suggestions)
+}
@@ -0,0 +1,152 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diegoholiveira/jsonlogic/v3"
+)
+
+// Time helper operators available to assertions
+const (
+	// InTimeWindowOperator checks that a time of day falls in a daily window:
+	// {"in_time_window": [{"var": "span.start"}, "22:00", "02:00"]}
+	InTimeWindowOperator = "in_time_window"
+	// InPeriodOperator checks that an instant falls in a period:
+	// {"in_period": [{"var": "span.start"}, "2025-06-01T22:00:00Z", "2025-06-02T02:00:00Z"]}
+	InPeriodOperator = "in_period"
+)
+
+func init() {
+	jsonlogic.AddOperator(InTimeWindowOperator, inTimeWindow)
+	jsonlogic.AddOperator(InPeriodOperator, inPeriod)
+}
+
+// timeVariables breaks a span timestamp in Unix nanoseconds down into the variables exposed
+// as span.start and span.end, in the given zone; UTC when nil
+func timeVariables(nanos int64, location *time.Location) map[string]interface{} {
+	if location == nil {
+		location = time.UTC
+	}
+	at := time.Unix(0, nanos).In(location)
+	weekday := int(at.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	return map[string]interface{}{
+		"iso":            at.Format(time.RFC3339Nano),
+		"unix":           at.Unix(),
+		"date":           at.Format(time.DateOnly),
+		"time":           at.Format(time.TimeOnly),
+		"year":           at.Year(),
+		"month":          int(at.Month()),
+		"day":            at.Day(),
+		"hour":           at.Hour(),
+		"minute":         at.Minute(),
+		"weekday":        strings.ToLower(at.Weekday().String()),
+		"weekday_number": weekday, // ISO 8601: 1 for Monday to 7 for Sunday
+		"is_weekend":     weekday >= 6,
+	}
+}
+
+// inTimeWindow implements in_time_window: whether a time of day, given as span time
+// variables or "15:04[:05]", is at or after the start of the window and before its end. A
+// window ending before it starts spans midnight.
+func inTimeWindow(values, data interface{}) interface{} {
+	args := operatorArguments(InTimeWindowOperator, values, 3)
+	at, err := timeOfDay(args[0])
+	if err != nil {
+		panic(fmt.Errorf("%s: %w", InTimeWindowOperator, err))
+	}
+	from, err := timeOfDay(args[1])
+	if err != nil {
+		panic(fmt.Errorf("%s: invalid window start: %w", InTimeWindowOperator, err))
+	}
+	to, err := timeOfDay(args[2])
+	if err != nil {
+		panic(fmt.Errorf("%s: invalid window end: %w", InTimeWindowOperator, err))
+	}
+	if from <= to {
+		return from <= at && at < to
+	}
+	return at >= from || at < to
+}
+
+// inPeriod implements in_period: whether an instant, given as span time variables, an RFC 3339
+// timestamp or Unix seconds, is at or after the start of the period and before its end
+func inPeriod(values, data interface{}) interface{} {
+	args := operatorArguments(InPeriodOperator, values, 3)
+	at, err := instant(args[0])
+	if err != nil {
+		panic(fmt.Errorf("%s: %w", InPeriodOperator, err))
+	}
+	from, err := instant(args[1])
+	if err != nil {
+		panic(fmt.Errorf("%s: invalid period start: %w", InPeriodOperator, err))
+	}
+	to, err := instant(args[2])
+	if err != nil {
+		panic(fmt.Errorf("%s: invalid period end: %w", InPeriodOperator, err))
+	}
+	return !at.Before(from) && at.Before(to)
+}
+
+// operatorArguments returns the evaluated arguments of an operator, which must have count of them
+func operatorArguments(operator string, values interface{}, count int) []interface{} {
+	args, ok := values.([]interface{})
+	if !ok || len(args) != count {
+		panic(fmt.Errorf("%s expects %d arguments", operator, count))
+	}
+	return args
+}
+
+// timeOfDay returns the seconds since midnight of span time variables or a "15:04[:05]" string
+func timeOfDay(value interface{}) (int, error) {
+	if variables, ok := value.(map[string]interface{}); ok {
+		value = variables["time"]
+	}
+	text, ok := value.(string)
+	if !ok {
+		return 0, fmt.Errorf("expected a time of day such as \"22:00\", got %v", value)
+	}
+	for _, layout := range []string{time.TimeOnly, "15:04"} {
+		if parsed, err := time.Parse(layout, text); err == nil {
+			return parsed.Hour()*3600 + parsed.Minute()*60 + parsed.Second(), nil
+		}
+	}
+	return 0, fmt.Errorf("expected a time of day such as \"22:00\", got %q", text)
+}
+
+// instant returns the time of span time variables, an RFC 3339 timestamp or Unix seconds
+func instant(value interface{}) (time.Time, error) {
+	if variables, ok := value.(map[string]interface{}); ok {
+		value = variables["iso"]
+	}
+	switch v := value.(type) {
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("expected an RFC 3339 timestamp, got %q", v)
+		}
+		return parsed, nil
+	case int, int64, float64:
+		seconds := toFloat64(v)
+		return time.Unix(0, int64(seconds*float64(time.Second))), nil
+	}
+	return time.Time{}, fmt.Errorf("expected an RFC 3339 timestamp or Unix seconds, got %v", value)
+}
@@ -0,0 +1,191 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// maintenanceSpan started on Sunday 2025-06-01 at 23:30 UTC and failed with a 503
+func maintenanceSpan() *models.Span {
+	start := time.Date(2025, 6, 1, 23, 30, 0, 0, time.UTC)
+	return &models.Span{
+		SpanID:     "span-1",
+		TraceID:    "trace-1",
+		Name:       "GET /orders",
+		StartTime:  start.UnixNano(),
+		EndTime:    start.Add(45 * time.Minute).UnixNano(),
+		Attributes: map[string]interface{}{"http.status_code": 503},
+	}
+}
+
+func TestTimeVariables(t *testing.T) {
+	start := maintenanceSpan().StartTime
+
+	utc := timeVariables(start, nil)
+	assert.Equal(t, "2025-06-01T23:30:00Z", utc["iso"])
+	assert.Equal(t, "2025-06-01", utc["date"])
+	assert.Equal(t, "23:30:00", utc["time"])
+	assert.Equal(t, 23, utc["hour"])
+	assert.Equal(t, "sunday", utc["weekday"])
+	assert.Equal(t, 7, utc["weekday_number"])
+	assert.Equal(t, true, utc["is_weekend"])
+
+	berlin := timeVariables(start, time.FixedZone("CEST", 2*60*60))
+	assert.Equal(t, "2025-06-02T01:30:00+02:00", berlin["iso"])
+	assert.Equal(t, 1, berlin["hour"])
+	assert.Equal(t, 30, berlin["minute"])
+	assert.Equal(t, "monday", berlin["weekday"])
+	assert.Equal(t, 1, berlin["weekday_number"])
+	assert.Equal(t, false, berlin["is_weekend"])
+	assert.Equal(t, utc["unix"], berlin["unix"])
+}
+
+func TestTimeAssertions(t *testing.T) {
+	evaluator := NewJSONLogicEvaluator()
+	span := maintenanceSpan()
+
+	testCases := []struct {
+		name      string
+		assertion map[string]interface{}
+		location  *time.Location
+		expected  bool
+	}{
+		{
+			name:      "hour of day",
+			assertion: map[string]interface{}{">=": []interface{}{map[string]interface{}{"var": "span.start.hour"}, 22}},
+			expected:  true,
+		},
+		{
+			name:      "hour of day in zone",
+			assertion: map[string]interface{}{">=": []interface{}{map[string]interface{}{"var": "span.start.hour"}, 22}},
+			location:  time.FixedZone("CEST", 2*60*60),
+			expected:  false,
+		},
+		{
+			name:      "weekend",
+			assertion: map[string]interface{}{"in": []interface{}{map[string]interface{}{"var": "span.start.weekday"}, []interface{}{"saturday", "sunday"}}},
+			expected:  true,
+		},
+		{
+			name:      "window spanning midnight",
+			assertion: map[string]interface{}{"in_time_window": []interface{}{map[string]interface{}{"var": "span.start"}, "22:00", "02:00"}},
+			expected:  true,
+		},
+		{
+			name:      "window ending before the span",
+			assertion: map[string]interface{}{"in_time_window": []interface{}{map[string]interface{}{"var": "span.start"}, "09:00", "17:00"}},
+			expected:  false,
+		},
+		{
+			name:      "window end is exclusive",
+			assertion: map[string]interface{}{"in_time_window": []interface{}{map[string]interface{}{"var": "span.end"}, "22:00", "00:15"}},
+			expected:  false,
+		},
+		{
+			name:      "window of a time of day",
+			assertion: map[string]interface{}{"in_time_window": []interface{}{map[string]interface{}{"var": "span.start.time"}, "23:00", "23:59:59"}},
+			expected:  true,
+		},
+		{
+			name:      "period",
+			assertion: map[string]interface{}{"in_period": []interface{}{map[string]interface{}{"var": "span.start"}, "2025-06-01T22:00:00Z", "2025-06-02T02:00:00Z"}},
+			expected:  true,
+		},
+		{
+			name:      "period in another zone",
+			assertion: map[string]interface{}{"in_period": []interface{}{map[string]interface{}{"var": "span.start"}, "2025-06-02T00:00:00+02:00", "2025-06-02T01:00:00+02:00"}},
+			expected:  false,
+		},
+		{
+			name:      "period of Unix seconds",
+			assertion: map[string]interface{}{"in_period": []interface{}{map[string]interface{}{"var": "span.start.unix"}, "2025-06-01T23:00:00Z", "2025-06-01T23:30:01Z"}},
+			expected:  true,
+		},
+		{
+			name: "5xx only during the maintenance window",
+			assertion: map[string]interface{}{"or": []interface{}{
+				map[string]interface{}{"<": []interface{}{map[string]interface{}{"var": "span.attributes.http.status_code"}, 500}},
+				map[string]interface{}{"in_time_window": []interface{}{map[string]interface{}{"var": "span.start"}, "23:00", "01:00"}},
+			}},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			context := NewEvaluationContext(span, nil)
+			context.Location = tc.location
+			result, err := evaluator.EvaluateAssertion(tc.assertion, context)
+			require.NoError(t, err)
+			require.NoError(t, result.Error)
+			assert.Equal(t, tc.expected, result.Passed)
+		})
+	}
+}
+
+func TestTimeAssertions_InvalidArguments(t *testing.T) {
+	evaluator := NewJSONLogicEvaluator()
+
+	testCases := []struct {
+		name      string
+		assertion map[string]interface{}
+		errorMsg  string
+	}{
+		{
+			name:      "bad window start",
+			assertion: map[string]interface{}{"in_time_window": []interface{}{map[string]interface{}{"var": "span.start"}, "25:00", "02:00"}},
+			errorMsg:  "in_time_window: invalid window start",
+		},
+		{
+			name:      "missing window end",
+			assertion: map[string]interface{}{"in_time_window": []interface{}{map[string]interface{}{"var": "span.start"}, "22:00"}},
+			errorMsg:  "in_time_window expects 3 arguments",
+		},
+		{
+			name:      "bad period end",
+			assertion: map[string]interface{}{"in_period": []interface{}{map[string]interface{}{"var": "span.start"}, "2025-06-01T22:00:00Z", "tomorrow"}},
+			errorMsg:  "in_period: invalid period end",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := evaluator.EvaluateAssertion(tc.assertion, NewEvaluationContext(maintenanceSpan(), nil))
+			require.NoError(t, err)
+			assert.False(t, result.Passed)
+			require.Error(t, result.Error)
+			assert.Contains(t, result.Error.Error(), tc.errorMsg)
+		})
+	}
+}
+
+func TestAlignmentEngine_TimeZone(t *testing.T) {
+	config := DefaultEngineConfig()
+	config.TimeZone = time.FixedZone("CEST", 2*60*60)
+	engine := NewAlignmentEngineWithConfig(config)
+
+	context := engine.NewSpanEvaluationContext(maintenanceSpan(), nil)
+	result, err := engine.GetEvaluator().EvaluateAssertion(map[string]interface{}{
+		"==": []interface{}{map[string]interface{}{"var": "span.start.weekday"}, "monday"},
+	}, context)
+	require.NoError(t, err)
+	assert.True(t, result.Passed)
+}
@@ -0,0 +1,92 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// VariablePrefix is the namespace under which external variables are exposed to assertions
+const VariablePrefix = "vars."
+
+// PathParameterPrefix is the namespace under which path parameters of the request are exposed
+// to assertions, e.g. path.id for /users/{id}
+const PathParameterPrefix = "path."
+
+// ParseVariableAssignments parses --var style "key=value" assignments into a variable map.
+// Values are kept as strings so that versions like "1.10" are not coerced into numbers.
+func ParseVariableAssignments(assignments []string) (map[string]interface{}, error) {
+	vars := make(map[string]interface{})
+	for _, assignment := range assignments {
+		key, value, found := strings.Cut(assignment, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid variable assignment %q, expected key=value", assignment)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// LoadVariableFile loads variables from a YAML (or JSON) file containing a top-level mapping
+func LoadVariableFile(path string) (map[string]interface{}, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read variable file %s: %w", path, err)
+	}
+
+	vars := make(map[string]interface{})
+	if err := yaml.Unmarshal(content, &vars); err != nil {
+		return nil, fmt.Errorf("failed to parse variable file %s: %w", path, err)
+	}
+
+	return vars, nil
+}
+
+// MergeVariables merges variable maps, later maps taking precedence over earlier ones
+func MergeVariables(sources ...map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{})
+	for _, source := range sources {
+		for key, value := range source {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// ResolveVariables builds the external variable set from --var-file paths and --var assignments.
+// Files are applied in order and explicit assignments override values loaded from files.
+func ResolveVariables(files []string, assignments []string) (map[string]interface{}, error) {
+	sources := make([]map[string]interface{}, 0, len(files)+1)
+	for _, file := range files {
+		vars, err := LoadVariableFile(file)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, vars)
+	}
+
+	vars, err := ParseVariableAssignments(assignments)
+	if err != nil {
+		return nil, err
+	}
+	sources = append(sources, vars)
+
+	return MergeVariables(sources...), nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVariableAssignments(t *testing.T) {
+	testCases := []struct {
+		name        string
+		assignments []string
+		expected    map[string]interface{}
+		expectError bool
+	}{
+		{
+			name:        "simple assignments",
+			assignments: []string{"region=us-east-1", "build=1.10"},
+			expected:    map[string]interface{}{"region": "us-east-1", "build": "1.10"},
+		},
+		{
+			name:        "value containing equals sign",
+			assignments: []string{"query=a=b"},
+			expected:    map[string]interface{}{"query": "a=b"},
+		},
+		{
+			name:        "empty value",
+			assignments: []string{"suffix="},
+			expected:    map[string]interface{}{"suffix": ""},
+		},
+		{
+			name:        "missing equals sign",
+			assignments: []string{"region"},
+			expectError: true,
+		},
+		{
+			name:        "empty key",
+			assignments: []string{"=value"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vars, err := ParseVariableAssignments(tc.assignments)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, vars)
+		})
+	}
+}
+
+func TestResolveVariables(t *testing.T) {
+	dir := t.TempDir()
+	varFile := filepath.Join(dir, "vars.yaml")
+	content := "region: eu-west-1\nreplicas: 3\nbuild:\n  version: \"2.4.0\"\n"
+	require.NoError(t, os.WriteFile(varFile, []byte(content), 0644))
+
+	vars, err := ResolveVariables([]string{varFile}, []string{"region=us-east-1"})
+	require.NoError(t, err)
+
+	// Explicit assignments override file values
+	assert.Equal(t, "us-east-1", vars["region"])
+	assert.Equal(t, 3, vars["replicas"])
+	assert.Equal(t, map[string]interface{}{"version": "2.4.0"}, vars["build"])
+
+	_, err = ResolveVariables([]string{filepath.Join(dir, "missing.yaml")}, nil)
+	assert.Error(t, err)
+
+	badFile := filepath.Join(dir, "bad.yaml")
+	require.NoError(t, os.WriteFile(badFile, []byte("- not\n- a mapping\n"), 0644))
+	_, err = ResolveVariables([]string{badFile}, nil)
+	assert.Error(t, err)
+}
+
+func TestExternalVariablesInAssertions(t *testing.T) {
+	config := DefaultEngineConfig()
+	config.Variables = map[string]interface{}{
+		"region": "us-east-1",
+		"build":  map[string]interface{}{"version": "2.4.0"},
+	}
+	engine := NewAlignmentEngineWithConfig(config)
+
+	span := &models.Span{
+		SpanID:  "span-1",
+		TraceID: "trace-1",
+		Name:    "GET /users",
+		Attributes: map[string]interface{}{
+			"cloud.region":    "us-east-1",
+			"service.version": "2.4.0",
+		},
+	}
+
+	context := NewEvaluationContext(span, nil)
+	engine.populateEvaluationContext(context, span)
+
+	region, exists := context.GetVariable("vars.region")
+	assert.True(t, exists)
+	assert.Equal(t, "us-east-1", region)
+
+	testCases := []struct {
+		name      string
+		assertion map[string]interface{}
+		passed    bool
+	}{
+		{
+			name: "region matches",
+			assertion: map[string]interface{}{
+				"==": []interface{}{
+					map[string]interface{}{"var": "span.attributes.cloud.region"},
+					map[string]interface{}{"var": "vars.region"},
+				},
+			},
+			passed: true,
+		},
+		{
+			name: "nested file variable",
+			assertion: map[string]interface{}{
+				"==": []interface{}{
+					map[string]interface{}{"var": "span.attributes.service.version"},
+					map[string]interface{}{"var": "vars.build.version"},
+				},
+			},
+			passed: true,
+		},
+		{
+			name: "mismatch fails",
+			assertion: map[string]interface{}{
+				"==": []interface{}{
+					map[string]interface{}{"var": "vars.region"},
+					"eu-west-1",
+				},
+			},
+			passed: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := engine.GetEvaluator().EvaluateAssertion(tc.assertion, context)
+			require.NoError(t, err)
+			assert.Equal(t, tc.passed, result.Passed)
+		})
+	}
+}
+
+func TestPathParametersInAssertions(t *testing.T) {
+	engine := NewAlignmentEngine()
+	span := &models.Span{
+		SpanID:  "span-1",
+		TraceID: "trace-1",
+		Name:    "GET /users/:id",
+		Attributes: map[string]interface{}{
+			"http.route":  "/users/:id/orders/:orderId",
+			"http.target": "/users/42/orders/a%20b?expand=items",
+			"user.id":     "42",
+		},
+	}
+
+	context := NewEvaluationContext(span, nil)
+	engine.populateEvaluationContext(context, span)
+
+	id, exists := context.GetVariable("path.id")
+	assert.True(t, exists)
+	assert.Equal(t, "42", id)
+	orderID, _ := context.GetVariable("path.orderId")
+	assert.Equal(t, "a b", orderID, "values are unescaped")
+
+	// The contract path names the parameters when the operation is known
+	engine.populatePathParameters(context, span, "/users/{userId}/orders/{orderId}")
+	userID, _ := context.GetVariable("path.userId")
+	assert.Equal(t, "42", userID)
+
+	assertion := map[string]interface{}{
+		"==": []interface{}{
+			map[string]interface{}{"var": "path.id"},
+			map[string]interface{}{"var": "span.attributes.user.id"},
+		},
+	}
+	result, err := engine.GetEvaluator().EvaluateAssertion(assertion, context)
+	require.NoError(t, err)
+	assert.True(t, result.Passed)
+}
@@ -0,0 +1,262 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package errcode gives every failure of the CLI a stable, machine-readable code such as
+// FS-TRACE-004, so wrappers can branch on the category of a failure instead of matching the
+// free-text message. With --ci the failure is written to stderr as a JSON object.
+package errcode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/httpclient"
+	"github.com/flowspec/flowspec-cli/internal/ingestor/traffic"
+	"github.com/flowspec/flowspec-cli/internal/membudget"
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/parser"
+	"github.com/flowspec/flowspec-cli/internal/remote"
+	"github.com/flowspec/flowspec-cli/internal/renderer"
+	"github.com/flowspec/flowspec-cli/internal/selfupdate"
+	"github.com/flowspec/flowspec-cli/internal/shutdown"
+	"github.com/flowspec/flowspec-cli/internal/signing"
+)
+
+// Code identifies a category of failure; codes are never reused or renumbered
+type Code string
+
+const (
+	ContractUnreadable   Code = "FS-PARSE-001"    // The contract path does not exist or cannot be read
+	ContractInvalid      Code = "FS-PARSE-002"    // A contract has syntax or schema errors
+	ContractSignature    Code = "FS-PARSE-003"    // A contract is unsigned, signed with another key or changed since it was signed
+	TraceUnreadable      Code = "FS-TRACE-001"    // The trace path does not exist or cannot be read
+	TraceUnsupported     Code = "FS-TRACE-002"    // The trace format is not recognized
+	TraceMalformed       Code = "FS-TRACE-003"    // The trace cannot be decoded
+	TraceIncomplete      Code = "FS-TRACE-004"    // The trace is structurally broken and --fail-on-incomplete-trace is set
+	BundleInvalid        Code = "FS-BUNDLE-001"   // A bundle cannot be read or does not match its manifest
+	ConfigInvalid        Code = "FS-CONFIG-001"   // The configuration file cannot be read or is invalid
+	VersionMismatch      Code = "FS-CONFIG-002"   // The CLI version does not satisfy requireVersion
+	RemoteNotFound       Code = "FS-REMOTE-001"   // A remote contract does not exist
+	RemoteUnauthorized   Code = "FS-REMOTE-002"   // Credentials for a remote contract are missing or were rejected
+	RemoteChecksum       Code = "FS-REMOTE-003"   // A remote contract does not have its pinned checksum
+	RemoteUnavailable    Code = "FS-REMOTE-004"   // A remote contract stayed unreachable after retries
+	NetworkConfigInvalid Code = "FS-NET-001"      // Proxy, CA or client certificate settings are invalid
+	NetworkOffline       Code = "FS-NET-002"      // A command needed the network in offline mode
+	Interrupted          Code = "FS-RUN-001"      // The run was interrupted by a signal
+	MemoryBudgetExceeded Code = "FS-RUN-002"      // The run did not fit in --max-memory-mb
+	ErrorBudgetExceeded  Code = "FS-RUN-003"      // Too many traffic lines could not be parsed
+	Timeout              Code = "FS-RUN-004"      // A deadline expired
+	UpdateNotFound       Code = "FS-UPDATE-001"   // No release matches the channel, version or platform of self-update
+	UpdateVerification   Code = "FS-UPDATE-002"   // A release failed its checksum or signature verification
+	Internal             Code = "FS-INTERNAL-001" // Any other failure
+)
+
+// codeInfo describes a code and the exit code of its failures
+type codeInfo struct {
+	description string
+	exitCode    int
+}
+
+// catalog describes every code
+var catalog = map[Code]codeInfo{
+	ContractUnreadable:   {"contract path does not exist or cannot be read", renderer.ExitUsageError},
+	ContractInvalid:      {"contract has syntax or schema errors", renderer.ExitSpecFormatError},
+	ContractSignature:    {"contract signature is missing or does not verify", renderer.ExitSystemError},
+	TraceUnreadable:      {"trace path does not exist or cannot be read", renderer.ExitUsageError},
+	TraceUnsupported:     {"trace format is not recognized", renderer.ExitParseError},
+	TraceMalformed:       {"trace cannot be decoded", renderer.ExitParseError},
+	TraceIncomplete:      {"trace is structurally broken", renderer.ExitSystemError},
+	BundleInvalid:        {"bundle cannot be read or does not match its manifest", renderer.ExitUsageError},
+	ConfigInvalid:        {"configuration file cannot be read or is invalid", renderer.ExitUsageError},
+	VersionMismatch:      {"CLI version does not satisfy requireVersion", renderer.ExitUsageError},
+	RemoteNotFound:       {"remote contract does not exist", renderer.ExitUsageError},
+	RemoteUnauthorized:   {"remote contract credentials are missing or were rejected", renderer.ExitUsageError},
+	RemoteChecksum:       {"remote contract does not have its pinned checksum", renderer.ExitSystemError},
+	RemoteUnavailable:    {"remote contract stayed unreachable", renderer.ExitSystemError},
+	NetworkConfigInvalid: {"proxy or TLS settings are invalid", renderer.ExitUsageError},
+	NetworkOffline:       {"network access is disabled in offline mode", renderer.ExitUsageError},
+	Interrupted:          {"run was interrupted", renderer.ExitInterrupted},
+	MemoryBudgetExceeded: {"memory budget exceeded", renderer.ExitSystemError},
+	ErrorBudgetExceeded:  {"too many unparsable traffic lines", renderer.ExitSystemError},
+	Timeout:              {"deadline expired", renderer.ExitSystemError},
+	UpdateNotFound:       {"no matching release to update to", renderer.ExitUsageError},
+	UpdateVerification:   {"release failed checksum or signature verification", renderer.ExitSystemError},
+	Internal:             {"unexpected failure", renderer.ExitSystemError},
+}
+
+// Codes returns every code in order
+func Codes() []Code {
+	codes := make([]Code, 0, len(catalog))
+	for code := range catalog {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
+
+// Category returns the category of a code, e.g. "trace" for FS-TRACE-004
+func (c Code) Category() string {
+	parts := strings.Split(string(c), "-")
+	if len(parts) != 3 {
+		return ""
+	}
+	return strings.ToLower(parts[1])
+}
+
+// Description returns what a failure of the code means
+func (c Code) Description() string {
+	return catalog[c].description
+}
+
+// ExitCode returns the exit code of failures of the code
+func (c Code) ExitCode() int {
+	if info, ok := catalog[c]; ok {
+		return info.exitCode
+	}
+	return renderer.ExitSystemError
+}
+
+// Error is a failure with its code; errors.Is and errors.As see the wrapped error
+type Error struct {
+	Code Code
+	Err  error
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap attaches a code to an error: the code of a failure Of recognizes, or fallback for
+// failures only the caller can classify, such as a contract that does not parse. It returns
+// nil for a nil error.
+func Wrap(err error, fallback Code) error {
+	if err == nil {
+		return nil
+	}
+	var coded *Error
+	if errors.As(err, &coded) {
+		return err
+	}
+	code := recognize(err)
+	if code == "" {
+		code = fallback
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Of returns the code of an error: the code attached with Wrap, the code of a failure it
+// recognizes, or Internal. It returns "" for a nil error.
+func Of(err error) Code {
+	if err == nil {
+		return ""
+	}
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	if code := recognize(err); code != "" {
+		return code
+	}
+	return Internal
+}
+
+// recognize returns the code of the failures reported with a known sentinel or error type
+func recognize(err error) Code {
+	var formatError *parser.FormatDetectionError
+	switch {
+	case errors.Is(err, remote.ErrChecksumMismatch):
+		return RemoteChecksum
+	case errors.Is(err, signing.ErrMissingSignature), errors.Is(err, signing.ErrKeyMismatch), errors.Is(err, signing.ErrInvalidSignature):
+		return ContractSignature
+	case errors.Is(err, remote.ErrNotFound):
+		return RemoteNotFound
+	case errors.Is(err, remote.ErrUnauthorized):
+		return RemoteUnauthorized
+	case errors.Is(err, remote.ErrTransient):
+		return RemoteUnavailable
+	case errors.Is(err, selfupdate.ErrVersionMismatch):
+		return VersionMismatch
+	case errors.Is(err, selfupdate.ErrReleaseNotFound):
+		return UpdateNotFound
+	case errors.Is(err, selfupdate.ErrVerification):
+		return UpdateVerification
+	case errors.Is(err, httpclient.ErrInvalidConfig):
+		return NetworkConfigInvalid
+	case errors.Is(err, httpclient.ErrOffline):
+		return NetworkOffline
+	case errors.As(err, &formatError):
+		return TraceUnsupported
+	case errors.Is(err, models.ErrIncompleteTrace):
+		return TraceIncomplete
+	case errors.Is(err, shutdown.ErrInterrupted), errors.Is(err, context.Canceled):
+		return Interrupted
+	case errors.Is(err, membudget.ErrExceeded):
+		return MemoryBudgetExceeded
+	case errors.Is(err, traffic.ErrErrorBudgetExceeded):
+		return ErrorBudgetExceeded
+	case errors.Is(err, context.DeadlineExceeded):
+		return Timeout
+	}
+	return ""
+}
+
+// Report is the machine-readable description of a failure
+type Report struct {
+	Code     Code   `json:"code"`
+	Category string `json:"category"`
+	Message  string `json:"message"`
+	ExitCode int    `json:"exitCode"`
+}
+
+// NewReport describes a failure
+func NewReport(err error) Report {
+	code := Of(err)
+	return Report{Code: code, Category: code.Category(), Message: err.Error(), ExitCode: code.ExitCode()}
+}
+
+// ExitCode returns the exit code of a failure
+func ExitCode(err error) int {
+	if err == nil {
+		return renderer.ExitSuccess
+	}
+	return Of(err).ExitCode()
+}
+
+// Write reports a failure to w, typically stderr: in CI mode as one line of JSON,
+// {"error":{"code":"FS-TRACE-004","category":"trace","message":"...","exitCode":4}}, and
+// otherwise as "Error [FS-TRACE-004]: <message>"
+func Write(w io.Writer, err error, ci bool) error {
+	report := NewReport(err)
+	if !ci {
+		_, writeErr := fmt.Fprintf(w, "Error [%s]: %s\n", report.Code, report.Message)
+		return writeErr
+	}
+	data, marshalErr := json.Marshal(map[string]Report{"error": report})
+	if marshalErr != nil {
+		return fmt.Errorf("failed to marshal error report: %w", marshalErr)
+	}
+	_, writeErr := fmt.Fprintf(w, "%s\n", data)
+	return writeErr
+}
@@ -0,0 +1,113 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errcode
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/httpclient"
+	"github.com/flowspec/flowspec-cli/internal/membudget"
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/parser"
+	"github.com/flowspec/flowspec-cli/internal/remote"
+	"github.com/flowspec/flowspec-cli/internal/renderer"
+	"github.com/flowspec/flowspec-cli/internal/selfupdate"
+	"github.com/flowspec/flowspec-cli/internal/shutdown"
+	"github.com/flowspec/flowspec-cli/internal/signing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOf(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected Code
+	}{
+		{name: "nil", err: nil, expected: ""},
+		{name: "unknown", err: errors.New("boom"), expected: Internal},
+		{name: "wrapped code", err: fmt.Errorf("verify: %w", &Error{Code: ContractInvalid, Err: errors.New("bad")}), expected: ContractInvalid},
+		{name: "incomplete trace", err: fmt.Errorf("refused: %w", models.ErrIncompleteTrace), expected: TraceIncomplete},
+		{name: "unsupported trace", err: &parser.FormatDetectionError{DetectedFormat: "zipkin"}, expected: TraceUnsupported},
+		{name: "checksum", err: fmt.Errorf("fetch: %w", remote.ErrChecksumMismatch), expected: RemoteChecksum},
+		{name: "signature", err: fmt.Errorf("contract.yaml: %w", signing.ErrInvalidSignature), expected: ContractSignature},
+		{name: "remote not found", err: fmt.Errorf("fetch: %w", remote.ErrNotFound), expected: RemoteNotFound},
+		{name: "signal", err: &shutdown.SignalError{}, expected: Interrupted},
+		{name: "memory budget", err: &membudget.Error{Stage: "aligning"}, expected: MemoryBudgetExceeded},
+		{name: "deadline", err: context.DeadlineExceeded, expected: Timeout},
+		{name: "version mismatch", err: fmt.Errorf("config: %w", selfupdate.ErrVersionMismatch), expected: VersionMismatch},
+		{name: "offline", err: fmt.Errorf("fetch: %w", httpclient.ErrOffline), expected: NetworkOffline},
+		{name: "update verification", err: fmt.Errorf("update: %w", selfupdate.ErrVerification), expected: UpdateVerification},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Of(tc.err))
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	assert.NoError(t, Wrap(nil, TraceMalformed))
+
+	err := Wrap(errors.New("unexpected token"), TraceMalformed)
+	assert.Equal(t, TraceMalformed, Of(err))
+	assert.EqualError(t, err, "unexpected token")
+
+	recognized := Wrap(fmt.Errorf("refused: %w", models.ErrIncompleteTrace), TraceMalformed)
+	assert.Equal(t, TraceIncomplete, Of(recognized), "a recognized failure keeps its code")
+	assert.ErrorIs(t, recognized, models.ErrIncompleteTrace)
+
+	assert.Same(t, err, Wrap(err, ConfigInvalid), "a coded error is not coded again")
+}
+
+func TestCodes(t *testing.T) {
+	codes := Codes()
+	require.NotEmpty(t, codes)
+	for _, code := range codes {
+		assert.Regexp(t, `^FS-[A-Z]+-\d{3}$`, string(code))
+		assert.NotEmpty(t, code.Category())
+		assert.NotEmpty(t, code.Description())
+	}
+	assert.Equal(t, "trace", TraceIncomplete.Category())
+	assert.Equal(t, renderer.ExitSpecFormatError, ContractInvalid.ExitCode())
+	assert.Equal(t, renderer.ExitInterrupted, ExitCode(&shutdown.SignalError{}))
+	assert.Equal(t, renderer.ExitSuccess, ExitCode(nil))
+}
+
+func TestWrite(t *testing.T) {
+	err := Wrap(fmt.Errorf("trace trace.json: %w", models.ErrIncompleteTrace), TraceMalformed)
+
+	var ci bytes.Buffer
+	require.NoError(t, Write(&ci, err, true))
+	var document map[string]Report
+	require.NoError(t, json.Unmarshal(ci.Bytes(), &document))
+	assert.Equal(t, Report{
+		Code:     TraceIncomplete,
+		Category: "trace",
+		Message:  "trace trace.json: trace is incomplete",
+		ExitCode: renderer.ExitSystemError,
+	}, document["error"])
+	assert.Equal(t, 1, bytes.Count(ci.Bytes(), []byte("\n")), "one line per failure")
+
+	var human bytes.Buffer
+	require.NoError(t, Write(&human, err, false))
+	assert.Equal(t, "Error [FS-TRACE-004]: trace trace.json: trace is incomplete\n", human.String())
+}
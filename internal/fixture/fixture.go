@@ -0,0 +1,178 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fixture records the spans each contract operation matched as minimized trace files,
+// so contract tests can re-run against them in CI without generating a full trace.
+package fixture
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/ingestor"
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/tracefile"
+)
+
+// fileSuffix ends the names of recorded fixture files, which a new recording replaces
+const fileSuffix = ".trace.json"
+
+// unsafeName matches the runs of characters kept out of fixture file names
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// Fixture describes a recorded fixture file
+type Fixture struct {
+	File      string `json:"file"`              // Name of the file in the fixture directory
+	Service   string `json:"service,omitempty"` // Service of YAML specs
+	Operation string `json:"operation"`         // "METHOD /path" of YAML specs, the operation ID of other specs
+	Spans     int    `json:"spans"`             // Matched spans recorded, besides their ancestors
+}
+
+// Record writes a fixture trace into dir for every operation of the report that matched spans:
+// the matched spans and their ancestors, so the fixture keeps the structure of the trace.
+// Fixtures of an earlier recording in dir are removed first, so operations that no longer
+// match do not leave stale fixtures behind.
+func Record(report *models.AlignmentReport, trace *models.TraceData, dir string) ([]Fixture, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	stale, err := filepath.Glob(filepath.Join(dir, "*"+fileSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fixtures: %w", err)
+	}
+	for _, path := range stale {
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale fixture: %w", err)
+		}
+	}
+
+	var fixtures []Fixture
+	names := make(map[string]bool)
+	record := func(service, operation string, spanIDs []string) error {
+		if len(spanIDs) == 0 {
+			return nil
+		}
+		name := fileName(names, service, operation)
+		spans := withAncestors(trace, spanIDs)
+		var content bytes.Buffer
+		if err := ingestor.WriteOTLP(&content, spans); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), content.Bytes(), 0644); err != nil {
+			return fmt.Errorf("failed to write fixture %s: %w", name, err)
+		}
+		fixtures = append(fixtures, Fixture{File: name, Service: service, Operation: operation, Spans: len(spanIDs)})
+		return nil
+	}
+
+	for _, result := range report.Results {
+		if len(result.OperationResults) == 0 {
+			if err := record(result.Service, result.SpecOperationID, result.MatchedSpans); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		keys := make([]string, 0, len(result.OperationResults))
+		for key := range result.OperationResults {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			operation := result.OperationResults[key]
+			if operation == nil {
+				continue
+			}
+			label := strings.ToUpper(operation.Method) + " " + operation.Path
+			if err := record(result.Service, label, operation.MatchedSpans); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return fixtures, nil
+}
+
+// Load reads every JSON trace file of a directory, in OTLP or FlowSpec format, into one trace.
+// A span recorded in several fixtures, such as an ancestor shared by operations, is loaded once.
+func Load(dir string) (*models.TraceData, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fixtures: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("fixture directory %s has no JSON trace files", dir)
+	}
+	sort.Strings(paths)
+
+	merged := &models.TraceData{Spans: make(map[string]*models.Span)}
+	for _, path := range paths {
+		trace, err := tracefile.Load(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load fixture %s: %w", filepath.Base(path), err)
+		}
+		ids := make([]string, 0, len(trace.Spans))
+		for id := range trace.Spans {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			if _, loaded := merged.Spans[id]; loaded {
+				continue
+			}
+			merged.AddSpan(trace.Spans[id])
+		}
+		if merged.TraceID == "" {
+			merged.TraceID = trace.TraceID
+		}
+	}
+	if err := merged.BuildSpanTree(); err != nil {
+		return nil, fmt.Errorf("failed to build span tree of fixtures: %w", err)
+	}
+	return merged, nil
+}
+
+// withAncestors returns the spans with the given IDs and all their ancestors present in the trace
+func withAncestors(trace *models.TraceData, spanIDs []string) []*models.Span {
+	seen := make(map[string]bool)
+	var spans []*models.Span
+	for _, id := range spanIDs {
+		for span := trace.Spans[id]; span != nil && !seen[span.SpanID]; span = trace.Spans[span.ParentID] {
+			seen[span.SpanID] = true
+			spans = append(spans, span)
+			if span.ParentID == "" {
+				break
+			}
+		}
+	}
+	return spans
+}
+
+// fileName returns an unused file name for the fixture of an operation, e.g.
+// orders_GET_orders_id.trace.json
+func fileName(used map[string]bool, service, operation string) string {
+	base := strings.Trim(unsafeName.ReplaceAllString(service+" "+operation, "_"), "_")
+	if base == "" {
+		base = "operation"
+	}
+	name := base + fileSuffix
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s_%d%s", base, i, fileSuffix)
+	}
+	used[name] = true
+	return name
+}
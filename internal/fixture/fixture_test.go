@@ -0,0 +1,135 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fixture
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/engine"
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/tracefile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTrace() *models.TraceData {
+	span := func(id, parentID, name, operationID string, start int64, status string) *models.Span {
+		span := &models.Span{
+			SpanID: id, TraceID: "trace1", ParentID: parentID, Name: name, StartTime: start, EndTime: start + 100,
+			Status: models.SpanStatus{Code: status}, Attributes: map[string]interface{}{},
+		}
+		if operationID != "" {
+			span.Attributes["operation.id"] = operationID
+		}
+		return span
+	}
+	trace := &models.TraceData{TraceID: "trace1"}
+	for _, s := range []*models.Span{
+		span("root", "", "gateway", "", 1000, "OK"),
+		span("checkout", "root", "checkout", "", 1100, "OK"),
+		span("pay", "checkout", "pay", "createPayment", 1200, "ERROR"),
+		span("list", "root", "list", "listOrders", 1300, "OK"),
+		span("unrelated", "root", "health", "", 1400, "OK"),
+	} {
+		trace.AddSpan(s)
+	}
+	if err := trace.BuildSpanTree(); err != nil {
+		panic(err)
+	}
+	return trace
+}
+
+func TestRecordAndLoad(t *testing.T) {
+	noError := map[string]interface{}{"==": []interface{}{map[string]interface{}{"var": "span.has_error"}, false}}
+	specs := []models.ServiceSpec{
+		{OperationID: "createPayment", Description: "Create payment", Postconditions: noError},
+		{OperationID: "listOrders", Description: "List orders", Postconditions: noError},
+		{OperationID: "deleteOrder", Description: "Delete order", Postconditions: noError},
+	}
+	trace := testTrace()
+	report, err := engine.NewAlignmentEngine().AlignSpecsWithTrace(specs, trace)
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "fixtures")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "removed_op.trace.json"), []byte("{}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("kept"), 0644))
+
+	fixtures, err := Record(report, trace, dir)
+	require.NoError(t, err)
+	assert.Equal(t, []Fixture{
+		{File: "createPayment.trace.json", Operation: "createPayment", Spans: 1},
+		{File: "listOrders.trace.json", Operation: "listOrders", Spans: 1},
+	}, fixtures, "operations without matched spans get no fixture")
+	assert.NoFileExists(t, filepath.Join(dir, "removed_op.trace.json"), "stale fixtures are removed")
+	assert.FileExists(t, filepath.Join(dir, "notes.txt"))
+
+	payment, err := tracefile.Load(filepath.Join(dir, "createPayment.trace.json"))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"root", "checkout", "pay"}, spanIDs(payment), "ancestors are kept")
+
+	replayTrace, err := Load(dir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"root", "checkout", "pay", "list"}, spanIDs(replayTrace), "the shared root is loaded once")
+	assert.Empty(t, replayTrace.DuplicateSpanIDs)
+	assert.Equal(t, "root", replayTrace.RootSpan.SpanID)
+
+	replay, err := engine.NewAlignmentEngine().AlignSpecsWithTrace(specs, replayTrace)
+	require.NoError(t, err)
+	for i, result := range report.Results {
+		assert.Equal(t, result.Status, replay.Results[i].Status, result.SpecOperationID)
+		assert.Equal(t, result.MatchedSpans, replay.Results[i].MatchedSpans, result.SpecOperationID)
+	}
+}
+
+func TestRecord_OperationResults(t *testing.T) {
+	report := &models.AlignmentReport{Results: []models.AlignmentResult{{
+		Service: "orders",
+		OperationResults: map[string]*models.OperationResult{
+			"GET /orders":       {Method: "GET", Path: "/orders", MatchedSpans: []string{"list"}},
+			"POST /orders/{id}": {Method: "post", Path: "/orders/{id}", MatchedSpans: []string{"pay", "checkout"}},
+			"POST /orders/(id)": {Method: "POST", Path: "/orders/(id)", MatchedSpans: []string{"pay"}},
+			"DELETE /orders":    {Method: "DELETE", Path: "/orders"},
+		},
+	}}}
+
+	fixtures, err := Record(report, testTrace(), t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, []Fixture{
+		{File: "orders_GET_orders.trace.json", Service: "orders", Operation: "GET /orders", Spans: 1},
+		{File: "orders_POST_orders_id.trace.json", Service: "orders", Operation: "POST /orders/(id)", Spans: 1},
+		{File: "orders_POST_orders_id_2.trace.json", Service: "orders", Operation: "POST /orders/{id}", Spans: 2},
+	}, fixtures)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := t.TempDir()
+	_, err := Load(dir)
+	assert.ErrorContains(t, err, "has no JSON trace files")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0644))
+	_, err = Load(dir)
+	assert.ErrorContains(t, err, "failed to load fixture broken.json")
+}
+
+func spanIDs(trace *models.TraceData) []string {
+	ids := make([]string, 0, len(trace.Spans))
+	for id := range trace.Spans {
+		ids = append(ids, id)
+	}
+	return ids
+}
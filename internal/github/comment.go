@@ -0,0 +1,250 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package github posts verification summaries to GitHub pull requests.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/httpclient"
+)
+
+// StickyMarker identifies the comment owned by flowspec-cli so it is updated instead of duplicated
+const StickyMarker = "<!-- flowspec-cli-report -->"
+
+// DefaultAPIURL is the GitHub REST API used when GITHUB_API_URL is not set
+const DefaultAPIURL = "https://api.github.com"
+
+// CommentConfig holds the settings needed to comment on a pull request
+type CommentConfig struct {
+	Token       string
+	Repository  string // owner/name
+	PullRequest int
+	APIURL      string
+	Timeout     time.Duration
+	Network     *httpclient.Config // Proxy and TLS settings; httpclient.ConfigFromEnv() when nil
+}
+
+// CommentConfigFromEnv reads the comment settings from the GitHub Actions environment.
+// The pull request number comes from FLOWSPEC_PR_NUMBER, the event payload, or GITHUB_REF.
+func CommentConfigFromEnv() (CommentConfig, error) {
+	config := CommentConfig{
+		Token:      os.Getenv("GITHUB_TOKEN"),
+		Repository: os.Getenv("GITHUB_REPOSITORY"),
+		APIURL:     os.Getenv("GITHUB_API_URL"),
+		Timeout:    30 * time.Second,
+	}
+	if config.APIURL == "" {
+		config.APIURL = DefaultAPIURL
+	}
+
+	number, err := pullRequestFromEnv()
+	if err != nil {
+		return config, err
+	}
+	config.PullRequest = number
+
+	return config, config.Validate()
+}
+
+// Validate checks that the configuration identifies a pull request and can authenticate
+func (c CommentConfig) Validate() error {
+	if c.Token == "" {
+		return fmt.Errorf("GITHUB_TOKEN is required to post a pull request comment")
+	}
+	if parts := strings.Split(c.Repository, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("GITHUB_REPOSITORY must be in owner/name form, got %q", c.Repository)
+	}
+	if c.PullRequest <= 0 {
+		return fmt.Errorf("pull request number is not available; set FLOWSPEC_PR_NUMBER or run on a pull_request event")
+	}
+	return nil
+}
+
+// pullRequestFromEnv determines the pull request number of the current workflow run
+func pullRequestFromEnv() (int, error) {
+	if value := os.Getenv("FLOWSPEC_PR_NUMBER"); value != "" {
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid FLOWSPEC_PR_NUMBER %q: %w", value, err)
+		}
+		return number, nil
+	}
+
+	if path := os.Getenv("GITHUB_EVENT_PATH"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read GitHub event payload: %w", err)
+		}
+		var event struct {
+			Number      int `json:"number"`
+			PullRequest struct {
+				Number int `json:"number"`
+			} `json:"pull_request"`
+		}
+		if err := json.Unmarshal(data, &event); err != nil {
+			return 0, fmt.Errorf("failed to parse GitHub event payload: %w", err)
+		}
+		if event.PullRequest.Number > 0 {
+			return event.PullRequest.Number, nil
+		}
+		if event.Number > 0 {
+			return event.Number, nil
+		}
+	}
+
+	// refs/pull/<number>/merge
+	if ref := os.Getenv("GITHUB_REF"); strings.HasPrefix(ref, "refs/pull/") {
+		parts := strings.Split(ref, "/")
+		if len(parts) >= 3 {
+			if number, err := strconv.Atoi(parts[2]); err == nil {
+				return number, nil
+			}
+		}
+	}
+
+	return 0, nil
+}
+
+// issueComment is the subset of the GitHub issue comment resource that is used
+type issueComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// Commenter creates or updates the sticky verification comment on a pull request
+type Commenter struct {
+	config CommentConfig
+	client *http.Client
+}
+
+// NewCommenter creates a new commenter with the given configuration
+func NewCommenter(config CommentConfig) *Commenter {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return NewCommenterWithClient(config, httpclient.New(config.Network, timeout))
+}
+
+// NewCommenterWithClient creates a new commenter that uses the given HTTP client
+func NewCommenterWithClient(config CommentConfig, client *http.Client) *Commenter {
+	if config.APIURL == "" {
+		config.APIURL = DefaultAPIURL
+	}
+	config.APIURL = strings.TrimRight(config.APIURL, "/")
+	return &Commenter{config: config, client: client}
+}
+
+// Upsert posts body as the sticky comment, replacing the previous one if present.
+// It returns the ID of the created or updated comment.
+func (c *Commenter) Upsert(ctx context.Context, body string) (int64, error) {
+	if err := c.config.Validate(); err != nil {
+		return 0, err
+	}
+	if !strings.Contains(body, StickyMarker) {
+		body = StickyMarker + "\n" + body
+	}
+
+	existing, err := c.findSticky(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	payload := map[string]string{"body": body}
+	var result issueComment
+	if existing != 0 {
+		url := fmt.Sprintf("%s/repos/%s/issues/comments/%d", c.config.APIURL, c.config.Repository, existing)
+		err = c.do(ctx, http.MethodPatch, url, payload, &result)
+	} else {
+		url := fmt.Sprintf("%s/repos/%s/issues/%d/comments", c.config.APIURL, c.config.Repository, c.config.PullRequest)
+		err = c.do(ctx, http.MethodPost, url, payload, &result)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return result.ID, nil
+}
+
+// findSticky returns the ID of the existing sticky comment, or 0 when there is none
+func (c *Commenter) findSticky(ctx context.Context) (int64, error) {
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/repos/%s/issues/%d/comments?per_page=100&page=%d",
+			c.config.APIURL, c.config.Repository, c.config.PullRequest, page)
+		var comments []issueComment
+		if err := c.do(ctx, http.MethodGet, url, nil, &comments); err != nil {
+			return 0, err
+		}
+		for _, comment := range comments {
+			if strings.Contains(comment.Body, StickyMarker) {
+				return comment.ID, nil
+			}
+		}
+		if len(comments) < 100 {
+			return 0, nil
+		}
+	}
+}
+
+// do sends an authenticated API request and decodes the JSON response into out
+func (c *Commenter) do(ctx context.Context, method, url string, payload interface{}, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode GitHub request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.config.Token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("GitHub request %s %s failed: %w", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("GitHub request %s %s returned %s: %s", method, url, resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode GitHub response: %w", err)
+	}
+	return nil
+}
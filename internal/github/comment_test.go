@@ -0,0 +1,186 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitHub serves the issue comment endpoints of a single pull request
+type fakeGitHub struct {
+	comments []issueComment
+	requests []string
+	nextID   int64
+}
+
+func (f *fakeGitHub) handler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/shop/issues/7/comments":
+			page := r.URL.Query().Get("page")
+			start := 0
+			if page == "2" {
+				start = 100
+			}
+			end := start + 100
+			if end > len(f.comments) {
+				end = len(f.comments)
+			}
+			if start > end {
+				start = end
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(f.comments[start:end]))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/shop/issues/7/comments":
+			var payload map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			f.nextID++
+			comment := issueComment{ID: f.nextID, Body: payload["body"]}
+			f.comments = append(f.comments, comment)
+			w.WriteHeader(http.StatusCreated)
+			require.NoError(t, json.NewEncoder(w).Encode(comment))
+		case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/repos/acme/shop/issues/comments/"):
+			var payload map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			for i := range f.comments {
+				if r.URL.Path == fmt.Sprintf("/repos/acme/shop/issues/comments/%d", f.comments[i].ID) {
+					f.comments[i].Body = payload["body"]
+					require.NoError(t, json.NewEncoder(w).Encode(f.comments[i]))
+					return
+				}
+			}
+			http.NotFound(w, r)
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	})
+}
+
+func TestCommenter_Upsert(t *testing.T) {
+	fake := &fakeGitHub{nextID: 1000}
+	for i := 0; i < 120; i++ {
+		fake.comments = append(fake.comments, issueComment{ID: int64(i + 1), Body: "review comment"})
+	}
+	server := httptest.NewServer(fake.handler(t))
+	defer server.Close()
+
+	config := CommentConfig{Token: "token", Repository: "acme/shop", PullRequest: 7, APIURL: server.URL + "/"}
+	commenter := NewCommenterWithClient(config, server.Client())
+
+	id, err := commenter.Upsert(context.Background(), "first run")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1001), id)
+	assert.Equal(t, StickyMarker+"\nfirst run", fake.comments[len(fake.comments)-1].Body)
+
+	fake.requests = nil
+	id, err = commenter.Upsert(context.Background(), "second run")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1001), id, "the sticky comment is updated in place")
+	assert.Len(t, fake.comments, 121)
+	assert.Equal(t, StickyMarker+"\nsecond run", fake.comments[120].Body)
+	assert.Equal(t, []string{
+		"GET /repos/acme/shop/issues/7/comments",
+		"GET /repos/acme/shop/issues/7/comments",
+		"PATCH /repos/acme/shop/issues/comments/1001",
+	}, fake.requests)
+}
+
+func TestCommenter_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Resource not accessible by integration"}`, http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	config := CommentConfig{Token: "token", Repository: "acme/shop", PullRequest: 7, APIURL: server.URL}
+	_, err := NewCommenterWithClient(config, server.Client()).Upsert(context.Background(), "body")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+	assert.Contains(t, err.Error(), "Resource not accessible")
+
+	config.Repository = "shop"
+	_, err = NewCommenter(config).Upsert(context.Background(), "body")
+	assert.ErrorContains(t, err, "owner/name")
+}
+
+func TestCommentConfigFromEnv(t *testing.T) {
+	eventPath := filepath.Join(t.TempDir(), "event.json")
+	require.NoError(t, os.WriteFile(eventPath, []byte(`{"action":"synchronize","pull_request":{"number":42}}`), 0644))
+
+	testCases := []struct {
+		name     string
+		env      map[string]string
+		expected int
+		wantErr  string
+	}{
+		{
+			name:     "event payload",
+			env:      map[string]string{"GITHUB_EVENT_PATH": eventPath},
+			expected: 42,
+		},
+		{
+			name:     "explicit override",
+			env:      map[string]string{"FLOWSPEC_PR_NUMBER": "9", "GITHUB_EVENT_PATH": eventPath},
+			expected: 9,
+		},
+		{
+			name:     "pull request ref",
+			env:      map[string]string{"GITHUB_REF": "refs/pull/15/merge"},
+			expected: 15,
+		},
+		{
+			name:    "branch push",
+			env:     map[string]string{"GITHUB_REF": "refs/heads/main"},
+			wantErr: "pull request number is not available",
+		},
+		{
+			name:    "invalid override",
+			env:     map[string]string{"FLOWSPEC_PR_NUMBER": "abc"},
+			wantErr: "invalid FLOWSPEC_PR_NUMBER",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("GITHUB_TOKEN", "token")
+			t.Setenv("GITHUB_REPOSITORY", "acme/shop")
+			t.Setenv("GITHUB_API_URL", "")
+			for _, key := range []string{"FLOWSPEC_PR_NUMBER", "GITHUB_EVENT_PATH", "GITHUB_REF"} {
+				t.Setenv(key, tc.env[key])
+			}
+
+			config, err := CommentConfigFromEnv()
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, config.PullRequest)
+			assert.Equal(t, DefaultAPIURL, config.APIURL)
+		})
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package history compares verification runs and keeps a local store of past results.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/renderer"
+)
+
+// ChangeKind classifies how an operation's outcome changed between two runs
+type ChangeKind string
+
+const (
+	NewlyFailing ChangeKind = "newly_failing"
+	Fixed        ChangeKind = "fixed"
+	StillFailing ChangeKind = "still_failing"
+	Added        ChangeKind = "added"
+	Removed      ChangeKind = "removed"
+)
+
+// OutcomeChange describes the outcome of one operation in the previous and current run
+type OutcomeChange struct {
+	Kind     ChangeKind             `json:"kind"`
+	Key      string                 `json:"key"`
+	Previous models.AlignmentStatus `json:"previous,omitempty"`
+	Current  models.AlignmentStatus `json:"current,omitempty"`
+}
+
+// Comparison is the result of comparing two verification runs
+type Comparison struct {
+	Changes []OutcomeChange  `json:"changes"`
+	Flaky   []FlakyOperation `json:"flaky,omitempty"` // Filled in when history is available
+}
+
+// Regressions returns the operations that pass in the previous run but fail in the current one
+func (c *Comparison) Regressions() []OutcomeChange {
+	return c.byKind(NewlyFailing)
+}
+
+// Improvements returns the operations that fail in the previous run but pass in the current one
+func (c *Comparison) Improvements() []OutcomeChange {
+	return c.byKind(Fixed)
+}
+
+// HasRegressions reports whether any operation started failing
+func (c *Comparison) HasRegressions() bool {
+	return len(c.Regressions()) > 0
+}
+
+// ExitCode returns the CLI exit code: validation failed when there are regressions
+func (c *Comparison) ExitCode() int {
+	if c.HasRegressions() {
+		return renderer.ExitValidationFailed
+	}
+	return renderer.ExitSuccess
+}
+
+func (c *Comparison) byKind(kind ChangeKind) []OutcomeChange {
+	var changes []OutcomeChange
+	for _, change := range c.Changes {
+		if change.Kind == kind {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// LoadReport reads a report written with --output json
+func LoadReport(path string) (*models.AlignmentReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report %s: %w", path, err)
+	}
+	var report models.AlignmentReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	if err := renderer.CheckSchemaVersion(report.SchemaVersion); err != nil {
+		return nil, fmt.Errorf("failed to read report %s: %w", path, err)
+	}
+	return &report, nil
+}
+
+// CompareFiles compares two reports written with --output json
+func CompareFiles(previousPath, currentPath string) (*Comparison, error) {
+	previous, err := LoadReport(previousPath)
+	if err != nil {
+		return nil, err
+	}
+	current, err := LoadReport(currentPath)
+	if err != nil {
+		return nil, err
+	}
+	return Compare(previous, current), nil
+}
+
+// Compare compares the per-operation outcomes of two runs. Operations that pass or are
+// unchanged in both runs are not listed.
+func Compare(previous, current *models.AlignmentReport) *Comparison {
+	before := Outcomes(previous)
+	after := Outcomes(current)
+
+	keys := make([]string, 0, len(before)+len(after))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	comparison := &Comparison{Changes: []OutcomeChange{}}
+	for _, key := range keys {
+		previousStatus, inPrevious := before[key]
+		currentStatus, inCurrent := after[key]
+		change := OutcomeChange{Key: key, Previous: previousStatus, Current: currentStatus}
+
+		switch {
+		case !inPrevious:
+			change.Kind = Added
+		case !inCurrent:
+			change.Kind = Removed
+		case isFailure(currentStatus) && isFailure(previousStatus):
+			change.Kind = StillFailing
+		case isFailure(currentStatus):
+			change.Kind = NewlyFailing
+		case isFailure(previousStatus) && currentStatus == models.StatusSuccess:
+			change.Kind = Fixed
+		default:
+			continue
+		}
+		comparison.Changes = append(comparison.Changes, change)
+	}
+	return comparison
+}
+
+// Outcomes returns the status of every operation in the report, keyed by "spec / operation".
+// Specs without per-operation results are keyed by their spec ID.
+func Outcomes(report *models.AlignmentReport) map[string]models.AlignmentStatus {
+	outcomes := make(map[string]models.AlignmentStatus)
+	if report == nil {
+		return outcomes
+	}
+	for _, result := range report.Results {
+		if len(result.OperationResults) == 0 || result.Status == models.StatusTimeout {
+			outcomes[result.SpecOperationID] = result.Status
+			continue
+		}
+		for key, operation := range result.OperationResults {
+			outcomes[result.SpecOperationID+" / "+key] = operation.Status
+		}
+	}
+	return outcomes
+}
+
+// isFailure reports whether a status counts as failing
+func isFailure(status models.AlignmentStatus) bool {
+	return status == models.StatusFailed || status == models.StatusTimeout
+}
+
+// FormatHuman renders the comparison as human-readable text
+func (c *Comparison) FormatHuman() string {
+	var output strings.Builder
+
+	sections := []struct {
+		kind  ChangeKind
+		title string
+	}{
+		{NewlyFailing, "Newly failing"},
+		{Fixed, "Fixed"},
+		{StillFailing, "Still failing"},
+		{Added, "Added"},
+		{Removed, "Removed"},
+	}
+	for _, section := range sections {
+		changes := c.byKind(section.kind)
+		if len(changes) == 0 {
+			continue
+		}
+		output.WriteString(fmt.Sprintf("%s (%d)\n", section.title, len(changes)))
+		for _, change := range changes {
+			switch change.Kind {
+			case Added:
+				output.WriteString(fmt.Sprintf("  + %s (%s)\n", change.Key, change.Current))
+			case Removed:
+				output.WriteString(fmt.Sprintf("  - %s (%s)\n", change.Key, change.Previous))
+			default:
+				output.WriteString(fmt.Sprintf("  %s: %s → %s\n", change.Key, change.Previous, change.Current))
+			}
+		}
+		output.WriteString("\n")
+	}
+
+	if len(c.Flaky) > 0 {
+		output.WriteString(fmt.Sprintf("Flaky (%d)\n", len(c.Flaky)))
+		for _, flaky := range c.Flaky {
+			output.WriteString(fmt.Sprintf("  %s: failed %d of %d runs (%.0f%%), %d flip(s)\n",
+				flaky.Key, flaky.Failures, flaky.Runs, flaky.FailureRate*100, flaky.Flips))
+		}
+		output.WriteString("\n")
+	}
+
+	output.WriteString(fmt.Sprintf("%d regression(s), %d improvement(s)\n", len(c.Regressions()), len(c.Improvements())))
+	return output.String()
+}
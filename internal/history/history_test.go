@@ -0,0 +1,148 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/renderer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRun builds a report with one YAML spec whose operations have the given statuses
+func newRun(endTime int64, operations map[string]models.AlignmentStatus) *models.AlignmentReport {
+	result := models.NewAlignmentResult("user-service-v1")
+	result.Status = models.StatusSuccess
+	result.OperationResults = make(map[string]*models.OperationResult)
+	for key, status := range operations {
+		result.OperationResults[key] = &models.OperationResult{Status: status}
+		if status == models.StatusFailed {
+			result.Status = models.StatusFailed
+		}
+	}
+
+	report := models.NewAlignmentReport()
+	report.AddResult(*result)
+	report.EndTime = endTime
+	return report
+}
+
+func TestCompare(t *testing.T) {
+	previous := newRun(1, map[string]models.AlignmentStatus{
+		"GET /users":    models.StatusSuccess,
+		"POST /users":   models.StatusFailed,
+		"DELETE /users": models.StatusFailed,
+		"GET /legacy":   models.StatusSuccess,
+		"GET /health":   models.StatusSkipped,
+	})
+	current := newRun(2, map[string]models.AlignmentStatus{
+		"GET /users":    models.StatusFailed,
+		"POST /users":   models.StatusSuccess,
+		"DELETE /users": models.StatusFailed,
+		"GET /orders":   models.StatusSuccess,
+		"GET /health":   models.StatusSkipped,
+	})
+
+	comparison := Compare(previous, current)
+	assert.Equal(t, []OutcomeChange{
+		{Kind: StillFailing, Key: "user-service-v1 / DELETE /users", Previous: models.StatusFailed, Current: models.StatusFailed},
+		{Kind: Removed, Key: "user-service-v1 / GET /legacy", Previous: models.StatusSuccess},
+		{Kind: Added, Key: "user-service-v1 / GET /orders", Current: models.StatusSuccess},
+		{Kind: NewlyFailing, Key: "user-service-v1 / GET /users", Previous: models.StatusSuccess, Current: models.StatusFailed},
+		{Kind: Fixed, Key: "user-service-v1 / POST /users", Previous: models.StatusFailed, Current: models.StatusSuccess},
+	}, comparison.Changes)
+	assert.True(t, comparison.HasRegressions())
+	assert.Equal(t, renderer.ExitValidationFailed, comparison.ExitCode())
+
+	output := comparison.FormatHuman()
+	assert.Contains(t, output, "Newly failing (1)\n  user-service-v1 / GET /users: SUCCESS → FAILED\n")
+	assert.Contains(t, output, "  + user-service-v1 / GET /orders (SUCCESS)")
+	assert.Contains(t, output, "1 regression(s), 1 improvement(s)")
+
+	assert.Equal(t, renderer.ExitSuccess, Compare(current, current).ExitCode())
+}
+
+func TestCompareFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, report *models.AlignmentReport) string {
+		data, err := json.Marshal(report)
+		require.NoError(t, err)
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, data, 0644))
+		return path
+	}
+
+	first := write("run1.json", newRun(1, map[string]models.AlignmentStatus{"GET /users": models.StatusFailed}))
+	second := write("run2.json", newRun(2, map[string]models.AlignmentStatus{"GET /users": models.StatusSuccess}))
+
+	comparison, err := CompareFiles(first, second)
+	require.NoError(t, err)
+	require.Len(t, comparison.Improvements(), 1)
+
+	_, err = CompareFiles(first, filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+
+	future := newRun(3, map[string]models.AlignmentStatus{"GET /users": models.StatusSuccess})
+	future.SchemaVersion = "2.0"
+	_, err = CompareFiles(first, write("run3.json", future))
+	assert.ErrorContains(t, err, "schemaVersion 2.0 is not supported")
+}
+
+func TestStore_Record(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history"))
+
+	statuses := []models.AlignmentStatus{
+		models.StatusSuccess, models.StatusFailed, models.StatusSuccess, models.StatusSuccess, models.StatusFailed,
+	}
+	var comparison *Comparison
+	for i, status := range statuses {
+		run := newRun(int64(i+1), map[string]models.AlignmentStatus{
+			"GET /users":  status,
+			"POST /users": models.StatusSuccess,
+		})
+		var err error
+		comparison, err = store.Record(run, 4)
+		require.NoError(t, err)
+		if i == 0 {
+			assert.Nil(t, comparison, "nothing to compare against on the first run")
+		}
+	}
+
+	require.NotNil(t, comparison)
+	require.Len(t, comparison.Regressions(), 1)
+	assert.Equal(t, "user-service-v1 / GET /users", comparison.Regressions()[0].Key)
+
+	require.Len(t, comparison.Flaky, 1, "only operations with both outcomes are flaky")
+	assert.Equal(t, FlakyOperation{Key: "user-service-v1 / GET /users", Runs: 4, Failures: 2, FailureRate: 0.5, Flips: 2}, comparison.Flaky[0])
+
+	runs, err := store.Recent(0)
+	require.NoError(t, err)
+	require.Len(t, runs, 4, "the store is pruned to the configured number of runs")
+	assert.Equal(t, int64(2), runs[0].EndTime)
+}
+
+func TestFlakiness_IgnoresSkipped(t *testing.T) {
+	runs := []*models.AlignmentReport{
+		newRun(1, map[string]models.AlignmentStatus{"GET /users": models.StatusSkipped}),
+		newRun(2, map[string]models.AlignmentStatus{"GET /users": models.StatusSuccess}),
+		newRun(3, map[string]models.AlignmentStatus{"GET /users": models.StatusSkipped}),
+	}
+	assert.Empty(t, Flakiness(runs))
+}
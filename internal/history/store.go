@@ -0,0 +1,217 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// DefaultRuns is the number of stored runs used for flakiness rates
+const DefaultRuns = 10
+
+// runFilePrefix and runFileSuffix frame the stored run files; the timestamp in between sorts chronologically
+const (
+	runFilePrefix = "run-"
+	runFileSuffix = ".json"
+)
+
+// FlakyOperation is an operation that both passed and failed across recent runs
+type FlakyOperation struct {
+	Key         string  `json:"key"`
+	Runs        int     `json:"runs"`
+	Failures    int     `json:"failures"`
+	FailureRate float64 `json:"failureRate"`
+	Flips       int     `json:"flips"` // Number of pass/fail transitions between consecutive runs
+}
+
+// Store keeps verification reports in a local directory, one JSON file per run
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store in dir; the directory is created on first save
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Save stores the report and returns the file it was written to
+func (s *Store) Save(report *models.AlignmentReport) (string, error) {
+	if report == nil {
+		return "", fmt.Errorf("report cannot be nil")
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create history directory %s: %w", s.dir, err)
+	}
+
+	timestamp := report.EndTime
+	if timestamp == 0 {
+		timestamp = time.Now().UnixNano()
+	}
+	path := filepath.Join(s.dir, fmt.Sprintf("%s%020d%s", runFilePrefix, timestamp, runFileSuffix))
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal report: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write history file %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// Recent loads up to limit of the most recent runs, oldest first. A limit of 0 loads all runs.
+func (s *Store) Recent(limit int) ([]*models.AlignmentReport, error) {
+	files, err := s.runFiles()
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(files) > limit {
+		files = files[len(files)-limit:]
+	}
+
+	reports := make([]*models.AlignmentReport, 0, len(files))
+	for _, file := range files {
+		report, err := LoadReport(file)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// Prune removes all but the keep most recent runs
+func (s *Store) Prune(keep int) error {
+	files, err := s.runFiles()
+	if err != nil {
+		return err
+	}
+	for len(files) > keep {
+		if err := os.Remove(files[0]); err != nil {
+			return fmt.Errorf("failed to remove history file %s: %w", files[0], err)
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// Record compares the report with the latest stored run, computes flakiness over the last runs
+// including this one, then stores the report and prunes the store to runs entries. The
+// comparison is nil when the store holds no previous run.
+func (s *Store) Record(report *models.AlignmentReport, runs int) (*Comparison, error) {
+	if runs <= 0 {
+		runs = DefaultRuns
+	}
+
+	previous, err := s.Recent(runs - 1)
+	if err != nil {
+		return nil, err
+	}
+
+	var comparison *Comparison
+	if len(previous) > 0 {
+		comparison = Compare(previous[len(previous)-1], report)
+		comparison.Flaky = Flakiness(append(previous, report))
+	}
+
+	if _, err := s.Save(report); err != nil {
+		return nil, err
+	}
+	if err := s.Prune(runs); err != nil {
+		return nil, err
+	}
+	return comparison, nil
+}
+
+// runFiles lists the stored run files, oldest first
+func (s *Store) runFiles() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read history directory %s: %w", s.dir, err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, runFilePrefix) && strings.HasSuffix(name, runFileSuffix) {
+			files = append(files, filepath.Join(s.dir, name))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Flakiness returns the operations that both passed and failed across the given runs, oldest
+// first, sorted by failure rate and then key
+func Flakiness(reports []*models.AlignmentReport) []FlakyOperation {
+	type tally struct {
+		runs, failures, flips int
+		last                  models.AlignmentStatus
+	}
+	tallies := make(map[string]*tally)
+
+	for _, report := range reports {
+		for key, status := range Outcomes(report) {
+			if status != models.StatusSuccess && !isFailure(status) {
+				continue
+			}
+			t, ok := tallies[key]
+			if !ok {
+				t = &tally{}
+				tallies[key] = t
+			}
+			if t.runs > 0 && isFailure(t.last) != isFailure(status) {
+				t.flips++
+			}
+			t.runs++
+			if isFailure(status) {
+				t.failures++
+			}
+			t.last = status
+		}
+	}
+
+	var flaky []FlakyOperation
+	for key, t := range tallies {
+		if t.failures == 0 || t.failures == t.runs {
+			continue
+		}
+		flaky = append(flaky, FlakyOperation{
+			Key:         key,
+			Runs:        t.runs,
+			Failures:    t.failures,
+			FailureRate: float64(t.failures) / float64(t.runs),
+			Flips:       t.flips,
+		})
+	}
+	sort.Slice(flaky, func(i, j int) bool {
+		if flaky[i].FailureRate != flaky[j].FailureRate {
+			return flaky[i].FailureRate > flaky[j].FailureRate
+		}
+		return flaky[i].Key < flaky[j].Key
+	})
+	return flaky
+}
@@ -0,0 +1,214 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httpclient builds the HTTP clients of the network integrations, such as remote
+// contracts, publishing, webhooks and pull request comments, so they reach hosts behind
+// corporate proxies and private CAs and present client certificates for mutual TLS. In offline
+// mode the clients refuse every request that would leave the machine.
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrInvalidConfig is matched by errors.Is when a request fails because the proxy or TLS
+// settings are invalid
+var ErrInvalidConfig = errors.New("invalid network configuration")
+
+// ErrOffline is matched by errors.Is when a request is refused because offline mode is on
+var ErrOffline = errors.New("network access is disabled in offline mode")
+
+// Config holds the proxy and TLS settings of outgoing connections
+type Config struct {
+	Proxy    string // Proxy URL of every request; HTTPS_PROXY, HTTP_PROXY and NO_PROXY when empty
+	CAFile   string // PEM bundle of private CAs, trusted in addition to the system roots
+	CertFile string // PEM client certificate presented for mutual TLS
+	KeyFile  string // PEM private key of the client certificate
+	Offline  bool   // Refuse every request to a host other than the loopback interface
+}
+
+// ConfigFromEnv reads the settings from FLOWSPEC_PROXY, FLOWSPEC_CA_FILE, FLOWSPEC_CLIENT_CERT,
+// FLOWSPEC_CLIENT_KEY and FLOWSPEC_OFFLINE; the standard proxy variables apply without
+// FLOWSPEC_PROXY
+func ConfigFromEnv() *Config {
+	offline := strings.ToLower(os.Getenv("FLOWSPEC_OFFLINE"))
+	return &Config{
+		Proxy:    os.Getenv("FLOWSPEC_PROXY"),
+		CAFile:   os.Getenv("FLOWSPEC_CA_FILE"),
+		CertFile: os.Getenv("FLOWSPEC_CLIENT_CERT"),
+		KeyFile:  os.Getenv("FLOWSPEC_CLIENT_KEY"),
+		Offline:  offline != "" && offline != "false" && offline != "0",
+	}
+}
+
+// IsOffline reports whether offline mode is on in config or in the environment; FLOWSPEC_OFFLINE
+// applies to every client, so it guarantees no integration reaches the network
+func IsOffline(config *Config) bool {
+	return (config != nil && config.Offline) || ConfigFromEnv().Offline
+}
+
+// Merge returns a copy of the settings with the empty ones taken from fallback. Offline mode
+// is on when either turns it on.
+func (c *Config) Merge(fallback *Config) *Config {
+	merged := *c
+	if fallback == nil {
+		return &merged
+	}
+	merged.Offline = merged.Offline || fallback.Offline
+	if merged.Proxy == "" {
+		merged.Proxy = fallback.Proxy
+	}
+	if merged.CAFile == "" {
+		merged.CAFile = fallback.CAFile
+	}
+	if merged.CertFile == "" && merged.KeyFile == "" {
+		merged.CertFile, merged.KeyFile = fallback.CertFile, fallback.KeyFile
+	}
+	return &merged
+}
+
+// Validate checks the proxy URL and loads the CA bundle and client certificate
+func (c *Config) Validate() error {
+	_, err := c.Transport()
+	return err
+}
+
+// Transport returns a transport with the settings of the default transport, the proxy, the CA
+// bundle and the client certificate
+func (c *Config) Transport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c.Proxy != "" {
+		proxy, err := parseProxy(c.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if c.CAFile == "" && c.CertFile == "" && c.KeyFile == "" {
+		return transport, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.CAFile != "" {
+		bundle, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("CA bundle %s holds no PEM certificate", c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, fmt.Errorf("a client certificate needs both a certificate and a key file")
+		}
+		certificate, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// ValidateProxy checks that a proxy URL is an http, https or socks5 URL with a host
+func ValidateProxy(proxy string) error {
+	_, err := parseProxy(proxy)
+	return err
+}
+
+// parseProxy parses a proxy URL
+func parseProxy(proxy string) (*url.URL, error) {
+	parsed, err := url.Parse(proxy)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q", proxy)
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https", "socks5", "socks5h":
+		return parsed, nil
+	}
+	return nil, fmt.Errorf("unsupported proxy scheme %q, expected http, https or socks5", parsed.Scheme)
+}
+
+// New returns a client with the timeout and the settings of config, or of ConfigFromEnv() when
+// config is nil. Invalid settings fail every request of the client, so integrations that
+// cannot return an error when they are created still report them. In offline mode the client
+// only reaches loopback hosts, without the proxy, and fails other requests with ErrOffline.
+func New(config *Config, timeout time.Duration) *http.Client {
+	if config == nil {
+		config = ConfigFromEnv()
+	}
+	transport, err := config.Transport()
+	if err != nil {
+		return &http.Client{Timeout: timeout, Transport: failingTransport{err: err}}
+	}
+	if IsOffline(config) {
+		transport.Proxy = nil
+		return &http.Client{Timeout: timeout, Transport: offlineTransport{next: transport}}
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// IsLoopback reports whether a host name or address is on the loopback interface
+func IsLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// offlineTransport passes requests to loopback hosts on and refuses the others
+type offlineTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if IsLoopback(req.URL.Hostname()) {
+		return t.next.RoundTrip(req)
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, fmt.Errorf("%w: refused request to %s", ErrOffline, req.URL.Host)
+}
+
+// failingTransport fails every request with the error of an invalid configuration
+type failingTransport struct {
+	err error
+}
+
+// RoundTrip implements http.RoundTripper
+func (t failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, t.err)
+}
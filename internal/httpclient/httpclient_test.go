@@ -0,0 +1,188 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_Proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer proxy.Close()
+
+	client := New(&Config{Proxy: proxy.URL}, time.Second)
+	resp, err := client.Get("http://contracts.example.com/orders.yaml")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "http://contracts.example.com/orders.yaml", proxied)
+}
+
+func TestNew_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, clientCertificate := writeClientCertificate(t, dir)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCertificate)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+
+	_, err := New(&Config{}, time.Second).Get(server.URL)
+	assert.Error(t, err, "the server certificate is signed by an unknown authority")
+	_, err = New(&Config{CAFile: caFile}, time.Second).Get(server.URL)
+	assert.Error(t, err, "the server requires a client certificate")
+
+	resp, err := New(&Config{CAFile: caFile, CertFile: certFile, KeyFile: keyFile}, time.Second).Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "flowspec-ci", string(body))
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0600))
+
+	testCases := []struct {
+		name   string
+		config Config
+	}{
+		{name: "proxy without host", config: Config{Proxy: "proxy.example.com"}},
+		{name: "unsupported proxy scheme", config: Config{Proxy: "ftp://proxy.example.com"}},
+		{name: "missing CA bundle", config: Config{CAFile: filepath.Join(dir, "missing.pem")}},
+		{name: "CA bundle without certificates", config: Config{CAFile: notPEM}},
+		{name: "certificate without key", config: Config{CertFile: notPEM}},
+		{name: "invalid client certificate", config: Config{CertFile: notPEM, KeyFile: notPEM}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Error(t, tc.config.Validate())
+			_, err := New(&tc.config, time.Second).Get("http://contracts.example.com/orders.yaml")
+			assert.ErrorIs(t, err, ErrInvalidConfig)
+		})
+	}
+}
+
+func TestConfig_Merge(t *testing.T) {
+	fallback := &Config{Proxy: "http://proxy:3128", CAFile: "ca.pem", CertFile: "client.pem", KeyFile: "client-key.pem"}
+
+	merged := (&Config{Proxy: "socks5://localhost:1080", KeyFile: "other-key.pem"}).Merge(fallback)
+	assert.Equal(t, Config{Proxy: "socks5://localhost:1080", CAFile: "ca.pem", KeyFile: "other-key.pem"}, *merged,
+		"a certificate and its key are taken together")
+	assert.Equal(t, *fallback, *(&Config{}).Merge(fallback))
+	assert.Equal(t, Config{CAFile: "ca.pem"}, *(&Config{CAFile: "ca.pem"}).Merge(nil))
+}
+
+func TestNew_Offline(t *testing.T) {
+	var proxied bool
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = true
+	}))
+	defer proxy.Close()
+	local := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer local.Close()
+
+	client := New(&Config{Proxy: proxy.URL, Offline: true}, time.Second)
+	_, err := client.Get("http://contracts.example.com/orders.yaml")
+	assert.ErrorIs(t, err, ErrOffline)
+
+	resp, err := client.Get(local.URL)
+	require.NoError(t, err, "loopback hosts stay reachable")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.False(t, proxied, "offline requests bypass the proxy")
+}
+
+func TestConfigFromEnv_Offline(t *testing.T) {
+	for value, offline := range map[string]bool{"": false, "0": false, "false": false, "1": true, "true": true} {
+		t.Setenv("FLOWSPEC_OFFLINE", value)
+		assert.Equal(t, offline, ConfigFromEnv().Offline, value)
+		assert.Equal(t, offline, IsOffline(nil), value)
+	}
+	assert.True(t, (&Config{}).Merge(&Config{Offline: true}).Offline, "either setting turns offline mode on")
+	assert.True(t, (&Config{Offline: true}).Merge(&Config{}).Offline)
+
+	t.Setenv("FLOWSPEC_OFFLINE", "1")
+	assert.True(t, IsOffline(&Config{}), "the environment applies to explicit settings too")
+	_, err := New(&Config{}, time.Second).Get("http://contracts.example.com/orders.yaml")
+	assert.ErrorIs(t, err, ErrOffline)
+}
+
+func TestIsLoopback(t *testing.T) {
+	for _, host := range []string{"localhost", "LOCALHOST", "api.localhost", "127.0.0.1", "127.1.2.3", "::1"} {
+		assert.True(t, IsLoopback(host), host)
+	}
+	for _, host := range []string{"example.com", "10.0.0.1", "localhost.example.com", ""} {
+		assert.False(t, IsLoopback(host), host)
+	}
+}
+
+// writeClientCertificate writes a self-signed client certificate and its key
+func writeClientCertificate(t *testing.T, dir string) (string, string, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "flowspec-ci"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile, certificate
+}
@@ -40,10 +40,11 @@ type Localizer struct {
 	messages map[string]string
 }
 
-// NewLocalizer creates a new localizer with the specified language
+// NewLocalizer creates a new localizer with the specified language; regional variants such as
+// zh-CN select their language
 func NewLocalizer(lang SupportedLanguage) *Localizer {
 	l := &Localizer{
-		language: lang,
+		language: normalizeLanguage(lang),
 		messages: make(map[string]string),
 	}
 	l.loadMessages()
@@ -82,45 +83,71 @@ func (l *Localizer) GetLanguage() SupportedLanguage {
 
 // SetLanguage changes the current language
 func (l *Localizer) SetLanguage(lang SupportedLanguage) {
-	l.language = lang
+	l.language = normalizeLanguage(lang)
 	l.loadMessages()
 }
 
-// detectLanguageFromEnv detects language from environment variables
+// detectLanguageFromEnv detects language from environment variables: FLOWSPEC_LANG, then the
+// POSIX locale variables LC_ALL, LC_MESSAGES and LANG, skipping the C and POSIX locales
 func detectLanguageFromEnv() SupportedLanguage {
-	// Check FLOWSPEC_LANG first
-	if lang := os.Getenv("FLOWSPEC_LANG"); lang != "" {
-		return SupportedLanguage(lang)
-	}
-
-	// Check LANG environment variable
-	if lang := os.Getenv("LANG"); lang != "" {
-		lang = strings.ToLower(lang)
-		if strings.HasPrefix(lang, "zh") {
-			if strings.Contains(lang, "tw") || strings.Contains(lang, "hk") {
-				return LanguageChineseTraditional
-			}
-			return LanguageChinese
-		}
-		if strings.HasPrefix(lang, "ja") {
-			return LanguageJapanese
+	for _, name := range []string{"FLOWSPEC_LANG", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" || isNeutralLocale(value) {
+			continue
 		}
-		if strings.HasPrefix(lang, "ko") {
-			return LanguageKorean
+		if lang, ok := ParseLanguage(value); ok {
+			return lang
 		}
-		if strings.HasPrefix(lang, "fr") {
-			return LanguageFrench
+		// A locale that is set but unsupported is not overridden by a less specific variable
+		return LanguageEnglish
+	}
+
+	// Default to English
+	return LanguageEnglish
+}
+
+// ParseLanguage maps a --lang value, a BCP 47 tag or a POSIX locale to a supported language,
+// e.g. "zh-CN", "zh_CN.UTF-8" and "zh-Hans" to zh, "zh-HK" and "zh-Hant" to zh-TW, and
+// "en-US" to en
+func ParseLanguage(value string) (SupportedLanguage, bool) {
+	tag := strings.ToLower(strings.TrimSpace(value))
+	if dot := strings.IndexAny(tag, ".@"); dot >= 0 {
+		tag = tag[:dot]
+	}
+	tag = strings.ReplaceAll(tag, "_", "-")
+	primary, region, _ := strings.Cut(tag, "-")
+
+	if primary == "zh" {
+		switch region {
+		case "tw", "hk", "mo", "hant":
+			return LanguageChineseTraditional, true
 		}
-		if strings.HasPrefix(lang, "de") {
-			return LanguageGerman
+		if strings.HasPrefix(region, "hant") {
+			return LanguageChineseTraditional, true
 		}
-		if strings.HasPrefix(lang, "es") {
-			return LanguageSpanish
+		return LanguageChinese, true
+	}
+	for _, lang := range GetSupportedLanguages() {
+		if primary == string(lang) {
+			return lang, true
 		}
 	}
+	return LanguageEnglish, false
+}
 
-	// Default to English
-	return LanguageEnglish
+// normalizeLanguage returns the supported language of a regional variant, and other values
+// unchanged
+func normalizeLanguage(lang SupportedLanguage) SupportedLanguage {
+	if parsed, ok := ParseLanguage(string(lang)); ok {
+		return parsed
+	}
+	return lang
+}
+
+// isNeutralLocale reports whether a locale is the C or POSIX locale, which names no language
+func isNeutralLocale(value string) bool {
+	locale, _, _ := strings.Cut(strings.ToUpper(value), ".")
+	return locale == "C" || locale == "POSIX"
 }
 
 // loadMessages loads messages for the current language
@@ -197,6 +197,18 @@ func TestDetectLanguageFromEnv(t *testing.T) {
 			langVar:      "fr_FR.UTF-8",
 			expected:     LanguageFrench,
 		},
+		{
+			name:         "FLOWSPEC_LANG regional variant",
+			flowspecLang: "zh-CN",
+			langVar:      "en_US.UTF-8",
+			expected:     LanguageChinese,
+		},
+		{
+			name:         "C locale defaults to English",
+			flowspecLang: "",
+			langVar:      "C.UTF-8",
+			expected:     LanguageEnglish,
+		},
 		{
 			name:         "Unsupported language defaults to English",
 			flowspecLang: "",
@@ -216,6 +228,8 @@ func TestDetectLanguageFromEnv(t *testing.T) {
 			// Clear environment
 			os.Unsetenv("FLOWSPEC_LANG")
 			os.Unsetenv("LANG")
+			t.Setenv("LC_ALL", "")
+			t.Setenv("LC_MESSAGES", "")
 
 			// Set test environment
 			if tt.flowspecLang != "" {
@@ -258,3 +272,49 @@ func TestAllLanguagesHaveBasicTranslations(t *testing.T) {
 		}
 	}
 }
+
+func TestDetectLanguageFromEnv_LocaleCategories(t *testing.T) {
+	t.Setenv("FLOWSPEC_LANG", "")
+	t.Setenv("LANG", "de_DE.UTF-8")
+	t.Setenv("LC_MESSAGES", "ja_JP.UTF-8")
+	t.Setenv("LC_ALL", "zh_CN.UTF-8")
+	if lang := detectLanguageFromEnv(); lang != LanguageChinese {
+		t.Errorf("LC_ALL should take precedence, got %s", lang)
+	}
+
+	t.Setenv("LC_ALL", "C")
+	if lang := detectLanguageFromEnv(); lang != LanguageJapanese {
+		t.Errorf("the C locale should be skipped for LC_MESSAGES, got %s", lang)
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected SupportedLanguage
+		ok       bool
+	}{
+		{"en", LanguageEnglish, true},
+		{"en-US", LanguageEnglish, true},
+		{"zh-CN", LanguageChinese, true},
+		{"zh_CN.UTF-8", LanguageChinese, true},
+		{"zh-Hans", LanguageChinese, true},
+		{"zh-TW", LanguageChineseTraditional, true},
+		{"zh_HK.UTF-8", LanguageChineseTraditional, true},
+		{"zh-Hant-TW", LanguageChineseTraditional, true},
+		{"FR_fr@euro", LanguageFrench, true},
+		{"tlh", LanguageEnglish, false},
+		{"", LanguageEnglish, false},
+	}
+
+	for _, tt := range tests {
+		lang, ok := ParseLanguage(tt.value)
+		if lang != tt.expected || ok != tt.ok {
+			t.Errorf("ParseLanguage(%q) = %s, %v, expected %s, %v", tt.value, lang, ok, tt.expected, tt.ok)
+		}
+	}
+
+	if lang := NewLocalizer("zh-CN").GetLanguage(); lang != LanguageChinese {
+		t.Errorf("NewLocalizer(zh-CN) should select %s, got %s", LanguageChinese, lang)
+	}
+}
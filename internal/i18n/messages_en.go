@@ -27,7 +27,108 @@ var englishMessages = map[string]string{
 	"summary.success":      "Success: %d",
 	"summary.failed":       "Failed: %d",
 	"summary.skipped":      "Skipped: %d",
+	"summary.coverage":     "Coverage: %d/%d operations exercised (%.1f%%)",
 	"summary.success_rate": "(%.1f%%)",
+	"summary.incomplete":   "Incomplete: %s, results cover only the specs that finished",
+	"summary.fail_fast":    "Stopped at the first critical failure in %s (--fail-fast), %d spec(s) not aligned",
+
+	// Contract coverage
+	"coverage.samples":         "%d samples",
+	"coverage.not_exercised":   "not exercised",
+	"coverage.below_threshold": "Validation result: ❌ Coverage %.1f%% is below the required minimum of %.1f%%",
+	"coverage.estimated":       "≈%.0f requests",
+	"coverage.sampled_out":     "up to %d request(s) may have been sampled out",
+
+	// Trace sampling
+	"sampling.note":              "Traces sampled at %.1f%% (%s): request counts are extrapolated, intervals and bounds hold at %.0f%% confidence",
+	"sampling.source.config":     "--trace-sample-rate",
+	"sampling.source.attributes": "span attributes",
+
+	// Unmatched spans
+	"unmatched.title": "🔎 Unmatched Spans (%d)",
+	"unmatched.none":  "All spans matched a spec",
+
+	// Trace completeness
+	"completeness.title": "Trace completeness: %d structural issue(s) in %d spans, results may be misleading",
+	"completeness.more":  "... and %d more",
+	"completeness.hint":  "Set engine.failOnIncompleteTrace to refuse such traces",
+
+	// Clock skew correction
+	"clock_skew.corrected": "Clock skew corrected: %d span(s) moved, by up to %s",
+
+	// Duplicate matched spans
+	"duplicate_spans.dropped": "Duplicate spans ignored: %d span(s) shared the ID of a matched span; only the last span with an ID was evaluated",
+
+	// Spans matching several operations
+	"ambiguity.title":      "Ambiguous matches: %d span(s) matched several operations",
+	"ambiguity.resolved":   "evaluated by %s",
+	"ambiguity.unresolved": "evaluated by all of them (%s)",
+	"ambiguity.more":       "... and %d more",
+	"ambiguity.hint":       "Set matcher.ambiguity to most-specific-wins, fail or all to choose how they are evaluated",
+
+	// Explain mode
+	"explain.title":       "Match explanation",
+	"explain.accepted_by": "accepted by %s",
+	"explain.rejected":    "rejected",
+
+	// Per-service summary
+	"services.title": "🧩 Services (%d)",
+	"services.line":  "%d/%d specs passed, %d failed, %d/%d operations exercised",
+
+	// Per-tag summary
+	"tags.title": "🏷️ Tags (%d)",
+	"tags.line":  "%d/%d operations passed, %d failed, %d/%d operations exercised",
+
+	// Per-trace summary
+	"traces.title": "🧵 Traces (%d, %d failed)",
+	"traces.line":  "%d/%d specs passed, %d failed (%d spans)",
+	"traces.error": "not aligned: %s",
+
+	// Result warnings
+	"result.warning": "Warning",
+	"result.error":   "Error",
+	"result.trace":   "Trace: %s",
+
+	// Validation details
+	"detail.expression":     "Expression",
+	"detail.expected":       "Expected",
+	"detail.actual":         "Actual",
+	"detail.failure_reason": "Failure reason",
+	"detail.context":        "Context",
+	"detail.span_name":      "Span name",
+	"detail.span_status":    "Status",
+	"detail.suggestions":    "Suggestions",
+
+	// HTML report
+	"html.generated":      "Generated %s",
+	"html.filter_all":     "All",
+	"html.filter_failed":  "Failed only",
+	"html.operation":      "Operation",
+	"html.status":         "Status",
+	"html.samples":        "Samples",
+	"html.assertions":     "Assertions passed",
+	"html.expected":       "Expected",
+	"html.actual":         "Actual",
+	"html.expression":     "Expression",
+	"html.failure_reason": "Failure reason",
+	"html.suggestions":    "Suggestions",
+	"html.span_context":   "Span context",
+
+	// Failure groups
+	"groups.title":          "Failure groups (%d)",
+	"groups.representative": "representative span %s (trace %s)",
+
+	// Trace UI links
+	"trace.link": "Trace",
+
+	// Exit policy
+	"exit.skipped_not_allowed":  "❌ %d ServiceSpec(s) skipped without matching spans",
+	"exit.warnings_not_allowed": "❌ %d warning(s) reported",
+	"exit.not_enforced":         "The exit policy does not fail the run on these problems (exit code 0)",
+
+	// Markdown summary
+	"markdown.more_failures": "…and %d more failure(s)",
+	"markdown.warnings":      "%d warning(s), see the full report for details",
 
 	// Performance metrics
 	"performance.processing_rate":    "Processing Rate: %.2f specs/sec",
@@ -53,8 +154,8 @@ var englishMessages = map[string]string{
 	"result.no_matching_spans_for_op": "No matching spans found for operation: %s",
 
 	// Final status messages
-	"status.success":                 "✅ Success (All assertions passed)",
-	"status.failed":                  "❌ Failed (%d assertions failed)",
+	"status.success":                 "Validation result: ✅ Success (all assertions passed)",
+	"status.failed":                  "Validation result: ❌ Failed (%d assertions failed)",
 	"status.congratulations":         "🎉 Congratulations! All %d ServiceSpecs comply with expected specifications.",
 	"status.suggestions":             "💡 Suggestions:",
 	"status.suggestion.check_failed": "• Check failed assertions to see if they reflect actual service behavior changes",
@@ -27,14 +27,115 @@ var chineseMessages = map[string]string{
 	"summary.success":      "成功: %d 个",
 	"summary.failed":       "失败: %d 个",
 	"summary.skipped":      "跳过: %d 个",
+	"summary.coverage":     "覆盖率: %d/%d 个操作被覆盖 (%.1f%%)",
 	"summary.success_rate": "(%.1f%%)",
+	"summary.incomplete":   "结果不完整: %s，仅包含已完成的 ServiceSpec",
+	"summary.fail_fast":    "在 %s 的第一个关键失败处停止 (--fail-fast)，%d 个 ServiceSpec 未对齐",
+
+	// Contract coverage
+	"coverage.samples":         "%d 个样本",
+	"coverage.not_exercised":   "未覆盖",
+	"coverage.below_threshold": "验证结果: ❌ 覆盖率 %.1f%% 低于要求的最小值 %.1f%%",
+	"coverage.estimated":       "约 %.0f 个请求",
+	"coverage.sampled_out":     "最多 %d 个请求可能因采样未被记录",
+
+	// Trace sampling
+	"sampling.note":              "Trace 采样率 %.1f%% (%s): 请求数为推算值，区间和上限的置信度为 %.0f%%",
+	"sampling.source.config":     "--trace-sample-rate",
+	"sampling.source.attributes": "Span 属性",
+
+	// Unmatched spans
+	"unmatched.title": "🔎 未匹配的 Span (%d 个)",
+	"unmatched.none":  "所有 Span 均已匹配规约",
+
+	// Trace completeness
+	"completeness.title": "Trace 完整性: %d 个结构问题 (共 %d 个 Span)，结果可能有误",
+	"completeness.more":  "... 另有 %d 个",
+	"completeness.hint":  "设置 engine.failOnIncompleteTrace 可拒绝此类 Trace",
+
+	// Clock skew correction
+	"clock_skew.corrected": "已校正时钟偏差: 移动了 %d 个 Span，最大偏移 %s",
+
+	// Duplicate matched spans
+	"duplicate_spans.dropped": "已忽略重复 Span: %d 个 Span 与匹配的 Span ID 相同，每个 ID 只评估最后一个 Span",
+
+	// Spans matching several operations
+	"ambiguity.title":      "匹配歧义: %d 个 Span 匹配了多个操作",
+	"ambiguity.resolved":   "由 %s 评估",
+	"ambiguity.unresolved": "由所有匹配的操作评估 (%s)",
+	"ambiguity.more":       "... 以及另外 %d 个",
+	"ambiguity.hint":       "设置 matcher.ambiguity 为 most-specific-wins、fail 或 all 以选择评估方式",
+
+	// Explain mode
+	"explain.title":       "匹配说明",
+	"explain.accepted_by": "由 %s 接受",
+	"explain.rejected":    "已拒绝",
+
+	// Per-service summary
+	"services.title": "🧩 服务 (%d 个)",
+	"services.line":  "%d/%d 个规约通过, %d 个失败, %d/%d 个操作被覆盖",
+
+	// Per-tag summary
+	"tags.title": "🏷️ 标签 (%d 个)",
+	"tags.line":  "%d/%d 个操作通过, %d 个失败, %d/%d 个操作被覆盖",
+
+	// Per-trace summary
+	"traces.title": "🧵 Trace (%d 个, %d 个失败)",
+	"traces.line":  "%d/%d 个规约通过, %d 个失败 (%d 个 Span)",
+	"traces.error": "未能对齐: %s",
+
+	// Result warnings
+	"result.warning": "警告",
+	"result.error":   "错误信息",
+	"result.trace":   "Trace: %s",
+
+	// Validation details
+	"detail.expression":     "表达式",
+	"detail.expected":       "期望",
+	"detail.actual":         "实际",
+	"detail.failure_reason": "失败原因",
+	"detail.context":        "上下文信息",
+	"detail.span_name":      "Span 名称",
+	"detail.span_status":    "状态",
+	"detail.suggestions":    "建议",
+
+	// HTML report
+	"html.generated":      "生成于 %s",
+	"html.filter_all":     "全部",
+	"html.filter_failed":  "仅失败",
+	"html.operation":      "操作",
+	"html.status":         "状态",
+	"html.samples":        "样本数",
+	"html.assertions":     "通过的断言",
+	"html.expected":       "期望值",
+	"html.actual":         "实际值",
+	"html.expression":     "表达式",
+	"html.failure_reason": "失败原因",
+	"html.suggestions":    "建议",
+	"html.span_context":   "Span 上下文",
+
+	// Failure groups
+	"groups.title":          "失败分组 (%d 组)",
+	"groups.representative": "代表性 Span %s (Trace %s)",
+
+	// Trace UI links
+	"trace.link": "Trace 链接",
+
+	// Exit policy
+	"exit.skipped_not_allowed":  "❌ %d 个 ServiceSpec 因没有匹配的 Span 而被跳过",
+	"exit.warnings_not_allowed": "❌ 报告了 %d 个警告",
+	"exit.not_enforced":         "根据退出策略，上述问题不会导致运行失败（退出码 0）",
+
+	// Markdown summary
+	"markdown.more_failures": "……另有 %d 个失败",
+	"markdown.warnings":      "%d 个警告，详情请查看完整报告",
 
 	// Performance metrics
 	"performance.processing_rate":    "处理速度: %.2f specs/秒",
 	"performance.memory_usage":       "内存使用: %.2f MB",
 	"performance.concurrent_workers": "并发工作线程: %d 个",
 	"performance.assertions":         "断言评估: %d 个",
-	"performance.execution_time":     "⏱️ 执行时间: %v",
+	"performance.execution_time":     "执行时间: %v",
 	"performance.average_time":       "平均处理时间: %v/spec",
 
 	// Result sections
@@ -43,14 +144,14 @@ var chineseMessages = map[string]string{
 	"results.skipped": "⏭️ 跳过的验证 (%d 个)",
 
 	// Result details
-	"result.execution_time":           "⏱️ 执行时间: %v",
-	"result.matched_span":             "🎯 匹配的 Span: %s",
-	"result.assertion_stats":          "📊 断言统计: %d 总计, %d 通过, %d 失败",
-	"result.preconditions":            "✅ 前置条件: (%d/%d 通过)",
-	"result.postconditions":           "✅ 后置条件: (%d/%d 通过)",
-	"result.no_matching_spans":        "🔍 未找到匹配的 Span",
-	"result.span_matching":            "🔗 Span 匹配:",
-	"result.no_matching_spans_for_op": "✅ No matching spans found for operation: %s",
+	"result.execution_time":           "执行时间: %v",
+	"result.matched_span":             "匹配的 Span: %s",
+	"result.assertion_stats":          "断言统计: %d 总计, %d 通过, %d 失败",
+	"result.preconditions":            "前置条件: (%d/%d 通过)",
+	"result.postconditions":           "后置条件: (%d/%d 通过)",
+	"result.no_matching_spans":        "未找到匹配的 Span",
+	"result.span_matching":            "Span 匹配:",
+	"result.no_matching_spans_for_op": "未找到操作 %s 的匹配 Span",
 
 	// Final status messages
 	"status.success":                 "验证结果: ✅ 成功 (所有断言通过)",
@@ -389,7 +389,7 @@ func (ti *DefaultTraceIngestor) convertOTLPToTraceData(otlpTrace OTLPTrace, metr
 				}
 
 				// Add to spans map
-				traceData.Spans[span.SpanID] = span
+				traceData.AddSpan(span)
 				metrics.TotalSpans++
 			}
 		}
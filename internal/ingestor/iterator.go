@@ -23,6 +23,7 @@ type ChannelIterator[T any] struct {
 	errCh    <-chan error
 	current  T
 	err      error
+	pending  error // Error received while records were still buffered
 	closed   bool
 	ctx      context.Context
 	cancel   context.CancelFunc
@@ -55,15 +56,27 @@ func (c *ChannelIterator[T]) Next() bool {
 		return false
 	}
 	
+	// Records buffered before an error or the end of the data are delivered first
 	select {
 	case item, ok := <-c.ch:
-		if !ok {
-			c.closed = true
-			return false
-		}
-		c.current = item
-		return true
+		return c.receive(item, ok)
+	default:
+	}
+	if c.pending != nil {
+		c.err = c.pending
+		return false
+	}
+	
+	select {
+	case item, ok := <-c.ch:
+		return c.receive(item, ok)
 	case err := <-c.errCh:
+		c.pending = err
+		select {
+		case item, ok := <-c.ch:
+			return c.receive(item, ok)
+		default:
+		}
 		c.err = err
 		return false
 	case <-c.ctx.Done():
@@ -72,6 +85,25 @@ func (c *ChannelIterator[T]) Next() bool {
 	}
 }
 
+// receive handles a receive from the data channel. Once it is closed, an error sent before
+// closing it is still reported, whichever of the two the select picked first.
+func (c *ChannelIterator[T]) receive(item T, ok bool) bool {
+	if ok {
+		c.current = item
+		return true
+	}
+	c.closed = true
+	c.err = c.pending
+	if c.err == nil {
+		select {
+		case err := <-c.errCh:
+			c.err = err
+		default:
+		}
+	}
+	return false
+}
+
 // Value returns the current item
 func (c *ChannelIterator[T]) Value() T {
 	c.mu.RLock()
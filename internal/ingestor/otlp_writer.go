@@ -0,0 +1,124 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingestor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// fixtureScope names the instrumentation scope of traces written by WriteOTLP
+const fixtureScope = "flowspec-cli"
+
+// WriteOTLP writes spans as OTLP JSON, which IngestFromReader reads back into the same spans.
+// Spans are written in start time order under a single resource and scope; a status mapped by
+// status rules is written with the code set by the instrumentation, so rules apply again.
+func WriteOTLP(writer io.Writer, spans []*models.Span) error {
+	sorted := make([]*models.Span, len(spans))
+	copy(sorted, spans)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].StartTime != sorted[j].StartTime {
+			return sorted[i].StartTime < sorted[j].StartTime
+		}
+		return sorted[i].SpanID < sorted[j].SpanID
+	})
+
+	otlpSpans := make([]OTLPSpan, len(sorted))
+	for i, span := range sorted {
+		otlpSpans[i] = toOTLPSpan(span)
+	}
+	trace := OTLPTrace{ResourceSpans: []ResourceSpan{{
+		Resource:   Resource{Attributes: []Attribute{}},
+		ScopeSpans: []ScopeSpan{{Scope: Scope{Name: fixtureScope}, Spans: otlpSpans}},
+	}}}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(trace); err != nil {
+		return fmt.Errorf("failed to write OTLP trace: %w", err)
+	}
+	return nil
+}
+
+// toOTLPSpan converts a span to the OTLP JSON format
+func toOTLPSpan(span *models.Span) OTLPSpan {
+	code := span.Status.Code
+	if span.Status.Original != "" {
+		code = span.Status.Original
+	}
+	otlpSpan := OTLPSpan{
+		TraceID:           span.TraceID,
+		SpanID:            span.SpanID,
+		ParentSpanID:      span.ParentID,
+		Name:              span.Name,
+		StartTimeUnixNano: strconv.FormatInt(span.StartTime, 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.EndTime, 10),
+		Attributes:        toOTLPAttributes(span.Attributes),
+		Status:            Status{Code: toOTLPStatusCode(code), Message: span.Status.Message},
+		Events:            []Event{},
+	}
+	for _, event := range span.Events {
+		otlpSpan.Events = append(otlpSpan.Events, Event{
+			TimeUnixNano: strconv.FormatInt(event.Timestamp, 10),
+			Name:         event.Name,
+			Attributes:   toOTLPAttributes(event.Attributes),
+		})
+	}
+	return otlpSpan
+}
+
+// toOTLPAttributes converts attributes to OTLP key-values sorted by key; values that have no
+// OTLP scalar type, such as maps, are written as they are and read back unchanged
+func toOTLPAttributes(attributes map[string]interface{}) []Attribute {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	otlpAttributes := make([]Attribute, len(keys))
+	for i, key := range keys {
+		value := attributes[key]
+		switch typed := value.(type) {
+		case string:
+			value = map[string]interface{}{"stringValue": typed}
+		case bool:
+			value = map[string]interface{}{"boolValue": typed}
+		case int, int32, int64:
+			value = map[string]interface{}{"intValue": typed}
+		case float32, float64:
+			value = map[string]interface{}{"doubleValue": typed}
+		}
+		otlpAttributes[i] = Attribute{Key: key, Value: value}
+	}
+	return otlpAttributes
+}
+
+// toOTLPStatusCode converts a span status code to its OTLP value
+func toOTLPStatusCode(code string) StatusCode {
+	switch code {
+	case "OK":
+		return 1
+	case "ERROR":
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,76 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingestor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteOTLP(t *testing.T) {
+	spans := []*models.Span{
+		{
+			SpanID: "child", TraceID: "t1", ParentID: "root", Name: "GET /orders/{id}", StartTime: 1200, EndTime: 1800,
+			Status: models.SpanStatus{Code: "OK", Message: "", Original: "UNSET"},
+			Attributes: map[string]interface{}{
+				"http.method":      "GET",
+				"http.status_code": float64(200),
+				"cache.hit":        true,
+				"db.rows":          int64(3),
+				"request.body":     map[string]interface{}{"id": "42"},
+				"request.tags":     []interface{}{"a", "b"},
+			},
+			Events: []models.SpanEvent{{Name: "retry", Timestamp: 1300, Attributes: map[string]interface{}{"attempt": float64(2)}}},
+		},
+		{
+			SpanID: "root", TraceID: "t1", Name: "gateway", StartTime: 1000, EndTime: 2000,
+			Status: models.SpanStatus{Code: "ERROR", Message: "upstream failed"},
+		},
+	}
+
+	var output bytes.Buffer
+	require.NoError(t, WriteOTLP(&output, spans))
+	assert.Less(t, bytes.Index(output.Bytes(), []byte(`"spanId": "root"`)), bytes.Index(output.Bytes(), []byte(`"spanId": "child"`)),
+		"spans are written in start time order")
+
+	traceData, err := NewTraceIngestor().IngestFromReader(&output)
+	require.NoError(t, err)
+	require.Len(t, traceData.Spans, 2)
+	assert.Equal(t, "root", traceData.RootSpan.SpanID)
+
+	child := traceData.Spans["child"]
+	assert.Equal(t, "root", child.ParentID)
+	assert.Equal(t, int64(1200), child.StartTime)
+	assert.Equal(t, int64(1800), child.EndTime)
+	assert.Equal(t, models.SpanStatus{Code: "UNSET"}, child.Status, "the instrumentation's status is written")
+	assert.Equal(t, map[string]interface{}{
+		"http.method":      "GET",
+		"http.status_code": float64(200),
+		"cache.hit":        true,
+		"db.rows":          float64(3),
+		"request.body":     map[string]interface{}{"id": "42"},
+		"request.tags":     []interface{}{"a", "b"},
+	}, child.Attributes)
+	require.Len(t, child.Events, 1)
+	assert.Equal(t, models.SpanEvent{Name: "retry", Timestamp: 1300, Attributes: map[string]interface{}{"attempt": float64(2)}}, child.Events[0])
+
+	root := traceData.Spans["root"]
+	assert.Equal(t, models.SpanStatus{Code: "ERROR", Message: "upstream failed"}, root.Status)
+	assert.Empty(t, root.Attributes)
+}
@@ -276,7 +276,7 @@ func (si *StreamingIngestor) processBatch(batch []OTLPSpan, traceData *models.Tr
 		}
 
 		// Add to spans map
-		traceData.Spans[span.SpanID] = span
+		traceData.AddSpan(span)
 
 		// Check memory usage periodically
 		if len(traceData.Spans)%100 == 0 {
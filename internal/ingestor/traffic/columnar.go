@@ -0,0 +1,475 @@
+package traffic
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Columnar traffic files hold normalized records, so repeated runs over the same logs skip
+// parsing them. A file starts with columnarMagic, followed by blocks of up to
+// columnarBlockRecords records, each a uvarint record count, the uvarint length of its
+// payload and the zstd-compressed payload. A zero count ends the blocks and is followed by
+// the uvarint length of the JSON ColumnarFooter and the footer itself.
+//
+// A payload holds a dictionary of the strings of its block, then one column per record
+// field: dictionary indexes for strings, uvarints for the status, varint deltas for the
+// timestamp seconds, varints for the body size and IEEE 754 bits for durations. Query and
+// header maps are a uvarint of their size plus one (zero for nil), then per key in order its
+// dictionary index, the number of values and their indexes.
+const (
+	// ColumnarFormat is the format name of the ingestor reading columnar traffic files
+	ColumnarFormat = "columnar"
+	// ColumnarExtension is the file extension of columnar traffic files
+	ColumnarExtension = ".fstraffic"
+	// ColumnarVersion is the version of the columnar file layout written
+	ColumnarVersion = 1
+
+	// columnarBlockRecords is the number of records compressed together
+	columnarBlockRecords = 4096
+)
+
+// columnarMagic starts every columnar traffic file
+var columnarMagic = []byte("FSTRAFC1")
+
+// ErrCorruptColumnar is returned when a columnar traffic file is truncated or malformed
+var ErrCorruptColumnar = errors.New("corrupt columnar traffic file")
+
+// ColumnarFooter describes the contents of a columnar traffic file and how they were ingested
+type ColumnarFooter struct {
+	Version   int              `json:"version"`
+	CreatedAt time.Time        `json:"createdAt"`
+	Records   int64            `json:"records"`
+	Sources   []ColumnarSource `json:"sources"`
+	Format    string           `json:"format,omitempty"`    // Ingestor of the sources
+	LogFormat string           `json:"logFormat,omitempty"` // Log format of the sources, if any
+	Metrics   *IngestMetrics   `json:"metrics,omitempty"`   // Metrics of the conversion
+}
+
+// ColumnarSource is an input file of a conversion
+type ColumnarSource struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+// columnarWriter writes records as a columnar traffic file
+type columnarWriter struct {
+	writer  *bufio.Writer
+	encoder *zstd.Encoder
+	records []*NormalizedRecord
+	written int64
+	bytes   int64
+}
+
+// newColumnarWriter writes the start of a columnar traffic file to writer
+func newColumnarWriter(writer io.Writer) (*columnarWriter, error) {
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, err
+	}
+	w := &columnarWriter{writer: bufio.NewWriter(writer), encoder: encoder}
+	if err := w.write(columnarMagic); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write adds a record, compressing a block whenever it is full
+func (w *columnarWriter) Write(record *NormalizedRecord) error {
+	w.records = append(w.records, record)
+	if len(w.records) < columnarBlockRecords {
+		return nil
+	}
+	return w.flush()
+}
+
+// Close writes the remaining records and the footer, counting the records written
+func (w *columnarWriter) Close(footer ColumnarFooter) error {
+	defer w.encoder.Close()
+	if err := w.flush(); err != nil {
+		return err
+	}
+	footer.Version = ColumnarVersion
+	footer.Records = w.written
+	content, err := json.Marshal(footer)
+	if err != nil {
+		return fmt.Errorf("failed to encode columnar footer: %w", err)
+	}
+	end := binary.AppendUvarint(nil, 0)
+	end = binary.AppendUvarint(end, uint64(len(content)))
+	if err := w.write(append(end, content...)); err != nil {
+		return err
+	}
+	return w.writer.Flush()
+}
+
+// Bytes returns the size of the file written so far
+func (w *columnarWriter) Bytes() int64 {
+	return w.bytes
+}
+
+// flush compresses the buffered records into a block
+func (w *columnarWriter) flush() error {
+	if len(w.records) == 0 {
+		return nil
+	}
+	payload := w.encoder.EncodeAll(encodeColumnarBlock(w.records), nil)
+	header := binary.AppendUvarint(nil, uint64(len(w.records)))
+	header = binary.AppendUvarint(header, uint64(len(payload)))
+	if err := w.write(header); err != nil {
+		return err
+	}
+	if err := w.write(payload); err != nil {
+		return err
+	}
+	w.written += int64(len(w.records))
+	w.records = w.records[:0]
+	return nil
+}
+
+// write writes data, counting its size
+func (w *columnarWriter) write(data []byte) error {
+	n, err := w.writer.Write(data)
+	w.bytes += int64(n)
+	return err
+}
+
+// columnarDictionary numbers the distinct strings of a block in order of appearance
+type columnarDictionary struct {
+	indexes map[string]uint64
+	values  []string
+}
+
+// index returns the index of value, adding it when it is new
+func (d *columnarDictionary) index(value string) uint64 {
+	index, ok := d.indexes[value]
+	if !ok {
+		index = uint64(len(d.values))
+		d.indexes[value] = index
+		d.values = append(d.values, value)
+	}
+	return index
+}
+
+// encodeColumnarBlock encodes records column by column
+func encodeColumnarBlock(records []*NormalizedRecord) []byte {
+	dictionary := &columnarDictionary{indexes: make(map[string]uint64)}
+	var columns []byte
+	for _, field := range []func(*NormalizedRecord) string{
+		func(r *NormalizedRecord) string { return r.Method },
+		func(r *NormalizedRecord) string { return r.Path },
+		func(r *NormalizedRecord) string { return r.RawPath },
+		func(r *NormalizedRecord) string { return r.Host },
+		func(r *NormalizedRecord) string { return r.Scheme },
+	} {
+		for _, record := range records {
+			columns = binary.AppendUvarint(columns, dictionary.index(field(record)))
+		}
+	}
+	for _, record := range records {
+		columns = binary.AppendUvarint(columns, uint64(record.Status))
+	}
+	var previous int64
+	for _, record := range records {
+		seconds := record.Timestamp.Unix()
+		columns = binary.AppendVarint(columns, seconds-previous)
+		previous = seconds
+	}
+	for _, record := range records {
+		columns = binary.AppendUvarint(columns, uint64(record.Timestamp.Nanosecond()))
+	}
+	for _, record := range records {
+		columns = binary.AppendVarint(columns, record.BodyBytes)
+	}
+	for _, record := range records {
+		columns = binary.LittleEndian.AppendUint64(columns, math.Float64bits(record.DurationMs))
+	}
+	for _, record := range records {
+		columns = binary.LittleEndian.AppendUint64(columns, math.Float64bits(record.UpstreamDurationMs))
+	}
+	for _, record := range records {
+		columns = appendColumnarMap(columns, dictionary, record.Query)
+	}
+	for _, record := range records {
+		columns = appendColumnarMap(columns, dictionary, record.Headers)
+	}
+
+	block := binary.AppendUvarint(nil, uint64(len(dictionary.values)))
+	for _, value := range dictionary.values {
+		block = binary.AppendUvarint(block, uint64(len(value)))
+		block = append(block, value...)
+	}
+	return append(block, columns...)
+}
+
+// appendColumnarMap encodes a query or header map
+func appendColumnarMap(columns []byte, dictionary *columnarDictionary, values map[string][]string) []byte {
+	if values == nil {
+		return binary.AppendUvarint(columns, 0)
+	}
+	columns = binary.AppendUvarint(columns, uint64(len(values))+1)
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		columns = binary.AppendUvarint(columns, dictionary.index(key))
+		columns = binary.AppendUvarint(columns, uint64(len(values[key])))
+		for _, value := range values[key] {
+			columns = binary.AppendUvarint(columns, dictionary.index(value))
+		}
+	}
+	return columns
+}
+
+// columnarDecoder reads the values of a block payload, keeping the first error
+type columnarDecoder struct {
+	data       []byte
+	dictionary []string
+	err        error
+}
+
+// decodeColumnarBlock decodes the count records of a block payload
+func decodeColumnarBlock(data []byte, count int) ([]*NormalizedRecord, error) {
+	decoder := &columnarDecoder{data: data}
+	size := decoder.uvarint()
+	if size > uint64(len(data)) {
+		return nil, fmt.Errorf("%w: dictionary of %d strings", ErrCorruptColumnar, size)
+	}
+	decoder.dictionary = make([]string, size)
+	for i := range decoder.dictionary {
+		decoder.dictionary[i] = string(decoder.bytes(decoder.uvarint()))
+	}
+
+	records := make([]*NormalizedRecord, count)
+	for i := range records {
+		records[i] = &NormalizedRecord{}
+	}
+	for _, field := range []func(*NormalizedRecord) *string{
+		func(r *NormalizedRecord) *string { return &r.Method },
+		func(r *NormalizedRecord) *string { return &r.Path },
+		func(r *NormalizedRecord) *string { return &r.RawPath },
+		func(r *NormalizedRecord) *string { return &r.Host },
+		func(r *NormalizedRecord) *string { return &r.Scheme },
+	} {
+		for _, record := range records {
+			*field(record) = decoder.string()
+		}
+	}
+	for _, record := range records {
+		record.Status = int(decoder.uvarint())
+	}
+	seconds := make([]int64, count)
+	var previous int64
+	for i := range records {
+		previous += decoder.varint()
+		seconds[i] = previous
+	}
+	for i, record := range records {
+		record.Timestamp = time.Unix(seconds[i], int64(decoder.uvarint())).UTC()
+	}
+	for _, record := range records {
+		record.BodyBytes = decoder.varint()
+	}
+	for _, record := range records {
+		record.DurationMs = decoder.float()
+	}
+	for _, record := range records {
+		record.UpstreamDurationMs = decoder.float()
+	}
+	for _, record := range records {
+		record.Query = decoder.stringMap()
+	}
+	for _, record := range records {
+		record.Headers = decoder.stringMap()
+	}
+
+	if decoder.err == nil && len(decoder.data) > 0 {
+		decoder.err = fmt.Errorf("%w: %d trailing bytes in block", ErrCorruptColumnar, len(decoder.data))
+	}
+	if decoder.err != nil {
+		return nil, decoder.err
+	}
+	return records, nil
+}
+
+// uvarint reads an unsigned varint
+func (d *columnarDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	value, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = fmt.Errorf("%w: truncated block", ErrCorruptColumnar)
+		return 0
+	}
+	d.data = d.data[n:]
+	return value
+}
+
+// varint reads a signed varint
+func (d *columnarDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	value, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = fmt.Errorf("%w: truncated block", ErrCorruptColumnar)
+		return 0
+	}
+	d.data = d.data[n:]
+	return value
+}
+
+// bytes reads length bytes
+func (d *columnarDecoder) bytes(length uint64) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if length > uint64(len(d.data)) {
+		d.err = fmt.Errorf("%w: truncated block", ErrCorruptColumnar)
+		return nil
+	}
+	value := d.data[:length]
+	d.data = d.data[length:]
+	return value
+}
+
+// float reads the IEEE 754 bits of a float64
+func (d *columnarDecoder) float() float64 {
+	value := d.bytes(8)
+	if value == nil {
+		return 0
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(value))
+}
+
+// string reads a dictionary index and returns its string
+func (d *columnarDecoder) string() string {
+	index := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if index >= uint64(len(d.dictionary)) {
+		d.err = fmt.Errorf("%w: string %d of a dictionary of %d", ErrCorruptColumnar, index, len(d.dictionary))
+		return ""
+	}
+	return d.dictionary[index]
+}
+
+// stringMap reads a query or header map
+func (d *columnarDecoder) stringMap() map[string][]string {
+	size := d.uvarint()
+	if size == 0 || d.err != nil {
+		return nil
+	}
+	if size-1 > uint64(len(d.data)) {
+		d.err = fmt.Errorf("%w: map of %d keys", ErrCorruptColumnar, size-1)
+		return nil
+	}
+	values := make(map[string][]string, size-1)
+	for i := uint64(1); i < size && d.err == nil; i++ {
+		key := d.string()
+		count := d.uvarint()
+		if count > uint64(len(d.data)) {
+			d.err = fmt.Errorf("%w: %d values of %q", ErrCorruptColumnar, count, key)
+			return nil
+		}
+		list := make([]string, count)
+		for j := range list {
+			list[j] = d.string()
+		}
+		values[key] = list
+	}
+	return values
+}
+
+// columnarReader reads the blocks of a columnar traffic file in order
+type columnarReader struct {
+	reader  *bufio.Reader
+	decoder *zstd.Decoder
+	footer  *ColumnarFooter
+	read    int64 // Bytes read so far
+}
+
+// newColumnarReader checks the start of a columnar traffic file
+func newColumnarReader(reader io.Reader) (*columnarReader, error) {
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, err
+	}
+	r := &columnarReader{reader: bufio.NewReader(reader), decoder: decoder}
+	magic := make([]byte, len(columnarMagic))
+	if _, err := io.ReadFull(r.reader, magic); err != nil || !bytes.Equal(magic, columnarMagic) {
+		decoder.Close()
+		return nil, fmt.Errorf("%w: not a columnar traffic file", ErrCorruptColumnar)
+	}
+	r.read = int64(len(magic))
+	return r, nil
+}
+
+// next returns the records of the next block, or io.EOF once the footer is read
+func (r *columnarReader) next() ([]*NormalizedRecord, error) {
+	if r.footer != nil {
+		return nil, io.EOF
+	}
+	count, err := r.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	length, err := r.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	if count > columnarBlockRecords || length > math.MaxInt32 {
+		return nil, fmt.Errorf("%w: block of %d records in %d bytes", ErrCorruptColumnar, count, length)
+	}
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r.reader, content); err != nil {
+		return nil, fmt.Errorf("%w: truncated file", ErrCorruptColumnar)
+	}
+	r.read += int64(length)
+
+	// A zero count ends the blocks and is followed by the footer
+	if count == 0 {
+		r.footer = &ColumnarFooter{}
+		if err := json.Unmarshal(content, r.footer); err != nil {
+			return nil, fmt.Errorf("%w: invalid footer: %v", ErrCorruptColumnar, err)
+		}
+		if r.footer.Version > ColumnarVersion {
+			return nil, fmt.Errorf("columnar traffic file version %d is newer than the supported version %d", r.footer.Version, ColumnarVersion)
+		}
+		return nil, io.EOF
+	}
+	payload, err := r.decoder.DecodeAll(content, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptColumnar, err)
+	}
+	return decodeColumnarBlock(payload, int(count))
+}
+
+// uvarint reads an unsigned varint of the block framing
+func (r *columnarReader) uvarint() (uint64, error) {
+	value, err := binary.ReadUvarint(r.reader)
+	if err != nil {
+		return 0, fmt.Errorf("%w: truncated file", ErrCorruptColumnar)
+	}
+	r.read += int64(len(binary.AppendUvarint(nil, value)))
+	return value, nil
+}
+
+// close releases the decompressor
+func (r *columnarReader) close() {
+	r.decoder.Close()
+}
@@ -0,0 +1,337 @@
+package traffic
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/ingestor"
+)
+
+func init() {
+	Register(ColumnarFormat, func() TrafficIngestor { return NewColumnarIngestor() })
+}
+
+// ColumnarIngestor reads the records of columnar traffic files written by Convert. The records
+// were normalized and redacted when the files were written, so only the time filter and the
+// sample rate of the options apply.
+type ColumnarIngestor struct {
+	metrics *IngestMetrics
+	footers []*ColumnarFooter
+	mu      sync.Mutex
+}
+
+// NewColumnarIngestor creates a new columnar traffic file ingestor
+func NewColumnarIngestor() *ColumnarIngestor {
+	return &ColumnarIngestor{metrics: NewIngestMetrics()}
+}
+
+// Supports reports whether the file has the columnar extension or starts like a columnar file
+func (c *ColumnarIngestor) Supports(filePath string) bool {
+	if strings.EqualFold(filepath.Ext(filePath), ColumnarExtension) {
+		return true
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	magic := make([]byte, len(columnarMagic))
+	_, err = io.ReadFull(file, magic)
+	return err == nil && bytes.Equal(magic, columnarMagic)
+}
+
+// Ingest returns the records of the input files in order
+func (c *ColumnarIngestor) Ingest(inputs []string, options *IngestOptions) (ingestor.Iterator[*NormalizedRecord], error) {
+	if options == nil {
+		options = DefaultIngestOptions()
+	}
+	iterator := &columnarIterator{ingestor: c, inputs: inputs, options: options, sampler: newEndpointSampler(options.SampleRate), start: time.Now()}
+	if options.ProgressCallback != nil {
+		for _, input := range inputs {
+			if info, err := os.Stat(input); err == nil {
+				iterator.totalBytes += info.Size()
+			}
+		}
+	}
+
+	c.mu.Lock()
+	c.metrics = NewIngestMetrics()
+	c.footers = nil
+	c.mu.Unlock()
+	return iterator, nil
+}
+
+// Footers returns the footers of the files read completely by the last Ingest call
+func (c *ColumnarIngestor) Footers() []*ColumnarFooter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.footers
+}
+
+// Metrics returns the metrics of the records read so far; every record counts as a parsed line
+func (c *ColumnarIngestor) Metrics() *IngestMetrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.metrics
+}
+
+// Close releases any resources held by the ingestor
+func (c *ColumnarIngestor) Close() error {
+	return nil
+}
+
+// columnarIterator decodes the blocks of each input in turn as the records are consumed
+type columnarIterator struct {
+	ingestor   *ColumnarIngestor
+	inputs     []string
+	options    *IngestOptions
+	sampler    *endpointSampler
+	start      time.Time
+	totalBytes int64
+	readBytes  int64 // Bytes of the files before the current one
+	records    int64
+
+	file     *os.File
+	path     string
+	reader   *columnarReader
+	metrics  *FileMetrics
+	block    []*NormalizedRecord
+	current  *NormalizedRecord
+	err      error
+	finished bool
+}
+
+// Next advances to the next record that passes the time filter and the sampling
+func (i *columnarIterator) Next() bool {
+	for i.err == nil && !i.finished {
+		if len(i.block) == 0 {
+			i.err = i.nextBlock()
+			continue
+		}
+		record := i.block[0]
+		i.block = i.block[1:]
+		i.metrics.TotalLines++
+		if i.options.TimeFilter != nil && !withinTimeRange(i.options.TimeFilter, record.Timestamp) {
+			continue
+		}
+		if i.sampler != nil && !i.sampler.keep(record) {
+			i.metrics.SkippedLines++
+			continue
+		}
+		i.metrics.ParsedLines++
+		i.records++
+		i.current = record
+		return true
+	}
+	return false
+}
+
+// nextBlock decodes the next block of the current file, moving on to the next file at the
+// end of one
+func (i *columnarIterator) nextBlock() error {
+	if i.reader == nil {
+		if len(i.inputs) == 0 {
+			i.finish()
+			return nil
+		}
+		if err := i.open(i.inputs[0]); err != nil {
+			return fmt.Errorf("failed to process file %s: %w", i.inputs[0], err)
+		}
+		i.inputs = i.inputs[1:]
+	}
+
+	block, err := i.reader.next()
+	i.reportProgress()
+	if errors.Is(err, io.EOF) {
+		return i.closeFile()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to process file %s: %w", i.path, err)
+	}
+	i.block = block
+	return i.options.MemoryBudget.Check("reading " + filepath.Base(i.path))
+}
+
+// open starts reading a file
+func (i *columnarIterator) open(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	reader, err := newColumnarReader(file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	i.file, i.path, i.reader, i.metrics = file, path, reader, &FileMetrics{}
+	return nil
+}
+
+// closeFile finishes the current file, adding its metrics and footer
+func (i *columnarIterator) closeFile() error {
+	i.readBytes += i.reader.read
+	footer := i.reader.footer
+	i.reader.close()
+	err := i.file.Close()
+
+	i.ingestor.mu.Lock()
+	fileMetrics := i.metrics
+	i.ingestor.metrics.TotalLines += fileMetrics.TotalLines
+	i.ingestor.metrics.ParsedLines += fileMetrics.ParsedLines
+	i.ingestor.metrics.AddFile(i.path, fileMetrics)
+	if footer != nil {
+		i.ingestor.footers = append(i.ingestor.footers, footer)
+	}
+	i.ingestor.mu.Unlock()
+
+	i.file, i.reader, i.metrics = nil, nil, nil
+	return err
+}
+
+// finish records the duration once every file is read
+func (i *columnarIterator) finish() {
+	i.finished = true
+	i.ingestor.mu.Lock()
+	i.ingestor.metrics.SetDuration(time.Since(i.start))
+	i.ingestor.mu.Unlock()
+}
+
+// reportProgress passes the bytes read and records kept so far to the progress callback
+func (i *columnarIterator) reportProgress() {
+	if i.options.ProgressCallback == nil {
+		return
+	}
+	read := i.readBytes
+	if i.reader != nil {
+		read += i.reader.read
+	}
+	i.options.ProgressCallback(read, i.totalBytes, i.records)
+}
+
+// Value returns the current record
+func (i *columnarIterator) Value() *NormalizedRecord {
+	return i.current
+}
+
+// Err returns the first error reading the files
+func (i *columnarIterator) Err() error {
+	return i.err
+}
+
+// Close stops reading, closing the current file
+func (i *columnarIterator) Close() error {
+	i.inputs = nil
+	i.block = nil
+	if i.reader == nil {
+		return nil
+	}
+	i.reader.close()
+	err := i.file.Close()
+	i.file, i.reader = nil, nil
+	return err
+}
+
+// withinTimeRange reports whether a timestamp is within a time filter
+func withinTimeRange(filter *TimeRange, timestamp time.Time) bool {
+	if filter.Since != nil && timestamp.Before(*filter.Since) {
+		return false
+	}
+	if filter.Until != nil && timestamp.After(*filter.Until) {
+		return false
+	}
+	return true
+}
+
+// ConvertResult describes a columnar traffic file written by Convert
+type ConvertResult struct {
+	Path       string         `json:"path"`
+	Bytes      int64          `json:"bytes"`
+	InputBytes int64          `json:"inputBytes"`
+	Footer     ColumnarFooter `json:"footer"`
+}
+
+// FormatHuman returns a short summary of the conversion
+func (r *ConvertResult) FormatHuman() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Converted %d file(s) (%d bytes) into %s (%d bytes)\n", len(r.Footer.Sources), r.InputBytes, r.Path, r.Bytes)
+	fmt.Fprintf(&builder, "  records: %d\n", r.Footer.Records)
+	if metrics := r.Footer.Metrics; metrics != nil && metrics.ErrorLines > 0 {
+		fmt.Fprintf(&builder, "  unparseable lines: %d of %d\n", metrics.ErrorLines, metrics.TotalLines)
+	}
+	return builder.String()
+}
+
+// Convert ingests the input logs with the ingestor of options.Format and writes their records
+// to a columnar traffic file at outPath, which later runs read with the columnar format
+// instead of parsing the logs again. The file is replaced only once it is complete.
+func Convert(inputs []string, outPath string, options *IngestOptions) (*ConvertResult, error) {
+	if options == nil {
+		options = DefaultIngestOptions()
+	}
+	footer := ColumnarFooter{CreatedAt: time.Now().UTC(), Format: options.Format}
+	if options.NginxLogFormat == "" && options.CustomRegex == "" {
+		footer.LogFormat = options.LogFormat
+	}
+	result := &ConvertResult{Path: outPath}
+	for _, input := range inputs {
+		info, err := os.Stat(input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", input, err)
+		}
+		footer.Sources = append(footer.Sources, ColumnarSource{Path: input, Size: info.Size(), ModTime: info.ModTime().UTC()})
+		result.InputBytes += info.Size()
+	}
+
+	trafficIngestor, err := NewIngestor(options.Format)
+	if err != nil {
+		return nil, err
+	}
+	defer trafficIngestor.Close()
+	iterator, err := trafficIngestor.Ingest(inputs, options)
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
+	if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	temp, err := os.CreateTemp(filepath.Dir(outPath), ".traffic-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", outPath, err)
+	}
+	defer os.Remove(temp.Name())
+	defer temp.Close()
+
+	writer, err := newColumnarWriter(temp)
+	for err == nil && iterator.Next() {
+		err = writer.Write(iterator.Value())
+	}
+	if err == nil {
+		err = iterator.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+	footer.Metrics = trafficIngestor.Metrics()
+	if err := writer.Close(footer); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", outPath, err)
+	}
+	if err := temp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", outPath, err)
+	}
+	if err := os.Rename(temp.Name(), outPath); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", outPath, err)
+	}
+
+	footer.Version, footer.Records = ColumnarVersion, writer.written
+	result.Bytes, result.Footer = writer.Bytes(), footer
+	return result, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traffic
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// combinedTrafficLines returns count combined log lines with queries, headers and durations,
+// one second apart
+func combinedTrafficLines(count int) []string {
+	lines := make([]string, count)
+	start := time.Date(2025, time.August, 10, 12, 0, 0, 0, time.UTC)
+	for i := range lines {
+		timestamp := start.Add(time.Duration(i) * time.Second).Format(timeLocalLayout)
+		lines[i] = fmt.Sprintf(`10.0.0.%d - - [%s] "GET /api/users/%d?page=%d&tag=a&tag=b HTTP/1.1" %d %d "https://example.com/%d" "curl/8.%d" 0.%03d "0.%03d, 0.001"`,
+			i%7, timestamp, i, i%3, 200+i%2, i*10, i%5, i%4, i%1000, i%500)
+	}
+	return lines
+}
+
+func TestConvert(t *testing.T) {
+	dir := t.TempDir()
+	lines := append(combinedTrafficLines(columnarBlockRecords+100), "invalid log line")
+	input := writeLogFile(t, dir, "access.log", lines)
+	output := filepath.Join(dir, "cache", "access"+ColumnarExtension)
+
+	result, err := Convert([]string{input}, output, DefaultIngestOptions())
+	require.NoError(t, err)
+	info, err := os.Stat(output)
+	require.NoError(t, err)
+	assert.Equal(t, info.Size(), result.Bytes)
+	assert.Equal(t, int64(columnarBlockRecords+100), result.Footer.Records)
+	require.Len(t, result.Footer.Sources, 1)
+	assert.Equal(t, input, result.Footer.Sources[0].Path)
+	assert.Equal(t, "combined", result.Footer.LogFormat)
+	assert.Equal(t, int64(1), result.Footer.Metrics.ErrorLines)
+	assert.Contains(t, result.FormatHuman(), fmt.Sprintf("records: %d", columnarBlockRecords+100))
+	assert.Less(t, result.Bytes, result.InputBytes)
+
+	// The columnar file yields the records of the logs it was converted from
+	expected, err := drain(t, NewNginxAccessIngestor(), []string{input}, DefaultIngestOptions())
+	require.NoError(t, err)
+	format, ok := DetectFormat(output)
+	require.True(t, ok)
+	assert.Equal(t, ColumnarFormat, format)
+	columnarIngestor, err := NewIngestor(format)
+	require.NoError(t, err)
+	records, err := drain(t, columnarIngestor, []string{output}, DefaultIngestOptions())
+	require.NoError(t, err)
+	assert.Equal(t, expected, records)
+
+	metrics := columnarIngestor.Metrics()
+	assert.Equal(t, int64(len(records)), metrics.TotalLines)
+	assert.Equal(t, int64(len(records)), metrics.Files[output].ParsedLines)
+	footers := columnarIngestor.(*ColumnarIngestor).Footers()
+	require.Len(t, footers, 1)
+	assert.Equal(t, result.Footer.Records, footers[0].Records)
+	assert.Equal(t, ColumnarVersion, footers[0].Version)
+
+	// Columnar files convert again, e.g. to merge several of them
+	merged, err := Convert([]string{output, output}, filepath.Join(dir, "merged"+ColumnarExtension), &IngestOptions{Format: FormatAuto})
+	require.NoError(t, err)
+	assert.Equal(t, 2*result.Footer.Records, merged.Footer.Records)
+}
+
+func TestColumnarIngestor_Options(t *testing.T) {
+	dir := t.TempDir()
+	input := writeLogFile(t, dir, "access.log", combinedTrafficLines(100))
+	output := filepath.Join(dir, "access"+ColumnarExtension)
+	_, err := Convert([]string{input}, output, DefaultIngestOptions())
+	require.NoError(t, err)
+
+	since := time.Date(2025, time.August, 10, 12, 0, 50, 0, time.UTC)
+	var processedBytes, totalBytes int64
+	options := DefaultIngestOptions()
+	options.TimeFilter = &TimeRange{Since: &since}
+	options.SampleRate = 0.5
+	options.ProgressCallback = func(processed, total, parsed int64) {
+		processedBytes, totalBytes = processed, total
+	}
+	ingestor := NewColumnarIngestor()
+	records, err := drain(t, ingestor, []string{output}, options)
+	require.NoError(t, err)
+
+	// IDs make all paths one endpoint, which keeps every other record from the time filter on
+	assert.Len(t, records, 25)
+	for _, record := range records {
+		assert.False(t, record.Timestamp.Before(since))
+	}
+	assert.Equal(t, int64(100), ingestor.Metrics().TotalLines)
+	assert.Equal(t, int64(25), ingestor.Metrics().Files[output].SkippedLines)
+	info, err := os.Stat(output)
+	require.NoError(t, err)
+	assert.Equal(t, info.Size(), totalBytes)
+	assert.Equal(t, totalBytes, processedBytes)
+}
+
+func TestColumnarIngestor_Corrupt(t *testing.T) {
+	dir := t.TempDir()
+	input := writeLogFile(t, dir, "access.log", combinedTrafficLines(10))
+	output := filepath.Join(dir, "access"+ColumnarExtension)
+	_, err := Convert([]string{input}, output, DefaultIngestOptions())
+	require.NoError(t, err)
+	content, err := os.ReadFile(output)
+	require.NoError(t, err)
+
+	for name, corrupt := range map[string][]byte{
+		"truncated":      content[:len(content)/2],
+		"not columnar":   []byte("10.0.0.1 - - [10/Aug/2025:12:00:00 +0000]\n"),
+		"missing footer": content[:len(content)-5],
+	} {
+		path := filepath.Join(dir, "corrupt"+ColumnarExtension)
+		require.NoError(t, os.WriteFile(path, corrupt, 0644))
+		_, err := drain(t, NewColumnarIngestor(), []string{path}, DefaultIngestOptions())
+		assert.ErrorIs(t, err, ErrCorruptColumnar, name)
+	}
+}
@@ -0,0 +1,132 @@
+package traffic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+const (
+	// EncodingUTF8 reads lines as UTF-8, replacing invalid bytes with U+FFFD
+	EncodingUTF8 = "utf-8"
+	// EncodingAuto keeps lines that are valid UTF-8 and reads the others as latin-1
+	// (windows-1252), the usual mix when some clients send legacy-encoded URLs
+	EncodingAuto = "auto"
+)
+
+// lineDecoder converts log lines and the values parsed from them to valid UTF-8
+type lineDecoder struct {
+	name    string
+	decoder *encoding.Decoder // nil for UTF-8
+}
+
+// ValidateEncoding checks that an encoding is empty, "auto" or a known character encoding
+// such as utf-8, latin1 or shift_jis
+func ValidateEncoding(name string) error {
+	_, err := newLineDecoder(name)
+	return err
+}
+
+// newLineDecoder creates the decoder of an encoding name, UTF-8 when empty
+func newLineDecoder(name string) (*lineDecoder, error) {
+	switch strings.ToLower(name) {
+	case "", EncodingUTF8, "utf8":
+		return &lineDecoder{name: EncodingUTF8}, nil
+	case EncodingAuto:
+		return &lineDecoder{name: EncodingAuto, decoder: charmap.Windows1252.NewDecoder()}, nil
+	}
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported encoding %q, must be %s, %s or a character encoding such as latin1 or shift_jis", name, EncodingUTF8, EncodingAuto)
+	}
+	canonical, _ := htmlindex.Name(enc)
+	if canonical == EncodingUTF8 {
+		return &lineDecoder{name: EncodingUTF8}, nil
+	}
+	return &lineDecoder{name: canonical, decoder: enc.NewDecoder()}, nil
+}
+
+// decodeLine returns a line as valid UTF-8 and whether it had to be converted. nginx writes
+// non-ASCII bytes of the request line and headers as \xHH escapes, which are decoded first;
+// the escapes of quotes, backslashes and control characters are kept so the line still parses.
+func (d *lineDecoder) decodeLine(line string) (string, bool) {
+	unescaped := unescapeNonASCII(line)
+	if isASCII(unescaped) || (d.decoder == nil || d.name == EncodingAuto) && utf8.ValidString(unescaped) {
+		return unescaped, unescaped != line
+	}
+	return d.decode(unescaped), true
+}
+
+// isASCII reports whether a string has only 7-bit bytes, which every supported encoding
+// reads the same
+func isASCII(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// decode converts a string from the encoding; bytes that cannot be converted become U+FFFD
+func (d *lineDecoder) decode(value string) string {
+	if d.decoder == nil {
+		return strings.ToValidUTF8(value, "\uFFFD")
+	}
+	decoded, err := d.decoder.String(value)
+	if err != nil {
+		return strings.ToValidUTF8(value, "\uFFFD")
+	}
+	return decoded
+}
+
+// sanitizeRecord converts the path, query and headers of a record that percent-decoding left
+// invalid, e.g. "%E9" sent by a latin-1 client, so they do not produce garbage keys
+func (d *lineDecoder) sanitizeRecord(record *NormalizedRecord) {
+	if !utf8.ValidString(record.Path) {
+		record.Path = d.decode(record.Path)
+	}
+	record.Query = d.sanitizeValues(record.Query)
+	record.Headers = d.sanitizeValues(record.Headers)
+}
+
+// sanitizeValues converts the invalid keys and values of a multi-value map
+func (d *lineDecoder) sanitizeValues(values map[string][]string) map[string][]string {
+	for key, list := range values {
+		for i, value := range list {
+			if !utf8.ValidString(value) {
+				list[i] = d.decode(value)
+			}
+		}
+		if !utf8.ValidString(key) {
+			delete(values, key)
+			values[d.decode(key)] = append(values[d.decode(key)], list...)
+		}
+	}
+	return values
+}
+
+// unescapeNonASCII replaces the \xHH escapes of bytes 0x80-0xFF with the bytes themselves
+func unescapeNonASCII(line string) string {
+	if !strings.Contains(line, `\x`) {
+		return line
+	}
+	var builder strings.Builder
+	builder.Grow(len(line))
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' && i+3 < len(line) && line[i+1] == 'x' {
+			if value, err := strconv.ParseUint(line[i+2:i+4], 16, 8); err == nil && value >= 0x80 {
+				builder.WriteByte(byte(value))
+				i += 3
+				continue
+			}
+		}
+		builder.WriteByte(line[i])
+	}
+	return builder.String()
+}
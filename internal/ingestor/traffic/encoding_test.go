@@ -0,0 +1,103 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traffic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnescapeNonASCII(t *testing.T) {
+	assert.Equal(t, `GET /café?q=\x22a\x22 \xZZ \x4`, unescapeNonASCII(`GET /caf\xC3\xA9?q=\x22a\x22 \xZZ \x4`))
+	assert.Equal(t, "plain", unescapeNonASCII("plain"))
+}
+
+func TestLineDecoder_DecodeLine(t *testing.T) {
+	testCases := []struct {
+		name              string
+		encoding          string
+		line              string
+		expected          string
+		expectedConverted bool
+	}{
+		{name: "ascii", encoding: "", line: "GET /users", expected: "GET /users"},
+		{name: "valid utf-8", encoding: "utf-8", line: "GET /café", expected: "GET /café"},
+		{name: "broken utf-8 is replaced", encoding: "utf-8", line: "GET /caf\xe9", expected: "GET /caf�", expectedConverted: true},
+		{name: "truncated multibyte sequence", encoding: "utf-8", line: "GET /caf\xc3", expected: "GET /caf�", expectedConverted: true},
+		{name: "escaped utf-8", encoding: "utf-8", line: `GET /caf\xC3\xA9`, expected: "GET /café", expectedConverted: true},
+		{name: "auto keeps utf-8", encoding: "auto", line: "GET /café", expected: "GET /café"},
+		{name: "auto reads latin-1", encoding: "auto", line: "GET /caf\xe9", expected: "GET /café", expectedConverted: true},
+		{name: "auto reads escaped latin-1", encoding: "auto", line: `GET /caf\xE9`, expected: "GET /café", expectedConverted: true},
+		{name: "latin-1", encoding: "ISO-8859-1", line: "GET /\xfcber", expected: "GET /über", expectedConverted: true},
+		{name: "shift_jis", encoding: "shift_jis", line: "GET /\x93\xfa\x96\x7b", expected: "GET /日本", expectedConverted: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decoder, err := newLineDecoder(tc.encoding)
+			require.NoError(t, err)
+			decoded, converted := decoder.decodeLine(tc.line)
+			assert.Equal(t, tc.expected, decoded)
+			assert.Equal(t, tc.expectedConverted, converted)
+		})
+	}
+}
+
+func TestValidateEncoding(t *testing.T) {
+	for _, name := range []string{"", "utf-8", "UTF8", "auto", "latin1", "windows-1252", "gbk"} {
+		assert.NoError(t, ValidateEncoding(name), name)
+	}
+	assert.ErrorContains(t, ValidateEncoding("klingon"), `unsupported encoding "klingon"`)
+}
+
+func TestNginxAccessIngestor_Encoding(t *testing.T) {
+	path := writeLogFile(t, t.TempDir(), "access.log", []string{
+		"10.0.0.1 - - [10/Aug/2025:12:00:00 +0000] \"GET /caf\xe9?caf%E9=%E9t%E9 HTTP/1.1\" 200 512",
+		`10.0.0.1 - - [10/Aug/2025:12:00:01 +0000] "GET /users?page=1 HTTP/1.1" 200 512`,
+	})
+
+	testCases := []struct {
+		encoding      string
+		expectedPath  string
+		expectedQuery map[string][]string
+	}{
+		{encoding: "utf-8", expectedPath: "/caf�", expectedQuery: map[string][]string{"caf�": {"�t�"}}},
+		{encoding: "auto", expectedPath: "/café", expectedQuery: map[string][]string{"café": {"été"}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.encoding, func(t *testing.T) {
+			options := DefaultIngestOptions()
+			options.LogFormat = "common"
+			options.Encoding = tc.encoding
+			trafficIngestor := NewNginxAccessIngestor()
+
+			records, err := drain(t, trafficIngestor, []string{path}, options)
+			require.NoError(t, err)
+			require.Len(t, records, 2)
+			assert.Equal(t, tc.expectedPath, records[0].Path)
+			assert.Equal(t, tc.expectedQuery, records[0].Query)
+			assert.Equal(t, "/users", records[1].Path)
+			assert.Equal(t, int64(1), trafficIngestor.Metrics().ConvertedLines)
+		})
+	}
+
+	options := DefaultIngestOptions()
+	options.Encoding = "klingon"
+	_, err := NewNginxAccessIngestor().Ingest([]string{path}, options)
+	assert.ErrorContains(t, err, "unsupported encoding")
+}
@@ -0,0 +1,122 @@
+package traffic
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// ErrErrorBudgetExceeded is matched by errors.Is when a file has more unparseable lines than
+// IngestOptions.MaxErrorRate allows
+var ErrErrorBudgetExceeded = errors.New("error budget exceeded")
+
+const (
+	// errorBudgetMinLines is the number of lines of a file parsed before its error rate is
+	// enforced, so a few bad lines at its start do not abort the run; a file read to the end is
+	// always enforced
+	errorBudgetMinLines = 100
+	// maxSuggestionLines is the number of failed lines of a file kept to suggest a log format
+	maxSuggestionLines = 20
+)
+
+// FileMetrics counts the lines of a single input file
+type FileMetrics struct {
+	TotalLines   int64 `json:"totalLines"`
+	ParsedLines  int64 `json:"parsedLines"`
+	ErrorLines   int64 `json:"errorLines"`
+	SkippedLines int64 `json:"skippedLines,omitempty"` // Left out by sampling
+	LongLines    int64 `json:"longLines,omitempty"`    // Over the maximum line length
+}
+
+// ErrorRate returns the share of the sampled lines of the file that failed to parse
+func (f *FileMetrics) ErrorRate() float64 {
+	sampled := f.TotalLines - f.SkippedLines
+	if sampled <= 0 {
+		return 0.0
+	}
+	return float64(f.ErrorLines) / float64(sampled)
+}
+
+// ErrorBudgetError reports a file whose lines fail to parse more often than the error budget
+// allows, which usually means the log format does not match the logs
+type ErrorBudgetError struct {
+	File         string
+	ErrorLines   int64
+	SampledLines int64
+	MaxErrorRate float64
+	LogFormat    string // Format the lines were parsed with: a predefined format, "custom" or "template"
+	Suggestion   string // Predefined format that parses most of the failed lines, if any
+}
+
+func (e *ErrorBudgetError) Error() string {
+	message := fmt.Sprintf("%d of %d lines of %s failed to parse as %s (%.1f%%), above the maximum error rate of %.1f%%",
+		e.ErrorLines, e.SampledLines, e.File, e.LogFormat,
+		100*float64(e.ErrorLines)/float64(e.SampledLines), 100*e.MaxErrorRate)
+	if e.Suggestion != "" {
+		return message + fmt.Sprintf("; the lines look like --log-format %s", e.Suggestion)
+	}
+	return message + "; check that --log-format, --regex or --nginx-log-format matches the logs, or raise --max-error-rate"
+}
+
+// Is makes errors.Is(err, ErrErrorBudgetExceeded) hold
+func (e *ErrorBudgetError) Is(target error) bool {
+	return target == ErrErrorBudgetExceeded
+}
+
+// checkErrorBudget returns an ErrorBudgetError when the error rate of a file exceeds
+// MaxErrorRate; before the file is complete only once enough of its lines were sampled
+func (n *NginxAccessIngestor) checkErrorBudget(filePath string, file *FileMetrics, failedLines []string, complete bool) error {
+	if n.options.MaxErrorRate <= 0 || file.ErrorLines == 0 {
+		return nil
+	}
+	sampled := file.TotalLines - file.SkippedLines
+	if !complete && sampled < errorBudgetMinLines {
+		return nil
+	}
+	if file.ErrorRate() <= n.options.MaxErrorRate {
+		return nil
+	}
+	return &ErrorBudgetError{
+		File:         filePath,
+		ErrorLines:   file.ErrorLines,
+		SampledLines: sampled,
+		MaxErrorRate: n.options.MaxErrorRate,
+		LogFormat:    n.logFormat,
+		Suggestion:   suggestLogFormat(failedLines, n.logFormat),
+	}
+}
+
+// suggestLogFormat returns the predefined format other than current that parses most of the
+// lines, provided it parses at least half of them
+func suggestLogFormat(lines []string, current string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(nginxLogFormats))
+	for name := range nginxLogFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	best, bestMatches := "", 0
+	for _, name := range names {
+		if name == current {
+			continue
+		}
+		regex := regexp.MustCompile(nginxLogFormats[name].regex)
+		matches := 0
+		for _, line := range lines {
+			if regex.MatchString(line) {
+				matches++
+			}
+		}
+		if matches > bestMatches {
+			best, bestMatches = name, matches
+		}
+	}
+	if 2*bestMatches < len(lines) {
+		return ""
+	}
+	return best
+}
@@ -0,0 +1,139 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traffic
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLogFile writes lines to a log file in dir
+func writeLogFile(t *testing.T, dir, name string, lines []string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644))
+	return path
+}
+
+// commonLines returns count lines in the common log format
+func commonLines(count int) []string {
+	lines := make([]string, count)
+	for i := range lines {
+		lines[i] = fmt.Sprintf(`10.0.0.1 - - [10/Aug/2025:12:00:00 +0000] "GET /api/users/%d HTTP/1.1" 200 512`, i)
+	}
+	return lines
+}
+
+// drain reads all records of an ingestion and returns them with the iterator error
+func drain(t *testing.T, trafficIngestor TrafficIngestor, inputs []string, options *IngestOptions) ([]*NormalizedRecord, error) {
+	iterator, err := trafficIngestor.Ingest(inputs, options)
+	require.NoError(t, err)
+	defer iterator.Close()
+
+	var records []*NormalizedRecord
+	for iterator.Next() {
+		records = append(records, iterator.Value())
+	}
+	return records, iterator.Err()
+}
+
+func TestNginxAccessIngestor_ErrorBudget(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("wrong log format aborts early with a suggestion", func(t *testing.T) {
+		path := writeLogFile(t, dir, "common.log", commonLines(500))
+		options := DefaultIngestOptions()
+		options.MaxErrorRate = 0.05
+		trafficIngestor := NewNginxAccessIngestor()
+
+		records, err := drain(t, trafficIngestor, []string{path}, options)
+		assert.Empty(t, records)
+		require.ErrorIs(t, err, ErrErrorBudgetExceeded)
+		var budgetErr *ErrorBudgetError
+		require.True(t, errors.As(err, &budgetErr))
+		assert.Equal(t, "combined", budgetErr.LogFormat)
+		assert.Equal(t, "common", budgetErr.Suggestion)
+		assert.Contains(t, err.Error(), "100 of 100 lines of "+path+" failed to parse as combined (100.0%), above the maximum error rate of 5.0%; the lines look like --log-format common")
+		assert.Equal(t, int64(errorBudgetMinLines), trafficIngestor.Metrics().Files[path].ErrorLines)
+	})
+
+	t.Run("small file is checked once read", func(t *testing.T) {
+		path := writeLogFile(t, dir, "garbage.log", append(commonLines(3), "garbage"))
+		options := DefaultIngestOptions()
+		options.LogFormat = "common"
+		options.MaxErrorRate = 0.1
+
+		_, err := drain(t, NewNginxAccessIngestor(), []string{path}, options)
+		require.ErrorIs(t, err, ErrErrorBudgetExceeded)
+		assert.Contains(t, err.Error(), "1 of 4 lines")
+		assert.Contains(t, err.Error(), "check that --log-format, --regex or --nginx-log-format matches the logs")
+	})
+
+	t.Run("errors within the budget", func(t *testing.T) {
+		path := writeLogFile(t, dir, "mostly-common.log", append(commonLines(49), "garbage"))
+		options := DefaultIngestOptions()
+		options.LogFormat = "common"
+		options.MaxErrorRate = 0.05
+
+		records, err := drain(t, NewNginxAccessIngestor(), []string{path}, options)
+		require.NoError(t, err)
+		assert.Len(t, records, 49)
+	})
+
+	t.Run("disabled budget", func(t *testing.T) {
+		path := writeLogFile(t, dir, "all-common.log", commonLines(200))
+		records, err := drain(t, NewNginxAccessIngestor(), []string{path}, DefaultIngestOptions())
+		require.NoError(t, err)
+		assert.Empty(t, records)
+	})
+}
+
+func TestNginxAccessIngestor_FileMetrics(t *testing.T) {
+	dir := t.TempDir()
+	clean := writeLogFile(t, dir, "clean.log", commonLines(10))
+	broken := writeLogFile(t, dir, "broken.log", append(commonLines(8), "garbage", "more garbage"))
+
+	for _, parallelism := range []int{1, 2} {
+		t.Run(fmt.Sprintf("parallelism %d", parallelism), func(t *testing.T) {
+			options := DefaultIngestOptions()
+			options.LogFormat = "common"
+			options.Parallelism = parallelism
+			trafficIngestor := NewNginxAccessIngestor()
+
+			_, err := drain(t, trafficIngestor, []string{clean, broken}, options)
+			require.NoError(t, err)
+
+			metrics := trafficIngestor.Metrics()
+			assert.Equal(t, int64(2), metrics.ErrorLines)
+			assert.Equal(t, map[string]*FileMetrics{
+				clean:  {TotalLines: 10, ParsedLines: 10},
+				broken: {TotalLines: 10, ParsedLines: 8, ErrorLines: 2},
+			}, metrics.Files)
+			assert.InDelta(t, 0.2, metrics.Files[broken].ErrorRate(), 1e-9)
+		})
+	}
+}
+
+func TestFileMetrics_ErrorRateExcludesSkippedLines(t *testing.T) {
+	file := &FileMetrics{TotalLines: 100, SkippedLines: 90, ErrorLines: 5}
+	assert.InDelta(t, 0.5, file.ErrorRate(), 1e-9)
+	assert.Equal(t, 0.0, (&FileMetrics{}).ErrorRate())
+}
@@ -3,30 +3,37 @@ package traffic
 import (
 	"time"
 
+	"github.com/flowspec/flowspec-cli/internal/concurrency"
 	"github.com/flowspec/flowspec-cli/internal/ingestor"
+	"github.com/flowspec/flowspec-cli/internal/membudget"
 )
 
 // NormalizedRecord represents a normalized traffic record
 type NormalizedRecord struct {
-	Method    string                 `json:"method"`
-	Path      string                 `json:"path"`         // Normalized path
-	RawPath   string                 `json:"rawPath"`      // Original path
-	Status    int                    `json:"status"`
-	Timestamp time.Time              `json:"timestamp"`    // RFC3339 format
-	Query     map[string][]string    `json:"query"`        // Keys preserved as-is, supports multi-value
-	Headers   map[string][]string    `json:"headers"`      // Keys normalized to lowercase, supports multi-value
-	Host      string                 `json:"host"`
-	Scheme    string                 `json:"scheme"`
-	BodyBytes int64                  `json:"bodyBytes,omitempty"` // Optional
+	Method             string              `json:"method"`
+	Path               string              `json:"path"`    // Normalized path
+	RawPath            string              `json:"rawPath"` // Original path
+	Status             int                 `json:"status"`
+	Timestamp          time.Time           `json:"timestamp"` // RFC3339 format
+	Query              map[string][]string `json:"query"`     // Keys preserved as-is, supports multi-value
+	Headers            map[string][]string `json:"headers"`   // Keys normalized to lowercase, supports multi-value
+	Host               string              `json:"host"`
+	Scheme             string              `json:"scheme"`
+	BodyBytes          int64               `json:"bodyBytes,omitempty"`          // Optional
+	DurationMs         float64             `json:"durationMs,omitempty"`         // $request_time, when logged
+	UpstreamDurationMs float64             `json:"upstreamDurationMs,omitempty"` // $upstream_response_time summed over upstreams, when logged
 }
 
 // IngestMetrics tracks ingestion statistics and error samples
 type IngestMetrics struct {
-	TotalLines   int64         `json:"totalLines"`
-	ParsedLines  int64         `json:"parsedLines"`
-	ErrorLines   int64         `json:"errorLines"`
-	Duration     time.Duration `json:"duration"`
-	ErrorSamples []string      `json:"errorSamples"` // Limited collection, max 10 by default
+	TotalLines     int64                   `json:"totalLines"`
+	ParsedLines    int64                   `json:"parsedLines"`
+	ErrorLines     int64                   `json:"errorLines"`
+	Duration       time.Duration           `json:"duration"`
+	ErrorSamples   []string                `json:"errorSamples"`             // Limited collection, max 10 by default
+	LongLines      int64                   `json:"longLines,omitempty"`      // Lines over MaxLineBytes, skipped or truncated
+	ConvertedLines int64                   `json:"convertedLines,omitempty"` // Lines converted to UTF-8 from escapes or another encoding
+	Files          map[string]*FileMetrics `json:"files,omitempty"`          // Per input file
 }
 
 // TimeRange defines a time filter for ingestion
@@ -35,15 +42,28 @@ type TimeRange struct {
 	Until *time.Time `json:"until,omitempty"`
 }
 
+// ProgressFunc receives the input bytes read so far, the total input size and the records parsed
+type ProgressFunc func(processedBytes, totalBytes, records int64)
+
 // IngestOptions configures the ingestion process
 type IngestOptions struct {
-	LogFormat         string     `json:"logFormat"`         // e.g., "combined", "common"
-	CustomRegex       string     `json:"customRegex"`       // Custom regex pattern
-	SampleRate        float64    `json:"sampleRate"`        // 0.0-1.0, default 1.0
-	TimeFilter        *TimeRange `json:"timeFilter"`        // Optional time range filter
-	SensitiveKeys     []string   `json:"sensitiveKeys"`     // Keys to redact
-	RedactionPolicy   string     `json:"redactionPolicy"`   // "drop"|"mask"|"hash"
-	MaxErrorSamples   int        `json:"maxErrorSamples"`   // Max error samples to collect, default 10
+	Format           string            `json:"format"`          // Registered ingestor such as "nginx", or "auto" to detect per file
+	LogFormat        string            `json:"logFormat"`       // e.g., "combined", "common"
+	CustomRegex      string            `json:"customRegex"`     // Custom regex pattern
+	NginxLogFormat   string            `json:"nginxLogFormat"`  // nginx log_format template, e.g. `$remote_addr [$time_local] "$request" $status`
+	SampleRate       float64           `json:"sampleRate"`      // 0.0-1.0, default 1.0
+	TimeFilter       *TimeRange        `json:"timeFilter"`      // Optional time range filter
+	SensitiveKeys    []string          `json:"sensitiveKeys"`   // Keys to redact
+	RedactionPolicy  string            `json:"redactionPolicy"` // "drop"|"mask"|"hash"
+	MaxErrorSamples  int               `json:"maxErrorSamples"` // Max error samples to collect, default 10
+	MaxErrorRate     float64           `json:"maxErrorRate"`    // 0.0-1.0, share of unparseable lines of a file that aborts ingestion; 0 disables
+	MaxLineBytes     int               `json:"maxLineBytes"`    // Longest line read in full, default 1 MiB
+	LongLines        string            `json:"longLines"`       // "skip"|"truncate" lines over MaxLineBytes, default "skip"
+	Encoding         string            `json:"encoding"`        // "utf-8"|"auto"|a charset such as "latin1", default "utf-8"
+	Parallelism      int               `json:"parallelism"`     // Files processed concurrently, default GOMAXPROCS
+	Mmap             string            `json:"mmap"`            // "auto"|"off" memory-mapped reads of large uncompressed files, default "auto"
+	ProgressCallback ProgressFunc      `json:"-"`               // Called periodically while reading the inputs
+	MemoryBudget     *membudget.Budget `json:"-"`               // Checked periodically while reading; nil for no limit
 }
 
 // TrafficIngestor defines the interface for traffic log ingestion
@@ -64,11 +84,17 @@ type TrafficIngestor interface {
 // DefaultIngestOptions returns default ingestion options
 func DefaultIngestOptions() *IngestOptions {
 	return &IngestOptions{
+		Format:          DefaultFormat,
 		LogFormat:       "combined",
 		SampleRate:      1.0,
 		SensitiveKeys:   []string{"authorization", "cookie", "set-cookie", "token", "password", "api_key"},
 		RedactionPolicy: "drop",
 		MaxErrorSamples: 10,
+		MaxLineBytes:    DefaultMaxLineBytes,
+		LongLines:       LongLinesSkip,
+		Encoding:        EncodingUTF8,
+		Parallelism:     concurrency.Workers(concurrency.Auto),
+		Mmap:            MmapAuto,
 	}
 }
 
@@ -89,11 +115,56 @@ func (m *IngestMetrics) AddError(errorLine string, maxSamples int) {
 	}
 }
 
+// Merge adds the counts and error samples of other, keeping at most maxSamples samples
+func (m *IngestMetrics) Merge(other *IngestMetrics, maxSamples int) {
+	m.TotalLines += other.TotalLines
+	m.ParsedLines += other.ParsedLines
+	m.ErrorLines += other.ErrorLines
+	m.LongLines += other.LongLines
+	m.ConvertedLines += other.ConvertedLines
+	for _, sample := range other.ErrorSamples {
+		if len(m.ErrorSamples) >= maxSamples {
+			break
+		}
+		m.ErrorSamples = append(m.ErrorSamples, sample)
+	}
+	for filePath, file := range other.Files {
+		m.AddFile(filePath, file)
+	}
+}
+
+// AddFile adds the line counts of an input file
+func (m *IngestMetrics) AddFile(filePath string, file *FileMetrics) {
+	if m.Files == nil {
+		m.Files = make(map[string]*FileMetrics)
+	}
+	existing, ok := m.Files[filePath]
+	if !ok {
+		existing = &FileMetrics{}
+		m.Files[filePath] = existing
+	}
+	existing.TotalLines += file.TotalLines
+	existing.ParsedLines += file.ParsedLines
+	existing.ErrorLines += file.ErrorLines
+	existing.SkippedLines += file.SkippedLines
+	existing.LongLines += file.LongLines
+}
+
 // AddParsed increments the parsed lines counter
 func (m *IngestMetrics) AddParsed() {
 	m.ParsedLines++
 }
 
+// AddLongLine increments the counter of lines over the maximum line length
+func (m *IngestMetrics) AddLongLine() {
+	m.LongLines++
+}
+
+// AddConvertedLine increments the counter of lines converted to UTF-8
+func (m *IngestMetrics) AddConvertedLine() {
+	m.ConvertedLines++
+}
+
 // AddTotal increments the total lines counter
 func (m *IngestMetrics) AddTotal() {
 	m.TotalLines++
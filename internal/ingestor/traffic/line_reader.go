@@ -0,0 +1,163 @@
+package traffic
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"runtime/debug"
+)
+
+// Policies for lines longer than IngestOptions.MaxLineBytes
+const (
+	// LongLinesSkip counts an oversized line as an error without parsing it
+	LongLinesSkip = "skip"
+	// LongLinesTruncate parses the first MaxLineBytes bytes of an oversized line
+	LongLinesTruncate = "truncate"
+)
+
+const (
+	// DefaultMaxLineBytes is the longest line read in full unless configured otherwise
+	DefaultMaxLineBytes = 1024 * 1024
+	// longLineSampleBytes is the prefix of an oversized line kept as an error sample
+	longLineSampleBytes = 256
+)
+
+// ValidateLongLines checks that a long-line policy is empty, "skip" or "truncate"
+func ValidateLongLines(policy string) error {
+	switch policy {
+	case "", LongLinesSkip, LongLinesTruncate:
+		return nil
+	}
+	return fmt.Errorf("unsupported long-line policy %q, must be %s or %s", policy, LongLinesSkip, LongLinesTruncate)
+}
+
+// lineReader reads lines of any length like bufio.Scanner, but keeps at most maxBytes of each
+// line and discards the rest instead of failing with "token too long"
+type lineReader struct {
+	reader    *bufio.Reader
+	maxBytes  int
+	line      []byte
+	length    int // Length of the current line before truncation
+	truncated bool
+	err       error
+
+	// Set instead of reader when scanning a memory-mapped file
+	data    []byte
+	offset  int
+	counter *progressCounter // Counts the bytes scanned, as countingReader does for reader
+}
+
+// newLineReader creates a line reader keeping at most maxBytes of each line
+func newLineReader(reader io.Reader, maxBytes int) *lineReader {
+	return &lineReader{reader: bufio.NewReaderSize(reader, 64*1024), maxBytes: maxBytes}
+}
+
+// newMappedLineReader creates a line reader over the contents of a memory-mapped file,
+// adding the bytes scanned to counter unless it is nil
+func newMappedLineReader(data []byte, maxBytes int, counter *progressCounter) *lineReader {
+	return &lineReader{data: data, maxBytes: maxBytes, counter: counter}
+}
+
+// Scan advances to the next line, dropping its "\n" or "\r\n" line ending
+func (l *lineReader) Scan() bool {
+	if l.reader == nil {
+		return l.scanMapped()
+	}
+	l.line = l.line[:0]
+	l.length = 0
+	l.truncated = false
+	var last byte // Last byte before the line ending, to drop a '\r'
+	for {
+		chunk, err := l.reader.ReadSlice('\n')
+		if err == io.EOF && len(chunk) == 0 && l.length == 0 {
+			return false
+		}
+		if err == nil {
+			chunk = chunk[:len(chunk)-1]
+		}
+		if len(chunk) > 0 {
+			last = chunk[len(chunk)-1]
+		}
+		l.length += len(chunk)
+		// One byte past the limit is kept so a trailing '\r' does not count as truncation
+		if room := l.maxBytes + 1 - len(l.line); room > 0 {
+			l.line = append(l.line, chunk[:min(room, len(chunk))]...)
+		}
+
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil && err != io.EOF {
+			l.err = err
+			return false
+		}
+		break
+	}
+
+	if last == '\r' {
+		l.length--
+	}
+	length := l.length
+	if length > l.maxBytes {
+		l.truncated = true
+		length = l.maxBytes
+	}
+	l.line = l.line[:length]
+	return true
+}
+
+// scanMapped advances to the next line of the mapped data, copying at most maxBytes of it so
+// lines stay valid after the file is unmapped. A page that can no longer be read, because the
+// file was truncated while mapped, fails the scan instead of crashing the process
+func (l *lineReader) scanMapped() (ok bool) {
+	if l.offset >= len(l.data) {
+		return false
+	}
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+	defer func() {
+		if r := recover(); r != nil {
+			l.err = fmt.Errorf("reading mapped file at offset %d: %v", l.offset, r)
+			ok = false
+		}
+	}()
+
+	rest := l.data[l.offset:]
+	end := bytes.IndexByte(rest, '\n')
+	next := end + 1
+	if end < 0 {
+		end, next = len(rest), len(rest)
+	}
+	line := rest[:end]
+	if len(line) > 0 && line[len(line)-1] == '\r' {
+		line = line[:len(line)-1]
+	}
+	l.length = len(line)
+	l.truncated = len(line) > l.maxBytes
+	l.line = append(l.line[:0], line[:min(len(line), l.maxBytes)]...)
+	l.offset += next
+	if l.counter != nil {
+		l.counter.readBytes.Add(int64(next))
+	}
+	return true
+}
+
+// Text returns the current line, truncated to maxBytes
+func (l *lineReader) Text() string {
+	return string(l.line)
+}
+
+// Truncated reports whether the current line is longer than maxBytes
+func (l *lineReader) Truncated() bool {
+	return l.truncated
+}
+
+// Err returns the first read error other than io.EOF
+func (l *lineReader) Err() error {
+	return l.err
+}
+
+// longLineSample describes an oversized line for the error samples by its start and length
+func (l *lineReader) longLineSample() string {
+	return fmt.Sprintf("%s... (line of %d bytes)", l.line[:min(longLineSampleBytes, len(l.line))], l.length)
+}
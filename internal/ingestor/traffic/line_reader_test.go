@@ -0,0 +1,113 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traffic
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineReader(t *testing.T) {
+	type line struct {
+		text      string
+		truncated bool
+	}
+	huge := strings.Repeat("x", 200*1024)
+
+	testCases := []struct {
+		name     string
+		input    string
+		maxBytes int
+		expected []line
+	}{
+		{name: "line endings", input: "a\nbb\r\nccc", maxBytes: 10, expected: []line{{"a", false}, {"bb", false}, {"ccc", false}}},
+		{name: "empty lines", input: "\n\r\n", maxBytes: 10, expected: []line{{"", false}, {"", false}}},
+		{name: "truncated", input: "abcdef\nxy\n", maxBytes: 3, expected: []line{{"abc", true}, {"xy", false}}},
+		{name: "carriage return at the limit", input: "abc\r\nabcd\r\n", maxBytes: 3, expected: []line{{"abc", false}, {"abc", true}}},
+		{name: "longer than the read buffer", input: huge + "\nshort", maxBytes: 1024, expected: []line{{huge[:1024], true}, {"short", false}}},
+		{name: "long line within the limit", input: huge + "\r\n", maxBytes: len(huge), expected: []line{{huge, false}}},
+		{name: "empty input", input: "", maxBytes: 10},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Mapped files are scanned in place and must split lines the same way
+			readers := map[string]*lineReader{
+				"buffered": newLineReader(strings.NewReader(tc.input), tc.maxBytes),
+				"mapped":   newMappedLineReader([]byte(tc.input), tc.maxBytes, nil),
+			}
+			for name, reader := range readers {
+				var lines []line
+				for reader.Scan() {
+					lines = append(lines, line{reader.Text(), reader.Truncated()})
+				}
+				require.NoError(t, reader.Err(), name)
+				assert.Equal(t, tc.expected, lines, name)
+			}
+		})
+	}
+}
+
+func TestLineReader_LongLineSample(t *testing.T) {
+	reader := newLineReader(strings.NewReader(strings.Repeat("y", 5000)+"\n"), 1000)
+	require.True(t, reader.Scan())
+	assert.Equal(t, strings.Repeat("y", longLineSampleBytes)+"... (line of 5000 bytes)", reader.longLineSample())
+}
+
+func TestNginxAccessIngestor_LongLines(t *testing.T) {
+	long := `10.0.0.1 - - [10/Aug/2025:12:00:00 +0000] "GET /api/users/long HTTP/1.1" 200 512 ` + strings.Repeat("z", 4096)
+	path := writeLogFile(t, t.TempDir(), "access.log", append(commonLines(2), long))
+
+	testCases := []struct {
+		name            string
+		policy          string
+		expectedRecords int
+		expectedErrors  int64
+	}{
+		{name: "skip", policy: LongLinesSkip, expectedRecords: 2, expectedErrors: 1},
+		{name: "truncate", policy: LongLinesTruncate, expectedRecords: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := DefaultIngestOptions()
+			options.LogFormat = "common"
+			options.MaxLineBytes = 1024
+			options.LongLines = tc.policy
+			trafficIngestor := NewNginxAccessIngestor()
+
+			records, err := drain(t, trafficIngestor, []string{path}, options)
+			require.NoError(t, err)
+			assert.Len(t, records, tc.expectedRecords)
+
+			metrics := trafficIngestor.Metrics()
+			assert.Equal(t, int64(1), metrics.LongLines)
+			assert.Equal(t, int64(1), metrics.Files[path].LongLines)
+			assert.Equal(t, tc.expectedErrors, metrics.ErrorLines)
+			if tc.expectedErrors > 0 {
+				assert.Contains(t, metrics.ErrorSamples[0], fmt.Sprintf("(line of %d bytes)", len(long)))
+			}
+		})
+	}
+
+	options := DefaultIngestOptions()
+	options.LongLines = "wrap"
+	_, err := NewNginxAccessIngestor().Ingest([]string{path}, options)
+	assert.ErrorContains(t, err, `unsupported long-line policy "wrap"`)
+}
@@ -0,0 +1,78 @@
+package traffic
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// MetricsReport is the ingestion metrics artifact written by --metrics-out, for pipelines that
+// monitor the data quality of the log sources feeding contract generation
+type MetricsReport struct {
+	GeneratedAt    time.Time                    `json:"generatedAt"`
+	Inputs         []string                     `json:"inputs"`
+	TotalLines     int64                        `json:"totalLines"`
+	ParsedLines    int64                        `json:"parsedLines"`
+	ErrorLines     int64                        `json:"errorLines"`
+	ErrorRate      float64                      `json:"errorRate"`
+	Incomplete     bool                         `json:"incomplete"` // More than 10% of the lines failed to parse
+	LongLines      int64                        `json:"longLines"`
+	ConvertedLines int64                        `json:"convertedLines"`
+	DurationMs     int64                        `json:"durationMs"`
+	Files          map[string]FileMetricsReport `json:"files"`
+	ErrorSamples   []string                     `json:"errorSamples"`
+}
+
+// FileMetricsReport is the line counts of one input file with its error rate
+type FileMetricsReport struct {
+	FileMetrics
+	ErrorRate float64 `json:"errorRate"`
+}
+
+// NewMetricsReport creates the metrics artifact of an ingestion of inputs
+func NewMetricsReport(inputs []string, metrics *IngestMetrics) *MetricsReport {
+	sortedInputs := append([]string(nil), inputs...)
+	sort.Strings(sortedInputs)
+
+	report := &MetricsReport{
+		GeneratedAt:    time.Now().UTC(),
+		Inputs:         sortedInputs,
+		TotalLines:     metrics.TotalLines,
+		ParsedLines:    metrics.ParsedLines,
+		ErrorLines:     metrics.ErrorLines,
+		ErrorRate:      metrics.ErrorRate(),
+		Incomplete:     metrics.IsIncomplete(),
+		LongLines:      metrics.LongLines,
+		ConvertedLines: metrics.ConvertedLines,
+		DurationMs:     metrics.Duration.Milliseconds(),
+		Files:          make(map[string]FileMetricsReport, len(metrics.Files)),
+		ErrorSamples:   metrics.ErrorSamples,
+	}
+	for filePath, file := range metrics.Files {
+		report.Files[filePath] = FileMetricsReport{FileMetrics: *file, ErrorRate: file.ErrorRate()}
+	}
+	if report.ErrorSamples == nil {
+		report.ErrorSamples = []string{}
+	}
+	return report
+}
+
+// WriteMetricsReport writes the metrics artifact as indented JSON, creating its directory
+func WriteMetricsReport(path string, report *MetricsReport) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create metrics directory %s: %w", dir, err)
+		}
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal ingestion metrics: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write ingestion metrics %s: %w", path, err)
+	}
+	return nil
+}
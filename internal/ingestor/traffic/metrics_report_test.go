@@ -0,0 +1,68 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traffic
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteMetricsReport(t *testing.T) {
+	dir := t.TempDir()
+	clean := writeLogFile(t, dir, "b-clean.log", commonLines(8))
+	broken := writeLogFile(t, dir, "a-broken.log", append(commonLines(3), "garbage"))
+
+	options := DefaultIngestOptions()
+	options.LogFormat = "common"
+	trafficIngestor := NewNginxAccessIngestor()
+	_, err := drain(t, trafficIngestor, []string{clean, broken}, options)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "artifacts", "ingest-metrics.json")
+	require.NoError(t, WriteMetricsReport(path, NewMetricsReport([]string{clean, broken}, trafficIngestor.Metrics())))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var document map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &document))
+
+	assert.Equal(t, []interface{}{broken, clean}, document["inputs"])
+	assert.Equal(t, 12.0, document["totalLines"])
+	assert.Equal(t, 11.0, document["parsedLines"])
+	assert.Equal(t, 1.0, document["errorLines"])
+	assert.InDelta(t, 1.0/12, document["errorRate"], 1e-9)
+	assert.Equal(t, false, document["incomplete"])
+	assert.Equal(t, []interface{}{"garbage"}, document["errorSamples"])
+	assert.Contains(t, document, "generatedAt")
+	assert.Contains(t, document, "durationMs")
+
+	files := document["files"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"totalLines": 4.0, "parsedLines": 3.0, "errorLines": 1.0, "errorRate": 0.25,
+	}, files[broken])
+	assert.Equal(t, 0.0, files[clean].(map[string]interface{})["errorRate"])
+}
+
+func TestNewMetricsReport_Empty(t *testing.T) {
+	report := NewMetricsReport(nil, NewIngestMetrics())
+	assert.Empty(t, report.Files)
+	assert.NotNil(t, report.ErrorSamples)
+	assert.Equal(t, 0.0, report.ErrorRate)
+}
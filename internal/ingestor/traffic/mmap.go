@@ -0,0 +1,55 @@
+package traffic
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Policies for reading uncompressed log files through a memory mapping
+const (
+	// MmapAuto maps uncompressed files of at least mmapMinBytes, falling back to buffered
+	// reads when the file cannot be mapped
+	MmapAuto = "auto"
+	// MmapOff always reads files through a buffered reader
+	MmapOff = "off"
+)
+
+// mmapMinBytes is the smallest file worth mapping; below it buffered reads are as fast
+var mmapMinBytes int64 = 16 * 1024 * 1024
+
+// errMmapUnsupported is returned by mapFile on platforms without memory mapping
+var errMmapUnsupported = errors.New("memory mapping is not supported on this platform")
+
+// ValidateMmap checks that a memory mapping policy is empty, "auto" or "off"
+func ValidateMmap(policy string) error {
+	switch policy {
+	case "", MmapAuto, MmapOff:
+		return nil
+	}
+	return fmt.Errorf("unsupported mmap policy %q, must be %s or %s", policy, MmapAuto, MmapOff)
+}
+
+// mapLogFile maps file read-only when the policy allows it and the file is uncompressed and
+// large enough. It returns nil data when the file is to be read buffered instead, including
+// when mapping fails
+func mapLogFile(file *os.File, filePath, policy string) ([]byte, func() error) {
+	if policy == MmapOff {
+		return nil, nil
+	}
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".gz", ".zst":
+		return nil, nil
+	}
+	info, err := file.Stat()
+	if err != nil || !info.Mode().IsRegular() || info.Size() < mmapMinBytes || int64(int(info.Size())) != info.Size() {
+		return nil, nil
+	}
+	data, unmap, err := mapFile(file, info.Size())
+	if err != nil {
+		return nil, nil
+	}
+	return data, unmap
+}
@@ -0,0 +1,10 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package traffic
+
+import "os"
+
+// mapFile is not supported on this platform, so files are always read buffered
+func mapFile(file *os.File, size int64) ([]byte, func() error, error) {
+	return nil, nil, errMmapUnsupported
+}
@@ -0,0 +1,107 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traffic
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMmap(t *testing.T) {
+	for _, policy := range []string{"", MmapAuto, MmapOff} {
+		assert.NoError(t, ValidateMmap(policy), policy)
+	}
+	assert.ErrorContains(t, ValidateMmap("always"), `unsupported mmap policy "always"`)
+}
+
+func TestMapLogFile(t *testing.T) {
+	previous := mmapMinBytes
+	mmapMinBytes = 10
+	t.Cleanup(func() { mmapMinBytes = previous })
+
+	dir := t.TempDir()
+	open := func(name, content string) *os.File {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		t.Cleanup(func() { file.Close() })
+		return file
+	}
+
+	large := open("access.log", strings.Repeat("x", 100))
+	_, unmap, err := mapFile(large, 100)
+	if err == errMmapUnsupported {
+		t.Skip(err)
+	}
+	require.NoError(t, err)
+	require.NoError(t, unmap())
+	data, unmap := mapLogFile(large, "access.log", MmapAuto)
+	require.NotNil(t, data)
+	assert.Equal(t, strings.Repeat("x", 100), string(data))
+	require.NoError(t, unmap())
+
+	// Small, compressed and opted-out files are read buffered
+	data, _ = mapLogFile(open("small.log", "x"), "small.log", MmapAuto)
+	assert.Nil(t, data)
+	data, _ = mapLogFile(open("access.log.gz", strings.Repeat("x", 100)), "access.log.gz", MmapAuto)
+	assert.Nil(t, data)
+	data, _ = mapLogFile(large, "access.log", MmapOff)
+	assert.Nil(t, data)
+}
+
+func TestNginxAccessIngestor_Mmap(t *testing.T) {
+	previous := mmapMinBytes
+	mmapMinBytes = 0
+	t.Cleanup(func() { mmapMinBytes = previous })
+
+	lines := append(commonLines(20), "invalid log line", commonLines(1)[0]+"\r")
+	path := writeLogFile(t, t.TempDir(), "access.log", lines)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+
+	ingest := func(policy string) ([]*NormalizedRecord, *IngestMetrics, int64) {
+		options := DefaultIngestOptions()
+		options.LogFormat = "common"
+		options.Mmap = policy
+		var processedBytes int64
+		options.ProgressCallback = func(processed, total, parsed int64) {
+			processedBytes = processed
+		}
+		trafficIngestor := NewNginxAccessIngestor()
+		records, err := drain(t, trafficIngestor, []string{path}, options)
+		require.NoError(t, err)
+		return records, trafficIngestor.Metrics(), processedBytes
+	}
+
+	mapped, mappedMetrics, mappedBytes := ingest(MmapAuto)
+	buffered, bufferedMetrics, bufferedBytes := ingest(MmapOff)
+	require.Len(t, mapped, 21)
+	assert.Equal(t, buffered, mapped)
+	assert.Equal(t, bufferedMetrics.TotalLines, mappedMetrics.TotalLines)
+	assert.Equal(t, int64(1), mappedMetrics.ErrorLines)
+	assert.Equal(t, info.Size(), mappedBytes)
+	assert.Equal(t, bufferedBytes, mappedBytes)
+
+	options := DefaultIngestOptions()
+	options.Mmap = "always"
+	_, err = NewNginxAccessIngestor().Ingest([]string{path}, options)
+	assert.ErrorContains(t, err, `unsupported mmap policy "always"`)
+}
@@ -11,6 +11,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/flowspec/flowspec-cli/internal/ingestor"
@@ -24,25 +26,68 @@ type NginxAccessIngestor struct {
 	regex       *regexp.Regexp
 	logFormat   string
 	timeLayout  string
+	template    *logFormatTemplate
+	decoder     *lineDecoder
+	progress    *progressCounter
 }
 
+// progressReportInterval is the number of lines between progress callbacks and memory budget checks
+const progressReportInterval = 10000
+
+// progressCounter counts the input bytes read and records parsed across all files for
+// progress callbacks; files processed in parallel share it
+type progressCounter struct {
+	totalBytes int64
+	readBytes  atomic.Int64
+	records    atomic.Int64
+	mu         sync.Mutex // Serializes callbacks
+}
+
+// countingReader counts the bytes read from the underlying (possibly compressed) file
+type countingReader struct {
+	reader  io.Reader
+	counter *progressCounter
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.counter.readBytes.Add(int64(n))
+	return n, err
+}
+
+// Named regex groups that capture $request_time and $upstream_response_time, in the
+// predefined formats as well as in custom regexes
+const (
+	requestTimeGroup          = "request_time"
+	upstreamResponseTimeGroup = "upstream_response_time"
+)
+
+// durationSuffix optionally captures $request_time and $upstream_response_time appended to a
+// predefined format, bare or as rt=... and urt=..., e.g. ` 0.005 "0.004"` or ` rt=0.005 urt=0.004`
+const durationSuffix = `(?: (?:rt=|request_time=)?(?P<request_time>` + logFormatNumber + `|-)` +
+	`(?: (?:urt=|upstream_response_time=)?"?(?P<upstream_response_time>-|` + logFormatNumber + `(?:(?:, | : )(?:` + logFormatNumber + `|-))*)"?)?)?`
+
 // Predefined Nginx log formats with their corresponding regex patterns
 var nginxLogFormats = map[string]struct {
 	regex      string
 	timeLayout string
 }{
 	"combined": {
-		// Combined log format: $remote_addr - $remote_user [$time_local] "$request" $status $body_bytes_sent "$http_referer" "$http_user_agent"
-		regex:      `^(\S+) - (\S+) \[([^\]]+)\] "([A-Z]+) ([^"]*) HTTP/[^"]*" (\d+) (\d+) "([^"]*)" "([^"]*)"`,
-		timeLayout: "02/Jan/2006:15:04:05 -0700",
+		// Combined log format: $remote_addr - $remote_user [$time_local] "$request" $status $body_bytes_sent "$http_referer" "$http_user_agent" [$request_time [$upstream_response_time]]
+		regex:      `^(\S+) - (\S+) \[([^\]]+)\] "([A-Z]+) ([^"]*) HTTP/[^"]*" (\d+) (\d+) "([^"]*)" "([^"]*)"` + durationSuffix,
+		timeLayout: timeLocalLayout,
 	},
 	"common": {
-		// Common log format: $remote_addr - $remote_user [$time_local] "$request" $status $body_bytes_sent
-		regex:      `^(\S+) - (\S+) \[([^\]]+)\] "([A-Z]+) ([^"]*) HTTP/[^"]*" (\d+) (\d+)`,
-		timeLayout: "02/Jan/2006:15:04:05 -0700",
+		// Common log format: $remote_addr - $remote_user [$time_local] "$request" $status $body_bytes_sent [$request_time [$upstream_response_time]]
+		regex:      `^(\S+) - (\S+) \[([^\]]+)\] "([A-Z]+) ([^"]*) HTTP/[^"]*" (\d+) (\d+)` + durationSuffix,
+		timeLayout: timeLocalLayout,
 	},
 }
 
+func init() {
+	Register(DefaultFormat, func() TrafficIngestor { return NewNginxAccessIngestor() })
+}
+
 // NewNginxAccessIngestor creates a new Nginx access log ingestor
 func NewNginxAccessIngestor() *NginxAccessIngestor {
 	return &NginxAccessIngestor{
@@ -183,6 +228,18 @@ func (n *NginxAccessIngestor) Ingest(inputs []string, options *IngestOptions) (i
 	n.options = options
 	n.metrics = NewIngestMetrics()
 	
+	if err := ValidateLongLines(options.LongLines); err != nil {
+		return nil, err
+	}
+	if err := ValidateMmap(options.Mmap); err != nil {
+		return nil, err
+	}
+	decoder, err := newLineDecoder(options.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	n.decoder = decoder
+	
 	// Setup regex pattern
 	if err := n.setupRegex(); err != nil {
 		return nil, fmt.Errorf("failed to setup regex pattern: %w", err)
@@ -202,10 +259,26 @@ func (n *NginxAccessIngestor) setupRegex() error {
 	var regexPattern string
 	var timeLayout string
 	
+	// A log_format template compiles to its own parser
+	n.template = nil
+	if n.options.NginxLogFormat != "" {
+		if n.options.CustomRegex != "" {
+			return fmt.Errorf("a custom regex and an nginx log format cannot be used together")
+		}
+		template, err := compileLogFormat(n.options.NginxLogFormat)
+		if err != nil {
+			return err
+		}
+		n.template = template
+		n.regex = template.regex
+		n.logFormat = "template"
+		return nil
+	}
+	
 	// Use custom regex if provided
 	if n.options.CustomRegex != "" {
 		regexPattern = n.options.CustomRegex
-		timeLayout = "02/Jan/2006:15:04:05 -0700" // Default time layout
+		timeLayout = timeLocalLayout // Default time layout
 		n.logFormat = "custom"
 	} else {
 		// Use predefined format
@@ -245,7 +318,8 @@ Example log lines:
   common:   192.168.1.1 - - [10/Aug/2025:12:00:00 +0000] "GET /api/users/123 HTTP/1.1" 200 1234
 
 To use a custom format, specify --regex with your own regular expression pattern.
-The regex should capture groups in this order: remote_addr, remote_user, time_local, method, request_uri, status, body_bytes_sent, [referer], [user_agent]`,
+The regex should capture groups in this order: remote_addr, remote_user, time_local, method, request_uri, status, body_bytes_sent, [referer], [user_agent].
+Durations are read from groups named (?P<request_time>...) and (?P<upstream_response_time>...), in seconds.`,
 		n.options.LogFormat, strings.Join(supportedFormats, ", "))
 }
 
@@ -255,50 +329,170 @@ func (n *NginxAccessIngestor) processFiles(inputs []string, dataCh chan<- *Norma
 	
 	startTime := time.Now()
 	
-	for _, input := range inputs {
-		if err := n.processFile(input, dataCh); err != nil {
-			errCh <- fmt.Errorf("failed to process file %s: %w", input, err)
-			return
+	if n.options.ProgressCallback != nil {
+		n.progress = &progressCounter{}
+		for _, input := range inputs {
+			if info, err := os.Stat(input); err == nil {
+				n.progress.totalBytes += info.Size()
+			}
+		}
+	}
+	
+	var err error
+	if n.options.Parallelism > 1 && len(inputs) > 1 {
+		err = n.processFilesParallel(inputs, dataCh)
+	} else {
+		for _, input := range inputs {
+			if err = n.processFile(context.Background(), input, n.metrics, dataCh); err != nil {
+				err = fmt.Errorf("failed to process file %s: %w", input, err)
+				break
+			}
 		}
 	}
+	if err != nil {
+		errCh <- err
+		return
+	}
 	
 	n.metrics.SetDuration(time.Since(startTime))
 }
 
-// processFile processes a single file
-func (n *NginxAccessIngestor) processFile(filePath string, dataCh chan<- *NormalizedRecord) error {
+// processFilesParallel processes up to Parallelism files at once. Records of one file keep
+// their order; records of different files interleave as they are parsed. The first failure
+// stops the remaining files.
+func (n *NginxAccessIngestor) processFilesParallel(inputs []string, dataCh chan<- *NormalizedRecord) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	
+	files := make(chan string)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	workers := n.options.Parallelism
+	if workers > len(inputs) {
+		workers = len(inputs)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for input := range files {
+				// Each file counts into its own metrics, merged once the file is done
+				metrics := NewIngestMetrics()
+				err := n.processFile(ctx, input, metrics, dataCh)
+				
+				mu.Lock()
+				n.metrics.Merge(metrics, n.options.MaxErrorSamples)
+				if err != nil && firstErr == nil && ctx.Err() == nil {
+					firstErr = fmt.Errorf("failed to process file %s: %w", input, err)
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	
+	for _, input := range inputs {
+		select {
+		case files <- input:
+		case <-ctx.Done():
+		}
+	}
+	close(files)
+	wg.Wait()
+	return firstErr
+}
+
+// processFile processes a single file, counting its lines into metrics
+func (n *NginxAccessIngestor) processFile(ctx context.Context, filePath string, metrics *IngestMetrics, dataCh chan<- *NormalizedRecord) error {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
-	
-	// Create reader with compression support
-	reader, err := n.createReader(file, filePath)
-	if err != nil {
-		return fmt.Errorf("failed to create reader: %w", err)
+	if n.progress != nil {
+		defer n.reportProgress()
 	}
-	defer reader.Close()
 	
-	scanner := bufio.NewScanner(reader)
-	
-	// Set a larger buffer for long log lines
-	const maxCapacity = 1024 * 1024 // 1MB
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, maxCapacity)
+	// Lines longer than MaxLineBytes are cut instead of failing the whole file
+	maxLineBytes := n.options.MaxLineBytes
+	if maxLineBytes <= 0 {
+		maxLineBytes = DefaultMaxLineBytes
+	}
 	
-	for scanner.Scan() {
-		line := scanner.Text()
-		n.metrics.AddTotal()
-		
-		// Apply sampling if configured
-		if n.options.SampleRate < 1.0 && n.shouldSkipLine() {
-			continue
+	// Large uncompressed files are scanned in place through a memory mapping, saving a read
+	// syscall and a copy per buffer; anything else, or a file that cannot be mapped, is read buffered
+	var lines *lineReader
+	if data, unmap := mapLogFile(file, filePath, n.options.Mmap); data != nil {
+		defer unmap()
+		lines = newMappedLineReader(data, maxLineBytes, n.progress)
+	} else {
+		// Create reader with compression support, counting the bytes read from disk for progress
+		var source io.Reader = file
+		if n.progress != nil {
+			source = &countingReader{reader: file, counter: n.progress}
+		}
+		reader, err := n.createReader(source, filePath)
+		if err != nil {
+			return fmt.Errorf("failed to create reader: %w", err)
+		}
+		defer reader.Close()
+		lines = newLineReader(reader, maxLineBytes)
+	}
+	
+	// Count the lines of the file separately for the per-file breakdown and the error budget
+	fileMetrics := &FileMetrics{}
+	defer metrics.AddFile(filePath, fileMetrics)
+	var failedLines []string
+	sampler := newEndpointSampler(n.options.SampleRate)
+	
+	for lines.Scan() {
+		line := lines.Text()
+		metrics.AddTotal()
+		fileMetrics.TotalLines++
+		if metrics.TotalLines%progressReportInterval == 0 {
+			if n.progress != nil {
+				n.reportProgress()
+			}
+			if err := n.options.MemoryBudget.Check("reading " + filepath.Base(filePath)); err != nil {
+				return err
+			}
 		}
 		
-		record, err := n.parseLogLine(line)
+		// An oversized line is an error unless the policy is to parse its start
+		var record *NormalizedRecord
+		var err error
+		if lines.Truncated() {
+			metrics.AddLongLine()
+			fileMetrics.LongLines++
+		}
+		if lines.Truncated() && n.options.LongLines != LongLinesTruncate {
+			err = fmt.Errorf("line longer than %d bytes", maxLineBytes)
+		} else {
+			// Lines are parsed as UTF-8 whatever the configured encoding
+			decoded, converted := n.decoder.decodeLine(line)
+			if converted {
+				metrics.AddConvertedLine()
+			}
+			if record, err = n.parseLogLine(decoded); err == nil {
+				n.decoder.sanitizeRecord(record)
+			}
+		}
 		if err != nil {
-			n.metrics.AddError(line, n.options.MaxErrorSamples)
+			fileMetrics.ErrorLines++
+			if lines.Truncated() {
+				metrics.AddError(lines.longLineSample(), n.options.MaxErrorSamples)
+			} else {
+				metrics.AddError(line, n.options.MaxErrorSamples)
+				if len(failedLines) < maxSuggestionLines {
+					failedLines = append(failedLines, line)
+				}
+			}
+			if err := n.checkErrorBudget(filePath, fileMetrics, failedLines, false); err != nil {
+				return err
+			}
 			continue
 		}
 		
@@ -307,25 +501,42 @@ func (n *NginxAccessIngestor) processFile(filePath string, dataCh chan<- *Normal
 			continue
 		}
 		
-		n.metrics.AddParsed()
+		// Sample per endpoint, so bursts of one endpoint do not crowd out the others
+		if sampler != nil && !sampler.keep(record) {
+			fileMetrics.SkippedLines++
+			continue
+		}
 		
-		// Send record to channel (with context cancellation support)
+		metrics.AddParsed()
+		fileMetrics.ParsedLines++
+		if n.progress != nil {
+			n.progress.records.Add(1)
+		}
+		
+		// Send record to channel, giving up when another file failed
 		select {
 		case dataCh <- record:
-		case <-context.Background().Done():
-			return context.Background().Err()
+		case <-ctx.Done():
+			return ctx.Err()
 		}
 	}
 	
-	if err := scanner.Err(); err != nil {
+	if err := lines.Err(); err != nil {
 		return fmt.Errorf("error reading file: %w", err)
 	}
 	
-	return nil
+	return n.checkErrorBudget(filePath, fileMetrics, failedLines, true)
+}
+
+// reportProgress passes the bytes read and records parsed so far to the progress callback
+func (n *NginxAccessIngestor) reportProgress() {
+	n.progress.mu.Lock()
+	defer n.progress.mu.Unlock()
+	n.options.ProgressCallback(n.progress.readBytes.Load(), n.progress.totalBytes, n.progress.records.Load())
 }
 
 // createReader creates an appropriate reader based on file extension
-func (n *NginxAccessIngestor) createReader(file *os.File, filePath string) (io.ReadCloser, error) {
+func (n *NginxAccessIngestor) createReader(file io.Reader, filePath string) (io.ReadCloser, error) {
 	ext := strings.ToLower(filepath.Ext(filePath))
 	
 	switch ext {
@@ -348,13 +559,6 @@ func (n *NginxAccessIngestor) createReader(file *os.File, filePath string) (io.R
 	}
 }
 
-// shouldSkipLine determines if a line should be skipped based on sampling rate
-func (n *NginxAccessIngestor) shouldSkipLine() bool {
-	// Simple sampling based on line count
-	// In a real implementation, you might want to use a more sophisticated approach
-	return float64(n.metrics.TotalLines%100)/100.0 >= n.options.SampleRate
-}
-
 // isWithinTimeRange checks if a timestamp is within the configured time range
 func (n *NginxAccessIngestor) isWithinTimeRange(timestamp time.Time) bool {
 	if n.options.TimeFilter == nil {
@@ -371,52 +575,54 @@ func (n *NginxAccessIngestor) isWithinTimeRange(timestamp time.Time) bool {
 
 // parseLogLine parses a single log line into a NormalizedRecord
 func (n *NginxAccessIngestor) parseLogLine(line string) (*NormalizedRecord, error) {
-	matches := n.regex.FindStringSubmatch(line)
-	if matches == nil {
-		return nil, fmt.Errorf("line does not match expected format")
+	if n.template != nil {
+		return n.parseTemplateLine(line)
 	}
 	
-	// Extract fields based on the regex groups
-	// The exact mapping depends on the regex pattern, but we'll handle the common cases
-	
-	var (
-		remoteAddr   string
-		timeLocal    string
-		method       string
-		requestURI   string
-		status       string
-		bodyBytes    string
-		referer      string
-		userAgent    string
-	)
-	
-	// Map regex groups to fields (this assumes the standard nginx formats)
-	if len(matches) >= 7 {
-		remoteAddr = matches[1]
-		// remoteUser = matches[2] // Not currently used, but available for future enhancement
-		timeLocal = matches[3]
-		method = matches[4]
-		requestURI = matches[5]
-		status = matches[6]
-		bodyBytes = matches[7]
+	// The predefined formats are split by hand, which is several times faster than the regex;
+	// lines the splitter does not accept go through the regex for its error
+	var fields accessLogFields
+	split := false
+	if n.logFormat == "combined" || n.logFormat == "common" {
+		fields, split = splitAccessLogLine(line, n.logFormat == "combined")
+	}
+	if !split {
+		matches := n.regex.FindStringSubmatch(line)
+		if matches == nil {
+			return nil, fmt.Errorf("line does not match expected format")
+		}
 		
-		// Additional fields for combined format
-		if len(matches) >= 9 {
-			referer = matches[8]
-			userAgent = matches[9]
+		// Map regex groups to fields (this assumes the standard nginx formats)
+		if len(matches) >= 7 {
+			fields.remoteAddr = matches[1]
+			// remoteUser = matches[2] // Not currently used, but available for future enhancement
+			fields.timeLocal = matches[3]
+			fields.method = matches[4]
+			fields.requestURI = matches[5]
+			fields.status = matches[6]
+			fields.bodyBytes = matches[7]
+			
+			// Additional fields for combined format; named groups hold durations instead
+			if len(matches) >= 10 && n.regex.SubexpNames()[8] == "" && n.regex.SubexpNames()[9] == "" {
+				fields.referer = matches[8]
+				fields.userAgent = matches[9]
+			}
+		} else {
+			return nil, fmt.Errorf("insufficient regex groups captured")
 		}
-	} else {
-		return nil, fmt.Errorf("insufficient regex groups captured")
+		fields.requestTime = namedGroup(n.regex, matches, requestTimeGroup)
+		fields.upstreamResponseTime = namedGroup(n.regex, matches, upstreamResponseTimeGroup)
 	}
+	remoteAddr, method, requestURI, bodyBytes := fields.remoteAddr, fields.method, fields.requestURI, fields.bodyBytes
 	
 	// Parse timestamp
-	timestamp, err := n.parseTimestamp(timeLocal)
+	timestamp, err := n.parseTimestamp(fields.timeLocal)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse timestamp: %w", err)
 	}
 	
 	// Parse status code
-	statusCode, err := strconv.Atoi(status)
+	statusCode, err := strconv.Atoi(fields.status)
 	if err != nil {
 		return nil, fmt.Errorf("invalid status code: %w", err)
 	}
@@ -437,11 +643,11 @@ func (n *NginxAccessIngestor) parseLogLine(line string) (*NormalizedRecord, erro
 	
 	// Create headers map from available data
 	headers := make(map[string]string)
-	if referer != "" && referer != "-" {
-		headers["referer"] = referer
+	if fields.referer != "" && fields.referer != "-" {
+		headers["referer"] = fields.referer
 	}
-	if userAgent != "" && userAgent != "-" {
-		headers["user-agent"] = userAgent
+	if fields.userAgent != "" && fields.userAgent != "-" {
+		headers["user-agent"] = fields.userAgent
 	}
 	
 	// Create the normalized record
@@ -457,6 +663,9 @@ func (n *NginxAccessIngestor) parseLogLine(line string) (*NormalizedRecord, erro
 		Scheme:    "http",     // Default to http, could be enhanced to detect https
 		BodyBytes: bodyBytesInt,
 	}
+	if err := setRecordDurations(record, fields.requestTime, fields.upstreamResponseTime); err != nil {
+		return nil, err
+	}
 	
 	// Apply redaction policy
 	record.Headers, record.Query = ApplyRedactionPolicy(
@@ -471,6 +680,12 @@ func (n *NginxAccessIngestor) parseLogLine(line string) (*NormalizedRecord, erro
 
 // parseTimestamp parses the timestamp from the log line and converts it to RFC3339
 func (n *NginxAccessIngestor) parseTimestamp(timeStr string) (time.Time, error) {
+	if n.timeLayout == timeLocalLayout {
+		if parsedTime, ok := parseTimeLocal(timeStr); ok {
+			return parsedTime, nil
+		}
+	}
+	
 	// Parse using the configured time layout
 	parsedTime, err := time.Parse(n.timeLayout, timeStr)
 	if err != nil {
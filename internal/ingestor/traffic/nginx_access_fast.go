@@ -0,0 +1,272 @@
+package traffic
+
+import (
+	"strings"
+	"time"
+)
+
+// accessLogFields are the fields of an access log line, as substrings of the line
+type accessLogFields struct {
+	remoteAddr           string
+	timeLocal            string
+	method               string
+	requestURI           string
+	status               string
+	bodyBytes            string
+	referer              string
+	userAgent            string
+	requestTime          string
+	upstreamResponseTime string
+}
+
+// splitAccessLogLine splits a line of the predefined combined or common format by hand,
+// without allocating, into the same fields as their regexes. It reports false for lines it
+// does not accept, which are left to the regex.
+func splitAccessLogLine(line string, combined bool) (accessLogFields, bool) {
+	var fields accessLogFields
+	var ok bool
+
+	// $remote_addr - $remote_user [$time_local] "
+	if fields.remoteAddr, line, ok = cutToken(line, " - "); !ok {
+		return fields, false
+	}
+	if _, line, ok = cutToken(line, " ["); !ok {
+		return fields, false
+	}
+	end := strings.IndexByte(line, ']')
+	if end <= 0 || !strings.HasPrefix(line[end:], `] "`) {
+		return fields, false
+	}
+	fields.timeLocal, line = line[:end], line[end+3:]
+
+	// "$request": an uppercase method, then the URI up to the last " HTTP/" before the quote
+	end = 0
+	for end < len(line) && line[end] >= 'A' && line[end] <= 'Z' {
+		end++
+	}
+	if end == 0 || end == len(line) || line[end] != ' ' {
+		return fields, false
+	}
+	fields.method, line = line[:end], line[end+1:]
+	quote := strings.IndexByte(line, '"')
+	if quote < 0 {
+		return fields, false
+	}
+	version := strings.LastIndex(line[:quote], " HTTP/")
+	if version < 0 || !strings.HasPrefix(line[quote:], `" `) {
+		return fields, false
+	}
+	fields.requestURI, line = line[:version], line[quote+2:]
+
+	// $status $body_bytes_sent
+	if fields.status, line, ok = cutDigits(line, " "); !ok {
+		return fields, false
+	}
+	if combined {
+		// "$http_referer" "$http_user_agent"
+		if fields.bodyBytes, line, ok = cutDigits(line, " "); !ok {
+			return fields, false
+		}
+		if fields.referer, line, ok = cutQuoted(line, ` `); !ok {
+			return fields, false
+		}
+		if fields.userAgent, line, ok = cutQuoted(line, ""); !ok {
+			return fields, false
+		}
+	} else if fields.bodyBytes, line, ok = cutDigits(line, ""); !ok {
+		return fields, false
+	}
+
+	fields.requestTime, fields.upstreamResponseTime = splitDurationSuffix(line)
+	return fields, true
+}
+
+// cutToken cuts line at the first space, which must start separator, returning the
+// non-empty text before it and the text after the separator. Like \S+, the text must not
+// contain other whitespace either.
+func cutToken(line, separator string) (string, string, bool) {
+	end := strings.IndexByte(line, ' ')
+	if end <= 0 || !strings.HasPrefix(line[end:], separator) || strings.ContainsAny(line[:end], "\t\n\f\r") {
+		return "", line, false
+	}
+	return line[:end], line[end+len(separator):], true
+}
+
+// cutDigits cuts the digits at the start of line, which must be followed by separator
+func cutDigits(line, separator string) (string, string, bool) {
+	end := digitsPrefix(line)
+	if end == 0 || !strings.HasPrefix(line[end:], separator) {
+		return "", line, false
+	}
+	return line[:end], line[end+len(separator):], true
+}
+
+// cutQuoted cuts a quoted value at the start of line, which must be followed by separator
+func cutQuoted(line, separator string) (string, string, bool) {
+	if !strings.HasPrefix(line, `"`) {
+		return "", line, false
+	}
+	end := strings.IndexByte(line[1:], '"')
+	if end < 0 || !strings.HasPrefix(line[end+2:], separator) {
+		return "", line, false
+	}
+	return line[1 : end+1], line[end+2+len(separator):], true
+}
+
+// digitsPrefix returns the number of ASCII digits at the start of value
+func digitsPrefix(value string) int {
+	end := 0
+	for end < len(value) && value[end] >= '0' && value[end] <= '9' {
+		end++
+	}
+	return end
+}
+
+// numberPrefix returns the length of the duration, such as 0.005, at the start of value,
+// matching logFormatNumber
+func numberPrefix(value string) int {
+	end := digitsPrefix(value)
+	if end > 0 && end < len(value) && value[end] == '.' {
+		if fraction := digitsPrefix(value[end+1:]); fraction > 0 {
+			end += 1 + fraction
+		}
+	}
+	return end
+}
+
+// durationPrefix returns the length of a duration or "-" at the start of value
+func durationPrefix(value string) int {
+	if strings.HasPrefix(value, "-") {
+		return 1
+	}
+	return numberPrefix(value)
+}
+
+// splitDurationSuffix returns the $request_time and $upstream_response_time at the start of
+// rest as durationSuffix captures them, e.g. from ` 0.005 "0.004"` or ` rt=0.005 urt=0.004`
+func splitDurationSuffix(rest string) (string, string) {
+	if !strings.HasPrefix(rest, " ") {
+		return "", ""
+	}
+	rest = rest[1:]
+	value := trimAnyPrefix(rest, "rt=", "request_time=")
+	length := durationPrefix(value)
+	if length == 0 {
+		value = rest
+		if length = durationPrefix(value); length == 0 {
+			return "", ""
+		}
+	}
+	requestTime := value[:length]
+	rest = value[length:]
+
+	if !strings.HasPrefix(rest, " ") {
+		return requestTime, ""
+	}
+	rest = rest[1:]
+	for _, candidate := range []string{trimAnyPrefix(rest, "urt=", "upstream_response_time="), rest} {
+		if upstream := upstreamPrefix(strings.TrimPrefix(candidate, `"`)); upstream != "" {
+			return requestTime, upstream
+		}
+		if upstream := upstreamPrefix(candidate); upstream != "" {
+			return requestTime, upstream
+		}
+	}
+	return requestTime, ""
+}
+
+// upstreamPrefix returns the $upstream_response_time at the start of value: "-", or
+// durations of several upstreams separated by ", " or " : "
+func upstreamPrefix(value string) string {
+	if strings.HasPrefix(value, "-") {
+		return "-"
+	}
+	end := numberPrefix(value)
+	if end == 0 {
+		return ""
+	}
+	for {
+		rest := value[end:]
+		separator := 0
+		if strings.HasPrefix(rest, ", ") {
+			separator = 2
+		} else if strings.HasPrefix(rest, " : ") {
+			separator = 3
+		}
+		if separator == 0 {
+			return value[:end]
+		}
+		length := durationPrefix(rest[separator:])
+		if length == 0 {
+			return value[:end]
+		}
+		end += separator + length
+	}
+}
+
+// trimAnyPrefix removes the first of prefixes that value starts with
+func trimAnyPrefix(value string, prefixes ...string) string {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(value, prefix) {
+			return value[len(prefix):]
+		}
+	}
+	return value
+}
+
+// timeLocalLayout is the layout of $time_local
+const timeLocalLayout = "02/Jan/2006:15:04:05 -0700"
+
+// accessLogMonths are the month abbreviations of $time_local
+var accessLogMonths = [...]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+
+// parseTimeLocal parses a $time_local such as "10/Aug/2025:12:00:00 +0000" in UTC, like
+// time.Parse with timeLocalLayout but several times faster. It reports false for anything
+// out of the ordinary, which is left to time.Parse.
+func parseTimeLocal(value string) (time.Time, bool) {
+	if len(value) != 26 || value[2] != '/' || value[6] != '/' || value[11] != ':' || value[14] != ':' ||
+		value[17] != ':' || value[20] != ' ' || (value[21] != '+' && value[21] != '-') {
+		return time.Time{}, false
+	}
+	month := 0
+	for i, name := range accessLogMonths {
+		if value[3:6] == name {
+			month = i + 1
+			break
+		}
+	}
+	day, ok1 := twoDigits(value[0:2])
+	century, ok2 := twoDigits(value[7:9])
+	year, ok3 := twoDigits(value[9:11])
+	hour, ok4 := twoDigits(value[12:14])
+	minute, ok5 := twoDigits(value[15:17])
+	second, ok6 := twoDigits(value[18:20])
+	zoneHours, ok7 := twoDigits(value[22:24])
+	zoneMinutes, ok8 := twoDigits(value[24:26])
+	if month == 0 || !(ok1 && ok2 && ok3 && ok4 && ok5 && ok6 && ok7 && ok8) {
+		return time.Time{}, false
+	}
+	year += century * 100
+	if day < 1 || day > daysIn(time.Month(month), year) || hour > 23 || minute > 59 || second > 59 ||
+		zoneHours > 24 || zoneMinutes > 59 {
+		return time.Time{}, false
+	}
+	offset := time.Duration(zoneHours)*time.Hour + time.Duration(zoneMinutes)*time.Minute
+	if value[21] == '-' {
+		offset = -offset
+	}
+	return time.Date(year, time.Month(month), day, hour, minute, second, 0, time.UTC).Add(-offset), true
+}
+
+// twoDigits parses two ASCII digits
+func twoDigits(value string) (int, bool) {
+	if value[0] < '0' || value[0] > '9' || value[1] < '0' || value[1] > '9' {
+		return 0, false
+	}
+	return int(value[0]-'0')*10 + int(value[1]-'0'), true
+}
+
+// daysIn returns the number of days of month in year
+func daysIn(month time.Month, year int) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
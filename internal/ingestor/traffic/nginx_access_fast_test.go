@@ -0,0 +1,194 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traffic
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// regexAccessLogFields splits a line with the regex of a predefined format
+func regexAccessLogFields(t *testing.T, format, line string) (accessLogFields, bool) {
+	regex := regexp.MustCompile(nginxLogFormats[format].regex)
+	matches := regex.FindStringSubmatch(line)
+	if matches == nil {
+		return accessLogFields{}, false
+	}
+	fields := accessLogFields{
+		remoteAddr:           matches[1],
+		timeLocal:            matches[3],
+		method:               matches[4],
+		requestURI:           matches[5],
+		status:               matches[6],
+		bodyBytes:            matches[7],
+		requestTime:          namedGroup(regex, matches, requestTimeGroup),
+		upstreamResponseTime: namedGroup(regex, matches, upstreamResponseTimeGroup),
+	}
+	if format == "combined" {
+		fields.referer, fields.userAgent = matches[8], matches[9]
+	}
+	return fields, true
+}
+
+func TestSplitAccessLogLine(t *testing.T) {
+	const prefix = `192.168.1.1 - frank [10/Aug/2025:12:00:00 +0000] "GET /api/users/123?active=true HTTP/1.1" 200 1234`
+	const agents = ` "http://example.com" "Mozilla/5.0 (X11)"`
+	combinedLines := []string{
+		prefix + agents,
+		prefix + agents + ` 0.005`,
+		prefix + agents + ` 0.005 0.004`,
+		prefix + agents + ` 0.005 "0.004"`,
+		prefix + agents + ` rt=0.005 urt=0.004`,
+		prefix + agents + ` rt=0.005 urt="0.010, 0.020 : -"`,
+		prefix + agents + ` request_time=1 upstream_response_time=-`,
+		prefix + agents + ` - -`,
+		prefix + agents + ` 0.005 "-, 0.1"`,
+		prefix + agents + ` 0.005 0.004, x`,
+		prefix + agents + ` 1. 2`,
+		prefix + agents + ` rt=x`,
+		prefix + agents + ` extra fields`,
+		prefix + agents + `0.005`,
+		`10.0.0.1 - - [10/Aug/2025:12:00:00 +0000] "GET /a HTTP/1.1 HTTP/2.0" 200 1 "-" "-"`,
+		`10.0.0.1 - - [10/Aug/2025:12:00:00 +0000] "GET  HTTP/1.1" 200 1 "" ""`,
+		`10.0.0.1 - - [10/Aug/2025:12:00:00 +0000] "GET /a b c HTTP/1.1" 200 1 "-" "-"`,
+		`10.0.0.1 - - [10/Aug/2025:12:00:00 +0000] "get /a HTTP/1.1" 200 1 "-" "-"`,
+		`10.0.0.1 - - [10/Aug/2025:12:00:00 +0000] "GET /a" 200 1 "-" "-"`,
+		`10.0.0.1 - - [10/Aug/2025:12:00:00 +0000] "GET /a HTTP/1.1" 200 - "-" "-"`,
+		`10.0.0.1 - - [] "GET /a HTTP/1.1" 200 1 "-" "-"`,
+		"10.0.0.1\t - - [10/Aug/2025:12:00:00 +0000] \"GET /a HTTP/1.1\" 200 1 \"-\" \"-\"",
+		`10.0.0.1  - - [10/Aug/2025:12:00:00 +0000] "GET /a HTTP/1.1" 200 1 "-" "-"`,
+		`10.0.0.1 - - [10/Aug/2025:12:00:00 +0000] "GET /a HTTP/1.1" 200 1 "-"`,
+		`10.0.0.1 - - [10/Aug/2025:12:00:00 +0000] "GET /a HTTP/1.1" 200 1 "-" "unterminated`,
+		`10.0.0.1 - - [10/Aug/2025:12:00:00 +0000] "GET /a HTTP/1.1"`,
+		`invalid log line`,
+		``,
+	}
+	commonLines := []string{
+		prefix,
+		prefix + ` 0.005 0.004`,
+		prefix + ` rt=0.005 urt=0.004`,
+		prefix + `5`,
+		prefix + agents,
+		`10.0.0.1 - - [10/Aug/2025:12:00:00 +0000] "POST /a HTTP/1.0" 201 0`,
+		`10.0.0.1 - - [10/Aug/2025:12:00:00 +0000] "POST /a HTTP/1.0" 201`,
+		`10.0.0.1 - - [10/Aug/2025:12:00:00 +0000] "POST /a HTTP/1.0" 2x1 0`,
+	}
+
+	for format, lines := range map[string][]string{"combined": combinedLines, "common": commonLines} {
+		for _, line := range lines {
+			expected, matched := regexAccessLogFields(t, format, line)
+			fields, split := splitAccessLogLine(line, format == "combined")
+			// The splitter accepts the same lines as the regex, with the same fields
+			if split {
+				assert.True(t, matched, "%s: accepted %q", format, line)
+				assert.Equal(t, expected, fields, "%s: %q", format, line)
+			} else {
+				assert.False(t, matched, "%s: rejected %q", format, line)
+			}
+		}
+	}
+
+	fields, split := splitAccessLogLine(prefix+agents+` rt=0.005 urt="0.010, 0.020"`, true)
+	require.True(t, split)
+	assert.Equal(t, accessLogFields{
+		remoteAddr:           "192.168.1.1",
+		timeLocal:            "10/Aug/2025:12:00:00 +0000",
+		method:               "GET",
+		requestURI:           "/api/users/123?active=true",
+		status:               "200",
+		bodyBytes:            "1234",
+		referer:              "http://example.com",
+		userAgent:            "Mozilla/5.0 (X11)",
+		requestTime:          "0.005",
+		upstreamResponseTime: "0.010, 0.020",
+	}, fields)
+}
+
+func TestSplitAccessLogLine_NoAllocations(t *testing.T) {
+	line := `192.168.1.1 - - [10/Aug/2025:12:00:00 +0000] "GET /api/users/123 HTTP/1.1" 200 1234 "-" "curl/7.68.0" 0.005 0.004`
+	allocations := testing.AllocsPerRun(100, func() {
+		if _, ok := splitAccessLogLine(line, true); !ok {
+			t.Fatal("line not split")
+		}
+		if _, ok := parseTimeLocal("10/Aug/2025:12:00:00 +0000"); !ok {
+			t.Fatal("time not parsed")
+		}
+	})
+	assert.Zero(t, allocations)
+}
+
+func TestParseTimeLocal(t *testing.T) {
+	values := []string{
+		"10/Aug/2025:12:00:00 +0000",
+		"01/Jan/2024:00:00:00 -0530",
+		"31/Dec/1999:23:59:59 +1400",
+		"29/Feb/2024:08:30:15 +0200",
+		"29/Feb/2023:08:30:15 +0200",
+		"31/Apr/2025:12:00:00 +0000",
+		"00/Aug/2025:12:00:00 +0000",
+		"10/aug/2025:12:00:00 +0000",
+		"10/Aug/2025:24:00:00 +0000",
+		"10/Aug/2025:12:60:00 +0000",
+		"10/Aug/2025:12:00:00 +0060",
+		"10/Aug/2025:12:00:00 +2500",
+		"10/Aug/2025:12:00:00.123 +0000",
+		"10/Aug/2025:12:00:00 Z",
+		"1/Aug/2025:12:00:00 +0000",
+		"",
+	}
+	for _, value := range values {
+		parsed, ok := parseTimeLocal(value)
+		expected, err := time.Parse(timeLocalLayout, value)
+		// Values the fast path rejects are left to time.Parse, so it must only agree on the ones it accepts
+		if ok {
+			require.NoError(t, err, value)
+			assert.Equal(t, expected.UTC(), parsed, value)
+		}
+	}
+
+	parsed, ok := parseTimeLocal("10/Aug/2025:12:00:00 -0130")
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, time.August, 10, 13, 30, 0, 0, time.UTC), parsed)
+	_, ok = parseTimeLocal("31/Apr/2025:12:00:00 +0000")
+	assert.False(t, ok)
+}
+
+func BenchmarkParseLogLine(b *testing.B) {
+	line := `192.168.1.1 - - [10/Aug/2025:12:00:00 +0000] "GET /api/users/123?active=true HTTP/1.1" 200 1234 "http://example.com" "Mozilla/5.0" 0.005 0.004`
+	for _, format := range []string{"combined", "custom"} {
+		b.Run(format, func(b *testing.B) {
+			options := DefaultIngestOptions()
+			if format == "custom" {
+				// The same format through a custom regex takes the regex path
+				options.CustomRegex = nginxLogFormats["combined"].regex
+			}
+			ingestor := NewNginxAccessIngestor()
+			ingestor.options = options
+			if err := ingestor.setupRegex(); err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := ingestor.parseLogLine(line); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -16,9 +16,11 @@ package traffic
 
 import (
 	"compress/gzip"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -458,31 +460,6 @@ func TestNginxAccessIngestor_createReader_ZstdFile(t *testing.T) {
 	assert.Equal(t, content, string(buf))
 }
 
-func TestNginxAccessIngestor_shouldSkipLine(t *testing.T) {
-	ingestor := NewNginxAccessIngestor()
-	ingestor.options = &IngestOptions{
-		SampleRate: 0.5, // 50% sampling
-	}
-	ingestor.metrics = NewIngestMetrics()
-
-	// Test sampling behavior
-	skippedCount := 0
-	totalTests := 1000
-
-	for i := 0; i < totalTests; i++ {
-		ingestor.metrics.TotalLines = int64(i)
-		if ingestor.shouldSkipLine() {
-			skippedCount++
-		}
-	}
-
-	// With 50% sampling, we expect roughly half to be skipped
-	// Allow some variance due to the simple modulo-based sampling
-	expectedSkipped := totalTests / 2
-	tolerance := totalTests / 10 // 10% tolerance
-	assert.InDelta(t, expectedSkipped, skippedCount, float64(tolerance))
-}
-
 func TestNginxAccessIngestor_isWithinTimeRange(t *testing.T) {
 	ingestor := NewNginxAccessIngestor()
 	
@@ -648,4 +625,85 @@ func TestNginxAccessIngestor_Integration_ProcessFile(t *testing.T) {
 	assert.Equal(t, "/api/users/456", record3.Path)
 	assert.Contains(t, record3.Query, "include")
 	assert.Equal(t, []string{"profile"}, record3.Query["include"])
-}
\ No newline at end of file
+}
+
+func TestNginxAccessIngestor_ProgressCallback(t *testing.T) {
+	ingestor := NewNginxAccessIngestor()
+
+	tmpDir := t.TempDir()
+	logFile := filepath.Join(tmpDir, "access.log")
+	logContent := `192.168.1.1 - - [10/Aug/2025:12:00:00 +0000] "GET /api/users/123 HTTP/1.1" 200 1234 "-" "curl/7.68.0"
+invalid log line
+192.168.1.2 - - [10/Aug/2025:12:01:00 +0000] "POST /api/users HTTP/1.1" 201 567 "-" "curl/7.68.0"
+`
+	require.NoError(t, os.WriteFile(logFile, []byte(logContent), 0644))
+
+	var processedBytes, totalBytes, records int64
+	options := DefaultIngestOptions()
+	options.ProgressCallback = func(processed, total, parsed int64) {
+		processedBytes, totalBytes, records = processed, total, parsed
+	}
+	iterator, err := ingestor.Ingest([]string{logFile}, options)
+	require.NoError(t, err)
+	for iterator.Next() {
+	}
+	require.NoError(t, iterator.Err())
+
+	// The final callback runs after each file is read completely
+	assert.Equal(t, int64(len(logContent)), totalBytes)
+	assert.Equal(t, totalBytes, processedBytes)
+	assert.Equal(t, int64(2), records)
+}
+func TestNginxAccessIngestor_Parallelism(t *testing.T) {
+	tmpDir := t.TempDir()
+	var inputs []string
+	for file := 0; file < 4; file++ {
+		var content strings.Builder
+		for line := 0; line < 50; line++ {
+			content.WriteString(fmt.Sprintf(`10.0.0.%d - - [10/Aug/2025:12:00:%02d +0000] "GET /api/files/%d/lines/%d HTTP/1.1" 200 10 "-" "curl/7.68.0"`+"\n", file, line, file, line))
+		}
+		content.WriteString("invalid log line\n")
+		input := filepath.Join(tmpDir, fmt.Sprintf("access-%d.log", file))
+		require.NoError(t, os.WriteFile(input, []byte(content.String()), 0644))
+		inputs = append(inputs, input)
+	}
+
+	ingestor := NewNginxAccessIngestor()
+	options := DefaultIngestOptions()
+	options.Parallelism = 3
+	var lastRecords int64
+	options.ProgressCallback = func(processed, total, parsed int64) {
+		lastRecords = parsed
+	}
+	iterator, err := ingestor.Ingest(inputs, options)
+	require.NoError(t, err)
+
+	// Records of different files interleave, but each file's records keep their order
+	nextLine := make(map[string]int)
+	for iterator.Next() {
+		record := iterator.Value()
+		parts := strings.Split(record.RawPath, "/")
+		require.Len(t, parts, 6)
+		line, err := strconv.Atoi(parts[5])
+		require.NoError(t, err)
+		assert.Equal(t, nextLine[parts[3]], line, "file %s out of order", parts[3])
+		nextLine[parts[3]] = line + 1
+	}
+	require.NoError(t, iterator.Err())
+	assert.Equal(t, map[string]int{"0": 50, "1": 50, "2": 50, "3": 50}, nextLine)
+
+	metrics := ingestor.Metrics()
+	assert.Equal(t, int64(204), metrics.TotalLines)
+	assert.Equal(t, int64(200), metrics.ParsedLines)
+	assert.Equal(t, int64(4), metrics.ErrorLines)
+	assert.Len(t, metrics.ErrorSamples, 4)
+	assert.Equal(t, int64(200), lastRecords)
+
+	// A failing file stops the run
+	ingestor = NewNginxAccessIngestor()
+	iterator, err = ingestor.Ingest(append(inputs, filepath.Join(tmpDir, "missing.log")), options)
+	require.NoError(t, err)
+	for iterator.Next() {
+	}
+	assert.ErrorContains(t, iterator.Err(), "failed to process file")
+}
@@ -0,0 +1,281 @@
+package traffic
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// logFormatVariable matches a variable of an nginx log_format, "$name" or "${name}"
+var logFormatVariable = regexp.MustCompile(`\$(?:\{(\w+)\}|(\w+))`)
+
+// logFormatNumber matches a duration in seconds as nginx logs it, e.g. 0.005
+const logFormatNumber = `\d+(?:\.\d+)?`
+
+// logFormatPatterns are the patterns of the nginx variables a template can use; other
+// variables match any text up to the next quote inside quotes, or up to whitespace outside
+var logFormatPatterns = map[string]string{
+	"remote_addr":            `\S+`,
+	"realip_remote_addr":     `\S+`,
+	"remote_user":            `\S+`,
+	"time_local":             `[^\]]+`,
+	"time_iso8601":           `\S+`,
+	"msec":                   logFormatNumber,
+	"request":                `[A-Z]+ [^"\s]+(?: [^"\s]+)?`,
+	"request_method":         `[A-Z]+`,
+	"request_uri":            `\S+`,
+	"uri":                    `\S+`,
+	"args":                   `\S*`,
+	"query_string":           `\S*`,
+	"status":                 `\d{3}`,
+	"body_bytes_sent":        `\d+|-`,
+	"bytes_sent":             `\d+|-`,
+	"request_length":         `\d+|-`,
+	"request_time":           logFormatNumber + `|-`,
+	"upstream_response_time": `-|` + logFormatNumber + `(?:(?:, | : )(?:` + logFormatNumber + `|-))*`,
+	"host":                   `\S+`,
+	"server_name":            `\S+`,
+	"scheme":                 `https?`,
+}
+
+// logFormatTemplate parses lines written with an nginx log_format
+type logFormatTemplate struct {
+	template string
+	regex    *regexp.Regexp
+	groups   map[string]int // Submatch index of the first occurrence of each variable
+}
+
+// compileLogFormat compiles a log_format template such as
+// `$remote_addr - $remote_user [$time_local] "$request" $status $request_time` into a parser.
+// The template must log the time, the request and the status.
+func compileLogFormat(template string) (*logFormatTemplate, error) {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	groups := make(map[string]int)
+	group := 0
+	last := 0
+	for _, match := range logFormatVariable.FindAllStringSubmatchIndex(template, -1) {
+		literal := template[last:match[0]]
+		pattern.WriteString(regexp.QuoteMeta(literal))
+
+		var name string
+		if match[2] >= 0 {
+			name = template[match[2]:match[3]] // ${name}
+		} else {
+			name = template[match[4]:match[5]]
+		}
+		variablePattern, known := logFormatPatterns[name]
+		if !known {
+			variablePattern = `\S*`
+			if strings.Count(template[:match[0]], `"`)%2 == 1 {
+				variablePattern = `[^"]*`
+			}
+		}
+		group++
+		pattern.WriteString("(" + variablePattern + ")")
+		if _, seen := groups[name]; !seen {
+			groups[name] = group
+		}
+		last = match[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(template[last:]))
+
+	if group == 0 {
+		return nil, fmt.Errorf("log format %q contains no variables", template)
+	}
+	var missing []string
+	if !hasAnyVariable(groups, "time_local", "time_iso8601", "msec") {
+		missing = append(missing, "$time_local, $time_iso8601 or $msec")
+	}
+	if !hasAnyVariable(groups, "request") && !(hasAnyVariable(groups, "request_method") && hasAnyVariable(groups, "request_uri", "uri")) {
+		missing = append(missing, "$request, or $request_method with $request_uri or $uri")
+	}
+	if !hasAnyVariable(groups, "status") {
+		missing = append(missing, "$status")
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("log format %q must contain %s", template, strings.Join(missing, "; "))
+	}
+
+	regex, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile log format %q: %w", template, err)
+	}
+	return &logFormatTemplate{template: template, regex: regex, groups: groups}, nil
+}
+
+// ValidateNginxLogFormat checks that a log_format template compiles into a parser
+func ValidateNginxLogFormat(template string) error {
+	_, err := compileLogFormat(template)
+	return err
+}
+
+// hasAnyVariable reports whether the template uses one of the variables
+func hasAnyVariable(groups map[string]int, names ...string) bool {
+	for _, name := range names {
+		if _, ok := groups[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parse matches a line and returns the value of each variable, or nil if it does not match
+func (t *logFormatTemplate) parse(line string) map[string]string {
+	matches := t.regex.FindStringSubmatch(line)
+	if matches == nil {
+		return nil
+	}
+	values := make(map[string]string, len(t.groups))
+	for name, index := range t.groups {
+		values[name] = matches[index]
+	}
+	return values
+}
+
+// parseTemplateLine parses a line written with the configured log_format template
+func (n *NginxAccessIngestor) parseTemplateLine(line string) (*NormalizedRecord, error) {
+	values := n.template.parse(line)
+	if values == nil {
+		return nil, fmt.Errorf("line does not match log format %q", n.template.template)
+	}
+
+	timestamp, err := parseLogFormatTime(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse timestamp: %w", err)
+	}
+
+	method, requestURI := values["request_method"], values["request_uri"]
+	if request, ok := values["request"]; ok {
+		parts := strings.Fields(request)
+		method, requestURI = parts[0], parts[1]
+	} else if requestURI == "" {
+		requestURI = values["uri"]
+		if args := firstLogValue(values, "args", "query_string"); args != "" {
+			requestURI += "?" + args
+		}
+	}
+
+	statusCode, err := strconv.Atoi(values["status"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid status code: %w", err)
+	}
+
+	var bodyBytes int64
+	if sent := firstLogValue(values, "body_bytes_sent", "bytes_sent"); sent != "" {
+		if bodyBytes, err = strconv.ParseInt(sent, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid body bytes: %w", err)
+		}
+	}
+
+	// $http_<name> variables log request headers
+	headers := make(map[string]string)
+	for name, value := range values {
+		if header, ok := strings.CutPrefix(name, "http_"); ok && value != "" && value != "-" {
+			headers[strings.ReplaceAll(header, "_", "-")] = value
+		}
+	}
+
+	host := firstLogValue(values, "host", "server_name", "remote_addr", "realip_remote_addr")
+	scheme := firstLogValue(values, "scheme")
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	record := &NormalizedRecord{
+		Method:    strings.ToUpper(method),
+		Path:      NormalizePath(requestURI),
+		RawPath:   requestURI,
+		Status:    statusCode,
+		Timestamp: timestamp,
+		Query:     NormalizeQuery(ExtractQueryString(requestURI)),
+		Headers:   NormalizeHeaders(headers),
+		Host:      host,
+		Scheme:    scheme,
+		BodyBytes: bodyBytes,
+	}
+	if err := setRecordDurations(record, values[requestTimeGroup], values[upstreamResponseTimeGroup]); err != nil {
+		return nil, err
+	}
+	record.Headers, record.Query = ApplyRedactionPolicy(
+		record.Headers,
+		record.Query,
+		n.options.SensitiveKeys,
+		n.options.RedactionPolicy,
+	)
+	return record, nil
+}
+
+// parseLogFormatTime parses whichever time variable the template logs, in UTC
+func parseLogFormatTime(values map[string]string) (time.Time, error) {
+	if value, ok := values["time_local"]; ok {
+		if parsed, ok := parseTimeLocal(value); ok {
+			return parsed, nil
+		}
+		parsed, err := time.Parse(timeLocalLayout, value)
+		return parsed.UTC(), err
+	}
+	if value, ok := values["time_iso8601"]; ok {
+		parsed, err := time.Parse(time.RFC3339, value)
+		return parsed.UTC(), err
+	}
+	seconds, err := strconv.ParseFloat(values["msec"], 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(int64(seconds * 1000)).UTC(), nil
+}
+
+// setRecordDurations sets the durations of a record from the logged $request_time and
+// $upstream_response_time; without a request time the upstream time is the best estimate of
+// the request duration
+func setRecordDurations(record *NormalizedRecord, requestTime, upstreamResponseTime string) error {
+	var err error
+	if record.DurationMs, err = parseLogDuration(requestTime); err != nil {
+		return fmt.Errorf("invalid request time: %w", err)
+	}
+	if record.UpstreamDurationMs, err = parseLogDuration(upstreamResponseTime); err != nil {
+		return fmt.Errorf("invalid upstream response time: %w", err)
+	}
+	if record.DurationMs == 0 {
+		record.DurationMs = record.UpstreamDurationMs
+	}
+	return nil
+}
+
+// namedGroup returns the submatch of a named group, or "" if the regex has no such group
+func namedGroup(regex *regexp.Regexp, matches []string, name string) string {
+	if index := regex.SubexpIndex(name); index > 0 && index < len(matches) {
+		return matches[index]
+	}
+	return ""
+}
+
+// parseLogDuration converts a time logged in seconds to milliseconds. Values of several
+// upstreams ("0.010, 0.020" or "0.010 : 0.020") are summed; "-" or an empty value is 0.
+func parseLogDuration(value string) (float64, error) {
+	var total float64
+	for _, part := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ':' || r == ' ' }) {
+		if part == "-" {
+			continue
+		}
+		seconds, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0, err
+		}
+		total += seconds * 1000
+	}
+	return total, nil
+}
+
+// firstLogValue returns the first of the variables that is logged with a value
+func firstLogValue(values map[string]string, names ...string) string {
+	for _, name := range names {
+		if value := values[name]; value != "" && value != "-" {
+			return value
+		}
+	}
+	return ""
+}
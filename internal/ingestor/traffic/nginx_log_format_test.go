@@ -0,0 +1,198 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traffic
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileLogFormat_Errors(t *testing.T) {
+	testCases := []struct {
+		name      string
+		template  string
+		errorText string
+	}{
+		{name: "no variables", template: "static text", errorText: "contains no variables"},
+		{name: "no time", template: `"$request" $status`, errorText: "must contain $time_local, $time_iso8601 or $msec"},
+		{name: "no request", template: `[$time_local] $status`, errorText: "$request, or $request_method with $request_uri or $uri"},
+		{name: "method without uri", template: `[$time_local] $request_method $status`, errorText: "$request, or $request_method"},
+		{name: "no status", template: `[$time_local] "$request"`, errorText: "must contain $status"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ErrorContains(t, ValidateNginxLogFormat(tc.template), tc.errorText)
+		})
+	}
+}
+
+func TestNginxAccessIngestor_ParseTemplateLine(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template string
+		line     string
+		check    func(t *testing.T, record *NormalizedRecord)
+	}{
+		{
+			name:     "request time",
+			template: `$remote_addr - $remote_user [$time_local] "$request" $status $request_time`,
+			line:     `10.0.0.1 - alice [10/Aug/2025:12:00:00 +0200] "GET /api/users/42?expand=1 HTTP/1.1" 200 0.125`,
+			check: func(t *testing.T, record *NormalizedRecord) {
+				assert.Equal(t, "GET", record.Method)
+				assert.Equal(t, "/api/users/42?expand=1", record.RawPath)
+				assert.Equal(t, []string{"1"}, record.Query["expand"])
+				assert.Equal(t, 200, record.Status)
+				assert.Equal(t, time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC), record.Timestamp)
+				assert.Equal(t, 125.0, record.DurationMs)
+				assert.Equal(t, "10.0.0.1", record.Host)
+			},
+		},
+		{
+			name:     "upstream times of several upstreams",
+			template: `${time_iso8601} $scheme $host "$request_method $uri?$args" $status $body_bytes_sent rt=$request_time urt=$upstream_response_time "$http_user_agent" "$http_x_request_id"`,
+			line:     `2025-08-10T12:00:00Z https api.example.com "POST /orders?dry_run=true" 201 512 rt=0.050 urt=0.010, 0.030 "curl/8.0 (linux)" "abc-123"`,
+			check: func(t *testing.T, record *NormalizedRecord) {
+				assert.Equal(t, "POST", record.Method)
+				assert.Equal(t, "/orders?dry_run=true", record.RawPath)
+				assert.Equal(t, 201, record.Status)
+				assert.Equal(t, int64(512), record.BodyBytes)
+				assert.InDelta(t, 50.0, record.DurationMs, 1e-9)
+				assert.InDelta(t, 40.0, record.UpstreamDurationMs, 1e-9)
+				assert.Equal(t, "https", record.Scheme)
+				assert.Equal(t, "api.example.com", record.Host)
+				assert.Equal(t, []string{"curl/8.0 (linux)"}, record.Headers["user-agent"])
+				assert.Equal(t, []string{"abc-123"}, record.Headers["x-request-id"])
+			},
+		},
+		{
+			name:     "missing upstream and millisecond timestamps",
+			template: `$msec "$request" $status $upstream_response_time`,
+			line:     `1754827200.250 "DELETE /api/items/7 HTTP/2.0" 204 -`,
+			check: func(t *testing.T, record *NormalizedRecord) {
+				assert.Equal(t, "DELETE", record.Method)
+				assert.Equal(t, time.UnixMilli(1754827200250).UTC(), record.Timestamp)
+				assert.Zero(t, record.UpstreamDurationMs)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := DefaultIngestOptions()
+			options.NginxLogFormat = tc.template
+			ingestor := NewNginxAccessIngestor()
+			ingestor.options = options
+			require.NoError(t, ingestor.setupRegex())
+
+			record, err := ingestor.parseLogLine(tc.line)
+			require.NoError(t, err)
+			tc.check(t, record)
+		})
+	}
+}
+
+func TestNginxAccessIngestor_IngestWithLogFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	require.NoError(t, os.WriteFile(path, []byte(
+		`10.0.0.1 [10/Aug/2025:12:00:00 +0000] "GET /api/users/1 HTTP/1.1" 200 0.004`+"\n"+
+			`10.0.0.1 [10/Aug/2025:12:00:01 +0000] "GET /api/users/2 HTTP/1.1" 200 slow`+"\n"), 0644))
+
+	options := DefaultIngestOptions()
+	options.NginxLogFormat = `$remote_addr [$time_local] "$request" $status $request_time`
+	ingestor := NewNginxAccessIngestor()
+	iterator, err := ingestor.Ingest([]string{path}, options)
+	require.NoError(t, err)
+	defer iterator.Close()
+
+	var records []*NormalizedRecord
+	for iterator.Next() {
+		records = append(records, iterator.Value())
+	}
+	require.NoError(t, iterator.Err())
+	require.Len(t, records, 1)
+	assert.Equal(t, 4.0, records[0].DurationMs)
+	assert.Equal(t, int64(1), ingestor.Metrics().ErrorLines)
+
+	options.CustomRegex = `^(\S+)`
+	_, err = NewNginxAccessIngestor().Ingest([]string{path}, options)
+	assert.ErrorContains(t, err, "cannot be used together")
+}
+
+func TestNginxAccessIngestor_parseLogLine_Durations(t *testing.T) {
+	testCases := []struct {
+		name             string
+		logFormat        string
+		customRegex      string
+		line             string
+		expectedDuration float64
+		expectedUpstream float64
+	}{
+		{
+			name:             "combined with request and upstream time",
+			logFormat:        "combined",
+			line:             `192.168.1.1 - - [10/Aug/2025:12:00:00 +0000] "GET /api/users HTTP/1.1" 200 1234 "http://example.com" "Mozilla/5.0" 0.125 "0.100"`,
+			expectedDuration: 125,
+			expectedUpstream: 100,
+		},
+		{
+			name:      "combined without durations",
+			logFormat: "combined",
+			line:      `192.168.1.1 - - [10/Aug/2025:12:00:00 +0000] "GET /api/users HTTP/1.1" 200 1234 "http://example.com" "Mozilla/5.0"`,
+		},
+		{
+			name:             "common with labelled times of several upstreams",
+			logFormat:        "common",
+			line:             `192.168.1.1 - - [10/Aug/2025:12:00:00 +0000] "GET /api/users HTTP/1.1" 200 1234 rt=0.050 urt=0.010, 0.020`,
+			expectedDuration: 50,
+			expectedUpstream: 30,
+		},
+		{
+			name:             "request time not logged",
+			logFormat:        "common",
+			line:             `192.168.1.1 - - [10/Aug/2025:12:00:00 +0000] "GET /api/users HTTP/1.1" 200 1234 - 0.040`,
+			expectedDuration: 40,
+			expectedUpstream: 40,
+		},
+		{
+			name:             "custom regex with named group",
+			customRegex:      `^(\S+) - (\S+) \[([^\]]+)\] "([A-Z]+) ([^"]*) HTTP/[^"]*" (\d+) (\d+) (?P<request_time>[\d.]+)`,
+			line:             `192.168.1.1 - - [10/Aug/2025:12:00:00 +0000] "GET /api/users HTTP/1.1" 200 1234 1.5`,
+			expectedDuration: 1500,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ingestor := NewNginxAccessIngestor()
+			ingestor.options = &IngestOptions{LogFormat: tc.logFormat, CustomRegex: tc.customRegex}
+			require.NoError(t, ingestor.setupRegex())
+
+			record, err := ingestor.parseLogLine(tc.line)
+			require.NoError(t, err)
+			assert.Equal(t, "/api/users", record.Path)
+			assert.InDelta(t, tc.expectedDuration, record.DurationMs, 1e-9)
+			assert.InDelta(t, tc.expectedUpstream, record.UpstreamDurationMs, 1e-9)
+			if tc.logFormat == "combined" {
+				assert.Equal(t, []string{"Mozilla/5.0"}, record.Headers["user-agent"])
+			}
+		})
+	}
+}
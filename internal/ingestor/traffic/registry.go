@@ -0,0 +1,275 @@
+package traffic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/flowspec/flowspec-cli/internal/ingestor"
+)
+
+const (
+	// FormatAuto selects an ingestor per file by probing every registered ingestor
+	FormatAuto = "auto"
+	// DefaultFormat is the ingestor used when no format is given
+	DefaultFormat = "nginx"
+)
+
+// IngestorFactory creates a new, unused ingestor
+type IngestorFactory func() TrafficIngestor
+
+// registeredIngestor is a named ingestor factory; probe is used for detection
+type registeredIngestor struct {
+	name    string
+	factory IngestorFactory
+	probe   TrafficIngestor
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []registeredIngestor
+)
+
+// Register makes an ingestor available under a format name such as "nginx". Detection probes
+// ingestors in registration order. It panics if the name is empty, reserved or taken.
+func Register(name string, factory IngestorFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if name == "" || name == FormatAuto {
+		panic(fmt.Sprintf("traffic: invalid ingestor format name %q", name))
+	}
+	for _, existing := range registry {
+		if existing.name == name {
+			panic(fmt.Sprintf("traffic: ingestor format %q registered twice", name))
+		}
+	}
+	registry = append(registry, registeredIngestor{name: name, factory: factory, probe: factory()})
+}
+
+// Formats returns the registered format names in order
+func Formats() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for _, registered := range registry {
+		names = append(names, registered.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateFormat checks that a format is empty, "auto" or registered
+func ValidateFormat(format string) error {
+	if format == "" || format == FormatAuto {
+		return nil
+	}
+	if _, ok := lookupIngestor(format); !ok {
+		return fmt.Errorf("unsupported traffic format %q, must be one of: %s", format, strings.Join(append([]string{FormatAuto}, Formats()...), ", "))
+	}
+	return nil
+}
+
+// NewIngestor creates the ingestor registered for a format, DefaultFormat when empty; "auto"
+// creates an ingestor that picks one per file
+func NewIngestor(format string) (TrafficIngestor, error) {
+	switch format {
+	case "":
+		format = DefaultFormat
+	case FormatAuto:
+		return NewAutoIngestor(), nil
+	}
+	registered, ok := lookupIngestor(format)
+	if !ok {
+		return nil, ValidateFormat(format)
+	}
+	return registered.factory(), nil
+}
+
+// DetectFormat returns the first registered format whose ingestor supports the file
+func DetectFormat(filePath string) (string, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, registered := range registry {
+		if registered.probe.Supports(filePath) {
+			return registered.name, true
+		}
+	}
+	return "", false
+}
+
+// lookupIngestor returns the registration of a format
+func lookupIngestor(format string) (registeredIngestor, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, registered := range registry {
+		if registered.name == format {
+			return registered, true
+		}
+	}
+	return registeredIngestor{}, false
+}
+
+// AutoIngestor ingests a mix of formats, handing every file to the registered ingestor that
+// detects it. Files are grouped by format and the groups are read one after another, in the
+// order their first file appears in the inputs.
+type AutoIngestor struct {
+	ingestors []TrafficIngestor
+	detected  map[string][]string
+	options   *IngestOptions
+	mu        sync.Mutex
+}
+
+// NewAutoIngestor creates an ingestor that detects the format of each file
+func NewAutoIngestor() *AutoIngestor {
+	return &AutoIngestor{}
+}
+
+// Supports reports whether any registered ingestor supports the file
+func (a *AutoIngestor) Supports(filePath string) bool {
+	_, ok := DetectFormat(filePath)
+	return ok
+}
+
+// Ingest detects the format of every input and returns the records of all of them. It fails
+// before reading anything if a file matches no format.
+func (a *AutoIngestor) Ingest(inputs []string, options *IngestOptions) (ingestor.Iterator[*NormalizedRecord], error) {
+	detected := make(map[string][]string)
+	var order []string
+	for _, input := range inputs {
+		format, ok := DetectFormat(input)
+		if !ok {
+			return nil, fmt.Errorf("cannot detect the traffic format of %s, supported formats: %s", input, strings.Join(Formats(), ", "))
+		}
+		if _, seen := detected[format]; !seen {
+			order = append(order, format)
+		}
+		detected[format] = append(detected[format], input)
+	}
+
+	a.mu.Lock()
+	a.detected = detected
+	a.options = options
+	a.ingestors = nil
+	a.mu.Unlock()
+
+	return &chainIterator{auto: a, formats: order, options: options}, nil
+}
+
+// DetectedFormats returns the input files of the last Ingest call by detected format
+func (a *AutoIngestor) DetectedFormats() map[string][]string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.detected
+}
+
+// Metrics returns the metrics of all files read so far
+func (a *AutoIngestor) Metrics() *IngestMetrics {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	metrics := NewIngestMetrics()
+	maxSamples := DefaultIngestOptions().MaxErrorSamples
+	if a.options != nil {
+		maxSamples = a.options.MaxErrorSamples
+	}
+	for _, trafficIngestor := range a.ingestors {
+		formatMetrics := trafficIngestor.Metrics()
+		metrics.Merge(formatMetrics, maxSamples)
+		metrics.Duration += formatMetrics.Duration
+	}
+	return metrics
+}
+
+// Close closes the ingestors of every format
+func (a *AutoIngestor) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var firstErr error
+	for _, trafficIngestor := range a.ingestors {
+		if err := trafficIngestor.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// start creates the ingestor of a format and starts reading its files
+func (a *AutoIngestor) start(format string, options *IngestOptions) (ingestor.Iterator[*NormalizedRecord], error) {
+	registered, ok := lookupIngestor(format)
+	if !ok {
+		return nil, ValidateFormat(format)
+	}
+	trafficIngestor := registered.factory()
+
+	a.mu.Lock()
+	a.ingestors = append(a.ingestors, trafficIngestor)
+	files := a.detected[format]
+	a.mu.Unlock()
+
+	iterator, err := trafficIngestor.Ingest(files, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ingest %s traffic: %w", format, err)
+	}
+	return iterator, nil
+}
+
+// chainIterator yields the records of each format in turn, starting the ingestor of a format
+// only once the previous one is exhausted
+type chainIterator struct {
+	auto    *AutoIngestor
+	formats []string
+	options *IngestOptions
+	current ingestor.Iterator[*NormalizedRecord]
+	err     error
+}
+
+// Next advances to the next record, moving on to the next format when one is exhausted
+func (c *chainIterator) Next() bool {
+	for c.err == nil {
+		if c.current != nil {
+			if c.current.Next() {
+				return true
+			}
+			c.err = c.current.Err()
+			c.current.Close()
+			c.current = nil
+			continue
+		}
+		if len(c.formats) == 0 {
+			return false
+		}
+		c.current, c.err = c.auto.start(c.formats[0], c.options)
+		c.formats = c.formats[1:]
+	}
+	return false
+}
+
+// Value returns the current record
+func (c *chainIterator) Value() *NormalizedRecord {
+	if c.current == nil {
+		return nil
+	}
+	return c.current.Value()
+}
+
+// Err returns the first error of any format
+func (c *chainIterator) Err() error {
+	return c.err
+}
+
+// Close stops reading
+func (c *chainIterator) Close() error {
+	c.formats = nil
+	if c.current == nil {
+		return nil
+	}
+	err := c.current.Close()
+	c.current = nil
+	return err
+}
@@ -0,0 +1,131 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traffic
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/ingestor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lineIngestor reads files ending in .lines, one "METHOD /path" record per line
+type lineIngestor struct {
+	metrics *IngestMetrics
+}
+
+func (l *lineIngestor) Supports(filePath string) bool {
+	return strings.HasSuffix(filePath, ".lines")
+}
+
+func (l *lineIngestor) Ingest(inputs []string, options *IngestOptions) (ingestor.Iterator[*NormalizedRecord], error) {
+	l.metrics = NewIngestMetrics()
+	var records []*NormalizedRecord
+	for _, input := range inputs {
+		content, err := os.ReadFile(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+			l.metrics.AddTotal()
+			method, path, _ := strings.Cut(line, " ")
+			records = append(records, &NormalizedRecord{Method: method, Path: path, Status: 200})
+			l.metrics.AddParsed()
+		}
+	}
+	return ingestor.NewSliceIterator(records), nil
+}
+
+func (l *lineIngestor) Metrics() *IngestMetrics { return l.metrics }
+
+func (l *lineIngestor) Close() error { return nil }
+
+var registerLineIngestor sync.Once
+
+func registerTestIngestor() {
+	registerLineIngestor.Do(func() {
+		Register("lines", func() TrafficIngestor { return &lineIngestor{metrics: NewIngestMetrics()} })
+	})
+}
+
+func TestRegistry_Formats(t *testing.T) {
+	registerTestIngestor()
+
+	assert.Contains(t, Formats(), DefaultFormat)
+	assert.Contains(t, Formats(), "lines")
+	assert.NoError(t, ValidateFormat(""))
+	assert.NoError(t, ValidateFormat(FormatAuto))
+	assert.NoError(t, ValidateFormat("lines"))
+	assert.ErrorContains(t, ValidateFormat("envoy"), `unsupported traffic format "envoy", must be one of: auto, `)
+
+	defaultIngestor, err := NewIngestor("")
+	require.NoError(t, err)
+	assert.IsType(t, &NginxAccessIngestor{}, defaultIngestor)
+	autoIngestor, err := NewIngestor(FormatAuto)
+	require.NoError(t, err)
+	assert.IsType(t, &AutoIngestor{}, autoIngestor)
+	_, err = NewIngestor("envoy")
+	assert.Error(t, err)
+
+	assert.Panics(t, func() { Register("lines", func() TrafficIngestor { return &lineIngestor{} }) })
+	assert.Panics(t, func() { Register(FormatAuto, func() TrafficIngestor { return &lineIngestor{} }) })
+}
+
+func TestAutoIngestor_MixedFormats(t *testing.T) {
+	registerTestIngestor()
+
+	dir := t.TempDir()
+	lines := filepath.Join(dir, "gateway.lines")
+	require.NoError(t, os.WriteFile(lines, []byte("DELETE /api/orders/1\nPATCH /api/orders/2\n"), 0644))
+	access := filepath.Join(dir, "access.log")
+	require.NoError(t, os.WriteFile(access, []byte(
+		`192.168.1.1 - - [10/Aug/2025:12:00:00 +0000] "GET /api/users/1 HTTP/1.1" 200 12 "-" "curl/8.0"`+"\n"+
+			"not a log line\n"), 0644))
+
+	autoIngestor := NewAutoIngestor()
+	assert.True(t, autoIngestor.Supports(lines))
+	iterator, err := autoIngestor.Ingest([]string{lines, access}, DefaultIngestOptions())
+	require.NoError(t, err)
+	defer autoIngestor.Close()
+
+	var methods []string
+	for iterator.Next() {
+		methods = append(methods, iterator.Value().Method)
+	}
+	require.NoError(t, iterator.Err())
+	require.NoError(t, iterator.Close())
+
+	assert.Equal(t, []string{"DELETE", "PATCH", "GET"}, methods, "formats are read in the order their first file appears")
+	assert.Equal(t, map[string][]string{"lines": {lines}, DefaultFormat: {access}}, autoIngestor.DetectedFormats())
+
+	metrics := autoIngestor.Metrics()
+	assert.Equal(t, int64(4), metrics.TotalLines)
+	assert.Equal(t, int64(3), metrics.ParsedLines)
+	assert.Equal(t, int64(1), metrics.ErrorLines)
+}
+
+func TestAutoIngestor_UndetectedFile(t *testing.T) {
+	dir := t.TempDir()
+	unknown := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(unknown, []byte("hello\n"), 0644))
+
+	_, err := NewAutoIngestor().Ingest([]string{unknown}, nil)
+	assert.ErrorContains(t, err, "cannot detect the traffic format of "+unknown)
+}
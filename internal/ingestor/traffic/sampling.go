@@ -0,0 +1,52 @@
+package traffic
+
+import (
+	"hash/fnv"
+	"io"
+	"strings"
+)
+
+// endpointSampler samples records per endpoint, by method and path shape: every endpoint keeps
+// the same share of its records, evenly spaced, and its first record is always kept, so a
+// burst of one endpoint neither crowds out nor hides low-traffic endpoints
+type endpointSampler struct {
+	rate   float64
+	counts map[uint64]int64 // Records seen per endpoint hash
+}
+
+// newEndpointSampler creates a sampler keeping rate of the records of every endpoint, or nil
+// when the rate keeps everything
+func newEndpointSampler(rate float64) *endpointSampler {
+	if rate <= 0 || rate >= 1 {
+		return nil
+	}
+	return &endpointSampler{rate: rate, counts: make(map[uint64]int64)}
+}
+
+// keep reports whether a record is sampled: the nth record of an endpoint is kept whenever
+// n*rate reaches the next whole number
+func (s *endpointSampler) keep(record *NormalizedRecord) bool {
+	key := endpointHash(record.Method, record.Path)
+	seen := s.counts[key]
+	s.counts[key] = seen + 1
+	if seen == 0 {
+		return true
+	}
+	return int64(float64(seen)*s.rate) > int64(float64(seen-1)*s.rate)
+}
+
+// endpointHash hashes a method with the shape of a path, in which segments containing digits
+// (IDs, UUIDs, hashes) are all alike, so /users/1 and /users/2 are the same endpoint
+func endpointHash(method, path string) uint64 {
+	hash := fnv.New64a()
+	io.WriteString(hash, method)
+	for _, segment := range strings.Split(path, "/") {
+		io.WriteString(hash, "/")
+		if strings.ContainsAny(segment, "0123456789") {
+			io.WriteString(hash, "{}")
+		} else {
+			io.WriteString(hash, segment)
+		}
+	}
+	return hash.Sum64()
+}
@@ -0,0 +1,95 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traffic
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEndpointSampler(t *testing.T) {
+	assert.Nil(t, newEndpointSampler(1.0))
+	assert.Nil(t, newEndpointSampler(0), "an unset rate keeps everything")
+	assert.NotNil(t, newEndpointSampler(0.5))
+}
+
+func TestEndpointSampler_Keep(t *testing.T) {
+	sampler := newEndpointSampler(0.1)
+	kept := make(map[string]int)
+	// A burst of one endpoint interleaved with a rare one
+	for i := 0; i < 1000; i++ {
+		record := &NormalizedRecord{Method: "GET", Path: fmt.Sprintf("/api/users/%d", i)}
+		if sampler.keep(record) {
+			kept["users"]++
+		}
+		if i%200 == 0 {
+			if sampler.keep(&NormalizedRecord{Method: "POST", Path: "/api/orders"}) {
+				kept["orders"]++
+			}
+		}
+	}
+	if sampler.keep(&NormalizedRecord{Method: "GET", Path: "/health"}) {
+		kept["health"]++
+	}
+
+	assert.Equal(t, 100, kept["users"])
+	assert.Equal(t, 1, kept["orders"], "the first record of an endpoint is always kept")
+	assert.Equal(t, 1, kept["health"])
+
+	alternate := newEndpointSampler(0.5)
+	var pattern []bool
+	for i := 0; i < 6; i++ {
+		pattern = append(pattern, alternate.keep(&NormalizedRecord{Method: "GET", Path: "/items"}))
+	}
+	assert.Equal(t, []bool{true, false, true, false, true, false}, pattern)
+}
+
+func TestEndpointHash(t *testing.T) {
+	assert.Equal(t, endpointHash("GET", "/users/1"), endpointHash("GET", "/users/4f1c-9a2b"))
+	assert.Equal(t, endpointHash("GET", "/users/1/orders/7"), endpointHash("GET", "/users/2/orders/8"))
+	assert.NotEqual(t, endpointHash("GET", "/users/1"), endpointHash("POST", "/users/1"))
+	assert.NotEqual(t, endpointHash("GET", "/users/1"), endpointHash("GET", "/orders/1"))
+	assert.NotEqual(t, endpointHash("GET", "/users/me"), endpointHash("GET", "/users/1"))
+}
+
+func TestNginxAccessIngestor_SamplingKeepsRareEndpoints(t *testing.T) {
+	lines := commonLines(500)
+	for _, path := range []string{"/api/orders", "/api/invoices", "/health"} {
+		lines = append(lines, fmt.Sprintf(`10.0.0.1 - - [10/Aug/2025:12:00:00 +0000] "GET %s HTTP/1.1" 200 512`, path))
+	}
+	path := writeLogFile(t, t.TempDir(), "access.log", lines)
+
+	options := DefaultIngestOptions()
+	options.LogFormat = "common"
+	options.SampleRate = 0.05
+	trafficIngestor := NewNginxAccessIngestor()
+
+	records, err := drain(t, trafficIngestor, []string{path}, options)
+	require.NoError(t, err)
+
+	paths := make(map[string]int)
+	for _, record := range records {
+		if record.Path == "/api/orders" || record.Path == "/api/invoices" || record.Path == "/health" {
+			paths[record.Path]++
+		} else {
+			paths["users"]++
+		}
+	}
+	assert.Equal(t, map[string]int{"users": 25, "/api/orders": 1, "/api/invoices": 1, "/health": 1}, paths)
+	assert.Equal(t, int64(475), trafficIngestor.Metrics().Files[path].SkippedLines)
+}
@@ -0,0 +1,440 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/diegoholiveira/jsonlogic/v3"
+	"github.com/flowspec/flowspec-cli/internal/diff"
+	"github.com/flowspec/flowspec-cli/internal/engine"
+	"github.com/flowspec/flowspec-cli/internal/ingestor"
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/parser"
+	"github.com/flowspec/flowspec-cli/internal/plugin"
+)
+
+// Severity indicates how serious a lint issue is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Issue describes a single problem found while linting contracts or traces
+type Issue struct {
+	Severity Severity `json:"severity"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Spec     string   `json:"spec,omitempty"` // Spec operation ID or "name-version" for YAML specs
+	Message  string   `json:"message"`
+}
+
+// String returns a compiler-style representation of the issue
+func (i Issue) String() string {
+	var location strings.Builder
+	if i.File != "" {
+		location.WriteString(i.File)
+		if i.Line > 0 {
+			location.WriteString(fmt.Sprintf(":%d", i.Line))
+			if i.Column > 0 {
+				location.WriteString(fmt.Sprintf(":%d", i.Column))
+			}
+		}
+		location.WriteString(": ")
+	}
+	if i.Spec != "" {
+		location.WriteString(fmt.Sprintf("[%s] ", i.Spec))
+	}
+	return fmt.Sprintf("%s%s: %s", location.String(), i.Severity, i.Message)
+}
+
+// Result contains the outcome of a lint run
+type Result struct {
+	Issues       []Issue `json:"issues"`
+	SpecsChecked int     `json:"specsChecked"`
+	SpansSampled int     `json:"spansSampled"`
+}
+
+// HasErrors returns true if any issue has error severity
+func (r *Result) HasErrors() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of issues with the given severity
+func (r *Result) Count(severity Severity) int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// LinterConfig holds configuration for the linter
+type LinterConfig struct {
+	MaxSampleSpans int                    // Maximum spans inspected per spec when checking variables
+	Variables      map[string]interface{} // External variables available to assertions as vars.*
+	Against        string                 // Baseline contracts; changes from them are checked with Policy
+	Policy         *diff.Policy           // Breaking-change policy used with Against; the default policy when nil
+}
+
+// DefaultLinterConfig returns a default linter configuration
+func DefaultLinterConfig() *LinterConfig {
+	return &LinterConfig{
+		MaxSampleSpans: 100,
+	}
+}
+
+// Linter validates contracts and traces without running alignment
+type Linter struct {
+	config     *LinterConfig
+	specParser parser.SpecParser
+	ingestor   ingestor.TraceIngestor
+	evaluator  *engine.JSONLogicEvaluator
+}
+
+// NewLinter creates a new linter with default configuration
+func NewLinter() *Linter {
+	return NewLinterWithConfig(DefaultLinterConfig())
+}
+
+// NewLinterWithConfig creates a new linter with custom configuration
+func NewLinterWithConfig(config *LinterConfig) *Linter {
+	return &Linter{
+		config:     config,
+		specParser: parser.NewSpecParser(),
+		ingestor:   ingestor.NewTraceIngestor(),
+		evaluator:  engine.NewJSONLogicEvaluator(),
+	}
+}
+
+// Lint parses the contracts at specPath and, if tracePath is not empty, the trace file,
+// and reports problems found in either. The returned error is reserved for failures
+// that prevent linting altogether.
+func (l *Linter) Lint(specPath, tracePath string) (*Result, error) {
+	result := &Result{Issues: []Issue{}}
+
+	parseResult, err := l.specParser.ParseFromSource(specPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse contracts from %s: %w", specPath, err)
+	}
+
+	for _, parseErr := range parseResult.Errors {
+		result.Issues = append(result.Issues, Issue{
+			Severity: SeverityError,
+			File:     parseErr.File,
+			Line:     parseErr.Line,
+			Column:   parseErr.Column,
+			Message:  parseErr.Message,
+		})
+	}
+
+	var traceData *models.TraceData
+	if tracePath != "" {
+		traceData, err = l.ingestor.IngestFromFile(tracePath)
+		if err != nil {
+			result.Issues = append(result.Issues, Issue{
+				Severity: SeverityError,
+				File:     tracePath,
+				Message:  fmt.Sprintf("trace file is invalid: %v", err),
+			})
+			traceData = nil
+		} else if len(traceData.Spans) == 0 {
+			result.Issues = append(result.Issues, Issue{
+				Severity: SeverityWarning,
+				File:     tracePath,
+				Message:  "trace file contains no spans",
+			})
+		}
+	}
+
+	specResult := l.LintSpecs(parseResult.Specs, traceData)
+	result.Issues = append(result.Issues, specResult.Issues...)
+	result.SpecsChecked = specResult.SpecsChecked
+	result.SpansSampled = specResult.SpansSampled
+
+	if l.config.Against != "" {
+		baseline, err := diff.LoadSpecs(l.config.Against)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load baseline contracts: %w", err)
+		}
+		result.Issues = append(result.Issues, l.checkPolicy(baseline, parseResult.Specs)...)
+	}
+
+	return result, nil
+}
+
+// checkPolicy reports changes from the baseline contracts that violate the breaking-change policy
+func (l *Linter) checkPolicy(baseline, specs []models.ServiceSpec) []Issue {
+	var issues []Issue
+	diffResult := diff.CompareSpecsWithPolicy(baseline, specs, l.config.Policy)
+	for _, violation := range diffResult.Violations {
+		severity := SeverityWarning
+		if violation.Severity == diff.SeverityBreaking {
+			severity = SeverityError
+		}
+
+		change := violation.Change
+		location := strings.TrimSpace(change.Method + " " + change.Path)
+		if location != "" {
+			location += ": "
+		}
+		issues = append(issues, Issue{
+			Severity: severity,
+			Spec:     change.Service,
+			Message:  fmt.Sprintf("%s change against %s (%s): %s%s", violation.Severity, l.config.Against, violation.Rule, location, change.Message),
+		})
+	}
+	return issues
+}
+
+// lintGuards reports the when guards of a YAML spec that do not compile
+func (l *Linter) lintGuards(spec models.ServiceSpec, specID string) []Issue {
+	var issues []Issue
+	for _, endpoint := range spec.Spec.Endpoints {
+		for _, operation := range endpoint.Operations {
+			guards := []struct {
+				kind  string
+				guard map[string]interface{}
+			}{
+				{"when guard", operation.When},
+				{"responses when guard", operation.Responses.When},
+				{"required when guard", operation.Required.When},
+			}
+			if operation.Request != nil {
+				guards = append(guards, struct {
+					kind  string
+					guard map[string]interface{}
+				}{"request when guard", operation.Request.When})
+			}
+
+			file, line := operation.SourceFile, operation.LineNumber
+			if file == "" {
+				file, line = spec.SourceFile, spec.LineNumber
+			}
+			for _, entry := range guards {
+				if len(entry.guard) == 0 {
+					continue
+				}
+				if err := l.compileGuard(entry.guard); err != nil {
+					issues = append(issues, Issue{
+						Severity: SeverityError,
+						File:     file,
+						Line:     line,
+						Spec:     specID,
+						Message:  fmt.Sprintf("%s %s %s does not compile: %v", operation.Method, endpoint.Path, entry.kind, err),
+					})
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// compileGuard validates a when guard, including the unknown operators JSONLogic would
+// silently treat as data; plugin operators are left to the plugins that handle them
+func (l *Linter) compileGuard(guard map[string]interface{}) error {
+	if err := l.evaluator.ValidateAssertion(guard); err != nil {
+		return err
+	}
+	return checkOperators(guard)
+}
+
+// checkOperators reports the first operator of an expression that JSONLogic does not know
+func checkOperators(expression interface{}) error {
+	switch value := expression.(type) {
+	case map[string]interface{}:
+		for operator, arguments := range value {
+			known := operator == "var" || operator == "match" ||
+				strings.HasPrefix(operator, plugin.OperatorPrefix) ||
+				jsonlogic.ValidateJsonLogic(map[string]interface{}{operator: []interface{}{}})
+			if len(value) == 1 && !known {
+				return fmt.Errorf("unknown operator %q", operator)
+			}
+			if err := checkOperators(arguments); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if err := checkOperators(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// LintSpecs validates already parsed specs; traceData is optional and, when given,
+// is used to check that referenced variables exist in sample spans
+func (l *Linter) LintSpecs(specs []models.ServiceSpec, traceData *models.TraceData) *Result {
+	result := &Result{Issues: []Issue{}}
+	sampledSpans := make(map[string]bool)
+
+	alignmentEngineConfig := engine.DefaultEngineConfig()
+	alignmentEngineConfig.Variables = l.config.Variables
+	alignmentEngine := engine.NewAlignmentEngineWithConfig(alignmentEngineConfig)
+
+	for _, spec := range specs {
+		result.SpecsChecked++
+		specID := specIdentifier(spec)
+
+		newIssue := func(severity Severity, message string) Issue {
+			return Issue{
+				Severity: severity,
+				File:     spec.SourceFile,
+				Line:     spec.LineNumber,
+				Spec:     specID,
+				Message:  message,
+			}
+		}
+
+		if err := spec.Validate(); err != nil {
+			result.Issues = append(result.Issues, newIssue(SeverityError, err.Error()))
+			continue
+		}
+
+		// The only expressions of YAML specs are the when guards of their operations
+		if spec.IsYAMLFormat() {
+			result.Issues = append(result.Issues, l.lintGuards(spec, specID)...)
+			continue
+		}
+
+		assertions := []struct {
+			kind      string
+			assertion map[string]interface{}
+		}{
+			{"precondition", spec.Preconditions},
+			{"postcondition", spec.Postconditions},
+		}
+
+		var samples []map[string]interface{}
+		if traceData != nil && len(traceData.Spans) > 0 {
+			samples = l.sampleEvaluationData(alignmentEngine, spec, traceData, sampledSpans)
+		}
+
+		for _, entry := range assertions {
+			if len(entry.assertion) == 0 {
+				continue
+			}
+
+			if err := l.evaluator.ValidateAssertion(entry.assertion); err != nil {
+				result.Issues = append(result.Issues, newIssue(SeverityError,
+					fmt.Sprintf("%s does not compile: %v", entry.kind, err)))
+				continue
+			}
+
+			for _, variable := range l.evaluator.ReferencedVariables(entry.assertion) {
+				if issue, ok := l.checkVariable(variable, samples); !ok {
+					result.Issues = append(result.Issues, newIssue(SeverityWarning,
+						fmt.Sprintf("%s references %s", entry.kind, issue)))
+				}
+			}
+		}
+	}
+
+	result.SpansSampled = len(sampledSpans)
+	sortIssues(result.Issues)
+	return result
+}
+
+// sampleEvaluationData builds evaluation data for the spans a spec would be checked against,
+// falling back to all spans when none match so that variable checks still have data
+func (l *Linter) sampleEvaluationData(
+	alignmentEngine *engine.DefaultAlignmentEngine,
+	spec models.ServiceSpec,
+	traceData *models.TraceData,
+	sampledSpans map[string]bool,
+) []map[string]interface{} {
+	spans, err := engine.NewSpecMatcher().FindMatchingSpans(spec, traceData)
+	if err != nil || len(spans) == 0 {
+		spans = make([]*models.Span, 0, len(traceData.Spans))
+		for _, span := range traceData.Spans {
+			spans = append(spans, span)
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].SpanID < spans[j].SpanID })
+	if l.config.MaxSampleSpans > 0 && len(spans) > l.config.MaxSampleSpans {
+		spans = spans[:l.config.MaxSampleSpans]
+	}
+
+	samples := make([]map[string]interface{}, 0, len(spans))
+	for _, span := range spans {
+		context := alignmentEngine.NewSpanEvaluationContext(span, traceData)
+		data, err := l.evaluator.BuildEvaluationData(context)
+		if err != nil {
+			continue
+		}
+		samples = append(samples, data)
+		sampledSpans[span.SpanID] = true
+	}
+	return samples
+}
+
+// checkVariable reports whether a variable resolves in external variables or any sample,
+// returning a description of the problem when it does not
+func (l *Linter) checkVariable(variable string, samples []map[string]interface{}) (string, bool) {
+	if strings.HasPrefix(variable, engine.VariablePrefix) {
+		if _, ok := engine.ResolveVariable(l.config.Variables, strings.TrimPrefix(variable, engine.VariablePrefix)); ok {
+			return "", true
+		}
+		return fmt.Sprintf("external variable %q which is not defined (use --var or --var-file)", variable), false
+	}
+
+	// Without trace data there is nothing to check against
+	if samples == nil {
+		return "", true
+	}
+
+	for _, data := range samples {
+		if _, ok := engine.ResolveVariable(data, variable); ok {
+			return "", true
+		}
+	}
+	return fmt.Sprintf("variable %q which is not present in any of %d sampled spans", variable, len(samples)), false
+}
+
+// specIdentifier returns a human readable identifier for a spec
+func specIdentifier(spec models.ServiceSpec) string {
+	if spec.IsYAMLFormat() {
+		return fmt.Sprintf("%s-%s", spec.Metadata.Name, spec.Metadata.Version)
+	}
+	return spec.OperationID
+}
+
+// sortIssues orders issues by file, line and message for stable output
+func sortIssues(issues []Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
+		}
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Message < issues[j].Message
+	})
+}
@@ -0,0 +1,274 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLintTraceData() *models.TraceData {
+	span := &models.Span{
+		SpanID:  "span1",
+		TraceID: "trace1",
+		Name:    "createUser",
+		Attributes: map[string]interface{}{
+			"operation.id":     "createUser",
+			"http.method":      "POST",
+			"http.status_code": 201,
+		},
+	}
+	return &models.TraceData{
+		TraceID:  "trace1",
+		RootSpan: span,
+		Spans:    map[string]*models.Span{"span1": span},
+	}
+}
+
+func TestLintSpecs(t *testing.T) {
+	testCases := []struct {
+		name             string
+		spec             models.ServiceSpec
+		variables        map[string]interface{}
+		expectedSeverity Severity
+		expectedMessage  string
+	}{
+		{
+			name: "valid spec",
+			spec: models.ServiceSpec{
+				OperationID: "createUser",
+				Description: "Create a user",
+				SourceFile:  "UserController.java",
+				LineNumber:  12,
+				Preconditions: map[string]interface{}{
+					"==": []interface{}{map[string]interface{}{"var": "http.method"}, "POST"},
+				},
+				Postconditions: map[string]interface{}{
+					"==": []interface{}{map[string]interface{}{"var": "span.attributes.http.status_code"}, 201},
+				},
+			},
+		},
+		{
+			name: "unknown span variable",
+			spec: models.ServiceSpec{
+				OperationID: "createUser",
+				Description: "Create a user",
+				SourceFile:  "UserController.java",
+				LineNumber:  12,
+				Postconditions: map[string]interface{}{
+					"==": []interface{}{map[string]interface{}{"var": "response.body.id"}, "42"},
+				},
+			},
+			expectedSeverity: SeverityWarning,
+			expectedMessage:  `postcondition references variable "response.body.id"`,
+		},
+		{
+			name: "variable with default is not reported",
+			spec: models.ServiceSpec{
+				OperationID: "createUser",
+				Description: "Create a user",
+				SourceFile:  "UserController.java",
+				LineNumber:  12,
+				Postconditions: map[string]interface{}{
+					"==": []interface{}{map[string]interface{}{"var": []interface{}{"response.body.id", "42"}}, "42"},
+				},
+			},
+		},
+		{
+			name: "undefined external variable",
+			spec: models.ServiceSpec{
+				OperationID: "createUser",
+				Description: "Create a user",
+				SourceFile:  "UserController.java",
+				LineNumber:  12,
+				Preconditions: map[string]interface{}{
+					"==": []interface{}{map[string]interface{}{"var": "vars.region"}, "us-east-1"},
+				},
+			},
+			expectedSeverity: SeverityWarning,
+			expectedMessage:  `external variable "vars.region"`,
+		},
+		{
+			name: "defined external variable",
+			spec: models.ServiceSpec{
+				OperationID: "createUser",
+				Description: "Create a user",
+				SourceFile:  "UserController.java",
+				LineNumber:  12,
+				Preconditions: map[string]interface{}{
+					"==": []interface{}{map[string]interface{}{"var": "vars.region"}, "us-east-1"},
+				},
+			},
+			variables: map[string]interface{}{"region": "us-east-1"},
+		},
+		{
+			name: "invalid structure",
+			spec: models.ServiceSpec{
+				Description: "missing operation id",
+			},
+			expectedSeverity: SeverityError,
+			expectedMessage:  "ServiceSpec must be either YAML format",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := DefaultLinterConfig()
+			config.Variables = tc.variables
+			linter := NewLinterWithConfig(config)
+
+			result := linter.LintSpecs([]models.ServiceSpec{tc.spec}, newLintTraceData())
+			assert.Equal(t, 1, result.SpecsChecked)
+
+			if tc.expectedMessage == "" {
+				assert.Empty(t, result.Issues)
+				return
+			}
+			require.Len(t, result.Issues, 1)
+			assert.Equal(t, tc.expectedSeverity, result.Issues[0].Severity)
+			assert.Contains(t, result.Issues[0].Message, tc.expectedMessage)
+			assert.Equal(t, tc.expectedSeverity == SeverityError, result.HasErrors())
+		})
+	}
+}
+
+func TestLintSpecs_WithoutTrace(t *testing.T) {
+	spec := models.ServiceSpec{
+		OperationID: "createUser",
+		Description: "Create a user",
+		SourceFile:  "UserController.java",
+		LineNumber:  12,
+		Postconditions: map[string]interface{}{
+			"==": []interface{}{map[string]interface{}{"var": "response.body.id"}, "42"},
+		},
+	}
+
+	result := NewLinter().LintSpecs([]models.ServiceSpec{spec}, nil)
+	assert.Empty(t, result.Issues, "span variables cannot be checked without a trace")
+	assert.Equal(t, 0, result.SpansSampled)
+}
+
+func TestLint_Files(t *testing.T) {
+	linter := NewLinter()
+
+	result, err := linter.Lint(filepath.Join("..", "..", "examples", "service-spec.yaml"),
+		filepath.Join("..", "..", "examples", "yaml-contracts", "test-traces", "user-service-trace.json"))
+	require.NoError(t, err)
+	assert.False(t, result.HasErrors(), "unexpected issues: %v", result.Issues)
+	assert.Equal(t, 1, result.SpecsChecked)
+
+	dir := t.TempDir()
+	invalidTrace := filepath.Join(dir, "trace.json")
+	require.NoError(t, os.WriteFile(invalidTrace, []byte("{not json"), 0644))
+
+	result, err = linter.Lint(filepath.Join("..", "..", "examples", "service-spec.yaml"), invalidTrace)
+	require.NoError(t, err)
+	assert.True(t, result.HasErrors())
+	assert.Equal(t, 1, result.Count(SeverityError))
+	assert.Contains(t, result.Issues[0].String(), "trace.json: error: trace file is invalid")
+}
+
+func TestLint_Against(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, statusCodes string) string {
+		path := filepath.Join(dir, name)
+		content := `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: user-service
+  version: v1.0.0
+spec:
+  endpoints:
+    - path: /users
+      operations:
+        - method: GET
+          responses:
+            statusCodes: ` + statusCodes + `
+`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	baseline := write("old.yaml", "[200, 404]")
+	current := write("new.yaml", "[200]")
+
+	config := DefaultLinterConfig()
+	config.Against = baseline
+	result, err := NewLinterWithConfig(config).Lint(current, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Count(SeverityError))
+	assert.Equal(t, "user-service", result.Issues[0].Spec)
+	assert.Contains(t, result.Issues[0].Message, "breaking change against "+baseline+" (removed-status-code): GET /users: status code 404 removed")
+
+	config.Against = filepath.Join(dir, "missing.yaml")
+	_, err = NewLinterWithConfig(config).Lint(current, "")
+	assert.Error(t, err)
+}
+
+func TestIssue_String(t *testing.T) {
+	issue := Issue{Severity: SeverityError, File: "spec.yaml", Line: 3, Column: 5, Spec: "user-service-v1", Message: "bad"}
+	assert.Equal(t, "spec.yaml:3:5: [user-service-v1] error: bad", issue.String())
+
+	issue = Issue{Severity: SeverityWarning, Message: "no location"}
+	assert.Equal(t, "warning: no location", issue.String())
+}
+
+func TestLintSpecs_Guards(t *testing.T) {
+	newSpec := func(operation models.OperationSpec) models.ServiceSpec {
+		return models.ServiceSpec{
+			APIVersion: "flowspec/v1alpha1",
+			Kind:       "ServiceSpec",
+			Metadata:   &models.ServiceSpecMetadata{Name: "users", Version: "v1"},
+			Spec: &models.ServiceSpecDefinition{
+				Endpoints: []models.EndpointSpec{{
+					Path:       "/users",
+					Operations: []models.OperationSpec{operation},
+				}},
+			},
+			SourceFile: "users.yaml",
+		}
+	}
+	validGuard := map[string]interface{}{
+		"==": []interface{}{map[string]interface{}{"var": "span.attributes.tenant"}, "acme"},
+	}
+	badGuard := map[string]interface{}{
+		"no-such-operator": []interface{}{map[string]interface{}{"var": "span.attributes.tenant"}, "acme"},
+	}
+
+	valid := models.OperationSpec{
+		Method:    "GET",
+		Responses: models.ResponseSpec{StatusCodes: []int{200}, When: validGuard},
+		When:      validGuard,
+	}
+	result := NewLinter().LintSpecs([]models.ServiceSpec{newSpec(valid)}, nil)
+	assert.Empty(t, result.Issues)
+
+	bad := models.OperationSpec{
+		Method:     "GET",
+		Responses:  models.ResponseSpec{StatusCodes: []int{200}},
+		Required:   models.RequiredFieldsSpec{When: badGuard},
+		LineNumber: 9,
+	}
+	result = NewLinter().LintSpecs([]models.ServiceSpec{newSpec(bad)}, nil)
+	require.Len(t, result.Issues, 1)
+	assert.Equal(t, SeverityError, result.Issues[0].Severity)
+	assert.Equal(t, "users.yaml", result.Issues[0].File)
+	assert.Contains(t, result.Issues[0].Message, "GET /users required when guard does not compile")
+	assert.True(t, result.HasErrors())
+}
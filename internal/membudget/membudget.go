@@ -0,0 +1,127 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package membudget enforces the --max-memory-mb budget. The budget is applied as the Go
+// runtime's soft memory limit, so the garbage collector works harder as the heap approaches it,
+// and long-running stages check it between units of work to fail with a clear error instead of
+// being killed by the runner's OOM killer. Nothing is spilled to disk or approximated: a run
+// whose live data does not fit fails rather than degrading.
+package membudget
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+)
+
+// ErrExceeded matches every budget error
+var ErrExceeded = errors.New("memory budget exceeded")
+
+// highWaterPercent is the share of the budget above which a check forces a collection first
+const highWaterPercent = 90
+
+// heapObjectsMetric is the runtime metric read by checks. Unlike runtime.ReadMemStats, reading it
+// does not stop the world, so checking once per unit of work stays cheap under many workers.
+const heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+
+// Error reports the stage that could not stay within the budget
+type Error struct {
+	Stage   string
+	HeapMB  int64
+	LimitMB int64
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("memory budget exceeded while %s: the heap needs %d MB but --max-memory-mb is %d; "+
+		"raise the budget, lower --max-workers, or reduce the input (e.g. --sample-rate or a shorter --since/--until window)",
+		e.Stage, e.HeapMB, e.LimitMB)
+}
+
+// Is makes errors.Is(err, ErrExceeded) hold
+func (e *Error) Is(target error) bool {
+	return target == ErrExceeded
+}
+
+// Budget is a heap budget. A nil Budget is unlimited, so callers can pass it unconditionally.
+type Budget struct {
+	limitMB  int64
+	readHeap func() uint64
+	collect  func()
+}
+
+// New creates a budget of maxMB megabytes, or returns nil (unlimited) when maxMB is not positive
+func New(maxMB int64) *Budget {
+	if maxMB <= 0 {
+		return nil
+	}
+	return &Budget{limitMB: maxMB, readHeap: heapInUse, collect: runtime.GC}
+}
+
+// LimitMB returns the budget in megabytes, 0 when unlimited
+func (b *Budget) LimitMB() int64 {
+	if b == nil {
+		return 0
+	}
+	return b.limitMB
+}
+
+// Apply sets the runtime soft memory limit to the budget and returns a function restoring the
+// previous limit
+func (b *Budget) Apply() func() {
+	if b == nil {
+		return func() {}
+	}
+	previous := debug.SetMemoryLimit(b.limitBytes())
+	return func() { debug.SetMemoryLimit(previous) }
+}
+
+// Check returns an *Error when the heap is over the budget. Above the high-water mark it first
+// forces a collection, which holds the caller back until garbage is reclaimed.
+func (b *Budget) Check(stage string) error {
+	if b == nil {
+		return nil
+	}
+	limit := uint64(b.limitBytes())
+	heap := b.readHeap()
+	if heap < limit/100*highWaterPercent {
+		return nil
+	}
+	b.collect()
+	if heap = b.readHeap(); heap <= limit {
+		return nil
+	}
+	return &Error{Stage: stage, HeapMB: int64(heap >> 20), LimitMB: b.limitMB}
+}
+
+func (b *Budget) limitBytes() int64 {
+	if b.limitMB > math.MaxInt64>>20 {
+		return math.MaxInt64
+	}
+	return b.limitMB << 20
+}
+
+// heapInUse returns the bytes of heap memory held by live and not yet collected objects
+func heapInUse() uint64 {
+	sample := []metrics.Sample{{Name: heapObjectsMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		return stats.HeapAlloc
+	}
+	return sample[0].Value.Uint64()
+}
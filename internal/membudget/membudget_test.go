@@ -0,0 +1,69 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package membudget
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudget_Check(t *testing.T) {
+	testCases := []struct {
+		name        string
+		before      uint64 // Heap before a forced collection
+		after       uint64 // Heap after it
+		collections int
+		exceeded    bool
+	}{
+		{"below high water", 50 << 20, 50 << 20, 0, false},
+		{"reclaimed by collection", 95 << 20, 60 << 20, 1, false},
+		{"over budget", 140 << 20, 130 << 20, 1, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			budget := New(100)
+			heap, collections := tc.before, 0
+			budget.readHeap = func() uint64 { return heap }
+			budget.collect = func() { collections++; heap = tc.after }
+
+			err := budget.Check("indexing spans")
+			assert.Equal(t, tc.collections, collections)
+			if !tc.exceeded {
+				assert.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrExceeded)
+			assert.Contains(t, err.Error(), "while indexing spans: the heap needs 130 MB but --max-memory-mb is 100")
+		})
+	}
+}
+
+func TestBudget_Unlimited(t *testing.T) {
+	var budget *Budget
+	assert.Nil(t, New(0))
+	assert.NoError(t, budget.Check("anything"))
+	assert.Equal(t, int64(0), budget.LimitMB())
+	budget.Apply()()
+}
+
+func TestHeapInUse(t *testing.T) {
+	retained := make([]byte, 8<<20)
+	assert.GreaterOrEqual(t, heapInUse(), uint64(len(retained)))
+	runtime.KeepAlive(retained)
+}
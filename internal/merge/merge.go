@@ -0,0 +1,403 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/engine"
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/parser"
+)
+
+// StatusStrategy resolves conflicting status expectations of the same operation
+type StatusStrategy string
+
+const (
+	// StrategyUnion accepts every status code and range seen in any fragment
+	StrategyUnion StatusStrategy = "union"
+	// StrategyIntersection keeps only status codes and ranges present in every fragment
+	StrategyIntersection StatusStrategy = "intersection"
+	// StrategyFirst keeps the expectations of the first fragment defining the operation
+	StrategyFirst StatusStrategy = "first"
+	// StrategyFail reports conflicting expectations as an error
+	StrategyFail StatusStrategy = "fail"
+)
+
+// Options configures how ServiceSpec fragments are merged
+type Options struct {
+	ServiceName    string         // Overrides the merged service name; fragments must agree when empty
+	ServiceVersion string         // Overrides the merged version; the first fragment's version when empty
+	StatusStrategy StatusStrategy // How to resolve conflicting status expectations
+}
+
+// DefaultOptions returns default merge options
+func DefaultOptions() *Options {
+	return &Options{
+		StatusStrategy: StrategyUnion,
+	}
+}
+
+// ParseStatusStrategy validates a strategy name
+func ParseStatusStrategy(name string) (StatusStrategy, error) {
+	switch strategy := StatusStrategy(strings.ToLower(name)); strategy {
+	case StrategyUnion, StrategyIntersection, StrategyFirst, StrategyFail:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unknown status strategy %q, must be one of: union, intersection, first, fail", name)
+}
+
+// MergeFiles loads every ServiceSpec document from the given YAML files and merges them
+func MergeFiles(paths []string, options *Options) (*models.ServiceSpec, error) {
+	fileParser := parser.NewYAMLFileParser()
+
+	var specs []models.ServiceSpec
+	for _, path := range paths {
+		fileSpecs, errors := fileParser.ParseFile(path)
+		if len(errors) > 0 {
+			first := errors[0]
+			return nil, fmt.Errorf("contract %s is invalid: %s:%d: %s", path, first.File, first.Line, first.Message)
+		}
+		specs = append(specs, fileSpecs...)
+	}
+
+	return MergeSpecs(specs, options)
+}
+
+// MergeSpecs merges ServiceSpec fragments into one contract. Endpoints and operations are unioned,
+// status expectations are resolved with the configured strategy, a field stays required only when
+// every fragment defining the operation requires it, and Stats are recomputed from the fragments.
+func MergeSpecs(specs []models.ServiceSpec, options *Options) (*models.ServiceSpec, error) {
+	if options == nil {
+		options = DefaultOptions()
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no ServiceSpec fragments to merge")
+	}
+
+	merged := &models.ServiceSpec{
+		APIVersion: specs[0].APIVersion,
+		Kind:       specs[0].Kind,
+		Metadata: &models.ServiceSpecMetadata{
+			Name:    options.ServiceName,
+			Version: options.ServiceVersion,
+		},
+		Spec: &models.ServiceSpecDefinition{},
+	}
+
+	// Collect every definition of each operation, keyed by path and method
+	operations := make(map[string]map[string][]models.OperationSpec)
+	for _, spec := range specs {
+		if !spec.IsYAMLFormat() {
+			return nil, fmt.Errorf("cannot merge legacy format spec %s", spec.OperationID)
+		}
+		if err := mergeMetadata(merged.Metadata, spec.Metadata, options); err != nil {
+			return nil, err
+		}
+		for _, endpoint := range spec.Spec.Endpoints {
+			if operations[endpoint.Path] == nil {
+				operations[endpoint.Path] = make(map[string][]models.OperationSpec)
+			}
+			for _, operation := range endpoint.Operations {
+				method := strings.ToUpper(operation.Method)
+				operations[endpoint.Path][method] = append(operations[endpoint.Path][method], operation)
+			}
+		}
+	}
+
+	for _, path := range sortedKeys(operations) {
+		endpoint := models.EndpointSpec{Path: path}
+		for _, method := range sortedKeys(operations[path]) {
+			operation, err := mergeOperation(method, operations[path][method], options.StatusStrategy)
+			if err != nil {
+				return nil, fmt.Errorf("failed to merge %s %s: %w", method, path, err)
+			}
+			endpoint.Operations = append(endpoint.Operations, operation)
+		}
+		endpoint.Stats = endpointStats(endpoint.Operations)
+		merged.Spec.Endpoints = append(merged.Spec.Endpoints, endpoint)
+	}
+
+	return merged, nil
+}
+
+// mergeMetadata fills the merged metadata from a fragment, rejecting fragments of other services
+func mergeMetadata(merged, fragment *models.ServiceSpecMetadata, options *Options) error {
+	if options.ServiceName == "" {
+		if merged.Name == "" {
+			merged.Name = fragment.Name
+		} else if fragment.Name != merged.Name {
+			return fmt.Errorf("cannot merge fragments of different services %q and %q, set a service name to override", merged.Name, fragment.Name)
+		}
+	}
+	if merged.Version == "" {
+		merged.Version = fragment.Version
+	}
+	return nil
+}
+
+// mergeOperation merges every definition of one operation
+func mergeOperation(method string, definitions []models.OperationSpec, strategy StatusStrategy) (models.OperationSpec, error) {
+	merged := models.OperationSpec{Method: method}
+
+	responses, err := mergeResponses(definitions, strategy)
+	if err != nil {
+		return merged, err
+	}
+	merged.Responses = responses
+
+	// A field stays required only when every definition requires it; everything else is optional
+	requiredQuery := definitions[0].Required.Query
+	requiredHeaders := lowerAll(definitions[0].Required.Headers)
+	var seenQuery, seenHeaders []string
+	for _, definition := range definitions {
+		requiredQuery = intersect(requiredQuery, definition.Required.Query)
+		requiredHeaders = intersect(requiredHeaders, lowerAll(definition.Required.Headers))
+		seenQuery = union(seenQuery, union(definition.Required.Query, definition.Optional.Query))
+		seenHeaders = union(seenHeaders, lowerAll(union(definition.Required.Headers, definition.Optional.Headers)))
+	}
+	merged.Required = models.RequiredFieldsSpec{Query: requiredQuery, Headers: requiredHeaders}
+	merged.Optional = models.OptionalFieldsSpec{
+		Query:   subtract(seenQuery, requiredQuery),
+		Headers: subtract(seenHeaders, requiredHeaders),
+	}
+
+	// An operation deprecated by any fragment stays deprecated, with the earliest sunset date
+	for _, definition := range definitions {
+		if !definition.Deprecated {
+			continue
+		}
+		merged.Deprecated = true
+		if definition.Sunset != "" && (merged.Sunset == "" || definition.Sunset < merged.Sunset) {
+			merged.Sunset = definition.Sunset
+		}
+	}
+
+	merged.Stats = operationStats(definitions)
+	return merged, nil
+}
+
+// mergeResponses resolves the status expectations of every definition with the given strategy
+func mergeResponses(definitions []models.OperationSpec, strategy StatusStrategy) (models.ResponseSpec, error) {
+	merged := definitions[0].Responses
+	merged.StatusCodes = append([]int(nil), merged.StatusCodes...)
+	merged.StatusRanges = append([]string(nil), merged.StatusRanges...)
+
+	for _, definition := range definitions[1:] {
+		responses := definition.Responses
+		if merged.Aggregation == "" {
+			merged.Aggregation = responses.Aggregation
+		}
+
+		switch strategy {
+		case StrategyFirst:
+			continue
+		case StrategyFail:
+			if !sameStatuses(merged, responses) {
+				return merged, fmt.Errorf("conflicting status expectations %s and %s", describeStatuses(merged), describeStatuses(responses))
+			}
+		case StrategyIntersection:
+			merged.StatusCodes = intersectInts(merged.StatusCodes, responses.StatusCodes)
+			merged.StatusRanges = intersect(merged.StatusRanges, responses.StatusRanges)
+		default:
+			merged.StatusCodes = unionInts(merged.StatusCodes, responses.StatusCodes)
+			merged.StatusRanges = union(merged.StatusRanges, responses.StatusRanges)
+		}
+	}
+
+	if len(merged.StatusCodes) == 0 && len(merged.StatusRanges) == 0 {
+		return merged, fmt.Errorf("no status expectations are shared by every fragment")
+	}
+	return merged, nil
+}
+
+// operationStats sums support counts and widens the seen window across definitions
+func operationStats(definitions []models.OperationSpec) *models.OperationStats {
+	var stats *models.OperationStats
+	for _, definition := range definitions {
+		if definition.Stats == nil {
+			continue
+		}
+		if stats == nil {
+			copied := *definition.Stats
+			stats = &copied
+			continue
+		}
+		stats.SupportCount += definition.Stats.SupportCount
+		if definition.Stats.FirstSeen.Before(stats.FirstSeen) || stats.FirstSeen.IsZero() {
+			stats.FirstSeen = definition.Stats.FirstSeen
+		}
+		if definition.Stats.LastSeen.After(stats.LastSeen) {
+			stats.LastSeen = definition.Stats.LastSeen
+		}
+		stats.Latency = mergeLatency(stats.Latency, definition.Stats.Latency)
+	}
+	return stats
+}
+
+// mergeLatency combines the latency of two fragments. Percentiles cannot be recomputed without
+// the samples, so the higher of each is kept as an upper bound.
+func mergeLatency(a, b *models.LatencyStats) *models.LatencyStats {
+	if a == nil || b == nil {
+		if a == nil {
+			return b
+		}
+		return a
+	}
+	return &models.LatencyStats{
+		Samples: a.Samples + b.Samples,
+		P50Ms:   math.Max(a.P50Ms, b.P50Ms),
+		P95Ms:   math.Max(a.P95Ms, b.P95Ms),
+		P99Ms:   math.Max(a.P99Ms, b.P99Ms),
+		MaxMs:   math.Max(a.MaxMs, b.MaxMs),
+	}
+}
+
+// endpointStats recomputes endpoint statistics from its merged operations
+func endpointStats(operations []models.OperationSpec) *models.EndpointStats {
+	var stats *models.EndpointStats
+	for _, operation := range operations {
+		if operation.Stats == nil {
+			continue
+		}
+		if stats == nil {
+			stats = &models.EndpointStats{FirstSeen: operation.Stats.FirstSeen, LastSeen: operation.Stats.LastSeen}
+		}
+		stats.SupportCount += operation.Stats.SupportCount
+		if operation.Stats.FirstSeen.Before(stats.FirstSeen) || stats.FirstSeen.IsZero() {
+			stats.FirstSeen = operation.Stats.FirstSeen
+		}
+		if operation.Stats.LastSeen.After(stats.LastSeen) {
+			stats.LastSeen = operation.Stats.LastSeen
+		}
+	}
+	return stats
+}
+
+// Encode writes a merged spec as YAML, without the legacy fields of ServiceSpec
+func Encode(w io.Writer, spec *models.ServiceSpec) error {
+	if err := engine.EncodeContract(w, spec, engine.ContractFormatYAML); err != nil {
+		return fmt.Errorf("failed to encode merged spec: %w", err)
+	}
+	return nil
+}
+
+// sameStatuses reports whether two response specs accept the same statuses
+func sameStatuses(a, b models.ResponseSpec) bool {
+	return reflect.DeepEqual(unionInts(nil, a.StatusCodes), unionInts(nil, b.StatusCodes)) &&
+		reflect.DeepEqual(union(nil, a.StatusRanges), union(nil, b.StatusRanges))
+}
+
+// describeStatuses formats status expectations for error messages
+func describeStatuses(responses models.ResponseSpec) string {
+	return fmt.Sprintf("(codes %v, ranges %v)", responses.StatusCodes, responses.StatusRanges)
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// union returns the sorted, de-duplicated union of two string lists
+func union(a, b []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, value := range append(append([]string(nil), a...), b...) {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// intersect returns the sorted values present in both lists
+func intersect(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, value := range b {
+		inB[value] = true
+	}
+	var result []string
+	for _, value := range union(nil, a) {
+		if inB[value] {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// subtract returns the values of a that are not in b
+func subtract(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, value := range b {
+		inB[value] = true
+	}
+	var result []string
+	for _, value := range a {
+		if !inB[value] {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// unionInts returns the sorted, de-duplicated union of two status code lists
+func unionInts(a, b []int) []int {
+	seen := make(map[int]bool)
+	var result []int
+	for _, value := range append(append([]int(nil), a...), b...) {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	sort.Ints(result)
+	return result
+}
+
+// intersectInts returns the sorted status codes present in both lists
+func intersectInts(a, b []int) []int {
+	inB := make(map[int]bool, len(b))
+	for _, value := range b {
+		inB[value] = true
+	}
+	var result []int
+	for _, value := range unionInts(nil, a) {
+		if inB[value] {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// lowerAll lower-cases header names, which are case-insensitive
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, value := range values {
+		lowered[i] = strings.ToLower(value)
+	}
+	return lowered
+}
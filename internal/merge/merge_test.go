@@ -0,0 +1,218 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFragment(name string, endpoints ...models.EndpointSpec) models.ServiceSpec {
+	return models.ServiceSpec{
+		APIVersion: "flowspec/v1alpha1",
+		Kind:       "ServiceSpec",
+		Metadata:   &models.ServiceSpecMetadata{Name: name, Version: "v1.0.0"},
+		Spec:       &models.ServiceSpecDefinition{Endpoints: endpoints},
+	}
+}
+
+func TestMergeSpecs(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	shardA := newFragment("user-service", models.EndpointSpec{Path: "/users", Operations: []models.OperationSpec{
+		{
+			Method:    "GET",
+			Responses: models.ResponseSpec{StatusCodes: []int{200, 404}, Aggregation: "exact"},
+			Required:  models.RequiredFieldsSpec{Headers: []string{"Authorization", "X-Tenant"}},
+			Stats:     &models.OperationStats{SupportCount: 10, FirstSeen: day(2), LastSeen: day(3)},
+		},
+	}})
+	shardB := newFragment("user-service",
+		models.EndpointSpec{Path: "/users", Operations: []models.OperationSpec{
+			{
+				Method:    "get",
+				Responses: models.ResponseSpec{StatusCodes: []int{200, 500}},
+				Required:  models.RequiredFieldsSpec{Headers: []string{"authorization"}, Query: []string{"page"}},
+				Stats:     &models.OperationStats{SupportCount: 5, FirstSeen: day(1), LastSeen: day(2)},
+			},
+			{
+				Method:    "POST",
+				Responses: models.ResponseSpec{StatusCodes: []int{201}},
+				Stats:     &models.OperationStats{SupportCount: 3, FirstSeen: day(4), LastSeen: day(5)},
+			},
+		}},
+		models.EndpointSpec{Path: "/health", Operations: []models.OperationSpec{
+			{Method: "GET", Responses: models.ResponseSpec{StatusRanges: []string{"2xx"}}},
+		}},
+	)
+
+	merged, err := MergeSpecs([]models.ServiceSpec{shardA, shardB}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "user-service", merged.Metadata.Name)
+
+	endpoints := merged.Spec.Endpoints
+	require.Len(t, endpoints, 2)
+	assert.Equal(t, "/health", endpoints[0].Path)
+	assert.Nil(t, endpoints[0].Stats)
+
+	users := endpoints[1]
+	require.Len(t, users.Operations, 2)
+	get := users.Operations[0]
+	assert.Equal(t, "GET", get.Method)
+	assert.Equal(t, []int{200, 404, 500}, get.Responses.StatusCodes)
+	assert.Equal(t, "exact", get.Responses.Aggregation)
+	assert.Equal(t, []string{"authorization"}, get.Required.Headers)
+	assert.Empty(t, get.Required.Query)
+	assert.Equal(t, []string{"x-tenant"}, get.Optional.Headers)
+	assert.Equal(t, []string{"page"}, get.Optional.Query)
+	assert.Equal(t, &models.OperationStats{SupportCount: 15, FirstSeen: day(1), LastSeen: day(3)}, get.Stats)
+
+	assert.Equal(t, &models.EndpointStats{SupportCount: 18, FirstSeen: day(1), LastSeen: day(5)}, users.Stats)
+
+	// Fragments are not modified
+	assert.Equal(t, 10, shardA.Spec.Endpoints[0].Operations[0].Stats.SupportCount)
+}
+
+func TestMergeSpecs_StatusStrategies(t *testing.T) {
+	fragments := []models.ServiceSpec{
+		newFragment("user-service", models.EndpointSpec{Path: "/users", Operations: []models.OperationSpec{
+			{Method: "GET", Responses: models.ResponseSpec{StatusCodes: []int{200, 404}}},
+		}}),
+		newFragment("user-service", models.EndpointSpec{Path: "/users", Operations: []models.OperationSpec{
+			{Method: "GET", Responses: models.ResponseSpec{StatusCodes: []int{200, 500}}},
+		}}),
+	}
+
+	testCases := []struct {
+		strategy      StatusStrategy
+		expectedCodes []int
+		expectedError string
+	}{
+		{strategy: StrategyUnion, expectedCodes: []int{200, 404, 500}},
+		{strategy: StrategyIntersection, expectedCodes: []int{200}},
+		{strategy: StrategyFirst, expectedCodes: []int{200, 404}},
+		{strategy: StrategyFail, expectedError: "failed to merge GET /users: conflicting status expectations"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.strategy), func(t *testing.T) {
+			merged, err := MergeSpecs(fragments, &Options{StatusStrategy: tc.strategy})
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCodes, merged.Spec.Endpoints[0].Operations[0].Responses.StatusCodes)
+		})
+	}
+}
+
+func TestMergeSpecs_Errors(t *testing.T) {
+	endpoint := models.EndpointSpec{Path: "/users", Operations: []models.OperationSpec{
+		{Method: "GET", Responses: models.ResponseSpec{StatusCodes: []int{200}}},
+	}}
+
+	_, err := MergeSpecs(nil, nil)
+	assert.ErrorContains(t, err, "no ServiceSpec fragments")
+
+	_, err = MergeSpecs([]models.ServiceSpec{newFragment("users", endpoint), newFragment("orders", endpoint)}, nil)
+	assert.ErrorContains(t, err, `different services "users" and "orders"`)
+
+	merged, err := MergeSpecs([]models.ServiceSpec{newFragment("users", endpoint), newFragment("orders", endpoint)},
+		&Options{ServiceName: "gateway", ServiceVersion: "v2", StatusStrategy: StrategyUnion})
+	require.NoError(t, err)
+	assert.Equal(t, "gateway", merged.Metadata.Name)
+	assert.Equal(t, "v2", merged.Metadata.Version)
+
+	disjoint := newFragment("users", models.EndpointSpec{Path: "/users", Operations: []models.OperationSpec{
+		{Method: "GET", Responses: models.ResponseSpec{StatusCodes: []int{500}}},
+	}})
+	_, err = MergeSpecs([]models.ServiceSpec{newFragment("users", endpoint), disjoint}, &Options{StatusStrategy: StrategyIntersection})
+	assert.ErrorContains(t, err, "no status expectations are shared")
+}
+
+func TestMergeSpecs_Latency(t *testing.T) {
+	operation := func(latency *models.LatencyStats) models.EndpointSpec {
+		return models.EndpointSpec{Path: "/users", Operations: []models.OperationSpec{{
+			Method:    "GET",
+			Responses: models.ResponseSpec{StatusCodes: []int{200}},
+			Stats:     &models.OperationStats{SupportCount: 1, Latency: latency},
+		}}}
+	}
+
+	merged, err := MergeSpecs([]models.ServiceSpec{
+		newFragment("users", operation(&models.LatencyStats{Samples: 10, P50Ms: 20, P95Ms: 90, P99Ms: 120, MaxMs: 150})),
+		newFragment("users", operation(&models.LatencyStats{Samples: 5, P50Ms: 30, P95Ms: 80, P99Ms: 100, MaxMs: 200})),
+		newFragment("users", operation(nil)),
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &models.LatencyStats{Samples: 15, P50Ms: 30, P95Ms: 90, P99Ms: 120, MaxMs: 200},
+		merged.Spec.Endpoints[0].Operations[0].Stats.Latency)
+
+	merged, err = MergeSpecs([]models.ServiceSpec{newFragment("users", operation(nil))}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, merged.Spec.Endpoints[0].Operations[0].Stats.Latency)
+}
+
+func TestParseStatusStrategy(t *testing.T) {
+	strategy, err := ParseStatusStrategy("Intersection")
+	require.NoError(t, err)
+	assert.Equal(t, StrategyIntersection, strategy)
+
+	_, err = ParseStatusStrategy("majority")
+	assert.ErrorContains(t, err, "unknown status strategy")
+}
+
+func TestMergeFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, path string) string {
+		file := filepath.Join(dir, name)
+		content := `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: user-service
+  version: v1.0.0
+spec:
+  endpoints:
+    - path: ` + path + `
+      operations:
+        - method: GET
+          responses:
+            statusCodes: [200]
+`
+		require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+		return file
+	}
+
+	merged, err := MergeFiles([]string{write("users.yaml", "/users"), write("orders.yaml", "/orders")}, DefaultOptions())
+	require.NoError(t, err)
+	require.Len(t, merged.Spec.Endpoints, 2)
+
+	var output bytes.Buffer
+	require.NoError(t, Encode(&output, merged))
+	assert.Contains(t, output.String(), "kind: ServiceSpec\n")
+	assert.Contains(t, output.String(), "  endpoints:\n    - path: /orders\n")
+	assert.NotContains(t, output.String(), "sourcefile", "legacy fields are not written")
+
+	_, err = MergeFiles([]string{filepath.Join(dir, "missing.yaml")}, DefaultOptions())
+	assert.Error(t, err)
+}
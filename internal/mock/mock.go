@@ -0,0 +1,334 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mock serves stub responses that satisfy a contract (flowspec-cli mock), so consumers
+// can develop against a contract before the real service exists
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// OperationHeader names the contract operation that produced a mock response
+const OperationHeader = "X-FlowSpec-Operation"
+
+// preferCode selects a declared status code, following the Prefer header convention of
+// other mock servers: "Prefer: code=404"
+var preferCode = regexp.MustCompile(`(?:^|[,;\s])code=(\d{3})`)
+
+// Options configures the mock handler
+type Options struct {
+	// SkipValidation serves responses even when required headers or query parameters are missing
+	SkipValidation bool
+}
+
+// Route is one contract operation served by the mock
+type Route struct {
+	Service   string
+	Method    string
+	Path      string
+	Statuses  []int // Declared status codes, the default first
+	operation models.OperationSpec
+	segments  []string
+}
+
+// Key returns the operation key, e.g. "GET /users/{id}"
+func (r *Route) Key() string {
+	return r.Method + " " + r.Path
+}
+
+// match reports whether the request path segments match the route
+func (r *Route) match(segments []string) bool {
+	if len(segments) != len(r.segments) {
+		return false
+	}
+	for i, segment := range r.segments {
+		if !isParameter(segment) && segment != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// specificity orders routes so that literal segments win over parameters
+func (r *Route) specificity() int {
+	literal := 0
+	for _, segment := range r.segments {
+		if !isParameter(segment) {
+			literal++
+		}
+	}
+	return literal
+}
+
+// Handler serves the mock responses
+type Handler struct {
+	routes  []*Route
+	options Options
+}
+
+// NewHandler builds a mock handler for the operations of specs
+func NewHandler(specs []models.ServiceSpec, options Options) (*Handler, error) {
+	handler := &Handler{options: options}
+	seen := make(map[string]string)
+	for _, spec := range specs {
+		if spec.Spec == nil {
+			continue // Legacy annotation specs describe no HTTP endpoints
+		}
+		service := ""
+		if spec.Metadata != nil {
+			service = spec.Metadata.Name
+		}
+		for _, endpoint := range spec.Spec.Endpoints {
+			for _, operation := range endpoint.Operations {
+				route := &Route{
+					Service:   service,
+					Method:    strings.ToUpper(operation.Method),
+					Path:      endpoint.Path,
+					Statuses:  declaredStatuses(operation.Responses),
+					operation: operation,
+					segments:  splitPath(endpoint.Path),
+				}
+				if owner, ok := seen[route.Key()]; ok {
+					logrus.WithFields(logrus.Fields{"operation": route.Key(), "service": service}).
+						Warnf("Operation already declared by %s, ignoring duplicate", owner)
+					continue
+				}
+				seen[route.Key()] = service
+				handler.routes = append(handler.routes, route)
+			}
+		}
+	}
+	if len(handler.routes) == 0 {
+		return nil, fmt.Errorf("no endpoints found in contracts; the mock needs YAML contracts with spec.endpoints")
+	}
+
+	sort.SliceStable(handler.routes, func(i, j int) bool {
+		return handler.routes[i].specificity() > handler.routes[j].specificity()
+	})
+	return handler, nil
+}
+
+// Routes returns the served operations sorted by path and method
+func (h *Handler) Routes() []*Route {
+	routes := append([]*Route(nil), h.routes...)
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	status := h.serve(w, r)
+	logrus.WithFields(logrus.Fields{
+		"method":   r.Method,
+		"path":     r.URL.Path,
+		"status":   status,
+		"duration": time.Since(start).Round(time.Microsecond),
+	}).Info("Mock request")
+}
+
+// serve writes the response for r and returns its status code
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request) int {
+	segments := splitPath(r.URL.Path)
+	var allowed []string
+	var route *Route
+	for _, candidate := range h.routes {
+		if !candidate.match(segments) {
+			continue
+		}
+		if candidate.Method == r.Method {
+			route = candidate
+			break
+		}
+		allowed = append(allowed, candidate.Method)
+	}
+
+	if route == nil {
+		if len(allowed) > 0 {
+			sort.Strings(allowed)
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			return writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s is not declared for %s; the contract allows %s", r.Method, r.URL.Path, strings.Join(allowed, ", ")))
+		}
+		return writeError(w, http.StatusNotFound, fmt.Sprintf("no contract operation matches %s %s", r.Method, r.URL.Path))
+	}
+
+	w.Header().Set(OperationHeader, route.Key())
+	if !h.options.SkipValidation {
+		if missing := missingRequired(route.operation.Required, r); len(missing) > 0 {
+			return writeError(w, http.StatusBadRequest, fmt.Sprintf("missing required %s for %s", strings.Join(missing, ", "), route.Key()))
+		}
+	}
+
+	status := route.Statuses[0]
+	if match := preferCode.FindStringSubmatch(r.Header.Get("Prefer")); match != nil {
+		preferred, _ := strconv.Atoi(match[1])
+		if !containsStatus(route.Statuses, preferred) {
+			return writeError(w, http.StatusBadRequest, fmt.Sprintf("status %d is not declared for %s; declared: %s", preferred, route.Key(), formatStatuses(route.Statuses)))
+		}
+		status = preferred
+	}
+
+	if !bodyAllowed(status, r.Method) {
+		w.WriteHeader(status)
+		return status
+	}
+	writeJSON(w, status, map[string]interface{}{
+		"mock":       true,
+		"service":    route.Service,
+		"operation":  route.Key(),
+		"status":     status,
+		"parameters": pathParameters(route.segments, segments),
+	})
+	return status
+}
+
+// declaredStatuses lists the status codes a response may have, the default first: the lowest
+// 2xx code, else the lowest declared code. Ranges such as "4xx" contribute their base code.
+func declaredStatuses(responses models.ResponseSpec) []int {
+	set := make(map[int]bool)
+	for _, code := range responses.StatusCodes {
+		set[code] = true
+	}
+	for _, statusRange := range responses.StatusRanges {
+		if len(statusRange) == 3 && strings.HasSuffix(strings.ToLower(statusRange), "xx") {
+			if class, err := strconv.Atoi(statusRange[:1]); err == nil && class >= 1 && class <= 5 {
+				set[class*100] = true
+			}
+		}
+	}
+	if len(set) == 0 {
+		return []int{http.StatusOK}
+	}
+
+	statuses := make([]int, 0, len(set))
+	for code := range set {
+		statuses = append(statuses, code)
+	}
+	sort.Ints(statuses)
+	for i, code := range statuses {
+		if code >= 200 && code < 300 {
+			statuses[0], statuses[i] = statuses[i], statuses[0]
+			sort.Ints(statuses[1:])
+			break
+		}
+	}
+	return statuses
+}
+
+// missingRequired lists the required headers and query parameters absent from r
+func missingRequired(required models.RequiredFieldsSpec, r *http.Request) []string {
+	var missing []string
+	for _, header := range required.Headers {
+		if r.Header.Get(header) == "" {
+			missing = append(missing, "header "+header)
+		}
+	}
+	query := r.URL.Query()
+	for _, parameter := range required.Query {
+		if !query.Has(parameter) {
+			missing = append(missing, "query parameter "+parameter)
+		}
+	}
+	return missing
+}
+
+// pathParameters maps the parameters of a route pattern to their request values
+func pathParameters(pattern, segments []string) map[string]string {
+	parameters := make(map[string]string)
+	for i, segment := range pattern {
+		if isParameter(segment) {
+			parameters[strings.Trim(segment, "{}")] = segments[i]
+		}
+	}
+	return parameters
+}
+
+// bodyAllowed reports whether a response with status may carry a body
+func bodyAllowed(status int, method string) bool {
+	if method == http.MethodHead {
+		return false
+	}
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func isParameter(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func containsStatus(statuses []int, status int) bool {
+	for _, declared := range statuses {
+		if declared == status {
+			return true
+		}
+	}
+	return false
+}
+
+func formatStatuses(statuses []int) string {
+	parts := make([]string, len(statuses))
+	for i, status := range statuses {
+		parts[i] = strconv.Itoa(status)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// writeJSON writes value as a JSON response
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// writeError writes a mock error, which is not part of the contract, and returns its status
+func writeError(w http.ResponseWriter, status int, message string) int {
+	writeJSON(w, status, map[string]interface{}{"mock": true, "error": message})
+	return status
+}
+
+// FormatRoutes renders the served operations for the startup banner
+func FormatRoutes(routes []*Route) string {
+	var output strings.Builder
+	for _, route := range routes {
+		output.WriteString(fmt.Sprintf("  %-7s %s → %s\n", route.Method, route.Path, formatStatuses(route.Statuses)))
+	}
+	return output.String()
+}
+
+// ListenAndServe serves handler on port until the server fails
+func ListenAndServe(port int, handler *Handler) error {
+	addr := fmt.Sprintf(":%d", port)
+	fmt.Printf("🎭 FlowSpec mock listening on http://localhost%s\n%s", addr, FormatRoutes(handler.Routes()))
+	server := &http.Server{Addr: addr, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	return server.ListenAndServe()
+}
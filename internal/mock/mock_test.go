@@ -0,0 +1,141 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSpecs() []models.ServiceSpec {
+	return []models.ServiceSpec{{
+		Metadata: &models.ServiceSpecMetadata{Name: "user-service", Version: "v1"},
+		Spec: &models.ServiceSpecDefinition{Endpoints: []models.EndpointSpec{
+			{Path: "/users/{id}", Operations: []models.OperationSpec{
+				{Method: "GET", Responses: models.ResponseSpec{StatusCodes: []int{404, 200}}},
+				{Method: "DELETE", Responses: models.ResponseSpec{StatusCodes: []int{204}},
+					Required: models.RequiredFieldsSpec{Headers: []string{"Authorization"}}},
+			}},
+			{Path: "/users/me", Operations: []models.OperationSpec{
+				{Method: "GET", Responses: models.ResponseSpec{StatusRanges: []string{"4xx", "2xx"}}},
+			}},
+			{Path: "/users", Operations: []models.OperationSpec{
+				{Method: "GET", Required: models.RequiredFieldsSpec{Query: []string{"page"}}},
+			}},
+		}},
+	}}
+}
+
+func TestDeclaredStatuses(t *testing.T) {
+	testCases := []struct {
+		name      string
+		responses models.ResponseSpec
+		expected  []int
+	}{
+		{"nothing declared", models.ResponseSpec{}, []int{200}},
+		{"success first", models.ResponseSpec{StatusCodes: []int{500, 404, 201, 200}}, []int{200, 201, 404, 500}},
+		{"ranges", models.ResponseSpec{StatusRanges: []string{"5xx", "2xx", "bad"}}, []int{200, 500}},
+		{"errors only", models.ResponseSpec{StatusCodes: []int{503, 400}}, []int{400, 503}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, declaredStatuses(tc.responses))
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	handler, err := NewHandler(newSpecs(), Options{})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name      string
+		method    string
+		target    string
+		headers   map[string]string
+		status    int
+		operation string
+	}{
+		{"default success status", "GET", "/users/42", nil, 200, "GET /users/{id}"},
+		{"literal path wins", "GET", "/users/me", nil, 200, "GET /users/me"},
+		{"preferred status", "GET", "/users/42", map[string]string{"Prefer": "code=404"}, 404, "GET /users/{id}"},
+		{"undeclared preferred status", "GET", "/users/42", map[string]string{"Prefer": "code=500"}, 400, "GET /users/{id}"},
+		{"missing required header", "DELETE", "/users/42", nil, 400, "DELETE /users/{id}"},
+		{"required header present", "DELETE", "/users/42", map[string]string{"Authorization": "Bearer x"}, 204, "DELETE /users/{id}"},
+		{"missing required query", "GET", "/users", nil, 400, "GET /users"},
+		{"required query present", "GET", "/users?page=2", nil, 200, "GET /users"},
+		{"method not declared", "POST", "/users/42", nil, 405, ""},
+		{"path not declared", "GET", "/orders", nil, 404, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest(tc.method, tc.target, nil)
+			for name, value := range tc.headers {
+				request.Header.Set(name, value)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, tc.status, recorder.Code, recorder.Body.String())
+			assert.Equal(t, tc.operation, recorder.Header().Get(OperationHeader))
+		})
+	}
+}
+
+func TestHandler_ResponseBody(t *testing.T) {
+	handler, err := NewHandler(newSpecs(), Options{})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/users/42", nil))
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, "user-service", body["service"])
+	assert.Equal(t, map[string]interface{}{"id": "42"}, body["parameters"])
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/users/42", nil))
+	assert.Equal(t, "DELETE, GET", recorder.Header().Get("Allow"))
+
+	lenient, err := NewHandler(newSpecs(), Options{SkipValidation: true})
+	require.NoError(t, err)
+	recorder = httptest.NewRecorder()
+	lenient.ServeHTTP(recorder, httptest.NewRequest("DELETE", "/users/42", nil))
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.Empty(t, recorder.Body.String())
+}
+
+func TestNewHandler_NoEndpoints(t *testing.T) {
+	_, err := NewHandler([]models.ServiceSpec{{OperationID: "legacy"}}, Options{})
+	assert.Error(t, err)
+}
+
+func TestFormatRoutes(t *testing.T) {
+	handler, err := NewHandler(newSpecs(), Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "  GET     /users → 200\n"+
+		"  GET     /users/me → 200, 400\n"+
+		"  DELETE  /users/{id} → 204\n"+
+		"  GET     /users/{id} → 200, 404\n", FormatRoutes(handler.Routes()))
+}
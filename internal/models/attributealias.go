@@ -0,0 +1,132 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AliasAttributes maps each field that can be aliased to the span attribute the engine reads it
+// from
+var AliasAttributes = map[string]string{
+	"method":      "http.method",
+	"route":       "http.route",
+	"target":      "http.target",
+	"url":         "http.url",
+	"statusCode":  "http.status_code",
+	"operationId": "operation.id",
+}
+
+// AttributeAliases lists, per field, the span attributes that may carry it in order of
+// preference, e.g. {"method": ["http.method", "http.request.method", "custom.verb"]}
+type AttributeAliases map[string][]string
+
+// Validate checks that every field is known and lists at least one attribute
+func (a AttributeAliases) Validate() error {
+	for _, field := range a.fields() {
+		if _, known := AliasAttributes[field]; !known {
+			return fmt.Errorf("unknown field '%s', must be one of: %s", field, strings.Join(aliasFieldNames(), ", "))
+		}
+		if len(a[field]) == 0 {
+			return fmt.Errorf("%s: at least one attribute is required", field)
+		}
+		for i, attribute := range a[field] {
+			if strings.TrimSpace(attribute) == "" {
+				return fmt.Errorf("%s[%d]: attribute name must not be empty", field, i)
+			}
+		}
+	}
+	return nil
+}
+
+// Resolve returns a copy of the span whose attributes read by the engine hold the value of the
+// first listed alias present on the span; the span itself is returned when no alias applies
+func (a AttributeAliases) Resolve(span *Span) *Span {
+	var attributes map[string]interface{}
+	for _, field := range a.fields() {
+		canonical, known := AliasAttributes[field]
+		if !known {
+			continue
+		}
+		for _, alias := range a[field] {
+			value, present := span.Attributes[alias]
+			if !present {
+				continue
+			}
+			if alias != canonical {
+				if attributes == nil {
+					attributes = make(map[string]interface{}, len(span.Attributes)+len(a))
+					for key, existing := range span.Attributes {
+						attributes[key] = existing
+					}
+				}
+				attributes[canonical] = value
+			}
+			break
+		}
+	}
+	if attributes == nil {
+		return span
+	}
+
+	resolved := *span
+	resolved.Attributes = attributes
+	return &resolved
+}
+
+// WithAttributeAliases returns a copy of the trace whose spans have their aliases resolved, with
+// the span tree rebuilt; the trace itself is returned when no span changes
+func (td *TraceData) WithAttributeAliases(aliases AttributeAliases) *TraceData {
+	if len(aliases) == 0 {
+		return td
+	}
+
+	resolved := &TraceData{TraceID: td.TraceID, Spans: make(map[string]*Span, len(td.Spans)), DuplicateSpanIDs: td.DuplicateSpanIDs}
+	changed := false
+	for id, span := range td.Spans {
+		resolved.Spans[id] = aliases.Resolve(span)
+		changed = changed || resolved.Spans[id] != span
+	}
+	if !changed {
+		return td
+	}
+	if resolved.BuildSpanTree() != nil {
+		resolved.RootSpan = nil
+		resolved.SpanTree = nil
+	}
+	return resolved
+}
+
+// fields returns the aliased fields in order, so aliases resolve the same way in every run
+func (a AttributeAliases) fields() []string {
+	fields := make([]string, 0, len(a))
+	for field := range a {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// aliasFieldNames returns the fields that can be aliased in order
+func aliasFieldNames() []string {
+	names := make([]string, 0, len(AliasAttributes))
+	for field := range AliasAttributes {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,96 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttributeAliases_Validate(t *testing.T) {
+	assert.NoError(t, AttributeAliases(nil).Validate())
+	assert.NoError(t, AttributeAliases{"method": {"http.method", "custom.verb"}}.Validate())
+	assert.ErrorContains(t, AttributeAliases{"verb": {"custom.verb"}}.Validate(), "unknown field 'verb', must be one of: method, operationId, route, statusCode, target, url")
+	assert.ErrorContains(t, AttributeAliases{"method": nil}.Validate(), "method: at least one attribute is required")
+	assert.ErrorContains(t, AttributeAliases{"route": {"x.route", " "}}.Validate(), "route[1]: attribute name must not be empty")
+}
+
+func TestAttributeAliases_Resolve(t *testing.T) {
+	aliases := AttributeAliases{
+		"method":     {"http.method", "http.request.method", "custom.verb"},
+		"statusCode": {"custom.status", "http.status_code"},
+	}
+
+	testCases := []struct {
+		name       string
+		attributes map[string]interface{}
+		expected   map[string]interface{}
+	}{
+		{
+			name:       "canonical attribute listed first wins",
+			attributes: map[string]interface{}{"http.method": "GET", "custom.verb": "POST"},
+			expected:   map[string]interface{}{"http.method": "GET", "custom.verb": "POST"},
+		},
+		{
+			name:       "first present alias is copied",
+			attributes: map[string]interface{}{"custom.verb": "POST"},
+			expected:   map[string]interface{}{"http.method": "POST", "custom.verb": "POST"},
+		},
+		{
+			name:       "alias listed before the canonical attribute overrides it",
+			attributes: map[string]interface{}{"http.status_code": 200, "custom.status": 503},
+			expected:   map[string]interface{}{"http.status_code": 503, "custom.status": 503},
+		},
+		{
+			name:       "no alias present",
+			attributes: map[string]interface{}{"db.system": "redis"},
+			expected:   map[string]interface{}{"db.system": "redis"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			span := &Span{SpanID: "s1", Attributes: tc.attributes}
+			resolved := aliases.Resolve(span)
+			assert.Equal(t, tc.expected, resolved.Attributes)
+		})
+	}
+
+	unchanged := &Span{Attributes: map[string]interface{}{"http.method": "GET"}}
+	assert.Same(t, unchanged, aliases.Resolve(unchanged))
+}
+
+func TestTraceData_WithAttributeAliases(t *testing.T) {
+	trace := &TraceData{TraceID: "t1", Spans: map[string]*Span{
+		"root":  {SpanID: "root", Attributes: map[string]interface{}{"custom.verb": "GET"}},
+		"child": {SpanID: "child", ParentID: "root", Attributes: map[string]interface{}{}},
+	}, DuplicateSpanIDs: []string{"child"}}
+	aliases := AttributeAliases{"method": {"custom.verb"}}
+
+	assert.Same(t, trace, trace.WithAttributeAliases(nil))
+	resolved := trace.WithAttributeAliases(aliases)
+	assert.NotSame(t, trace, resolved)
+	assert.Equal(t, "GET", resolved.Spans["root"].Attributes["http.method"])
+	assert.NotContains(t, trace.Spans["root"].Attributes, "http.method", "the original trace is unchanged")
+	assert.Same(t, trace.Spans["child"], resolved.Spans["child"])
+	assert.Equal(t, []string{"child"}, resolved.DuplicateSpanIDs, "duplicates dropped on ingestion are still reported")
+	require.NotNil(t, resolved.RootSpan)
+	assert.Same(t, resolved.Spans["root"], resolved.RootSpan)
+
+	plain := &TraceData{TraceID: "t2", Spans: map[string]*Span{"s": {SpanID: "s", Attributes: map[string]interface{}{}}}}
+	assert.Same(t, plain, plain.WithAttributeAliases(aliases))
+}
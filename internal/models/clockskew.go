@@ -0,0 +1,109 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// ClockSkewCorrection summarizes the spans moved by WithClockSkewCorrection
+type ClockSkewCorrection struct {
+	Spans    int   `json:"spans"`    // Spans moved or whose end was clamped
+	MaxShift int64 `json:"maxShift"` // Largest shift of a span, in nanoseconds
+}
+
+// WithClockSkewCorrection returns a copy of the trace corrected for clock drift between hosts.
+// A child span starting before its parent, or ending after the root of its trace, by more than
+// tolerance is moved with its descendants to start with its parent or end with the root; its
+// duration is kept, as it was measured on a single clock. A span ending before it starts is
+// given a zero duration. The trace is returned unchanged, with a nil correction, when no span
+// needed one.
+func (td *TraceData) WithClockSkewCorrection(tolerance time.Duration) (*TraceData, *ClockSkewCorrection) {
+	children := make(map[string][]*Span, len(td.Spans))
+	var roots []*Span
+	for _, span := range td.Spans {
+		if _, ok := td.Spans[span.ParentID]; ok && span.ParentID != span.SpanID {
+			children[span.ParentID] = append(children[span.ParentID], span)
+		} else {
+			roots = append(roots, span)
+		}
+	}
+
+	correction := &ClockSkewCorrection{}
+	corrected := make(map[string]*Span)
+	visited := make(map[string]bool, len(td.Spans))
+	var correct func(span, parent *Span, rootEnd, shift int64)
+	correct = func(span, parent *Span, rootEnd, shift int64) {
+		if visited[span.SpanID] {
+			return
+		}
+		visited[span.SpanID] = true
+
+		start, end := span.StartTime+shift, span.EndTime+shift
+		clamped := end < start
+		if clamped {
+			end = start
+		}
+		if parent != nil {
+			var delta int64
+			if skew := parent.StartTime - start; skew > int64(tolerance) {
+				delta = skew
+			} else if overrun := end - rootEnd; overrun > int64(tolerance) {
+				delta = max(-overrun, parent.StartTime-start)
+			}
+			shift += delta
+			start, end = start+delta, end+delta
+		}
+
+		current := span
+		if start != span.StartTime || end != span.EndTime {
+			moved := *span
+			moved.StartTime, moved.EndTime = start, end
+			current = &moved
+			corrected[span.SpanID] = current
+			correction.Spans++
+			correction.MaxShift = max(correction.MaxShift, abs(shift))
+		}
+		for _, child := range children[span.SpanID] {
+			correct(child, current, rootEnd, shift)
+		}
+	}
+	for _, root := range roots {
+		end := max(root.EndTime, root.StartTime)
+		correct(root, nil, end, 0)
+	}
+
+	if len(corrected) == 0 {
+		return td, nil
+	}
+	result := &TraceData{TraceID: td.TraceID, Spans: make(map[string]*Span, len(td.Spans)), DuplicateSpanIDs: td.DuplicateSpanIDs}
+	for id, span := range td.Spans {
+		if moved, ok := corrected[id]; ok {
+			span = moved
+		}
+		result.Spans[id] = span
+	}
+	if result.BuildSpanTree() != nil {
+		result.RootSpan = nil
+		result.SpanTree = nil
+	}
+	return result, correction
+}
+
+// abs returns the absolute value of n
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,118 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceData_WithClockSkewCorrection(t *testing.T) {
+	ms := int64(time.Millisecond)
+	span := func(id, parentID string, start, end int64) *Span {
+		return &Span{TraceID: "t1", SpanID: id, ParentID: parentID, StartTime: start * ms, EndTime: end * ms}
+	}
+	trace := func(spans ...*Span) *TraceData {
+		td := &TraceData{TraceID: "t1"}
+		for _, s := range spans {
+			td.AddSpan(s)
+		}
+		require.NoError(t, td.BuildSpanTree())
+		return td
+	}
+
+	testCases := []struct {
+		name       string
+		trace      *TraceData
+		tolerance  time.Duration
+		expected   map[string][2]int64 // Start and end of each span, in milliseconds
+		correction *ClockSkewCorrection
+	}{
+		{
+			name:       "consistent trace",
+			trace:      trace(span("root", "", 0, 100), span("child", "root", 10, 90)),
+			correction: nil,
+		},
+		{
+			name:       "skew within tolerance",
+			trace:      trace(span("root", "", 10, 100), span("child", "root", 5, 104)),
+			tolerance:  5 * time.Millisecond,
+			correction: nil,
+		},
+		{
+			name: "child starting before its parent is moved with its descendants",
+			trace: trace(span("root", "", 100, 200), span("child", "root", 70, 120),
+				span("grandchild", "child", 80, 90)),
+			expected: map[string][2]int64{
+				"root": {100, 200}, "child": {100, 150}, "grandchild": {110, 120},
+			},
+			correction: &ClockSkewCorrection{Spans: 2, MaxShift: 30 * ms},
+		},
+		{
+			name:  "child ending after the root is moved back",
+			trace: trace(span("root", "", 0, 100), span("child", "root", 60, 120)),
+			expected: map[string][2]int64{
+				"root": {0, 100}, "child": {40, 100},
+			},
+			correction: &ClockSkewCorrection{Spans: 1, MaxShift: 20 * ms},
+		},
+		{
+			name:  "child longer than the root starts with its parent",
+			trace: trace(span("root", "", 0, 100), span("child", "root", 50, 200)),
+			expected: map[string][2]int64{
+				"root": {0, 100}, "child": {0, 150},
+			},
+			correction: &ClockSkewCorrection{Spans: 1, MaxShift: 50 * ms},
+		},
+		{
+			name:  "span ending before it starts gets a zero duration",
+			trace: trace(span("root", "", 0, 100), span("child", "root", 50, 40)),
+			expected: map[string][2]int64{
+				"root": {0, 100}, "child": {50, 50},
+			},
+			correction: &ClockSkewCorrection{Spans: 1, MaxShift: 0},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			corrected, correction := tc.trace.WithClockSkewCorrection(tc.tolerance)
+			assert.Equal(t, tc.correction, correction)
+			if tc.correction == nil {
+				assert.Same(t, tc.trace, corrected)
+				return
+			}
+
+			assert.NotSame(t, tc.trace, corrected)
+			for id, times := range tc.expected {
+				span := corrected.Spans[id]
+				assert.Equal(t, times[0]*ms, span.StartTime, id)
+				assert.Equal(t, times[1]*ms, span.EndTime, id)
+			}
+			require.NotNil(t, corrected.RootSpan)
+			assert.Equal(t, "root", corrected.RootSpan.SpanID)
+			assert.Empty(t, corrected.CheckCompleteness(tc.tolerance).Issues)
+		})
+	}
+
+	t.Run("original trace is left unchanged", func(t *testing.T) {
+		original := trace(span("root", "", 100, 200), span("child", "root", 70, 120))
+		original.WithClockSkewCorrection(0)
+		assert.Equal(t, 70*ms, original.Spans["child"].StartTime)
+	})
+}
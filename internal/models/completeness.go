@@ -0,0 +1,169 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TraceIssueKind identifies a structural problem of a trace
+type TraceIssueKind string
+
+const (
+	TraceIssueOrphanSpan      TraceIssueKind = "orphan_span"       // The parent of the span is not in the trace
+	TraceIssueMissingRoot     TraceIssueKind = "missing_root"      // No span of a trace ID is a root
+	TraceIssueMultipleRoots   TraceIssueKind = "multiple_roots"    // Several spans of a trace ID are roots
+	TraceIssueClockSkew       TraceIssueKind = "clock_skew"        // The span starts before its parent or ends before it starts
+	TraceIssueDuplicateSpanID TraceIssueKind = "duplicate_span_id" // Several spans of the trace share an ID
+)
+
+// ErrIncompleteTrace is matched by errors.Is when a structurally broken trace is refused
+var ErrIncompleteTrace = errors.New("trace is incomplete")
+
+// TraceIssue describes a structural problem of a trace
+type TraceIssue struct {
+	Kind     TraceIssueKind `json:"kind"`
+	TraceID  string         `json:"traceId,omitempty"`
+	SpanID   string         `json:"spanId,omitempty"`
+	ParentID string         `json:"parentSpanId,omitempty"`
+	Message  string         `json:"message"`
+}
+
+// TraceCompleteness is the outcome of checking the structure of a trace before alignment
+type TraceCompleteness struct {
+	Spans  int          `json:"spans"`
+	Traces int          `json:"traces"` // Distinct trace IDs of the spans
+	Issues []TraceIssue `json:"issues"`
+}
+
+// Complete reports whether the trace has no structural issues
+func (c *TraceCompleteness) Complete() bool {
+	return len(c.Issues) == 0
+}
+
+// Counts returns the number of issues of each kind
+func (c *TraceCompleteness) Counts() map[TraceIssueKind]int {
+	counts := make(map[TraceIssueKind]int)
+	for _, issue := range c.Issues {
+		counts[issue.Kind]++
+	}
+	return counts
+}
+
+// String summarizes the issues, e.g. "2 orphan_span, 1 clock_skew"
+func (c *TraceCompleteness) String() string {
+	if c.Complete() {
+		return "no issues"
+	}
+	counts := c.Counts()
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, string(kind))
+	}
+	sort.Strings(kinds)
+	parts := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[TraceIssueKind(kind)], kind))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Err returns an error matching ErrIncompleteTrace when the trace has issues, and nil otherwise
+func (c *TraceCompleteness) Err() error {
+	if c.Complete() {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrIncompleteTrace, c)
+}
+
+// AddSpan adds a span to the trace, recording its ID when the trace already holds a span with
+// it; the later span replaces the earlier one
+func (td *TraceData) AddSpan(span *Span) {
+	if td.Spans == nil {
+		td.Spans = make(map[string]*Span)
+	}
+	if _, exists := td.Spans[span.SpanID]; exists {
+		td.DuplicateSpanIDs = append(td.DuplicateSpanIDs, span.SpanID)
+	}
+	td.Spans[span.SpanID] = span
+}
+
+// CheckCompleteness checks the structure of the trace: spans whose parent is missing, trace IDs
+// without a single root, spans sharing an ID, and children starting before their parent by more
+// than tolerance or ending before they start. Issues are sorted by kind, trace ID and span ID.
+func (td *TraceData) CheckCompleteness(tolerance time.Duration) *TraceCompleteness {
+	completeness := &TraceCompleteness{Spans: len(td.Spans), Issues: []TraceIssue{}}
+	if len(td.Spans) == 0 {
+		return completeness
+	}
+
+	for _, id := range td.DuplicateSpanIDs {
+		completeness.Issues = append(completeness.Issues, TraceIssue{Kind: TraceIssueDuplicateSpanID, SpanID: id,
+			Message: fmt.Sprintf("span ID %s is used by several spans, only the last one is verified", id)})
+	}
+
+	roots := make(map[string]int)
+	for id, span := range td.Spans {
+		if _, seen := roots[span.TraceID]; !seen {
+			roots[span.TraceID] = 0
+		}
+		if span.EndTime < span.StartTime {
+			completeness.Issues = append(completeness.Issues, TraceIssue{Kind: TraceIssueClockSkew, TraceID: span.TraceID, SpanID: id,
+				Message: fmt.Sprintf("span %s ends %s before it starts", id, time.Duration(span.StartTime-span.EndTime))})
+		}
+		if span.ParentID == "" {
+			roots[span.TraceID]++
+			continue
+		}
+		parent, ok := td.Spans[span.ParentID]
+		if !ok {
+			completeness.Issues = append(completeness.Issues, TraceIssue{Kind: TraceIssueOrphanSpan, TraceID: span.TraceID, SpanID: id, ParentID: span.ParentID,
+				Message: fmt.Sprintf("parent %s of span %s is not in the trace", span.ParentID, id)})
+			continue
+		}
+		if skew := time.Duration(parent.StartTime - span.StartTime); skew > tolerance {
+			completeness.Issues = append(completeness.Issues, TraceIssue{Kind: TraceIssueClockSkew, TraceID: span.TraceID, SpanID: id, ParentID: span.ParentID,
+				Message: fmt.Sprintf("span %s starts %s before its parent %s", id, skew, span.ParentID)})
+		}
+	}
+
+	completeness.Traces = len(roots)
+	for traceID, count := range roots {
+		switch {
+		case count == 0:
+			completeness.Issues = append(completeness.Issues, TraceIssue{Kind: TraceIssueMissingRoot, TraceID: traceID,
+				Message: fmt.Sprintf("trace %s has no root span, it was exported partially", traceID)})
+		case count > 1:
+			completeness.Issues = append(completeness.Issues, TraceIssue{Kind: TraceIssueMultipleRoots, TraceID: traceID,
+				Message: fmt.Sprintf("trace %s has %d root spans, only one is expected", traceID, count)})
+		}
+	}
+
+	sort.SliceStable(completeness.Issues, func(i, j int) bool {
+		a, b := completeness.Issues[i], completeness.Issues[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.TraceID != b.TraceID {
+			return a.TraceID < b.TraceID
+		}
+		return a.SpanID < b.SpanID
+	})
+	return completeness
+}
@@ -0,0 +1,131 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceData_CheckCompleteness(t *testing.T) {
+	ms := int64(time.Millisecond)
+	span := func(traceID, id, parentID string, start, end int64) *Span {
+		return &Span{TraceID: traceID, SpanID: id, ParentID: parentID, StartTime: start * ms, EndTime: end * ms}
+	}
+	trace := func(spans ...*Span) *TraceData {
+		td := &TraceData{}
+		for _, s := range spans {
+			td.AddSpan(s)
+		}
+		return td
+	}
+
+	testCases := []struct {
+		name      string
+		trace     *TraceData
+		tolerance time.Duration
+		expected  []TraceIssue
+	}{
+		{
+			name:     "complete",
+			trace:    trace(span("t1", "root", "", 0, 100), span("t1", "child", "root", 10, 90)),
+			expected: []TraceIssue{},
+		},
+		{
+			name:     "several traces with a root each",
+			trace:    trace(span("t1", "a", "", 0, 100), span("t2", "b", "", 0, 100)),
+			expected: []TraceIssue{},
+		},
+		{
+			name:  "orphan span",
+			trace: trace(span("t1", "root", "", 0, 100), span("t1", "child", "gone", 10, 90)),
+			expected: []TraceIssue{{Kind: TraceIssueOrphanSpan, TraceID: "t1", SpanID: "child", ParentID: "gone",
+				Message: "parent gone of span child is not in the trace"}},
+		},
+		{
+			name:  "missing root",
+			trace: trace(span("t1", "child", "gone", 10, 90)),
+			expected: []TraceIssue{
+				{Kind: TraceIssueMissingRoot, TraceID: "t1", Message: "trace t1 has no root span, it was exported partially"},
+				{Kind: TraceIssueOrphanSpan, TraceID: "t1", SpanID: "child", ParentID: "gone", Message: "parent gone of span child is not in the trace"},
+			},
+		},
+		{
+			name:     "multiple roots",
+			trace:    trace(span("t1", "a", "", 0, 100), span("t1", "b", "", 0, 100)),
+			expected: []TraceIssue{{Kind: TraceIssueMultipleRoots, TraceID: "t1", Message: "trace t1 has 2 root spans, only one is expected"}},
+		},
+		{
+			name:  "child starting before its parent",
+			trace: trace(span("t1", "root", "", 100, 200), span("t1", "child", "root", 40, 150)),
+			expected: []TraceIssue{{Kind: TraceIssueClockSkew, TraceID: "t1", SpanID: "child", ParentID: "root",
+				Message: "span child starts 60ms before its parent root"}},
+		},
+		{
+			name:      "skew within tolerance",
+			trace:     trace(span("t1", "root", "", 100, 200), span("t1", "child", "root", 40, 150)),
+			tolerance: 60 * time.Millisecond,
+			expected:  []TraceIssue{},
+		},
+		{
+			name:  "span ending before it starts",
+			trace: trace(span("t1", "root", "", 100, 70)),
+			expected: []TraceIssue{{Kind: TraceIssueClockSkew, TraceID: "t1", SpanID: "root",
+				Message: "span root ends 30ms before it starts"}},
+		},
+		{
+			name:  "duplicate span ID",
+			trace: trace(span("t1", "root", "", 0, 100), span("t1", "child", "root", 10, 20), span("t1", "child", "root", 30, 40)),
+			expected: []TraceIssue{{Kind: TraceIssueDuplicateSpanID, SpanID: "child",
+				Message: "span ID child is used by several spans, only the last one is verified"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			completeness := tc.trace.CheckCompleteness(tc.tolerance)
+			assert.Equal(t, tc.expected, completeness.Issues)
+			assert.Equal(t, len(tc.expected) == 0, completeness.Complete())
+			assert.Equal(t, len(tc.trace.Spans), completeness.Spans)
+		})
+	}
+}
+
+func TestTraceCompleteness_Err(t *testing.T) {
+	complete := &TraceCompleteness{Spans: 2, Traces: 1, Issues: []TraceIssue{}}
+	assert.NoError(t, complete.Err())
+	assert.Equal(t, "no issues", complete.String())
+
+	broken := &TraceCompleteness{Spans: 3, Traces: 1, Issues: []TraceIssue{
+		{Kind: TraceIssueOrphanSpan}, {Kind: TraceIssueClockSkew}, {Kind: TraceIssueOrphanSpan},
+	}}
+	err := broken.Err()
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrIncompleteTrace))
+	assert.Equal(t, "trace is incomplete: 1 clock_skew, 2 orphan_span", err.Error())
+}
+
+func TestFromCompatFormat_DuplicateSpanIDs(t *testing.T) {
+	traceData := FromCompatFormat(&TraceDataCompat{TraceID: "t1", Spans: []*Span{
+		{SpanID: "a", Name: "first"}, {SpanID: "b"}, {SpanID: "a", Name: "second"},
+	}})
+	assert.Len(t, traceData.Spans, 2)
+	assert.Equal(t, "second", traceData.Spans["a"].Name)
+	assert.Equal(t, []string{"a"}, traceData.DuplicateSpanIDs)
+}
@@ -0,0 +1,105 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// stableIDLength is the number of hex characters kept from the hash of an ID
+const stableIDLength = 12
+
+// StableID derives an ID from the parts that identify an item, so the same item gets the same
+// ID in every run regardless of the order results were produced in
+func StableID(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])[:stableIDLength]
+}
+
+// Normalize sorts the details of the result and its operations and assigns stable IDs to the
+// result, its operations and its details. Details are ordered by the priority of their
+// operation, critical first, then by operation, then by the start time and ID of their span,
+// with operation-level details last; details of one span keep the order they were evaluated in.
+func (r *AlignmentResult) Normalize() {
+	r.ID = StableID(r.Service, r.SpecOperationID)
+	sortDetails(r.Details, r.operationRank)
+	assignDetailIDs(r.ID, r.Details)
+
+	for key, operation := range r.OperationResults {
+		if operation == nil {
+			continue
+		}
+		operation.ID = StableID(r.ID, key)
+		sortDetails(operation.Details, r.operationRank)
+		assignDetailIDs(operation.ID, operation.Details)
+	}
+}
+
+// operationRank returns the rank of the priority of an operation of the result
+func (r *AlignmentResult) operationRank(operation string) int {
+	if result := r.OperationResults[operation]; result != nil {
+		return result.Priority.Rank()
+	}
+	return PriorityNormal.Rank()
+}
+
+// sortDetails orders details by the rank of their operation, operation and span, keeping the
+// relative order of the details of one span
+func sortDetails(details []ValidationDetail, rank func(operation string) int) {
+	sort.SliceStable(details, func(i, j int) bool {
+		a, b := details[i], details[j]
+		if a.Operation != b.Operation {
+			if rankA, rankB := rank(a.Operation), rank(b.Operation); rankA != rankB {
+				return rankA < rankB
+			}
+			return a.Operation < b.Operation
+		}
+		// Details about the operation as a whole, e.g. a missing match, follow the span checks
+		if (a.SpanContext == nil) != (b.SpanContext == nil) {
+			return b.SpanContext == nil
+		}
+		aStart, aSpan := detailSpan(a)
+		bStart, bSpan := detailSpan(b)
+		if aStart != bStart {
+			return aStart < bStart
+		}
+		return aSpan < bSpan
+	})
+}
+
+// detailSpan returns the start time and ID of the span a detail was evaluated against
+func detailSpan(detail ValidationDetail) (int64, string) {
+	if detail.SpanContext == nil {
+		return 0, ""
+	}
+	return detail.SpanContext.StartTime, detail.SpanContext.SpanID
+}
+
+// assignDetailIDs gives every detail an ID derived from its owner, operation, type, expression
+// and span; repeated details of the same kind are numbered in order
+func assignDetailIDs(owner string, details []ValidationDetail) {
+	seen := make(map[string]int, len(details))
+	for i := range details {
+		detail := &details[i]
+		_, spanID := detailSpan(*detail)
+		key := strings.Join([]string{detail.Operation, detail.Type, detail.Expression, spanID}, "\x00")
+		seen[key]++
+		detail.ID = StableID(owner, key, strconv.Itoa(seen[key]))
+	}
+}
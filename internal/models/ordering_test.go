@@ -0,0 +1,119 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlignmentResult_Normalize(t *testing.T) {
+	early := &Span{SpanID: "b", StartTime: 1}
+	late := &Span{SpanID: "a", StartTime: 2}
+	newResult := func(details []ValidationDetail) *AlignmentResult {
+		operationDetails := append([]ValidationDetail(nil), details...)
+		return &AlignmentResult{
+			SpecOperationID: "orders",
+			Service:         "orders",
+			Details:         details,
+			OperationResults: map[string]*OperationResult{
+				"GET /orders": {Path: "/orders", Method: "GET", Details: operationDetails},
+			},
+		}
+	}
+
+	first := newResult([]ValidationDetail{
+		{Operation: "GET /orders", Type: "deprecation"},
+		{Operation: "GET /orders", Type: "status_code", SpanContext: late},
+		{Operation: "GET /orders", Type: "postcondition", Expression: "x", SpanContext: late},
+		{Operation: "GET /orders", Type: "status_code", SpanContext: early},
+		{Operation: "DELETE /orders", Type: "status_code", SpanContext: late},
+	})
+	// The same findings collected in a different order
+	second := newResult([]ValidationDetail{
+		{Operation: "DELETE /orders", Type: "status_code", SpanContext: late},
+		{Operation: "GET /orders", Type: "status_code", SpanContext: early},
+		{Operation: "GET /orders", Type: "deprecation"},
+		{Operation: "GET /orders", Type: "status_code", SpanContext: late},
+		{Operation: "GET /orders", Type: "postcondition", Expression: "x", SpanContext: late},
+	})
+	first.Normalize()
+	second.Normalize()
+
+	var order []string
+	for _, detail := range first.Details {
+		spanID := ""
+		if detail.SpanContext != nil {
+			spanID = detail.SpanContext.SpanID
+		}
+		order = append(order, detail.Operation+" "+detail.Type+" "+spanID)
+	}
+	assert.Equal(t, []string{
+		"DELETE /orders status_code a",
+		"GET /orders status_code b",
+		"GET /orders status_code a",
+		"GET /orders postcondition a",
+		"GET /orders deprecation ",
+	}, order, "sorted by operation and span, operation-level details last")
+
+	assert.Equal(t, first, second)
+	assert.Len(t, first.ID, stableIDLength)
+	assert.NotEmpty(t, first.OperationResults["GET /orders"].ID)
+	assert.NotEqual(t, first.ID, first.OperationResults["GET /orders"].ID)
+
+	ids := make(map[string]bool)
+	for _, detail := range first.Details {
+		assert.NotEmpty(t, detail.ID)
+		ids[detail.ID] = true
+	}
+	assert.Len(t, ids, len(first.Details), "detail IDs are unique")
+
+	// Repeated identical details are numbered rather than sharing an ID
+	repeated := &AlignmentResult{SpecOperationID: "op", Details: []ValidationDetail{{Type: "matching"}, {Type: "matching"}}}
+	repeated.Normalize()
+	assert.NotEqual(t, repeated.Details[0].ID, repeated.Details[1].ID)
+}
+
+func TestAlignmentResult_NormalizeByPriority(t *testing.T) {
+	result := &AlignmentResult{
+		SpecOperationID: "orders",
+		Details: []ValidationDetail{
+			{Operation: "DELETE /orders", Type: "status_code"},
+			{Operation: "GET /orders", Type: "status_code"},
+			{Operation: "POST /payments", Type: "status_code"},
+			{Operation: "GET /health", Type: "status_code"},
+		},
+		OperationResults: map[string]*OperationResult{
+			"DELETE /orders": {},
+			"GET /orders":    {Priority: PriorityNormal},
+			"POST /payments": {Priority: PriorityCritical},
+			"GET /health":    {Priority: PriorityLow},
+		},
+	}
+	result.Normalize()
+
+	var order []string
+	for _, detail := range result.Details {
+		order = append(order, detail.Operation)
+	}
+	assert.Equal(t, []string{"POST /payments", "DELETE /orders", "GET /orders", "GET /health"}, order,
+		"critical first, then by operation within a priority")
+}
+
+func TestStableID(t *testing.T) {
+	assert.Equal(t, StableID("orders", "GET /orders"), StableID("orders", "GET /orders"))
+	assert.NotEqual(t, StableID("orders", "GET /orders"), StableID("ordersGET", " /orders"))
+}
@@ -0,0 +1,148 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Path parameter types accepted in PathParamSpec.Type
+const (
+	PathParamString  = "string"
+	PathParamInteger = "integer"
+	PathParamNumber  = "number"
+	PathParamBoolean = "boolean"
+	PathParamUUID    = "uuid"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// anchoredPatterns caches the compiled, anchored form of each pattern, as every matched span
+// is checked against it
+var anchoredPatterns sync.Map
+
+// PathParamSpec constrains the values a path parameter takes in observed requests
+type PathParamSpec struct {
+	Type    string   `json:"type,omitempty" yaml:"type,omitempty"`       // string, integer, number, boolean or uuid
+	Pattern string   `json:"pattern,omitempty" yaml:"pattern,omitempty"` // Regular expression the whole value must match
+	Enum    []string `json:"enum,omitempty" yaml:"enum,omitempty"`       // Allowed values
+}
+
+// Validate checks that the type is known and the pattern compiles
+func (p PathParamSpec) Validate() error {
+	switch p.Type {
+	case "", PathParamString, PathParamInteger, PathParamNumber, PathParamBoolean, PathParamUUID:
+	default:
+		return fmt.Errorf("type '%s' is not valid, must be one of: %s", p.Type,
+			strings.Join([]string{PathParamString, PathParamInteger, PathParamNumber, PathParamBoolean, PathParamUUID}, ", "))
+	}
+	if p.Pattern != "" {
+		if _, err := regexp.Compile(p.Pattern); err != nil {
+			return fmt.Errorf("pattern '%s' is not a valid regular expression: %w", p.Pattern, err)
+		}
+	}
+	return nil
+}
+
+// String describes the constraint, e.g. "uuid" or "integer matching ^[1-9]"
+func (p PathParamSpec) String() string {
+	var parts []string
+	if p.Type != "" {
+		parts = append(parts, p.Type)
+	}
+	if p.Pattern != "" {
+		parts = append(parts, "matching "+p.Pattern)
+	}
+	if len(p.Enum) > 0 {
+		parts = append(parts, "one of "+strings.Join(p.Enum, ", "))
+	}
+	if len(parts) == 0 {
+		return "any value"
+	}
+	return strings.Join(parts, " ")
+}
+
+// Check reports whether a value satisfies the constraint and, if not, why
+func (p PathParamSpec) Check(value string) (bool, string) {
+	switch p.Type {
+	case PathParamInteger:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return false, "not an integer"
+		}
+	case PathParamNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return false, "not a number"
+		}
+	case PathParamBoolean:
+		if value != "true" && value != "false" {
+			return false, "not a boolean"
+		}
+	case PathParamUUID:
+		if !uuidPattern.MatchString(value) {
+			return false, "not a UUID"
+		}
+	}
+
+	if p.Pattern != "" {
+		if pattern := anchoredPattern(p.Pattern); pattern != nil && !pattern.MatchString(value) {
+			return false, "does not match pattern " + p.Pattern
+		}
+	}
+
+	if len(p.Enum) > 0 && !containsString(p.Enum, value) {
+		return false, "not one of " + strings.Join(p.Enum, ", ")
+	}
+	return true, ""
+}
+
+// SortedPathParamNames returns the names of the constrained path parameters in order
+func SortedPathParamNames(parameters map[string]PathParamSpec) []string {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// anchoredPattern compiles a pattern that must match the whole value; nil if it does not
+// compile, which Validate reports
+func anchoredPattern(pattern string) *regexp.Regexp {
+	if cached, ok := anchoredPatterns.Load(pattern); ok {
+		return cached.(*regexp.Regexp)
+	}
+	compiled, err := regexp.Compile(`^(?:` + pattern + `)$`)
+	if err != nil {
+		return nil
+	}
+	anchoredPatterns.Store(pattern, compiled)
+	return compiled
+}
+
+// pathParameterNames returns the names of the {param} segments of an endpoint path
+func pathParameterNames(path string) map[string]bool {
+	names := make(map[string]bool)
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if isPathParameter(segment) {
+			names[segment[1:len(segment)-1]] = true
+		}
+	}
+	return names
+}
@@ -0,0 +1,82 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathParamSpec_Check(t *testing.T) {
+	testCases := []struct {
+		name     string
+		spec     PathParamSpec
+		value    string
+		expected bool
+		reason   string
+	}{
+		{name: "no constraint", value: "anything", expected: true},
+		{name: "integer", spec: PathParamSpec{Type: PathParamInteger}, value: "42", expected: true},
+		{name: "not an integer", spec: PathParamSpec{Type: PathParamInteger}, value: "4.2", reason: "not an integer"},
+		{name: "number", spec: PathParamSpec{Type: PathParamNumber}, value: "4.2", expected: true},
+		{name: "not a number", spec: PathParamSpec{Type: PathParamNumber}, value: "abc", reason: "not a number"},
+		{name: "boolean", spec: PathParamSpec{Type: PathParamBoolean}, value: "false", expected: true},
+		{name: "not a boolean", spec: PathParamSpec{Type: PathParamBoolean}, value: "yes", reason: "not a boolean"},
+		{name: "uuid", spec: PathParamSpec{Type: PathParamUUID}, value: "3F2504E0-4F89-11D3-9A0C-0305E82C3301", expected: true},
+		{name: "not a uuid", spec: PathParamSpec{Type: PathParamUUID}, value: "42", reason: "not a UUID"},
+		{name: "pattern", spec: PathParamSpec{Pattern: "[a-z]+"}, value: "alice", expected: true},
+		{name: "pattern is anchored", spec: PathParamSpec{Pattern: "[a-z]+"}, value: "alice1", reason: "does not match pattern [a-z]+"},
+		{name: "enum", spec: PathParamSpec{Enum: []string{"active", "closed"}}, value: "closed", expected: true},
+		{name: "not in enum", spec: PathParamSpec{Enum: []string{"active", "closed"}}, value: "open", reason: "not one of active, closed"},
+		{name: "type checked before pattern", spec: PathParamSpec{Type: PathParamInteger, Pattern: "[1-9][0-9]*"}, value: "x", reason: "not an integer"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			passed, reason := tc.spec.Check(tc.value)
+			assert.Equal(t, tc.expected, passed)
+			assert.Equal(t, tc.reason, reason)
+		})
+	}
+}
+
+func TestPathParamSpec_ValidateAndString(t *testing.T) {
+	assert.NoError(t, PathParamSpec{Type: PathParamUUID}.Validate())
+	assert.ErrorContains(t, PathParamSpec{Type: "date"}.Validate(), "type 'date' is not valid")
+	assert.ErrorContains(t, PathParamSpec{Pattern: "[a-"}.Validate(), "not a valid regular expression")
+
+	assert.Equal(t, "any value", PathParamSpec{}.String())
+	assert.Equal(t, "integer matching [1-9][0-9]* one of 1, 2",
+		PathParamSpec{Type: PathParamInteger, Pattern: "[1-9][0-9]*", Enum: []string{"1", "2"}}.String())
+}
+
+func TestEndpointSpec_Validate_PathParams(t *testing.T) {
+	endpoint := EndpointSpec{
+		Path: "/users/{id}",
+		Operations: []OperationSpec{{
+			Method:     "GET",
+			Responses:  ResponseSpec{StatusCodes: []int{200}},
+			PathParams: map[string]PathParamSpec{"id": {Type: PathParamUUID}},
+		}},
+	}
+	assert.NoError(t, endpoint.Validate())
+
+	endpoint.Operations[0].PathParams = map[string]PathParamSpec{"userId": {Type: PathParamUUID}}
+	assert.ErrorContains(t, endpoint.Validate(), "operations[0]: pathParams.userId: path /users/{id} has no parameter {userId}")
+
+	endpoint.Operations[0].PathParams = map[string]PathParamSpec{"id": {Type: "date"}}
+	assert.ErrorContains(t, endpoint.Validate(), "pathParams.id: type 'date' is not valid")
+}
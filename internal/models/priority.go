@@ -0,0 +1,71 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "fmt"
+
+// Priority decides when an operation or spec is aligned and reported relative to the others
+type Priority string
+
+const (
+	PriorityCritical Priority = "critical" // Aligned and reported first; its failures stop a run with --fail-fast
+	PriorityHigh     Priority = "high"     // Aligned and reported before normal ones
+	PriorityNormal   Priority = "normal"   // The default
+	PriorityLow      Priority = "low"      // Aligned and reported last
+)
+
+// Validate checks that the priority is empty or one of critical, high, normal and low
+func (p Priority) Validate() error {
+	switch p {
+	case "", PriorityCritical, PriorityHigh, PriorityNormal, PriorityLow:
+		return nil
+	}
+	return fmt.Errorf("priority must be one of %s, %s, %s, %s, got '%s'", PriorityCritical, PriorityHigh, PriorityNormal, PriorityLow, p)
+}
+
+// Rank orders priorities, lowest first: 0 for critical up to 3 for low; empty ranks as normal
+func (p Priority) Rank() int {
+	switch p {
+	case PriorityCritical:
+		return 0
+	case PriorityHigh:
+		return 1
+	case PriorityLow:
+		return 3
+	}
+	return 2
+}
+
+// IsCritical reports whether the priority is critical
+func (p Priority) IsCritical() bool {
+	return p == PriorityCritical
+}
+
+// SpecPriority returns the priority of a spec: its own for an annotated spec, the highest of
+// its operations for a YAML spec
+func (s *ServiceSpec) SpecPriority() Priority {
+	if !s.IsYAMLFormat() {
+		return s.Priority
+	}
+	priority := Priority("")
+	for _, endpoint := range s.Spec.Endpoints {
+		for _, operation := range endpoint.Operations {
+			if operation.Priority != "" && (priority == "" || operation.Priority.Rank() < priority.Rank()) {
+				priority = operation.Priority
+			}
+		}
+	}
+	return priority
+}
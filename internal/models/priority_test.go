@@ -0,0 +1,61 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriority_Validate(t *testing.T) {
+	for _, priority := range []Priority{"", PriorityCritical, PriorityHigh, PriorityNormal, PriorityLow} {
+		assert.NoError(t, priority.Validate())
+	}
+	assert.ErrorContains(t, Priority("urgent").Validate(), "priority must be one of critical, high, normal, low, got 'urgent'")
+}
+
+func TestPriority_Rank(t *testing.T) {
+	assert.Less(t, PriorityCritical.Rank(), PriorityHigh.Rank())
+	assert.Less(t, PriorityHigh.Rank(), PriorityNormal.Rank())
+	assert.Less(t, PriorityNormal.Rank(), PriorityLow.Rank())
+	assert.Equal(t, PriorityNormal.Rank(), Priority("").Rank(), "empty is normal")
+	assert.True(t, PriorityCritical.IsCritical())
+	assert.False(t, PriorityHigh.IsCritical())
+}
+
+func TestServiceSpec_SpecPriority(t *testing.T) {
+	legacy := ServiceSpec{OperationID: "listOrders", Priority: PriorityHigh}
+	assert.Equal(t, PriorityHigh, legacy.SpecPriority())
+
+	yamlSpec := ServiceSpec{
+		APIVersion: "flowspec/v1alpha1",
+		Kind:       "ServiceSpec",
+		Metadata:   &ServiceSpecMetadata{Name: "orders", Version: "v1"},
+		Spec: &ServiceSpecDefinition{Endpoints: []EndpointSpec{
+			{Path: "/orders", Operations: []OperationSpec{{Method: "GET", Priority: PriorityLow}, {Method: "POST"}}},
+		}},
+	}
+	assert.Equal(t, PriorityLow, yamlSpec.SpecPriority(), "the highest declared priority")
+
+	yamlSpec.Spec.Endpoints = append(yamlSpec.Spec.Endpoints, EndpointSpec{
+		Path: "/payments", Operations: []OperationSpec{{Method: "POST", Priority: PriorityCritical}},
+	})
+	assert.Equal(t, PriorityCritical, yamlSpec.SpecPriority())
+
+	yamlSpec.Spec.Endpoints = yamlSpec.Spec.Endpoints[:1]
+	yamlSpec.Spec.Endpoints[0].Operations[0].Priority = ""
+	assert.Equal(t, Priority(""), yamlSpec.SpecPriority(), "no operation declares one")
+}
@@ -0,0 +1,285 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"mime"
+	"sort"
+	"strings"
+)
+
+// Span attributes and event attributes carrying the request body and its media type
+const (
+	RequestContentTypeAttribute = "http.request.header.content-type"
+	RequestBodyAttribute        = "http.request.body"
+)
+
+// schemaTypes are the JSON Schema types a request body schema may declare
+var schemaTypes = []string{"object", "array", "string", "number", "integer", "boolean", "null"}
+
+// RequestSpec describes the request bodies an operation accepts
+type RequestSpec struct {
+	ContentTypes []string               `json:"contentTypes,omitempty" yaml:"contentTypes,omitempty"` // Accepted media types, e.g. application/json or image/*
+	Schema       map[string]interface{} `json:"schema,omitempty" yaml:"schema,omitempty"`             // JSON Schema subset the JSON body must satisfy
+	When         map[string]interface{} `json:"when,omitempty" yaml:"when,omitempty"`                 // JSONLogic guard of the request checks
+}
+
+// Validate checks the media types and the types declared by the schema
+func (r *RequestSpec) Validate() error {
+	for i, contentType := range r.ContentTypes {
+		if _, _, err := mime.ParseMediaType(contentType); err != nil || !strings.Contains(contentType, "/") {
+			return fmt.Errorf("contentTypes[%d]: '%s' is not a valid media type", i, contentType)
+		}
+	}
+	if r.Schema != nil {
+		if err := validateSchemaTypes(r.Schema, ""); err != nil {
+			return fmt.Errorf("schema: %w", err)
+		}
+	}
+	return nil
+}
+
+// AcceptsContentType reports whether a Content-Type header value is one of the accepted media
+// types; parameters such as charset are ignored and "type/*" accepts any subtype
+func (r *RequestSpec) AcceptsContentType(value string) bool {
+	mediaType, _, err := mime.ParseMediaType(value)
+	if err != nil {
+		return false
+	}
+	for _, accepted := range r.ContentTypes {
+		accepted = strings.ToLower(strings.TrimSpace(accepted))
+		if prefix, ok := strings.CutSuffix(accepted, "/*"); ok {
+			if strings.HasPrefix(mediaType, prefix+"/") {
+				return true
+			}
+			continue
+		}
+		if acceptedType, _, err := mime.ParseMediaType(accepted); err == nil && acceptedType == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// SpanRequestContentType returns the Content-Type of the request recorded on a span, or "" if
+// the span does not carry it
+func SpanRequestContentType(span *Span) string {
+	for key, value := range span.Attributes {
+		if !strings.EqualFold(key, RequestContentTypeAttribute) {
+			continue
+		}
+		switch typed := value.(type) {
+		case string:
+			return typed
+		case []string:
+			if len(typed) > 0 {
+				return typed[0]
+			}
+		case []interface{}:
+			if len(typed) > 0 {
+				return fmt.Sprint(typed[0])
+			}
+		}
+	}
+	return ""
+}
+
+// SpanRequestBody returns the request body recorded on a span, either as the http.request.body
+// attribute or as that attribute of one of its events
+func SpanRequestBody(span *Span) (string, bool) {
+	if body, ok := span.Attributes[RequestBodyAttribute].(string); ok {
+		return body, true
+	}
+	for _, event := range span.Events {
+		if body, ok := event.Attributes[RequestBodyAttribute].(string); ok {
+			return body, true
+		}
+	}
+	return "", false
+}
+
+// IsJSONMediaType reports whether a Content-Type denotes JSON, e.g. application/json or
+// application/problem+json
+func IsJSONMediaType(value string) bool {
+	mediaType, _, err := mime.ParseMediaType(value)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// ValidateAgainstSchema checks a decoded JSON value against a JSON Schema subset: type (a name
+// or a list of names), enum, required, properties, additionalProperties and items. It returns
+// the first violation as a JSON Pointer and a message, or "" and "" if the value conforms.
+func ValidateAgainstSchema(schema map[string]interface{}, value interface{}) (string, string) {
+	return validateSchemaValue(schema, value, "")
+}
+
+// validateSchemaValue validates a value found at pointer; properties are visited in name order
+// so the reported violation is stable
+func validateSchemaValue(schema map[string]interface{}, value interface{}, pointer string) (string, string) {
+	switch declared := schema["type"].(type) {
+	case string:
+		if !jsonValueHasType(value, declared) {
+			return pointer, fmt.Sprintf("expected %s, got %s", declared, jsonTypeName(value))
+		}
+	case []interface{}:
+		names := make([]string, 0, len(declared))
+		matched := false
+		for _, name := range declared {
+			names = append(names, fmt.Sprint(name))
+			matched = matched || jsonValueHasType(value, fmt.Sprint(name))
+		}
+		if !matched {
+			return pointer, fmt.Sprintf("expected %s, got %s", strings.Join(names, " or "), jsonTypeName(value))
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		matched := false
+		for _, allowed := range enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return pointer, fmt.Sprintf("value %v is not one of the allowed values", value)
+		}
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, present := typed[fmt.Sprint(name)]; !present {
+					return pointer, fmt.Sprintf("missing required property %q", name)
+				}
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		names := make([]string, 0, len(typed))
+		for name := range typed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			propertyPointer := pointer + "/" + escapeJSONPointer(name)
+			if propertySchema, ok := properties[name].(map[string]interface{}); ok {
+				if at, message := validateSchemaValue(propertySchema, typed[name], propertyPointer); message != "" {
+					return at, message
+				}
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					return propertyPointer, fmt.Sprintf("unknown property %q", name)
+				}
+			case map[string]interface{}:
+				if at, message := validateSchemaValue(additional, typed[name], propertyPointer); message != "" {
+					return at, message
+				}
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range typed {
+				if at, message := validateSchemaValue(items, item, fmt.Sprintf("%s/%d", pointer, i)); message != "" {
+					return at, message
+				}
+			}
+		}
+	}
+	return "", ""
+}
+
+// validateSchemaTypes checks that every type the schema declares is a JSON Schema type
+func validateSchemaTypes(schema map[string]interface{}, pointer string) error {
+	if declared, ok := schema["type"]; ok {
+		name, isString := declared.(string)
+		if !isString || !containsString(schemaTypes, name) {
+			return fmt.Errorf("%stype '%v' is not valid, must be one of: %s", schemaPointerPrefix(pointer), declared, strings.Join(schemaTypes, ", "))
+		}
+	}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if propertySchema, ok := properties[name].(map[string]interface{}); ok {
+				if err := validateSchemaTypes(propertySchema, pointer+"/properties/"+escapeJSONPointer(name)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, key := range []string{"items", "additionalProperties"} {
+		if nested, ok := schema[key].(map[string]interface{}); ok {
+			if err := validateSchemaTypes(nested, pointer+"/"+key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// schemaPointerPrefix prefixes an error about a nested schema with its location
+func schemaPointerPrefix(pointer string) string {
+	if pointer == "" {
+		return ""
+	}
+	return pointer + ": "
+}
+
+// jsonValueHasType reports whether a value decoded by encoding/json has a JSON Schema type
+func jsonValueHasType(value interface{}, declared string) bool {
+	switch declared {
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == float64(int64(number))
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	}
+	return jsonTypeName(value) == declared
+}
+
+// jsonTypeName returns the JSON Schema type of a value decoded by encoding/json
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// escapeJSONPointer escapes a property name for use as a JSON Pointer reference token
+func escapeJSONPointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
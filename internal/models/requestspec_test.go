@@ -0,0 +1,112 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestSpec_AcceptsContentType(t *testing.T) {
+	request := &RequestSpec{ContentTypes: []string{"application/json", "image/*"}}
+
+	testCases := []struct {
+		value    string
+		expected bool
+	}{
+		{"application/json", true},
+		{"Application/JSON; charset=utf-8", true},
+		{"image/png", true},
+		{"application/xml", false},
+		{"text/plain", false},
+		{"not a media type;;", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			assert.Equal(t, tc.expected, request.AcceptsContentType(tc.value))
+		})
+	}
+}
+
+func TestRequestSpec_Validate(t *testing.T) {
+	assert.NoError(t, (&RequestSpec{ContentTypes: []string{"application/json"}, Schema: map[string]interface{}{"type": "object"}}).Validate())
+	assert.ErrorContains(t, (&RequestSpec{ContentTypes: []string{"json"}}).Validate(), "contentTypes[0]: 'json' is not a valid media type")
+	assert.ErrorContains(t, (&RequestSpec{Schema: map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"age": map[string]interface{}{"type": "int"}},
+	}}).Validate(), "schema: /properties/age: type 'int' is not valid")
+}
+
+func TestValidateAgainstSchema(t *testing.T) {
+	schema := map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"name"},
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{"type": "string"},
+			"age":  map[string]interface{}{"type": "integer"},
+			"role": map[string]interface{}{"enum": []interface{}{"admin", "user"}},
+			"tags": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			"note": map[string]interface{}{"type": []interface{}{"string", "null"}},
+		},
+		"additionalProperties": false,
+	}
+
+	testCases := []struct {
+		name            string
+		body            string
+		expectedPointer string
+		expectedMessage string
+	}{
+		{name: "valid", body: `{"name": "alice", "age": 30, "role": "admin", "tags": ["a"]}`},
+		{name: "wrong root type", body: `[]`, expectedMessage: "expected object, got array"},
+		{name: "missing required", body: `{"age": 30}`, expectedMessage: `missing required property "name"`},
+		{name: "not an integer", body: `{"name": "alice", "age": 30.5}`, expectedPointer: "/age", expectedMessage: "expected integer, got number"},
+		{name: "not in enum", body: `{"name": "alice", "role": "root"}`, expectedPointer: "/role", expectedMessage: "value root is not one of the allowed values"},
+		{name: "bad item", body: `{"name": "alice", "tags": ["a", 1]}`, expectedPointer: "/tags/1", expectedMessage: "expected string, got number"},
+		{name: "nullable", body: `{"name": "alice", "note": null}`},
+		{name: "not in type list", body: `{"name": "alice", "note": 1}`, expectedPointer: "/note", expectedMessage: "expected string or null, got number"},
+		{name: "unknown property", body: `{"name": "alice", "a/b": true}`, expectedPointer: "/a~1b", expectedMessage: `unknown property "a/b"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var value interface{}
+			require.NoError(t, json.Unmarshal([]byte(tc.body), &value))
+			pointer, message := ValidateAgainstSchema(schema, value)
+			assert.Equal(t, tc.expectedPointer, pointer)
+			assert.Equal(t, tc.expectedMessage, message)
+		})
+	}
+}
+
+func TestSpanRequestBodyAndContentType(t *testing.T) {
+	span := &Span{
+		Attributes: map[string]interface{}{"HTTP.Request.Header.Content-Type": []interface{}{"application/json"}},
+		Events:     []SpanEvent{{Name: "request", Attributes: map[string]interface{}{RequestBodyAttribute: `{"name": "alice"}`}}},
+	}
+	assert.Equal(t, "application/json", SpanRequestContentType(span))
+	body, ok := SpanRequestBody(span)
+	assert.True(t, ok)
+	assert.Equal(t, `{"name": "alice"}`, body)
+
+	_, ok = SpanRequestBody(&Span{Attributes: map[string]interface{}{}})
+	assert.False(t, ok)
+	assert.True(t, IsJSONMediaType("application/problem+json; charset=utf-8"))
+	assert.False(t, IsJSONMediaType("text/plain"))
+}
@@ -0,0 +1,112 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"net/url"
+	"strings"
+)
+
+// NormalizeRoute rewrites the path parameters of a router template to the contract's {param}
+// syntax: ":id" (Express, Gin), "*path" (Gin catch-all), "{id:[0-9]+}" (Spring, Gorilla) and
+// "<id>" or "<int:id>" (Flask) all become "{id}". A query string is dropped.
+func NormalizeRoute(route string) string {
+	if index := strings.Index(route, "?"); index >= 0 {
+		route = route[:index]
+	}
+	if !strings.ContainsAny(route, ":*{<") {
+		return route
+	}
+
+	segments := strings.Split(route, "/")
+	for i, segment := range segments {
+		if name := routeParameterName(segment); name != "" {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// routeParameterName returns the parameter name of a template segment, or "" if the segment
+// is a literal
+func routeParameterName(segment string) string {
+	switch {
+	case len(segment) > 1 && (segment[0] == ':' || segment[0] == '*'):
+		return strings.TrimSuffix(segment[1:], "?") // Express optional parameters end in "?"
+	case isPathParameter(segment) && len(segment) > 2:
+		name, _, _ := strings.Cut(segment[1:len(segment)-1], ":")
+		return name
+	case strings.HasPrefix(segment, "<") && strings.HasSuffix(segment, ">") && len(segment) > 2:
+		inner := segment[1 : len(segment)-1]
+		if _, name, found := strings.Cut(inner, ":"); found {
+			return name
+		}
+		return inner
+	}
+	return ""
+}
+
+// ExtractPathParameters matches a request path against a {param} template and returns the
+// unescaped value of each parameter, or nil if the path does not fit the template
+func ExtractPathParameters(template, path string) map[string]string {
+	if index := strings.Index(path, "?"); index >= 0 {
+		path = path[:index]
+	}
+	templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(templateSegments) != len(pathSegments) {
+		return nil
+	}
+
+	var parameters map[string]string
+	for i, segment := range templateSegments {
+		if !isPathParameter(segment) {
+			if segment != pathSegments[i] {
+				return nil
+			}
+			continue
+		}
+		value := pathSegments[i]
+		if unescaped, err := url.PathUnescape(value); err == nil {
+			value = unescaped
+		}
+		if parameters == nil {
+			parameters = make(map[string]string)
+		}
+		parameters[segment[1:len(segment)-1]] = value
+	}
+	return parameters
+}
+
+// SpanPathParameters extracts the path parameters of an HTTP span. The template is the given
+// contract path or, when empty, the span's normalized http.route; values come from http.target
+// or url.path.
+func SpanPathParameters(span *Span, template string) map[string]string {
+	if template == "" {
+		route, _ := span.Attributes["http.route"].(string)
+		template = NormalizeRoute(route)
+	}
+	if !strings.Contains(template, "{") {
+		return nil
+	}
+	path, _ := span.Attributes["http.target"].(string)
+	if path == "" {
+		path, _ = span.Attributes["url.path"].(string)
+	}
+	if path == "" {
+		return nil
+	}
+	return ExtractPathParameters(template, path)
+}
@@ -0,0 +1,76 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeRoute(t *testing.T) {
+	testCases := []struct {
+		route    string
+		expected string
+	}{
+		{"/api/users/{id}", "/api/users/{id}"},
+		{"/api/users/:id", "/api/users/{id}"},
+		{"/api/users/:id?", "/api/users/{id}"},
+		{"/static/*filepath", "/static/{filepath}"},
+		{"/api/users/{id:[0-9]+}", "/api/users/{id}"},
+		{"/api/users/<id>", "/api/users/{id}"},
+		{"/api/users/<int:id>/orders/<orderId>", "/api/users/{id}/orders/{orderId}"},
+		{"/api/users/:id?expand=true", "/api/users/{id}"},
+		{"/v1/projects:batchGet", "/v1/projects:batchGet"},
+		{"/api/users", "/api/users"},
+		{"/*", "/*"},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.route, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NormalizeRoute(tc.route))
+		})
+	}
+}
+
+func TestExtractPathParameters(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template string
+		path     string
+		expected map[string]string
+	}{
+		{"single", "/users/{id}", "/users/42", map[string]string{"id": "42"}},
+		{"several", "/users/{id}/orders/{orderId}", "/users/42/orders/7?x=1", map[string]string{"id": "42", "orderId": "7"}},
+		{"escaped", "/files/{name}", "/files/a%2Fb", map[string]string{"name": "a/b"}},
+		{"no parameters", "/users", "/users", nil},
+		{"literal mismatch", "/users/{id}", "/orders/42", nil},
+		{"segment count mismatch", "/users/{id}", "/users/42/orders", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ExtractPathParameters(tc.template, tc.path))
+		})
+	}
+}
+
+func TestSpanPathParameters(t *testing.T) {
+	span := &Span{Attributes: map[string]interface{}{"http.route": "/users/:id", "url.path": "/users/42"}}
+	assert.Equal(t, map[string]string{"id": "42"}, SpanPathParameters(span, ""))
+	assert.Equal(t, map[string]string{"userId": "42"}, SpanPathParameters(span, "/users/{userId}"))
+	assert.Nil(t, SpanPathParameters(&Span{Attributes: map[string]interface{}{"http.target": "/users/42"}}, ""))
+}
@@ -0,0 +1,131 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"math"
+	"strconv"
+)
+
+// Span attributes declaring the probability with which the backend kept the trace of a span
+const (
+	SamplingProbabilityAttribute = "sampling.probability" // Probability in (0, 1]
+	SampleRateAttribute          = "SampleRate"           // One trace kept out of N, as set by Honeycomb-style samplers
+	JaegerSamplerTypeAttribute   = "sampler.type"
+	JaegerSamplerParamAttribute  = "sampler.param" // Probability when sampler.type is probabilistic
+)
+
+// Sources of the sampling rate of a report
+const (
+	SamplingSourceConfig     = "config"     // Set with --trace-sample-rate
+	SamplingSourceAttributes = "attributes" // Declared by span attributes
+)
+
+// SamplingConfidence is the confidence level of the intervals and bounds reported for sampled traces
+const SamplingConfidence = 0.95
+
+// wilsonZ is the standard normal quantile of SamplingConfidence
+const wilsonZ = 1.959964
+
+// Sampling describes the head sampling the verified traces went through. Counts of a report
+// are those of the kept traces; estimates extrapolate them to the traffic they stand for.
+type Sampling struct {
+	Rate       float64 `json:"rate"`             // Share of traces kept (0.0 to 1.0), averaged over traces when declared by spans
+	Source     string  `json:"source"`           // Where the rate comes from, see the SamplingSource constants
+	Traces     int     `json:"traces,omitempty"` // Traces declaring a sampling probability, when read from span attributes
+	Confidence float64 `json:"confidence"`       // Confidence level of the reported intervals and bounds
+}
+
+// RateInterval is a confidence interval of a rate
+type RateInterval struct {
+	Low  float64 `json:"low"`
+	High float64 `json:"high"`
+}
+
+// SpanSampleRate returns the sampling probability declared by the attributes of a span, or 0
+// when it declares none or an invalid one
+func SpanSampleRate(span *Span) float64 {
+	if rate, ok := numericAttribute(span, SamplingProbabilityAttribute); ok && rate > 0 && rate <= 1 {
+		return rate
+	}
+	if keep, ok := numericAttribute(span, SampleRateAttribute); ok && keep >= 1 {
+		return 1 / keep
+	}
+	if span.GetAttribute(JaegerSamplerTypeAttribute) == "probabilistic" {
+		if rate, ok := numericAttribute(span, JaegerSamplerParamAttribute); ok && rate > 0 && rate <= 1 {
+			return rate
+		}
+	}
+	return 0
+}
+
+// SampleRates returns the sampling probability of every trace of the data that declares one,
+// by trace ID. Head sampling keeps or drops a trace as a whole, so the first span of a trace
+// declaring a probability, usually its root, stands for the whole trace.
+func (td *TraceData) SampleRates() map[string]float64 {
+	rates := make(map[string]float64)
+	for _, span := range td.Index().spans {
+		if _, known := rates[span.TraceID]; known {
+			continue
+		}
+		if rate := SpanSampleRate(span); rate > 0 {
+			rates[span.TraceID] = rate
+		}
+	}
+	return rates
+}
+
+// numericAttribute returns an attribute of a span as a number, parsing strings
+func numericAttribute(span *Span, key string) (float64, bool) {
+	switch value := span.GetAttribute(key).(type) {
+	case float64:
+		return value, true
+	case float32:
+		return float64(value), true
+	case int:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case string:
+		number, err := strconv.ParseFloat(value, 64)
+		return number, err == nil
+	}
+	return 0, false
+}
+
+// FailureRateInterval returns the Wilson score interval of failures out of samples at
+// SamplingConfidence, or nil without samples. Unlike the normal approximation it stays within
+// [0, 1] and is meaningful for the few samples a sampled trace often leaves per operation.
+func FailureRateInterval(failures, samples int) *RateInterval {
+	if samples <= 0 {
+		return nil
+	}
+	n := float64(samples)
+	rate := float64(failures) / n
+	z2 := wilsonZ * wilsonZ
+	center := (rate + z2/(2*n)) / (1 + z2/n)
+	margin := wilsonZ / (1 + z2/n) * math.Sqrt(rate*(1-rate)/n+z2/(4*n*n))
+	return &RateInterval{Low: math.Max(0, center-margin), High: math.Min(1, center+margin)}
+}
+
+// UnseenRequestsBound returns the most requests an operation may have received while none of
+// them was kept at a sampling rate, at SamplingConfidence: beyond it, seeing none would be
+// less likely than 1 - SamplingConfidence. It is 0 when every trace is kept.
+func UnseenRequestsBound(rate float64) int {
+	if rate <= 0 || rate >= 1 {
+		return 0
+	}
+	return int(math.Floor(math.Log(1-SamplingConfidence) / math.Log(1-rate)))
+}
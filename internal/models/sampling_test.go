@@ -0,0 +1,92 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpanSampleRate(t *testing.T) {
+	testCases := []struct {
+		name       string
+		attributes map[string]interface{}
+		expected   float64
+	}{
+		{name: "probability", attributes: map[string]interface{}{SamplingProbabilityAttribute: 0.25}, expected: 0.25},
+		{name: "probability as string", attributes: map[string]interface{}{SamplingProbabilityAttribute: "0.5"}, expected: 0.5},
+		{name: "one in N", attributes: map[string]interface{}{SampleRateAttribute: int64(20)}, expected: 0.05},
+		{name: "jaeger probabilistic", attributes: map[string]interface{}{JaegerSamplerTypeAttribute: "probabilistic", JaegerSamplerParamAttribute: 0.1}, expected: 0.1},
+		{name: "jaeger rate limiting", attributes: map[string]interface{}{JaegerSamplerTypeAttribute: "ratelimiting", JaegerSamplerParamAttribute: 2.0}},
+		{name: "out of range", attributes: map[string]interface{}{SamplingProbabilityAttribute: 1.5}},
+		{name: "zero one in N", attributes: map[string]interface{}{SampleRateAttribute: 0}},
+		{name: "none", attributes: map[string]interface{}{"http.method": "GET"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.InDelta(t, tc.expected, SpanSampleRate(&Span{Attributes: tc.attributes}), 1e-12)
+		})
+	}
+}
+
+func TestTraceData_SampleRates(t *testing.T) {
+	traceData := &TraceData{Spans: map[string]*Span{
+		"root":  {SpanID: "root", TraceID: "a", StartTime: 1, Attributes: map[string]interface{}{SamplingProbabilityAttribute: 0.1}},
+		"child": {SpanID: "child", TraceID: "a", StartTime: 2, Attributes: map[string]interface{}{SamplingProbabilityAttribute: 0.5}},
+		"other": {SpanID: "other", TraceID: "b", StartTime: 1, Attributes: map[string]interface{}{SampleRateAttribute: "4"}},
+		"plain": {SpanID: "plain", TraceID: "c", StartTime: 1},
+	}}
+	assert.Equal(t, map[string]float64{"a": 0.1, "b": 0.25}, traceData.SampleRates(), "the first span declaring a rate stands for its trace")
+}
+
+func TestFailureRateInterval(t *testing.T) {
+	assert.Nil(t, FailureRateInterval(0, 0))
+
+	interval := FailureRateInterval(0, 10)
+	require.NotNil(t, interval)
+	assert.Zero(t, interval.Low)
+	assert.InDelta(t, 0.2775, interval.High, 1e-4, "no failure among few samples still allows a high rate")
+
+	interval = FailureRateInterval(5, 10)
+	assert.InDelta(t, 0.2366, interval.Low, 1e-4)
+	assert.InDelta(t, 0.7634, interval.High, 1e-4)
+
+	narrow := FailureRateInterval(500, 1000)
+	assert.Less(t, narrow.High-narrow.Low, interval.High-interval.Low, "more samples narrow the interval")
+	assert.Equal(t, 1.0, FailureRateInterval(3, 3).High)
+}
+
+func TestUnseenRequestsBound(t *testing.T) {
+	assert.Zero(t, UnseenRequestsBound(1))
+	assert.Zero(t, UnseenRequestsBound(0))
+	assert.Equal(t, 28, UnseenRequestsBound(0.1))
+	assert.Equal(t, 298, UnseenRequestsBound(0.01))
+}
+
+func TestOperationSummary_CombineSampling(t *testing.T) {
+	first := &OperationSummary{SampleCount: 0, UnseenRequests: 28}
+	first.combine(&OperationSummary{SampleCount: 0, UnseenRequests: 58})
+	assert.Equal(t, 58, first.UnseenRequests)
+
+	first.combine(&OperationSummary{SampleCount: 2, EstimatedRequests: 20, FailureRateInterval: &RateInterval{Low: 0.1, High: 0.4}})
+	assert.Zero(t, first.UnseenRequests, "a matched span shows the operation was exercised")
+	assert.Equal(t, 20.0, first.EstimatedRequests)
+
+	first.combine(&OperationSummary{SampleCount: 1, EstimatedRequests: 10, FailureRateInterval: &RateInterval{Low: 0.05, High: 0.3}})
+	assert.Equal(t, 30.0, first.EstimatedRequests)
+	assert.Equal(t, &RateInterval{Low: 0.05, High: 0.4}, first.FailureRateInterval)
+}
@@ -17,6 +17,10 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -37,6 +41,9 @@ type ServiceSpec struct {
 	Postconditions map[string]interface{} `json:"postconditions,omitempty"`
 	SourceFile     string                 `json:"sourceFile,omitempty"`
 	LineNumber     int                    `json:"lineNumber,omitempty"`
+	SpanStatus     SpanStatusRules        `json:"spanStatus,omitempty"` // Status rules taking precedence over the engine-wide ones
+	Severity       Severity               `json:"severity,omitempty"`   // Effect of failed assertions; error when empty
+	Priority       Priority               `json:"priority,omitempty"`   // When the spec is aligned and reported; normal when empty
 }
 
 // ServiceSpecMetadata contains metadata for the service specification
@@ -55,28 +62,41 @@ type EndpointSpec struct {
 	Path       string          `json:"path" yaml:"path"`
 	Operations []OperationSpec `json:"operations" yaml:"operations"`
 	Stats      *EndpointStats  `json:"stats,omitempty" yaml:"stats,omitempty"`
+	Tags       []string        `json:"tags,omitempty" yaml:"tags,omitempty"` // Tags of every operation of the endpoint
 }
 
 // OperationSpec defines a specific HTTP operation (method) for an endpoint
 type OperationSpec struct {
-	Method    string             `json:"method" yaml:"method"`
-	Responses ResponseSpec       `json:"responses" yaml:"responses"`
-	Required  RequiredFieldsSpec `json:"required" yaml:"required"`
-	Optional  OptionalFieldsSpec `json:"optional,omitempty" yaml:"optional,omitempty"`
-	Stats     *OperationStats    `json:"stats,omitempty" yaml:"stats,omitempty"`
+	Method     string                   `json:"method" yaml:"method"`
+	Responses  ResponseSpec             `json:"responses" yaml:"responses"`
+	Required   RequiredFieldsSpec       `json:"required" yaml:"required"`
+	Optional   OptionalFieldsSpec       `json:"optional,omitempty" yaml:"optional,omitempty"`
+	Stats      *OperationStats          `json:"stats,omitempty" yaml:"stats,omitempty"`
+	Deprecated bool                     `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Sunset     string                   `json:"sunset,omitempty" yaml:"sunset,omitempty"`         // YYYY-MM-DD or RFC 3339
+	PathParams map[string]PathParamSpec `json:"pathParams,omitempty" yaml:"pathParams,omitempty"` // Constraints on path parameter values, by name
+	Request    *RequestSpec             `json:"request,omitempty" yaml:"request,omitempty"`       // Accepted request media types and body schema
+	Severity   Severity                 `json:"severity,omitempty" yaml:"severity,omitempty"`     // Effect of failed checks; error when empty
+	Priority   Priority                 `json:"priority,omitempty" yaml:"priority,omitempty"`     // When the operation is aligned and reported; normal when empty
+	When       map[string]interface{}   `json:"when,omitempty" yaml:"when,omitempty"`             // JSONLogic guard; checks only apply to spans for which it holds
+	Tags       []string                 `json:"tags,omitempty" yaml:"tags,omitempty"`             // Tags such as critical, in addition to those of the endpoint
+	SourceFile string                   `json:"-" yaml:"-"`                                       // File declaring the operation, set by the parser
+	LineNumber int                      `json:"-" yaml:"-"`                                       // Line declaring the operation, set by the parser
 }
 
 // ResponseSpec defines expected response characteristics
 type ResponseSpec struct {
-	StatusCodes  []int    `json:"statusCodes,omitempty" yaml:"statusCodes,omitempty"`
-	StatusRanges []string `json:"statusRanges,omitempty" yaml:"statusRanges,omitempty"` // e.g., ["2xx","4xx"]
-	Aggregation  string   `json:"aggregation,omitempty" yaml:"aggregation,omitempty"`  // "range"|"exact"|"auto"
+	StatusCodes  []int                  `json:"statusCodes,omitempty" yaml:"statusCodes,omitempty"`
+	StatusRanges []string               `json:"statusRanges,omitempty" yaml:"statusRanges,omitempty"` // e.g., ["2xx","4xx"]
+	Aggregation  string                 `json:"aggregation,omitempty" yaml:"aggregation,omitempty"`   // "range"|"exact"|"auto"
+	When         map[string]interface{} `json:"when,omitempty" yaml:"when,omitempty"`                 // JSONLogic guard of the status check
 }
 
 // RequiredFieldsSpec defines required query parameters and headers
 type RequiredFieldsSpec struct {
-	Query   []string `json:"query" yaml:"query"`
-	Headers []string `json:"headers" yaml:"headers"`
+	Query   []string               `json:"query" yaml:"query"`
+	Headers []string               `json:"headers" yaml:"headers"`
+	When    map[string]interface{} `json:"when,omitempty" yaml:"when,omitempty"` // JSONLogic guard of the presence checks
 }
 
 // OptionalFieldsSpec defines optional query parameters and headers
@@ -94,9 +114,42 @@ type EndpointStats struct {
 
 // OperationStats contains statistics for a specific operation
 type OperationStats struct {
-	SupportCount int       `json:"supportCount" yaml:"supportCount"`
-	FirstSeen    time.Time `json:"firstSeen" yaml:"firstSeen"`
-	LastSeen     time.Time `json:"lastSeen" yaml:"lastSeen"`
+	SupportCount int           `json:"supportCount" yaml:"supportCount"`
+	FirstSeen    time.Time     `json:"firstSeen" yaml:"firstSeen"`
+	LastSeen     time.Time     `json:"lastSeen" yaml:"lastSeen"`
+	Latency      *LatencyStats `json:"latency,omitempty" yaml:"latency,omitempty"` // Only when the traffic logged durations
+}
+
+// LatencyStats summarizes the observed request durations of an operation in milliseconds
+type LatencyStats struct {
+	Samples int     `json:"samples" yaml:"samples"`
+	P50Ms   float64 `json:"p50Ms" yaml:"p50Ms"`
+	P95Ms   float64 `json:"p95Ms" yaml:"p95Ms"`
+	P99Ms   float64 `json:"p99Ms" yaml:"p99Ms"`
+	MaxMs   float64 `json:"maxMs" yaml:"maxMs"`
+}
+
+// NewLatencyStats computes latency statistics from durations in milliseconds, or nil when
+// there are none; percentiles use the nearest-rank method. The durations are sorted in place.
+func NewLatencyStats(durationsMs []float64) *LatencyStats {
+	if len(durationsMs) == 0 {
+		return nil
+	}
+	sort.Float64s(durationsMs)
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p / 100 * float64(len(durationsMs))))
+		if rank < 1 {
+			rank = 1
+		}
+		return durationsMs[rank-1]
+	}
+	return &LatencyStats{
+		Samples: len(durationsMs),
+		P50Ms:   percentile(50),
+		P95Ms:   percentile(95),
+		P99Ms:   percentile(99),
+		MaxMs:   durationsMs[len(durationsMs)-1],
+	}
 }
 
 // ParseResult contains the results of parsing ServiceSpecs from source files
@@ -184,6 +237,15 @@ func (s *ServiceSpec) validateLegacyFormat() error {
 	if s.LineNumber <= 0 {
 		return fmt.Errorf("lineNumber must be positive")
 	}
+	if err := s.SpanStatus.Validate(); err != nil {
+		return fmt.Errorf("spanStatus: %w", err)
+	}
+	if err := s.Severity.Validate(); err != nil {
+		return err
+	}
+	if err := s.Priority.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -197,10 +259,16 @@ func (e *EndpointSpec) Validate() error {
 	}
 	
 	// Validate each operation
+	parameters := pathParameterNames(e.Path)
 	for i, operation := range e.Operations {
 		if err := operation.Validate(); err != nil {
 			return fmt.Errorf("operations[%d]: %w", i, err)
 		}
+		for _, name := range SortedPathParamNames(operation.PathParams) {
+			if !parameters[name] {
+				return fmt.Errorf("operations[%d]: pathParams.%s: path %s has no parameter {%s}", i, name, e.Path, name)
+			}
+		}
 	}
 	
 	return nil
@@ -229,10 +297,51 @@ func (o *OperationSpec) Validate() error {
 	if err := o.Responses.Validate(); err != nil {
 		return fmt.Errorf("responses: %w", err)
 	}
+
+	if o.Sunset != "" {
+		if !o.Deprecated {
+			return fmt.Errorf("sunset requires deprecated: true")
+		}
+		if _, err := o.SunsetTime(); err != nil {
+			return fmt.Errorf("sunset: %w", err)
+		}
+	}
+
+	for _, name := range SortedPathParamNames(o.PathParams) {
+		if err := o.PathParams[name].Validate(); err != nil {
+			return fmt.Errorf("pathParams.%s: %w", name, err)
+		}
+	}
+
+	if o.Request != nil {
+		if err := o.Request.Validate(); err != nil {
+			return fmt.Errorf("request: %w", err)
+		}
+	}
+
+	if err := o.Severity.Validate(); err != nil {
+		return err
+	}
+
+	if err := o.Priority.Validate(); err != nil {
+		return err
+	}
 	
 	return nil
 }
 
+// SunsetTime parses the sunset date; a plain date means the start of that day in UTC
+func (o *OperationSpec) SunsetTime() (time.Time, error) {
+	if sunset, err := time.Parse(time.RFC3339, o.Sunset); err == nil {
+		return sunset, nil
+	}
+	sunset, err := time.Parse("2006-01-02", o.Sunset)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date (YYYY-MM-DD) or RFC 3339 timestamp", o.Sunset)
+	}
+	return sunset, nil
+}
+
 // Validate validates a ResponseSpec
 func (r *ResponseSpec) Validate() error {
 	// Must have either StatusCodes or StatusRanges, but not necessarily both
@@ -309,6 +418,10 @@ type TraceData struct {
 	RootSpan *Span            `json:"rootSpan"`
 	Spans    map[string]*Span `json:"spans"`           // Internal map for O(1) access
 	SpanTree *SpanNode        `json:"spanTree"`
+
+	DuplicateSpanIDs []string `json:"-"` // IDs shared by several ingested spans, see AddSpan
+
+	index *SpanIndex // Built on demand by Index
 }
 
 // TraceDataCompat represents trace data in a format compatible with standard tracing systems
@@ -334,8 +447,9 @@ type Span struct {
 
 // SpanStatus represents the status of a span
 type SpanStatus struct {
-	Code    string `json:"code"` // "OK", "ERROR", "TIMEOUT"
-	Message string `json:"message"`
+	Code     string `json:"code"` // "OK", "ERROR", "TIMEOUT"
+	Message  string `json:"message"`
+	Original string `json:"original,omitempty"` // Code set by the instrumentation, when a status rule mapped it
 }
 
 // SpanEvent represents an event within a span
@@ -497,29 +611,64 @@ func (td *TraceData) ToCompatFormat() *TraceDataCompat {
 
 // FromCompatFormat creates TraceData from a standard-compatible format
 func FromCompatFormat(compat *TraceDataCompat) *TraceData {
-	spans := make(map[string]*Span)
-	for _, span := range compat.Spans {
-		spans[span.SpanID] = span
-	}
-	
-	return &TraceData{
+	traceData := &TraceData{
 		TraceID:  compat.TraceID,
 		RootSpan: compat.RootSpan,
-		Spans:    spans,
+		Spans:    make(map[string]*Span),
 		SpanTree: compat.SpanTree,
 	}
+	for _, span := range compat.Spans {
+		traceData.AddSpan(span)
+	}
+	return traceData
 }
 
 // AlignmentReport-related data structures
 
+// ReportSchemaVersion is the version of the JSON Schema that reports and summaries conform to:
+// the minor version grows with backward-compatible additions, the major version with changes
+// that may break consumers
+const ReportSchemaVersion = "1.0"
+
+// Identifiers of the published JSON Schemas of reports and summaries
+const (
+	ReportSchemaURL  = "https://flowspec.dev/schemas/v1/alignment-report.schema.json"
+	SummarySchemaURL = "https://flowspec.dev/schemas/v1/summary.schema.json"
+)
+
 // AlignmentReport represents the complete report of alignment verification
 type AlignmentReport struct {
-	Summary         AlignmentSummary  `json:"summary"`
-	Results         []AlignmentResult `json:"results"`
-	ExecutionTime   int64             `json:"executionTime"`   // Total execution time in nanoseconds
-	StartTime       int64             `json:"startTime"`       // Start timestamp in Unix nanoseconds
-	EndTime         int64             `json:"endTime"`         // End timestamp in Unix nanoseconds
-	PerformanceInfo PerformanceInfo   `json:"performanceInfo"` // Performance monitoring data
+	SchemaVersion    string               `json:"schemaVersion"`              // ReportSchemaVersion of the report format
+	Summary          AlignmentSummary     `json:"summary"`
+	Results          []AlignmentResult    `json:"results"`
+	ExecutionTime    int64                `json:"executionTime"`              // Total execution time in nanoseconds
+	StartTime        int64                `json:"startTime"`                  // Start timestamp in Unix nanoseconds
+	EndTime          int64                `json:"endTime"`                    // End timestamp in Unix nanoseconds
+	PerformanceInfo  PerformanceInfo      `json:"performanceInfo"`            // Performance monitoring data
+	Coverage         *CoverageReport      `json:"coverage,omitempty"`         // Contract operation coverage (YAML format specs)
+	Unmatched        *UnmatchedSpanReport `json:"unmatched,omitempty"`        // Spans that matched no spec (when requested)
+	Services         []ServiceSummary     `json:"services,omitempty"`         // Per-service breakdown when several services are verified
+	Incomplete       bool                 `json:"incomplete,omitempty"`       // The run was interrupted before every spec finished
+	IncompleteReason string               `json:"incompleteReason,omitempty"` // Why the run stopped, e.g. "interrupted by signal"
+	FailFast         *FailFastStop        `json:"failFast,omitempty"`         // Where the run stopped at a critical failure with --fail-fast
+	TimeWindow       *TimeWindow          `json:"timeWindow,omitempty"`       // Window the verified spans were restricted to
+	Completeness     *TraceCompleteness   `json:"completeness,omitempty"`     // Structural issues of the trace, when it has any
+	ClockSkew        *ClockSkewCorrection `json:"clockSkew,omitempty"`        // Spans moved to correct clock skew, when any was
+	Sampling         *Sampling            `json:"sampling,omitempty"`         // Head sampling of the traces, when they were sampled
+	Tags             []TagSummary         `json:"tags,omitempty"`             // Per-tag breakdown when contract operations are tagged
+	Traces           []TraceSummary       `json:"traces,omitempty"`           // Per-trace breakdown when several traces are verified
+}
+
+// ServiceSummary aggregates alignment results for one service
+type ServiceSummary struct {
+	Service           string `json:"service"`
+	Total             int    `json:"total"`
+	Success           int    `json:"success"`
+	Failed            int    `json:"failed"`
+	Skipped           int    `json:"skipped"`
+	TotalOperations   int    `json:"totalOperations"`
+	FailedOperations  int    `json:"failedOperations"`
+	CoveredOperations int    `json:"coveredOperations"`
 }
 
 // AlignmentSummary provides summary statistics for the alignment report
@@ -534,17 +683,22 @@ type AlignmentSummary struct {
 	AverageExecutionTime int64                      `json:"averageExecutionTime"` // Average execution time per spec in nanoseconds
 	TotalAssertions      int                        `json:"totalAssertions"`      // Total number of assertions evaluated
 	FailedAssertions     int                        `json:"failedAssertions"`     // Number of failed assertions
+	TimedOut             int                        `json:"timedOut,omitempty"`   // Number of specs aborted by timeout (also counted as failed)
+	Warnings             int                        `json:"warnings,omitempty"`   // Number of non-fatal findings, e.g. failed checks of warning severity
+	DuplicateSpans       int                        `json:"duplicateSpans,omitempty"` // Spans dropped on ingestion for sharing the ID of a matched span, so their assertions were not counted twice
+	AmbiguousSpans       int                        `json:"ambiguousSpans,omitempty"` // Spans that matched several operations of a spec
 	OperationSummary     *OperationLevelSummary     `json:"operationSummary,omitempty"` // Operation-level statistics
 }
 
 // OperationLevelSummary provides operation-level statistics for YAML format specs
 type OperationLevelSummary struct {
-	TotalOperations    int                           `json:"totalOperations"`    // Total number of operations across all specs
-	SuccessOperations  int                           `json:"successOperations"`  // Number of successful operations
-	FailedOperations   int                           `json:"failedOperations"`   // Number of failed operations
-	SkippedOperations  int                           `json:"skippedOperations"`  // Number of skipped operations
-	OperationDetails   map[string]*OperationSummary  `json:"operationDetails"`   // Details by operation (path+method)
-	TotalSampleCount   int                           `json:"totalSampleCount"`   // Total number of spans matched across all operations
+	TotalOperations   int                          `json:"totalOperations"`           // Total number of operations across all specs
+	SuccessOperations int                          `json:"successOperations"`         // Number of successful operations
+	FailedOperations  int                          `json:"failedOperations"`          // Number of failed operations
+	SkippedOperations int                          `json:"skippedOperations"`         // Number of skipped operations
+	FlakyOperations   int                          `json:"flakyOperations,omitempty"` // Number of operations within the tolerated flake rate
+	OperationDetails  map[string]*OperationSummary `json:"operationDetails"`          // Details by operation (path+method)
+	TotalSampleCount  int                          `json:"totalSampleCount"`          // Total number of spans matched across all operations
 }
 
 // OperationSummary provides summary for a specific operation
@@ -552,10 +706,51 @@ type OperationSummary struct {
 	Path             string          `json:"path"`
 	Method           string          `json:"method"`
 	Status           AlignmentStatus `json:"status"`
-	SampleCount      int             `json:"sampleCount"`      // Number of spans that matched this operation
-	AssertionsTotal  int             `json:"assertionsTotal"`  // Total assertions for this operation
-	AssertionsPassed int             `json:"assertionsPassed"` // Passed assertions for this operation
-	AssertionsFailed int             `json:"assertionsFailed"` // Failed assertions for this operation
+	SampleCount      int             `json:"sampleCount"`           // Number of spans that matched this operation
+	AssertionsTotal  int             `json:"assertionsTotal"`       // Total assertions for this operation
+	AssertionsPassed int             `json:"assertionsPassed"`      // Passed assertions for this operation
+	AssertionsFailed int             `json:"assertionsFailed"`      // Failed assertions for this operation
+	FailureRate      float64         `json:"failureRate,omitempty"` // Share of matched spans with a failed check
+
+	EstimatedRequests   float64       `json:"estimatedRequests,omitempty"`   // Requests the matched spans stand for on sampled traces
+	FailureRateInterval *RateInterval `json:"failureRateInterval,omitempty"` // Confidence interval of FailureRate on sampled traces
+	UnseenRequests      int           `json:"unseenRequests,omitempty"`      // Most requests that may have been sampled out when no span matched
+}
+
+// CoverageReport describes how many contract operations were exercised by the trace
+type CoverageReport struct {
+	TotalOperations   int                 `json:"totalOperations"`   // Number of operations defined in the contract
+	CoveredOperations int                 `json:"coveredOperations"` // Number of operations with at least one matched span
+	Ratio             float64             `json:"ratio"`             // Covered operations ratio (0.0 to 1.0)
+	Operations        []OperationCoverage `json:"operations"`        // Per-operation coverage, sorted by operation key
+}
+
+// OperationCoverage describes coverage for a single operation
+type OperationCoverage struct {
+	Operation   string `json:"operation"` // Operation identifier (METHOD path)
+	Path        string `json:"path"`
+	Method      string `json:"method"`
+	SampleCount int    `json:"sampleCount"` // Number of spans that matched this operation
+	Covered     bool   `json:"covered"`
+
+	EstimatedRequests float64 `json:"estimatedRequests,omitempty"` // Requests the matched spans stand for on sampled traces
+	UnseenRequests    int     `json:"unseenRequests,omitempty"`    // Most requests that may have been sampled out, when not covered
+}
+
+// UnmatchedSpanReport lists spans that matched no spec, grouped to surface undocumented endpoints
+type UnmatchedSpanReport struct {
+	TotalSpans int                  `json:"totalSpans"` // Number of unmatched spans
+	Groups     []UnmatchedSpanGroup `json:"groups"`     // Groups ordered by descending count
+}
+
+// UnmatchedSpanGroup aggregates unmatched spans sharing name, method, route and status
+type UnmatchedSpanGroup struct {
+	Name          string   `json:"name"`
+	Method        string   `json:"method,omitempty"`
+	Route         string   `json:"route,omitempty"`
+	Status        string   `json:"status,omitempty"`
+	Count         int      `json:"count"`
+	SampleSpanIDs []string `json:"sampleSpanIds"` // A few example span IDs for lookup in trace tools
 }
 
 // PerformanceInfo contains performance monitoring data
@@ -570,18 +765,29 @@ type PerformanceInfo struct {
 
 // AlignmentResult represents the result of aligning a single ServiceSpec with trace data
 type AlignmentResult struct {
-	SpecOperationID  string                        `json:"specOperationId"`
-	Status           AlignmentStatus               `json:"status"`
-	Details          []ValidationDetail            `json:"details"`
-	ExecutionTime    int64                         `json:"executionTime"`          // Duration in nanoseconds
-	StartTime        int64                         `json:"startTime"`              // Start timestamp in Unix nanoseconds
-	EndTime          int64                         `json:"endTime"`                // End timestamp in Unix nanoseconds
-	MatchedSpans     []string                      `json:"matchedSpans"`           // IDs of spans that matched this spec
-	AssertionsTotal  int                           `json:"assertionsTotal"`        // Total number of assertions evaluated
-	AssertionsPassed int                           `json:"assertionsPassed"`       // Number of assertions that passed
-	AssertionsFailed int                           `json:"assertionsFailed"`       // Number of assertions that failed
-	ErrorMessage     string                        `json:"errorMessage,omitempty"` // Error message if processing failed
-	OperationResults map[string]*OperationResult   `json:"operationResults,omitempty"` // Results by operation (path+method)
+	ID               string                      `json:"id,omitempty"` // Stable across runs, see Normalize
+	SpecOperationID  string                      `json:"specOperationId"`
+	Service          string                      `json:"service,omitempty"` // Service name for YAML format specs
+	Status           AlignmentStatus             `json:"status"`
+	Details          []ValidationDetail          `json:"details"`
+	ExecutionTime    int64                       `json:"executionTime"`              // Duration in nanoseconds
+	StartTime        int64                       `json:"startTime"`                  // Start timestamp in Unix nanoseconds
+	EndTime          int64                       `json:"endTime"`                    // End timestamp in Unix nanoseconds
+	MatchedSpans     []string                    `json:"matchedSpans"`               // IDs of spans that matched this spec
+	AssertionsTotal  int                         `json:"assertionsTotal"`            // Total number of assertions evaluated
+	AssertionsPassed int                         `json:"assertionsPassed"`           // Number of assertions that passed
+	AssertionsFailed int                         `json:"assertionsFailed"`           // Number of assertions that failed
+	ErrorMessage     string                      `json:"errorMessage,omitempty"`     // Error message if processing failed
+	OperationResults map[string]*OperationResult `json:"operationResults,omitempty"` // Results by operation (path+method)
+	Explanations     []MatchExplanation          `json:"explanations,omitempty"`     // Matching decisions per span for legacy specs (explain mode)
+	Warnings         []string                    `json:"warnings,omitempty"`         // Non-fatal findings, e.g. traffic to deprecated operations
+	SourceFile       string                      `json:"sourceFile,omitempty"`       // File declaring the spec
+	LineNumber       int                         `json:"lineNumber,omitempty"`       // Line declaring the spec
+	FailureGroups    []FailureGroup              `json:"failureGroups,omitempty"`    // Failed details grouped by likely root cause
+	TraceID          string                      `json:"traceId,omitempty"`          // Trace the spec was aligned with, when several traces are verified
+	Priority         Priority                    `json:"priority,omitempty"`         // Priority of the spec, the highest of its operations for YAML specs
+	DuplicateSpans   int                         `json:"duplicateSpans,omitempty"`   // Spans dropped on ingestion for sharing the ID of a matched span
+	Ambiguities      []MatchAmbiguity            `json:"ambiguities,omitempty"`      // Spans that matched several operations of the spec
 }
 
 // AlignmentStatus represents the status of an alignment result
@@ -591,10 +797,13 @@ const (
 	StatusSuccess AlignmentStatus = "SUCCESS"
 	StatusFailed  AlignmentStatus = "FAILED"
 	StatusSkipped AlignmentStatus = "SKIPPED"
+	StatusTimeout AlignmentStatus = "TIMEOUT"
+	StatusFlaky   AlignmentStatus = "FLAKY" // Operations only: a few matched spans failed, within the tolerated flake rate
 )
 
 // OperationResult represents the result of validating a specific operation (path+method)
 type OperationResult struct {
+	ID               string             `json:"id,omitempty"` // Stable across runs, see AlignmentResult.Normalize
 	Path             string             `json:"path"`
 	Method           string             `json:"method"`
 	Status           AlignmentStatus    `json:"status"`
@@ -603,12 +812,103 @@ type OperationResult struct {
 	AssertionsTotal  int                `json:"assertionsTotal"`
 	AssertionsPassed int                `json:"assertionsPassed"`
 	AssertionsFailed int                `json:"assertionsFailed"`
-	SampleCount      int                `json:"sampleCount"` // Number of spans that matched this operation
+	SampleCount      int                `json:"sampleCount"`            // Number of spans that matched this operation
+	FailureRate      float64            `json:"failureRate,omitempty"`  // Share of matched spans with a failed check
+	Explanations     []MatchExplanation `json:"explanations,omitempty"` // Matching decisions per span (explain mode)
+	Warnings         []string           `json:"warnings,omitempty"`     // Non-fatal findings, e.g. traffic to a deprecated operation
+	SourceFile       string             `json:"sourceFile,omitempty"`   // File declaring the operation
+	LineNumber       int                `json:"lineNumber,omitempty"`   // Line declaring the operation
+	Tags             []string           `json:"tags,omitempty"`         // Tags of the operation and of its endpoint
+	Priority         Priority           `json:"priority,omitempty"`     // Priority declared by the operation
+	DuplicateSpans   int                `json:"duplicateSpans,omitempty"` // Spans dropped on ingestion for sharing the ID of a matched span
+
+	EstimatedRequests   float64       `json:"estimatedRequests,omitempty"`   // Requests the matched spans stand for on sampled traces
+	FailureRateInterval *RateInterval `json:"failureRateInterval,omitempty"` // Confidence interval of FailureRate on sampled traces
+	UnseenRequests      int           `json:"unseenRequests,omitempty"`      // Most requests that may have been sampled out when no span matched
+}
+
+// Match decision outcomes
+const (
+	MatchOutcomeAccepted = "accepted"
+	MatchOutcomeRejected = "rejected"
+	MatchOutcomeInfo     = "info"
+)
+
+// MatchExplanation records why a candidate span was accepted or rejected for a spec operation
+type MatchExplanation struct {
+	SpanID     string          `json:"spanId"`
+	SpanName   string          `json:"spanName"`
+	Accepted   bool            `json:"accepted"`
+	AcceptedBy string          `json:"acceptedBy,omitempty"` // Matcher that accepted the span
+	Decisions  []MatchDecision `json:"decisions"`
+}
+
+// FailFastStop records where a run with --fail-fast stopped at the first failure of critical
+// priority
+type FailFastStop struct {
+	Spec       string `json:"spec"`                // Spec with the critical failure
+	Operation  string `json:"operation,omitempty"` // Critical operation that failed, for specs with operations
+	NotAligned int    `json:"notAligned"`          // Specs left unaligned by the stop
+}
+
+// MatchAmbiguity records a span that matched several operations of a spec, such as
+// GET /users/{id} and GET /users/profile, and how the ambiguity policy resolved it
+type MatchAmbiguity struct {
+	SpanID     string   `json:"spanId"`
+	SpanName   string   `json:"spanName"`
+	Operations []string `json:"operations"`           // Matched operations, in the order of the spec
+	ResolvedTo string   `json:"resolvedTo,omitempty"` // Only operation that evaluated the span; empty when all of them did
+	Policy     string   `json:"policy"`               // Ambiguity policy applied: most-specific-wins, fail or all
+}
+
+// MatchDecision is a single matcher's verdict on a span
+type MatchDecision struct {
+	Matcher string `json:"matcher"`
+	Outcome string `json:"outcome"` // "accepted" | "rejected" | "info"
+	Reason  string `json:"reason"`
+}
+
+// NewMatchExplanation creates an empty explanation for the given span
+func NewMatchExplanation(span *Span) *MatchExplanation {
+	return &MatchExplanation{
+		SpanID:    span.SpanID,
+		SpanName:  span.Name,
+		Decisions: []MatchDecision{},
+	}
+}
+
+// Accept records that a matcher accepted the span; it is a no-op on a nil explanation
+func (me *MatchExplanation) Accept(matcher, reason string) {
+	if me == nil {
+		return
+	}
+	if !me.Accepted {
+		me.Accepted = true
+		me.AcceptedBy = matcher
+	}
+	me.Decisions = append(me.Decisions, MatchDecision{Matcher: matcher, Outcome: MatchOutcomeAccepted, Reason: reason})
+}
+
+// Reject records that a matcher rejected the span; it is a no-op on a nil explanation
+func (me *MatchExplanation) Reject(matcher, reason string) {
+	if me == nil {
+		return
+	}
+	me.Decisions = append(me.Decisions, MatchDecision{Matcher: matcher, Outcome: MatchOutcomeRejected, Reason: reason})
+}
+
+// Note records an informational decision; it is a no-op on a nil explanation
+func (me *MatchExplanation) Note(matcher, reason string) {
+	if me == nil {
+		return
+	}
+	me.Decisions = append(me.Decisions, MatchDecision{Matcher: matcher, Outcome: MatchOutcomeInfo, Reason: reason})
 }
 
 // ValidationDetail provides detailed information about a specific validation
 type ValidationDetail struct {
-	Type          string                 `json:"type"` // "precondition" | "postcondition" | "status_code" | "required_header" | "required_query"
+	ID            string                 `json:"id,omitempty"` // Stable across runs, see AlignmentResult.Normalize
+	Type          string                 `json:"type"`         // "precondition" | "postcondition" | "status_code" | "required_header" | "required_query"
 	Expression    string                 `json:"expression"`
 	Expected      interface{}            `json:"expected"`
 	Actual        interface{}            `json:"actual"`
@@ -618,10 +918,69 @@ type ValidationDetail struct {
 	ContextInfo   map[string]interface{} `json:"contextInfo,omitempty"`   // Additional context information for debugging
 	Suggestions   []string               `json:"suggestions,omitempty"`   // Actionable suggestions for fixing the failure
 	Operation     string                 `json:"operation,omitempty"`     // Operation identifier (path+method) for YAML format
+	Passed        *bool                  `json:"passed,omitempty"`        // Outcome when it is not expected == actual, e.g. a code checked against a set
+	Severity      Severity               `json:"severity,omitempty"`      // Severity of the operation or spec checked; error when empty
+}
+
+// FailureGroup collects failed validation details that share the same operation, expression and
+// expected value, which usually means they have one root cause
+type FailureGroup struct {
+	Operation      string           `json:"operation,omitempty"`
+	Type           string           `json:"type"`
+	Expression     string           `json:"expression,omitempty"`
+	Expected       interface{}      `json:"expected"`
+	Count          int              `json:"count"`
+	Representative ValidationDetail `json:"representative"` // First failure of the group, with its span context
+}
+
+// GroupFailures groups the failed details, largest group first and otherwise in order of first occurrence
+func GroupFailures(details []ValidationDetail) []FailureGroup {
+	var groups []FailureGroup
+	index := make(map[string]int)
+	for _, detail := range details {
+		if detail.Type == "matching" || detail.IsPassed() {
+			continue
+		}
+		key := detail.GroupKey()
+		if i, ok := index[key]; ok {
+			groups[i].Count++
+			continue
+		}
+		index[key] = len(groups)
+		groups = append(groups, FailureGroup{
+			Operation:      detail.Operation,
+			Type:           detail.Type,
+			Expression:     detail.Expression,
+			Expected:       detail.Expected,
+			Count:          1,
+			Representative: detail,
+		})
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Count > groups[j].Count
+	})
+	return groups
+}
+
+// GroupKey identifies the failure group of a detail; expected values may be maps or slices,
+// so they are compared by their JSON encoding. Required field checks share a generic expression,
+// so their message, which names the field, is part of the key.
+func (vd *ValidationDetail) GroupKey() string {
+	expected, err := json.Marshal(vd.Expected)
+	if err != nil {
+		expected = []byte(fmt.Sprintf("%v", vd.Expected))
+	}
+	parts := []string{vd.Operation, vd.Type, vd.Expression, string(expected)}
+	if vd.Type == "required_header" || vd.Type == "required_query" {
+		parts = append(parts, vd.Message)
+	}
+	return strings.Join(parts, "\x00")
 }
 
 // AddResult adds an alignment result to the report and updates the summary
 func (ar *AlignmentReport) AddResult(result AlignmentResult) {
+	result.RollUpOperations()
 	ar.Results = append(ar.Results, result)
 	ar.updateSummary()
 }
@@ -632,6 +991,10 @@ func (ar *AlignmentReport) updateSummary() {
 	success := 0
 	failed := 0
 	skipped := 0
+	timedOut := 0
+	warnings := 0
+	duplicateSpans := 0
+	ambiguousSpans := 0
 	totalExecutionTime := int64(0)
 	totalAssertions := 0
 	failedAssertions := 0
@@ -642,8 +1005,12 @@ func (ar *AlignmentReport) updateSummary() {
 	successOperations := 0
 	failedOperations := 0
 	skippedOperations := 0
+	flakyOperations := 0
 	totalSampleCount := 0
 
+	// Operation keys are only unique within a service
+	multiService := countServices(ar.Results) > 1
+
 	for _, result := range ar.Results {
 		switch result.Status {
 		case StatusSuccess:
@@ -652,8 +1019,15 @@ func (ar *AlignmentReport) updateSummary() {
 			failed++
 		case StatusSkipped:
 			skipped++
+		case StatusTimeout:
+			// Timeouts are failures for exit code purposes
+			failed++
+			timedOut++
 		}
 
+		warnings += len(result.Warnings)
+		duplicateSpans += result.DuplicateSpans
+		ambiguousSpans += len(result.Ambiguities)
 		totalExecutionTime += result.ExecutionTime
 		totalAssertions += result.AssertionsTotal
 		failedAssertions += result.AssertionsFailed
@@ -671,10 +1045,16 @@ func (ar *AlignmentReport) updateSummary() {
 					failedOperations++
 				case StatusSkipped:
 					skippedOperations++
+				case StatusFlaky:
+					flakyOperations++
+				}
+
+				if multiService && result.Service != "" {
+					operationKey = result.Service + " " + operationKey
 				}
 
 				// Create operation summary
-				operationDetails[operationKey] = &OperationSummary{
+				summary := &OperationSummary{
 					Path:             operationResult.Path,
 					Method:           operationResult.Method,
 					Status:           operationResult.Status,
@@ -682,7 +1062,18 @@ func (ar *AlignmentReport) updateSummary() {
 					AssertionsTotal:  operationResult.AssertionsTotal,
 					AssertionsPassed: operationResult.AssertionsPassed,
 					AssertionsFailed: operationResult.AssertionsFailed,
+					FailureRate:      operationResult.FailureRate,
+
+					EstimatedRequests:   operationResult.EstimatedRequests,
+					FailureRateInterval: operationResult.FailureRateInterval,
+					UnseenRequests:      operationResult.UnseenRequests,
+				}
+				// The same operation aligned with several traces is summarized once
+				if existing, exists := operationDetails[operationKey]; exists {
+					existing.combine(summary)
+					continue
 				}
+				operationDetails[operationKey] = summary
 			}
 		}
 	}
@@ -694,6 +1085,10 @@ func (ar *AlignmentReport) updateSummary() {
 		Skipped:          skipped,
 		TotalAssertions:  totalAssertions,
 		FailedAssertions: failedAssertions,
+		TimedOut:         timedOut,
+		Warnings:         warnings,
+		DuplicateSpans:   duplicateSpans,
+		AmbiguousSpans:   ambiguousSpans,
 	}
 
 	// Add operation-level summary if we have operation results
@@ -703,11 +1098,19 @@ func (ar *AlignmentReport) updateSummary() {
 			SuccessOperations: successOperations,
 			FailedOperations:  failedOperations,
 			SkippedOperations: skippedOperations,
+			FlakyOperations:   flakyOperations,
 			OperationDetails:  operationDetails,
 			TotalSampleCount:  totalSampleCount,
 		}
 	}
 
+	ar.Coverage = calculateCoverage(operationDetails)
+	ar.Services = nil
+	if multiService {
+		ar.Services = summarizeServices(ar.Results)
+	}
+	ar.Tags = summarizeTags(ar.Results)
+
 	// Calculate rates
 	if total > 0 {
 		ar.Summary.SuccessRate = float64(success) / float64(total)
@@ -717,11 +1120,145 @@ func (ar *AlignmentReport) updateSummary() {
 	}
 }
 
+// countServices returns the number of distinct services among the results
+func countServices(results []AlignmentResult) int {
+	services := make(map[string]bool)
+	for _, result := range results {
+		if result.Service != "" {
+			services[result.Service] = true
+		}
+	}
+	return len(services)
+}
+
+// summarizeServices aggregates results per service, ordered by service name
+func summarizeServices(results []AlignmentResult) []ServiceSummary {
+	summaries := make(map[string]*ServiceSummary)
+	for _, result := range results {
+		if result.Service == "" {
+			continue
+		}
+		summary, exists := summaries[result.Service]
+		if !exists {
+			summary = &ServiceSummary{Service: result.Service}
+			summaries[result.Service] = summary
+		}
+
+		summary.Total++
+		switch result.Status {
+		case StatusSuccess:
+			summary.Success++
+		case StatusFailed, StatusTimeout:
+			summary.Failed++
+		case StatusSkipped:
+			summary.Skipped++
+		}
+
+		for _, operationResult := range result.OperationResults {
+			summary.TotalOperations++
+			if operationResult.Status == StatusFailed {
+				summary.FailedOperations++
+			}
+			if operationResult.SampleCount > 0 {
+				summary.CoveredOperations++
+			}
+		}
+	}
+
+	services := make([]ServiceSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		services = append(services, *summary)
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Service < services[j].Service })
+	return services
+}
+
+// calculateCoverage builds the coverage report from operation summaries, or nil if there are none
+func calculateCoverage(operationDetails map[string]*OperationSummary) *CoverageReport {
+	if len(operationDetails) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(operationDetails))
+	for key := range operationDetails {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	coverage := &CoverageReport{
+		TotalOperations: len(keys),
+		Operations:      make([]OperationCoverage, 0, len(keys)),
+	}
+	for _, key := range keys {
+		summary := operationDetails[key]
+		covered := summary.SampleCount > 0
+		if covered {
+			coverage.CoveredOperations++
+		}
+		coverage.Operations = append(coverage.Operations, OperationCoverage{
+			Operation:   key,
+			Path:        summary.Path,
+			Method:      summary.Method,
+			SampleCount: summary.SampleCount,
+			Covered:     covered,
+
+			EstimatedRequests: summary.EstimatedRequests,
+			UnseenRequests:    summary.UnseenRequests,
+		})
+	}
+	coverage.Ratio = float64(coverage.CoveredOperations) / float64(coverage.TotalOperations)
+
+	return coverage
+}
+
+// GetUncoveredOperations returns operations that no span exercised
+func (cr *CoverageReport) GetUncoveredOperations() []OperationCoverage {
+	var uncovered []OperationCoverage
+	for _, operation := range cr.Operations {
+		if !operation.Covered {
+			uncovered = append(uncovered, operation)
+		}
+	}
+	return uncovered
+}
+
+// MeetsThreshold returns true if the coverage ratio is at least minRatio (0.0 to 1.0)
+func (cr *CoverageReport) MeetsThreshold(minRatio float64) bool {
+	return cr.Ratio >= minRatio
+}
+
+// ParseCoverageThreshold parses a coverage threshold such as "80%", "80" or "0.8" into a ratio
+func ParseCoverageThreshold(value string) (float64, error) {
+	trimmed := strings.TrimSpace(value)
+	isPercent := strings.HasSuffix(trimmed, "%")
+	trimmed = strings.TrimSuffix(trimmed, "%")
+
+	number, err := strconv.ParseFloat(strings.TrimSpace(trimmed), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid coverage threshold %q: %w", value, err)
+	}
+
+	// Values above 1 are treated as percentages
+	if isPercent || number > 1 {
+		number = number / 100
+	}
+	if number < 0 || number > 1 {
+		return 0, fmt.Errorf("coverage threshold %q must be between 0%% and 100%%", value)
+	}
+
+	return number, nil
+}
+
 // HasFailures returns true if any alignment results have failed
 func (ar *AlignmentReport) HasFailures() bool {
 	return ar.Summary.Failed > 0
 }
 
+// HasWarnings returns true if any alignment result has non-fatal findings
+func (ar *AlignmentReport) HasWarnings() bool {
+	return ar.Summary.Warnings > 0
+}
+
 // GetSuccessRate returns the success rate as a percentage (0.0 to 1.0)
 func (ar *AlignmentReport) GetSuccessRate() float64 {
 	if ar.Summary.Total == 0 {
@@ -746,7 +1283,7 @@ func (as AlignmentStatus) String() string {
 // IsValid returns true if the AlignmentStatus is one of the valid values
 func (as AlignmentStatus) IsValid() bool {
 	switch as {
-	case StatusSuccess, StatusFailed, StatusSkipped:
+	case StatusSuccess, StatusFailed, StatusSkipped, StatusTimeout, StatusFlaky:
 		return true
 	default:
 		return false
@@ -784,12 +1321,12 @@ func (ar *AlignmentResult) updateStatus() {
 
 		totalAssertions++
 
-		// Check if this assertion passed or failed
+		// Check if this assertion passed or failed; failures below error severity do not fail the result
 		if detail.IsPassed() {
 			passedAssertions++
 		} else {
 			failedAssertions++
-			hasFailure = true
+			hasFailure = hasFailure || detail.Severity.Blocks()
 		}
 	}
 
@@ -807,11 +1344,50 @@ func (ar *AlignmentResult) updateStatus() {
 	}
 }
 
+// RollUpOperations derives the status and assertion counts of a result from its operation
+// results, so a spec with operations is reported exactly as its operations are: FAILED if any
+// operation failed, SUCCESS if any passed or was flaky, SKIPPED otherwise. Timed out results and
+// results without operations are left unchanged.
+func (ar *AlignmentResult) RollUpOperations() {
+	if len(ar.OperationResults) == 0 || ar.Status == StatusTimeout {
+		return
+	}
+
+	ar.AssertionsTotal = 0
+	ar.AssertionsPassed = 0
+	ar.AssertionsFailed = 0
+	failed := false
+	succeeded := false
+	for _, operation := range ar.OperationResults {
+		if operation == nil {
+			continue
+		}
+		ar.AssertionsTotal += operation.AssertionsTotal
+		ar.AssertionsPassed += operation.AssertionsPassed
+		ar.AssertionsFailed += operation.AssertionsFailed
+		switch operation.Status {
+		case StatusFailed, StatusTimeout:
+			failed = true
+		case StatusSuccess, StatusFlaky:
+			succeeded = true
+		}
+	}
+
+	switch {
+	case failed:
+		ar.Status = StatusFailed
+	case succeeded:
+		ar.Status = StatusSuccess
+	default:
+		ar.Status = StatusSkipped
+	}
+}
+
 // GetFailedDetails returns only the validation details that failed
 func (ar *AlignmentResult) GetFailedDetails() []ValidationDetail {
 	var failed []ValidationDetail
 	for _, detail := range ar.Details {
-		if detail.Expected != detail.Actual {
+		if !detail.IsPassed() {
 			failed = append(failed, detail)
 		}
 	}
@@ -846,16 +1422,21 @@ func (vd *ValidationDetail) String() string {
 		vd.Type, vd.Expression, vd.Expected, vd.Actual)
 }
 
-// IsPassed returns true if the validation detail passed (expected equals actual)
+// IsPassed returns true if the validation detail passed: its recorded outcome if set, otherwise
+// whether expected equals actual
 func (vd *ValidationDetail) IsPassed() bool {
+	if vd.Passed != nil {
+		return *vd.Passed
+	}
 	return vd.Expected == vd.Actual
 }
 
 // NewAlignmentReport creates a new empty alignment report
 func NewAlignmentReport() *AlignmentReport {
 	return &AlignmentReport{
-		Summary: AlignmentSummary{},
-		Results: []AlignmentResult{},
+		SchemaVersion: ReportSchemaVersion,
+		Summary:       AlignmentSummary{},
+		Results:       []AlignmentResult{},
 	}
 }
 
@@ -15,6 +15,7 @@
 package models
 
 import (
+	"fmt"
 	"testing"
 	"time"
 )
@@ -251,6 +252,44 @@ func TestOperationSpec_Validate(t *testing.T) {
 	}
 }
 
+func TestOperationSpec_Sunset(t *testing.T) {
+	tests := []struct {
+		name       string
+		deprecated bool
+		sunset     string
+		wantErr    bool
+		want       time.Time
+	}{
+		{name: "date", deprecated: true, sunset: "2025-06-30", want: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)},
+		{name: "timestamp", deprecated: true, sunset: "2025-06-30T12:00:00Z", want: time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)},
+		{name: "invalid date", deprecated: true, sunset: "next year", wantErr: true},
+		{name: "not deprecated", sunset: "2025-06-30", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := OperationSpec{
+				Method:     "GET",
+				Responses:  ResponseSpec{StatusCodes: []int{200}},
+				Deprecated: tt.deprecated,
+				Sunset:     tt.sunset,
+			}
+
+			err := op.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			sunset, err := op.SunsetTime()
+			if err != nil || !sunset.Equal(tt.want) {
+				t.Errorf("SunsetTime() = %v, %v, want %v", sunset, err, tt.want)
+			}
+		})
+	}
+}
+
 func TestEndpointStats(t *testing.T) {
 	now := time.Now()
 	stats := EndpointStats{
@@ -266,4 +305,273 @@ func TestEndpointStats(t *testing.T) {
 	if stats.LastSeen.Before(stats.FirstSeen) {
 		t.Error("Expected LastSeen to be after FirstSeen")
 	}
-}
\ No newline at end of file
+}
+
+func TestNewLatencyStats(t *testing.T) {
+	if stats := NewLatencyStats(nil); stats != nil {
+		t.Errorf("Expected no latency stats without durations, got %+v", stats)
+	}
+
+	durations := make([]float64, 0, 100)
+	for i := 100; i >= 1; i-- {
+		durations = append(durations, float64(i))
+	}
+	stats := NewLatencyStats(durations)
+	expected := LatencyStats{Samples: 100, P50Ms: 50, P95Ms: 95, P99Ms: 99, MaxMs: 100}
+	if stats == nil || *stats != expected {
+		t.Errorf("Expected %+v, got %+v", expected, stats)
+	}
+
+	single := NewLatencyStats([]float64{12.5})
+	if single.P50Ms != 12.5 || single.P99Ms != 12.5 || single.MaxMs != 12.5 {
+		t.Errorf("Expected every percentile of a single sample to be 12.5, got %+v", single)
+	}
+}
+
+func TestAlignmentReport_Coverage(t *testing.T) {
+	report := NewAlignmentReport()
+	if report.Coverage != nil {
+		t.Error("Expected empty report to have no coverage")
+	}
+
+	result := NewAlignmentResult("user-service-v1")
+	result.OperationResults = map[string]*OperationResult{
+		"GET /users":    {Path: "/users", Method: "GET", Status: StatusSuccess, SampleCount: 3},
+		"POST /users":   {Path: "/users", Method: "POST", Status: StatusSkipped, SampleCount: 0},
+		"DELETE /users": {Path: "/users", Method: "DELETE", Status: StatusSuccess, SampleCount: 1},
+		"GET /health":   {Path: "/health", Method: "GET", Status: StatusSkipped, SampleCount: 0},
+	}
+	report.AddResult(*result)
+
+	coverage := report.Coverage
+	if coverage == nil {
+		t.Fatal("Expected coverage to be calculated from operation results")
+	}
+	if coverage.TotalOperations != 4 || coverage.CoveredOperations != 2 {
+		t.Errorf("Expected 2/4 covered operations, got %d/%d", coverage.CoveredOperations, coverage.TotalOperations)
+	}
+	if coverage.Ratio != 0.5 {
+		t.Errorf("Expected coverage ratio 0.5, got %f", coverage.Ratio)
+	}
+
+	// Operations are sorted by key for stable output
+	expectedOrder := []string{"DELETE /users", "GET /health", "GET /users", "POST /users"}
+	for i, operation := range coverage.Operations {
+		if operation.Operation != expectedOrder[i] {
+			t.Errorf("Expected operation %d to be %s, got %s", i, expectedOrder[i], operation.Operation)
+		}
+	}
+	if coverage.Operations[2].SampleCount != 3 {
+		t.Errorf("Expected GET /users sample count 3, got %d", coverage.Operations[2].SampleCount)
+	}
+
+	uncovered := coverage.GetUncoveredOperations()
+	if len(uncovered) != 2 || uncovered[0].Operation != "GET /health" {
+		t.Errorf("Expected GET /health and POST /users to be uncovered, got %v", uncovered)
+	}
+
+	if !coverage.MeetsThreshold(0.5) {
+		t.Error("Expected coverage to meet 50% threshold")
+	}
+	if coverage.MeetsThreshold(0.8) {
+		t.Error("Expected coverage not to meet 80% threshold")
+	}
+}
+
+func TestAlignmentReport_Services(t *testing.T) {
+	report := NewAlignmentReport()
+
+	users := NewAlignmentResult("user-service-v1")
+	users.Service = "user-service"
+	users.Status = StatusSuccess
+	users.OperationResults = map[string]*OperationResult{
+		"GET /health": {Path: "/health", Method: "GET", Status: StatusSuccess, SampleCount: 2},
+		"GET /users":  {Path: "/users", Method: "GET", Status: StatusSkipped, SampleCount: 0},
+	}
+	report.AddResult(*users)
+
+	if report.Services != nil {
+		t.Errorf("Expected no per-service breakdown for a single service, got %v", report.Services)
+	}
+
+	orders := NewAlignmentResult("order-service-v1")
+	orders.Service = "order-service"
+	orders.Status = StatusFailed
+	orders.OperationResults = map[string]*OperationResult{
+		"GET /health": {Path: "/health", Method: "GET", Status: StatusFailed, SampleCount: 1},
+	}
+	report.AddResult(*orders)
+
+	if len(report.Services) != 2 {
+		t.Fatalf("Expected 2 service summaries, got %d", len(report.Services))
+	}
+	expected := []ServiceSummary{
+		{Service: "order-service", Total: 1, Failed: 1, TotalOperations: 1, FailedOperations: 1, CoveredOperations: 1},
+		{Service: "user-service", Total: 1, Success: 1, TotalOperations: 2, CoveredOperations: 1},
+	}
+	for i, summary := range report.Services {
+		if summary != expected[i] {
+			t.Errorf("Expected service summary %+v, got %+v", expected[i], summary)
+		}
+	}
+
+	// Operations with the same key in different services are kept apart
+	if report.Coverage.TotalOperations != 3 {
+		t.Errorf("Expected 3 operations across services, got %d", report.Coverage.TotalOperations)
+	}
+	if _, exists := report.Summary.OperationSummary.OperationDetails["order-service GET /health"]; !exists {
+		t.Error("Expected operation keys to be qualified with the service name")
+	}
+}
+
+func TestAlignmentResult_RollUpOperations(t *testing.T) {
+	testCases := []struct {
+		name       string
+		status     AlignmentStatus
+		operations map[AlignmentStatus]int
+		expected   AlignmentStatus
+	}{
+		{"any failure fails", StatusFailed, map[AlignmentStatus]int{StatusSuccess: 2, StatusFailed: 1}, StatusFailed},
+		{"passes with skipped operations", StatusFailed, map[AlignmentStatus]int{StatusSuccess: 1, StatusSkipped: 1}, StatusSuccess},
+		{"all skipped", StatusSuccess, map[AlignmentStatus]int{StatusSkipped: 2}, StatusSkipped},
+		{"timeout kept", StatusTimeout, map[AlignmentStatus]int{StatusSuccess: 1}, StatusTimeout},
+		{"no operations kept", StatusFailed, nil, StatusFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := NewAlignmentResult("user-service-v1")
+			result.Status = tc.status
+			for status, count := range tc.operations {
+				for i := 0; i < count; i++ {
+					if result.OperationResults == nil {
+						result.OperationResults = make(map[string]*OperationResult)
+					}
+					operation := &OperationResult{Status: status}
+					if status != StatusSkipped {
+						operation.AssertionsTotal = 1
+						if status == StatusSuccess {
+							operation.AssertionsPassed = 1
+						} else {
+							operation.AssertionsFailed = 1
+						}
+					}
+					result.OperationResults[fmt.Sprintf("%s %d", status, i)] = operation
+				}
+			}
+
+			result.RollUpOperations()
+			if result.Status != tc.expected {
+				t.Errorf("Expected status %s, got %s", tc.expected, result.Status)
+			}
+			if tc.expected == StatusTimeout || tc.operations == nil {
+				return
+			}
+			passed := tc.operations[StatusSuccess]
+			failed := tc.operations[StatusFailed]
+			if result.AssertionsTotal != passed+failed || result.AssertionsPassed != passed || result.AssertionsFailed != failed {
+				t.Errorf("Expected %d/%d/%d assertions, got %d/%d/%d", passed+failed, passed, failed,
+					result.AssertionsTotal, result.AssertionsPassed, result.AssertionsFailed)
+			}
+		})
+	}
+}
+
+func TestAlignmentReport_SummaryRollsUpOperations(t *testing.T) {
+	passed := true
+	result := NewAlignmentResult("user-service-v1")
+	// A passing status code check whose expected value describes the accepted codes
+	result.AddValidationDetail(ValidationDetail{Type: "status_code", Expected: "2xx", Actual: 200, Passed: &passed})
+	result.OperationResults = map[string]*OperationResult{
+		"GET /users":  {Status: StatusSuccess, AssertionsTotal: 1, AssertionsPassed: 1},
+		"POST /users": {Status: StatusSkipped},
+	}
+
+	report := NewAlignmentReport()
+	report.AddResult(*result)
+
+	if report.Results[0].Status != StatusSuccess {
+		t.Errorf("Expected result to pass with its operations, got %s", report.Results[0].Status)
+	}
+	if report.Summary.Success != 1 || report.Summary.Failed != 0 {
+		t.Errorf("Expected 1 passed spec, got %d passed and %d failed", report.Summary.Success, report.Summary.Failed)
+	}
+	if report.Summary.TotalAssertions != 1 || report.Summary.FailedAssertions != 0 {
+		t.Errorf("Expected 1 assertion and none failed, got %d and %d", report.Summary.TotalAssertions, report.Summary.FailedAssertions)
+	}
+	if len(report.Results[0].GetFailedDetails()) != 0 {
+		t.Error("Expected the status code check to pass")
+	}
+}
+
+func TestGroupFailures(t *testing.T) {
+	span := func(id string) *Span {
+		return &Span{SpanID: id, TraceID: "trace-1"}
+	}
+	details := []ValidationDetail{
+		{Type: "matching", Expected: "GET /orders", Actual: "GET /orders"},
+		{Type: "postcondition", Operation: "GET /orders", Expression: "status == 200", Expected: 200, Actual: 500, Message: "status mismatch", SpanContext: span("span-1")},
+		{Type: "postcondition", Operation: "GET /users", Expression: "status == 200", Expected: 200, Actual: 500, Message: "status mismatch"},
+		{Type: "postcondition", Operation: "GET /orders", Expression: "status == 200", Expected: 200, Actual: 503, Message: "status mismatch", SpanContext: span("span-2")},
+		{Type: "postcondition", Operation: "GET /orders", Expression: "status == 200", Expected: 200, Actual: 200},
+		{Type: "required_header", Operation: "GET /orders", Expression: "required", Expected: "present", Actual: "missing", Message: "Required header 'x-request-id' is missing"},
+		{Type: "required_header", Operation: "GET /orders", Expression: "required", Expected: "present", Actual: "missing", Message: "Required header 'x-tenant' is missing"},
+	}
+
+	groups := GroupFailures(details)
+	if len(groups) != 4 {
+		t.Fatalf("Expected 4 failure groups, got %d: %+v", len(groups), groups)
+	}
+
+	first := groups[0]
+	if first.Operation != "GET /orders" || first.Count != 2 {
+		t.Errorf("Expected the largest group first, got %+v", first)
+	}
+	if first.Representative.SpanContext == nil || first.Representative.SpanContext.SpanID != "span-1" {
+		t.Errorf("Expected the first failure as representative, got %+v", first.Representative)
+	}
+	if groups[1].Operation != "GET /users" || groups[1].Count != 1 {
+		t.Errorf("Expected groups of equal size in order of first occurrence, got %+v", groups[1])
+	}
+	if groups[2].Representative.Message == groups[3].Representative.Message {
+		t.Error("Expected required header failures for different headers to be grouped apart")
+	}
+
+	if groups := GroupFailures(details[:1]); len(groups) != 0 {
+		t.Errorf("Expected no groups without failures, got %+v", groups)
+	}
+}
+
+func TestParseCoverageThreshold(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expected    float64
+		expectError bool
+	}{
+		{input: "80%", expected: 0.8},
+		{input: "80", expected: 0.8},
+		{input: "0.8", expected: 0.8},
+		{input: " 100% ", expected: 1.0},
+		{input: "0", expected: 0},
+		{input: "150%", expectError: true},
+		{input: "-5", expectError: true},
+		{input: "abc", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		threshold, err := ParseCoverageThreshold(tc.input)
+		if tc.expectError {
+			if err == nil {
+				t.Errorf("Expected error for threshold %q", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for threshold %q: %v", tc.input, err)
+			continue
+		}
+		if diff := threshold - tc.expected; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("Expected threshold %q to parse as %f, got %f", tc.input, tc.expected, threshold)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "fmt"
+
+// Severity decides what a failed check of an operation or spec does to the run
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // Failures fail the operation; the default
+	SeverityWarning Severity = "warning" // Failures are reported as warnings, which only fail with --fail-on warnings
+	SeverityInfo    Severity = "info"    // Failures are only listed in the report details
+)
+
+// Validate checks that the severity is empty or one of error, warning and info
+func (s Severity) Validate() error {
+	switch s {
+	case "", SeverityError, SeverityWarning, SeverityInfo:
+		return nil
+	}
+	return fmt.Errorf("severity must be one of %s, %s, %s, got '%s'", SeverityError, SeverityWarning, SeverityInfo, s)
+}
+
+// Blocks reports whether failures of this severity fail their operation or spec
+func (s Severity) Blocks() bool {
+	return s == "" || s == SeverityError
+}
+
+// IsBlocking reports whether the detail is a failure that fails its operation or spec
+func (vd *ValidationDetail) IsBlocking() bool {
+	return !vd.IsPassed() && vd.Severity.Blocks()
+}
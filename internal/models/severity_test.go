@@ -0,0 +1,70 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeverity_Validate(t *testing.T) {
+	for _, severity := range []Severity{"", SeverityError, SeverityWarning, SeverityInfo} {
+		assert.NoError(t, severity.Validate())
+	}
+	assert.ErrorContains(t, Severity("critical").Validate(), "severity must be one of error, warning, info, got 'critical'")
+}
+
+func TestAlignmentResult_SeverityStatus(t *testing.T) {
+	testCases := []struct {
+		name     string
+		severity Severity
+		expected AlignmentStatus
+	}{
+		{name: "error", severity: SeverityError, expected: StatusFailed},
+		{name: "default", severity: "", expected: StatusFailed},
+		{name: "warning", severity: SeverityWarning, expected: StatusSuccess},
+		{name: "info", severity: SeverityInfo, expected: StatusSuccess},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := NewAlignmentResult("op")
+			result.AddValidationDetail(ValidationDetail{Type: "postcondition", Expected: true, Actual: true})
+			failed := ValidationDetail{Type: "postcondition", Expected: true, Actual: false, Severity: tc.severity}
+			result.AddValidationDetail(failed)
+
+			assert.Equal(t, tc.expected, result.Status)
+			assert.Equal(t, 1, result.AssertionsFailed)
+			assert.Equal(t, tc.severity.Blocks(), failed.IsBlocking())
+		})
+	}
+}
+
+func TestAlignmentReport_Warnings(t *testing.T) {
+	report := NewAlignmentReport()
+	result := NewAlignmentResult("op")
+	result.AddValidationDetail(ValidationDetail{Type: "postcondition", Expected: true, Actual: true})
+	report.AddResult(*result)
+	assert.False(t, report.HasWarnings())
+
+	result = NewAlignmentResult("deprecated")
+	result.AddValidationDetail(ValidationDetail{Type: "postcondition", Expected: true, Actual: true})
+	result.Warnings = []string{"first", "second"}
+	report.AddResult(*result)
+	assert.True(t, report.HasWarnings())
+	assert.Equal(t, 2, report.Summary.Warnings)
+	assert.Equal(t, 2, report.Summary.Success)
+}
@@ -0,0 +1,236 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// indexMu guards the lazily built index of every TraceData. A package-level lock keeps
+// TraceData copyable; building happens once per trace, so contention is negligible.
+var indexMu sync.Mutex
+
+// SpanIndex groups the spans of one trace so that matching an operation only looks at the
+// spans that can possibly match it instead of the whole trace. All lookups return spans in
+// start time order, then by span ID; the returned slices are shared and must not be modified.
+type SpanIndex struct {
+	source   int // Size of the span map the index was built from
+	spans    []*Span
+	position map[*Span]int
+
+	byMethod   map[string][]*Span // http.method value
+	byName     map[string][]*Span
+	byPath     map[string][]*Span // http.target and http.route, by segment count and first segment
+	byPathSize map[int][]*Span    // http.target and http.route, by segment count
+
+	attributeMu sync.Mutex
+	byAttribute map[string]map[string][]*Span // Attribute key → string value, built on demand
+}
+
+// NewSpanIndex indexes the given spans
+func NewSpanIndex(spans map[string]*Span) *SpanIndex {
+	index := &SpanIndex{
+		source:      len(spans),
+		spans:       make([]*Span, 0, len(spans)),
+		position:    make(map[*Span]int, len(spans)),
+		byMethod:    make(map[string][]*Span),
+		byName:      make(map[string][]*Span),
+		byPath:      make(map[string][]*Span),
+		byPathSize:  make(map[int][]*Span),
+		byAttribute: make(map[string]map[string][]*Span),
+	}
+	for _, span := range spans {
+		if span != nil {
+			index.spans = append(index.spans, span)
+		}
+	}
+	sort.Slice(index.spans, func(i, j int) bool {
+		if index.spans[i].StartTime != index.spans[j].StartTime {
+			return index.spans[i].StartTime < index.spans[j].StartTime
+		}
+		return index.spans[i].SpanID < index.spans[j].SpanID
+	})
+
+	for position, span := range index.spans {
+		index.position[span] = position
+		if method, ok := span.Attributes["http.method"].(string); ok {
+			index.byMethod[method] = append(index.byMethod[method], span)
+		}
+		index.byName[span.Name] = append(index.byName[span.Name], span)
+
+		// A span is added once per bucket even when target and route share it
+		var keys []string
+		var sizes []int
+		for _, attribute := range []string{"http.target", "http.route"} {
+			path, ok := span.Attributes[attribute].(string)
+			if !ok {
+				continue
+			}
+			size, first := pathKey(path)
+			if key := strconv.Itoa(size) + "/" + first; !containsString(keys, key) {
+				keys = append(keys, key)
+				index.byPath[key] = append(index.byPath[key], span)
+			}
+			if !containsInt(sizes, size) {
+				sizes = append(sizes, size)
+				index.byPathSize[size] = append(index.byPathSize[size], span)
+			}
+		}
+	}
+	return index
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// pathKey returns the segment count and first segment of a path, split the same way the
+// engine splits paths for pattern matching
+func pathKey(path string) (int, string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	return len(segments), segments[0]
+}
+
+// isPathParameter reports whether a pattern segment is a {parameter}
+func isPathParameter(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// Len returns the number of indexed spans
+func (index *SpanIndex) Len() int {
+	return len(index.spans)
+}
+
+// Spans returns all spans
+func (index *SpanIndex) Spans() []*Span {
+	return index.spans
+}
+
+// ByName returns the spans with the given name
+func (index *SpanIndex) ByName(name string) []*Span {
+	return index.byName[name]
+}
+
+// ByMethod returns the spans whose http.method equals method
+func (index *SpanIndex) ByMethod(method string) []*Span {
+	return index.byMethod[method]
+}
+
+// WithAttributeValue returns the spans whose attribute key holds the string value. The
+// index for a key is built the first time it is asked for.
+func (index *SpanIndex) WithAttributeValue(key, value string) []*Span {
+	index.attributeMu.Lock()
+	defer index.attributeMu.Unlock()
+
+	values, ok := index.byAttribute[key]
+	if !ok {
+		values = make(map[string][]*Span)
+		for _, span := range index.spans {
+			if attribute, ok := span.Attributes[key].(string); ok {
+				values[attribute] = append(values[attribute], span)
+			}
+		}
+		index.byAttribute[key] = values
+	}
+	return values[value]
+}
+
+// OperationCandidates returns the spans that may match an operation: spans whose
+// http.method equals method or is missing, and whose http.target or http.route has the shape
+// of pathPattern or whose name is "METHOD pathPattern". The caller still applies the full
+// match; the candidates only rule out spans that cannot match.
+func (index *SpanIndex) OperationCandidates(method, pathPattern string) []*Span {
+	size, first := pathKey(pathPattern)
+	var byPath []*Span
+	if isPathParameter(first) {
+		byPath = index.byPathSize[size]
+	} else {
+		byPath = index.byPath[strconv.Itoa(size)+"/"+first]
+	}
+	byName := index.byName[method+" "+pathPattern]
+
+	candidates := make([]*Span, 0, len(byPath)+len(byName))
+	add := func(span *Span) {
+		if spanMethod, ok := span.Attributes["http.method"].(string); ok && spanMethod != method {
+			return
+		}
+		candidates = append(candidates, span)
+	}
+	for _, span := range byPath {
+		add(span)
+	}
+	if len(byName) == 0 {
+		return candidates
+	}
+
+	// Spans named after the operation are merged in unless their path already put them there
+	inPath := make(map[*Span]bool, len(byPath))
+	for _, span := range byPath {
+		inPath[span] = true
+	}
+	for _, span := range byName {
+		if !inPath[span] {
+			add(span)
+		}
+	}
+	if len(byPath) > 0 {
+		index.sortByPosition(candidates)
+	}
+	return candidates
+}
+
+// sortByPosition restores index order after merging several lookups
+func (index *SpanIndex) sortByPosition(spans []*Span) {
+	sort.Slice(spans, func(i, j int) bool {
+		return index.position[spans[i]] < index.position[spans[j]]
+	})
+}
+
+// Index returns the span index of the trace, building it on first use. The index is rebuilt
+// when spans were added or removed since it was built; call BuildIndex after replacing spans
+// in place.
+func (td *TraceData) Index() *SpanIndex {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+	if td.index == nil || td.index.source != len(td.Spans) {
+		td.index = NewSpanIndex(td.Spans)
+	}
+	return td.index
+}
+
+// BuildIndex rebuilds the span index of the trace
+func (td *TraceData) BuildIndex() *SpanIndex {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+	td.index = NewSpanIndex(td.Spans)
+	return td.index
+}
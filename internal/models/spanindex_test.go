@@ -0,0 +1,153 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// matchesOperation is the engine's matching rule, used as the brute force reference
+func matchesOperation(span *Span, method, pattern string) bool {
+	if spanMethod, ok := span.Attributes["http.method"].(string); ok && spanMethod != method {
+		return false
+	}
+	pathMatches := func(path string) bool {
+		requestSegments := strings.Split(strings.Trim(path, "/"), "/")
+		patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+		if path == pattern {
+			return true
+		}
+		if len(requestSegments) != len(patternSegments) {
+			return false
+		}
+		for i, segment := range patternSegments {
+			if !isPathParameter(segment) && requestSegments[i] != segment {
+				return false
+			}
+		}
+		return true
+	}
+	for _, attribute := range []string{"http.target", "http.route"} {
+		if path, ok := span.Attributes[attribute].(string); ok && pathMatches(path) {
+			return true
+		}
+	}
+	return span.Name == method+" "+pattern
+}
+
+func indexTestTrace() *TraceData {
+	spans := make(map[string]*Span)
+	add := func(id string, start int64, name string, attributes map[string]interface{}) {
+		spans[id] = &Span{SpanID: id, TraceID: "t1", Name: name, StartTime: start, Attributes: attributes}
+	}
+	add("a", 1, "GET /users/{id}", map[string]interface{}{"http.method": "GET", "http.target": "/users/42", "http.route": "/users/{id}"})
+	add("b", 2, "POST /users", map[string]interface{}{"http.method": "POST", "http.target": "/users"})
+	add("c", 3, "GET /users", map[string]interface{}{"http.target": "/users"})
+	add("d", 3, "GET /orders/{id}", map[string]interface{}{"http.method": "GET"})
+	add("e", 4, "db.query", map[string]interface{}{"operation.id": "listUsers"})
+	add("f", 5, "listUsers", map[string]interface{}{"operation.name": "GET /users", "http.method": "GET", "http.target": "/v1/users/42"})
+	add("g", 6, "GET /", map[string]interface{}{"http.method": "GET", "http.target": "/"})
+	return &TraceData{TraceID: "t1", Spans: spans}
+}
+
+func spanIDs(spans []*Span) []string {
+	ids := make([]string, 0, len(spans))
+	for _, span := range spans {
+		ids = append(ids, span.SpanID)
+	}
+	return ids
+}
+
+func TestSpanIndex_OperationCandidates(t *testing.T) {
+	trace := indexTestTrace()
+	index := trace.Index()
+
+	testCases := []struct {
+		method  string
+		pattern string
+	}{
+		{"GET", "/users/{id}"},
+		{"GET", "/users"},
+		{"POST", "/users"},
+		{"GET", "/orders/{id}"},
+		{"GET", "/{version}/users/{id}"},
+		{"GET", "/"},
+		{"DELETE", "/users/{id}"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.pattern, func(t *testing.T) {
+			var expected []string
+			for _, span := range index.Spans() {
+				if matchesOperation(span, tc.method, tc.pattern) {
+					expected = append(expected, span.SpanID)
+				}
+			}
+			var matched []string
+			for _, span := range index.OperationCandidates(tc.method, tc.pattern) {
+				if matchesOperation(span, tc.method, tc.pattern) {
+					matched = append(matched, span.SpanID)
+				}
+			}
+			assert.Equal(t, expected, matched)
+		})
+	}
+}
+
+func TestSpanIndex_Lookups(t *testing.T) {
+	trace := indexTestTrace()
+	index := trace.Index()
+
+	assert.Equal(t, []string{"a", "b", "c", "d", "e", "f", "g"}, spanIDs(index.Spans()))
+	assert.Equal(t, []string{"f"}, spanIDs(index.ByName("listUsers")))
+	assert.Equal(t, []string{"a", "d", "f", "g"}, spanIDs(index.ByMethod("GET")))
+	assert.Equal(t, []string{"e"}, spanIDs(index.WithAttributeValue("operation.id", "listUsers")))
+	assert.Equal(t, []string{"f"}, spanIDs(index.WithAttributeValue("operation.name", "GET /users")))
+	assert.Empty(t, index.WithAttributeValue("operation.id", "missing"))
+
+	// Candidates are narrowed by path shape and method
+	assert.Equal(t, []string{"b", "c"}, spanIDs(index.OperationCandidates("POST", "/users")))
+	assert.Equal(t, []string{"a"}, spanIDs(index.OperationCandidates("GET", "/users/{id}")))
+	assert.Equal(t, []string{"d"}, spanIDs(index.OperationCandidates("GET", "/orders/{id}")))
+
+	// The index is reused until spans are added
+	assert.Same(t, index, trace.Index())
+	trace.Spans["h"] = &Span{SpanID: "h", Name: "listUsers", StartTime: 7}
+	rebuilt := trace.Index()
+	require.NotSame(t, index, rebuilt)
+	assert.Equal(t, []string{"f", "h"}, spanIDs(rebuilt.ByName("listUsers")))
+}
+
+func BenchmarkSpanIndex_OperationCandidates(b *testing.B) {
+	spans := make(map[string]*Span)
+	for i := 0; i < 100000; i++ {
+		id := fmt.Sprintf("s%06d", i)
+		spans[id] = &Span{SpanID: id, Name: "GET /items/{id}", StartTime: int64(i), Attributes: map[string]interface{}{
+			"http.method": "GET",
+			"http.target": fmt.Sprintf("/service%d/items/%d", i%50, i),
+		}}
+	}
+	index := NewSpanIndex(spans)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		index.OperationCandidates("GET", fmt.Sprintf("/service%d/items/{id}", i%50))
+	}
+}
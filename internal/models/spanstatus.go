@@ -0,0 +1,144 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Span status codes recognized by status rules; spans without a status code are UNSET
+const (
+	SpanStatusUnset = "UNSET"
+	SpanStatusOK    = "OK"
+	SpanStatusError = "ERROR"
+)
+
+// statusMessagePatterns caches the compiled message patterns of status rules
+var statusMessagePatterns sync.Map
+
+// SpanStatusRule maps the status of the spans whose status code, and message when the rule
+// names one, match to another status code, for instrumentation that does not set the status
+// the way the contract expects, e.g. {code: UNSET, as: OK} or
+// {code: ERROR, message: "context canceled", as: OK}
+type SpanStatusRule struct {
+	Code    string `json:"code" yaml:"code"`                           // UNSET, OK or ERROR
+	Message string `json:"message,omitempty" yaml:"message,omitempty"` // Regular expression searched in the status message
+	As      string `json:"as" yaml:"as"`                               // OK or ERROR
+}
+
+// SpanStatusRules lists status rules; the first rule matching a span applies
+type SpanStatusRules []SpanStatusRule
+
+// Validate checks that every rule has known status codes and a valid message pattern
+func (r SpanStatusRules) Validate() error {
+	for i, rule := range r {
+		switch strings.ToUpper(rule.Code) {
+		case SpanStatusUnset, SpanStatusOK, SpanStatusError:
+		default:
+			return fmt.Errorf("rule %d: code must be one of %s, %s, %s, got '%s'", i+1, SpanStatusUnset, SpanStatusOK, SpanStatusError, rule.Code)
+		}
+		switch strings.ToUpper(rule.As) {
+		case SpanStatusOK, SpanStatusError:
+		default:
+			return fmt.Errorf("rule %d: as must be %s or %s, got '%s'", i+1, SpanStatusOK, SpanStatusError, rule.As)
+		}
+		if _, err := regexp.Compile(rule.Message); err != nil {
+			return fmt.Errorf("rule %d: invalid message pattern: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Map returns the status the first matching rule maps a status to, recording the code set by
+// the instrumentation in Original; the status itself is returned when no rule matches. Rules
+// match the original code of an already mapped status, so mapping again gives the same result.
+func (r SpanStatusRules) Map(status SpanStatus) SpanStatus {
+	code := status.Code
+	if status.Original != "" {
+		code = status.Original
+	}
+	if code == "" {
+		code = SpanStatusUnset
+	}
+	for _, rule := range r {
+		if !strings.EqualFold(rule.Code, code) || !rule.matchesMessage(status.Message) {
+			continue
+		}
+		mapped := SpanStatus{Code: strings.ToUpper(rule.As), Message: status.Message}
+		if mapped.Code != code {
+			mapped.Original = code
+		}
+		return mapped
+	}
+	return status
+}
+
+// Apply returns a copy of the span with its status mapped; the span itself is returned when
+// no rule changes its status
+func (r SpanStatusRules) Apply(span *Span) *Span {
+	if len(r) == 0 {
+		return span
+	}
+	status := r.Map(span.Status)
+	if status == span.Status {
+		return span
+	}
+	mapped := *span
+	mapped.Status = status
+	return &mapped
+}
+
+// matchesMessage reports whether a status message matches the message pattern of the rule;
+// rules without a pattern match every message
+func (rule SpanStatusRule) matchesMessage(message string) bool {
+	if rule.Message == "" {
+		return true
+	}
+	cached, ok := statusMessagePatterns.Load(rule.Message)
+	if !ok {
+		pattern, err := regexp.Compile(rule.Message)
+		if err != nil {
+			return false
+		}
+		cached, _ = statusMessagePatterns.LoadOrStore(rule.Message, pattern)
+	}
+	return cached.(*regexp.Regexp).MatchString(message)
+}
+
+// WithSpanStatusRules returns a copy of the trace whose span statuses are mapped by the rules,
+// with the span tree rebuilt; the trace itself is returned when no span changes
+func (td *TraceData) WithSpanStatusRules(rules SpanStatusRules) *TraceData {
+	if len(rules) == 0 {
+		return td
+	}
+
+	mapped := &TraceData{TraceID: td.TraceID, Spans: make(map[string]*Span, len(td.Spans)), DuplicateSpanIDs: td.DuplicateSpanIDs}
+	changed := false
+	for id, span := range td.Spans {
+		mapped.Spans[id] = rules.Apply(span)
+		changed = changed || mapped.Spans[id] != span
+	}
+	if !changed {
+		return td
+	}
+	if mapped.BuildSpanTree() != nil {
+		mapped.RootSpan = nil
+		mapped.SpanTree = nil
+	}
+	return mapped
+}
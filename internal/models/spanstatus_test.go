@@ -0,0 +1,114 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpanStatusRules_Validate(t *testing.T) {
+	assert.NoError(t, SpanStatusRules(nil).Validate())
+	assert.NoError(t, SpanStatusRules{{Code: "unset", As: "ok"}, {Code: "ERROR", Message: "canceled$", As: "OK"}}.Validate())
+	assert.ErrorContains(t, SpanStatusRules{{Code: "TIMEOUT", As: "OK"}}.Validate(), "rule 1: code must be one of UNSET, OK, ERROR, got 'TIMEOUT'")
+	assert.ErrorContains(t, SpanStatusRules{{Code: "OK", As: "OK"}, {Code: "OK", As: "UNSET"}}.Validate(), "rule 2: as must be OK or ERROR, got 'UNSET'")
+	assert.ErrorContains(t, SpanStatusRules{{Code: "ERROR", Message: "(", As: "OK"}}.Validate(), "rule 1: invalid message pattern")
+}
+
+func TestSpanStatusRules_Map(t *testing.T) {
+	rules := SpanStatusRules{
+		{Code: "ERROR", Message: "context canceled", As: "OK"},
+		{Code: "UNSET", As: "OK"},
+		{Code: "OK", Message: "partial", As: "ERROR"},
+	}
+
+	testCases := []struct {
+		name     string
+		status   SpanStatus
+		expected SpanStatus
+	}{
+		{
+			name:     "unset is treated as OK",
+			status:   SpanStatus{Code: "UNSET"},
+			expected: SpanStatus{Code: "OK", Original: "UNSET"},
+		},
+		{
+			name:     "missing code is unset",
+			status:   SpanStatus{},
+			expected: SpanStatus{Code: "OK", Original: "UNSET"},
+		},
+		{
+			name:     "acceptable error message",
+			status:   SpanStatus{Code: "ERROR", Message: "rpc error: context canceled"},
+			expected: SpanStatus{Code: "OK", Message: "rpc error: context canceled", Original: "ERROR"},
+		},
+		{
+			name:     "other error messages are kept",
+			status:   SpanStatus{Code: "ERROR", Message: "connection refused"},
+			expected: SpanStatus{Code: "ERROR", Message: "connection refused"},
+		},
+		{
+			name:     "OK mapped to ERROR by message",
+			status:   SpanStatus{Code: "OK", Message: "partial content"},
+			expected: SpanStatus{Code: "ERROR", Message: "partial content", Original: "OK"},
+		},
+		{
+			name:     "already mapped status matches its original code",
+			status:   SpanStatus{Code: "OK", Original: "UNSET"},
+			expected: SpanStatus{Code: "OK", Original: "UNSET"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, rules.Map(tc.status))
+		})
+	}
+}
+
+func TestSpanStatusRules_Apply(t *testing.T) {
+	rules := SpanStatusRules{{Code: "UNSET", As: "OK"}}
+
+	span := &Span{SpanID: "s1", Status: SpanStatus{Code: "UNSET"}}
+	mapped := rules.Apply(span)
+	assert.False(t, mapped.HasError())
+	assert.Equal(t, "OK", mapped.Status.Code)
+	assert.Equal(t, "UNSET", span.Status.Code, "the span itself is not modified")
+
+	unchanged := &Span{Status: SpanStatus{Code: "ERROR"}}
+	assert.Same(t, unchanged, rules.Apply(unchanged))
+
+	// A spec rule keeps the instrumentation's ERROR that an engine-wide rule mapped to OK
+	engineMapped := SpanStatusRules{{Code: "ERROR", As: "OK"}}.Apply(&Span{Status: SpanStatus{Code: "ERROR", Message: "timeout"}})
+	kept := SpanStatusRules{{Code: "ERROR", Message: "timeout", As: "ERROR"}}.Apply(engineMapped)
+	assert.Equal(t, SpanStatus{Code: "ERROR", Message: "timeout"}, kept.Status)
+}
+
+func TestTraceData_WithSpanStatusRules(t *testing.T) {
+	trace := &TraceData{TraceID: "t1", Spans: map[string]*Span{
+		"root":  {SpanID: "root", Status: SpanStatus{Code: "UNSET"}},
+		"child": {SpanID: "child", ParentID: "root", Status: SpanStatus{Code: "OK"}},
+	}, DuplicateSpanIDs: []string{"root"}}
+	assert.Same(t, trace, trace.WithSpanStatusRules(nil))
+	assert.Same(t, trace, trace.WithSpanStatusRules(SpanStatusRules{{Code: "ERROR", As: "OK"}}))
+
+	mapped := trace.WithSpanStatusRules(SpanStatusRules{{Code: "UNSET", As: "OK"}})
+	assert.Equal(t, "OK", mapped.Spans["root"].Status.Code)
+	assert.Same(t, trace.Spans["child"], mapped.Spans["child"])
+	assert.Equal(t, "root", mapped.RootSpan.SpanID)
+	assert.Equal(t, []string{"root"}, mapped.DuplicateSpanIDs)
+	assert.Equal(t, "UNSET", trace.Spans["root"].Status.Code)
+}
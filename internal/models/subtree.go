@@ -0,0 +1,65 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "fmt"
+
+// Subtree returns a trace of a span and all its descendants. The span becomes the root of the
+// new trace unless withAncestors is set, in which case its ancestors are kept too, without
+// their other descendants, so the span keeps its place in the trace. Spans are shared with
+// the original trace, except a detached root, which is copied.
+func (td *TraceData) Subtree(spanID string, withAncestors bool) (*TraceData, error) {
+	root, ok := td.Spans[spanID]
+	if !ok {
+		return nil, fmt.Errorf("span %s is not in the trace", spanID)
+	}
+
+	children := make(map[string][]*Span, len(td.Spans))
+	for _, span := range td.Spans {
+		if span.ParentID != "" && span.ParentID != span.SpanID {
+			children[span.ParentID] = append(children[span.ParentID], span)
+		}
+	}
+
+	subtree := &TraceData{TraceID: root.TraceID, Spans: make(map[string]*Span)}
+	queue := []*Span{root}
+	for len(queue) > 0 {
+		span := queue[0]
+		queue = queue[1:]
+		if _, seen := subtree.Spans[span.SpanID]; seen {
+			continue
+		}
+		subtree.Spans[span.SpanID] = span
+		queue = append(queue, children[span.SpanID]...)
+	}
+
+	if withAncestors {
+		for parent := td.Spans[root.ParentID]; parent != nil; parent = td.Spans[parent.ParentID] {
+			if _, seen := subtree.Spans[parent.SpanID]; seen {
+				break
+			}
+			subtree.Spans[parent.SpanID] = parent
+		}
+	} else if root.ParentID != "" {
+		detached := *root
+		detached.ParentID = ""
+		subtree.Spans[root.SpanID] = &detached
+	}
+
+	if err := subtree.BuildSpanTree(); err != nil {
+		return nil, err
+	}
+	return subtree, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceData_Subtree(t *testing.T) {
+	trace := &TraceData{TraceID: "t1"}
+	for _, span := range []*Span{
+		{SpanID: "root", TraceID: "t1"},
+		{SpanID: "checkout", TraceID: "t1", ParentID: "root"},
+		{SpanID: "pay", TraceID: "t1", ParentID: "checkout"},
+		{SpanID: "charge", TraceID: "t1", ParentID: "pay"},
+		{SpanID: "reserve", TraceID: "t1", ParentID: "checkout"},
+		{SpanID: "list", TraceID: "t1", ParentID: "root"},
+	} {
+		trace.AddSpan(span)
+	}
+	require.NoError(t, trace.BuildSpanTree())
+
+	testCases := []struct {
+		name          string
+		spanID        string
+		withAncestors bool
+		expected      []string
+		root          string
+	}{
+		{name: "subtree", spanID: "checkout", expected: []string{"checkout", "pay", "charge", "reserve"}, root: "checkout"},
+		{name: "leaf", spanID: "charge", expected: []string{"charge"}, root: "charge"},
+		{name: "whole trace", spanID: "root", expected: []string{"root", "checkout", "pay", "charge", "reserve", "list"}, root: "root"},
+		{name: "with ancestors", spanID: "pay", withAncestors: true, expected: []string{"root", "checkout", "pay", "charge"}, root: "root"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			subtree, err := trace.Subtree(tc.spanID, tc.withAncestors)
+			require.NoError(t, err)
+			ids := make([]string, 0, len(subtree.Spans))
+			for id := range subtree.Spans {
+				ids = append(ids, id)
+			}
+			assert.ElementsMatch(t, tc.expected, ids)
+			assert.Equal(t, tc.root, subtree.RootSpan.SpanID)
+			assert.Equal(t, "t1", subtree.TraceID)
+			assert.True(t, subtree.CheckCompleteness(0).Complete())
+		})
+	}
+
+	assert.Equal(t, "root", trace.Spans["checkout"].ParentID, "the detached root is a copy")
+
+	_, err := trace.Subtree("missing", false)
+	assert.EqualError(t, err, "span missing is not in the trace")
+}
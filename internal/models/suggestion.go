@@ -0,0 +1,223 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Kinds of failures, recorded as contextInfo.failure and matched by suggestion rules
+const (
+	FailureMissing       = "missing"        // A value the check reads is absent
+	FailureTypeMismatch  = "type_mismatch"  // A value has another type than the one it is compared with
+	FailureValueMismatch = "value_mismatch" // Any other failure
+)
+
+// suggestionMessagePatterns caches the compiled message patterns of suggestion rules
+var suggestionMessagePatterns sync.Map
+
+// SuggestionRule adds organization-specific remediation to the failed validation details it
+// matches, e.g. {key: "*tenant*", failure: missing, suggestion: "Set X-Tenant at the gateway",
+// runbook: https://runbooks.example.com/tenant}. Empty conditions match every detail.
+type SuggestionRule struct {
+	Name       string `json:"name" yaml:"name"`
+	Type       string `json:"type,omitempty" yaml:"type,omitempty"`           // Detail type, e.g. postcondition or required_header
+	Failure    string `json:"failure,omitempty" yaml:"failure,omitempty"`     // missing, type_mismatch or value_mismatch
+	Key        string `json:"key,omitempty" yaml:"key,omitempty"`             // Case-insensitive glob on a variable, attribute, header or parameter the check reads
+	Operation  string `json:"operation,omitempty" yaml:"operation,omitempty"` // Case-insensitive glob on the operation, e.g. "POST /orders/*"
+	Message    string `json:"message,omitempty" yaml:"message,omitempty"`     // Regular expression searched in the failure message
+	Suggestion string `json:"suggestion" yaml:"suggestion"`
+	Runbook    string `json:"runbook,omitempty" yaml:"runbook,omitempty"` // Link appended to the suggestion
+	Replace    bool   `json:"replace,omitempty" yaml:"replace,omitempty"` // Drop the built-in suggestions of the details the rule matches
+}
+
+// SuggestionRules lists suggestion rules; every matching rule adds its suggestion, in order,
+// before the built-in suggestions
+type SuggestionRules []SuggestionRule
+
+// Validate checks that every rule has a name, a suggestion, a known failure kind and valid
+// patterns
+func (r SuggestionRules) Validate() error {
+	for i, rule := range r {
+		if rule.Name == "" {
+			return fmt.Errorf("rule %d: name is required", i+1)
+		}
+		if strings.TrimSpace(rule.Suggestion) == "" {
+			return fmt.Errorf("rule %d (%s): suggestion is required", i+1, rule.Name)
+		}
+		switch rule.Failure {
+		case "", FailureMissing, FailureTypeMismatch, FailureValueMismatch:
+		default:
+			return fmt.Errorf("rule %d (%s): failure must be one of %s, %s, %s, got '%s'", i+1, rule.Name, FailureMissing, FailureTypeMismatch, FailureValueMismatch, rule.Failure)
+		}
+		for _, glob := range []string{rule.Key, rule.Operation} {
+			if _, err := path.Match(strings.ToLower(glob), ""); err != nil {
+				return fmt.Errorf("rule %d (%s): invalid pattern %q: %w", i+1, rule.Name, glob, err)
+			}
+		}
+		if _, err := regexp.Compile(rule.Message); err != nil {
+			return fmt.Errorf("rule %d (%s): invalid message pattern: %w", i+1, rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// Apply adds the suggestions of the rules matching a failed detail; passed details are left
+// unchanged
+func (r SuggestionRules) Apply(detail *ValidationDetail) {
+	if len(r) == 0 || detail.IsPassed() {
+		return
+	}
+	var suggestions []string
+	replace := false
+	for _, rule := range r {
+		if !rule.matches(detail) {
+			continue
+		}
+		suggestion := rule.Suggestion
+		if rule.Runbook != "" {
+			suggestion += " (runbook: " + rule.Runbook + ")"
+		}
+		suggestions = append(suggestions, suggestion)
+		replace = replace || rule.Replace
+	}
+	if len(suggestions) == 0 {
+		return
+	}
+	if !replace {
+		suggestions = append(suggestions, detail.Suggestions...)
+	}
+	detail.Suggestions = suggestions
+}
+
+// matches reports whether every condition of the rule holds for a detail
+func (rule SuggestionRule) matches(detail *ValidationDetail) bool {
+	if rule.Type != "" && !strings.EqualFold(rule.Type, detail.Type) {
+		return false
+	}
+	if rule.Failure != "" && rule.Failure != detail.FailureKind() {
+		return false
+	}
+	if rule.Operation != "" && !globMatch(rule.Operation, detail.Operation) {
+		return false
+	}
+	if rule.Key != "" {
+		matched := false
+		for _, key := range detail.FailureKeys() {
+			matched = matched || globMatch(rule.Key, key)
+		}
+		if !matched {
+			return false
+		}
+	}
+	return rule.matchesMessage(detail.Message)
+}
+
+// matchesMessage reports whether a failure message matches the message pattern of the rule;
+// rules without a pattern match every message
+func (rule SuggestionRule) matchesMessage(message string) bool {
+	if rule.Message == "" {
+		return true
+	}
+	cached, ok := suggestionMessagePatterns.Load(rule.Message)
+	if !ok {
+		pattern, err := regexp.Compile(rule.Message)
+		if err != nil {
+			return false
+		}
+		cached, _ = suggestionMessagePatterns.LoadOrStore(rule.Message, pattern)
+	}
+	return cached.(*regexp.Regexp).MatchString(message)
+}
+
+// FailureKind returns the kind of failure recorded as contextInfo.failure; otherwise a detail
+// without an actual value, or whose actual value is "missing", is missing a value
+func (vd *ValidationDetail) FailureKind() string {
+	if kind, ok := vd.ContextInfo["failure"].(string); ok && kind != "" {
+		return kind
+	}
+	if vd.Actual == nil || vd.Actual == "missing" {
+		return FailureMissing
+	}
+	return FailureValueMismatch
+}
+
+// FailureKeys returns the variables, attributes, headers or parameters a check reads: those
+// recorded as contextInfo.keys, otherwise the variables of a JSONLogic expression, or the
+// expression itself
+func (vd *ValidationDetail) FailureKeys() []string {
+	switch keys := vd.ContextInfo["keys"].(type) {
+	case []string:
+		return keys
+	case []interface{}:
+		names := make([]string, 0, len(keys))
+		for _, key := range keys {
+			names = append(names, fmt.Sprint(key))
+		}
+		return names
+	}
+	var expression interface{}
+	if err := json.Unmarshal([]byte(vd.Expression), &expression); err != nil {
+		if vd.Expression == "" {
+			return nil
+		}
+		return []string{vd.Expression}
+	}
+	return ExpressionVariables(expression)
+}
+
+// ExpressionVariables returns the variables a JSONLogic expression reads, in order of first
+// appearance
+func ExpressionVariables(expression interface{}) []string {
+	var variables []string
+	seen := make(map[string]bool)
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			for operator, argument := range typed {
+				if operator == "var" {
+					name := argument
+					if list, ok := argument.([]interface{}); ok && len(list) > 0 {
+						name = list[0]
+					}
+					if text, ok := name.(string); ok && text != "" && !seen[text] {
+						seen[text] = true
+						variables = append(variables, text)
+					}
+					continue
+				}
+				walk(argument)
+			}
+		case []interface{}:
+			for _, item := range typed {
+				walk(item)
+			}
+		}
+	}
+	walk(expression)
+	return variables
+}
+
+// globMatch reports whether a value matches a case-insensitive glob pattern
+func globMatch(pattern, value string) bool {
+	matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(value))
+	return matched
+}
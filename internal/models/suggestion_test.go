@@ -0,0 +1,104 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestionRules_Validate(t *testing.T) {
+	assert.NoError(t, SuggestionRules(nil).Validate())
+	assert.NoError(t, SuggestionRules{{Name: "tenant", Key: "*tenant*", Failure: "missing", Suggestion: "Set X-Tenant"}}.Validate())
+	assert.ErrorContains(t, SuggestionRules{{Suggestion: "Set X-Tenant"}}.Validate(), "rule 1: name is required")
+	assert.ErrorContains(t, SuggestionRules{{Name: "tenant"}}.Validate(), "rule 1 (tenant): suggestion is required")
+	assert.ErrorContains(t, SuggestionRules{{Name: "tenant", Failure: "absent", Suggestion: "x"}}.Validate(), "failure must be one of missing, type_mismatch, value_mismatch, got 'absent'")
+	assert.ErrorContains(t, SuggestionRules{{Name: "tenant", Key: "[", Suggestion: "x"}}.Validate(), `invalid pattern "["`)
+	assert.ErrorContains(t, SuggestionRules{{Name: "tenant", Message: "(", Suggestion: "x"}}.Validate(), "invalid message pattern")
+}
+
+func TestSuggestionRules_Apply(t *testing.T) {
+	rules := SuggestionRules{
+		{Name: "tenant", Failure: FailureMissing, Key: "*X-Tenant*", Suggestion: "Set X-Tenant at the gateway", Runbook: "https://runbooks.example.com/tenant"},
+		{Name: "status type", Failure: FailureTypeMismatch, Key: "span.attributes.http.status_code", Suggestion: "Record the status code as an integer", Replace: true},
+		{Name: "orders", Operation: "POST /orders/*", Message: "timeout", Suggestion: "Check the order service SLO dashboard"},
+	}
+
+	testCases := []struct {
+		name     string
+		detail   ValidationDetail
+		expected []string
+	}{
+		{
+			name: "missing header",
+			detail: ValidationDetail{Type: "required_header", Actual: "missing", Suggestions: []string{"Add the header"},
+				ContextInfo: map[string]interface{}{"failure": FailureMissing, "keys": []string{"x-tenant"}}},
+			expected: []string{"Set X-Tenant at the gateway (runbook: https://runbooks.example.com/tenant)", "Add the header"},
+		},
+		{
+			name: "type mismatch replaces the built-in suggestions",
+			detail: ValidationDetail{Type: "postcondition", Actual: false, Suggestions: []string{"Check the value"},
+				ContextInfo: map[string]interface{}{"failure": FailureTypeMismatch, "keys": []interface{}{"span.attributes.http.status_code"}}},
+			expected: []string{"Record the status code as an integer"},
+		},
+		{
+			name: "keys fall back to the variables of the expression",
+			detail: ValidationDetail{Type: "postcondition", Actual: false, Suggestions: []string{"Check the value"},
+				Expression: `{"==":[{"var":"span.attributes.http.status_code"},200]}`, ContextInfo: map[string]interface{}{"failure": FailureTypeMismatch}},
+			expected: []string{"Record the status code as an integer"},
+		},
+		{
+			name:     "operation and message",
+			detail:   ValidationDetail{Type: "postcondition", Operation: "POST /orders/{id}", Actual: false, Message: "upstream timeout"},
+			expected: []string{"Check the order service SLO dashboard"},
+		},
+		{
+			name:     "no rule matches",
+			detail:   ValidationDetail{Type: "postcondition", Operation: "GET /orders", Actual: false, Message: "upstream timeout", Suggestions: []string{"Check the value"}},
+			expected: []string{"Check the value"},
+		},
+		{
+			name: "passed details are left unchanged",
+			detail: ValidationDetail{Type: "required_header", Expected: "present", Actual: "present",
+				ContextInfo: map[string]interface{}{"failure": FailureMissing, "keys": []string{"x-tenant"}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			detail := tc.detail
+			rules.Apply(&detail)
+			assert.Equal(t, tc.expected, detail.Suggestions)
+		})
+	}
+}
+
+func TestValidationDetail_FailureKind(t *testing.T) {
+	assert.Equal(t, FailureTypeMismatch, (&ValidationDetail{Actual: 1, ContextInfo: map[string]interface{}{"failure": FailureTypeMismatch}}).FailureKind())
+	assert.Equal(t, FailureMissing, (&ValidationDetail{Actual: "missing"}).FailureKind())
+	assert.Equal(t, FailureMissing, (&ValidationDetail{}).FailureKind())
+	assert.Equal(t, FailureValueMismatch, (&ValidationDetail{Actual: 500}).FailureKind())
+}
+
+func TestExpressionVariables(t *testing.T) {
+	expression := map[string]interface{}{"and": []interface{}{
+		map[string]interface{}{"==": []interface{}{map[string]interface{}{"var": "a"}, 1}},
+		map[string]interface{}{"in": []interface{}{map[string]interface{}{"var": []interface{}{"b", "default"}}, []interface{}{"x"}}},
+		map[string]interface{}{"!=": []interface{}{map[string]interface{}{"var": "a"}, nil}},
+	}}
+	assert.Equal(t, []string{"a", "b"}, ExpressionVariables(expression))
+	assert.Empty(t, ExpressionVariables(true))
+}
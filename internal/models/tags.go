@@ -0,0 +1,132 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+// TagSummary aggregates the operations carrying a tag
+type TagSummary struct {
+	Tag               string `json:"tag"`
+	TotalOperations   int    `json:"totalOperations"`
+	SuccessOperations int    `json:"successOperations"`
+	FailedOperations  int    `json:"failedOperations"`
+	SkippedOperations int    `json:"skippedOperations"`
+	FlakyOperations   int    `json:"flakyOperations,omitempty"`
+	CoveredOperations int    `json:"coveredOperations"` // Operations matched by at least one span
+}
+
+// OperationTags returns the tags of an operation of the endpoint: its own and those of the
+// endpoint, sorted and without duplicates
+func (e EndpointSpec) OperationTags(operation OperationSpec) []string {
+	if len(e.Tags)+len(operation.Tags) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var tags []string
+	for _, tag := range append(append([]string(nil), e.Tags...), operation.Tags...) {
+		if tag = strings.TrimSpace(tag); tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// FilterSpecsByTags returns the specs restricted to the operations carrying at least one of
+// tags, dropping endpoints and specs left without operations. Legacy specs have no tags and are
+// dropped; with no tags, specs are returned unchanged.
+func FilterSpecsByTags(specs []ServiceSpec, tags []string) []ServiceSpec {
+	if len(tags) == 0 {
+		return specs
+	}
+	wanted := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		wanted[strings.TrimSpace(tag)] = true
+	}
+
+	var filtered []ServiceSpec
+	for _, spec := range specs {
+		if !spec.IsYAMLFormat() || spec.Spec == nil {
+			continue
+		}
+		var endpoints []EndpointSpec
+		for _, endpoint := range spec.Spec.Endpoints {
+			var operations []OperationSpec
+			for _, operation := range endpoint.Operations {
+				for _, tag := range endpoint.OperationTags(operation) {
+					if wanted[tag] {
+						operations = append(operations, operation)
+						break
+					}
+				}
+			}
+			if len(operations) > 0 {
+				endpoint.Operations = operations
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+		if len(endpoints) > 0 {
+			spec.Spec = &ServiceSpecDefinition{Endpoints: endpoints}
+			filtered = append(filtered, spec)
+		}
+	}
+	return filtered
+}
+
+// summarizeTags aggregates operation results per tag, ordered by tag, or nil when no operation
+// is tagged
+func summarizeTags(results []AlignmentResult) []TagSummary {
+	summaries := make(map[string]*TagSummary)
+	for _, result := range results {
+		for _, operationResult := range result.OperationResults {
+			for _, tag := range operationResult.Tags {
+				summary, exists := summaries[tag]
+				if !exists {
+					summary = &TagSummary{Tag: tag}
+					summaries[tag] = summary
+				}
+
+				summary.TotalOperations++
+				switch operationResult.Status {
+				case StatusSuccess:
+					summary.SuccessOperations++
+				case StatusFailed, StatusTimeout:
+					summary.FailedOperations++
+				case StatusSkipped:
+					summary.SkippedOperations++
+				case StatusFlaky:
+					summary.FlakyOperations++
+				}
+				if operationResult.SampleCount > 0 {
+					summary.CoveredOperations++
+				}
+			}
+		}
+	}
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	tags := make([]TagSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		tags = append(tags, *summary)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Tag < tags[j].Tag })
+	return tags
+}
@@ -0,0 +1,103 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTaggedSpec() ServiceSpec {
+	return ServiceSpec{
+		APIVersion: "flowspec/v1alpha1",
+		Kind:       "ServiceSpec",
+		Metadata:   &ServiceSpecMetadata{Name: "orders", Version: "v1"},
+		Spec: &ServiceSpecDefinition{Endpoints: []EndpointSpec{
+			{Path: "/orders", Tags: []string{"orders"}, Operations: []OperationSpec{
+				{Method: "GET"},
+				{Method: "POST", Tags: []string{"critical", "orders"}},
+			}},
+			{Path: "/payments", Operations: []OperationSpec{{Method: "POST", Tags: []string{"payments", "critical"}}}},
+			{Path: "/health", Operations: []OperationSpec{{Method: "GET"}}},
+		}},
+	}
+}
+
+func TestEndpointSpec_OperationTags(t *testing.T) {
+	endpoints := newTaggedSpec().Spec.Endpoints
+	assert.Equal(t, []string{"orders"}, endpoints[0].OperationTags(endpoints[0].Operations[0]))
+	assert.Equal(t, []string{"critical", "orders"}, endpoints[0].OperationTags(endpoints[0].Operations[1]))
+	assert.Equal(t, []string{"critical", "payments"}, endpoints[1].OperationTags(endpoints[1].Operations[0]))
+	assert.Nil(t, endpoints[2].OperationTags(endpoints[2].Operations[0]))
+}
+
+func TestFilterSpecsByTags(t *testing.T) {
+	legacy := ServiceSpec{OperationID: "createOrder"}
+	specs := []ServiceSpec{newTaggedSpec(), legacy}
+
+	testCases := []struct {
+		name      string
+		tags      []string
+		wantPaths []string
+		wantOps   int
+	}{
+		{name: "no tags", tags: nil, wantPaths: []string{"/orders", "/payments", "/health"}, wantOps: 4},
+		{name: "endpoint tag", tags: []string{"orders"}, wantPaths: []string{"/orders"}, wantOps: 2},
+		{name: "operation tag", tags: []string{"critical"}, wantPaths: []string{"/orders", "/payments"}, wantOps: 2},
+		{name: "any of several", tags: []string{"payments", " orders "}, wantPaths: []string{"/orders", "/payments"}, wantOps: 3},
+		{name: "unknown tag", tags: []string{"search"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filtered := FilterSpecsByTags(specs, tc.tags)
+			var paths []string
+			operations := 0
+			for _, spec := range filtered {
+				if spec.Spec == nil {
+					continue
+				}
+				for _, endpoint := range spec.Spec.Endpoints {
+					paths = append(paths, endpoint.Path)
+					operations += len(endpoint.Operations)
+				}
+			}
+			assert.Equal(t, tc.wantPaths, paths)
+			assert.Equal(t, tc.wantOps, operations)
+		})
+	}
+	assert.Len(t, specs[0].Spec.Endpoints, 3, "the specs passed in are unchanged")
+}
+
+func TestAlignmentReport_TagSummaries(t *testing.T) {
+	report := NewAlignmentReport()
+	report.AddResult(AlignmentResult{SpecOperationID: "orders", Status: StatusFailed, OperationResults: map[string]*OperationResult{
+		"GET /orders":    {Status: StatusSuccess, SampleCount: 3, Tags: []string{"orders"}},
+		"POST /orders":   {Status: StatusFailed, SampleCount: 1, Tags: []string{"critical", "orders"}},
+		"POST /payments": {Status: StatusSkipped, Tags: []string{"critical"}},
+		"GET /health":    {Status: StatusSuccess, SampleCount: 1},
+		"DELETE /orders": {Status: StatusFlaky, SampleCount: 5, Tags: []string{"orders"}},
+	}})
+
+	assert.Equal(t, []TagSummary{
+		{Tag: "critical", TotalOperations: 2, FailedOperations: 1, SkippedOperations: 1, CoveredOperations: 1},
+		{Tag: "orders", TotalOperations: 3, SuccessOperations: 1, FailedOperations: 1, FlakyOperations: 1, CoveredOperations: 3},
+	}, report.Tags)
+
+	untagged := NewAlignmentReport()
+	untagged.AddResult(AlignmentResult{SpecOperationID: "health", Status: StatusSuccess})
+	assert.Nil(t, untagged.Tags)
+}
@@ -0,0 +1,115 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeWindow limits verification to spans that started within it; a nil bound is open
+type TimeWindow struct {
+	Since *time.Time `json:"since,omitempty"`
+	Until *time.Time `json:"until,omitempty"`
+}
+
+// ParseTimeWindow parses RFC 3339 bounds such as the --since and --until flags. It returns nil
+// when both are empty.
+func ParseTimeWindow(since, until string) (*TimeWindow, error) {
+	if since == "" && until == "" {
+		return nil, nil
+	}
+
+	window := &TimeWindow{}
+	for _, bound := range []struct {
+		name  string
+		value string
+		field **time.Time
+	}{
+		{"since", since, &window.Since},
+		{"until", until, &window.Until},
+	} {
+		if bound.value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, bound.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s time %q, expected RFC 3339 such as 2025-08-01T00:00:00Z: %w", bound.name, bound.value, err)
+		}
+		*bound.field = &parsed
+	}
+
+	if window.Since != nil && window.Until != nil && window.Until.Before(*window.Since) {
+		return nil, fmt.Errorf("until time %s is before since time %s", until, since)
+	}
+	return window, nil
+}
+
+// Contains reports whether t lies within the window; both bounds are inclusive
+func (w *TimeWindow) Contains(t time.Time) bool {
+	if w == nil {
+		return true
+	}
+	if w.Since != nil && t.Before(*w.Since) {
+		return false
+	}
+	if w.Until != nil && t.After(*w.Until) {
+		return false
+	}
+	return true
+}
+
+// String describes the window, e.g. "since 2025-08-01T00:00:00Z until 2025-08-02T00:00:00Z"
+func (w *TimeWindow) String() string {
+	if w == nil {
+		return "any time"
+	}
+	var parts []string
+	if w.Since != nil {
+		parts = append(parts, "since "+w.Since.Format(time.RFC3339))
+	}
+	if w.Until != nil {
+		parts = append(parts, "until "+w.Until.Format(time.RFC3339))
+	}
+	return strings.Join(parts, " ")
+}
+
+// WithinTimeWindow returns a copy of the trace holding only the spans that started within the
+// window. The span tree is rebuilt from the kept spans; spans whose parent was dropped stay in
+// the trace but outside the tree, and the trace has no root when the root was dropped. Duplicate
+// span IDs are kept for the spans that remain.
+func (td *TraceData) WithinTimeWindow(window *TimeWindow) *TraceData {
+	if window == nil {
+		return td
+	}
+
+	filtered := &TraceData{TraceID: td.TraceID, Spans: make(map[string]*Span, len(td.Spans))}
+	for id, span := range td.Spans {
+		if window.Contains(time.Unix(0, span.StartTime)) {
+			filtered.Spans[id] = span
+		}
+	}
+	for _, id := range td.DuplicateSpanIDs {
+		if _, ok := filtered.Spans[id]; ok {
+			filtered.DuplicateSpanIDs = append(filtered.DuplicateSpanIDs, id)
+		}
+	}
+	if filtered.BuildSpanTree() != nil {
+		filtered.RootSpan = nil
+		filtered.SpanTree = nil
+	}
+	return filtered
+}
@@ -0,0 +1,85 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeWindow(t *testing.T) {
+	testCases := []struct {
+		name      string
+		since     string
+		until     string
+		expected  string
+		errorText string
+	}{
+		{name: "no bounds", expected: "any time"},
+		{name: "since only", since: "2025-08-01T00:00:00Z", expected: "since 2025-08-01T00:00:00Z"},
+		{name: "until only", until: "2025-08-02T12:00:00+02:00", expected: "until 2025-08-02T12:00:00+02:00"},
+		{name: "both", since: "2025-08-01T00:00:00Z", until: "2025-08-01T00:00:00Z", expected: "since 2025-08-01T00:00:00Z until 2025-08-01T00:00:00Z"},
+		{name: "bad since", since: "yesterday", errorText: `invalid since time "yesterday"`},
+		{name: "bad until", until: "2025-08-01", errorText: `invalid until time "2025-08-01"`},
+		{name: "reversed", since: "2025-08-02T00:00:00Z", until: "2025-08-01T00:00:00Z", errorText: "is before since time"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			window, err := ParseTimeWindow(tc.since, tc.until)
+			if tc.errorText != "" {
+				assert.ErrorContains(t, err, tc.errorText)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, window.String())
+		})
+	}
+}
+
+func TestTraceData_WithinTimeWindow(t *testing.T) {
+	base := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) int64 { return base.Add(time.Duration(minutes) * time.Minute).UnixNano() }
+	trace := &TraceData{TraceID: "t1", Spans: map[string]*Span{
+		"root":   {SpanID: "root", StartTime: at(0)},
+		"early":  {SpanID: "early", ParentID: "root", StartTime: at(1)},
+		"inside": {SpanID: "inside", ParentID: "root", StartTime: at(10)},
+		"child":  {SpanID: "child", ParentID: "inside", StartTime: at(11)},
+		"late":   {SpanID: "late", ParentID: "root", StartTime: at(30)},
+	}, DuplicateSpanIDs: []string{"inside", "late", "inside"}}
+
+	assert.Same(t, trace, trace.WithinTimeWindow(nil))
+
+	since := base.Add(5 * time.Minute)
+	until := base.Add(20 * time.Minute)
+	filtered := trace.WithinTimeWindow(&TimeWindow{Since: &since, Until: &until})
+	assert.Len(t, trace.Spans, 5, "the original trace is unchanged")
+	assert.ElementsMatch(t, []string{"inside", "child"}, spanIDs(filtered.GetAllSpans()))
+	assert.Nil(t, filtered.RootSpan, "the root started before the window")
+	assert.Equal(t, []string{"inside", "inside"}, filtered.DuplicateSpanIDs, "duplicates of the kept spans survive the filter")
+
+	withRoot := trace.WithinTimeWindow(&TimeWindow{Until: &until})
+	assert.ElementsMatch(t, []string{"root", "early", "inside", "child"}, spanIDs(withRoot.GetAllSpans()))
+	require.NotNil(t, withRoot.RootSpan)
+	assert.Equal(t, "root", withRoot.RootSpan.SpanID)
+	assert.Equal(t, 3, withRoot.SpanTree.GetTotalDescendants())
+
+	// Bounds are inclusive
+	edge := base.Add(30 * time.Minute)
+	assert.Equal(t, []string{"late"}, spanIDs(trace.WithinTimeWindow(&TimeWindow{Since: &edge}).GetAllSpans()))
+}
@@ -0,0 +1,86 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "math"
+
+// TraceSummary aggregates the results of one trace when several traces are verified together
+type TraceSummary struct {
+	TraceID    string `json:"traceId"`
+	Spans      int    `json:"spans"`
+	Total      int    `json:"total"`
+	Success    int    `json:"success"`
+	Failed     int    `json:"failed"`
+	Skipped    int    `json:"skipped"`
+	Incomplete bool   `json:"incomplete,omitempty"` // The trace was not fully aligned, e.g. on interruption
+	Error      string `json:"error,omitempty"`      // Why the trace could not be aligned
+}
+
+// AddResults adds several alignment results to the report and updates the summary once
+func (ar *AlignmentReport) AddResults(results []AlignmentResult) {
+	for _, result := range results {
+		result.RollUpOperations()
+		ar.Results = append(ar.Results, result)
+	}
+	ar.updateSummary()
+}
+
+// combine merges the summary of the same operation aligned with another trace: samples and
+// assertions add up and the worst status wins. On sampled traces, estimated requests add up, the
+// failure rate interval spans those of both traces and requests may only have been sampled out
+// when neither trace matched a span.
+func (summary *OperationSummary) combine(other *OperationSummary) {
+	summary.SampleCount += other.SampleCount
+	summary.AssertionsTotal += other.AssertionsTotal
+	summary.AssertionsPassed += other.AssertionsPassed
+	summary.AssertionsFailed += other.AssertionsFailed
+	if other.FailureRate > summary.FailureRate {
+		summary.FailureRate = other.FailureRate
+	}
+	if statusSeverity(other.Status) > statusSeverity(summary.Status) {
+		summary.Status = other.Status
+	}
+
+	summary.EstimatedRequests += other.EstimatedRequests
+	if interval := other.FailureRateInterval; interval != nil {
+		if summary.FailureRateInterval == nil {
+			summary.FailureRateInterval = &RateInterval{Low: interval.Low, High: interval.High}
+		} else {
+			summary.FailureRateInterval = &RateInterval{
+				Low:  math.Min(summary.FailureRateInterval.Low, interval.Low),
+				High: math.Max(summary.FailureRateInterval.High, interval.High),
+			}
+		}
+	}
+	if other.UnseenRequests > summary.UnseenRequests {
+		summary.UnseenRequests = other.UnseenRequests
+	}
+	if summary.SampleCount > 0 {
+		summary.UnseenRequests = 0
+	}
+}
+
+// statusSeverity orders statuses from skipped to failed
+func statusSeverity(status AlignmentStatus) int {
+	switch status {
+	case StatusFailed, StatusTimeout:
+		return 3
+	case StatusFlaky:
+		return 2
+	case StatusSuccess:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,79 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTraceResult(traceID string, getStatus AlignmentStatus, getSamples int) AlignmentResult {
+	return AlignmentResult{
+		SpecOperationID: "orders",
+		Service:         "orders",
+		TraceID:         traceID,
+		OperationResults: map[string]*OperationResult{
+			"GET /orders": {Path: "/orders", Method: "GET", Status: getStatus, SampleCount: getSamples,
+				AssertionsTotal: getSamples, AssertionsPassed: getSamples},
+			"POST /orders": {Path: "/orders", Method: "POST", Status: StatusSkipped},
+		},
+	}
+}
+
+func TestAlignmentReport_AddResults(t *testing.T) {
+	testCases := []struct {
+		name        string
+		statuses    []AlignmentStatus
+		wantStatus  AlignmentStatus
+		wantSamples int
+	}{
+		{name: "single trace", statuses: []AlignmentStatus{StatusSuccess}, wantStatus: StatusSuccess, wantSamples: 1},
+		{name: "skipped then covered", statuses: []AlignmentStatus{StatusSkipped, StatusSuccess}, wantStatus: StatusSuccess, wantSamples: 1},
+		{name: "worst status wins", statuses: []AlignmentStatus{StatusSuccess, StatusFailed, StatusFlaky}, wantStatus: StatusFailed, wantSamples: 3},
+		{name: "flaky over success", statuses: []AlignmentStatus{StatusFlaky, StatusSuccess}, wantStatus: StatusFlaky, wantSamples: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var results []AlignmentResult
+			for i, status := range tc.statuses {
+				samples := 1
+				if status == StatusSkipped {
+					samples = 0
+				}
+				results = append(results, newTraceResult(string(rune('a'+i)), status, samples))
+			}
+
+			report := NewAlignmentReport()
+			report.AddResults(results)
+
+			assert.Len(t, report.Results, len(tc.statuses))
+			assert.Equal(t, len(tc.statuses), report.Summary.Total)
+			require.NotNil(t, report.Summary.OperationSummary)
+			details := report.Summary.OperationSummary.OperationDetails
+			require.Len(t, details, 2)
+			assert.Equal(t, tc.wantStatus, details["GET /orders"].Status)
+			assert.Equal(t, tc.wantSamples, details["GET /orders"].SampleCount)
+			assert.Equal(t, tc.wantSamples, details["GET /orders"].AssertionsPassed)
+			assert.Equal(t, StatusSkipped, details["POST /orders"].Status)
+
+			require.NotNil(t, report.Coverage)
+			assert.Equal(t, 2, report.Coverage.TotalOperations)
+			assert.Equal(t, 1, report.Coverage.CoveredOperations)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/httpclient"
+)
+
+// DefaultPushgatewayJob is the job label used when none is configured
+const DefaultPushgatewayJob = "flowspec"
+
+// PushgatewayConfig holds the settings for pushing metrics to a Prometheus Pushgateway
+type PushgatewayConfig struct {
+	URL      string            // Base URL of the Pushgateway, e.g. http://pushgateway:9091
+	Job      string            // Job label; DefaultPushgatewayJob when empty
+	Grouping map[string]string // Additional grouping labels, e.g. {"branch": "main"}
+	Timeout  time.Duration
+	Network  *httpclient.Config // Proxy and TLS settings; httpclient.ConfigFromEnv() when nil
+}
+
+// PushMetrics replaces the metrics of the configured group with the given Prometheus text metrics
+func PushMetrics(ctx context.Context, config PushgatewayConfig, metrics string) error {
+	return PushMetricsWithClient(ctx, config, metrics, httpclient.New(config.Network, pushTimeout(config)))
+}
+
+// PushMetricsWithClient pushes metrics using the given HTTP client
+func PushMetricsWithClient(ctx context.Context, config PushgatewayConfig, metrics string, client *http.Client) error {
+	if config.URL == "" {
+		return fmt.Errorf("pushgateway URL is required")
+	}
+	job := config.Job
+	if job == "" {
+		job = DefaultPushgatewayJob
+	}
+
+	// Grouping labels become path segments: /metrics/job/<job>/<label>/<value>
+	target := strings.TrimRight(config.URL, "/") + "/metrics/job/" + url.PathEscape(job)
+	labels := make([]string, 0, len(config.Grouping))
+	for label := range config.Grouping {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		target += "/" + url.PathEscape(label) + "/" + url.PathEscape(config.Grouping[label])
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, strings.NewReader(metrics))
+	if err != nil {
+		return fmt.Errorf("failed to create pushgateway request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push metrics to %s: %w", config.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("pushgateway returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// pushTimeout returns the configured timeout or a default
+func pushTimeout(config PushgatewayConfig) time.Duration {
+	if config.Timeout > 0 {
+		return config.Timeout
+	}
+	return 10 * time.Second
+}
@@ -0,0 +1,60 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushMetrics(t *testing.T) {
+	var method, path, body, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, contentType = r.Method, r.URL.EscapedPath(), r.Header.Get("Content-Type")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	config := PushgatewayConfig{URL: server.URL + "/", Grouping: map[string]string{"service": "user service", "branch": "main"}}
+	err := PushMetricsWithClient(context.Background(), config, "flowspec_checks_total 2\n", server.Client())
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/metrics/job/flowspec/branch/main/service/user%20service", path)
+	assert.Equal(t, "flowspec_checks_total 2\n", body)
+	assert.Contains(t, contentType, "text/plain")
+}
+
+func TestPushMetrics_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "text format parsing error", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := PushMetrics(context.Background(), PushgatewayConfig{URL: server.URL, Job: "ci"}, "bad")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "400")
+	assert.Contains(t, err.Error(), "text format parsing error")
+
+	assert.Error(t, PushMetrics(context.Background(), PushgatewayConfig{}, ""))
+}
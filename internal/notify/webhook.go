@@ -0,0 +1,252 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notify posts verification summaries to chat and webhook endpoints.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/httpclient"
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/renderer"
+)
+
+// Payload formats
+const (
+	FormatAuto  = "auto"  // Slack for hooks.slack.com URLs, JSON otherwise
+	FormatSlack = "slack" // Slack incoming webhook message with blocks
+	FormatJSON  = "json"  // Generic JSON document
+)
+
+// DefaultTopFailures is the number of failures included in a notification
+const DefaultTopFailures = 5
+
+// Config holds the notification settings
+type Config struct {
+	URL          string
+	Format       string // FormatAuto, FormatSlack or FormatJSON
+	TemplatePath string // Go text/template producing the JSON payload; overrides Format
+	TopFailures  int
+	Title        string // Optional title, e.g. the pipeline or repository name
+	Timeout      time.Duration
+	Network      *httpclient.Config // Proxy and TLS settings; httpclient.ConfigFromEnv() when nil
+}
+
+// DefaultConfig returns a default notification configuration
+func DefaultConfig() Config {
+	return Config{
+		Format:      FormatAuto,
+		TopFailures: DefaultTopFailures,
+		Timeout:     10 * time.Second,
+	}
+}
+
+// Data is the view of a report passed to payload templates
+type Data struct {
+	Title    string                    `json:"title,omitempty"`
+	Passed   bool                      `json:"passed"`
+	Status   string                    `json:"status"` // "passed" or "failed"
+	ExitCode int                       `json:"exitCode"`
+	Summary  models.AlignmentSummary   `json:"summary"`
+	Coverage *models.CoverageReport    `json:"coverage,omitempty"`
+	Failures []renderer.FailureSummary `json:"failures"` // Top failures
+	Omitted  int                       `json:"omitted"`  // Failures not included in Failures
+	Duration string                    `json:"duration"`
+}
+
+// NewData builds the template data of a report, keeping at most top failures
+func NewData(report *models.AlignmentReport, title string, top int) Data {
+	exitCode := renderer.NewReportRenderer().GetExitCode(report)
+	failures := renderer.FailureSummaries(report)
+	omitted := 0
+	if top >= 0 && len(failures) > top {
+		omitted = len(failures) - top
+		failures = failures[:top]
+	}
+
+	data := Data{
+		Title:    title,
+		Passed:   exitCode == renderer.ExitSuccess,
+		Status:   "passed",
+		ExitCode: exitCode,
+		Summary:  report.Summary,
+		Coverage: report.Coverage,
+		Failures: failures,
+		Omitted:  omitted,
+		Duration: time.Duration(report.ExecutionTime).Round(time.Millisecond).String(),
+	}
+	if !data.Passed {
+		data.Status = "failed"
+	}
+	return data
+}
+
+// slackTemplate is the built-in Slack payload; json escapes values into JSON strings
+const slackTemplate = `{
+  "text": {{json (printf "%s FlowSpec verification %s" (icon .Passed) .Status)}},
+  "blocks": [
+    {"type": "header", "text": {"type": "plain_text", "text": {{json (printf "%s FlowSpec verification %s%s" (icon .Passed) .Status (suffix .Title))}}}},
+    {"type": "section", "fields": [
+      {"type": "mrkdwn", "text": {{json (printf "*Specs*\n%d total, %d passed, %d failed, %d skipped" .Summary.Total .Summary.Success .Summary.Failed .Summary.Skipped)}}},
+      {"type": "mrkdwn", "text": {{json (printf "*Duration*\n%s" .Duration)}}}{{with .Coverage}},
+      {"type": "mrkdwn", "text": {{json (printf "*Coverage*\n%d/%d operations (%.1f%%)" .CoveredOperations .TotalOperations (percent .Ratio))}}}{{end}}
+    ]}{{if .Failures}},
+    {"type": "section", "text": {"type": "mrkdwn", "text": {{json (failures .)}}}}{{end}}
+  ]
+}`
+
+// templateFuncs are available to built-in and custom templates
+var templateFuncs = template.FuncMap{
+	"json": func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+	"icon": func(passed bool) string {
+		if passed {
+			return "✅"
+		}
+		return "❌"
+	},
+	"suffix": func(title string) string {
+		if title == "" {
+			return ""
+		}
+		return ": " + title
+	},
+	"percent": func(ratio float64) float64 { return ratio * 100 },
+	"failures": func(data Data) string {
+		var text strings.Builder
+		text.WriteString("*Top failures*\n")
+		for _, failure := range data.Failures {
+			text.WriteString(fmt.Sprintf("• `%s` %s", failure.Key, failure.Message))
+			if failure.Count > 1 {
+				text.WriteString(fmt.Sprintf(" (%d failures)", failure.Count))
+			}
+			text.WriteString("\n")
+		}
+		if data.Omitted > 0 {
+			text.WriteString(fmt.Sprintf("…and %d more\n", data.Omitted))
+		}
+		return text.String()
+	},
+}
+
+// BuildPayload renders the notification payload for the report
+func BuildPayload(config Config, report *models.AlignmentReport) ([]byte, error) {
+	if report == nil {
+		return nil, fmt.Errorf("report cannot be nil")
+	}
+	data := NewData(report, config.Title, config.TopFailures)
+
+	source := ""
+	switch {
+	case config.TemplatePath != "":
+		content, err := os.ReadFile(config.TemplatePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read notification template: %w", err)
+		}
+		source = string(content)
+	case resolveFormat(config) == FormatSlack:
+		source = slackTemplate
+	default:
+		payload, err := json.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal notification: %w", err)
+		}
+		return payload, nil
+	}
+
+	tmpl, err := template.New("payload").Funcs(templateFuncs).Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse notification template: %w", err)
+	}
+	var payload bytes.Buffer
+	if err := tmpl.Execute(&payload, data); err != nil {
+		return nil, fmt.Errorf("failed to render notification template: %w", err)
+	}
+	if !json.Valid(payload.Bytes()) {
+		return nil, fmt.Errorf("notification template did not produce valid JSON")
+	}
+	return payload.Bytes(), nil
+}
+
+// resolveFormat picks the payload format, detecting Slack webhooks by host
+func resolveFormat(config Config) string {
+	if config.Format != "" && config.Format != FormatAuto {
+		return config.Format
+	}
+	if parsed, err := url.Parse(config.URL); err == nil && parsed.Host == "hooks.slack.com" {
+		return FormatSlack
+	}
+	return FormatJSON
+}
+
+// Send posts the verification summary to the configured webhook
+func Send(ctx context.Context, config Config, report *models.AlignmentReport) error {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return SendWithClient(ctx, config, report, httpclient.New(config.Network, timeout))
+}
+
+// SendWithClient posts the verification summary using the given HTTP client
+func SendWithClient(ctx context.Context, config Config, report *models.AlignmentReport, client *http.Client) error {
+	if config.URL == "" {
+		return fmt.Errorf("webhook URL is required")
+	}
+	switch config.Format {
+	case "", FormatAuto, FormatSlack, FormatJSON:
+	default:
+		return fmt.Errorf("unsupported notification format %q (supported: auto, slack, json)", config.Format)
+	}
+
+	payload, err := BuildPayload(config, report)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// Webhook URLs carry credentials; keep them out of the error message
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to post notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFailingReport builds a report with the given number of failed operations
+func newFailingReport(failures int) *models.AlignmentReport {
+	result := models.NewAlignmentResult("user-service-v1")
+	result.Status = models.StatusFailed
+	result.OperationResults = make(map[string]*models.OperationResult)
+	for i := 0; i < failures; i++ {
+		result.OperationResults[fmt.Sprintf("GET /items/%d", i)] = &models.OperationResult{
+			Status:  models.StatusFailed,
+			Details: []models.ValidationDetail{{Type: "status_code", Expected: 200, Actual: 500, Message: `Status code 500 "unexpected"`}},
+		}
+	}
+
+	report := models.NewAlignmentReport()
+	report.AddResult(*result)
+	report.Coverage = &models.CoverageReport{TotalOperations: 10, CoveredOperations: 8, Ratio: 0.8}
+	return report
+}
+
+func TestBuildPayload_Slack(t *testing.T) {
+	config := DefaultConfig()
+	config.URL = "https://hooks.slack.com/services/T000/B000/XXXX"
+	config.Title = "shop#42"
+	config.TopFailures = 2
+
+	payload, err := BuildPayload(config, newFailingReport(3))
+	require.NoError(t, err)
+
+	var message struct {
+		Text   string                   `json:"text"`
+		Blocks []map[string]interface{} `json:"blocks"`
+	}
+	require.NoError(t, json.Unmarshal(payload, &message))
+	assert.Equal(t, "❌ FlowSpec verification failed", message.Text)
+	require.Len(t, message.Blocks, 3)
+	assert.Contains(t, string(payload), "FlowSpec verification failed: shop#42")
+	assert.Contains(t, string(payload), "8/10 operations (80.0%)")
+
+	failures := message.Blocks[2]["text"].(map[string]interface{})["text"].(string)
+	assert.Contains(t, failures, "• `GET /items/0` Status code 500 \"unexpected\"")
+	assert.Contains(t, failures, "…and 1 more")
+}
+
+func TestBuildPayload_JSON(t *testing.T) {
+	config := DefaultConfig()
+	config.URL = "https://ci.example.com/hooks/flowspec"
+
+	payload, err := BuildPayload(config, newFailingReport(1))
+	require.NoError(t, err)
+
+	var data Data
+	require.NoError(t, json.Unmarshal(payload, &data))
+	assert.Equal(t, "failed", data.Status)
+	assert.Equal(t, 1, data.ExitCode)
+	require.Len(t, data.Failures, 1)
+	assert.Equal(t, "GET /items/0", data.Failures[0].Key)
+}
+
+func TestBuildPayload_Template(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "teams.tmpl")
+	require.NoError(t, os.WriteFile(valid, []byte(`{"title": {{json .Status}}, "failed": {{.Summary.Failed}}}`), 0644))
+	invalid := filepath.Join(dir, "broken.tmpl")
+	require.NoError(t, os.WriteFile(invalid, []byte(`{"title": {{.Status}}}`), 0644))
+
+	config := DefaultConfig()
+	config.TemplatePath = valid
+	payload, err := BuildPayload(config, newFailingReport(1))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"title": "failed", "failed": 1}`, string(payload))
+
+	config.TemplatePath = invalid
+	_, err = BuildPayload(config, newFailingReport(1))
+	assert.ErrorContains(t, err, "valid JSON")
+}
+
+func TestSend(t *testing.T) {
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		received, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.URL = server.URL
+	config.Format = FormatSlack
+	require.NoError(t, SendWithClient(context.Background(), config, newFailingReport(1), server.Client()))
+	assert.True(t, strings.HasPrefix(string(received), "{\n  \"text\""))
+
+	config.Format = "teams"
+	assert.ErrorContains(t, Send(context.Background(), config, newFailingReport(1)), "unsupported notification format")
+}
+
+func TestSend_DoesNotLeakURL(t *testing.T) {
+	config := DefaultConfig()
+	config.URL = "http://127.0.0.1:1/services/secret-token"
+
+	err := Send(context.Background(), config, newFailingReport(1))
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-token")
+}
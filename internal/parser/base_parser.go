@@ -52,6 +52,9 @@ type ServiceSpecAnnotation struct {
 	Description    string
 	Preconditions  interface{}
 	Postconditions interface{}
+	SpanStatus     interface{}
+	Severity       interface{}
+	Priority       interface{}
 	StartLine      int
 	EndLine        int
 }
@@ -423,6 +426,18 @@ func (bp *BaseFileParser) parseAnnotationContent(content string, annotation *Ser
 		annotation.Postconditions = postconditions
 	}
 
+	if spanStatus, ok := data["spanStatus"]; ok {
+		annotation.SpanStatus = spanStatus
+	}
+
+	if severity, ok := data["severity"]; ok {
+		annotation.Severity = severity
+	}
+
+	if priority, ok := data["priority"]; ok {
+		annotation.Priority = priority
+	}
+
 	return nil
 }
 
@@ -457,6 +472,36 @@ func (bp *BaseFileParser) convertAnnotationToSpec(annotation ServiceSpecAnnotati
 		spec.Postconditions = make(map[string]interface{})
 	}
 
+	// Convert span status rules through JSON, so YAML and JSON annotations decode alike
+	if annotation.SpanStatus != nil {
+		if _, ok := annotation.SpanStatus.([]interface{}); !ok {
+			return spec, fmt.Errorf("spanStatus must be a list of rules")
+		}
+		content, err := json.Marshal(annotation.SpanStatus)
+		if err != nil {
+			return spec, fmt.Errorf("invalid spanStatus: %s", err.Error())
+		}
+		if err := json.Unmarshal(content, &spec.SpanStatus); err != nil {
+			return spec, fmt.Errorf("invalid spanStatus: %s", err.Error())
+		}
+	}
+
+	if annotation.Severity != nil {
+		severity, ok := annotation.Severity.(string)
+		if !ok {
+			return spec, fmt.Errorf("severity must be a string")
+		}
+		spec.Severity = models.Severity(severity)
+	}
+
+	if annotation.Priority != nil {
+		priority, ok := annotation.Priority.(string)
+		if !ok {
+			return spec, fmt.Errorf("priority must be a string")
+		}
+		spec.Priority = models.Priority(priority)
+	}
+
 	// Validate the spec
 	if err := spec.Validate(); err != nil {
 		return spec, fmt.Errorf("invalid ServiceSpec: %s", err.Error())
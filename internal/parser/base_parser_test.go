@@ -19,6 +19,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/flowspec/flowspec-cli/internal/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -265,6 +266,75 @@ func TestConvertAnnotationToSpec_InvalidPreconditions(t *testing.T) {
 	assert.Contains(t, err.Error(), "preconditions must be a map/object")
 }
 
+func TestConvertAnnotationToSpec_SpanStatus(t *testing.T) {
+	parser := NewBaseFileParser(LanguageJava)
+	annotation := &ServiceSpecAnnotation{StartLine: 10}
+
+	content := `operationId: "streamOrders"
+description: "Stream orders"
+spanStatus:
+  - code: ERROR
+    message: "context canceled"
+    as: OK
+postconditions:
+  "==": [{"var": "span.has_error"}, false]`
+	require.NoError(t, parser.parseAnnotationContent(content, annotation, "test.java"))
+
+	spec, err := parser.convertAnnotationToSpec(*annotation, "test.java")
+	require.NoError(t, err)
+	assert.Equal(t, models.SpanStatusRules{{Code: "ERROR", Message: "context canceled", As: "OK"}}, spec.SpanStatus)
+
+	annotation.SpanStatus = []interface{}{map[string]interface{}{"code": "ERROR", "as": "MAYBE"}}
+	_, err = parser.convertAnnotationToSpec(*annotation, "test.java")
+	assert.ErrorContains(t, err, "spanStatus: rule 1: as must be OK or ERROR")
+
+	annotation.SpanStatus = map[string]interface{}{"code": "ERROR", "as": "OK"}
+	_, err = parser.convertAnnotationToSpec(*annotation, "test.java")
+	assert.ErrorContains(t, err, "spanStatus must be a list of rules")
+}
+
+func TestConvertAnnotationToSpec_Severity(t *testing.T) {
+	parser := NewBaseFileParser(LanguageJava)
+	annotation := &ServiceSpecAnnotation{StartLine: 10}
+
+	content := `operationId: "listOrders"
+description: "List orders"
+severity: warning`
+	require.NoError(t, parser.parseAnnotationContent(content, annotation, "test.java"))
+	spec, err := parser.convertAnnotationToSpec(*annotation, "test.java")
+	require.NoError(t, err)
+	assert.Equal(t, models.SeverityWarning, spec.Severity)
+
+	annotation.Severity = "critical"
+	_, err = parser.convertAnnotationToSpec(*annotation, "test.java")
+	assert.ErrorContains(t, err, "severity must be one of error, warning, info")
+
+	annotation.Severity = 3
+	_, err = parser.convertAnnotationToSpec(*annotation, "test.java")
+	assert.ErrorContains(t, err, "severity must be a string")
+}
+
+func TestConvertAnnotationToSpec_Priority(t *testing.T) {
+	parser := NewBaseFileParser(LanguageJava)
+	annotation := &ServiceSpecAnnotation{StartLine: 10}
+
+	content := `operationId: "checkout"
+description: "Check out the cart"
+priority: critical`
+	require.NoError(t, parser.parseAnnotationContent(content, annotation, "test.java"))
+	spec, err := parser.convertAnnotationToSpec(*annotation, "test.java")
+	require.NoError(t, err)
+	assert.Equal(t, models.PriorityCritical, spec.Priority)
+
+	annotation.Priority = "urgent"
+	_, err = parser.convertAnnotationToSpec(*annotation, "test.java")
+	assert.ErrorContains(t, err, "priority must be one of critical, high, normal, low")
+
+	annotation.Priority = 1
+	_, err = parser.convertAnnotationToSpec(*annotation, "test.java")
+	assert.ErrorContains(t, err, "priority must be a string")
+}
+
 func TestValidateJSONLogic(t *testing.T) {
 	parser := NewBaseFileParser(LanguageJava)
 
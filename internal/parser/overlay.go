@@ -0,0 +1,241 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// OverlayKind is the kind of documents that patch ServiceSpec contracts
+const OverlayKind = "ServiceSpecOverlay"
+
+// Overlay patches base contracts for one environment before verification
+type Overlay struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   OverlayMetadata   `yaml:"metadata"`
+	Service    string            `yaml:"service,omitempty"` // Only patch this service; required when several services are loaded
+	Endpoints  []EndpointOverlay `yaml:"endpoints"`
+	SourceFile string            `yaml:"-"`
+}
+
+// OverlayMetadata identifies an overlay, typically by environment
+type OverlayMetadata struct {
+	Name string `yaml:"name"`
+}
+
+// EndpointOverlay patches, disables or adds an endpoint matched by path
+type EndpointOverlay struct {
+	Path       string             `yaml:"path"`
+	Disabled   bool               `yaml:"disabled,omitempty"`
+	Operations []OperationOverlay `yaml:"operations,omitempty"`
+}
+
+// OperationOverlay patches, disables or adds an operation matched by method.
+// Set fields replace the corresponding field of the base operation.
+type OperationOverlay struct {
+	Method    string                     `yaml:"method"`
+	Disabled  bool                       `yaml:"disabled,omitempty"`
+	Responses *models.ResponseSpec       `yaml:"responses,omitempty"`
+	Required  *models.RequiredFieldsSpec `yaml:"required,omitempty"`
+	Optional  *models.OptionalFieldsSpec `yaml:"optional,omitempty"`
+}
+
+// LoadOverlay reads and validates an overlay file
+func LoadOverlay(path string) (*Overlay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overlay %s: %w", path, err)
+	}
+
+	var overlay Overlay
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&overlay); err != nil {
+		return nil, fmt.Errorf("failed to parse overlay %s: %w", path, err)
+	}
+	overlay.SourceFile = path
+
+	if overlay.Kind != OverlayKind {
+		return nil, fmt.Errorf("overlay %s has kind %q, expected %q", path, overlay.Kind, OverlayKind)
+	}
+	for i, endpoint := range overlay.Endpoints {
+		if endpoint.Path == "" {
+			return nil, fmt.Errorf("overlay %s: endpoints[%d].path is required", path, i)
+		}
+		for j, operation := range endpoint.Operations {
+			if operation.Method == "" {
+				return nil, fmt.Errorf("overlay %s: endpoints[%d].operations[%d].method is required", path, i, j)
+			}
+		}
+	}
+
+	return &overlay, nil
+}
+
+// ApplyOverlays patches YAML format specs with the given overlays in order and returns the result.
+// Legacy specs are passed through unchanged. The input specs are not modified. An overlay
+// without service only applies when a single service is loaded, since the paths it patches,
+// disables or adds belong to one service.
+func ApplyOverlays(specs []models.ServiceSpec, overlays []*Overlay) ([]models.ServiceSpec, error) {
+	services := yamlServiceNames(specs)
+	if len(services) > 1 {
+		for _, overlay := range overlays {
+			if overlay.Service == "" {
+				return nil, fmt.Errorf("overlay %s must set service, the contracts define several services: %s",
+					overlay.SourceFile, strings.Join(services, ", "))
+			}
+		}
+	}
+
+	patched := make([]models.ServiceSpec, 0, len(specs))
+	for _, spec := range specs {
+		if !spec.IsYAMLFormat() {
+			patched = append(patched, spec)
+			continue
+		}
+
+		spec = copySpecDefinition(spec)
+		for _, overlay := range overlays {
+			if overlay.Service != "" && overlay.Service != spec.Metadata.Name {
+				continue
+			}
+			if err := overlay.apply(&spec); err != nil {
+				return nil, fmt.Errorf("failed to apply overlay %s to %s: %w", overlay.SourceFile, spec.Metadata.Name, err)
+			}
+		}
+
+		if len(spec.Spec.Endpoints) == 0 {
+			return nil, fmt.Errorf("overlays disable every endpoint of %s", spec.Metadata.Name)
+		}
+		patched = append(patched, spec)
+	}
+
+	return patched, nil
+}
+
+// yamlServiceNames returns the distinct names of the YAML format specs, in order
+func yamlServiceNames(specs []models.ServiceSpec) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, spec := range specs {
+		if spec.IsYAMLFormat() && !seen[spec.Metadata.Name] {
+			seen[spec.Metadata.Name] = true
+			names = append(names, spec.Metadata.Name)
+		}
+	}
+	return names
+}
+
+// apply patches a single spec in place
+func (o *Overlay) apply(spec *models.ServiceSpec) error {
+	for _, endpointOverlay := range o.Endpoints {
+		index := findEndpoint(spec.Spec.Endpoints, endpointOverlay.Path)
+
+		if endpointOverlay.Disabled {
+			if index < 0 {
+				return fmt.Errorf("cannot disable unknown endpoint %s", endpointOverlay.Path)
+			}
+			spec.Spec.Endpoints = append(spec.Spec.Endpoints[:index], spec.Spec.Endpoints[index+1:]...)
+			continue
+		}
+
+		if index < 0 {
+			spec.Spec.Endpoints = append(spec.Spec.Endpoints, models.EndpointSpec{Path: endpointOverlay.Path})
+			index = len(spec.Spec.Endpoints) - 1
+		}
+		endpoint := &spec.Spec.Endpoints[index]
+
+		for _, operationOverlay := range endpointOverlay.Operations {
+			if err := operationOverlay.apply(endpoint); err != nil {
+				return err
+			}
+		}
+
+		if len(endpoint.Operations) == 0 {
+			// Disabling every operation removes the endpoint
+			spec.Spec.Endpoints = append(spec.Spec.Endpoints[:index], spec.Spec.Endpoints[index+1:]...)
+		}
+	}
+	return nil
+}
+
+// apply patches, disables or adds an operation of an endpoint
+func (o OperationOverlay) apply(endpoint *models.EndpointSpec) error {
+	method := strings.ToUpper(o.Method)
+	index := -1
+	for i, operation := range endpoint.Operations {
+		if strings.EqualFold(operation.Method, method) {
+			index = i
+			break
+		}
+	}
+
+	if o.Disabled {
+		if index < 0 {
+			return fmt.Errorf("cannot disable unknown operation %s %s", method, endpoint.Path)
+		}
+		endpoint.Operations = append(endpoint.Operations[:index], endpoint.Operations[index+1:]...)
+		return nil
+	}
+
+	if index < 0 {
+		if o.Responses == nil {
+			return fmt.Errorf("new operation %s %s must define responses", method, endpoint.Path)
+		}
+		endpoint.Operations = append(endpoint.Operations, models.OperationSpec{Method: method})
+		index = len(endpoint.Operations) - 1
+	}
+
+	operation := &endpoint.Operations[index]
+	if o.Responses != nil {
+		operation.Responses = *o.Responses
+	}
+	if o.Required != nil {
+		operation.Required = *o.Required
+	}
+	if o.Optional != nil {
+		operation.Optional = *o.Optional
+	}
+	return nil
+}
+
+// findEndpoint returns the index of the endpoint with the given path, or -1
+func findEndpoint(endpoints []models.EndpointSpec, path string) int {
+	for i, endpoint := range endpoints {
+		if endpoint.Path == path {
+			return i
+		}
+	}
+	return -1
+}
+
+// copySpecDefinition copies the endpoint and operation slices of a spec so patches do not leak
+func copySpecDefinition(spec models.ServiceSpec) models.ServiceSpec {
+	definition := *spec.Spec
+	definition.Endpoints = make([]models.EndpointSpec, len(spec.Spec.Endpoints))
+	for i, endpoint := range spec.Spec.Endpoints {
+		endpoint.Operations = append([]models.OperationSpec(nil), endpoint.Operations...)
+		definition.Endpoints[i] = endpoint
+	}
+	spec.Spec = &definition
+	return spec
+}
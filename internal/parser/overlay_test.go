@@ -0,0 +1,215 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOverlayBaseSpec() models.ServiceSpec {
+	return models.ServiceSpec{
+		APIVersion: "flowspec/v1alpha1",
+		Kind:       "ServiceSpec",
+		Metadata:   &models.ServiceSpecMetadata{Name: "user-service", Version: "v1.0.0"},
+		Spec: &models.ServiceSpecDefinition{
+			Endpoints: []models.EndpointSpec{
+				{
+					Path: "/users",
+					Operations: []models.OperationSpec{
+						{Method: "GET", Responses: models.ResponseSpec{StatusCodes: []int{200}}},
+						{Method: "POST", Responses: models.ResponseSpec{StatusCodes: []int{201}}},
+					},
+				},
+				{
+					Path: "/debug",
+					Operations: []models.OperationSpec{
+						{Method: "GET", Responses: models.ResponseSpec{StatusCodes: []int{200}}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func writeOverlay(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "overlay.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestApplyOverlays(t *testing.T) {
+	overlay, err := LoadOverlay(writeOverlay(t, `apiVersion: flowspec/v1alpha1
+kind: ServiceSpecOverlay
+metadata:
+  name: prod
+endpoints:
+  - path: /debug
+    disabled: true
+  - path: /users
+    operations:
+      - method: post
+        responses:
+          statusCodes: [201, 409]
+      - method: DELETE
+        responses:
+          statusRanges: ["2xx"]
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "prod", overlay.Metadata.Name)
+
+	base := newOverlayBaseSpec()
+	specs, err := ApplyOverlays([]models.ServiceSpec{base}, []*Overlay{overlay})
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+
+	endpoints := specs[0].Spec.Endpoints
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, "/users", endpoints[0].Path)
+	require.Len(t, endpoints[0].Operations, 3)
+	assert.Equal(t, []int{200}, endpoints[0].Operations[0].Responses.StatusCodes)
+	assert.Equal(t, []int{201, 409}, endpoints[0].Operations[1].Responses.StatusCodes)
+	assert.Equal(t, "DELETE", endpoints[0].Operations[2].Method)
+
+	// The base contract is left untouched
+	assert.Len(t, base.Spec.Endpoints, 2)
+	assert.Len(t, base.Spec.Endpoints[0].Operations, 2)
+	assert.Equal(t, []int{201}, base.Spec.Endpoints[0].Operations[1].Responses.StatusCodes)
+}
+
+func TestApplyOverlays_Errors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		overlay       string
+		expectedError string
+	}{
+		{
+			name: "unknown endpoint",
+			overlay: `kind: ServiceSpecOverlay
+endpoints:
+  - path: /missing
+    disabled: true
+`,
+			expectedError: "cannot disable unknown endpoint /missing",
+		},
+		{
+			name: "new operation without responses",
+			overlay: `kind: ServiceSpecOverlay
+endpoints:
+  - path: /users
+    operations:
+      - method: PUT
+`,
+			expectedError: "new operation PUT /users must define responses",
+		},
+		{
+			name: "every endpoint disabled",
+			overlay: `kind: ServiceSpecOverlay
+endpoints:
+  - path: /users
+    disabled: true
+  - path: /debug
+    operations:
+      - method: GET
+        disabled: true
+`,
+			expectedError: "overlays disable every endpoint of user-service",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			overlay, err := LoadOverlay(writeOverlay(t, tc.overlay))
+			require.NoError(t, err)
+
+			_, err = ApplyOverlays([]models.ServiceSpec{newOverlayBaseSpec()}, []*Overlay{overlay})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestApplyOverlays_ServiceFilter(t *testing.T) {
+	overlay, err := LoadOverlay(writeOverlay(t, `kind: ServiceSpecOverlay
+service: order-service
+endpoints:
+  - path: /missing
+    disabled: true
+`))
+	require.NoError(t, err)
+
+	specs, err := ApplyOverlays([]models.ServiceSpec{newOverlayBaseSpec()}, []*Overlay{overlay})
+	require.NoError(t, err)
+	assert.Len(t, specs[0].Spec.Endpoints, 2)
+}
+
+func TestApplyOverlays_SeveralServices(t *testing.T) {
+	users := newOverlayBaseSpec()
+	orders := newOverlayBaseSpec()
+	orders.Metadata = &models.ServiceSpecMetadata{Name: "order-service", Version: "v2.0.0"}
+	orders.Spec = &models.ServiceSpecDefinition{Endpoints: []models.EndpointSpec{{
+		Path:       "/orders",
+		Operations: []models.OperationSpec{{Method: "GET", Responses: models.ResponseSpec{StatusCodes: []int{200}}}},
+	}}}
+	content := `kind: ServiceSpecOverlay
+endpoints:
+  - path: /debug
+    disabled: true
+  - path: /health
+    operations:
+      - method: GET
+        responses:
+          statusCodes: [200]
+`
+
+	overlay, err := LoadOverlay(writeOverlay(t, content))
+	require.NoError(t, err)
+	_, err = ApplyOverlays([]models.ServiceSpec{users, orders}, []*Overlay{overlay})
+	assert.ErrorContains(t, err, "must set service, the contracts define several services: user-service, order-service")
+
+	overlay, err = LoadOverlay(writeOverlay(t, "service: user-service\n"+content))
+	require.NoError(t, err)
+	specs, err := ApplyOverlays([]models.ServiceSpec{users, orders}, []*Overlay{overlay})
+	require.NoError(t, err)
+	require.Len(t, specs, 2)
+	assert.Equal(t, []string{"/users", "/health"}, endpointPaths(specs[0]))
+	assert.Equal(t, []string{"/orders"}, endpointPaths(specs[1]), "other services are not patched")
+}
+
+// endpointPaths lists the endpoint paths of a spec
+func endpointPaths(spec models.ServiceSpec) []string {
+	var paths []string
+	for _, endpoint := range spec.Spec.Endpoints {
+		paths = append(paths, endpoint.Path)
+	}
+	return paths
+}
+
+func TestLoadOverlay_Invalid(t *testing.T) {
+	_, err := LoadOverlay(writeOverlay(t, "kind: ServiceSpec\n"))
+	assert.ErrorContains(t, err, `expected "ServiceSpecOverlay"`)
+
+	_, err = LoadOverlay(writeOverlay(t, "kind: ServiceSpecOverlay\nendpoint: []\n"))
+	assert.ErrorContains(t, err, "field endpoint not found")
+
+	_, err = LoadOverlay(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
@@ -20,13 +20,19 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/flowspec/flowspec-cli/internal/concurrency"
 	"github.com/flowspec/flowspec-cli/internal/models"
 )
 
+// serviceSpecKindPattern matches the kind declaration of a ServiceSpec YAML document
+var serviceSpecKindPattern = regexp.MustCompile(`(?m)^kind:\s*["']?ServiceSpec["']?\s*(#.*)?$`)
+
 // Hash format constants
 const (
 	hexFormat = "%x"
@@ -114,7 +120,7 @@ type ParseMetrics struct {
 // DefaultParserConfig returns a default parser configuration
 func DefaultParserConfig() *ParserConfig {
 	return &ParserConfig{
-		MaxWorkers:    4,
+		MaxWorkers:    concurrency.Workers(concurrency.Auto),
 		SkipHidden:    true,
 		SkipVendor:    true,
 		MaxFileSize:   10 * 1024 * 1024, // 10MB
@@ -243,6 +249,10 @@ func (p *DefaultSpecParser) ParseFromSource(sourcePath string) (*models.ParseRes
 		return nil, err
 	}
 
+	var duplicateErrors []models.ParseError
+	result.Specs, duplicateErrors = removeDuplicateServices(result.Specs)
+	result.Errors = append(result.Errors, duplicateErrors...)
+
 	// Update metrics
 	metrics.ProcessedFiles = len(files)
 	metrics.TotalSpecs = len(result.Specs)
@@ -287,7 +297,7 @@ func (p *DefaultSpecParser) scanFiles(rootPath string) ([]string, error) {
 }
 
 // scanFilesWithYAMLPriority scans directory with YAML priority logic
-// Priority: service-spec.yaml > other YAML files > source code files
+// Priority: service-spec.yaml > all ServiceSpec YAML files > source code files
 func (p *DefaultSpecParser) scanFilesWithYAMLPriority(rootPath string) ([]string, error) {
 	// First, look for YAML files in the root directory
 	yamlFiles, err := p.findYAMLFiles(rootPath)
@@ -295,17 +305,20 @@ func (p *DefaultSpecParser) scanFilesWithYAMLPriority(rootPath string) ([]string
 		return nil, err
 	}
 
-	// If we found YAML files, use them with priority logic
-	if len(yamlFiles) > 0 {
-		prioritizedFiles := p.prioritizeYAMLFiles(yamlFiles)
-		
-		// Check for conflicts: if we have multiple YAML files but no service-spec.yaml
-		if len(prioritizedFiles) > 1 && !p.hasServiceSpecYAML(yamlFiles) {
-			return nil, fmt.Errorf("multiple YAML files found but no service-spec.yaml. Found files: %s. Please use --path to specify the exact file you want to use", 
-				strings.Join(p.getFileNames(yamlFiles), ", "))
+	// service-spec.yaml keeps precedence so existing single-contract layouts are unchanged
+	if p.hasServiceSpecYAML(yamlFiles) {
+		return p.prioritizeYAMLFiles(yamlFiles), nil
+	}
+
+	// Otherwise load every YAML file holding ServiceSpec documents, ignoring unrelated YAML
+	var contractFiles []string
+	for _, file := range yamlFiles {
+		if isServiceSpecYAMLFile(file) {
+			contractFiles = append(contractFiles, file)
 		}
-		
-		return prioritizedFiles, nil
+	}
+	if len(contractFiles) > 0 {
+		return contractFiles, nil
 	}
 
 	// No YAML files found, fallback to source code scanning
@@ -371,6 +384,50 @@ func (p *DefaultSpecParser) prioritizeYAMLFiles(yamlFiles []string) []string {
 	return yamlFiles
 }
 
+// isServiceSpecYAMLFile reports whether a YAML file declares at least one ServiceSpec document
+func isServiceSpecYAMLFile(filename string) bool {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return false
+	}
+	return serviceSpecKindPattern.Match(content)
+}
+
+// removeDuplicateServices drops and reports ServiceSpec documents that define the same service name
+// and version as an earlier document, since their results could not be told apart in the report
+func removeDuplicateServices(specs []models.ServiceSpec) ([]models.ServiceSpec, []models.ParseError) {
+	// Specs arrive in worker completion order; sort for deterministic reporting
+	sort.SliceStable(specs, func(i, j int) bool {
+		if specs[i].SourceFile != specs[j].SourceFile {
+			return specs[i].SourceFile < specs[j].SourceFile
+		}
+		return specs[i].LineNumber < specs[j].LineNumber
+	})
+
+	unique := make([]models.ServiceSpec, 0, len(specs))
+	var errors []models.ParseError
+	seen := make(map[string]models.ServiceSpec)
+	for _, spec := range specs {
+		if !spec.IsYAMLFormat() {
+			unique = append(unique, spec)
+			continue
+		}
+		key := spec.Metadata.Name + "@" + spec.Metadata.Version
+		if first, exists := seen[key]; exists {
+			errors = append(errors, models.ParseError{
+				File: spec.SourceFile,
+				Line: spec.LineNumber,
+				Message: fmt.Sprintf("service %s %s is already defined in %s:%d",
+					spec.Metadata.Name, spec.Metadata.Version, first.SourceFile, first.LineNumber),
+			})
+			continue
+		}
+		seen[key] = spec
+		unique = append(unique, spec)
+	}
+	return unique, errors
+}
+
 // isYAMLFile checks if a file is a YAML file
 func (p *DefaultSpecParser) isYAMLFile(filename string) bool {
 	ext := strings.ToLower(filepath.Ext(filename))
@@ -459,10 +516,7 @@ func (p *DefaultSpecParser) parseFilesWithMetrics(files []string, metrics *Parse
 	errorsChan := make(chan []models.ParseError, len(files))
 
 	// Create worker pool
-	workers := p.maxWorkers
-	if workers > len(files) {
-		workers = len(files)
-	}
+	workers := concurrency.Bounded(p.maxWorkers, len(files))
 
 	fileChan := make(chan string, len(files))
 	var wg sync.WaitGroup
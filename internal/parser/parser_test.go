@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/flowspec/flowspec-cli/internal/models"
@@ -49,7 +50,7 @@ func TestNewSpecParser(t *testing.T) {
 	parser := NewSpecParser()
 
 	assert.NotNil(t, parser)
-	assert.Equal(t, 4, parser.maxWorkers)
+	assert.Equal(t, runtime.GOMAXPROCS(0), parser.maxWorkers)
 	assert.NotNil(t, parser.fileParsers)
 	assert.NotNil(t, parser.supportedTypes)
 	assert.Equal(t, 4, parser.GetFileCount()) // Java, TypeScript, Go, and YAML parsers registered by default
@@ -72,7 +73,7 @@ func TestDefaultParserConfig(t *testing.T) {
 	config := DefaultParserConfig()
 
 	assert.NotNil(t, config)
-	assert.Equal(t, 4, config.MaxWorkers)
+	assert.Equal(t, runtime.GOMAXPROCS(0), config.MaxWorkers)
 	assert.True(t, config.SkipHidden)
 	assert.True(t, config.SkipVendor)
 	assert.Equal(t, int64(10*1024*1024), config.MaxFileSize)
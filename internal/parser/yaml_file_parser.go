@@ -15,7 +15,10 @@
 package parser
 
 import (
+	"bytes"
+	stderrors "errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -25,20 +28,29 @@ import (
 )
 
 // YAMLFileParser implements the FileParser interface for YAML files
-type YAMLFileParser struct{}
+type YAMLFileParser struct {
+	localReferences bool // Only resolve $ref and include within the contract itself
+}
 
 // NewYAMLFileParser creates a new YAML file parser
 func NewYAMLFileParser() *YAMLFileParser {
 	return &YAMLFileParser{}
 }
 
+// NewSandboxedYAMLFileParser creates a YAML file parser for untrusted contracts: $ref and
+// include may only point inside the contract itself, never at other files
+func NewSandboxedYAMLFileParser() *YAMLFileParser {
+	return &YAMLFileParser{localReferences: true}
+}
+
 // CanParse returns true if the file has a .yaml or .yml extension
 func (y *YAMLFileParser) CanParse(filename string) bool {
 	ext := strings.ToLower(filepath.Ext(filename))
 	return ext == ".yaml" || ext == ".yml"
 }
 
-// ParseFile parses a YAML file and returns ServiceSpecs and any parse errors
+// ParseFile parses a YAML file and returns ServiceSpecs and any parse errors.
+// A file may hold several ServiceSpec documents separated by "---".
 func (y *YAMLFileParser) ParseFile(filepath string) ([]models.ServiceSpec, []models.ParseError) {
 	var specs []models.ServiceSpec
 	var errors []models.ParseError
@@ -54,20 +66,33 @@ func (y *YAMLFileParser) ParseFile(filepath string) ([]models.ServiceSpec, []mod
 		return specs, errors
 	}
 
-	// Parse YAML
-	var spec models.ServiceSpec
-	err = yaml.Unmarshal(data, &spec)
-	if err != nil {
-		// Try to extract line and column information from YAML error
-		lineNum, colNum := extractLineColumnFromYAMLError(err)
+	// Parse YAML documents
+	var documents []*yaml.Node
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		document := &yaml.Node{}
+		err := decoder.Decode(document)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Try to extract line and column information from YAML error
+			lineNum, colNum := extractLineColumnFromYAMLError(err)
 
-		errors = append(errors, models.ParseError{
-			File:    filepath,
-			Line:    lineNum,
-			Column:  colNum,
-			Message: fmt.Sprintf("failed to parse YAML: %s", err.Error()),
-		})
-		return specs, errors
+			errors = append(errors, models.ParseError{
+				File:    filepath,
+				Line:    lineNum,
+				Column:  colNum,
+				Message: fmt.Sprintf("failed to parse YAML: %s", err.Error()),
+			})
+			return specs, errors
+		}
+
+		// Skip empty documents such as a trailing "---"
+		if len(document.Content) == 0 || isNullNode(document.Content[0]) {
+			continue
+		}
+		documents = append(documents, document)
 	}
 
 	// Create schema validator
@@ -81,24 +106,112 @@ func (y *YAMLFileParser) ParseFile(filepath string) ([]models.ServiceSpec, []mod
 		return specs, errors
 	}
 
-	// Validate using JSON Schema
+	if len(documents) == 0 {
+		errors = append(errors, models.ParseError{
+			File:    filepath,
+			Line:    1,
+			Message: "file contains no ServiceSpec documents",
+		})
+		return specs, errors
+	}
+
+	for _, document := range documents {
+		spec, documentErrors := y.parseDocument(filepath, validator, document)
+		errors = append(errors, documentErrors...)
+		if spec != nil {
+			specs = append(specs, *spec)
+		}
+	}
+
+	return specs, errors
+}
+
+// parseDocument validates and decodes a single YAML document; the spec is nil when it has errors
+func (y *YAMLFileParser) parseDocument(filepath string, validator *SchemaValidator, document *yaml.Node) (*models.ServiceSpec, []models.ParseError) {
+	var errors []models.ParseError
+	documentLine := document.Content[0].Line
+
+	// Expand include and $ref directives so the composed contract is validated as a whole
+	resolver := newReferenceResolver()
+	resolver.localOnly = y.localReferences
+	resolved, err := resolver.resolveDocument(document, filepath)
+	if err != nil {
+		var refErr *ReferenceError
+		if stderrors.As(err, &refErr) {
+			return nil, []models.ParseError{refErr.ToParseError()}
+		}
+		return nil, []models.ParseError{{File: filepath, Line: documentLine, Message: err.Error()}}
+	}
+
+	// Validate the document as written, so unknown fields and wrong types are reported with their location
+	for _, schemaError := range validator.validateDocument(resolved, resolver.origins) {
+		if schemaError.File == "" {
+			schemaError.File = filepath
+		}
+		errors = append(errors, schemaError)
+	}
+	if len(errors) > 0 {
+		return nil, errors
+	}
+
+	var spec models.ServiceSpec
+	if err := resolved.Decode(&spec); err != nil {
+		lineNum, colNum := extractLineColumnFromYAMLError(err)
+
+		errors = append(errors, models.ParseError{
+			File:    filepath,
+			Line:    lineNum,
+			Column:  colNum,
+			Message: fmt.Sprintf("failed to parse YAML: %s", err.Error()),
+		})
+		return nil, errors
+	}
+
+	// Validate decoded values using JSON Schema rules
 	schemaErrors := validator.ValidateServiceSpec(&spec)
 	for _, schemaError := range schemaErrors {
 		schemaError.File = filepath
+		if schemaError.Line == 0 {
+			schemaError.Line = documentLine
+		}
 		errors = append(errors, schemaError)
 	}
 
 	// If there are validation errors, don't return the spec
 	if len(errors) > 0 {
-		return specs, errors
+		return nil, errors
 	}
 
 	// Set source file information
 	spec.SourceFile = filepath
-	spec.LineNumber = 1 // YAML files start at line 1
+	spec.LineNumber = documentLine
+	recordOperationLocations(&spec, resolved, resolver.origins, filepath)
 
-	specs = append(specs, spec)
-	return specs, errors
+	return &spec, errors
+}
+
+// recordOperationLocations sets the file and line declaring each operation, following
+// references into the files they were included from
+func recordOperationLocations(spec *models.ServiceSpec, document *yaml.Node, origins map[*yaml.Node]string, filepath string) {
+	for i := range spec.Spec.Endpoints {
+		endpoint := &spec.Spec.Endpoints[i]
+		for j := range endpoint.Operations {
+			node, err := lookupPointer(document, fmt.Sprintf("/spec/endpoints/%d/operations/%d", i, j))
+			if err != nil {
+				continue
+			}
+			endpoint.Operations[j].SourceFile = filepath
+			if origin, ok := origins[node]; ok {
+				endpoint.Operations[j].SourceFile = origin
+			}
+			endpoint.Operations[j].LineNumber = node.Line
+		}
+	}
+}
+
+// isNullNode reports whether a node is an explicit or implicit YAML null
+func isNullNode(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.ShortTag() == "!!null"
 }
 
 // extractLineColumnFromYAMLError attempts to extract line and column information from YAML error
@@ -15,10 +15,12 @@
 package parser
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/flowspec/flowspec-cli/internal/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -253,6 +255,179 @@ spec:
 	assert.True(t, hasKindError, "Should have error about missing kind")
 }
 
+func TestYAMLFileParser_ParseFile_UnknownFieldLocation(t *testing.T) {
+	parser := NewYAMLFileParser()
+
+	tmpDir := t.TempDir()
+	yamlFile := filepath.Join(tmpDir, "unknown-field.yaml")
+
+	unknownFieldYAML := `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: test-service
+  version: v1.0.0
+spec:
+  endpoints:
+    - path: /api/users
+      operations:
+        - method: GET
+          response:
+            statusCodes: [200]
+`
+
+	err := os.WriteFile(yamlFile, []byte(unknownFieldYAML), 0644)
+	require.NoError(t, err)
+
+	specs, errors := parser.ParseFile(yamlFile)
+	assert.Empty(t, specs)
+	require.Len(t, errors, 2)
+
+	// The misspelled key is reported where it appears, followed by the missing field it replaced
+	assert.Equal(t, yamlFile, errors[0].File)
+	assert.Equal(t, 11, errors[0].Line)
+	assert.Equal(t, 11, errors[0].Column)
+	assert.Equal(t, "/spec/endpoints/0/operations/0/response", errors[0].JSONPointer)
+	assert.Contains(t, errors[0].Message, `unknown field "response"`)
+	assert.Equal(t, "/spec/endpoints/0/operations/0/responses", errors[1].JSONPointer)
+	assert.Contains(t, errors[1].Message, `missing required field "responses"`)
+}
+
+func TestYAMLFileParser_ParseFile_Severity(t *testing.T) {
+	parser := NewYAMLFileParser()
+	tmpDir := t.TempDir()
+	contract := `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: test-service
+  version: v1.0.0
+spec:
+  endpoints:
+    - path: /api/users
+      operations:
+        - method: GET
+          severity: %s
+          responses:
+            statusCodes: [200]
+`
+
+	validFile := filepath.Join(tmpDir, "warning.yaml")
+	require.NoError(t, os.WriteFile(validFile, []byte(fmt.Sprintf(contract, "warning")), 0644))
+	specs, errors := parser.ParseFile(validFile)
+	require.Empty(t, errors)
+	assert.Equal(t, models.SeverityWarning, specs[0].Spec.Endpoints[0].Operations[0].Severity)
+
+	invalidFile := filepath.Join(tmpDir, "critical.yaml")
+	require.NoError(t, os.WriteFile(invalidFile, []byte(fmt.Sprintf(contract, "critical")), 0644))
+	specs, errors = parser.ParseFile(invalidFile)
+	assert.Empty(t, specs)
+	require.NotEmpty(t, errors)
+	assert.Equal(t, "/spec/endpoints/0/operations/0/severity", errors[0].JSONPointer)
+}
+
+func TestYAMLFileParser_ParseFile_Priority(t *testing.T) {
+	parser := NewYAMLFileParser()
+	tmpDir := t.TempDir()
+	contract := `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: test-service
+  version: v1.0.0
+spec:
+  endpoints:
+    - path: /api/checkout
+      operations:
+        - method: POST
+          priority: %s
+          responses:
+            statusCodes: [200]
+`
+
+	validFile := filepath.Join(tmpDir, "critical.yaml")
+	require.NoError(t, os.WriteFile(validFile, []byte(fmt.Sprintf(contract, "critical")), 0644))
+	specs, errors := parser.ParseFile(validFile)
+	require.Empty(t, errors)
+	assert.Equal(t, models.PriorityCritical, specs[0].Spec.Endpoints[0].Operations[0].Priority)
+
+	invalidFile := filepath.Join(tmpDir, "urgent.yaml")
+	require.NoError(t, os.WriteFile(invalidFile, []byte(fmt.Sprintf(contract, "urgent")), 0644))
+	specs, errors = parser.ParseFile(invalidFile)
+	assert.Empty(t, specs)
+	require.NotEmpty(t, errors)
+	assert.Equal(t, "/spec/endpoints/0/operations/0/priority", errors[0].JSONPointer)
+}
+
+func TestYAMLFileParser_ParseFile_WhenGuards(t *testing.T) {
+	parser := NewYAMLFileParser()
+	tmpDir := t.TempDir()
+	contract := `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: test-service
+  version: v1.0.0
+spec:
+  endpoints:
+    - path: /api/users
+      operations:
+        - method: GET
+          when: {"==": [{"var": "span.attributes.http.scheme"}, "https"]}
+          responses:
+            statusCodes: [200]
+          required:
+            headers: [authorization]
+            when: %s
+`
+
+	validFile := filepath.Join(tmpDir, "valid.yaml")
+	require.NoError(t, os.WriteFile(validFile, []byte(fmt.Sprintf(contract, `{"!=": [{"var": "span.attributes.http.target"}, "/health"]}`)), 0644))
+	specs, errors := parser.ParseFile(validFile)
+	require.Empty(t, errors)
+	operation := specs[0].Spec.Endpoints[0].Operations[0]
+	assert.Contains(t, operation.When, "==")
+	assert.Contains(t, operation.Required.When, "!=")
+
+	invalidFile := filepath.Join(tmpDir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalidFile, []byte(fmt.Sprintf(contract, "/health")), 0644))
+	specs, errors = parser.ParseFile(invalidFile)
+	assert.Empty(t, specs)
+	require.NotEmpty(t, errors)
+	assert.Equal(t, "/spec/endpoints/0/operations/0/required/when", errors[0].JSONPointer)
+}
+
+func TestYAMLFileParser_ParseFile_Tags(t *testing.T) {
+	parser := NewYAMLFileParser()
+	tmpDir := t.TempDir()
+	contract := `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: test-service
+  version: v1.0.0
+spec:
+  endpoints:
+    - path: /api/payments
+      tags: [payments]
+      operations:
+        - method: POST
+          tags: %s
+          responses:
+            statusCodes: [201]
+`
+
+	validFile := filepath.Join(tmpDir, "valid.yaml")
+	require.NoError(t, os.WriteFile(validFile, []byte(fmt.Sprintf(contract, "[critical]")), 0644))
+	specs, errors := parser.ParseFile(validFile)
+	require.Empty(t, errors)
+	endpoint := specs[0].Spec.Endpoints[0]
+	assert.Equal(t, []string{"payments"}, endpoint.Tags)
+	assert.Equal(t, []string{"critical"}, endpoint.Operations[0].Tags)
+
+	invalidFile := filepath.Join(tmpDir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalidFile, []byte(fmt.Sprintf(contract, "critical")), 0644))
+	specs, errors = parser.ParseFile(invalidFile)
+	assert.Empty(t, specs)
+	require.NotEmpty(t, errors)
+	assert.Equal(t, "/spec/endpoints/0/operations/0/tags", errors[0].JSONPointer)
+}
+
 func TestYAMLFileParser_ParseFile_FileNotFound(t *testing.T) {
 	parser := NewYAMLFileParser()
 
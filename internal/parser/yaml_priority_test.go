@@ -227,13 +227,14 @@ spec:
 	// Parse the directory
 	result, err := parser.ParseFromSource(tmpDir)
 
-	// Verify results - should return conflict error when multiple YAML files exist without service-spec.yaml
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "multiple YAML files found but no service-spec.yaml")
-	assert.Contains(t, err.Error(), "service1.yaml")
-	assert.Contains(t, err.Error(), "service2.yaml")
-	assert.Contains(t, err.Error(), "Please use --path to specify the exact file")
-	assert.Nil(t, result)
+	// Verify results - every contract in the directory is loaded when there is no service-spec.yaml
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+	require.Len(t, result.Specs, 2)
+	assert.Equal(t, "service1", result.Specs[0].Metadata.Name)
+	assert.Equal(t, yaml1File, result.Specs[0].SourceFile)
+	assert.Equal(t, "service2", result.Specs[1].Metadata.Name)
+	assert.Equal(t, yaml2File, result.Specs[1].SourceFile)
 }
 
 func TestDefaultSpecParser_ParseFromSource_UnsupportedSingleFile(t *testing.T) {
@@ -369,17 +370,69 @@ spec:
 	err = os.WriteFile(yaml3File, []byte(yaml1Content), 0644) // Reuse content
 	require.NoError(t, err)
 
-	// Parse the directory - should return conflict error
+	// Unrelated YAML files are ignored
+	err = os.WriteFile(filepath.Join(tmpDir, "docker-compose.yml"), []byte("services:\n  app:\n    image: app\n"), 0644)
+	require.NoError(t, err)
+
+	// Parse the directory - the duplicate service definition is reported and dropped
 	result, err := parser.ParseFromSource(tmpDir)
+	require.NoError(t, err)
 
-	// Verify error
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "multiple YAML files found but no service-spec.yaml")
-	assert.Contains(t, err.Error(), "service1.yaml")
-	assert.Contains(t, err.Error(), "service2.yaml")
-	assert.Contains(t, err.Error(), "service3.yml")
-	assert.Contains(t, err.Error(), "Please use --path to specify the exact file")
-	assert.Nil(t, result)
+	require.Len(t, result.Specs, 2)
+	assert.Equal(t, "service1", result.Specs[0].Metadata.Name)
+	assert.Equal(t, "service2", result.Specs[1].Metadata.Name)
+
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, yaml3File, result.Errors[0].File)
+	assert.Contains(t, result.Errors[0].Message, "service service1 v1.0.0 is already defined in "+yaml1File)
+}
+
+func TestDefaultSpecParser_ParseFromSource_MultiDocumentYAML(t *testing.T) {
+	parser := NewSpecParser()
+
+	multiDocContent := `# Contracts for the checkout domain
+apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: cart-service
+  version: v1.0.0
+spec:
+  endpoints:
+    - path: /api/cart
+      operations:
+        - method: GET
+          responses:
+            statusRanges: ["2xx"]
+---
+apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: payment-service
+  version: v2.0.0
+spec:
+  endpoints:
+    - path: /api/payments
+      operations:
+        - method: POST
+          responses:
+            statusCodes: [201]
+---
+`
+
+	tmpDir := t.TempDir()
+	yamlFile := filepath.Join(tmpDir, "contracts.yaml")
+	err := os.WriteFile(yamlFile, []byte(multiDocContent), 0644)
+	require.NoError(t, err)
+
+	result, err := parser.ParseFromSource(yamlFile)
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+	require.Len(t, result.Specs, 2)
+
+	assert.Equal(t, "cart-service", result.Specs[0].Metadata.Name)
+	assert.Equal(t, 2, result.Specs[0].LineNumber)
+	assert.Equal(t, "payment-service", result.Specs[1].Metadata.Name)
+	assert.Equal(t, 15, result.Specs[1].LineNumber)
 }
 
 func TestDefaultSpecParser_hasServiceSpecYAML(t *testing.T) {
@@ -0,0 +1,353 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// refKey replaces the mapping it appears in with the referenced node; sibling keys override
+	// fields of a referenced mapping
+	refKey = "$ref"
+
+	// includeKey merges one or more referenced mappings into the mapping it appears in
+	includeKey = "include"
+)
+
+// ReferenceError describes a $ref or include that could not be resolved
+type ReferenceError struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// Error implements the error interface
+func (e *ReferenceError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+}
+
+// ToParseError converts the reference error into a parse error
+func (e *ReferenceError) ToParseError() models.ParseError {
+	return models.ParseError{File: e.File, Line: e.Line, Column: e.Column, Message: e.Message}
+}
+
+// referenceResolver expands $ref and include directives in YAML contract documents.
+// Referenced files are resolved relative to the file containing the reference.
+type referenceResolver struct {
+	roots   map[string]*yaml.Node // Root node of each loaded file, keyed by absolute path
+	origins map[*yaml.Node]string // File each resolved node was copied from, as displayed to users
+	stack   []string              // References being resolved, for cycle detection
+	chain   []string              // Display form of the references in stack
+
+	localOnly bool // Reject references to other files
+}
+
+// newReferenceResolver creates a resolver for one document
+func newReferenceResolver() *referenceResolver {
+	return &referenceResolver{
+		roots:   make(map[string]*yaml.Node),
+		origins: make(map[*yaml.Node]string),
+	}
+}
+
+// ReferencedFiles returns the absolute paths of the other files the YAML contract at path pulls
+// in with $ref and include, directly or through referenced files, sorted
+func ReferencedFiles(path string) ([]string, error) {
+	absFile, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+	file, err := os.Open(absFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	resolver := newReferenceResolver()
+	decoder := yaml.NewDecoder(file)
+	for {
+		var document yaml.Node
+		if err := decoder.Decode(&document); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if _, err := resolver.resolveDocument(&document, path); err != nil {
+			return nil, err
+		}
+	}
+
+	var files []string
+	for file := range resolver.roots {
+		if file != absFile {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// resolveDocument returns a copy of the document with all references expanded
+func (r *referenceResolver) resolveDocument(document *yaml.Node, file string) (*yaml.Node, error) {
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path %s: %w", file, err)
+	}
+	r.roots[absFile] = document
+	return r.resolve(document, absFile, file)
+}
+
+// resolve copies a node, expanding references found in it; displayFile is used in error messages
+func (r *referenceResolver) resolve(node *yaml.Node, absFile, displayFile string) (*yaml.Node, error) {
+	node = resolveAlias(node)
+
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		resolved := r.copyNode(node, displayFile)
+		for _, child := range node.Content {
+			resolvedChild, err := r.resolve(child, absFile, displayFile)
+			if err != nil {
+				return nil, err
+			}
+			resolved.Content = append(resolved.Content, resolvedChild)
+		}
+		return resolved, nil
+	case yaml.MappingNode:
+		return r.resolveMapping(node, absFile, displayFile)
+	}
+	return r.copyNode(node, displayFile), nil
+}
+
+// resolveMapping expands include and $ref keys of a mapping, letting explicit keys override merged ones
+func (r *referenceResolver) resolveMapping(node *yaml.Node, absFile, displayFile string) (*yaml.Node, error) {
+	var base [][2]*yaml.Node
+	var explicit [][2]*yaml.Node
+	var refNode *yaml.Node
+
+	for _, pair := range mappingPairs(node) {
+		key, value := pair[0], resolveAlias(pair[1])
+		switch key.Value {
+		case includeKey:
+			targets := []*yaml.Node{value}
+			if value.Kind == yaml.SequenceNode {
+				targets = value.Content
+			}
+			for _, target := range targets {
+				resolved, err := r.resolveReference(resolveAlias(target), absFile, displayFile)
+				if err != nil {
+					return nil, err
+				}
+				if resolved.Kind != yaml.MappingNode {
+					return nil, r.errorAt(target, displayFile, "include %q must refer to a mapping", target.Value)
+				}
+				base = append(base, mappingPairs(resolved)...)
+			}
+		case refKey:
+			refNode = value
+		default:
+			explicit = append(explicit, pair)
+		}
+	}
+
+	if refNode != nil {
+		resolved, err := r.resolveReference(refNode, absFile, displayFile)
+		if err != nil {
+			return nil, err
+		}
+		if resolved.Kind != yaml.MappingNode {
+			if len(base) > 0 || len(explicit) > 0 {
+				return nil, r.errorAt(refNode, displayFile, "$ref %q refers to a non-mapping value and cannot be combined with other keys", refNode.Value)
+			}
+			return resolved, nil
+		}
+		base = append(base, mappingPairs(resolved)...)
+	}
+
+	resolved := r.copyNode(node, displayFile)
+	overridden := make(map[string]bool)
+	var resolvedExplicit []*yaml.Node
+	for _, pair := range explicit {
+		value, err := r.resolve(pair[1], absFile, displayFile)
+		if err != nil {
+			return nil, err
+		}
+		overridden[pair[0].Value] = true
+		resolvedExplicit = append(resolvedExplicit, r.copyNode(pair[0], displayFile), value)
+	}
+
+	// Merged keys keep their position ahead of explicit keys; later includes win over earlier ones
+	merged := make(map[string]int)
+	for _, pair := range base {
+		if overridden[pair[0].Value] {
+			continue
+		}
+		if index, exists := merged[pair[0].Value]; exists {
+			resolved.Content[index+1] = pair[1]
+			continue
+		}
+		merged[pair[0].Value] = len(resolved.Content)
+		resolved.Content = append(resolved.Content, pair[0], pair[1])
+	}
+	resolved.Content = append(resolved.Content, resolvedExplicit...)
+
+	return resolved, nil
+}
+
+// resolveReference loads and resolves the node a "file.yaml#/pointer" reference points to
+func (r *referenceResolver) resolveReference(ref *yaml.Node, absFile, displayFile string) (*yaml.Node, error) {
+	if ref.Kind != yaml.ScalarNode || ref.Value == "" {
+		return nil, r.errorAt(ref, displayFile, "reference must be a non-empty string")
+	}
+
+	targetPath, pointer, _ := strings.Cut(ref.Value, "#")
+	targetFile := absFile
+	targetDisplay := displayFile
+	if targetPath != "" && r.localOnly {
+		return nil, r.errorAt(ref, displayFile, "reference %q points at another file, which is not allowed here", ref.Value)
+	}
+	if targetPath != "" {
+		targetFile = filepath.Join(filepath.Dir(absFile), targetPath)
+		targetDisplay = filepath.Join(filepath.Dir(displayFile), targetPath)
+	}
+
+	key := targetFile + "#" + pointer
+	display := targetDisplay + "#" + pointer
+	for i, entry := range r.stack {
+		if entry == key {
+			chain := append(append([]string{}, r.chain[i:]...), display)
+			return nil, r.errorAt(ref, displayFile, "circular reference: %s", strings.Join(chain, " -> "))
+		}
+	}
+
+	root, err := r.loadRoot(targetFile)
+	if err != nil {
+		return nil, r.errorAt(ref, displayFile, "cannot load %q: %v", ref.Value, err)
+	}
+
+	target, err := lookupPointer(root, pointer)
+	if err != nil {
+		return nil, r.errorAt(ref, displayFile, "cannot resolve %q: %v", ref.Value, err)
+	}
+
+	r.stack = append(r.stack, key)
+	r.chain = append(r.chain, display)
+	defer func() {
+		r.stack = r.stack[:len(r.stack)-1]
+		r.chain = r.chain[:len(r.chain)-1]
+	}()
+
+	return r.resolve(target, targetFile, targetDisplay)
+}
+
+// loadRoot returns the root content node of a YAML file, loading it on first use
+func (r *referenceResolver) loadRoot(absFile string) (*yaml.Node, error) {
+	if root, exists := r.roots[absFile]; exists {
+		return root, nil
+	}
+
+	data, err := os.ReadFile(absFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	r.roots[absFile] = &document
+	return &document, nil
+}
+
+// copyNode makes a shallow copy of a node without children and records the file it came from
+func (r *referenceResolver) copyNode(node *yaml.Node, file string) *yaml.Node {
+	copied := &yaml.Node{
+		Kind:        node.Kind,
+		Style:       node.Style,
+		Tag:         node.Tag,
+		Value:       node.Value,
+		Line:        node.Line,
+		Column:      node.Column,
+		HeadComment: node.HeadComment,
+		LineComment: node.LineComment,
+		FootComment: node.FootComment,
+	}
+	r.origins[copied] = file
+	return copied
+}
+
+// errorAt creates a reference error located at the given node
+func (r *referenceResolver) errorAt(node *yaml.Node, file string, format string, args ...interface{}) *ReferenceError {
+	return &ReferenceError{
+		File:    file,
+		Line:    node.Line,
+		Column:  node.Column,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// lookupPointer navigates a JSON Pointer (RFC 6901) within a YAML node
+func lookupPointer(root *yaml.Node, pointer string) (*yaml.Node, error) {
+	node := resolveAlias(root)
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil, fmt.Errorf("document is empty")
+		}
+		node = resolveAlias(node.Content[0])
+	}
+	if pointer == "" || pointer == "/" {
+		return node, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("pointer %q must start with /", pointer)
+	}
+
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch node.Kind {
+		case yaml.MappingNode:
+			var next *yaml.Node
+			for _, pair := range mappingPairs(node) {
+				if pair[0].Value == token {
+					next = pair[1]
+					break
+				}
+			}
+			if next == nil {
+				return nil, fmt.Errorf("key %q not found", token)
+			}
+			node = resolveAlias(next)
+		case yaml.SequenceNode:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node.Content) {
+				return nil, fmt.Errorf("index %q out of range", token)
+			}
+			node = resolveAlias(node.Content[index])
+		default:
+			return nil, fmt.Errorf("cannot descend into scalar at %q", token)
+		}
+	}
+	return node, nil
+}
@@ -0,0 +1,256 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sharedDefinitionsYAML = `headers:
+  auth:
+    headers: [authorization]
+responses:
+  ok:
+    statusCodes: [200]
+  created:
+    statusCodes: [201]
+operations:
+  list:
+    method: GET
+    responses:
+      $ref: "#/responses/ok"
+    required:
+      include: "#/headers/auth"
+`
+
+func writeContractFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestYAMLFileParser_ParseFile_References(t *testing.T) {
+	dir := writeContractFiles(t, map[string]string{
+		"shared/common.yaml": sharedDefinitionsYAML,
+		"service-spec.yaml": `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: user-service
+  version: v1.0.0
+definitions:
+  notFound:
+    statusCodes: [200, 404]
+spec:
+  endpoints:
+    - path: /users
+      operations:
+        - $ref: "shared/common.yaml#/operations/list"
+        - include: "shared/common.yaml#/operations/list"
+          method: POST
+          responses:
+            $ref: "shared/common.yaml#/responses/created"
+    - path: /users/{id}
+      operations:
+        - method: GET
+          responses:
+            $ref: "#/definitions/notFound"
+`,
+	})
+
+	specs, errors := NewYAMLFileParser().ParseFile(filepath.Join(dir, "service-spec.yaml"))
+	require.Empty(t, errors)
+	require.Len(t, specs, 1)
+
+	endpoints := specs[0].Spec.Endpoints
+	require.Len(t, endpoints, 2)
+	require.Len(t, endpoints[0].Operations, 2)
+
+	list := endpoints[0].Operations[0]
+	assert.Equal(t, "GET", list.Method)
+	assert.Equal(t, []int{200}, list.Responses.StatusCodes)
+	assert.Equal(t, []string{"authorization"}, list.Required.Headers)
+
+	// Explicit keys override the included template
+	create := endpoints[0].Operations[1]
+	assert.Equal(t, "POST", create.Method)
+	assert.Equal(t, []int{201}, create.Responses.StatusCodes)
+	assert.Equal(t, []string{"authorization"}, create.Required.Headers)
+
+	assert.Equal(t, []int{200, 404}, endpoints[1].Operations[0].Responses.StatusCodes)
+
+	// Operations are located where the contract declares them, including through a reference
+	assert.Equal(t, filepath.Join(dir, "service-spec.yaml"), list.SourceFile)
+	assert.Equal(t, 13, list.LineNumber)
+	assert.Equal(t, filepath.Join(dir, "service-spec.yaml"), endpoints[1].Operations[0].SourceFile)
+	assert.Equal(t, 20, endpoints[1].Operations[0].LineNumber)
+}
+
+func TestYAMLFileParser_ParseFile_ReferenceErrors(t *testing.T) {
+	contract := func(operation string) string {
+		return `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: user-service
+  version: v1.0.0
+spec:
+  endpoints:
+    - path: /users
+      operations:
+        - ` + operation + `
+`
+	}
+
+	testCases := []struct {
+		name            string
+		files           map[string]string
+		expectedFile    string
+		expectedLine    int
+		expectedMessage string
+	}{
+		{
+			name: "missing file",
+			files: map[string]string{
+				"service-spec.yaml": contract(`$ref: "missing.yaml#/operations/list"`),
+			},
+			expectedFile:    "service-spec.yaml",
+			expectedLine:    10,
+			expectedMessage: `cannot load "missing.yaml#/operations/list"`,
+		},
+		{
+			name: "missing pointer",
+			files: map[string]string{
+				"common.yaml":       sharedDefinitionsYAML,
+				"service-spec.yaml": contract(`$ref: "common.yaml#/operations/delete"`),
+			},
+			expectedFile:    "service-spec.yaml",
+			expectedLine:    10,
+			expectedMessage: `key "delete" not found`,
+		},
+		{
+			name: "circular include",
+			files: map[string]string{
+				"a.yaml":            "op:\n  include: \"b.yaml#/op\"\n",
+				"b.yaml":            "op:\n  include: \"a.yaml#/op\"\n",
+				"service-spec.yaml": contract(`include: "a.yaml#/op"`),
+			},
+			expectedFile:    "b.yaml",
+			expectedLine:    2,
+			expectedMessage: "circular reference: ",
+		},
+		{
+			name: "schema error inside referenced file",
+			files: map[string]string{
+				"common.yaml":       "op:\n  method: FETCH\n  responses:\n    statusCodes: [200]\n",
+				"service-spec.yaml": contract(`$ref: "common.yaml#/op"`),
+			},
+			expectedFile:    "common.yaml",
+			expectedLine:    2,
+			expectedMessage: `value "FETCH" is not allowed`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeContractFiles(t, tc.files)
+
+			specs, errors := NewYAMLFileParser().ParseFile(filepath.Join(dir, "service-spec.yaml"))
+			assert.Empty(t, specs)
+			require.NotEmpty(t, errors)
+			assert.Equal(t, filepath.Join(dir, tc.expectedFile), errors[0].File)
+			assert.Equal(t, tc.expectedLine, errors[0].Line)
+			assert.Contains(t, errors[0].Message, tc.expectedMessage)
+		})
+	}
+}
+
+func TestReferencedFiles(t *testing.T) {
+	dir := writeContractFiles(t, map[string]string{
+		"shared/common.yaml":  "operations:\n  list:\n    include: \"../headers.yaml#/auth\"\n",
+		"headers.yaml":        "auth:\n  headers: [authorization]\n",
+		"unused.yaml":         "auth: {}\n",
+		"service-spec.yaml":   "a:\n  $ref: \"shared/common.yaml#/operations/list\"\nb:\n  $ref: \"#/a\"\n---\nc:\n  include: \"headers.yaml#/auth\"\n",
+		"self-contained.yaml": "a: {x: 1}\nb:\n  $ref: \"#/a\"\n",
+	})
+
+	files, err := ReferencedFiles(filepath.Join(dir, "service-spec.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "headers.yaml"), filepath.Join(dir, "shared", "common.yaml")}, files)
+
+	files, err = ReferencedFiles(filepath.Join(dir, "self-contained.yaml"))
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte("a:\n  $ref: \"missing.yaml\"\n"), 0644))
+	_, err = ReferencedFiles(filepath.Join(dir, "broken.yaml"))
+	assert.ErrorContains(t, err, `cannot load "missing.yaml"`)
+}
+
+func TestSandboxedYAMLFileParser_ParseFile(t *testing.T) {
+	outside := writeContractFiles(t, map[string]string{"secret.yaml": sharedDefinitionsYAML})
+	contract := func(reference string) string {
+		return `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: user-service
+  version: v1.0.0
+definitions:
+  ok:
+    statusCodes: [200]
+spec:
+  endpoints:
+    - path: /users
+      operations:
+        - method: GET
+          responses:
+            $ref: "` + reference + `"
+`
+	}
+
+	dir := writeContractFiles(t, map[string]string{
+		"local.yaml":  contract("#/definitions/ok"),
+		"common.yaml": sharedDefinitionsYAML,
+	})
+	specs, errors := NewSandboxedYAMLFileParser().ParseFile(filepath.Join(dir, "local.yaml"))
+	require.Empty(t, errors)
+	require.Len(t, specs, 1)
+	assert.Equal(t, []int{200}, specs[0].Spec.Endpoints[0].Operations[0].Responses.StatusCodes)
+
+	for _, reference := range []string{
+		"common.yaml#/responses/ok",
+		"../" + filepath.Base(outside) + "/secret.yaml#/responses/ok",
+		filepath.Join(outside, "secret.yaml") + "#/responses/ok",
+	} {
+		t.Run(reference, func(t *testing.T) {
+			path := filepath.Join(dir, "service-spec.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(contract(reference)), 0644))
+
+			specs, errors := NewSandboxedYAMLFileParser().ParseFile(path)
+			assert.Empty(t, specs)
+			require.Len(t, errors, 1)
+			assert.Equal(t, 15, errors[0].Line)
+			assert.Contains(t, errors[0].Message, "points at another file")
+		})
+	}
+}
@@ -68,7 +68,17 @@ const ServiceSpecSchema = `{
         }
       },
       "additionalProperties": false
-    }
+    },
+    "definitions": {
+      "type": "object",
+      "description": "Reusable fragments referenced with $ref or include"
+    },
+    "operationid": {"description": "Legacy field written by older explore versions, ignored"},
+    "description": {"description": "Legacy field written by older explore versions, ignored"},
+    "preconditions": {"description": "Legacy field written by older explore versions, ignored"},
+    "postconditions": {"description": "Legacy field written by older explore versions, ignored"},
+    "sourcefile": {"description": "Legacy field written by older explore versions, ignored"},
+    "linenumber": {"description": "Legacy field written by older explore versions, ignored"}
   },
   "additionalProperties": false,
   "definitions": {
@@ -90,13 +100,16 @@ const ServiceSpecSchema = `{
         },
         "stats": {
           "$ref": "#/definitions/endpointStats"
+        },
+        "tags": {
+          "$ref": "#/definitions/tags"
         }
       },
       "additionalProperties": false
     },
     "operation": {
       "type": "object",
-      "required": ["method", "responses", "required"],
+      "required": ["method", "responses"],
       "properties": {
         "method": {
           "type": "string",
@@ -114,6 +127,78 @@ const ServiceSpecSchema = `{
         },
         "stats": {
           "$ref": "#/definitions/operationStats"
+        },
+        "deprecated": {
+          "type": "boolean",
+          "description": "Operation is deprecated; traffic to it produces warnings"
+        },
+        "sunset": {
+          "type": "string",
+          "pattern": "^[0-9]{4}-[0-9]{2}-[0-9]{2}",
+          "description": "Date (YYYY-MM-DD or RFC 3339) after which a deprecated operation must not receive traffic"
+        },
+        "severity": {
+          "type": "string",
+          "enum": ["error", "warning", "info"],
+          "description": "Effect of failed checks: error fails the operation, warning reports a warning, info only lists them"
+        },
+        "priority": {
+          "type": "string",
+          "enum": ["critical", "high", "normal", "low"],
+          "description": "When the operation is aligned and reported: critical first, low last; failures of critical operations stop a run with --fail-fast"
+        },
+        "when": {
+          "$ref": "#/definitions/when"
+        },
+        "tags": {
+          "$ref": "#/definitions/tags"
+        },
+        "pathParams": {
+          "type": "object",
+          "description": "Constraints on the values of the endpoint's path parameters, by name",
+          "additionalProperties": {
+            "$ref": "#/definitions/pathParam"
+          }
+        },
+        "request": {
+          "type": "object",
+          "description": "Request bodies the operation accepts",
+          "properties": {
+            "contentTypes": {
+              "type": "array",
+              "items": {
+                "type": "string"
+              }
+            },
+            "schema": {
+              "type": "object",
+              "description": "JSON Schema subset (type, enum, required, properties, additionalProperties, items) the JSON request body must satisfy"
+            },
+            "when": {
+              "$ref": "#/definitions/when"
+            }
+          },
+          "additionalProperties": false
+        }
+      },
+      "additionalProperties": false
+    },
+    "pathParam": {
+      "type": "object",
+      "properties": {
+        "type": {
+          "type": "string",
+          "enum": ["string", "integer", "number", "boolean", "uuid"]
+        },
+        "pattern": {
+          "type": "string",
+          "description": "Regular expression the whole value must match"
+        },
+        "enum": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
         }
       },
       "additionalProperties": false
@@ -139,6 +224,9 @@ const ServiceSpecSchema = `{
         "aggregation": {
           "type": "string",
           "enum": ["range", "exact", "auto"]
+        },
+        "when": {
+          "$ref": "#/definitions/when"
         }
       },
       "anyOf": [
@@ -149,7 +237,6 @@ const ServiceSpecSchema = `{
     },
     "requiredFields": {
       "type": "object",
-      "required": ["query", "headers"],
       "properties": {
         "query": {
           "type": "array",
@@ -162,13 +249,27 @@ const ServiceSpecSchema = `{
           "items": {
             "type": "string"
           }
+        },
+        "when": {
+          "$ref": "#/definitions/when"
         }
       },
       "additionalProperties": false
     },
+    "when": {
+      "type": "object",
+      "description": "JSONLogic expression over the span, e.g. span.attributes.http.target; the checks it guards only apply to spans for which it holds"
+    },
+    "tags": {
+      "type": "array",
+      "description": "Tags such as critical or payments, selecting operations with verify --tags; an operation has its own tags and those of its endpoint",
+      "items": {
+        "type": "string",
+        "minLength": 1
+      }
+    },
     "optionalFields": {
       "type": "object",
-      "required": ["query", "headers"],
       "properties": {
         "query": {
           "type": "array",
@@ -219,6 +320,36 @@ const ServiceSpecSchema = `{
         "lastSeen": {
           "type": "string",
           "format": "date-time"
+        },
+        "latency": {
+          "$ref": "#/definitions/latencyStats"
+        }
+      },
+      "additionalProperties": false
+    },
+    "latencyStats": {
+      "type": "object",
+      "description": "Observed request durations in milliseconds",
+      "properties": {
+        "samples": {
+          "type": "integer",
+          "minimum": 0
+        },
+        "p50Ms": {
+          "type": "number",
+          "minimum": 0
+        },
+        "p95Ms": {
+          "type": "number",
+          "minimum": 0
+        },
+        "p99Ms": {
+          "type": "number",
+          "minimum": 0
+        },
+        "maxMs": {
+          "type": "number",
+          "minimum": 0
         }
       },
       "additionalProperties": false
@@ -0,0 +1,341 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlMergeKey is the YAML merge key, whose entries are folded into the enclosing mapping
+const yamlMergeKey = "<<"
+
+// ValidateDocument validates a parsed YAML document against the embedded JSON Schema.
+// Unlike ValidateServiceSpec it sees the document as written, so it reports unknown fields
+// and wrong types together with the line, column and JSON Pointer of the offending node.
+func (sv *SchemaValidator) ValidateDocument(document *yaml.Node) []models.ParseError {
+	return sv.validateDocument(document, nil)
+}
+
+// validateDocument validates a document whose nodes may come from several files; origins maps
+// nodes to the file they were read from and is used to attribute errors
+func (sv *SchemaValidator) validateDocument(document *yaml.Node, origins map[*yaml.Node]string) []models.ParseError {
+	node := resolveAlias(document)
+	if node != nil && node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return []models.ParseError{{Line: node.Line, Column: node.Column, Message: "document is empty"}}
+		}
+		node = resolveAlias(node.Content[0])
+	}
+	if node == nil {
+		return []models.ParseError{{Message: "document is empty"}}
+	}
+
+	var errors []models.ParseError
+	sv.validateNode(node, sv.schema, "", origins, &errors)
+	return errors
+}
+
+// validateNode validates a node against a schema fragment, appending any violations to errors
+func (sv *SchemaValidator) validateNode(
+	node *yaml.Node,
+	schema map[string]interface{},
+	pointer string,
+	origins map[*yaml.Node]string,
+	errors *[]models.ParseError,
+) {
+	node = resolveAlias(node)
+	schema = sv.resolveRef(schema)
+
+	addError := func(at *yaml.Node, atPointer string, format string, args ...interface{}) {
+		*errors = append(*errors, models.ParseError{
+			File:        origins[at],
+			Line:        at.Line,
+			Column:      at.Column,
+			Message:     fmt.Sprintf("%s: %s", displayPointer(atPointer), fmt.Sprintf(format, args...)),
+			JSONPointer: atPointer,
+		})
+	}
+
+	if expected, ok := schema["type"].(string); ok {
+		if !nodeHasType(node, expected) {
+			addError(node, pointer, "expected %s, got %s", expected, describeNode(node))
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && node.Kind == yaml.ScalarNode {
+		allowed := make([]string, 0, len(enum))
+		matched := false
+		for _, value := range enum {
+			allowed = append(allowed, fmt.Sprint(value))
+			if fmt.Sprint(value) == node.Value {
+				matched = true
+			}
+		}
+		if !matched {
+			addError(node, pointer, "value %q is not allowed, must be one of: %s", node.Value, strings.Join(allowed, ", "))
+		}
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		sv.validateMapping(node, schema, pointer, origins, errors, addError)
+	case yaml.SequenceNode:
+		if minItems, ok := schema["minItems"].(float64); ok && len(node.Content) < int(minItems) {
+			addError(node, pointer, "must contain at least %d item(s)", int(minItems))
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range node.Content {
+				sv.validateNode(item, items, fmt.Sprintf("%s/%d", pointer, i), origins, errors)
+			}
+		}
+	case yaml.ScalarNode:
+		validateScalar(node, schema, pointer, addError)
+	}
+
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		sv.validateAnyOf(node, anyOf, pointer, origins, addError)
+	}
+}
+
+// validateMapping checks properties, required fields and unknown fields of a mapping node
+func (sv *SchemaValidator) validateMapping(
+	node *yaml.Node,
+	schema map[string]interface{},
+	pointer string,
+	origins map[*yaml.Node]string,
+	errors *[]models.ParseError,
+	addError func(at *yaml.Node, atPointer string, format string, args ...interface{}),
+) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	additionalAllowed := true
+	if additional, ok := schema["additionalProperties"].(bool); ok {
+		additionalAllowed = additional
+	}
+	// A schema for additional properties validates the values of maps keyed by name
+	additionalSchema, _ := schema["additionalProperties"].(map[string]interface{})
+
+	present := make(map[string]bool)
+	for _, pair := range mappingPairs(node) {
+		key := pair[0].Value
+		present[key] = true
+		childPointer := pointer + "/" + escapePointerToken(key)
+
+		propertySchema, known := properties[key].(map[string]interface{})
+		if !known && additionalSchema != nil {
+			propertySchema, known = additionalSchema, true
+		}
+		if !known {
+			if !additionalAllowed {
+				addError(pair[0], childPointer, "unknown field %q", key)
+			}
+			continue
+		}
+		sv.validateNode(pair[1], propertySchema, childPointer, origins, errors)
+	}
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, field := range required {
+			name := fmt.Sprint(field)
+			if !present[name] {
+				addError(node, pointer+"/"+escapePointerToken(name), "missing required field %q", name)
+			}
+		}
+	}
+}
+
+// validateAnyOf reports an error when the node satisfies none of the alternatives
+func (sv *SchemaValidator) validateAnyOf(
+	node *yaml.Node,
+	anyOf []interface{},
+	pointer string,
+	origins map[*yaml.Node]string,
+	addError func(at *yaml.Node, atPointer string, format string, args ...interface{}),
+) {
+	var alternatives []string
+	for _, option := range anyOf {
+		optionSchema, ok := option.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var optionErrors []models.ParseError
+		sv.validateNode(node, optionSchema, pointer, origins, &optionErrors)
+		if len(optionErrors) == 0 {
+			return
+		}
+		if required, ok := optionSchema["required"].([]interface{}); ok {
+			for _, field := range required {
+				alternatives = append(alternatives, fmt.Sprint(field))
+			}
+		}
+	}
+
+	if len(alternatives) > 0 {
+		addError(node, pointer, "must specify at least one of: %s", strings.Join(alternatives, ", "))
+		return
+	}
+	addError(node, pointer, "does not match any of the allowed forms")
+}
+
+// validateScalar applies string and numeric constraints to a scalar node
+func validateScalar(
+	node *yaml.Node,
+	schema map[string]interface{},
+	pointer string,
+	addError func(at *yaml.Node, atPointer string, format string, args ...interface{}),
+) {
+	if minLength, ok := schema["minLength"].(float64); ok && len(node.Value) < int(minLength) {
+		addError(node, pointer, "must not be empty")
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(node.Value) {
+			addError(node, pointer, "value %q does not match pattern %s", node.Value, pattern)
+		}
+	}
+
+	if format, ok := schema["format"].(string); ok && format == "date-time" {
+		if _, err := time.Parse(time.RFC3339, node.Value); err != nil {
+			addError(node, pointer, "value %q is not a valid RFC 3339 date-time", node.Value)
+		}
+	}
+
+	if number, err := strconv.ParseFloat(node.Value, 64); err == nil && node.ShortTag() != "!!str" {
+		if minimum, ok := schema["minimum"].(float64); ok && number < minimum {
+			addError(node, pointer, "value %s is less than minimum %v", node.Value, minimum)
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && number > maximum {
+			addError(node, pointer, "value %s is greater than maximum %v", node.Value, maximum)
+		}
+	}
+}
+
+// resolveRef follows a local "#/definitions/..." reference
+func (sv *SchemaValidator) resolveRef(schema map[string]interface{}) map[string]interface{} {
+	ref, ok := schema["$ref"].(string)
+	if !ok || !strings.HasPrefix(ref, "#/definitions/") {
+		return schema
+	}
+	definitions, _ := sv.schema["definitions"].(map[string]interface{})
+	if definition, ok := definitions[strings.TrimPrefix(ref, "#/definitions/")].(map[string]interface{}); ok {
+		return definition
+	}
+	return schema
+}
+
+// nodeHasType reports whether a node is compatible with a JSON Schema type
+func nodeHasType(node *yaml.Node, expected string) bool {
+	switch expected {
+	case "object":
+		return node.Kind == yaml.MappingNode
+	case "array":
+		return node.Kind == yaml.SequenceNode
+	case "string":
+		// Any non-null scalar decodes into a string field, e.g. version: 1.0
+		return node.Kind == yaml.ScalarNode && node.ShortTag() != "!!null"
+	case "integer":
+		return node.Kind == yaml.ScalarNode && node.ShortTag() == "!!int"
+	case "number":
+		return node.Kind == yaml.ScalarNode && (node.ShortTag() == "!!int" || node.ShortTag() == "!!float")
+	case "boolean":
+		return node.Kind == yaml.ScalarNode && node.ShortTag() == "!!bool"
+	}
+	return true
+}
+
+// describeNode returns a short description of a node's type for error messages
+func describeNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "mapping"
+	case yaml.SequenceNode:
+		return "sequence"
+	}
+
+	switch node.ShortTag() {
+	case "!!null":
+		return "null"
+	case "!!int":
+		return fmt.Sprintf("integer %s", node.Value)
+	case "!!float":
+		return fmt.Sprintf("number %s", node.Value)
+	case "!!bool":
+		return fmt.Sprintf("boolean %s", node.Value)
+	}
+	return fmt.Sprintf("string %q", node.Value)
+}
+
+// mappingPairs returns the key/value pairs of a mapping node with merge keys expanded
+func mappingPairs(node *yaml.Node) [][2]*yaml.Node {
+	var pairs [][2]*yaml.Node
+	var merged [][2]*yaml.Node
+	seen := make(map[string]bool)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Value == yamlMergeKey && key.ShortTag() == "!!merge" {
+			value = resolveAlias(value)
+			sources := []*yaml.Node{value}
+			if value.Kind == yaml.SequenceNode {
+				sources = value.Content
+			}
+			for _, source := range sources {
+				if source = resolveAlias(source); source.Kind == yaml.MappingNode {
+					merged = append(merged, mappingPairs(source)...)
+				}
+			}
+			continue
+		}
+		seen[key.Value] = true
+		pairs = append(pairs, [2]*yaml.Node{key, value})
+	}
+
+	// Explicit keys override merged ones
+	for _, pair := range merged {
+		if !seen[pair[0].Value] {
+			seen[pair[0].Value] = true
+			pairs = append(pairs, pair)
+		}
+	}
+	return pairs
+}
+
+// resolveAlias follows alias nodes to the node they refer to
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// escapePointerToken escapes a key for use in a JSON Pointer (RFC 6901)
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// displayPointer returns a printable form of a JSON Pointer, using "/" for the document root
+func displayPointer(pointer string) string {
+	if pointer == "" {
+		return "/"
+	}
+	return pointer
+}
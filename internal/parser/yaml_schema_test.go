@@ -20,6 +20,7 @@ import (
 	"github.com/flowspec/flowspec-cli/internal/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestNewSchemaValidator(t *testing.T) {
@@ -397,4 +398,223 @@ func TestSchemaValidator_ValidateServiceSpec_InvalidStatusRange(t *testing.T) {
 	assert.Len(t, errors, 1)
 	assert.Contains(t, errors[0].Message, "status range '9xx' is not valid")
 	assert.Equal(t, "/spec/endpoints/0/operations/0/responses/statusRanges/0", errors[0].JSONPointer)
-}
\ No newline at end of file
+}
+func TestSchemaValidator_ValidateDocument(t *testing.T) {
+	testCases := []struct {
+		name            string
+		document        string
+		expectedPointer string
+		expectedMessage string
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{
+			name: "valid document",
+			document: `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: user-service
+  version: 1.0
+spec:
+  endpoints:
+    - path: /users
+      operations:
+        - method: GET
+          responses:
+            statusCodes: [200]
+`,
+		},
+		{
+			name: "unknown field",
+			document: `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: user-service
+  version: v1
+  owner: team-a
+spec:
+  endpoints:
+    - path: /users
+      operations:
+        - method: GET
+          responses:
+            statusCodes: [200]
+`,
+			expectedPointer: "/metadata/owner",
+			expectedMessage: `unknown field "owner"`,
+			expectedLine:    6,
+			expectedColumn:  3,
+		},
+		{
+			name: "wrong type",
+			document: `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: user-service
+  version: v1
+spec:
+  endpoints:
+    - path: /users
+      operations:
+        - method: GET
+          responses:
+            statusCodes: [ok]
+`,
+			expectedPointer: "/spec/endpoints/0/operations/0/responses/statusCodes/0",
+			expectedMessage: `expected integer, got string "ok"`,
+			expectedLine:    12,
+			expectedColumn:  27,
+		},
+		{
+			name: "missing status codes and ranges",
+			document: `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: user-service
+  version: v1
+spec:
+  endpoints:
+    - path: /users
+      operations:
+        - method: GET
+          responses:
+            aggregation: exact
+`,
+			expectedPointer: "/spec/endpoints/0/operations/0/responses",
+			expectedMessage: "must specify at least one of: statusCodes, statusRanges",
+			expectedLine:    12,
+			expectedColumn:  13,
+		},
+		{
+			name: "invalid enum value through merge key",
+			document: `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: user-service
+  version: v1
+spec:
+  endpoints:
+    - path: /users
+      operations:
+        - &base
+          method: FETCH
+          responses:
+            statusCodes: [200]
+        - <<: *base
+          stats: {}
+`,
+			expectedPointer: "/spec/endpoints/0/operations/0/method",
+			expectedMessage: `value "FETCH" is not allowed`,
+			expectedLine:    11,
+			expectedColumn:  19,
+		},
+		{
+			name: "valid path parameter constraints",
+			document: `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: user-service
+  version: v1
+spec:
+  endpoints:
+    - path: /users/{id}/orders/{status}
+      operations:
+        - method: GET
+          pathParams:
+            id: {type: uuid}
+            status: {enum: [open, closed]}
+          responses:
+            statusCodes: [200]
+`,
+		},
+		{
+			name: "valid request body constraints",
+			document: `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: user-service
+  version: v1
+spec:
+  endpoints:
+    - path: /users
+      operations:
+        - method: POST
+          request:
+            contentTypes: [application/json]
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string}
+          responses:
+            statusCodes: [201]
+`,
+		},
+		{
+			name: "unknown request field",
+			document: `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: user-service
+  version: v1
+spec:
+  endpoints:
+    - path: /users
+      operations:
+        - method: POST
+          request:
+            mediaTypes: [application/json]
+          responses:
+            statusCodes: [201]
+`,
+			expectedPointer: "/spec/endpoints/0/operations/0/request/mediaTypes",
+			expectedMessage: `unknown field "mediaTypes"`,
+			expectedLine:    12,
+			expectedColumn:  13,
+		},
+		{
+			name: "invalid path parameter type",
+			document: `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: user-service
+  version: v1
+spec:
+  endpoints:
+    - path: /users/{id}
+      operations:
+        - method: GET
+          pathParams:
+            id: {type: date}
+          responses:
+            statusCodes: [200]
+`,
+			expectedPointer: "/spec/endpoints/0/operations/0/pathParams/id/type",
+			expectedMessage: `value "date" is not allowed`,
+			expectedLine:    12,
+			expectedColumn:  24,
+		},
+	}
+
+	validator, err := NewSchemaValidator()
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var document yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(tc.document), &document))
+
+			errors := validator.ValidateDocument(&document)
+			if tc.expectedMessage == "" {
+				assert.Empty(t, errors)
+				return
+			}
+
+			require.NotEmpty(t, errors)
+			assert.Equal(t, tc.expectedPointer, errors[0].JSONPointer)
+			assert.Contains(t, errors[0].Message, tc.expectedMessage)
+			assert.Equal(t, tc.expectedLine, errors[0].Line)
+			assert.Equal(t, tc.expectedColumn, errors[0].Column)
+		})
+	}
+}
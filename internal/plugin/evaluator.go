@@ -0,0 +1,149 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/engine"
+)
+
+// DispatchingEvaluator sends assertions with a custom operator to plugin evaluators and all
+// other assertions to the built-in evaluator. Registry.ApplyEngine sets it on an engine
+// configuration.
+type DispatchingEvaluator struct {
+	registry *Registry
+	fallback engine.AssertionEvaluator
+}
+
+// NewDispatchingEvaluator wraps fallback, usually the JSONLogic evaluator
+func NewDispatchingEvaluator(registry *Registry, fallback engine.AssertionEvaluator) *DispatchingEvaluator {
+	return &DispatchingEvaluator{registry: registry, fallback: fallback}
+}
+
+// EvaluateAssertion implements engine.AssertionEvaluator. Custom operators and built-in
+// conditions may be mixed in one assertion; all of them must pass.
+func (d *DispatchingEvaluator) EvaluateAssertion(assertion map[string]interface{}, evaluationContext *engine.EvaluationContext) (*engine.AssertionResult, error) {
+	custom, builtin := d.split(assertion)
+	if len(custom) == 0 {
+		return d.fallback.EvaluateAssertion(assertion, evaluationContext)
+	}
+
+	for _, operator := range sortedKeys(custom) {
+		evaluator, ok := d.registry.Evaluator(operator)
+		if !ok {
+			return nil, fmt.Errorf("no plugin provides assertion operator %q", operator)
+		}
+		request := EvaluationRequest{Operator: operator, Arguments: custom[operator]}
+		if evaluationContext != nil {
+			request.Span = evaluationContext.Span
+			request.Variables = evaluationContext.Variables
+		}
+		// The engine's evaluator interface carries no context; process plugins are bounded by
+		// their own timeout
+		response, err := evaluator.Evaluate(context.Background(), request)
+		if err != nil {
+			return &engine.AssertionResult{
+				Passed:     false,
+				Expected:   true,
+				Actual:     false,
+				Expression: operator,
+				Message:    fmt.Sprintf("plugin operator %s failed: %v", operator, err),
+				Error:      err,
+			}, nil
+		}
+		if !response.Passed {
+			message := response.Message
+			if message == "" {
+				message = fmt.Sprintf("assertion %s failed", operator)
+			}
+			return &engine.AssertionResult{
+				Passed:     false,
+				Expected:   valueOr(response.Expected, true),
+				Actual:     valueOr(response.Actual, false),
+				Expression: operator,
+				Message:    message,
+			}, nil
+		}
+	}
+
+	if len(builtin) > 0 {
+		return d.fallback.EvaluateAssertion(builtin, evaluationContext)
+	}
+	return &engine.AssertionResult{
+		Passed:     true,
+		Expected:   true,
+		Actual:     true,
+		Expression: strings.Join(sortedKeys(custom), ", "),
+		Message:    "Plugin assertions passed",
+	}, nil
+}
+
+// ValidateAssertion implements engine.AssertionEvaluator
+func (d *DispatchingEvaluator) ValidateAssertion(assertion map[string]interface{}) error {
+	custom, builtin := d.split(assertion)
+	for operator := range custom {
+		if _, ok := d.registry.Evaluator(operator); !ok {
+			return fmt.Errorf("no plugin provides assertion operator %q", operator)
+		}
+	}
+	if len(builtin) > 0 {
+		return d.fallback.ValidateAssertion(builtin)
+	}
+	return nil
+}
+
+// ApplyEngine makes engines created with config send the custom operators of the registry to
+// their plugins; the evaluator already configured, JSONLogic by default, evaluates the rest
+func (r *Registry) ApplyEngine(config *engine.EngineConfig) {
+	fallback := config.Evaluator
+	if fallback == nil {
+		fallback = engine.NewJSONLogicEvaluator()
+	}
+	config.Evaluator = NewDispatchingEvaluator(r, fallback)
+}
+
+// split separates custom operators from built-in conditions
+func (d *DispatchingEvaluator) split(assertion map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	custom := make(map[string]interface{})
+	builtin := make(map[string]interface{})
+	for key, value := range assertion {
+		if strings.HasPrefix(key, OperatorPrefix) {
+			custom[key] = value
+		} else {
+			builtin[key] = value
+		}
+	}
+	return custom, builtin
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func valueOr(value, fallback interface{}) interface{} {
+	if value == nil {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,179 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plugin lets trace ingestors and assertion evaluators be added without changing
+// FlowSpec. Plugins are either registered in-process or discovered as executables that speak
+// a JSON protocol over stdin and stdout (see process.go).
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// Ingestor converts a proprietary trace or log format into trace data
+type Ingestor interface {
+	// Format is the name selected with --trace-format, e.g. "acme-log"
+	Format() string
+	Ingest(ctx context.Context, reader io.Reader) (*models.TraceData, error)
+}
+
+// EvaluationRequest is what an evaluator receives for one assertion
+type EvaluationRequest struct {
+	Operator  string                 `json:"operator"`  // Assertion key, e.g. "x-acme-pii"
+	Arguments interface{}            `json:"arguments"` // Value of the assertion key
+	Span      *models.Span           `json:"span,omitempty"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// EvaluationResponse is the outcome of an assertion
+type EvaluationResponse struct {
+	Passed   bool        `json:"passed"`
+	Message  string      `json:"message,omitempty"`
+	Expected interface{} `json:"expected,omitempty"`
+	Actual   interface{} `json:"actual,omitempty"`
+}
+
+// Evaluator evaluates assertions with a custom operator
+type Evaluator interface {
+	// Operator is the assertion key handled, which must start with "x-" so it cannot clash
+	// with JSONLogic operators
+	Operator() string
+	Evaluate(ctx context.Context, request EvaluationRequest) (*EvaluationResponse, error)
+}
+
+// OperatorPrefix starts every custom assertion operator
+const OperatorPrefix = "x-"
+
+// Registry holds the available plugins
+type Registry struct {
+	ingestors  map[string]Ingestor
+	evaluators map[string]Evaluator
+	sources    map[string]string // Plugin that registered each format or operator
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		ingestors:  make(map[string]Ingestor),
+		evaluators: make(map[string]Evaluator),
+		sources:    make(map[string]string),
+	}
+}
+
+// builtinSource names the plugin of built-in operators in conflict errors
+const builtinSource = "flowspec (built-in)"
+
+// NewBuiltinRegistry creates a registry holding the built-in operators: x-wasm, loading modules
+// from baseDir. It fails when FLOWSPEC_WASM_RUNTIME names an unknown runtime.
+func NewBuiltinRegistry(baseDir string) (*Registry, error) {
+	wasm, err := NewWASMEvaluator(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	registry := NewRegistry()
+	if err := registry.RegisterEvaluator(wasm, builtinSource); err != nil {
+		panic(err) // The registry is empty
+	}
+	return registry, nil
+}
+
+// RegisterIngestor adds an ingestor; source names the plugin in conflict errors
+func (r *Registry) RegisterIngestor(ingestor Ingestor, source string) error {
+	format := strings.ToLower(ingestor.Format())
+	if format == "" {
+		return fmt.Errorf("plugin %s: ingestor format is empty", source)
+	}
+	key := "format " + format
+	if existing, ok := r.sources[key]; ok {
+		return fmt.Errorf("plugin %s: trace format %q is already provided by %s", source, format, existing)
+	}
+	r.ingestors[format] = ingestor
+	r.sources[key] = source
+	return nil
+}
+
+// RegisterEvaluator adds an evaluator; source names the plugin in conflict errors
+func (r *Registry) RegisterEvaluator(evaluator Evaluator, source string) error {
+	operator := evaluator.Operator()
+	if !strings.HasPrefix(operator, OperatorPrefix) || len(operator) == len(OperatorPrefix) {
+		return fmt.Errorf("plugin %s: operator %q must start with %q", source, operator, OperatorPrefix)
+	}
+	key := "operator " + operator
+	if existing, ok := r.sources[key]; ok {
+		return fmt.Errorf("plugin %s: operator %q is already provided by %s", source, operator, existing)
+	}
+	r.evaluators[operator] = evaluator
+	r.sources[key] = source
+	return nil
+}
+
+// Ingestor returns the ingestor of a trace format
+func (r *Registry) Ingestor(format string) (Ingestor, bool) {
+	ingestor, ok := r.ingestors[strings.ToLower(format)]
+	return ingestor, ok
+}
+
+// Evaluator returns the evaluator of an operator
+func (r *Registry) Evaluator(operator string) (Evaluator, bool) {
+	evaluator, ok := r.evaluators[operator]
+	return evaluator, ok
+}
+
+// Formats lists the registered trace formats
+func (r *Registry) Formats() []string {
+	formats := make([]string, 0, len(r.ingestors))
+	for format := range r.ingestors {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Operators lists the registered assertion operators
+func (r *Registry) Operators() []string {
+	operators := make([]string, 0, len(r.evaluators))
+	for operator := range r.evaluators {
+		operators = append(operators, operator)
+	}
+	sort.Strings(operators)
+	return operators
+}
+
+// Ingest reads trace data in a plugin format
+func (r *Registry) Ingest(ctx context.Context, format string, reader io.Reader) (*models.TraceData, error) {
+	ingestor, ok := r.Ingestor(format)
+	if !ok {
+		available := "none installed"
+		if formats := r.Formats(); len(formats) > 0 {
+			available = strings.Join(formats, ", ")
+		}
+		return nil, fmt.Errorf("unknown trace format %q (plugin formats: %s)", format, available)
+	}
+	traceData, err := ingestor.Ingest(ctx, reader)
+	if err != nil {
+		return nil, fmt.Errorf("trace format %s: %w", format, err)
+	}
+	if traceData.SpanTree == nil && len(traceData.Spans) > 0 {
+		if err := traceData.BuildSpanTree(); err != nil {
+			return nil, fmt.Errorf("trace format %s: %w", format, err)
+		}
+	}
+	return traceData, nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/engine"
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// allowListEvaluator passes when the span's http.target is in its arguments
+type allowListEvaluator struct{}
+
+func (allowListEvaluator) Operator() string { return "x-allowed-target" }
+
+func (allowListEvaluator) Evaluate(ctx context.Context, request EvaluationRequest) (*EvaluationResponse, error) {
+	target, _ := request.Span.Attributes["http.target"].(string)
+	for _, allowed := range request.Arguments.([]interface{}) {
+		if allowed == target {
+			return &EvaluationResponse{Passed: true}, nil
+		}
+	}
+	return &EvaluationResponse{Passed: false, Message: "target " + target + " is not allowed", Actual: target}, nil
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.RegisterEvaluator(allowListEvaluator{}, "test"))
+	assert.ErrorContains(t, registry.RegisterEvaluator(allowListEvaluator{}, "other"), "already provided by test")
+	assert.Equal(t, []string{"x-allowed-target"}, registry.Operators())
+
+	_, err := registry.Ingest(context.Background(), "acme", strings.NewReader(""))
+	assert.ErrorContains(t, err, "plugin formats: none installed")
+
+	builtin, err := NewBuiltinRegistry("contracts")
+	require.NoError(t, err)
+	assert.Equal(t, []string{WASMOperator}, builtin.Operators())
+	assert.ErrorContains(t, builtin.RegisterEvaluator(&processEvaluator{operator: WASMOperator}, "acme"), "already provided by flowspec (built-in)")
+}
+
+func TestDispatchingEvaluator(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.RegisterEvaluator(allowListEvaluator{}, "test"))
+	evaluator := NewDispatchingEvaluator(registry, engine.NewJSONLogicEvaluator())
+
+	span := &models.Span{SpanID: "s1", Attributes: map[string]interface{}{"http.target": "/health", "http.status_code": 200}}
+	evaluationContext := engine.NewEvaluationContext(span, nil)
+
+	testCases := []struct {
+		name      string
+		assertion map[string]interface{}
+		passed    bool
+	}{
+		{"plugin operator passes", map[string]interface{}{"x-allowed-target": []interface{}{"/health"}}, true},
+		{"plugin operator fails", map[string]interface{}{"x-allowed-target": []interface{}{"/users"}}, false},
+		{"built-in only", map[string]interface{}{"==": []interface{}{1, 1}}, true},
+		{"mixed, built-in fails", map[string]interface{}{"x-allowed-target": []interface{}{"/health"}, "==": []interface{}{1, 2}}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := evaluator.EvaluateAssertion(tc.assertion, evaluationContext)
+			require.NoError(t, err)
+			assert.Equal(t, tc.passed, result.Passed, result.Message)
+		})
+	}
+
+	_, err := evaluator.EvaluateAssertion(map[string]interface{}{"x-unknown": true}, evaluationContext)
+	assert.ErrorContains(t, err, `no plugin provides assertion operator "x-unknown"`)
+	assert.Error(t, evaluator.ValidateAssertion(map[string]interface{}{"x-unknown": true}))
+	assert.NoError(t, evaluator.ValidateAssertion(map[string]interface{}{"x-allowed-target": []interface{}{}}))
+}
+
+// writePlugin writes a shell script process plugin
+func writePlugin(t *testing.T, dir, name, script string) {
+	path := filepath.Join(dir, ExecutablePrefix+name)
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755))
+}
+
+func TestLoad_ProcessPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugins need a POSIX shell")
+	}
+	first, second := t.TempDir(), t.TempDir()
+	writePlugin(t, first, "acme", `case "$1" in
+describe) echo '{"name": "acme", "version": "1.2.0", "protocolVersion": 1, "formats": ["acme-log"], "operators": ["x-acme-pii"]}' ;;
+ingest) read line; echo '{"traceId": "t1", "spans": [{"spanId": "s1", "traceId": "t1", "name": "'"$line"'", "startTime": 1, "endTime": 2, "status": {"code": "OK"}, "attributes": {"http.method": "GET"}}]}' ;;
+evaluate) grep -q '"operator":"x-acme-pii"' && echo '{"passed": false, "message": "email address in response"}' ;;
+esac
+`)
+	writePlugin(t, first, "broken", "echo 'boom' >&2; exit 3\n")
+	writePlugin(t, second, "acme", "exit 1\n") // Shadowed by the first directory
+	require.NoError(t, os.WriteFile(filepath.Join(first, ExecutablePrefix+"notes.txt"), []byte("not executable"), 0644))
+
+	registry := NewRegistry()
+	processes, err := Load(context.Background(), registry, []string{first, second, filepath.Join(first, "missing")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "plugin flowspec-plugin-broken describe: boom")
+	require.Len(t, processes, 1)
+	assert.Equal(t, "1.2.0", processes[0].Manifest.Version)
+	assert.Contains(t, FormatHuman(processes), "trace formats: acme-log")
+
+	traceData, err := registry.Ingest(context.Background(), "ACME-LOG", strings.NewReader("GET /health\n"))
+	require.NoError(t, err)
+	require.Len(t, traceData.Spans, 1)
+	assert.Equal(t, "GET /health", traceData.Spans["s1"].Name)
+	assert.NotNil(t, traceData.RootSpan)
+
+	evaluator, ok := registry.Evaluator("x-acme-pii")
+	require.True(t, ok)
+	response, err := evaluator.Evaluate(context.Background(), EvaluationRequest{Operator: "x-acme-pii", Arguments: true})
+	require.NoError(t, err)
+	assert.False(t, response.Passed)
+	assert.Equal(t, "email address in response", response.Message)
+}
+
+func TestLoad_ProtocolVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugins need a POSIX shell")
+	}
+	dir := t.TempDir()
+	writePlugin(t, dir, "future", `echo '{"name": "future", "protocolVersion": 2}'`+"\n")
+
+	processes, err := Load(context.Background(), NewRegistry(), []string{dir})
+	assert.ErrorContains(t, err, "speaks protocol version 2")
+	assert.Empty(t, processes)
+}
+
+func TestNewWASMEvaluator_Runtimes(t *testing.T) {
+	for _, runtime := range []string{"", "wasmer", "/opt/wazero/bin/wazero", "wasmedge.exe"} {
+		t.Setenv(WASMRuntimeEnv, runtime)
+		_, err := NewWASMEvaluator("checks")
+		assert.NoError(t, err, runtime)
+	}
+
+	for _, runtime := range []string{"wasmtime run --dir /", "sh", "/usr/bin/node"} {
+		t.Setenv(WASMRuntimeEnv, runtime)
+		_, err := NewWASMEvaluator("checks")
+		assert.ErrorContains(t, err, "is not a known WASM runtime, use one of: wasmedge, wasmer, wasmtime, wazero", runtime)
+		_, err = NewBuiltinRegistry("checks")
+		assert.Error(t, err)
+	}
+}
+
+func TestWASMEvaluator(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake runtime needs a POSIX shell")
+	}
+	dir, bin, outside := t.TempDir(), t.TempDir(), t.TempDir()
+	// The fake runtime stands in for wasmtime and checks that the function is invoked with
+	// nothing but the module and the function
+	runtimePath := filepath.Join(bin, "wasmtime")
+	require.NoError(t, os.WriteFile(runtimePath, []byte(`#!/bin/sh
+[ "$#" = 3 ] && [ "$1" = run ] && [ "$3" = verify_signature ] || exit 2
+grep -q '"operator":"verify_signature"' || exit 3
+[ -z "$FLOWSPEC_TEST_SECRET" ] || { echo 'environment leaked' >&2; exit 4; }
+echo '{"passed": true}'
+`), 0755))
+	module := []byte("\x00asm\x01\x00\x00\x00")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hmac.wasm"), module, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fake.wasm"), []byte("not wasm"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "other.wasm"), module, 0644))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "other.wasm"), filepath.Join(dir, "linked.wasm")))
+
+	t.Setenv("FLOWSPEC_TEST_SECRET", "s3cr3t")
+	t.Setenv(WASMRuntimeEnv, runtimePath)
+	evaluator, err := NewWASMEvaluator(dir)
+	require.NoError(t, err)
+	span := &models.Span{SpanID: "s1", Attributes: map[string]interface{}{"http.request.header.x-signature": "abc"}}
+
+	testCases := []struct {
+		name      string
+		arguments interface{}
+		errorText string
+	}{
+		{"valid call", map[string]interface{}{"module": "hmac.wasm", "function": "verify_signature"}, ""},
+		{"missing function", map[string]interface{}{"module": "hmac.wasm"}, "module and function are required"},
+		{"not a module", map[string]interface{}{"module": "fake.wasm", "function": "verify_signature"}, "is not a WebAssembly module"},
+		{"missing module", map[string]interface{}{"module": "missing.wasm", "function": "verify_signature"}, "failed to open wasm module"},
+		{"runtime failure", map[string]interface{}{"module": "hmac.wasm", "function": "other"}, "wasm hmac.wasm other"},
+		{"absolute module", map[string]interface{}{"module": filepath.Join(outside, "other.wasm"), "function": "verify_signature"}, "must be a relative path inside"},
+		{"module above the base directory", map[string]interface{}{"module": "../" + filepath.Base(outside) + "/other.wasm", "function": "verify_signature"}, "must be a relative path inside"},
+		{"module linked outside", map[string]interface{}{"module": "linked.wasm", "function": "verify_signature"}, "links outside"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response, err := evaluator.Evaluate(context.Background(), EvaluationRequest{Operator: WASMOperator, Arguments: tc.arguments, Span: span})
+			if tc.errorText != "" {
+				assert.ErrorContains(t, err, tc.errorText)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, response.Passed)
+		})
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// Process plugins are executables named flowspec-plugin-<name>. FlowSpec runs them with one
+// command per call and exchanges JSON over stdin and stdout:
+//
+//	flowspec-plugin-acme describe           → Manifest
+//	flowspec-plugin-acme ingest <format>    raw input on stdin → FlowSpec trace JSON ({"traceId", "spans": [...]})
+//	flowspec-plugin-acme evaluate <op>      EvaluationRequest on stdin → EvaluationResponse
+//
+// A non-zero exit status fails the call; stderr becomes the error message.
+
+// ExecutablePrefix starts the file name of every process plugin
+const ExecutablePrefix = "flowspec-plugin-"
+
+// ProtocolVersion is the protocol version this build speaks
+const ProtocolVersion = 1
+
+// PathEnv lists extra plugin directories, separated like PATH
+const PathEnv = "FLOWSPEC_PLUGIN_PATH"
+
+// DefaultTimeout bounds a single plugin call
+const DefaultTimeout = 30 * time.Second
+
+// Manifest describes what a process plugin provides
+type Manifest struct {
+	Name            string   `json:"name"`
+	Version         string   `json:"version"`
+	ProtocolVersion int      `json:"protocolVersion"`
+	Formats         []string `json:"formats,omitempty"`   // Trace formats it ingests
+	Operators       []string `json:"operators,omitempty"` // Assertion operators it evaluates
+}
+
+// Process is a discovered process plugin
+type Process struct {
+	Path     string
+	Manifest Manifest
+	Timeout  time.Duration
+	Env      []string // Environment of each call; the parent's when nil
+}
+
+// run executes a plugin command with stdin and returns its stdout
+func (p *Process) run(ctx context.Context, stdin io.Reader, args ...string) ([]byte, error) {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	command := exec.CommandContext(ctx, p.Path, args...)
+	command.Env = p.Env
+	command.Stdin = stdin
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("plugin %s %s timed out after %s", filepath.Base(p.Path), args[0], timeout)
+		}
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return nil, fmt.Errorf("plugin %s %s: %s", filepath.Base(p.Path), args[0], message)
+	}
+	return stdout.Bytes(), nil
+}
+
+// describe loads the manifest of the plugin
+func (p *Process) describe(ctx context.Context) error {
+	output, err := p.run(ctx, nil, "describe")
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(output, &p.Manifest); err != nil {
+		return fmt.Errorf("plugin %s describe: invalid manifest: %w", filepath.Base(p.Path), err)
+	}
+	if p.Manifest.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("plugin %s speaks protocol version %d, this build supports %d",
+			filepath.Base(p.Path), p.Manifest.ProtocolVersion, ProtocolVersion)
+	}
+	if p.Manifest.Name == "" {
+		p.Manifest.Name = strings.TrimPrefix(filepath.Base(p.Path), ExecutablePrefix)
+	}
+	return nil
+}
+
+// processIngestor ingests one format through a process plugin
+type processIngestor struct {
+	process *Process
+	format  string
+}
+
+func (i *processIngestor) Format() string { return i.format }
+
+func (i *processIngestor) Ingest(ctx context.Context, reader io.Reader) (*models.TraceData, error) {
+	output, err := i.process.run(ctx, reader, "ingest", i.format)
+	if err != nil {
+		return nil, err
+	}
+	var compat models.TraceDataCompat
+	if err := json.Unmarshal(output, &compat); err != nil {
+		return nil, fmt.Errorf("plugin %s returned invalid trace JSON: %w", i.process.Manifest.Name, err)
+	}
+	return models.FromCompatFormat(&compat), nil
+}
+
+// processEvaluator evaluates one operator through a process plugin
+type processEvaluator struct {
+	process  *Process
+	operator string
+}
+
+func (e *processEvaluator) Operator() string { return e.operator }
+
+func (e *processEvaluator) Evaluate(ctx context.Context, request EvaluationRequest) (*EvaluationResponse, error) {
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal evaluation request: %w", err)
+	}
+	output, err := e.process.run(ctx, bytes.NewReader(input), "evaluate", e.operator)
+	if err != nil {
+		return nil, err
+	}
+	var response EvaluationResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		return nil, fmt.Errorf("plugin %s returned an invalid evaluation response: %w", e.process.Manifest.Name, err)
+	}
+	return &response, nil
+}
+
+// DefaultDirs returns the plugin directories in lookup order: FLOWSPEC_PLUGIN_PATH, the
+// project's .flowspec/plugins and the user's ~/.flowspec/plugins
+func DefaultDirs() []string {
+	var dirs []string
+	if value := os.Getenv(PathEnv); value != "" {
+		for _, dir := range filepath.SplitList(value) {
+			if dir != "" {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	dirs = append(dirs, filepath.Join(".flowspec", "plugins"))
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".flowspec", "plugins"))
+	}
+	return dirs
+}
+
+// Discover finds the process plugins in dirs. A plugin name found in several directories is
+// taken from the first. Missing directories are ignored.
+func Discover(dirs []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read plugin directory %s: %w", dir, err)
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || !strings.HasPrefix(name, ExecutablePrefix) {
+				continue
+			}
+			plugin := strings.TrimSuffix(name, filepath.Ext(name))
+			if runtime.GOOS == "windows" && !strings.EqualFold(filepath.Ext(name), ".exe") {
+				continue
+			}
+			if info, err := entry.Info(); err != nil || (runtime.GOOS != "windows" && info.Mode()&0111 == 0) {
+				continue // Not executable
+			}
+			if seen[plugin] {
+				continue
+			}
+			seen[plugin] = true
+			paths = append(paths, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Load discovers the process plugins in dirs, asks each for its manifest and registers what it
+// provides. Plugins that fail to describe themselves are reported as errors.
+func Load(ctx context.Context, registry *Registry, dirs []string) ([]*Process, error) {
+	paths, err := Discover(dirs)
+	if err != nil {
+		return nil, err
+	}
+
+	var processes []*Process
+	var errs []string
+	for _, path := range paths {
+		process := &Process{Path: path}
+		if err := process.describe(ctx); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		source := process.Manifest.Name + " (" + path + ")"
+		for _, format := range process.Manifest.Formats {
+			if err := registry.RegisterIngestor(&processIngestor{process: process, format: format}, source); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		for _, operator := range process.Manifest.Operators {
+			if err := registry.RegisterEvaluator(&processEvaluator{process: process, operator: operator}, source); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		processes = append(processes, process)
+	}
+	if len(errs) > 0 {
+		return processes, fmt.Errorf("failed to load plugins: %s", strings.Join(errs, "; "))
+	}
+	return processes, nil
+}
+
+// FormatHuman renders the loaded plugins for `flowspec-cli plugins list`
+func FormatHuman(processes []*Process) string {
+	if len(processes) == 0 {
+		return fmt.Sprintf("No plugins found. Install executables named %s<name> in one of: %s\n",
+			ExecutablePrefix, strings.Join(DefaultDirs(), ", "))
+	}
+	var output strings.Builder
+	for _, process := range processes {
+		manifest := process.Manifest
+		output.WriteString(fmt.Sprintf("🔌 %s %s (%s)\n", manifest.Name, manifest.Version, process.Path))
+		if len(manifest.Formats) > 0 {
+			output.WriteString(fmt.Sprintf("   trace formats: %s\n", strings.Join(manifest.Formats, ", ")))
+		}
+		if len(manifest.Operators) > 0 {
+			output.WriteString(fmt.Sprintf("   assertion operators: %s\n", strings.Join(manifest.Operators, ", ")))
+		}
+	}
+	return output.String()
+}
@@ -0,0 +1,197 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WASM assertion functions are WASI command modules referenced from an assertion:
+//
+//	postconditions:
+//	  x-wasm: {module: "checks/hmac.wasm", function: "verify_signature", args: {secretVar: "..."}}
+//
+// The module is run by a known WASI runtime with the function as its argument, the
+// EvaluationRequest on stdin, and writes an EvaluationResponse to stdout, just like the evaluate
+// command of a process plugin. FlowSpec builds the runtime's command line itself and grants the
+// module no preopened directory, network access or environment variable, so it can only compute
+// on its input. The runtime process gets PATH as its only environment variable.
+
+// WASMOperator is the assertion operator that calls a WebAssembly function
+const WASMOperator = "x-wasm"
+
+// WASMRuntimeEnv selects the runtime: the name or path of one of the known runtimes, e.g. "wasmer"
+// or "/opt/wasmtime/bin/wasmtime"
+const WASMRuntimeEnv = "FLOWSPEC_WASM_RUNTIME"
+
+// DefaultWASMRuntime is the runtime used when WASMRuntimeEnv is unset
+const DefaultWASMRuntime = "wasmtime"
+
+// wasmRuntimes are the known WASI runtimes, with the arguments running a function of a module
+// without granting it any directory, network or environment
+var wasmRuntimes = map[string]func(module, function string) []string{
+	"wasmtime": func(module, function string) []string { return []string{"run", module, function} },
+	"wasmer":   func(module, function string) []string { return []string{"run", module, "--", function} },
+	"wazero":   func(module, function string) []string { return []string{"run", module, function} },
+	"wasmedge": func(module, function string) []string { return []string{module, function} },
+}
+
+// wasmMagic starts every WebAssembly binary module
+var wasmMagic = []byte{0x00, 'a', 's', 'm'}
+
+// WASMCall is the value of an x-wasm assertion
+type WASMCall struct {
+	Module   string      `json:"module"`
+	Function string      `json:"function"`
+	Args     interface{} `json:"args,omitempty"`
+}
+
+// WASMEvaluator evaluates x-wasm assertions
+type WASMEvaluator struct {
+	Runtime string        // Name or path of a known runtime
+	BaseDir string        // Directory holding the modules
+	Timeout time.Duration // Bounds a single call, DefaultTimeout when zero
+}
+
+// NewWASMEvaluator creates an evaluator loading modules from baseDir, using the runtime from
+// FLOWSPEC_WASM_RUNTIME or wasmtime. A runtime that is not known, or comes with flags, is refused.
+func NewWASMEvaluator(baseDir string) (*WASMEvaluator, error) {
+	runtime := strings.TrimSpace(os.Getenv(WASMRuntimeEnv))
+	if runtime == "" {
+		runtime = DefaultWASMRuntime
+	}
+	if _, err := runtimeArgs(runtime, "", ""); err != nil {
+		return nil, err
+	}
+	return &WASMEvaluator{Runtime: runtime, BaseDir: baseDir}, nil
+}
+
+// runtimeArgs returns the arguments a known runtime runs a module function with
+func runtimeArgs(runtime, module, function string) ([]string, error) {
+	name := strings.TrimSuffix(filepath.Base(runtime), ".exe")
+	args, ok := wasmRuntimes[name]
+	if !ok {
+		known := make([]string, 0, len(wasmRuntimes))
+		for runtimeName := range wasmRuntimes {
+			known = append(known, runtimeName)
+		}
+		sort.Strings(known)
+		return nil, fmt.Errorf("%s=%q is not a known WASM runtime, use one of: %s (without flags)",
+			WASMRuntimeEnv, runtime, strings.Join(known, ", "))
+	}
+	return args(module, function), nil
+}
+
+// Operator implements Evaluator
+func (e *WASMEvaluator) Operator() string { return WASMOperator }
+
+// Evaluate implements Evaluator
+func (e *WASMEvaluator) Evaluate(ctx context.Context, request EvaluationRequest) (*EvaluationResponse, error) {
+	call, err := ParseWASMCall(request.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	module, err := e.resolveModule(call.Module)
+	if err != nil {
+		return nil, err
+	}
+	args, err := runtimeArgs(e.Runtime, module, call.Function)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Operator = call.Function
+	request.Arguments = call.Args
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal evaluation request: %w", err)
+	}
+
+	runtime := &Process{Path: e.Runtime, Timeout: e.Timeout, Env: runtimeEnv()}
+	output, err := runtime.run(ctx, bytes.NewReader(input), args...)
+	if err != nil {
+		return nil, fmt.Errorf("wasm %s %s: %w", call.Module, call.Function, err)
+	}
+	var response EvaluationResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		return nil, fmt.Errorf("wasm %s %s returned an invalid evaluation response: %w", call.Module, call.Function, err)
+	}
+	return &response, nil
+}
+
+// runtimeEnv is the environment of the WASM runtime: PATH only, so no credentials or settings
+// of the parent reach it
+func runtimeEnv() []string {
+	if path, ok := os.LookupEnv("PATH"); ok {
+		return []string{"PATH=" + path}
+	}
+	return []string{}
+}
+
+// ParseWASMCall reads the value of an x-wasm assertion
+func ParseWASMCall(arguments interface{}) (*WASMCall, error) {
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s assertion: %w", WASMOperator, err)
+	}
+	var call WASMCall
+	if err := json.Unmarshal(data, &call); err != nil {
+		return nil, fmt.Errorf("invalid %s assertion, expected {module, function, args}: %w", WASMOperator, err)
+	}
+	if call.Module == "" || call.Function == "" {
+		return nil, fmt.Errorf("invalid %s assertion: module and function are required", WASMOperator)
+	}
+	return &call, nil
+}
+
+// resolveModule returns the absolute path of a module after checking it is WebAssembly inside
+// BaseDir, so a contract cannot load any other module of the machine
+func (e *WASMEvaluator) resolveModule(module string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(module)) {
+		return "", fmt.Errorf("wasm module %s must be a relative path inside %s", module, e.BaseDir)
+	}
+	path := filepath.Join(e.BaseDir, filepath.FromSlash(module))
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open wasm module: %w", err)
+	}
+	baseDir, err := filepath.EvalSymlinks(e.BaseDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to open wasm module: %w", err)
+	}
+	if relative, err := filepath.Rel(baseDir, resolved); err != nil || !filepath.IsLocal(relative) {
+		return "", fmt.Errorf("wasm module %s links outside %s", module, e.BaseDir)
+	}
+	file, err := os.Open(resolved)
+	if err != nil {
+		return "", fmt.Errorf("failed to open wasm module: %w", err)
+	}
+	defer file.Close()
+
+	header := make([]byte, len(wasmMagic))
+	if _, err := io.ReadFull(file, header); err != nil || !bytes.Equal(header, wasmMagic) {
+		return "", fmt.Errorf("%s is not a WebAssembly module", module)
+	}
+	return filepath.Abs(resolved)
+}
@@ -0,0 +1,313 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package probe actively verifies a running service by requesting every contract operation
+// and turning the responses into trace data for the alignment engine (flowspec-cli probe)
+package probe
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/httpclient"
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// DefaultTimeout bounds each probe request
+const DefaultTimeout = 10 * time.Second
+
+// DefaultParameterValue fills path and query parameters without a configured value
+const DefaultParameterValue = "1"
+
+// DefaultHeaderValue fills required headers without a configured value
+const DefaultHeaderValue = "flowspec-probe"
+
+// maxBodyBytes is how much of a response body is read before the connection is reused
+const maxBodyBytes = 1 << 20
+
+// DefaultMethods are the methods probed unless others are allowed explicitly; they must not
+// change server state
+var DefaultMethods = []string{http.MethodGet, http.MethodHead}
+
+// knownMethods are the methods accepted by ParseMethods
+var knownMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true,
+	http.MethodPatch: true, http.MethodDelete: true, http.MethodOptions: true,
+}
+
+// Options configures a probe run
+type Options struct {
+	BaseURL     string             // Service URL the contract paths are appended to
+	Methods     []string           // Methods to probe; DefaultMethods when empty
+	Headers     map[string]string  // Headers sent with every request, e.g. Authorization
+	PathParams  map[string]string  // Values for path parameters such as {id}
+	QueryParams map[string]string  // Values for required query parameters
+	Timeout     time.Duration      // Per-request timeout; DefaultTimeout when zero
+	Client      *http.Client       // HTTP client; a client with Timeout and Network when nil
+	Network     *httpclient.Config // Proxy and TLS settings; httpclient.ConfigFromEnv() when nil
+}
+
+// Request records one probe request
+type Request struct {
+	Operation string        `json:"operation"` // Contract operation, e.g. "GET /users/{id}"
+	URL       string        `json:"url"`
+	Status    int           `json:"status,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// Result holds the probe requests and the trace data built from them
+type Result struct {
+	Requests  []Request         `json:"requests"`
+	Skipped   []string          `json:"skipped,omitempty"` // Operations whose method was not allowed
+	TraceData *models.TraceData `json:"-"`
+}
+
+// ParseMethods parses a comma separated method list
+func ParseMethods(value string) ([]string, error) {
+	var methods []string
+	for _, part := range strings.Split(value, ",") {
+		method := strings.ToUpper(strings.TrimSpace(part))
+		if method == "" {
+			continue
+		}
+		if !knownMethods[method] {
+			return nil, fmt.Errorf("unknown HTTP method %q", part)
+		}
+		methods = append(methods, method)
+	}
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("at least one HTTP method is required")
+	}
+	return methods, nil
+}
+
+// ParseAssignments parses "name=value" pairs, as given to --header, --path-param and --query-param
+func ParseAssignments(values []string) (map[string]string, error) {
+	assignments := make(map[string]string, len(values))
+	for _, value := range values {
+		name, assigned, ok := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid assignment %q, expected name=value", value)
+		}
+		assignments[name] = assigned
+	}
+	return assignments, nil
+}
+
+// Probe requests every operation of specs whose method is allowed and returns the responses
+// as a trace: one server span per request under a root span for the probe run
+func Probe(ctx context.Context, specs []models.ServiceSpec, options Options) (*Result, error) {
+	base, err := url.Parse(strings.TrimSuffix(options.BaseURL, "/"))
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: expected an absolute http(s) URL", options.BaseURL)
+	}
+	methods := options.Methods
+	if len(methods) == 0 {
+		methods = DefaultMethods
+	}
+	allowed := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		allowed[strings.ToUpper(method)] = true
+	}
+	client := options.Client
+	if client == nil {
+		timeout := options.Timeout
+		if timeout <= 0 {
+			timeout = DefaultTimeout
+		}
+		client = httpclient.New(options.Network, timeout)
+	}
+
+	traceID := randomID(16)
+	root := &models.Span{
+		SpanID:     randomID(8),
+		TraceID:    traceID,
+		Name:       "flowspec probe " + base.Host,
+		StartTime:  time.Now().UnixNano(),
+		Status:     models.SpanStatus{Code: "OK"},
+		Attributes: map[string]interface{}{"probe.base_url": options.BaseURL},
+	}
+	traceData := &models.TraceData{TraceID: traceID, Spans: map[string]*models.Span{root.SpanID: root}}
+	result := &Result{TraceData: traceData}
+
+	for _, spec := range specs {
+		if spec.Spec == nil {
+			continue
+		}
+		for _, endpoint := range spec.Spec.Endpoints {
+			for _, operation := range endpoint.Operations {
+				method := strings.ToUpper(operation.Method)
+				key := method + " " + endpoint.Path
+				if !allowed[method] {
+					result.Skipped = append(result.Skipped, key)
+					continue
+				}
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+				request, span := probeOperation(ctx, client, base, endpoint.Path, operation, options)
+				request.Operation = key
+				span.TraceID = traceID
+				span.ParentID = root.SpanID
+				traceData.Spans[span.SpanID] = span
+				result.Requests = append(result.Requests, request)
+			}
+		}
+	}
+	if len(result.Requests) == 0 {
+		return nil, fmt.Errorf("no operations to probe with methods %s", strings.Join(methods, ", "))
+	}
+
+	root.EndTime = time.Now().UnixNano()
+	if err := traceData.BuildSpanTree(); err != nil {
+		return nil, fmt.Errorf("failed to build probe trace: %w", err)
+	}
+	return result, nil
+}
+
+// probeOperation sends one request and records it as a server span carrying the attributes
+// the engine matches and validates
+func probeOperation(ctx context.Context, client *http.Client, base *url.URL, path string, operation models.OperationSpec, options Options) (record Request, span *models.Span) {
+	target, escapedTarget := expandPath(path, options.PathParams)
+	query := url.Values{}
+	for _, name := range operation.Required.Query {
+		query.Set(name, valueOr(options.QueryParams, name, DefaultParameterValue))
+	}
+	requestURL := *base
+	requestURL.Path = base.Path + target
+	requestURL.RawPath = base.EscapedPath() + escapedTarget
+	requestURL.RawQuery = query.Encode()
+
+	method := strings.ToUpper(operation.Method)
+	span = &models.Span{
+		SpanID: randomID(8),
+		Name:   method + " " + path,
+		Attributes: map[string]interface{}{
+			"http.method": method,
+			"http.target": target,
+			"http.route":  path,
+			"http.url":    requestURL.String(),
+			"span.kind":   "server",
+		},
+	}
+	for name := range query {
+		span.Attributes["http.request.query."+name] = query.Get(name)
+	}
+
+	record = Request{URL: requestURL.String()}
+	start := time.Now()
+	span.StartTime = start.UnixNano()
+	defer func() {
+		record.Duration = time.Since(start)
+		span.EndTime = time.Now().UnixNano()
+	}()
+
+	httpRequest, err := http.NewRequestWithContext(ctx, method, requestURL.String(), nil)
+	if err != nil {
+		record.Error = err.Error()
+		span.Status = models.SpanStatus{Code: "ERROR", Message: err.Error()}
+		return record, span
+	}
+	httpRequest.Header.Set("User-Agent", "flowspec-cli probe")
+	for name, value := range options.Headers {
+		httpRequest.Header.Set(name, value)
+	}
+	for _, name := range operation.Required.Headers {
+		if httpRequest.Header.Get(name) == "" {
+			httpRequest.Header.Set(name, DefaultHeaderValue)
+		}
+	}
+	for name := range httpRequest.Header {
+		span.Attributes["http.request.header."+strings.ToLower(name)] = httpRequest.Header.Get(name)
+	}
+
+	response, err := client.Do(httpRequest)
+	if err != nil {
+		record.Error = err.Error()
+		span.Status = models.SpanStatus{Code: "ERROR", Message: err.Error()}
+		return record, span
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxBodyBytes))
+	response.Body.Close()
+
+	record.Status = response.StatusCode
+	span.Attributes["http.status_code"] = response.StatusCode
+	span.Status = models.SpanStatus{Code: "OK"}
+	if response.StatusCode >= 500 {
+		span.Status = models.SpanStatus{Code: "ERROR", Message: response.Status}
+	}
+	return record, span
+}
+
+// expandPath substitutes path parameters and returns the path and its escaped form
+func expandPath(path string, parameters map[string]string) (string, string) {
+	segments := strings.Split(path, "/")
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = valueOr(parameters, strings.Trim(segment, "{}"), DefaultParameterValue)
+		}
+		escaped[i] = url.PathEscape(segments[i])
+	}
+	return strings.Join(segments, "/"), strings.Join(escaped, "/")
+}
+
+// valueOr returns the configured value of name, matched case-insensitively, or fallback
+func valueOr(values map[string]string, name, fallback string) string {
+	if value, ok := values[name]; ok {
+		return value
+	}
+	for key, value := range values {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return fallback
+}
+
+// randomID returns a random hex identifier of n bytes
+func randomID(n int) string {
+	buffer := make([]byte, n)
+	_, _ = rand.Read(buffer)
+	return hex.EncodeToString(buffer)
+}
+
+// FormatHuman renders the probe requests, one line each
+func (r *Result) FormatHuman() string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("🔎 Probed %d operations\n", len(r.Requests)))
+	for _, request := range r.Requests {
+		outcome := fmt.Sprintf("%d", request.Status)
+		if request.Error != "" {
+			outcome = "error: " + request.Error
+		}
+		output.WriteString(fmt.Sprintf("  %-40s %s (%s)\n", request.Operation, outcome, request.Duration.Round(time.Millisecond)))
+	}
+	if len(r.Skipped) > 0 {
+		skipped := append([]string(nil), r.Skipped...)
+		sort.Strings(skipped)
+		output.WriteString(fmt.Sprintf("⏭️  Not probed (method not allowed, see --methods): %s\n", strings.Join(skipped, ", ")))
+	}
+	return output.String()
+}
@@ -0,0 +1,132 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probe
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/engine"
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSpecs() []models.ServiceSpec {
+	return []models.ServiceSpec{{
+		APIVersion: "flowspec/v1alpha1",
+		Kind:       "ServiceSpec",
+		Metadata:   &models.ServiceSpecMetadata{Name: "user-service", Version: "v1"},
+		Spec: &models.ServiceSpecDefinition{Endpoints: []models.EndpointSpec{
+			{Path: "/users/{id}", Operations: []models.OperationSpec{
+				{Method: "GET", Responses: models.ResponseSpec{StatusCodes: []int{200}}},
+				{Method: "DELETE", Responses: models.ResponseSpec{StatusCodes: []int{204}}},
+			}},
+			{Path: "/users", Operations: []models.OperationSpec{
+				{Method: "GET", Responses: models.ResponseSpec{StatusCodes: []int{200}},
+					Required: models.RequiredFieldsSpec{Query: []string{"page"}, Headers: []string{"Authorization", "X-Tenant"}}},
+			}},
+		}},
+	}}
+}
+
+func TestProbe(t *testing.T) {
+	var mu sync.Mutex
+	received := make(map[string]*http.Request)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received[r.Method+" "+r.URL.Path] = r
+		mu.Unlock()
+		if r.URL.Path == "/api/users/a b" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	result, err := Probe(context.Background(), newSpecs(), Options{
+		BaseURL:    server.URL + "/api/",
+		Headers:    map[string]string{"Authorization": "Bearer token"},
+		PathParams: map[string]string{"id": "a b"},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, result.Requests, 2)
+	assert.Equal(t, "GET /users/{id}", result.Requests[0].Operation)
+	assert.Equal(t, 404, result.Requests[0].Status)
+	assert.Equal(t, server.URL+"/api/users/a%20b", result.Requests[0].URL)
+	assert.Equal(t, []string{"DELETE /users/{id}"}, result.Skipped, "unsafe methods are not probed by default")
+
+	list := received["GET /api/users"]
+	require.NotNil(t, list)
+	assert.Equal(t, "1", list.URL.Query().Get("page"))
+	assert.Equal(t, "Bearer token", list.Header.Get("Authorization"))
+	assert.Equal(t, DefaultHeaderValue, list.Header.Get("X-Tenant"))
+	assert.Nil(t, received["DELETE /api/users/a b"])
+
+	// The probe trace goes through the engine like recorded traffic
+	traceData := result.TraceData
+	assert.Len(t, traceData.Spans, 3)
+	require.NotNil(t, traceData.RootSpan)
+	report, err := engine.NewAlignmentEngine().AlignSpecsWithTrace(newSpecs(), traceData)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	operations := report.Results[0].OperationResults
+	assert.Equal(t, models.StatusFailed, operations["GET /users/{id}"].Status)
+	assert.Equal(t, models.StatusSuccess, operations["GET /users"].Status)
+	assert.Equal(t, models.StatusSkipped, operations["DELETE /users/{id}"].Status)
+
+	assert.Contains(t, result.FormatHuman(), "Not probed (method not allowed, see --methods): DELETE /users/{id}")
+}
+
+func TestProbe_Errors(t *testing.T) {
+	_, err := Probe(context.Background(), newSpecs(), Options{BaseURL: "staging.example.com"})
+	assert.Error(t, err)
+
+	_, err = Probe(context.Background(), newSpecs(), Options{BaseURL: "http://localhost", Methods: []string{"PATCH"}})
+	assert.ErrorContains(t, err, "no operations to probe")
+
+	// Connection failures are recorded, not returned
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	result, err := Probe(context.Background(), newSpecs(), Options{BaseURL: server.URL})
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Requests[0].Error)
+	assert.Contains(t, result.FormatHuman(), "error:")
+}
+
+func TestParseMethods(t *testing.T) {
+	methods, err := ParseMethods("get, HEAD,post")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET", "HEAD", "POST"}, methods)
+
+	_, err = ParseMethods("GET,FETCH")
+	assert.Error(t, err)
+	_, err = ParseMethods(" , ")
+	assert.Error(t, err)
+}
+
+func TestParseAssignments(t *testing.T) {
+	assignments, err := ParseAssignments([]string{"id=42", "token=a=b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"id": "42", "token": "a=b"}, assignments)
+
+	_, err = ParseAssignments([]string{"missing"})
+	assert.Error(t, err)
+}
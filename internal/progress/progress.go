@@ -0,0 +1,244 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package progress reports the progress of long operations on stderr: a redrawn bar on a
+// terminal, periodic lines otherwise
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultInterval is how often a progress line is written when the output is not a terminal
+const DefaultInterval = 5 * time.Second
+
+// redrawInterval limits how often the terminal bar is redrawn
+const redrawInterval = 100 * time.Millisecond
+
+// barWidth is the number of cells in the terminal bar
+const barWidth = 30
+
+// Unit selects how amounts are rendered
+type Unit int
+
+const (
+	// Count renders plain numbers, e.g. specs
+	Count Unit = iota
+	// Bytes renders sizes, e.g. 12.5 MiB
+	Bytes
+)
+
+// Enabled reports whether progress should be shown: not when disabled with --no-progress, in
+// CI mode or when the CI environment variable is set
+func Enabled(noProgress, ci bool) bool {
+	if noProgress || ci {
+		return false
+	}
+	value := strings.ToLower(os.Getenv("CI"))
+	return value == "" || value == "false" || value == "0"
+}
+
+// IsTerminal reports whether w is a character device such as a terminal
+func IsTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Reporter tracks one operation. It is safe for concurrent use; a nil Reporter ignores all calls,
+// so callers can pass nil when progress is disabled.
+type Reporter struct {
+	out         io.Writer
+	label       string
+	unit        Unit
+	total       int64
+	interactive bool
+	interval    time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	start    time.Time
+	last     time.Time
+	done     int64
+	detail   string
+	finished bool
+}
+
+// New creates a reporter writing to out. total may be 0 when unknown, which disables the
+// percentage and ETA.
+func New(out io.Writer, label string, unit Unit, total int64) *Reporter {
+	reporter := &Reporter{
+		out:         out,
+		label:       label,
+		unit:        unit,
+		total:       total,
+		interactive: IsTerminal(out),
+		interval:    DefaultInterval,
+		now:         time.Now,
+	}
+	reporter.start = reporter.now()
+	reporter.last = reporter.start
+	return reporter
+}
+
+// Update records that done units are complete; detail is appended to the line, e.g. a
+// record count
+func (r *Reporter) Update(done int64, detail string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.finished {
+		return
+	}
+	r.done = done
+	r.detail = detail
+
+	interval := r.interval
+	if r.interactive {
+		interval = redrawInterval
+	}
+	now := r.now()
+	if now.Sub(r.last) < interval {
+		return
+	}
+	r.last = now
+	r.write(now)
+}
+
+// Add increments the completed units by one and keeps the current detail
+func (r *Reporter) Add() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	done, detail := r.done+1, r.detail
+	r.mu.Unlock()
+	r.Update(done, detail)
+}
+
+// Finish writes the final state and ends the terminal line
+func (r *Reporter) Finish() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.finished {
+		return
+	}
+	r.finished = true
+	r.write(r.now())
+	if r.interactive {
+		fmt.Fprintln(r.out)
+	}
+}
+
+// write renders the current state; the caller holds the lock
+func (r *Reporter) write(now time.Time) {
+	line := r.line(now)
+	if r.interactive {
+		fmt.Fprintf(r.out, "\r\033[K%s", line)
+		return
+	}
+	fmt.Fprintln(r.out, line)
+}
+
+// line renders the progress line, e.g. "explore [####    ] 45% 120.3 MiB/267.0 MiB, 12,034 records, ETA 1m12s"
+func (r *Reporter) line(now time.Time) string {
+	var parts []string
+	parts = append(parts, r.label)
+
+	amount := r.format(r.done)
+	if r.total > 0 {
+		fraction := float64(r.done) / float64(r.total)
+		if fraction > 1 {
+			fraction = 1
+		}
+		if r.interactive {
+			filled := int(fraction * barWidth)
+			parts = append(parts, "["+strings.Repeat("#", filled)+strings.Repeat(" ", barWidth-filled)+"]")
+		}
+		parts = append(parts, fmt.Sprintf("%3.0f%%", fraction*100))
+		amount += "/" + r.format(r.total)
+	}
+	if r.detail != "" {
+		amount += ", " + r.detail
+	}
+	parts = append(parts, amount)
+
+	elapsed := now.Sub(r.start)
+	if r.finished {
+		parts = append(parts, "in "+elapsed.Round(time.Second).String())
+	} else if eta, ok := r.eta(elapsed); ok {
+		parts = append(parts, "ETA "+eta.Round(time.Second).String())
+	}
+	return strings.Join(parts, " ")
+}
+
+// eta extrapolates the remaining time from the rate so far
+func (r *Reporter) eta(elapsed time.Duration) (time.Duration, bool) {
+	if r.total <= 0 || r.done <= 0 || r.done >= r.total {
+		return 0, false
+	}
+	remaining := float64(elapsed) * float64(r.total-r.done) / float64(r.done)
+	return time.Duration(remaining), true
+}
+
+func (r *Reporter) format(value int64) string {
+	if r.unit == Bytes {
+		return FormatBytes(value)
+	}
+	return FormatCount(value)
+}
+
+// FormatBytes renders a size with a binary unit, e.g. 1.5 MiB
+func FormatBytes(value int64) string {
+	const unit = 1024
+	if value < unit {
+		return fmt.Sprintf("%d B", value)
+	}
+	div, exp := int64(unit), 0
+	for n := value / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(value)/float64(div), "KMGTPE"[exp])
+}
+
+// FormatCount renders a number with thousands separators, e.g. 1,234,567
+func FormatCount(value int64) string {
+	digits := fmt.Sprintf("%d", value)
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	var output strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			output.WriteByte(',')
+		}
+		output.WriteRune(digit)
+	}
+	return sign + output.String()
+}
@@ -0,0 +1,105 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock returns a clock advanced by hand
+func fakeClock() (func() time.Time, func(time.Duration)) {
+	now := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+	return func() time.Time { return now }, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestReporter_Lines(t *testing.T) {
+	var output bytes.Buffer
+	now, advance := fakeClock()
+	reporter := New(&output, "explore", Bytes, 4*1024*1024)
+	reporter.now = now
+	reporter.start, reporter.last = now(), now()
+
+	reporter.Update(1024*1024, "1,000 records")
+	assert.Empty(t, output.String(), "no line before the interval")
+
+	advance(DefaultInterval)
+	reporter.Update(1024*1024, "12,034 records")
+	assert.Equal(t, "explore  25% 1.0 MiB/4.0 MiB, 12,034 records ETA 15s\n", output.String())
+
+	output.Reset()
+	advance(time.Second)
+	reporter.Update(4*1024*1024, "48,136 records")
+	reporter.Finish()
+	reporter.Finish()
+	assert.Equal(t, "explore 100% 4.0 MiB/4.0 MiB, 48,136 records in 6s\n", output.String())
+}
+
+func TestReporter_Count(t *testing.T) {
+	var output bytes.Buffer
+	now, advance := fakeClock()
+	reporter := New(&output, "verify", Count, 3)
+	reporter.now = now
+	reporter.start, reporter.last = now(), now()
+
+	reporter.Add()
+	advance(DefaultInterval)
+	reporter.Add()
+	reporter.Finish()
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Equal(t, []string{"verify  67% 2/3 ETA 3s", "verify  67% 2/3 in 5s"}, lines)
+
+	var nilReporter *Reporter
+	nilReporter.Update(1, "")
+	nilReporter.Add()
+	nilReporter.Finish()
+}
+
+func TestEnabled(t *testing.T) {
+	testCases := []struct {
+		name       string
+		ci         string
+		noProgress bool
+		ciMode     bool
+		expected   bool
+	}{
+		{"interactive", "", false, false, true},
+		{"disabled by flag", "", true, false, false},
+		{"CI mode", "", false, true, false},
+		{"CI environment", "true", false, false, false},
+		{"CI environment off", "false", false, false, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("CI", tc.ci)
+			assert.Equal(t, tc.expected, Enabled(tc.noProgress, tc.ciMode))
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "512 B", FormatBytes(512))
+	assert.Equal(t, "1.5 KiB", FormatBytes(1536))
+	assert.Equal(t, "2.0 GiB", FormatBytes(2*1024*1024*1024))
+	assert.Equal(t, "0", FormatCount(0))
+	assert.Equal(t, "999", FormatCount(999))
+	assert.Equal(t, "1,234,567", FormatCount(1234567))
+	assert.Equal(t, "-12,345", FormatCount(-12345))
+}
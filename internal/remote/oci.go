@@ -0,0 +1,312 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Media types of OCI image and Docker manifests accepted for contract artifacts
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// titleAnnotation names the file of an artifact layer, as set by oras push
+const titleAnnotation = "org.opencontainers.image.title"
+
+// RegistryAuth holds the credentials of an OCI registry; anonymous tokens are requested
+// without them
+type RegistryAuth struct {
+	Username string
+	Password string
+}
+
+// RegistryAuthFromEnv reads the registry credentials from FLOWSPEC_REGISTRY_USERNAME and
+// FLOWSPEC_REGISTRY_PASSWORD
+func RegistryAuthFromEnv() *RegistryAuth {
+	return &RegistryAuth{
+		Username: os.Getenv("FLOWSPEC_REGISTRY_USERNAME"),
+		Password: os.Getenv("FLOWSPEC_REGISTRY_PASSWORD"),
+	}
+}
+
+// ociReference is a parsed oci://registry/repository:tag or @digest reference
+type ociReference struct {
+	Registry   string
+	Repository string
+	Reference  string // Tag or digest
+}
+
+// ociManifest is an OCI image manifest
+type ociManifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType,omitempty"`
+	ArtifactType  string            `json:"artifactType,omitempty"`
+	Config        *ociDescriptor    `json:"config,omitempty"`
+	Layers        []ociDescriptor   `json:"layers"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// ociDescriptor refers to a blob of a manifest
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// parseOCIReference parses registry/repository[:tag|@digest]; the tag defaults to latest
+func parseOCIReference(location string) (*ociReference, error) {
+	registry, repository, _ := strings.Cut(location, "/")
+	if registry == "" || repository == "" {
+		return nil, fmt.Errorf("invalid OCI source oci://%s, expected oci://registry/repository:tag", location)
+	}
+	ref := &ociReference{Registry: registry, Repository: repository, Reference: "latest"}
+	if at := strings.Index(repository, "@"); at >= 0 {
+		ref.Repository, ref.Reference = repository[:at], repository[at+1:]
+		if err := ValidateChecksum(ref.Reference); err != nil {
+			return nil, fmt.Errorf("invalid digest in oci://%s: %w", location, err)
+		}
+	} else if colon := strings.LastIndex(repository, ":"); colon >= 0 {
+		ref.Repository, ref.Reference = repository[:colon], repository[colon+1:]
+	}
+	if ref.Repository == "" || ref.Reference == "" {
+		return nil, fmt.Errorf("invalid OCI source oci://%s, expected oci://registry/repository:tag", location)
+	}
+	return ref, nil
+}
+
+// newOCIFetcher returns the fetcher of an OCI artifact holding a contract: the layer titled
+// with a YAML file name, or its only layer
+func newOCIFetcher(location string, options Options, client *http.Client) (fetchFunc, string, error) {
+	ref, err := parseOCIReference(location)
+	if err != nil {
+		return nil, "", err
+	}
+	registry := newRegistryClient(ref, options.Registry, client)
+
+	fetch := func(ctx context.Context) ([]byte, error) {
+		content, err := registry.get(ctx, "/manifests/"+ref.Reference, strings.Join(manifestMediaTypes, ", "))
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(ref.Reference, "sha256:") && contentChecksum(content) != ref.Reference {
+			return nil, fmt.Errorf("manifest of oci://%s does not match its digest: %w", location, ErrChecksumMismatch)
+		}
+		var manifest ociManifest
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to decode manifest of oci://%s: %w", location, err)
+		}
+		digest, err := contractLayer(&manifest)
+		if err != nil {
+			return nil, fmt.Errorf("oci://%s: %w", location, err)
+		}
+
+		blob, err := registry.get(ctx, "/blobs/"+digest, "")
+		if err != nil {
+			return nil, err
+		}
+		if contentChecksum(blob) != digest {
+			return nil, fmt.Errorf("layer %s of oci://%s does not match its digest: %w", digest, location, ErrChecksumMismatch)
+		}
+		return blob, nil
+	}
+	return fetch, defaultFileName, nil
+}
+
+// contractLayer returns the digest of the layer holding the contract
+func contractLayer(manifest *ociManifest) (string, error) {
+	var yamlLayers []string
+	for _, layer := range manifest.Layers {
+		if fileName(layer.Annotations[titleAnnotation]) != defaultFileName {
+			yamlLayers = append(yamlLayers, layer.Digest)
+		}
+	}
+	switch {
+	case len(yamlLayers) == 1:
+		return yamlLayers[0], nil
+	case len(yamlLayers) == 0 && len(manifest.Layers) == 1:
+		return manifest.Layers[0].Digest, nil
+	case len(yamlLayers) > 1:
+		return "", fmt.Errorf("artifact has %d YAML layers, expected one contract", len(yamlLayers))
+	}
+	return "", fmt.Errorf("artifact has %d layers and none is titled with a YAML file name", len(manifest.Layers))
+}
+
+// registryClient sends requests to a repository of a registry, authenticating with a bearer
+// token or basic credentials when the registry challenges
+type registryClient struct {
+	client        *http.Client
+	auth          *RegistryAuth
+	base          string
+	authorization string
+	challenge     string // Last challenge answered
+}
+
+// newRegistryClient creates the client of the repository of a reference; nil credentials are
+// read with RegistryAuthFromEnv
+func newRegistryClient(ref *ociReference, auth *RegistryAuth, client *http.Client) *registryClient {
+	if auth == nil {
+		auth = RegistryAuthFromEnv()
+	}
+	return &registryClient{client: client, auth: auth, base: "https://" + ref.Registry + "/v2/" + ref.Repository}
+}
+
+// get returns the body of a registry endpoint
+func (r *registryClient) get(ctx context.Context, endpoint, accept string) ([]byte, error) {
+	header := http.Header{}
+	if accept != "" {
+		header.Set("Accept", accept)
+	}
+	req, resp, err := r.send(ctx, http.MethodGet, r.base+endpoint, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	return readBody(req, resp)
+}
+
+// send sends a request to the registry, answering an authentication challenge unless it was
+// answered before; a registry challenges again when pushing needs a token of a wider scope
+func (r *registryClient) send(ctx context.Context, method, target string, header http.Header, body []byte) (*http.Request, *http.Response, error) {
+	newRequest := func() (*http.Request, error) {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, target, reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create registry request: %w", err)
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		if r.authorization != "" {
+			req.Header.Set("Authorization", r.authorization)
+		}
+		return req, nil
+	}
+
+	req, err := newRequest()
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := do(r.client, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	if resp.StatusCode == http.StatusUnauthorized && challenge != "" && challenge != r.challenge {
+		resp.Body.Close()
+		if err := r.authenticate(ctx, challenge); err != nil {
+			return nil, nil, err
+		}
+		if req, err = newRequest(); err != nil {
+			return nil, nil, err
+		}
+		if resp, err = do(r.client, req); err != nil {
+			return nil, nil, err
+		}
+	}
+	return req, resp, nil
+}
+
+// authenticate answers a Basic or Bearer challenge, requesting a token from the realm
+func (r *registryClient) authenticate(ctx context.Context, challenge string) error {
+	r.challenge = challenge
+	scheme, params := parseChallenge(challenge)
+	if strings.EqualFold(scheme, "basic") {
+		if r.auth.Username == "" {
+			return fmt.Errorf("%w: registry requires credentials, set FLOWSPEC_REGISTRY_USERNAME and FLOWSPEC_REGISTRY_PASSWORD", ErrUnauthorized)
+		}
+		req, _ := http.NewRequest(http.MethodGet, r.base, nil)
+		req.SetBasicAuth(r.auth.Username, r.auth.Password)
+		r.authorization = req.Header.Get("Authorization")
+		return nil
+	}
+	if !strings.EqualFold(scheme, "bearer") || params["realm"] == "" {
+		return fmt.Errorf("unsupported registry authentication challenge %q", challenge)
+	}
+
+	query := url.Values{}
+	for _, name := range []string{"service", "scope"} {
+		if params[name] != "" {
+			query.Set(name, params[name])
+		}
+	}
+	realm := params["realm"]
+	if len(query) > 0 {
+		realm += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create registry token request: %w", err)
+	}
+	if r.auth.Username != "" {
+		req.SetBasicAuth(r.auth.Username, r.auth.Password)
+	}
+	content, err := readResponse(r.client, req)
+	if err != nil {
+		return fmt.Errorf("failed to get registry token: %w", err)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(content, &token); err != nil {
+		return fmt.Errorf("failed to decode registry token: %w", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	if token.Token == "" {
+		return fmt.Errorf("registry token response has no token")
+	}
+	r.authorization = "Bearer " + token.Token
+	return nil
+}
+
+// parseChallenge splits a WWW-Authenticate header into its scheme and parameters, e.g.
+// Bearer realm="https://auth.example.com/token",service="registry"
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := make(map[string]string)
+	for rest != "" {
+		var name, value string
+		name, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			params[name] = value
+		}
+	}
+	return scheme, params
+}
@@ -0,0 +1,171 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOCIReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	testCases := []struct {
+		location string
+		want     *ociReference
+		wantErr  bool
+	}{
+		{location: "ghcr.io/acme/orders:v1", want: &ociReference{"ghcr.io", "acme/orders", "v1"}},
+		{location: "localhost:5000/orders", want: &ociReference{"localhost:5000", "orders", "latest"}},
+		{location: "ghcr.io/acme/orders@" + digest, want: &ociReference{"ghcr.io", "acme/orders", digest}},
+		{location: "ghcr.io/acme/orders@sha256:abc", wantErr: true},
+		{location: "ghcr.io", wantErr: true},
+		{location: "ghcr.io/acme/orders:", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.location, func(t *testing.T) {
+			ref, err := parseOCIReference(tc.location)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, ref)
+		})
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:acme/orders:pull"`)
+	assert.Equal(t, "Bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:acme/orders:pull",
+	}, params)
+}
+
+// newTestRegistry serves a contract artifact of acme/orders:v1, requiring a bearer token
+func newTestRegistry(t *testing.T, layers []map[string]interface{}, blobs map[string]string) (*httptest.Server, *[]string) {
+	var requests []string
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		if r.URL.Path == "/token" {
+			assert.Equal(t, "repository:acme/orders:pull", r.URL.Query().Get("scope"))
+			w.Write([]byte(`{"access_token": "secret-token"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:acme/orders:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/v2/acme/orders/manifests/v1":
+			assert.Contains(t, r.Header.Get("Accept"), "application/vnd.oci.image.manifest.v1+json")
+			json.NewEncoder(w).Encode(map[string]interface{}{"schemaVersion": 2, "layers": layers})
+		case strings.HasPrefix(r.URL.Path, "/v2/acme/orders/blobs/"):
+			blob, ok := blobs[strings.TrimPrefix(r.URL.Path, "/v2/acme/orders/blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(blob))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server, &requests
+}
+
+func TestFetch_OCI(t *testing.T) {
+	readme := "# Orders contract\n"
+	layers := []map[string]interface{}{
+		{"mediaType": "text/markdown", "digest": contentChecksum([]byte(readme)), "annotations": map[string]string{titleAnnotation: "README.md"}},
+		{"mediaType": "application/yaml", "digest": contentChecksum([]byte(testContract)), "annotations": map[string]string{titleAnnotation: "orders.yaml"}},
+	}
+	server, requests := newTestRegistry(t, layers, map[string]string{
+		contentChecksum([]byte(readme)):       readme,
+		contentChecksum([]byte(testContract)): testContract,
+	})
+	defer server.Close()
+
+	source := "oci://" + strings.TrimPrefix(server.URL, "https://") + "/acme/orders:v1"
+	result, err := Fetch(context.Background(), source, Options{CacheDir: t.TempDir(), Client: server.Client()})
+	require.NoError(t, err)
+	assert.Equal(t, contentChecksum([]byte(testContract)), result.Checksum)
+	assert.Equal(t, []string{
+		"/v2/acme/orders/manifests/v1",
+		"/token",
+		"/v2/acme/orders/manifests/v1",
+		"/v2/acme/orders/blobs/" + contentChecksum([]byte(testContract)),
+	}, *requests, "the token is reused for the blob")
+}
+
+func TestFetch_OCICorruptBlob(t *testing.T) {
+	digest := contentChecksum([]byte(testContract))
+	layers := []map[string]interface{}{{"mediaType": "application/yaml", "digest": digest}}
+	server, _ := newTestRegistry(t, layers, map[string]string{digest: "tampered"})
+	defer server.Close()
+
+	source := "oci://" + strings.TrimPrefix(server.URL, "https://") + "/acme/orders:v1"
+	_, err := Fetch(context.Background(), source, Options{CacheDir: t.TempDir(), Client: server.Client()})
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+}
+
+func TestContractLayer(t *testing.T) {
+	layer := func(digest, title string) map[string]interface{} {
+		return map[string]interface{}{"digest": digest, "annotations": map[string]string{titleAnnotation: title}}
+	}
+	testCases := []struct {
+		name    string
+		layers  []map[string]interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "single untitled layer", layers: []map[string]interface{}{layer("sha256:1", "")}, want: "sha256:1"},
+		{name: "YAML layer among others", layers: []map[string]interface{}{layer("sha256:1", "README.md"), layer("sha256:2", "orders.yml")}, want: "sha256:2"},
+		{name: "several YAML layers", layers: []map[string]interface{}{layer("sha256:1", "a.yaml"), layer("sha256:2", "b.yaml")}, wantErr: true},
+		{name: "no YAML layer", layers: []map[string]interface{}{layer("sha256:1", "a.md"), layer("sha256:2", "b.md")}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(map[string]interface{}{"layers": tc.layers})
+			require.NoError(t, err)
+			var manifest ociManifest
+			require.NoError(t, json.Unmarshal(data, &manifest))
+
+			digest, err := contractLayer(&manifest)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, digest)
+		})
+	}
+}
@@ -0,0 +1,294 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/engine"
+	"github.com/flowspec/flowspec-cli/internal/httpclient"
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/parser"
+	"github.com/flowspec/flowspec-cli/internal/signing"
+)
+
+// Media types of published contract artifacts
+const (
+	ContractArtifactType       = "application/vnd.flowspec.contract.v1"
+	ContractMediaType          = "application/vnd.flowspec.contract.v1+yaml"
+	ContractConfigMediaType    = "application/vnd.flowspec.contract.config.v1+json"
+	ContractSignatureMediaType = "application/vnd.flowspec.contract.signature.v1+json"
+)
+
+// PublishOptions configures how a contract is published
+type PublishOptions struct {
+	SignKey     string             // PEM file of an Ed25519 private key (PKCS #8) signing the contract
+	Annotations map[string]string  // Extra manifest annotations, e.g. org.opencontainers.image.source
+	Timeout     time.Duration      // Per-request timeout; DefaultTimeout when zero
+	Client      *http.Client       // HTTP client; a client with Timeout and Network when nil
+	Network     *httpclient.Config // Proxy and TLS settings; httpclient.ConfigFromEnv() when nil
+	Registry    *RegistryAuth      // Registry credentials; RegistryAuthFromEnv() when nil
+}
+
+// PublishResult describes a published contract
+type PublishResult struct {
+	Reference      string           `json:"reference"`      // oci://registry/repository:tag
+	Digest         string           `json:"digest"`         // Manifest digest, to pin consumers with @digest
+	ContractDigest string           `json:"contractDigest"` // Digest of the contract layer
+	Signed         bool             `json:"signed"`
+	Metadata       ContractMetadata `json:"metadata"`
+}
+
+// ContractMetadata is the config blob of a published contract, describing its services
+type ContractMetadata struct {
+	Services  []PublishedService `json:"services"`
+	CreatedAt time.Time          `json:"createdAt"`
+}
+
+// PublishedService summarizes a service of a published contract
+type PublishedService struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	Endpoints  int    `json:"endpoints"`
+	Operations int    `json:"operations"`
+}
+
+// ContractSignature is the signature layer of a published contract
+type ContractSignature struct {
+	Algorithm      string `json:"algorithm"` // ed25519
+	ContractDigest string `json:"contractDigest"`
+	KeyID          string `json:"keyId"`     // Fingerprint of the public key, as in the .sig files of flowspec-cli sign
+	PublicKey      string `json:"publicKey"` // Base64 of the raw public key
+	Signature      string `json:"signature"` // Base64 of the signature of the contract bytes
+}
+
+// Publish pushes a YAML contract to an OCI registry as an artifact with the contract as its
+// layer, a ContractMetadata config and, with a signing key, a signature layer. A contract with
+// $refs to other files is published bundled, so consumers get a single self-contained file.
+func Publish(ctx context.Context, contractPath, target string, options PublishOptions) (*PublishResult, error) {
+	location, ok := strings.CutPrefix(target, "oci://")
+	if !ok {
+		return nil, fmt.Errorf("unsupported publish target %s, expected oci://registry/repository:tag", target)
+	}
+	ref, err := parseOCIReference(location)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(ref.Reference, "sha256:") || !strings.HasSuffix(location, ":"+ref.Reference) {
+		return nil, fmt.Errorf("publish target %s needs a tag, e.g. oci://registry/repository:1.4.0", target)
+	}
+
+	content, metadata, err := loadContract(contractPath)
+	if err != nil {
+		return nil, err
+	}
+	config, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal contract metadata: %w", err)
+	}
+
+	name := filepath.Base(contractPath)
+	blobs := [][]byte{config, content}
+	manifest := ociManifest{
+		SchemaVersion: 2,
+		MediaType:     manifestMediaTypes[0],
+		ArtifactType:  ContractArtifactType,
+		Config:        &ociDescriptor{MediaType: ContractConfigMediaType, Digest: contentChecksum(config), Size: int64(len(config))},
+		Layers: []ociDescriptor{{
+			MediaType:   ContractMediaType,
+			Digest:      contentChecksum(content),
+			Size:        int64(len(content)),
+			Annotations: map[string]string{titleAnnotation: name},
+		}},
+		Annotations: map[string]string{"org.opencontainers.image.created": metadata.CreatedAt.Format(time.RFC3339)},
+	}
+	if len(metadata.Services) == 1 {
+		manifest.Annotations["org.opencontainers.image.title"] = metadata.Services[0].Name
+		manifest.Annotations["org.opencontainers.image.version"] = metadata.Services[0].Version
+	}
+	for key, value := range options.Annotations {
+		manifest.Annotations[key] = value
+	}
+
+	if options.SignKey != "" {
+		signature, err := signContract(content, options.SignKey)
+		if err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, signature)
+		manifest.Layers = append(manifest.Layers, ociDescriptor{
+			MediaType:   ContractSignatureMediaType,
+			Digest:      contentChecksum(signature),
+			Size:        int64(len(signature)),
+			Annotations: map[string]string{titleAnnotation: name + ".sig.json"},
+		})
+	}
+
+	registry := newRegistryClient(ref, options.Registry, httpClient(options.Client, options.Network, options.Timeout))
+	for _, blob := range blobs {
+		if err := registry.pushBlob(ctx, blob); err != nil {
+			return nil, fmt.Errorf("failed to publish %s: %w", target, err)
+		}
+	}
+	body, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	header := http.Header{"Content-Type": {manifest.MediaType}}
+	req, resp, err := registry.send(ctx, http.MethodPut, registry.base+"/manifests/"+ref.Reference, header, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish %s: %w", target, err)
+	}
+	if _, err := readBody(req, resp); err != nil {
+		return nil, fmt.Errorf("failed to publish %s: %w", target, err)
+	}
+
+	return &PublishResult{
+		Reference:      target,
+		Digest:         contentChecksum(body),
+		ContractDigest: manifest.Layers[0].Digest,
+		Signed:         options.SignKey != "",
+		Metadata:       *metadata,
+	}, nil
+}
+
+// loadContract reads and validates a YAML contract, bundling it when it refers to other files
+func loadContract(contractPath string) ([]byte, *ContractMetadata, error) {
+	yamlParser := parser.NewYAMLFileParser()
+	if !yamlParser.CanParse(contractPath) {
+		return nil, nil, fmt.Errorf("only YAML contracts can be published, got %s", contractPath)
+	}
+	content, err := os.ReadFile(contractPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read contract: %w", err)
+	}
+	specs, parseErrors := yamlParser.ParseFile(contractPath)
+	if len(parseErrors) > 0 {
+		return nil, nil, fmt.Errorf("contract %s is invalid: %w", contractPath, &parseErrors[0])
+	}
+	if len(specs) == 0 {
+		return nil, nil, fmt.Errorf("contract %s has no ServiceSpec", contractPath)
+	}
+
+	// Files pulled in with $ref or include would be missing on the remote, so they are inlined
+	referenced, err := parser.ReferencedFiles(contractPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("contract %s is invalid: %w", contractPath, err)
+	}
+	if len(referenced) > 0 {
+		var bundled bytes.Buffer
+		for i := range specs {
+			if i > 0 {
+				bundled.WriteString("---\n")
+			}
+			if err := engine.EncodeContract(&bundled, &specs[i], engine.ContractFormatYAML); err != nil {
+				return nil, nil, err
+			}
+		}
+		content = bundled.Bytes()
+	}
+
+	metadata := &ContractMetadata{CreatedAt: time.Now().UTC().Truncate(time.Second)}
+	for _, spec := range specs {
+		metadata.Services = append(metadata.Services, summarizeService(&spec))
+	}
+	return content, metadata, nil
+}
+
+// summarizeService counts the endpoints and operations of a service
+func summarizeService(spec *models.ServiceSpec) PublishedService {
+	service := PublishedService{Name: spec.OperationID}
+	if spec.Metadata != nil {
+		service.Name, service.Version = spec.Metadata.Name, spec.Metadata.Version
+	}
+	if spec.Spec != nil {
+		service.Endpoints = len(spec.Spec.Endpoints)
+		for _, endpoint := range spec.Spec.Endpoints {
+			service.Operations += len(endpoint.Operations)
+		}
+	}
+	return service
+}
+
+// signContract signs the contract bytes with the Ed25519 key of a PEM file
+func signContract(content []byte, keyPath string) ([]byte, error) {
+	private, err := signing.LoadPrivateKey(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("signing key: %w", err)
+	}
+
+	signed := signing.Sign(content, private)
+	signature, err := json.Marshal(ContractSignature{
+		Algorithm:      signed.Algorithm,
+		ContractDigest: contentChecksum(content),
+		KeyID:          signed.KeyID,
+		PublicKey:      base64.StdEncoding.EncodeToString(private.Public().(ed25519.PublicKey)),
+		Signature:      signed.Signature,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal contract signature: %w", err)
+	}
+	return signature, nil
+}
+
+// pushBlob uploads a blob unless the repository already has it
+func (r *registryClient) pushBlob(ctx context.Context, blob []byte) error {
+	digest := contentChecksum(blob)
+	_, resp, err := r.send(ctx, http.MethodHead, r.base+"/blobs/"+digest, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	req, resp, err := r.send(ctx, http.MethodPost, r.base+"/blobs/uploads/", nil, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		if _, err := readBody(req, resp); err != nil {
+			return err
+		}
+		return fmt.Errorf("%s returned %s instead of starting an upload", req.URL.Redacted(), resp.Status)
+	}
+	resp.Body.Close()
+	upload, err := req.URL.Parse(resp.Header.Get("Location"))
+	if err != nil || resp.Header.Get("Location") == "" {
+		return fmt.Errorf("registry returned no valid upload location")
+	}
+	query := upload.Query()
+	query.Set("digest", digest)
+	upload.RawQuery = query.Encode()
+
+	header := http.Header{"Content-Type": {"application/octet-stream"}}
+	req, resp, err = r.send(ctx, http.MethodPut, upload.String(), header, blob)
+	if err != nil {
+		return err
+	}
+	_, err = readBody(req, resp)
+	return err
+}
@@ -0,0 +1,273 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/signing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const publishContract = `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: orders
+  version: v1.4.0
+spec:
+  endpoints:
+    - path: /orders/{id}
+      operations:
+        - method: GET
+          responses:
+            statusCodes: [200, 404]
+          required:
+            query: []
+            headers: []
+        - method: DELETE
+          responses:
+            statusCodes: [204]
+          required:
+            query: []
+            headers: []
+`
+
+// memoryRegistry is an in-memory OCI registry handing out tokens scoped per action, like
+// registries that challenge again when a pull token is used to push
+type memoryRegistry struct {
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	uploads   int
+	server    *httptest.Server
+}
+
+func newMemoryRegistry(t *testing.T) *memoryRegistry {
+	registry := &memoryRegistry{blobs: make(map[string][]byte), manifests: make(map[string][]byte)}
+	registry.server = httptest.NewTLSServer(http.HandlerFunc(registry.serve))
+	t.Cleanup(registry.server.Close)
+	return registry
+}
+
+func (m *memoryRegistry) serve(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r.URL.Path == "/token" {
+		json.NewEncoder(w).Encode(map[string]string{"token": "token:" + r.URL.Query().Get("scope")})
+		return
+	}
+	scope := "repository:contracts/orders:pull"
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		scope += ",push"
+	}
+	if r.Header.Get("Authorization") != "Bearer token:"+scope {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="%s"`, m.server.URL, scope))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/v2/contracts/orders")
+	switch {
+	case r.Method == http.MethodPost && path == "/blobs/uploads/":
+		m.uploads++
+		w.Header().Set("Location", fmt.Sprintf("/v2/contracts/orders/blobs/uploads/%d?state=x", m.uploads))
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == http.MethodPut && strings.HasPrefix(path, "/blobs/uploads/"):
+		if r.URL.Query().Get("state") != "x" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if contentChecksum(body) != r.URL.Query().Get("digest") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		m.blobs[contentChecksum(body)] = body
+		w.WriteHeader(http.StatusCreated)
+	case strings.HasPrefix(path, "/blobs/"):
+		blob, ok := m.blobs[strings.TrimPrefix(path, "/blobs/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(blob)
+	case r.Method == http.MethodPut && strings.HasPrefix(path, "/manifests/"):
+		body, _ := io.ReadAll(r.Body)
+		m.manifests[strings.TrimPrefix(path, "/manifests/")] = body
+		w.WriteHeader(http.StatusCreated)
+	case strings.HasPrefix(path, "/manifests/"):
+		manifest, ok := m.manifests[strings.TrimPrefix(path, "/manifests/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(manifest)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (m *memoryRegistry) target(tag string) string {
+	return "oci://" + strings.TrimPrefix(m.server.URL, "https://") + "/contracts/orders:" + tag
+}
+
+func writeSigningKey(t *testing.T, dir string) (string, ed25519.PublicKey) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	path := filepath.Join(dir, "signing.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+	return path, public
+}
+
+func TestPublish(t *testing.T) {
+	dir := t.TempDir()
+	contractPath := filepath.Join(dir, "service-spec.yaml")
+	require.NoError(t, os.WriteFile(contractPath, []byte(publishContract), 0644))
+	keyPath, public := writeSigningKey(t, dir)
+	registry := newMemoryRegistry(t)
+
+	options := PublishOptions{
+		SignKey:     keyPath,
+		Annotations: map[string]string{"org.opencontainers.image.source": "https://github.com/acme/orders"},
+		Client:      registry.server.Client(),
+	}
+	result, err := Publish(context.Background(), contractPath, registry.target("1.4.0"), options)
+	require.NoError(t, err)
+	assert.True(t, result.Signed)
+	assert.Equal(t, contentChecksum([]byte(publishContract)), result.ContractDigest)
+	assert.Equal(t, []PublishedService{{Name: "orders", Version: "v1.4.0", Endpoints: 1, Operations: 2}}, result.Metadata.Services)
+	assert.Equal(t, 3, registry.uploads, "config, contract and signature")
+
+	var manifest ociManifest
+	require.NoError(t, json.Unmarshal(registry.manifests["1.4.0"], &manifest))
+	assert.Equal(t, result.Digest, contentChecksum(registry.manifests["1.4.0"]))
+	assert.Equal(t, ContractArtifactType, manifest.ArtifactType)
+	assert.Equal(t, ContractConfigMediaType, manifest.Config.MediaType)
+	assert.Equal(t, "v1.4.0", manifest.Annotations["org.opencontainers.image.version"])
+	assert.Equal(t, "https://github.com/acme/orders", manifest.Annotations["org.opencontainers.image.source"])
+	require.Len(t, manifest.Layers, 2)
+	assert.Equal(t, "service-spec.yaml", manifest.Layers[0].Annotations[titleAnnotation])
+
+	var signature ContractSignature
+	require.NoError(t, json.Unmarshal(registry.blobs[manifest.Layers[1].Digest], &signature))
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature.Signature)
+	require.NoError(t, err)
+	assert.True(t, ed25519.Verify(public, []byte(publishContract), signatureBytes))
+	assert.Equal(t, base64.StdEncoding.EncodeToString(public), signature.PublicKey)
+	assert.Equal(t, signing.KeyID(public), signature.KeyID)
+
+	// Consumers fetch the published contract, pinned to the manifest digest
+	source := strings.TrimSuffix(registry.target("1.4.0"), ":1.4.0") + "@" + result.Digest
+	registry.manifests[result.Digest] = registry.manifests["1.4.0"]
+	fetched, err := Fetch(context.Background(), source, Options{CacheDir: t.TempDir(), Client: registry.server.Client()})
+	require.NoError(t, err)
+	content, err := os.ReadFile(fetched.Path)
+	require.NoError(t, err)
+	assert.Equal(t, publishContract, string(content))
+
+	// Blobs the repository already has are not uploaded again
+	options.SignKey = ""
+	_, err = Publish(context.Background(), contractPath, registry.target("1.4.1"), options)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, registry.uploads, 4, "the contract is not uploaded again, the config only when its timestamp changed")
+}
+
+func TestPublish_BundlesReferences(t *testing.T) {
+	dir := t.TempDir()
+	index := "apiVersion: flowspec/v1alpha1\nkind: ServiceSpec\nmetadata:\n  name: orders\n  version: v1\nspec:\n  endpoints:\n    - $ref: endpoints/orders.yaml\n"
+	endpoint := "path: /orders\noperations:\n  - method: GET\n    responses:\n      statusCodes: [200]\n    required:\n      query: []\n      headers: []\n"
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "endpoints"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.yaml"), []byte(index), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "endpoints", "orders.yaml"), []byte(endpoint), 0644))
+
+	content, metadata, err := loadContract(filepath.Join(dir, "index.yaml"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "$ref")
+	assert.Contains(t, string(content), "- path: /orders")
+	assert.Equal(t, 1, metadata.Services[0].Operations)
+}
+
+func TestPublish_BundlesIncludes(t *testing.T) {
+	dir := t.TempDir()
+	index := "apiVersion: flowspec/v1alpha1\nkind: ServiceSpec\nmetadata:\n  name: orders\n  version: v1\nspec:\n  endpoints:\n    - path: /orders\n      operations:\n        - include: shared/operations.yaml#/list\n"
+	operations := "list:\n  method: GET\n  responses:\n    statusCodes: [200]\n  required:\n    query: []\n    headers: []\n"
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "shared"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.yaml"), []byte(index), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shared", "operations.yaml"), []byte(operations), 0644))
+
+	content, metadata, err := loadContract(filepath.Join(dir, "index.yaml"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "include")
+	assert.Contains(t, string(content), "method: GET")
+	assert.Equal(t, 1, metadata.Services[0].Operations)
+
+	// A contract referencing only itself is published as written
+	local := "apiVersion: flowspec/v1alpha1\nkind: ServiceSpec\nmetadata:\n  name: orders\n  version: v1\ndefinitions:\n  ok:\n    statusCodes: [200]\nspec:\n  endpoints:\n    - path: /orders\n      operations:\n        - method: GET\n          responses:\n            $ref: \"#/definitions/ok\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "local.yaml"), []byte(local), 0644))
+	content, _, err = loadContract(filepath.Join(dir, "local.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, local, string(content))
+}
+
+func TestPublish_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	contractPath := filepath.Join(dir, "service-spec.yaml")
+	require.NoError(t, os.WriteFile(contractPath, []byte(publishContract), 0644))
+	invalidPath := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalidPath, []byte("apiVersion: flowspec/v1alpha1\nkind: ServiceSpec\nspec: 42\n"), 0644))
+	rsaKeyPath := filepath.Join(dir, "not-a-key.pem")
+	require.NoError(t, os.WriteFile(rsaKeyPath, []byte("not PEM"), 0600))
+
+	testCases := []struct {
+		name     string
+		contract string
+		target   string
+		options  PublishOptions
+		wantErr  string
+	}{
+		{name: "not OCI", contract: contractPath, target: "https://registry/contracts", wantErr: "expected oci://"},
+		{name: "no tag", contract: contractPath, target: "oci://registry.example.com/contracts/orders", wantErr: "needs a tag"},
+		{name: "digest", contract: contractPath, target: "oci://registry.example.com/contracts/orders@sha256:" + strings.Repeat("a", 64), wantErr: "needs a tag"},
+		{name: "invalid contract", contract: invalidPath, target: "oci://registry.example.com/contracts/orders:1", wantErr: "is invalid"},
+		{name: "not YAML", contract: filepath.Join(dir, "spec.json"), target: "oci://registry.example.com/contracts/orders:1", wantErr: "only YAML"},
+		{name: "bad key", contract: contractPath, target: "oci://registry.example.com/contracts/orders:1", options: PublishOptions{SignKey: rsaKeyPath}, wantErr: "not a PEM encoded private key"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Publish(context.Background(), tc.contract, tc.target, tc.options)
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
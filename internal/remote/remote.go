@@ -0,0 +1,325 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package remote fetches contracts published by provider teams over HTTP(S), from S3 or from
+// an OCI registry, so --path can name them directly; fetched contracts are cached locally and
+// can be pinned to a checksum. Publish pushes contracts to OCI registries.
+package remote
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/httpclient"
+)
+
+// DefaultTimeout bounds each request made to fetch a contract
+const DefaultTimeout = 30 * time.Second
+
+// maxContractBytes is the largest contract that is downloaded
+const maxContractBytes = 64 << 20
+
+// defaultFileName names a fetched contract whose source does not end in a YAML file name
+const defaultFileName = "contract.yaml"
+
+// schemes are the URL schemes of remote contract sources
+var schemes = []string{"http://", "https://", "s3://", "oci://"}
+
+// ErrChecksumMismatch is matched by errors.Is when a fetched contract does not have its pinned
+// checksum
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Options configures how contracts are fetched
+type Options struct {
+	CacheDir string             // Directory of fetched contracts; DefaultCacheDir() when empty
+	Checksum string             // Pinned "sha256:<hex>"; a #sha256=<hex> URL fragment pins too
+	Timeout  time.Duration      // Per-request timeout; DefaultTimeout when zero
+	Client   *http.Client       // HTTP client; a client with Timeout and Network when nil
+	Network  *httpclient.Config // Proxy and TLS settings; httpclient.ConfigFromEnv() when nil
+	S3       *S3Config          // S3 endpoint and credentials; S3ConfigFromEnv() when nil
+	Registry *RegistryAuth      // OCI registry credentials; RegistryAuthFromEnv() when nil
+	Retry    *RetryPolicy       // Retries of transient failures; DefaultRetryPolicy() when nil
+}
+
+// Result describes a fetched contract
+type Result struct {
+	Source   string `json:"source"`             // Source without its checksum fragment
+	Path     string `json:"path"`               // Local copy of the contract, for --path
+	Checksum string `json:"checksum"`           // "sha256:<hex>" of the contract
+	Cached   bool   `json:"cached"`             // Served from the cache without a request
+	Stale    bool   `json:"stale"`              // Fetching failed and the last cached copy was used
+	Error    string `json:"error,omitempty"`    // Why fetching failed, for stale results
+	Attempts int    `json:"attempts,omitempty"` // Fetch attempts made, retries included
+}
+
+// IsRemote reports whether a contract path is a remote source rather than a local path
+func IsRemote(source string) bool {
+	lower := strings.ToLower(source)
+	for _, scheme := range schemes {
+		if strings.HasPrefix(lower, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultCacheDir returns the user cache directory of fetched contracts
+func DefaultCacheDir() string {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "flowspec", "contracts")
+	}
+	return filepath.Join(os.TempDir(), "flowspec", "contracts")
+}
+
+// Resolve returns the local path of a contract source: local paths are returned unchanged and
+// remote sources are fetched
+func Resolve(ctx context.Context, source string, options Options) (string, error) {
+	if !IsRemote(source) {
+		return source, nil
+	}
+	result, err := Fetch(ctx, source, options)
+	if err != nil {
+		return "", err
+	}
+	return result.Path, nil
+}
+
+// Fetch downloads a remote contract into the cache. A pinned contract already in the cache is
+// served without a request; otherwise the contract is fetched every time, transient failures
+// being retried, and when the source stays unreachable the last cached copy is used and the
+// result marked stale. In offline mode only the cached copy is used, and a contract that was
+// never fetched fails with httpclient.ErrOffline. A source that does not exist or refuses the
+// credentials fails with ErrNotFound or ErrUnauthorized, and a pinned checksum that does not
+// match with ErrChecksumMismatch.
+func Fetch(ctx context.Context, source string, options Options) (*Result, error) {
+	source, checksum, err := splitChecksum(source, options.Checksum)
+	if err != nil {
+		return nil, err
+	}
+	fetch, name, err := newFetcher(source, options)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheDir := options.CacheDir
+	if cacheDir == "" {
+		cacheDir = DefaultCacheDir()
+	}
+	key := sha256.Sum256([]byte(source))
+	cachePath := filepath.Join(cacheDir, hex.EncodeToString(key[:12]), name)
+	result := &Result{Source: source, Path: cachePath}
+
+	if checksum != "" {
+		if content, err := os.ReadFile(cachePath); err == nil && contentChecksum(content) == checksum {
+			result.Checksum, result.Cached = checksum, true
+			return result, nil
+		}
+	}
+
+	if httpclient.IsOffline(options.Network) {
+		cached, err := os.ReadFile(cachePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch contract %s: %w and it is not cached", source, httpclient.ErrOffline)
+		}
+		result.Checksum, result.Cached = contentChecksum(cached), true
+		if checksum != "" && result.Checksum != checksum {
+			return nil, fmt.Errorf("cached contract %s has checksum %s, pinned %s: %w", source, result.Checksum, checksum, ErrChecksumMismatch)
+		}
+		return result, nil
+	}
+
+	policy := DefaultRetryPolicy()
+	if options.Retry != nil {
+		policy = *options.Retry
+	}
+	content, attempts, fetchErr := fetchWithRetry(ctx, fetch, policy)
+	result.Attempts = attempts
+	if fetchErr != nil {
+		cached, err := os.ReadFile(cachePath)
+		if checksum != "" || err != nil || !errors.Is(fetchErr, ErrTransient) {
+			return nil, fmt.Errorf("failed to fetch contract %s: %w", source, fetchErr)
+		}
+		result.Checksum, result.Cached, result.Stale = contentChecksum(cached), true, true
+		result.Error = fetchErr.Error()
+		return result, nil
+	}
+
+	result.Checksum = contentChecksum(content)
+	if checksum != "" && result.Checksum != checksum {
+		return nil, fmt.Errorf("contract %s has checksum %s, pinned %s: %w", source, result.Checksum, checksum, ErrChecksumMismatch)
+	}
+	if err := writeCacheFile(cachePath, content); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ValidateSource checks the form of a remote source and of its checksum fragment without
+// fetching it
+func ValidateSource(source string) error {
+	source, _, err := splitChecksum(source, "")
+	if err != nil {
+		return err
+	}
+	_, _, err = newFetcher(source, Options{S3: &S3Config{}, Registry: &RegistryAuth{}})
+	return err
+}
+
+// splitChecksum removes a #sha256=<hex> fragment from a source and returns the pinned
+// checksum, which must agree with the one of the options
+func splitChecksum(source, pinned string) (string, string, error) {
+	pinned = strings.ToLower(strings.TrimSpace(pinned))
+	if hash := strings.LastIndex(source, "#"); hash >= 0 {
+		fragment := source[hash+1:]
+		source = source[:hash]
+		value, ok := strings.CutPrefix(fragment, "sha256=")
+		if !ok {
+			return "", "", fmt.Errorf("unsupported fragment #%s in %s, expected #sha256=<hex>", fragment, source)
+		}
+		fromURL := "sha256:" + strings.ToLower(value)
+		if pinned != "" && pinned != fromURL {
+			return "", "", fmt.Errorf("checksum %s of %s does not agree with pinned checksum %s", fromURL, source, pinned)
+		}
+		pinned = fromURL
+	}
+	if pinned != "" {
+		if err := ValidateChecksum(pinned); err != nil {
+			return "", "", err
+		}
+	}
+	return source, pinned, nil
+}
+
+// ValidateChecksum checks that a pinned checksum has the form sha256:<64 hex digits>
+func ValidateChecksum(checksum string) error {
+	value, ok := strings.CutPrefix(checksum, "sha256:")
+	if _, err := hex.DecodeString(value); !ok || err != nil || len(value) != 2*sha256.Size {
+		return fmt.Errorf("invalid checksum %q, expected sha256:<64 hex digits>", checksum)
+	}
+	return nil
+}
+
+// contentChecksum returns the "sha256:<hex>" checksum of content
+func contentChecksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// fetchFunc downloads the content of a contract
+type fetchFunc func(ctx context.Context) ([]byte, error)
+
+// newFetcher returns the fetcher of a source and the file name of its local copy
+func newFetcher(source string, options Options) (fetchFunc, string, error) {
+	client := httpClient(options.Client, options.Network, options.Timeout)
+	scheme, rest, _ := strings.Cut(source, "://")
+	switch strings.ToLower(scheme) {
+	case "http", "https":
+		fetch := func(ctx context.Context) ([]byte, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create request: %w", err)
+			}
+			return readResponse(client, req)
+		}
+		return fetch, fileName(strings.SplitN(rest, "?", 2)[0]), nil
+	case "s3":
+		return newS3Fetcher(rest, options, client)
+	case "oci":
+		return newOCIFetcher(rest, options, client)
+	}
+	return nil, "", fmt.Errorf("unsupported contract source %s", source)
+}
+
+// httpClient returns client, or a client with the network settings and the timeout when it is nil
+func httpClient(client *http.Client, network *httpclient.Config, timeout time.Duration) *http.Client {
+	if client != nil {
+		return client
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return httpclient.New(network, timeout)
+}
+
+// readResponse sends a request and returns the body of a successful response
+func readResponse(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := do(client, req)
+	if err != nil {
+		return nil, err
+	}
+	return readBody(req, resp)
+}
+
+// readBody closes a response and returns its body when it is successful
+func readBody(req *http.Request, resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, newResponseError(req, resp, strings.TrimSpace(string(message)))
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxContractBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", req.URL.Redacted(), err)
+	}
+	if len(content) > maxContractBytes {
+		return nil, fmt.Errorf("%s is larger than %d bytes", req.URL.Redacted(), maxContractBytes)
+	}
+	return content, nil
+}
+
+// fileName returns the base name of a remote path when it is a YAML file, so the parser
+// recognizes the local copy, and defaultFileName otherwise
+func fileName(remotePath string) string {
+	name := path.Base(remotePath)
+	switch strings.ToLower(path.Ext(name)) {
+	case ".yaml", ".yml":
+		return name
+	}
+	return defaultFileName
+}
+
+// writeCacheFile replaces a cached contract atomically, so concurrent runs never read a
+// partial copy
+func writeCacheFile(cachePath string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return fmt.Errorf("failed to create contract cache: %w", err)
+	}
+	temp, err := os.CreateTemp(filepath.Dir(cachePath), ".fetch-*")
+	if err != nil {
+		return fmt.Errorf("failed to create contract cache file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(content); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to write contract cache file: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to write contract cache file: %w", err)
+	}
+	if err := os.Rename(temp.Name(), cachePath); err != nil {
+		return fmt.Errorf("failed to write contract cache file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/httpclient"
+	"github.com/flowspec/flowspec-cli/internal/renderer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testContract = "apiVersion: flowspec/v1alpha1\nkind: ServiceSpec\n"
+
+func TestIsRemote(t *testing.T) {
+	testCases := []struct {
+		source string
+		want   bool
+	}{
+		{"https://contracts.example.com/orders.yaml", true},
+		{"HTTP://contracts.example.com/orders.yaml", true},
+		{"s3://contracts/orders.yaml", true},
+		{"oci://ghcr.io/acme/orders-contract:v1", true},
+		{"contracts/orders.yaml", false},
+		{"/srv/contracts", false},
+		{"file:///srv/contracts", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.source, func(t *testing.T) {
+			assert.Equal(t, tc.want, IsRemote(tc.source))
+		})
+	}
+}
+
+func TestResolve_Local(t *testing.T) {
+	path, err := Resolve(context.Background(), "contracts", Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "contracts", path)
+}
+
+func TestSplitChecksum(t *testing.T) {
+	pin := contentChecksum([]byte(testContract))
+	hexPin := strings.TrimPrefix(pin, "sha256:")
+
+	testCases := []struct {
+		name       string
+		source     string
+		pinned     string
+		wantSource string
+		wantPin    string
+		wantErr    bool
+	}{
+		{name: "unpinned", source: "https://x/a.yaml", wantSource: "https://x/a.yaml"},
+		{name: "fragment", source: "https://x/a.yaml#sha256=" + strings.ToUpper(hexPin), wantSource: "https://x/a.yaml", wantPin: pin},
+		{name: "option", source: "https://x/a.yaml", pinned: pin, wantSource: "https://x/a.yaml", wantPin: pin},
+		{name: "both agree", source: "https://x/a.yaml#sha256=" + hexPin, pinned: pin, wantSource: "https://x/a.yaml", wantPin: pin},
+		{name: "both disagree", source: "https://x/a.yaml#sha256=" + hexPin, pinned: "sha256:" + strings.Repeat("0", 64), wantErr: true},
+		{name: "other fragment", source: "https://x/a.yaml#section", wantErr: true},
+		{name: "short checksum", source: "https://x/a.yaml", pinned: "sha256:abc", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			source, pinned, err := splitChecksum(tc.source, tc.pinned)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantSource, source)
+			assert.Equal(t, tc.wantPin, pinned)
+		})
+	}
+}
+
+func TestFetch_HTTP(t *testing.T) {
+	requests := 0
+	available := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if !available {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(testContract))
+	}))
+	defer server.Close()
+
+	options := Options{CacheDir: t.TempDir(), Retry: &RetryPolicy{Attempts: 2, InitialBackoff: time.Millisecond}}
+	source := server.URL + "/contracts/orders.yaml?ref=main"
+
+	result, err := Fetch(context.Background(), source, options)
+	require.NoError(t, err)
+	assert.Equal(t, "orders.yaml", filepath.Base(result.Path))
+	assert.Equal(t, contentChecksum([]byte(testContract)), result.Checksum)
+	assert.False(t, result.Cached)
+	content, err := os.ReadFile(result.Path)
+	require.NoError(t, err)
+	assert.Equal(t, testContract, string(content))
+
+	// A pinned contract in the cache is served without a request
+	options.Checksum = result.Checksum
+	pinned, err := Fetch(context.Background(), source, options)
+	require.NoError(t, err)
+	assert.True(t, pinned.Cached)
+	assert.Equal(t, result.Path, pinned.Path)
+	assert.Equal(t, 1, requests)
+
+	// An unpinned contract falls back to the cache when the source is down
+	options.Checksum = ""
+	available = false
+	stale, err := Fetch(context.Background(), source, options)
+	require.NoError(t, err)
+	assert.True(t, stale.Stale)
+	assert.Contains(t, stale.Error, "503")
+	assert.Equal(t, result.Checksum, stale.Checksum)
+
+	// ...but not when nothing was cached yet
+	_, err = Fetch(context.Background(), server.URL+"/contracts/payments.yaml", options)
+	assert.ErrorContains(t, err, "503")
+}
+
+func TestFetch_ChecksumMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testContract))
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	_, err := Fetch(context.Background(), server.URL+"/orders#sha256="+strings.Repeat("ab", 32), Options{CacheDir: cacheDir})
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "a contract with the wrong checksum is not cached")
+}
+
+func TestFetch_Offline(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(testContract))
+	}))
+	defer server.Close()
+
+	options := Options{CacheDir: t.TempDir()}
+	source := server.URL + "/contracts/orders.yaml"
+	online, err := Fetch(context.Background(), source, options)
+	require.NoError(t, err)
+
+	options.Network = &httpclient.Config{Offline: true}
+	offline, err := Fetch(context.Background(), source, options)
+	require.NoError(t, err)
+	assert.True(t, offline.Cached)
+	assert.Equal(t, online.Checksum, offline.Checksum)
+	assert.Equal(t, 1, requests, "offline mode serves the cached copy without a request")
+
+	_, err = Fetch(context.Background(), server.URL+"/contracts/payments.yaml", options)
+	assert.ErrorIs(t, err, httpclient.ErrOffline)
+	assert.Equal(t, renderer.ExitUsageError, ExitCode(err))
+	assert.Equal(t, 1, requests)
+}
+
+func TestFileName(t *testing.T) {
+	assert.Equal(t, "orders.yaml", fileName("/contracts/orders.yaml"))
+	assert.Equal(t, "orders.YML", fileName("orders.YML"))
+	assert.Equal(t, defaultFileName, fileName("/contracts/orders"))
+	assert.Equal(t, defaultFileName, fileName(""))
+}
@@ -0,0 +1,141 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/httpclient"
+	"github.com/flowspec/flowspec-cli/internal/renderer"
+)
+
+// Errors matched by errors.Is to tell why fetching a contract failed
+var (
+	ErrUnauthorized = errors.New("unauthorized")      // Credentials are missing or were rejected (401, 403)
+	ErrNotFound     = errors.New("not found")         // The source does not exist (404)
+	ErrTransient    = errors.New("transient failure") // Network errors, timeouts, 408, 429 and 5xx responses, which are retried
+)
+
+// RetryPolicy controls how a contract whose fetching fails with ErrTransient is fetched again
+type RetryPolicy struct {
+	Attempts       int           // Attempts in total, including the first; 1 disables retries
+	InitialBackoff time.Duration // Wait before the first retry, doubled before each further retry
+	MaxBackoff     time.Duration // Longest wait between attempts, also bounding a Retry-After header
+}
+
+// DefaultRetryPolicy makes three attempts, waiting 500ms and then 1s in between
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{Attempts: 3, InitialBackoff: 500 * time.Millisecond, MaxBackoff: 10 * time.Second}
+}
+
+// ExitCode returns the exit code of a failure to fetch a contract: sources that do not exist or
+// refuse the credentials, invalid network settings and offline mode are usage errors, which retrying the
+// job does not fix, and other failures system errors
+func ExitCode(err error) int {
+	if errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrNotFound) || errors.Is(err, httpclient.ErrInvalidConfig) || errors.Is(err, httpclient.ErrOffline) {
+		return renderer.ExitUsageError
+	}
+	return renderer.ExitSystemError
+}
+
+// responseError is the error of an unsuccessful response, classified by its status
+type responseError struct {
+	url        string
+	status     string
+	code       int
+	message    string
+	retryAfter time.Duration // Wait asked for by a 429 or 503 response
+}
+
+// Error implements the error interface
+func (e *responseError) Error() string {
+	return fmt.Sprintf("%s returned %s: %s", e.url, e.status, e.message)
+}
+
+// Is matches ErrUnauthorized, ErrNotFound and ErrTransient by status
+func (e *responseError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.code == http.StatusUnauthorized || e.code == http.StatusForbidden
+	case ErrNotFound:
+		return e.code == http.StatusNotFound
+	case ErrTransient:
+		return e.code == http.StatusRequestTimeout || e.code == http.StatusTooManyRequests || e.code >= 500
+	}
+	return false
+}
+
+// newResponseError describes an unsuccessful response
+func newResponseError(req *http.Request, resp *http.Response, message string) *responseError {
+	err := &responseError{url: req.URL.Redacted(), status: resp.Status, code: resp.StatusCode, message: message}
+	if seconds, parseErr := strconv.Atoi(resp.Header.Get("Retry-After")); parseErr == nil && seconds > 0 {
+		err.retryAfter = time.Duration(seconds) * time.Second
+	}
+	return err
+}
+
+// do sends a request, classifying a failure to get a response as transient unless the context
+// of the request ended, the network settings are invalid or offline mode refused it
+func do(client *http.Client, req *http.Request) (*http.Response, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		if req.Context().Err() != nil || errors.Is(err, httpclient.ErrInvalidConfig) || errors.Is(err, httpclient.ErrOffline) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %w", ErrTransient, err)
+	}
+	return resp, nil
+}
+
+// fetchWithRetry calls fetch until it succeeds, fails with an error that is not transient, or
+// the attempts of the policy are used up, backing off exponentially in between. It returns the
+// number of attempts made.
+func fetchWithRetry(ctx context.Context, fetch fetchFunc, policy RetryPolicy) ([]byte, int, error) {
+	backoff := policy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		content, err := fetch(ctx)
+		if err == nil || !errors.Is(err, ErrTransient) {
+			return content, attempt, err
+		}
+		if attempt >= policy.Attempts {
+			if attempt > 1 {
+				err = fmt.Errorf("%w (%d attempts)", err, attempt)
+			}
+			return nil, attempt, err
+		}
+
+		wait := backoff
+		var responseErr *responseError
+		if errors.As(err, &responseErr) && responseErr.retryAfter > wait {
+			wait = responseErr.retryAfter
+		}
+		if policy.MaxBackoff > 0 && wait > policy.MaxBackoff {
+			wait = policy.MaxBackoff
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, attempt, err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
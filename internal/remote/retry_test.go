@@ -0,0 +1,145 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/httpclient"
+	"github.com/flowspec/flowspec-cli/internal/renderer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseError_Is(t *testing.T) {
+	testCases := []struct {
+		code          int
+		wantAuth      bool
+		wantNotFound  bool
+		wantTransient bool
+	}{
+		{code: http.StatusBadRequest},
+		{code: http.StatusUnauthorized, wantAuth: true},
+		{code: http.StatusForbidden, wantAuth: true},
+		{code: http.StatusNotFound, wantNotFound: true},
+		{code: http.StatusRequestTimeout, wantTransient: true},
+		{code: http.StatusTooManyRequests, wantTransient: true},
+		{code: http.StatusInternalServerError, wantTransient: true},
+		{code: http.StatusServiceUnavailable, wantTransient: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(http.StatusText(tc.code), func(t *testing.T) {
+			err := fmt.Errorf("failed to fetch contract: %w", &responseError{code: tc.code})
+			assert.Equal(t, tc.wantAuth, errors.Is(err, ErrUnauthorized))
+			assert.Equal(t, tc.wantNotFound, errors.Is(err, ErrNotFound))
+			assert.Equal(t, tc.wantTransient, errors.Is(err, ErrTransient))
+		})
+	}
+}
+
+func TestFetch_Retry(t *testing.T) {
+	failures := map[string]int{}
+	requests := map[string]int{}
+	removed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch {
+		case r.URL.Path == "/missing.yaml", removed:
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/private.yaml":
+			w.WriteHeader(http.StatusUnauthorized)
+		case failures[r.URL.Path] > 0:
+			failures[r.URL.Path]--
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte(testContract))
+		}
+	}))
+	defer server.Close()
+	options := Options{CacheDir: t.TempDir(), Retry: &RetryPolicy{Attempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}}
+
+	// Transient failures are retried, and Retry-After is bounded by MaxBackoff
+	failures["/flaky.yaml"] = 2
+	start := time.Now()
+	result, err := Fetch(context.Background(), server.URL+"/flaky.yaml", options)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Attempts)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	failures["/down.yaml"] = 5
+	_, err = Fetch(context.Background(), server.URL+"/down.yaml", options)
+	assert.ErrorIs(t, err, ErrTransient)
+	assert.ErrorContains(t, err, "(3 attempts)")
+	assert.Equal(t, 3, requests["/down.yaml"])
+	assert.Equal(t, renderer.ExitSystemError, ExitCode(err))
+
+	// Missing sources and rejected credentials are not retried
+	_, err = Fetch(context.Background(), server.URL+"/missing.yaml", options)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, 1, requests["/missing.yaml"])
+	assert.Equal(t, renderer.ExitUsageError, ExitCode(err))
+	_, err = Fetch(context.Background(), server.URL+"/private.yaml", options)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.Equal(t, renderer.ExitUsageError, ExitCode(err))
+
+	// A cached copy stands in for an unreachable source, not for one that was removed
+	failures["/flaky.yaml"] = 5
+	stale, err := Fetch(context.Background(), server.URL+"/flaky.yaml", options)
+	require.NoError(t, err)
+	assert.True(t, stale.Stale)
+	removed = true
+	_, err = Fetch(context.Background(), server.URL+"/flaky.yaml", options)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestFetchWithRetry_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	fetch := func(ctx context.Context) ([]byte, error) {
+		attempts++
+		cancel()
+		return nil, fmt.Errorf("%w: connection reset", ErrTransient)
+	}
+	_, made, err := fetchWithRetry(ctx, fetch, RetryPolicy{Attempts: 5, InitialBackoff: time.Hour})
+	assert.ErrorIs(t, err, ErrTransient)
+	assert.Equal(t, 1, attempts, "no retry once the context ends")
+	assert.Equal(t, 1, made)
+
+	_, made, err = fetchWithRetry(context.Background(), fetch, RetryPolicy{})
+	assert.Error(t, err)
+	assert.Equal(t, 1, made, "a policy without attempts makes one")
+}
+
+func TestFetch_InvalidNetworkConfig(t *testing.T) {
+	options := Options{
+		CacheDir: t.TempDir(),
+		Network:  &httpclient.Config{CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+		Retry:    &RetryPolicy{Attempts: 3, InitialBackoff: time.Millisecond},
+	}
+	result, err := Fetch(context.Background(), "https://contracts.example.com/orders.yaml", options)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, httpclient.ErrInvalidConfig)
+	assert.NotErrorIs(t, err, ErrTransient)
+	assert.Equal(t, renderer.ExitUsageError, ExitCode(err))
+}
@@ -0,0 +1,144 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// Badge file names written to the badge directory
+const (
+	BadgeSVGFile  = "flowspec-badge.svg"
+	BadgeJSONFile = "flowspec-badge.json"
+)
+
+// Badge colors, matching the shields.io palette
+const (
+	badgeColorGreen  = "#4c1"
+	badgeColorYellow = "#dfb317"
+	badgeColorRed    = "#e05d44"
+	badgeColorLabel  = "#555"
+)
+
+// badgeWarnCoverage is the coverage ratio below which a passing badge turns yellow
+const badgeWarnCoverage = 0.8
+
+// badgeLabel is the left-hand text of the badge
+const badgeLabel = "contracts"
+
+// shieldsEndpoint is the shields.io endpoint badge schema
+type shieldsEndpoint struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// badgeSummary returns the badge message and color for the report
+func (r *DefaultReportRenderer) badgeSummary(report *models.AlignmentReport) (string, string) {
+	if report.Summary.Failed > 0 {
+		return fmt.Sprintf("%d failing", report.Summary.Failed), badgeColorRed
+	}
+	if r.policyExitCode(report) != ExitSuccess {
+		return "failing", badgeColorRed
+	}
+	if report.Coverage == nil || report.Coverage.TotalOperations == 0 {
+		return "passing", badgeColorGreen
+	}
+
+	message := fmt.Sprintf("passing | %.0f%% coverage", report.Coverage.Ratio*100)
+	if report.Coverage.Ratio < badgeWarnCoverage {
+		return message, badgeColorYellow
+	}
+	return message, badgeColorGreen
+}
+
+// RenderBadgeSVG renders a flat SVG badge summarizing verification status and coverage
+func (r *DefaultReportRenderer) RenderBadgeSVG(report *models.AlignmentReport) (string, error) {
+	if report == nil {
+		return "", fmt.Errorf("report cannot be nil")
+	}
+
+	message, color := r.badgeSummary(report)
+	// Approximate Verdana 11px glyph width; good enough for short labels
+	labelWidth := utf8.RuneCountInString(badgeLabel)*7 + 10
+	messageWidth := utf8.RuneCountInString(message)*7 + 10
+	width := labelWidth + messageWidth
+	title := html.EscapeString(badgeLabel + ": " + message)
+
+	svg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[2]s">
+<title>%[2]s</title>
+<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)"><rect width="%[3]d" height="20" fill="%[5]s"/><rect x="%[3]d" width="%[4]d" height="20" fill="%[6]s"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="%[7]d" y="14">%[8]s</text>
+<text x="%[9]d" y="14">%[10]s</text>
+</g>
+</svg>
+`, width, title, labelWidth, messageWidth, badgeColorLabel, color,
+		labelWidth/2, html.EscapeString(badgeLabel), labelWidth+messageWidth/2, html.EscapeString(message))
+	return svg, nil
+}
+
+// RenderShieldsEndpoint renders a shields.io endpoint JSON file for the same badge
+func (r *DefaultReportRenderer) RenderShieldsEndpoint(report *models.AlignmentReport) (string, error) {
+	if report == nil {
+		return "", fmt.Errorf("report cannot be nil")
+	}
+
+	message, color := r.badgeSummary(report)
+	data, err := json.MarshalIndent(shieldsEndpoint{
+		SchemaVersion: 1,
+		Label:         badgeLabel,
+		Message:       message,
+		Color:         color,
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal shields.io endpoint: %w", err)
+	}
+	return string(data), nil
+}
+
+// writeBadges writes the SVG badge and the shields.io endpoint file into dir
+func (r *DefaultReportRenderer) writeBadges(report *models.AlignmentReport, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create badge directory %s: %w", dir, err)
+	}
+
+	svg, err := r.RenderBadgeSVG(report)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, BadgeSVGFile), []byte(svg), 0644); err != nil {
+		return fmt.Errorf("failed to write badge: %w", err)
+	}
+
+	endpoint, err := r.RenderShieldsEndpoint(report)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, BadgeJSONFile), []byte(endpoint), 0644); err != nil {
+		return fmt.Errorf("failed to write shields.io endpoint: %w", err)
+	}
+	return nil
+}
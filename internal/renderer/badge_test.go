@@ -0,0 +1,97 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBadge(t *testing.T) {
+	testCases := []struct {
+		name            string
+		statuses        []models.AlignmentStatus
+		coverage        *models.CoverageReport
+		expectedMessage string
+		expectedColor   string
+	}{
+		{
+			name:            "passing without coverage",
+			statuses:        []models.AlignmentStatus{models.StatusSuccess},
+			expectedMessage: "passing",
+			expectedColor:   badgeColorGreen,
+		},
+		{
+			name:            "passing with full coverage",
+			statuses:        []models.AlignmentStatus{models.StatusSuccess},
+			coverage:        &models.CoverageReport{TotalOperations: 4, CoveredOperations: 4, Ratio: 1},
+			expectedMessage: "passing | 100% coverage",
+			expectedColor:   badgeColorGreen,
+		},
+		{
+			name:            "passing with low coverage",
+			statuses:        []models.AlignmentStatus{models.StatusSuccess},
+			coverage:        &models.CoverageReport{TotalOperations: 4, CoveredOperations: 2, Ratio: 0.5},
+			expectedMessage: "passing | 50% coverage",
+			expectedColor:   badgeColorYellow,
+		},
+		{
+			name:            "failing",
+			statuses:        []models.AlignmentStatus{models.StatusSuccess, models.StatusFailed, models.StatusFailed},
+			expectedMessage: "2 failing",
+			expectedColor:   badgeColorRed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report := createTestReport(t, tc.statuses)
+			report.Coverage = tc.coverage
+			renderer := NewReportRenderer()
+
+			endpoint, err := renderer.RenderShieldsEndpoint(report)
+			require.NoError(t, err)
+			var badge shieldsEndpoint
+			require.NoError(t, json.Unmarshal([]byte(endpoint), &badge))
+			assert.Equal(t, shieldsEndpoint{SchemaVersion: 1, Label: "contracts", Message: tc.expectedMessage, Color: tc.expectedColor}, badge)
+
+			svg, err := renderer.RenderBadgeSVG(report)
+			require.NoError(t, err)
+			assert.NoError(t, xml.Unmarshal([]byte(svg), new(struct{})), "badge must be well-formed XML")
+			assert.Contains(t, svg, ">"+tc.expectedMessage+"</text>")
+			assert.Contains(t, svg, `fill="`+tc.expectedColor+`"`)
+		})
+	}
+}
+
+func TestWriteArtifacts_Badge(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess})
+
+	config := DefaultRendererConfig()
+	config.BadgeDir = filepath.Join(t.TempDir(), "artifacts")
+	require.NoError(t, NewReportRendererWithConfig(config).WriteArtifacts(report))
+
+	assert.FileExists(t, filepath.Join(config.BadgeDir, BadgeSVGFile))
+	data, err := os.ReadFile(filepath.Join(config.BadgeDir, BadgeJSONFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"schemaVersion": 1`)
+}
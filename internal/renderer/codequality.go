@@ -0,0 +1,94 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// codeQualityIssue is a single entry of a GitLab Code Quality report
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+// RenderCodeQuality renders verification failures as a GitLab Code Quality report, grouped like
+// the SARIF output: one issue per failing check of each failed operation
+func (r *DefaultReportRenderer) RenderCodeQuality(report *models.AlignmentReport) (string, error) {
+	if report == nil {
+		return "", fmt.Errorf("report cannot be nil")
+	}
+
+	issues := []codeQualityIssue{}
+	for _, failure := range collectFailures(report) {
+		checkName := "flowspec/" + failure.ruleType
+		path := r.sarifURI(failure.file)
+		line := failure.line
+		if line < 1 {
+			line = 1
+		}
+
+		// The fingerprint must stay stable across runs so GitLab can tell new issues from resolved ones
+		sum := sha256.Sum256([]byte(checkName + "\x00" + failure.key + "\x00" + path))
+		severity := "major"
+		if failure.ruleType == "timeout" {
+			severity = "critical"
+		}
+
+		issues = append(issues, codeQualityIssue{
+			Description: fmt.Sprintf("%s: %s", failure.key, failure.message()),
+			CheckName:   checkName,
+			Fingerprint: hex.EncodeToString(sum[:16]),
+			Severity:    severity,
+			Location:    codeQualityLocation{Path: path, Lines: codeQualityLines{Begin: line}},
+		})
+	}
+
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal Code Quality report: %w", err)
+	}
+	return string(data), nil
+}
+
+// writeCodeQualityReport renders the report as GitLab Code Quality JSON and writes it to path
+func (r *DefaultReportRenderer) writeCodeQualityReport(report *models.AlignmentReport, path string) error {
+	data, err := r.RenderCodeQuality(report)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		return fmt.Errorf("failed to write Code Quality report %s: %w", path, err)
+	}
+	return nil
+}
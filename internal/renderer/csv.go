@@ -0,0 +1,130 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// csvHeader lists the columns of the CSV export
+var csvHeader = []string{
+	"spec", "service", "operation", "type", "status", "expected", "actual", "message", "span_id", "trace_id",
+}
+
+// RenderCSV renders one row per validation detail, for loading results into spreadsheets or BI tools
+func (r *DefaultReportRenderer) RenderCSV(report *models.AlignmentReport) (string, error) {
+	if report == nil {
+		return "", fmt.Errorf("report cannot be nil")
+	}
+
+	var output bytes.Buffer
+	writer := csv.NewWriter(&output)
+	if err := writer.Write(csvHeader); err != nil {
+		return "", fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, result := range report.Results {
+		for _, row := range csvRows(result) {
+			if err := writer.Write(row); err != nil {
+				return "", fmt.Errorf("failed to write CSV row: %w", err)
+			}
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf("failed to write CSV report: %w", err)
+	}
+	return output.String(), nil
+}
+
+// csvRows lists the rows of a spec result. YAML specs use their per-operation details so each
+// detail is exported once, under the operation it belongs to.
+func csvRows(result models.AlignmentResult) [][]string {
+	var rows [][]string
+	row := func(operation string, detail models.ValidationDetail) []string {
+		status := string(models.StatusSuccess)
+		if !detail.IsPassed() {
+			status = string(models.StatusFailed)
+		}
+		spanID, traceID := "", ""
+		if detail.SpanContext != nil {
+			spanID, traceID = detail.SpanContext.SpanID, detail.SpanContext.TraceID
+		}
+		return []string{
+			result.SpecOperationID, result.Service, operation, detail.Type, status,
+			csvValue(detail.Expected), csvValue(detail.Actual), detail.Message, spanID, traceID,
+		}
+	}
+
+	if len(result.OperationResults) == 0 {
+		for _, detail := range result.Details {
+			operation := detail.Operation
+			if operation == "" {
+				operation = result.SpecOperationID
+			}
+			rows = append(rows, row(operation, detail))
+		}
+		return rows
+	}
+
+	keys := make([]string, 0, len(result.OperationResults))
+	for key := range result.OperationResults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, detail := range result.OperationResults[key].Details {
+			rows = append(rows, row(key, detail))
+		}
+	}
+	return rows
+}
+
+// csvValue formats an expected or actual value; structured values are written as compact JSON
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool, int, int64, float64:
+		return fmt.Sprint(v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// writeCSVReport renders the report as CSV and writes it to path
+func (r *DefaultReportRenderer) writeCSVReport(report *models.AlignmentReport, path string) error {
+	data, err := r.RenderCSV(report)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		return fmt.Errorf("failed to write CSV report %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderCSV(t *testing.T) {
+	report := createGranularReport()
+	report.Results[0].OperationResults["GET /users"].Details[0].Expected = map[string]interface{}{"statusCodes": []int{200}}
+	report.Results[0].OperationResults["POST /users"].Details = []models.ValidationDetail{
+		{Type: "required_header", Expected: "present", Actual: "present", Message: "Required header 'authorization' is present"},
+	}
+
+	output, err := NewReportRenderer().RenderCSV(report)
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 5)
+	assert.Equal(t, csvHeader, rows[0])
+
+	assert.Equal(t, []string{
+		"user-service-v1", "user-service", "GET /users", "status_code", "FAILED", `{"statusCodes":[200]}`, "500",
+		"Status code 500 does not match any expected values", "span-1", "trace-1",
+	}, rows[1])
+	assert.Equal(t, "SUCCESS", rows[2][4])
+	assert.Equal(t, "present", rows[2][6])
+
+	assert.Equal(t, "createUser", rows[3][2], "legacy specs use the spec ID as operation")
+	assert.Equal(t, "SUCCESS", rows[3][4])
+	assert.Equal(t, "FAILED", rows[4][4])
+	assert.Equal(t, "true", rows[4][5])
+	assert.Equal(t, "false", rows[4][6])
+
+	_, err = NewReportRenderer().RenderCSV(nil)
+	assert.Error(t, err)
+}
+
+func TestWriteArtifacts_CSVReport(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusFailed})
+
+	config := DefaultRendererConfig()
+	config.CSVReportPath = filepath.Join(t.TempDir(), "details.csv")
+	require.NoError(t, NewReportRendererWithConfig(config).WriteArtifacts(report))
+
+	data, err := os.ReadFile(config.CSVReportPath)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "spec,service,operation,type,status,expected,actual,message,span_id,trace_id\n"))
+	assert.Contains(t, string(data), "operation-1,,operation-1,postcondition,FAILED,200,500,Status code assertion failed,,")
+}
@@ -0,0 +1,102 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// Conditions accepted by ParseExitPolicy
+const (
+	FailOnFailures = "failures" // Failed or timed out specs
+	FailOnSkipped  = "skipped"  // Specs skipped for lack of matching spans
+	FailOnCoverage = "coverage" // Coverage below the configured minimum
+	FailOnWarnings = "warnings" // Non-fatal findings, e.g. failed checks of warning severity
+)
+
+// ExitPolicy decides which verification outcomes produce a non-zero exit code
+type ExitPolicy struct {
+	FailOnFailures bool
+	FailOnSkipped  bool
+	FailOnCoverage bool // Only applies when a minimum coverage is configured
+	FailOnWarnings bool
+	ExitZero       bool // Warn-only: report problems but always exit 0
+}
+
+// DefaultExitPolicy fails on failed specs and on coverage below the configured minimum
+func DefaultExitPolicy() ExitPolicy {
+	return ExitPolicy{FailOnFailures: true, FailOnCoverage: true}
+}
+
+// ParseExitPolicy parses a comma-separated list of conditions such as "failures,skipped";
+// an empty list returns the default policy and "none" disables every condition
+func ParseExitPolicy(failOn string) (ExitPolicy, error) {
+	if strings.TrimSpace(failOn) == "" {
+		return DefaultExitPolicy(), nil
+	}
+
+	policy := ExitPolicy{}
+	for _, condition := range strings.Split(failOn, ",") {
+		switch strings.ToLower(strings.TrimSpace(condition)) {
+		case FailOnFailures:
+			policy.FailOnFailures = true
+		case FailOnSkipped:
+			policy.FailOnSkipped = true
+		case FailOnCoverage:
+			policy.FailOnCoverage = true
+		case FailOnWarnings, "warning":
+			policy.FailOnWarnings = true
+		case "none":
+		default:
+			return ExitPolicy{}, fmt.Errorf("unknown exit condition %q (supported: %s, %s, %s, %s, none)",
+				condition, FailOnFailures, FailOnSkipped, FailOnCoverage, FailOnWarnings)
+		}
+	}
+	return policy, nil
+}
+
+// exitPolicy returns the configured exit policy or the default one
+func (r *DefaultReportRenderer) exitPolicy() ExitPolicy {
+	if r.config.ExitPolicy == nil {
+		return DefaultExitPolicy()
+	}
+	return *r.config.ExitPolicy
+}
+
+// policyExitCode evaluates the exit policy without the warn-only override, so artifacts
+// such as badges still reflect whether the run met the policy
+func (r *DefaultReportRenderer) policyExitCode(report *models.AlignmentReport) int {
+	if report == nil {
+		return ExitSpecFormatError
+	}
+
+	policy := r.exitPolicy()
+	if policy.FailOnFailures && report.HasFailures() {
+		return ExitValidationFailed
+	}
+	if policy.FailOnSkipped && report.Summary.Skipped > 0 {
+		return ExitValidationFailed
+	}
+	if policy.FailOnCoverage && r.coverageBelowThreshold(report) {
+		return ExitValidationFailed
+	}
+	if policy.FailOnWarnings && report.HasWarnings() {
+		return ExitValidationFailed
+	}
+	return ExitSuccess
+}
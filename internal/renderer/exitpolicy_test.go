@@ -0,0 +1,139 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExitPolicy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		failOn   string
+		expected ExitPolicy
+		wantErr  bool
+	}{
+		{name: "default", failOn: "", expected: DefaultExitPolicy()},
+		{name: "failures only", failOn: "failures", expected: ExitPolicy{FailOnFailures: true}},
+		{name: "all conditions", failOn: "failures, Skipped,coverage", expected: ExitPolicy{FailOnFailures: true, FailOnSkipped: true, FailOnCoverage: true}},
+		{name: "warnings", failOn: "failures,warning", expected: ExitPolicy{FailOnFailures: true, FailOnWarnings: true}},
+		{name: "none", failOn: "none", expected: ExitPolicy{}},
+		{name: "unknown condition", failOn: "failures,flakes", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := ParseExitPolicy(tc.failOn)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, policy)
+		})
+	}
+}
+
+func TestGetExitCode_ExitPolicy(t *testing.T) {
+	testCases := []struct {
+		name        string
+		statuses    []models.AlignmentStatus
+		policy      ExitPolicy
+		minCoverage float64
+		expected    int
+	}{
+		{name: "skipped allowed by default", statuses: []models.AlignmentStatus{models.StatusSuccess, models.StatusSkipped}, policy: DefaultExitPolicy(), expected: ExitSuccess},
+		{name: "fail on skipped", statuses: []models.AlignmentStatus{models.StatusSuccess, models.StatusSkipped}, policy: ExitPolicy{FailOnFailures: true, FailOnSkipped: true}, expected: ExitValidationFailed},
+		{name: "failures ignored", statuses: []models.AlignmentStatus{models.StatusFailed}, policy: ExitPolicy{FailOnSkipped: true}, expected: ExitSuccess},
+		{name: "exit zero", statuses: []models.AlignmentStatus{models.StatusFailed}, policy: ExitPolicy{FailOnFailures: true, ExitZero: true}, expected: ExitSuccess},
+		{name: "coverage not enforced", statuses: nil, policy: ExitPolicy{FailOnFailures: true}, minCoverage: 0.8, expected: ExitSuccess},
+		{name: "coverage enforced", statuses: nil, policy: DefaultExitPolicy(), minCoverage: 0.8, expected: ExitValidationFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report := createCoverageReport(t)
+			if tc.statuses != nil {
+				report = createTestReport(t, tc.statuses)
+			}
+			config := DefaultRendererConfig()
+			config.MinCoverage = tc.minCoverage
+			config.ExitPolicy = &tc.policy
+
+			assert.Equal(t, tc.expected, NewReportRendererWithConfig(config).GetExitCode(report))
+		})
+	}
+}
+
+func TestGetExitCode_FailOnWarnings(t *testing.T) {
+	report := models.NewAlignmentReport()
+	result := models.NewAlignmentResult("listUsers")
+	result.AddValidationDetail(models.ValidationDetail{Type: "postcondition", Expected: true, Actual: true})
+	result.Warnings = []string{"Operation GET /users: 1 check(s) of warning severity failed"}
+	report.AddResult(*result)
+
+	config := DefaultRendererConfig()
+	assert.Equal(t, ExitSuccess, NewReportRendererWithConfig(config).GetExitCode(report), "warnings do not fail by default")
+
+	policy, err := ParseExitPolicy("failures,warnings")
+	require.NoError(t, err)
+	config.ExitPolicy = &policy
+	renderer := NewReportRendererWithConfig(config)
+	assert.Equal(t, ExitValidationFailed, renderer.GetExitCode(report))
+
+	output, err := renderer.RenderHuman(report)
+	require.NoError(t, err)
+	assert.Contains(t, output, "1 warning(s) reported")
+}
+
+func TestRenderHuman_ExitZero(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusFailed})
+
+	config := DefaultRendererConfig()
+	config.ColorOutput = false
+	config.ExitPolicy = &ExitPolicy{FailOnFailures: true, ExitZero: true}
+	renderer := NewReportRendererWithConfigAndLanguage(config, "en")
+
+	output, err := renderer.RenderHuman(report)
+	require.NoError(t, err)
+	assert.Contains(t, output, "The exit policy does not fail the run on these problems (exit code 0)")
+
+	// Artifacts keep reporting the real outcome
+	message, _ := renderer.badgeSummary(report)
+	assert.Equal(t, "1 failing", message)
+	metrics, err := renderer.RenderPrometheus(report)
+	require.NoError(t, err)
+	assert.Contains(t, metrics, "flowspec_verification_passed 0")
+}
+
+func TestGetExitCode_Incomplete(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess})
+	report.Incomplete = true
+	report.IncompleteReason = "interrupted by SIGINT"
+
+	config := DefaultRendererConfig()
+	config.ColorOutput = false
+	config.ExitPolicy = &ExitPolicy{FailOnFailures: true, ExitZero: true}
+	renderer := NewReportRendererWithConfigAndLanguage(config, "en")
+
+	assert.Equal(t, ExitInterrupted, renderer.GetExitCode(report))
+	output, err := renderer.RenderHuman(report)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Incomplete: interrupted by SIGINT, results cover only the specs that finished")
+}
@@ -0,0 +1,147 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// ReportFilter narrows the results shown in a report
+type ReportFilter struct {
+	OnlyFailures bool                     // Keep failed and timed out results only
+	Endpoints    []string                 // Glob patterns on the operation path or "METHOD /path", e.g. "/api/users/*"
+	Statuses     []models.AlignmentStatus // Keep results with one of these statuses
+}
+
+// ParseStatusFilter parses a list of statuses such as "FAILED|SKIPPED" or "failed,skipped"
+func ParseStatusFilter(value string) ([]models.AlignmentStatus, error) {
+	var statuses []models.AlignmentStatus
+	for _, field := range strings.FieldsFunc(value, func(r rune) bool { return r == '|' || r == ',' }) {
+		status := models.AlignmentStatus(strings.ToUpper(strings.TrimSpace(field)))
+		switch status {
+		case models.StatusSuccess, models.StatusFailed, models.StatusSkipped, models.StatusTimeout, models.StatusFlaky:
+			statuses = append(statuses, status)
+		default:
+			return nil, fmt.Errorf("unknown status %q (supported: SUCCESS, FAILED, SKIPPED, TIMEOUT, FLAKY)", field)
+		}
+	}
+	return statuses, nil
+}
+
+// IsEmpty returns true if the filter keeps every result
+func (f ReportFilter) IsEmpty() bool {
+	return !f.OnlyFailures && len(f.Endpoints) == 0 && len(f.Statuses) == 0
+}
+
+// Validate checks that the endpoint patterns are well-formed
+func (f ReportFilter) Validate() error {
+	for _, pattern := range f.Endpoints {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid endpoint pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// FilterReport returns a copy of the report keeping only matching results; operations of
+// YAML specs are filtered individually. The summary and coverage still describe the full run,
+// so exit codes are unaffected by filtering.
+func FilterReport(report *models.AlignmentReport, filter ReportFilter) *models.AlignmentReport {
+	if report == nil || filter.IsEmpty() {
+		return report
+	}
+
+	filtered := *report
+	filtered.Results = make([]models.AlignmentResult, 0, len(report.Results))
+	for _, result := range report.Results {
+		if len(result.OperationResults) == 0 {
+			if filter.matchesStatus(result.Status) && filter.matchesEndpoint(result.SpecOperationID, "") {
+				filtered.Results = append(filtered.Results, result)
+			}
+			continue
+		}
+
+		operations := make(map[string]*models.OperationResult)
+		for key, operation := range result.OperationResults {
+			if filter.matchesStatus(operation.Status) && filter.matchesEndpoint(key, operation.Path) {
+				operations[key] = operation
+			}
+		}
+		if len(operations) == 0 {
+			continue
+		}
+
+		result.OperationResults = operations
+		result.Details = filterDetails(result.Details, operations)
+		var groups []models.FailureGroup
+		for _, group := range result.FailureGroups {
+			if _, ok := operations[group.Operation]; ok {
+				groups = append(groups, group)
+			}
+		}
+		result.FailureGroups = groups
+		filtered.Results = append(filtered.Results, result)
+	}
+	return &filtered
+}
+
+// filterDetails keeps details of the given operations and details not tied to an operation
+func filterDetails(details []models.ValidationDetail, operations map[string]*models.OperationResult) []models.ValidationDetail {
+	kept := make([]models.ValidationDetail, 0, len(details))
+	for _, detail := range details {
+		if _, ok := operations[detail.Operation]; ok || detail.Operation == "" {
+			kept = append(kept, detail)
+		}
+	}
+	return kept
+}
+
+// matchesStatus returns true if the status passes the status and failure filters
+func (f ReportFilter) matchesStatus(status models.AlignmentStatus) bool {
+	if f.OnlyFailures && status != models.StatusFailed && status != models.StatusTimeout {
+		return false
+	}
+	if len(f.Statuses) == 0 {
+		return true
+	}
+	for _, allowed := range f.Statuses {
+		if status == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesEndpoint returns true if the operation key or path matches one of the endpoint patterns
+func (f ReportFilter) matchesEndpoint(key, operationPath string) bool {
+	if len(f.Endpoints) == 0 {
+		return true
+	}
+	for _, pattern := range f.Endpoints {
+		for _, candidate := range []string{key, operationPath} {
+			if candidate == "" {
+				continue
+			}
+			if matched, _ := path.Match(pattern, candidate); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createFilterReport builds a report with a YAML spec of three operations and a legacy spec
+func createFilterReport() *models.AlignmentReport {
+	report := models.NewAlignmentReport()
+
+	users := models.NewAlignmentResult("user-service-v1")
+	users.Status = models.StatusFailed
+	users.OperationResults = map[string]*models.OperationResult{
+		"GET /api/users/{id}": {Path: "/api/users/{id}", Method: "GET", Status: models.StatusFailed},
+		"POST /api/users":     {Path: "/api/users", Method: "POST", Status: models.StatusSuccess},
+		"GET /api/orders":     {Path: "/api/orders", Method: "GET", Status: models.StatusSkipped},
+	}
+	users.Details = []models.ValidationDetail{
+		{Type: "status_code", Operation: "GET /api/users/{id}", Expected: 200, Actual: 500, Message: "status mismatch"},
+		{Type: "status_code", Operation: "POST /api/users", Expected: 201, Actual: 201},
+	}
+	users.FailureGroups = models.GroupFailures(users.Details)
+	report.AddResult(*users)
+
+	legacy := models.NewAlignmentResult("createOrder")
+	legacy.Status = models.StatusSuccess
+	report.AddResult(*legacy)
+	return report
+}
+
+func TestFilterReport(t *testing.T) {
+	testCases := []struct {
+		name       string
+		filter     ReportFilter
+		results    []string
+		operations []string
+	}{
+		{name: "no filter", filter: ReportFilter{}, results: []string{"user-service-v1", "createOrder"}, operations: []string{"GET /api/orders", "GET /api/users/{id}", "POST /api/users"}},
+		{name: "only failures", filter: ReportFilter{OnlyFailures: true}, results: []string{"user-service-v1"}, operations: []string{"GET /api/users/{id}"}},
+		{name: "endpoint path", filter: ReportFilter{Endpoints: []string{"/api/users/*"}}, results: []string{"user-service-v1"}, operations: []string{"GET /api/users/{id}"}},
+		{name: "endpoint with method", filter: ReportFilter{Endpoints: []string{"POST /api/*"}}, results: []string{"user-service-v1"}, operations: []string{"POST /api/users"}},
+		{name: "legacy spec id", filter: ReportFilter{Endpoints: []string{"create*"}}, results: []string{"createOrder"}},
+		{name: "statuses", filter: ReportFilter{Statuses: []models.AlignmentStatus{models.StatusSkipped, models.StatusSuccess}}, results: []string{"user-service-v1", "createOrder"}, operations: []string{"GET /api/orders", "POST /api/users"}},
+		{name: "no match", filter: ReportFilter{Endpoints: []string{"/health"}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report := createFilterReport()
+			filtered := FilterReport(report, tc.filter)
+
+			var results, operations []string
+			for _, result := range filtered.Results {
+				results = append(results, result.SpecOperationID)
+				for key := range result.OperationResults {
+					operations = append(operations, key)
+				}
+			}
+			sort.Strings(operations)
+			assert.Equal(t, tc.results, results)
+			assert.Equal(t, tc.operations, operations)
+
+			// The summary keeps describing the full run and the input is untouched
+			assert.Equal(t, report.Summary, filtered.Summary)
+			assert.Len(t, report.Results[0].OperationResults, 3)
+		})
+	}
+}
+
+func TestFilterReport_Details(t *testing.T) {
+	filtered := FilterReport(createFilterReport(), ReportFilter{Endpoints: []string{"POST /api/users"}})
+	require.Len(t, filtered.Results, 1)
+
+	result := filtered.Results[0]
+	require.Len(t, result.Details, 1)
+	assert.Equal(t, "POST /api/users", result.Details[0].Operation)
+	assert.Empty(t, result.FailureGroups)
+}
+
+func TestParseStatusFilter(t *testing.T) {
+	statuses, err := ParseStatusFilter("FAILED|skipped,flaky")
+	require.NoError(t, err)
+	assert.Equal(t, []models.AlignmentStatus{models.StatusFailed, models.StatusSkipped, models.StatusFlaky}, statuses)
+
+	_, err = ParseStatusFilter("FAILED|BROKEN")
+	assert.Error(t, err)
+
+	assert.Error(t, ReportFilter{Endpoints: []string{"/api/["}}.Validate())
+	assert.NoError(t, ReportFilter{Endpoints: []string{"/api/*"}}.Validate())
+}
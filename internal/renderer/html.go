@@ -0,0 +1,231 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// htmlReportTemplate is a self-contained page: styles and the filter script are inlined
+// so the report can be shared as a single file
+const htmlReportTemplate = `<!DOCTYPE html>
+<html lang="{{.Language}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{T "report.title"}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; padding: 24px; background: #f6f8fa; color: #24292f; }
+h1 { margin-top: 0; }
+.summary { display: flex; gap: 12px; flex-wrap: wrap; margin-bottom: 16px; }
+.card { background: #fff; border: 1px solid #d0d7de; border-radius: 6px; padding: 12px 16px; min-width: 120px; }
+.filters { margin-bottom: 16px; }
+.filters button { border: 1px solid #d0d7de; background: #fff; border-radius: 6px; padding: 4px 12px; cursor: pointer; }
+.filters button.active { background: #0969da; color: #fff; border-color: #0969da; }
+.result { background: #fff; border: 1px solid #d0d7de; border-left-width: 4px; border-radius: 6px; margin-bottom: 12px; padding: 12px 16px; }
+.result.SUCCESS { border-left-color: #1a7f37; }
+.result.FAILED, .result.TIMEOUT { border-left-color: #cf222e; }
+.result.SKIPPED { border-left-color: #9a6700; }
+.status { font-weight: 600; }
+.SUCCESS .status { color: #1a7f37; }
+.FAILED .status, .TIMEOUT .status { color: #cf222e; }
+.SKIPPED .status, .FLAKY .status { color: #9a6700; }
+.warning { color: #9a6700; }
+table { border-collapse: collapse; width: 100%; margin: 8px 0; }
+th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #d0d7de; }
+details { margin: 6px 0; }
+summary { cursor: pointer; }
+pre { background: #f6f8fa; padding: 8px; border-radius: 6px; overflow-x: auto; }
+.meta { color: #57606a; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>{{T "report.title"}}</h1>
+<p class="meta">{{.Generated}}</p>
+<div class="summary">
+<div class="card">{{T "summary.total" .Report.Summary.Total}}</div>
+<div class="card SUCCESS"><span class="status">{{T "summary.success" .Report.Summary.Success}}</span></div>
+<div class="card FAILED"><span class="status">{{T "summary.failed" .Report.Summary.Failed}}</span></div>
+<div class="card SKIPPED"><span class="status">{{T "summary.skipped" .Report.Summary.Skipped}}</span></div>
+{{with .Report.Coverage}}<div class="card">{{T "summary.coverage" .CoveredOperations .TotalOperations (percent .Ratio)}}</div>{{end}}
+</div>
+<div class="filters">
+<button type="button" class="active" data-filter="all">{{T "html.filter_all"}}</button>
+<button type="button" data-filter="FAILED">{{T "html.filter_failed"}}</button>
+</div>
+{{range .Results}}
+<div class="result {{.Status}}" data-status="{{.Status}}">
+<div><span class="status">{{.Status}}</span> <strong>{{.Name}}</strong> <span class="meta">{{.Duration}}</span></div>
+{{with .ErrorMessage}}<p class="warning">{{.}}</p>{{end}}
+{{range .Warnings}}<p class="warning">⚠ {{T "result.warning"}}: {{.}}</p>{{end}}
+{{if .Operations}}
+<table>
+<tr><th>{{T "html.operation"}}</th><th>{{T "html.status"}}</th><th>{{T "html.samples"}}</th><th>{{T "html.assertions"}}</th></tr>
+{{range .Operations}}<tr class="{{.Status}}"><td>{{.Key}}</td><td class="status">{{.Status}}</td><td>{{.SampleCount}}</td><td>{{.AssertionsPassed}}/{{.AssertionsTotal}}</td></tr>
+{{end}}
+</table>
+{{end}}
+{{range .Failures}}
+<details>
+<summary>{{with .Operation}}<strong>{{.}}</strong>: {{end}}{{.Message}}</summary>
+<table>
+<tr><th>{{T "html.expected"}}</th><td><code>{{json .Expected}}</code></td></tr>
+<tr><th>{{T "html.actual"}}</th><td><code>{{json .Actual}}</code></td></tr>
+{{with .Expression}}<tr><th>{{T "html.expression"}}</th><td><code>{{.}}</code></td></tr>{{end}}
+{{with .FailureReason}}<tr><th>{{T "html.failure_reason"}}</th><td>{{.}}</td></tr>{{end}}
+{{with traceLink .SpanContext}}<tr><th>{{T "trace.link"}}</th><td><a href="{{.}}" target="_blank" rel="noopener">{{.}}</a></td></tr>{{end}}
+</table>
+{{with .Suggestions}}<p><strong>{{T "html.suggestions"}}</strong></p><ul>{{range .}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{with .SpanContext}}<details><summary>{{T "html.span_context"}}: {{.SpanID}} ({{.Name}})</summary><pre>{{json .}}</pre></details>{{end}}
+</details>
+{{end}}
+</div>
+{{end}}
+<script>
+document.querySelectorAll('.filters button').forEach(function (button) {
+  button.addEventListener('click', function () {
+    document.querySelectorAll('.filters button').forEach(function (b) { b.classList.remove('active'); });
+    button.classList.add('active');
+    var filter = button.getAttribute('data-filter');
+    document.querySelectorAll('.result').forEach(function (result) {
+      var status = result.getAttribute('data-status');
+      result.style.display = (filter === 'all' || status === filter || (filter === 'FAILED' && status === 'TIMEOUT')) ? '' : 'none';
+    });
+  });
+});
+</script>
+</body>
+</html>
+`
+
+// htmlReportData is the view model of the HTML report
+type htmlReportData struct {
+	Language  string
+	Generated string
+	Report    *models.AlignmentReport
+	Results   []htmlResult
+}
+
+// htmlResult is a single spec result in the HTML report
+type htmlResult struct {
+	Name         string
+	Status       models.AlignmentStatus
+	Duration     time.Duration
+	ErrorMessage string
+	Warnings     []string
+	Operations   []htmlOperation
+	Failures     []models.ValidationDetail
+}
+
+// htmlOperation is one operation row of a YAML spec result
+type htmlOperation struct {
+	Key string
+	*models.OperationResult
+}
+
+// RenderHTML renders the report as a self-contained interactive HTML page
+func (r *DefaultReportRenderer) RenderHTML(report *models.AlignmentReport) (string, error) {
+	if report == nil {
+		return "", fmt.Errorf("report cannot be nil")
+	}
+
+	tmpl, err := template.New("report").Funcs(template.FuncMap{
+		"T":         r.localizer.T,
+		"traceLink": r.traceLink,
+		"percent":   func(ratio float64) float64 { return ratio * 100 },
+		"json": func(value interface{}) string {
+			data, err := json.MarshalIndent(value, "", "  ")
+			if err != nil {
+				return fmt.Sprint(value)
+			}
+			return string(data)
+		},
+	}).Parse(htmlReportTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse HTML template: %w", err)
+	}
+
+	data := htmlReportData{
+		Language: string(r.localizer.GetLanguage()),
+		Report:   report,
+		Results:  make([]htmlResult, 0, len(report.Results)),
+	}
+	if report.EndTime > 0 {
+		data.Generated = r.localizer.T("html.generated", time.Unix(0, report.EndTime).UTC().Format(time.RFC3339))
+	}
+
+	for _, result := range report.Results {
+		data.Results = append(data.Results, newHTMLResult(result))
+	}
+
+	var output bytes.Buffer
+	if err := tmpl.Execute(&output, data); err != nil {
+		return "", fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return output.String(), nil
+}
+
+// newHTMLResult builds the view of a spec result, keeping only failed validation details
+func newHTMLResult(result models.AlignmentResult) htmlResult {
+	name := result.SpecOperationID
+	if result.Service != "" && !strings.HasPrefix(name, result.Service) {
+		name = result.Service + " / " + name
+	}
+
+	view := htmlResult{
+		Name:         name,
+		Status:       result.Status,
+		Duration:     time.Duration(result.ExecutionTime),
+		ErrorMessage: result.ErrorMessage,
+		Warnings:     result.Warnings,
+	}
+
+	keys := make([]string, 0, len(result.OperationResults))
+	for key := range result.OperationResults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		view.Operations = append(view.Operations, htmlOperation{Key: key, OperationResult: result.OperationResults[key]})
+	}
+
+	for _, detail := range result.Details {
+		if detail.Type != "matching" && !detail.IsPassed() {
+			view.Failures = append(view.Failures, detail)
+		}
+	}
+	return view
+}
+
+// writeHTMLReport renders the report as HTML and writes it to path
+func (r *DefaultReportRenderer) writeHTMLReport(report *models.AlignmentReport, path string) error {
+	page, err := r.RenderHTML(report)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(page), 0644); err != nil {
+		return fmt.Errorf("failed to write HTML report %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHTML(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess, models.StatusFailed})
+	failed := &report.Results[1]
+	failed.Warnings = []string{"Deprecated operation GET /legacy still received 1 request(s)"}
+	failed.Details[0].Suggestions = []string{"Check the <handler> error path"}
+	failed.Details[0].SpanContext = &models.Span{
+		SpanID:     "span-2",
+		Name:       "GET /users",
+		Attributes: map[string]interface{}{"http.status_code": 500},
+	}
+	failed.OperationResults = map[string]*models.OperationResult{
+		"GET /users": {Path: "/users", Method: "GET", Status: models.StatusFailed, SampleCount: 1, AssertionsTotal: 1},
+	}
+
+	renderer := NewReportRendererWithLanguage("en")
+	page, err := renderer.RenderHTML(report)
+	require.NoError(t, err)
+
+	assert.Contains(t, page, "<!DOCTYPE html>")
+	assert.Contains(t, page, "<title>FlowSpec Validation Report</title>")
+	assert.Contains(t, page, "Total: 2 ServiceSpecs")
+	assert.Contains(t, page, `data-status="FAILED"`)
+	assert.Contains(t, page, "<strong>operation-2</strong>")
+	assert.Contains(t, page, "Status code assertion failed")
+	assert.Contains(t, page, "Expected 200 but got 500")
+	assert.Contains(t, page, "Span context: span-2 (GET /users)")
+	assert.Contains(t, page, "&#34;http.status_code&#34;: 500")
+	assert.Contains(t, page, "Warning: Deprecated operation GET /legacy")
+	assert.Contains(t, page, "<td>GET /users</td>")
+	assert.Contains(t, page, "Check the &lt;handler&gt; error path", "content must be escaped")
+	assert.NotContains(t, page, "Assertion passed successfully", "passed details are not listed")
+
+	_, err = renderer.RenderHTML(nil)
+	assert.Error(t, err)
+}
+
+func TestWriteArtifacts_HTMLReport(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess})
+
+	config := DefaultRendererConfig()
+	config.HTMLReportPath = filepath.Join(t.TempDir(), "report.html")
+	require.NoError(t, NewReportRendererWithConfigAndLanguage(config, "zh").WriteArtifacts(report))
+
+	page, err := os.ReadFile(config.HTMLReportPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(page), `<html lang="zh">`)
+	assert.Contains(t, string(page), "仅失败")
+
+	config.HTMLReportPath = filepath.Join(t.TempDir(), "missing", "report.html")
+	assert.Error(t, NewReportRendererWithConfig(config).WriteArtifacts(report))
+}
@@ -0,0 +1,239 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	File      string          `xml:"file,attr,omitempty"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Time      string        `xml:"time,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// RenderJUnit renders the report as JUnit XML with one test suite per spec. YAML specs get one
+// test case per operation; source annotation specs get one test case per assertion, so CI test
+// tabs show which operation or assertion failed rather than only the spec.
+func (r *DefaultReportRenderer) RenderJUnit(report *models.AlignmentReport) (string, error) {
+	if report == nil {
+		return "", fmt.Errorf("report cannot be nil")
+	}
+
+	root := junitTestSuites{Name: "flowspec", Time: junitSeconds(report.ExecutionTime)}
+	for _, result := range report.Results {
+		suite := newJUnitSuite(result)
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.Errors += suite.Errors
+		root.Skipped += suite.Skipped
+		root.Suites = append(root.Suites, suite)
+	}
+
+	data, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JUnit report: %w", err)
+	}
+	return xml.Header + string(data) + "\n", nil
+}
+
+// newJUnitSuite builds the test suite of a single spec result
+func newJUnitSuite(result models.AlignmentResult) junitTestSuite {
+	className := result.SpecOperationID
+	if result.Service != "" {
+		className = result.Service
+	}
+	suite := junitTestSuite{
+		Name: result.SpecOperationID,
+		Time: junitSeconds(result.ExecutionTime),
+		File: result.SourceFile,
+	}
+
+	switch {
+	case result.Status == models.StatusTimeout:
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      result.SpecOperationID,
+			ClassName: className,
+			File:      result.SourceFile,
+			Line:      result.LineNumber,
+			Error:     &junitFailure{Message: result.ErrorMessage, Type: "timeout", Text: result.ErrorMessage},
+		})
+
+	case len(result.OperationResults) > 0:
+		keys := make([]string, 0, len(result.OperationResults))
+		for key := range result.OperationResults {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			operation := result.OperationResults[key]
+			testCase := junitTestCase{
+				Name:      key,
+				ClassName: className,
+				File:      operation.SourceFile,
+				Line:      operation.LineNumber,
+				SystemOut: strings.Join(operation.Warnings, "\n"),
+			}
+			switch operation.Status {
+			case models.StatusFailed:
+				testCase.Failure = newJUnitFailure(operation.Details)
+			case models.StatusSkipped:
+				testCase.Skipped = &junitSkipped{Message: "operation not exercised by the trace"}
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+
+	case len(result.Details) > 0:
+		for i, detail := range result.Details {
+			name := detail.Expression
+			if name == "" {
+				name = detail.Message
+			}
+			testCase := junitTestCase{
+				Name:      fmt.Sprintf("%s #%d: %s", detail.Type, i+1, name),
+				ClassName: className,
+				File:      result.SourceFile,
+				Line:      result.LineNumber,
+			}
+			if !detail.IsPassed() {
+				testCase.Failure = newJUnitFailure([]models.ValidationDetail{detail})
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+
+	default:
+		testCase := junitTestCase{
+			Name:      result.SpecOperationID,
+			ClassName: className,
+			File:      result.SourceFile,
+			Line:      result.LineNumber,
+		}
+		switch result.Status {
+		case models.StatusSkipped:
+			testCase.Skipped = &junitSkipped{Message: result.ErrorMessage}
+		case models.StatusFailed:
+			testCase.Failure = &junitFailure{Message: result.ErrorMessage, Type: "failed", Text: result.ErrorMessage}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	for _, testCase := range suite.TestCases {
+		suite.Tests++
+		switch {
+		case testCase.Failure != nil:
+			suite.Failures++
+		case testCase.Error != nil:
+			suite.Errors++
+		case testCase.Skipped != nil:
+			suite.Skipped++
+		}
+	}
+	return suite
+}
+
+// newJUnitFailure describes every failed detail; the first one becomes the failure message
+func newJUnitFailure(details []models.ValidationDetail) *junitFailure {
+	failure := &junitFailure{}
+	var text strings.Builder
+	for _, detail := range details {
+		if detail.IsPassed() {
+			continue
+		}
+		if failure.Message == "" {
+			failure.Message = detail.Message
+			failure.Type = detail.Type
+		}
+		text.WriteString(fmt.Sprintf("[%s] %s\n", detail.Type, detail.Message))
+		if detail.Expression != "" {
+			text.WriteString(fmt.Sprintf("  expression: %s\n", detail.Expression))
+		}
+		text.WriteString(fmt.Sprintf("  expected: %v\n  actual: %v\n", detail.Expected, detail.Actual))
+		if detail.FailureReason != "" {
+			text.WriteString(fmt.Sprintf("  reason: %s\n", detail.FailureReason))
+		}
+		if detail.SpanContext != nil {
+			text.WriteString(fmt.Sprintf("  span: %s (trace %s)\n", detail.SpanContext.SpanID, detail.SpanContext.TraceID))
+		}
+	}
+	if failure.Message == "" {
+		failure.Message = "operation failed"
+		failure.Type = "failed"
+	}
+	failure.Text = text.String()
+	return failure
+}
+
+// junitSeconds formats a nanosecond duration as JUnit seconds
+func junitSeconds(nanos int64) string {
+	return fmt.Sprintf("%.3f", time.Duration(nanos).Seconds())
+}
+
+// writeJUnitReport renders the report as JUnit XML and writes it to path
+func (r *DefaultReportRenderer) writeJUnitReport(report *models.AlignmentReport, path string) error {
+	data, err := r.RenderJUnit(report)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		return fmt.Errorf("failed to write JUnit report %s: %w", path, err)
+	}
+	return nil
+}
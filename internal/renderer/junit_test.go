@@ -0,0 +1,139 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createGranularReport builds a report with a YAML spec (per-operation results) and a legacy spec
+func createGranularReport() *models.AlignmentReport {
+	yamlResult := models.NewAlignmentResult("user-service-v1")
+	yamlResult.Service = "user-service"
+	yamlResult.SourceFile = "contracts/service-spec.yaml"
+	yamlResult.LineNumber = 1
+	yamlResult.Status = models.StatusFailed
+	yamlResult.OperationResults = map[string]*models.OperationResult{
+		"GET /users": {
+			Status:     models.StatusFailed,
+			SourceFile: "contracts/service-spec.yaml",
+			LineNumber: 12,
+			Details: []models.ValidationDetail{{
+				Type: "status_code", Expected: 200, Actual: 500,
+				Message:     "Status code 500 does not match any expected values",
+				SpanContext: &models.Span{SpanID: "span-1", TraceID: "trace-1"},
+			}},
+		},
+		"POST /users":   {Status: models.StatusSuccess, SourceFile: "contracts/service-spec.yaml", LineNumber: 20},
+		"DELETE /users": {Status: models.StatusSkipped, SourceFile: "contracts/service-spec.yaml", LineNumber: 28},
+	}
+
+	legacyResult := models.NewAlignmentResult("createUser")
+	legacyResult.SourceFile = "src/UserController.java"
+	legacyResult.LineNumber = 42
+	legacyResult.AddValidationDetail(models.ValidationDetail{Type: "precondition", Expression: `{"!=": [{"var": "name"}, null]}`, Expected: true, Actual: true, Message: "Precondition passed"})
+	legacyResult.AddValidationDetail(models.ValidationDetail{Type: "postcondition", Expression: `{"==": [{"var": "status"}, 201]}`, Expected: true, Actual: false, Message: "Postcondition failed"})
+
+	report := models.NewAlignmentReport()
+	report.AddResult(*yamlResult)
+	report.AddResult(*legacyResult)
+	return report
+}
+
+func TestRenderJUnit(t *testing.T) {
+	output, err := NewReportRenderer().RenderJUnit(createGranularReport())
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(output, xml.Header))
+
+	var root junitTestSuites
+	require.NoError(t, xml.Unmarshal([]byte(output), &root))
+	assert.Equal(t, 5, root.Tests)
+	assert.Equal(t, 2, root.Failures)
+	assert.Equal(t, 1, root.Skipped)
+	require.Len(t, root.Suites, 2)
+
+	operations := root.Suites[0]
+	require.Len(t, operations.TestCases, 3, "one test case per operation")
+	assert.Equal(t, "DELETE /users", operations.TestCases[0].Name)
+	assert.NotNil(t, operations.TestCases[0].Skipped)
+	getUsers := operations.TestCases[1]
+	assert.Equal(t, "GET /users", getUsers.Name)
+	assert.Equal(t, "user-service", getUsers.ClassName)
+	assert.Equal(t, 12, getUsers.Line)
+	require.NotNil(t, getUsers.Failure)
+	assert.Equal(t, "Status code 500 does not match any expected values", getUsers.Failure.Message)
+	assert.Equal(t, "status_code", getUsers.Failure.Type)
+	assert.Contains(t, getUsers.Failure.Text, "span: span-1 (trace trace-1)")
+	assert.Nil(t, operations.TestCases[2].Failure)
+
+	assertions := root.Suites[1]
+	require.Len(t, assertions.TestCases, 2, "one test case per assertion")
+	assert.Nil(t, assertions.TestCases[0].Failure)
+	assert.Equal(t, `postcondition #2: {"==": [{"var": "status"}, 201]}`, assertions.TestCases[1].Name)
+	require.NotNil(t, assertions.TestCases[1].Failure)
+	assert.Equal(t, "Postcondition failed", assertions.TestCases[1].Failure.Message)
+
+	_, err = NewReportRenderer().RenderJUnit(nil)
+	assert.Error(t, err)
+}
+
+func TestRenderCodeQuality(t *testing.T) {
+	renderer := NewReportRenderer()
+	output, err := renderer.RenderCodeQuality(createGranularReport())
+	require.NoError(t, err)
+
+	var issues []codeQualityIssue
+	require.NoError(t, json.Unmarshal([]byte(output), &issues))
+	require.Len(t, issues, 2)
+
+	assert.Equal(t, "flowspec/status_code", issues[0].CheckName)
+	assert.Equal(t, "GET /users: Status code 500 does not match any expected values", issues[0].Description)
+	assert.Equal(t, "major", issues[0].Severity)
+	assert.Equal(t, "contracts/service-spec.yaml", issues[0].Location.Path)
+	assert.Equal(t, 12, issues[0].Location.Lines.Begin)
+	assert.Equal(t, "src/UserController.java", issues[1].Location.Path)
+	assert.NotEqual(t, issues[0].Fingerprint, issues[1].Fingerprint)
+
+	again, err := renderer.RenderCodeQuality(createGranularReport())
+	require.NoError(t, err)
+	assert.Equal(t, output, again, "fingerprints are stable across runs")
+}
+
+func TestWriteArtifacts_GitLabReports(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess})
+
+	dir := t.TempDir()
+	config := DefaultRendererConfig()
+	config.JUnitReportPath = filepath.Join(dir, "flowspec-report.xml")
+	config.CodeQualityReportPath = filepath.Join(dir, "gl-code-quality-report.json")
+	require.NoError(t, NewReportRendererWithConfig(config).WriteArtifacts(report))
+
+	junit, err := os.ReadFile(config.JUnitReportPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(junit), `<testsuites name="flowspec" tests="1" failures="0"`)
+
+	codeQuality, err := os.ReadFile(config.CodeQualityReportPath)
+	require.NoError(t, err)
+	assert.Equal(t, "[]", string(codeQuality))
+}
@@ -0,0 +1,131 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// maxMarkdownFailures limits the failures listed in a Markdown summary to keep comments readable
+const maxMarkdownFailures = 20
+
+// RenderMarkdown renders a compact Markdown summary of the report, suitable for PR comments
+func (r *DefaultReportRenderer) RenderMarkdown(report *models.AlignmentReport) (string, error) {
+	if report == nil {
+		return "", fmt.Errorf("report cannot be nil")
+	}
+
+	var output strings.Builder
+	icon := IconSuccess
+	if r.policyExitCode(report) != ExitSuccess {
+		icon = IconFailed
+	}
+	output.WriteString(fmt.Sprintf("## %s %s\n\n", icon, r.localizer.T("report.title")))
+
+	summary := report.Summary
+	output.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+		r.localizer.T("summary.total", summary.Total),
+		r.localizer.T("summary.success", summary.Success),
+		r.localizer.T("summary.failed", summary.Failed),
+		r.localizer.T("summary.skipped", summary.Skipped)))
+	output.WriteString("|---|---|---|---|\n\n")
+
+	if report.Coverage != nil {
+		output.WriteString(r.localizer.T("summary.coverage",
+			report.Coverage.CoveredOperations, report.Coverage.TotalOperations, report.Coverage.Ratio*100))
+		output.WriteString("\n\n")
+	}
+
+	failures := markdownFailures(report)
+	if len(failures) > 0 {
+		output.WriteString(fmt.Sprintf("### %s\n\n", r.localizer.T("results.failed", len(failures))))
+		for i, failure := range failures {
+			if i == maxMarkdownFailures {
+				output.WriteString(fmt.Sprintf("- %s\n", r.localizer.T("markdown.more_failures", len(failures)-maxMarkdownFailures)))
+				break
+			}
+			output.WriteString(fmt.Sprintf("- **%s**: %s", escapeMarkdown(failure.key), escapeMarkdown(failure.message)))
+			if link := r.traceLink(failure.span); link != "" {
+				output.WriteString(fmt.Sprintf(" ([%s](%s))", r.localizer.T("trace.link"), link))
+			}
+			output.WriteString("\n")
+		}
+		output.WriteString("\n")
+	}
+
+	warnings := 0
+	for _, result := range report.Results {
+		warnings += len(result.Warnings)
+	}
+	if warnings > 0 {
+		output.WriteString(fmt.Sprintf("⚠️ %s\n", r.localizer.T("markdown.warnings", warnings)))
+	}
+
+	return output.String(), nil
+}
+
+// markdownFailure is a failed spec or operation with its first failed detail
+type markdownFailure struct {
+	key     string
+	message string
+	span    *models.Span
+}
+
+// markdownFailures lists failed specs or operations with their first failure message
+func markdownFailures(report *models.AlignmentReport) []markdownFailure {
+	var failures []markdownFailure
+	for _, result := range report.Results {
+		if result.Status != models.StatusFailed && result.Status != models.StatusTimeout {
+			continue
+		}
+
+		if len(result.OperationResults) == 0 {
+			failures = append(failures, newMarkdownFailure(result.SpecOperationID, result.Details, result.ErrorMessage))
+			continue
+		}
+
+		keys := make([]string, 0, len(result.OperationResults))
+		for key, operation := range result.OperationResults {
+			if operation.Status == models.StatusFailed {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			failures = append(failures, newMarkdownFailure(key, result.OperationResults[key].Details, ""))
+		}
+	}
+	return failures
+}
+
+// newMarkdownFailure describes a failure by its first failed detail, or fallback
+func newMarkdownFailure(key string, details []models.ValidationDetail, fallback string) markdownFailure {
+	for _, detail := range details {
+		if !detail.IsPassed() {
+			return markdownFailure{key: key, message: detail.Message, span: detail.SpanContext}
+		}
+	}
+	return markdownFailure{key: key, message: fallback}
+}
+
+// escapeMarkdown neutralizes characters that would change Markdown rendering
+func escapeMarkdown(text string) string {
+	replacer := strings.NewReplacer("\n", " ", "|", "\\|", "*", "\\*", "_", "\\_", "`", "\\`", "<", "&lt;", ">", "&gt;")
+	return replacer.Replace(text)
+}
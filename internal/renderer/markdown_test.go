@@ -0,0 +1,66 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess, models.StatusFailed})
+	report.Results[0].Warnings = []string{"Deprecated operation GET /legacy still received 1 request(s)"}
+	report.Results[1].OperationResults = map[string]*models.OperationResult{
+		"GET /users/{id}": {
+			Status:  models.StatusFailed,
+			Details: []models.ValidationDetail{{Type: "status_code", Expected: 200, Actual: 500, Message: "status 500 | not in *2xx*"}},
+		},
+		"GET /users": {Status: models.StatusSuccess},
+	}
+
+	renderer := NewReportRendererWithLanguage("en")
+	summary, err := renderer.RenderMarkdown(report)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(summary, "## ❌ FlowSpec Validation Report\n"))
+	assert.Contains(t, summary, "| Total: 2 ServiceSpecs | Success: 1 | Failed: 1 | Skipped: 0 |")
+	assert.Contains(t, summary, "### ❌ Failed Validations (1)")
+	assert.Contains(t, summary, `- **GET /users/{id}**: status 500 \| not in \*2xx\*`)
+	assert.NotContains(t, summary, "**GET /users**")
+	assert.Contains(t, summary, "1 warning(s)")
+
+	_, err = renderer.RenderMarkdown(nil)
+	assert.Error(t, err)
+}
+
+func TestRenderMarkdown_Truncates(t *testing.T) {
+	statuses := make([]models.AlignmentStatus, maxMarkdownFailures+5)
+	for i := range statuses {
+		statuses[i] = models.StatusFailed
+	}
+	report := createTestReport(t, statuses)
+
+	summary, err := NewReportRendererWithLanguage("en").RenderMarkdown(report)
+	require.NoError(t, err)
+
+	assert.Contains(t, summary, fmt.Sprintf("operation-%d", maxMarkdownFailures))
+	assert.NotContains(t, summary, fmt.Sprintf("operation-%d*", maxMarkdownFailures+1))
+	assert.Contains(t, summary, "…and 5 more failure(s)")
+}
@@ -0,0 +1,122 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// NDJSON record types
+const (
+	NDJSONTypeResult  = "result"
+	NDJSONTypeSummary = "summary"
+)
+
+// ndjsonResult is one streamed spec result
+type ndjsonResult struct {
+	Type string `json:"type"`
+	models.AlignmentResult
+}
+
+// ndjsonSummary is the final record, written once all results are streamed
+type ndjsonSummary struct {
+	Type          string                      `json:"type"`
+	Summary       models.AlignmentSummary     `json:"summary"`
+	Coverage      *models.CoverageReport      `json:"coverage,omitempty"`
+	Unmatched     *models.UnmatchedSpanReport `json:"unmatched,omitempty"`
+	Services      []models.ServiceSummary     `json:"services,omitempty"`
+	Tags          []models.TagSummary         `json:"tags,omitempty"`
+	Traces        []models.TraceSummary       `json:"traces,omitempty"`
+	ExecutionTime int64                       `json:"executionTime"`
+	StartTime     int64                       `json:"startTime"`
+	EndTime       int64                       `json:"endTime"`
+	ExitCode      int                         `json:"exitCode"`
+}
+
+// NDJSONWriter streams results as newline-delimited JSON, one object per line. Every spec result
+// is written as a "result" record when it completes, followed by a single "summary" record.
+type NDJSONWriter struct {
+	mu       sync.Mutex
+	encoder  *json.Encoder
+	renderer *DefaultReportRenderer
+}
+
+// NewNDJSONWriter creates a writer that streams records to w. The renderer decides the exit code
+// reported in the summary record.
+func NewNDJSONWriter(w io.Writer, renderer *DefaultReportRenderer) *NDJSONWriter {
+	if renderer == nil {
+		renderer = NewReportRenderer()
+	}
+	return &NDJSONWriter{encoder: json.NewEncoder(w), renderer: renderer}
+}
+
+// WriteResult writes a single spec result record
+func (w *NDJSONWriter) WriteResult(result models.AlignmentResult) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.encoder.Encode(ndjsonResult{Type: NDJSONTypeResult, AlignmentResult: result}); err != nil {
+		return fmt.Errorf("failed to write NDJSON result: %w", err)
+	}
+	return nil
+}
+
+// WriteSummary writes the final summary record of the report
+func (w *NDJSONWriter) WriteSummary(report *models.AlignmentReport) error {
+	if report == nil {
+		return fmt.Errorf("report cannot be nil")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	summary := ndjsonSummary{
+		Type:          NDJSONTypeSummary,
+		Summary:       report.Summary,
+		Coverage:      report.Coverage,
+		Unmatched:     report.Unmatched,
+		Services:      report.Services,
+		Tags:          report.Tags,
+		Traces:        report.Traces,
+		ExecutionTime: report.ExecutionTime,
+		StartTime:     report.StartTime,
+		EndTime:       report.EndTime,
+		ExitCode:      w.renderer.GetExitCode(report),
+	}
+	if err := w.encoder.Encode(summary); err != nil {
+		return fmt.Errorf("failed to write NDJSON summary: %w", err)
+	}
+	return nil
+}
+
+// RenderNDJSON renders a complete report as NDJSON, for callers that already hold the whole report
+func (r *DefaultReportRenderer) RenderNDJSON(report *models.AlignmentReport, w io.Writer) error {
+	if report == nil {
+		return fmt.Errorf("report cannot be nil")
+	}
+
+	writer := NewNDJSONWriter(w, r)
+	for _, result := range report.Results {
+		if err := writer.WriteResult(result); err != nil {
+			return err
+		}
+	}
+	return writer.WriteSummary(report)
+}
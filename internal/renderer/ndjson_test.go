@@ -0,0 +1,71 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNDJSONWriter(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess, models.StatusFailed})
+
+	var output bytes.Buffer
+	writer := NewNDJSONWriter(&output, NewReportRenderer())
+
+	// A result is visible to the reader as soon as it is written
+	require.NoError(t, writer.WriteResult(report.Results[0]))
+	assert.Equal(t, 1, strings.Count(output.String(), "\n"))
+
+	require.NoError(t, writer.WriteResult(report.Results[1]))
+	require.NoError(t, writer.WriteSummary(report))
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	require.Len(t, lines, 3)
+
+	var first map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, NDJSONTypeResult, first["type"])
+	assert.Equal(t, "operation-1", first["specOperationId"])
+	assert.Equal(t, "SUCCESS", first["status"])
+
+	var summary map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &summary))
+	assert.Equal(t, NDJSONTypeSummary, summary["type"])
+	assert.Equal(t, float64(ExitValidationFailed), summary["exitCode"])
+	assert.Equal(t, float64(1), summary["summary"].(map[string]interface{})["failed"])
+
+	assert.Error(t, writer.WriteSummary(nil))
+}
+
+func TestRenderNDJSON(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess, models.StatusSkipped, models.StatusFailed})
+
+	var output bytes.Buffer
+	require.NoError(t, NewReportRenderer().RenderNDJSON(report, &output))
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	require.Len(t, lines, 4)
+	for _, line := range lines {
+		assert.True(t, json.Valid([]byte(line)))
+	}
+	assert.Contains(t, lines[3], `"type":"summary"`)
+}
@@ -0,0 +1,109 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// prometheusLabelEscaper escapes label values as required by the Prometheus text format
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// RenderPrometheus renders verification metrics in the Prometheus text exposition format
+func (r *DefaultReportRenderer) RenderPrometheus(report *models.AlignmentReport) (string, error) {
+	if report == nil {
+		return "", fmt.Errorf("report cannot be nil")
+	}
+
+	var output strings.Builder
+	metric := func(name, metricType, help string, samples ...string) {
+		output.WriteString(fmt.Sprintf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType))
+		for _, sample := range samples {
+			output.WriteString(sample + "\n")
+		}
+	}
+
+	summary := report.Summary
+	metric("flowspec_checks_total", "counter", "Number of ServiceSpecs verified.",
+		fmt.Sprintf("flowspec_checks_total %d", summary.Total))
+	metric("flowspec_failures_total", "counter", "Number of ServiceSpecs that failed verification.",
+		fmt.Sprintf("flowspec_failures_total %d", summary.Failed))
+	metric("flowspec_skipped_total", "counter", "Number of ServiceSpecs skipped for lack of matching spans.",
+		fmt.Sprintf("flowspec_skipped_total %d", summary.Skipped))
+	metric("flowspec_assertions_total", "counter", "Number of assertions evaluated.",
+		fmt.Sprintf("flowspec_assertions_total %d", summary.TotalAssertions))
+	metric("flowspec_assertion_failures_total", "counter", "Number of assertions that failed.",
+		fmt.Sprintf("flowspec_assertion_failures_total %d", summary.FailedAssertions))
+	metric("flowspec_duration_seconds", "gauge", "Duration of the verification run.",
+		fmt.Sprintf("flowspec_duration_seconds %g", time.Duration(report.ExecutionTime).Seconds()))
+
+	passed := 0
+	if r.policyExitCode(report) == ExitSuccess {
+		passed = 1
+	}
+	metric("flowspec_verification_passed", "gauge", "Whether the verification run passed (1) or failed (0).",
+		fmt.Sprintf("flowspec_verification_passed %d", passed))
+
+	if report.Coverage != nil {
+		metric("flowspec_coverage_ratio", "gauge", "Ratio of contract operations exercised by the trace.",
+			fmt.Sprintf("flowspec_coverage_ratio %g", report.Coverage.Ratio))
+	}
+
+	var failures, samples []string
+	for _, result := range report.Results {
+		service := result.Service
+		if service == "" {
+			service = result.SpecOperationID
+		}
+		keys := make([]string, 0, len(result.OperationResults))
+		for key := range result.OperationResults {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			operation := result.OperationResults[key]
+			labels := fmt.Sprintf(`service="%s",method="%s",path="%s"`,
+				prometheusLabelEscaper.Replace(service),
+				prometheusLabelEscaper.Replace(operation.Method),
+				prometheusLabelEscaper.Replace(operation.Path))
+			failures = append(failures, fmt.Sprintf("flowspec_endpoint_failures_total{%s} %d", labels, operation.AssertionsFailed))
+			samples = append(samples, fmt.Sprintf("flowspec_endpoint_samples_total{%s} %d", labels, operation.SampleCount))
+		}
+	}
+	if len(failures) > 0 {
+		metric("flowspec_endpoint_failures_total", "counter", "Number of failed assertions per contract operation.", failures...)
+		metric("flowspec_endpoint_samples_total", "counter", "Number of spans matched per contract operation.", samples...)
+	}
+
+	return output.String(), nil
+}
+
+// writePrometheusReport renders the metrics and writes them to path
+func (r *DefaultReportRenderer) writePrometheusReport(report *models.AlignmentReport, path string) error {
+	data, err := r.RenderPrometheus(report)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		return fmt.Errorf("failed to write Prometheus metrics %s: %w", path, err)
+	}
+	return nil
+}
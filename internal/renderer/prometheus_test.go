@@ -0,0 +1,63 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderPrometheus(t *testing.T) {
+	report := createGranularReport()
+	report.ExecutionTime = int64(1500 * time.Millisecond)
+	report.Coverage = &models.CoverageReport{TotalOperations: 3, CoveredOperations: 2, Ratio: 2.0 / 3}
+	report.Results[0].OperationResults["GET /users"].Path = "/users"
+	report.Results[0].OperationResults["GET /users"].Method = "GET"
+	report.Results[0].OperationResults["GET /users"].AssertionsFailed = 3
+	report.Results[0].OperationResults["GET /users"].SampleCount = 4
+
+	metrics, err := NewReportRenderer().RenderPrometheus(report)
+	require.NoError(t, err)
+
+	assert.Contains(t, metrics, "# TYPE flowspec_checks_total counter\nflowspec_checks_total 2\n")
+	assert.Contains(t, metrics, "flowspec_failures_total 2\n")
+	assert.Contains(t, metrics, "flowspec_duration_seconds 1.5\n")
+	assert.Contains(t, metrics, "flowspec_coverage_ratio 0.6666666666666666\n")
+	assert.Contains(t, metrics, "flowspec_verification_passed 0\n")
+	assert.Contains(t, metrics, `flowspec_endpoint_failures_total{service="user-service",method="GET",path="/users"} 3`)
+	assert.Contains(t, metrics, `flowspec_endpoint_samples_total{service="user-service",method="GET",path="/users"} 4`)
+
+	_, err = NewReportRenderer().RenderPrometheus(nil)
+	assert.Error(t, err)
+}
+
+func TestWriteArtifacts_PrometheusReport(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess})
+
+	config := DefaultRendererConfig()
+	config.PrometheusReportPath = filepath.Join(t.TempDir(), "flowspec.prom")
+	require.NoError(t, NewReportRendererWithConfig(config).WriteArtifacts(report))
+
+	data, err := os.ReadFile(config.PrometheusReportPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "flowspec_verification_passed 1\n")
+	assert.NotContains(t, string(data), "flowspec_coverage_ratio")
+}
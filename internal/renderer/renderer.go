@@ -17,9 +17,12 @@ package renderer
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/flowspec/flowspec-cli/internal/concurrency"
 	"github.com/flowspec/flowspec-cli/internal/i18n"
 	"github.com/flowspec/flowspec-cli/internal/models"
 )
@@ -29,18 +32,26 @@ const (
 	IconSuccess = "✅"
 	IconFailed  = "❌"
 	IconSkipped = "⏭️"
+	IconTimeout = "⏱️"
+	IconFlaky   = "⚠️"
+	IconWarning = "⚠️"
+	IconInfo    = "ℹ️"
 )
 
 // Exit code constants
 const (
-	ExitSuccess          = 0  // Success
-	ExitValidationFailed = 1  // Validation failed
-	ExitSpecFormatError  = 2  // Contract format error
-	ExitParseError       = 3  // Parse error
-	ExitSystemError      = 4  // System error
-	ExitUsageError       = 64 // Usage error
+	ExitSuccess          = 0   // Success
+	ExitValidationFailed = 1   // Validation failed
+	ExitSpecFormatError  = 2   // Contract format error
+	ExitParseError       = 3   // Parse error
+	ExitSystemError      = 4   // System error
+	ExitUsageError       = 64  // Usage error
+	ExitInterrupted      = 130 // Interrupted by SIGINT/SIGTERM, partial results written
 )
 
+// maxListedTraceIssues bounds the structural trace issues listed in human output
+const maxListedTraceIssues = 10
+
 // ReportRenderer defines the interface for rendering alignment reports
 type ReportRenderer interface {
 	RenderHuman(report *models.AlignmentReport) (string, error)
@@ -59,10 +70,22 @@ type DefaultReportRenderer struct {
 
 // RendererConfig holds configuration for the report renderer
 type RendererConfig struct {
-	ShowTimestamps     bool
-	ShowPerformance    bool
-	ShowDetailedErrors bool
-	ColorOutput        bool
+	ShowTimestamps        bool
+	ShowPerformance       bool
+	ShowDetailedErrors    bool
+	ColorOutput           bool
+	MinCoverage           float64     // Minimum operation coverage ratio (0.0 to 1.0); 0 disables the gate
+	HTMLReportPath        string      // Write a self-contained HTML report to this path; empty disables it
+	SARIFReportPath       string      // Write failures as SARIF 2.1.0 to this path; empty disables it
+	JUnitReportPath       string      // Write per-operation JUnit XML to this path; empty disables it
+	CodeQualityReportPath string      // Write failures as a GitLab Code Quality report to this path; empty disables it
+	CSVReportPath         string      // Write one row per validation detail as CSV to this path; empty disables it
+	BadgeDir              string      // Write an SVG badge and a shields.io endpoint file into this directory; empty disables it
+	PrometheusReportPath  string      // Write verification metrics in Prometheus text format to this path; empty disables it
+	SummaryReportPath     string      // Write the Summary of the run as JSON to this path; empty disables it
+	Concurrency           int         // Artifacts written at once; GOMAXPROCS when zero
+	TraceUIURLTemplate    string      // Link failures to a trace UI, e.g. https://jaeger/trace/{traceId}; empty disables links
+	ExitPolicy            *ExitPolicy // Outcomes that fail the run; nil applies DefaultExitPolicy
 }
 
 // DefaultRendererConfig returns a default renderer configuration
@@ -167,35 +190,92 @@ func (r *DefaultReportRenderer) RenderHuman(report *models.AlignmentReport) (str
 			r.getColor("dim"), r.localizer.T("summary.skipped", 0), r.getColor("reset")))
 	}
 
+	if report.Incomplete {
+		output.WriteString(fmt.Sprintf("  %s⚠️ %s%s\n",
+			r.getColor("yellow"), r.localizer.T("summary.incomplete", report.IncompleteReason), r.getColor("reset")))
+	}
+
+	// Operations and specs left unaligned after a critical failure
+	if stop := report.FailFast; stop != nil {
+		location := stop.Spec
+		if stop.Operation != "" {
+			location = stop.Operation
+		}
+		output.WriteString(fmt.Sprintf("  %s⛔ %s%s\n",
+			r.getColor("red"), r.localizer.T("summary.fail_fast", location, stop.NotAligned), r.getColor("reset")))
+	}
+
+	// Contract coverage
+	if report.Coverage != nil {
+		r.renderCoverageHuman(&output, report.Coverage, report.Sampling)
+	}
+
+	// Per-service breakdown when several services were verified
+	if len(report.Services) > 0 {
+		r.renderServicesHuman(&output, report.Services)
+	}
+
+	// Per-tag breakdown when contract operations are tagged
+	if len(report.Tags) > 0 {
+		r.renderTagsHuman(&output, report.Tags)
+	}
+
+	// Per-trace breakdown when several traces were verified
+	if len(report.Traces) > 0 {
+		r.renderTracesHuman(&output, report.Traces)
+	}
+
+	// Spans moved to correct clock skew between hosts
+	if report.ClockSkew != nil {
+		output.WriteString(fmt.Sprintf("  %s🕑 %s%s\n", r.getColor("dim"),
+			r.localizer.T("clock_skew.corrected", report.ClockSkew.Spans, time.Duration(report.ClockSkew.MaxShift)), r.getColor("reset")))
+	}
+
+	// Matched spans that had the ID of another matched span and were evaluated once
+	if report.Summary.DuplicateSpans > 0 {
+		output.WriteString(fmt.Sprintf("  %s♊ %s%s\n", r.getColor("dim"),
+			r.localizer.T("duplicate_spans.dropped", report.Summary.DuplicateSpans), r.getColor("reset")))
+	}
+
+	// Spans that matched several operations of a spec
+	if report.Summary.AmbiguousSpans > 0 {
+		r.renderAmbiguitiesHuman(&output, report)
+	}
+
+	// Structural issues of the trace, which can make the results below misleading
+	if report.Completeness != nil {
+		r.renderCompletenessHuman(&output, report.Completeness)
+	}
+
 	// Performance metrics with enhanced formatting
 	if r.config.ShowPerformance && report.PerformanceInfo.SpecsProcessed > 0 {
 		output.WriteString("\n")
-		r.writeColoredSubsection(&output, "⚡ 性能指标")
-		output.WriteString(fmt.Sprintf("  处理速度: %s%.2f%s specs/秒\n",
-			r.getColor("cyan"), report.PerformanceInfo.ProcessingRate, r.getColor("reset")))
-		output.WriteString(fmt.Sprintf("  内存使用: %s%.2f%s MB\n",
-			r.getColor("cyan"), report.PerformanceInfo.MemoryUsageMB, r.getColor("reset")))
+		r.writeColoredSubsection(&output, r.localizer.T("report.performance"))
+		output.WriteString(fmt.Sprintf("  %s%s%s\n",
+			r.getColor("cyan"), r.localizer.T("performance.processing_rate", report.PerformanceInfo.ProcessingRate), r.getColor("reset")))
+		output.WriteString(fmt.Sprintf("  %s%s%s\n",
+			r.getColor("cyan"), r.localizer.T("performance.memory_usage", report.PerformanceInfo.MemoryUsageMB), r.getColor("reset")))
 		if report.PerformanceInfo.ConcurrentWorkers > 0 {
-			output.WriteString(fmt.Sprintf("  并发工作线程: %s%d%s 个\n",
-				r.getColor("cyan"), report.PerformanceInfo.ConcurrentWorkers, r.getColor("reset")))
+			output.WriteString(fmt.Sprintf("  %s%s%s\n",
+				r.getColor("cyan"), r.localizer.T("performance.concurrent_workers", report.PerformanceInfo.ConcurrentWorkers), r.getColor("reset")))
 		}
 		if report.Summary.TotalAssertions > 0 {
-			output.WriteString(fmt.Sprintf("  断言评估: %s%d%s 个\n",
-				r.getColor("cyan"), report.Summary.TotalAssertions, r.getColor("reset")))
+			output.WriteString(fmt.Sprintf("  %s%s%s\n",
+				r.getColor("cyan"), r.localizer.T("performance.assertions", report.Summary.TotalAssertions), r.getColor("reset")))
 		}
 	}
 
 	// Execution time with enhanced formatting
 	if r.config.ShowTimestamps {
 		executionTime := time.Duration(report.ExecutionTime)
-		output.WriteString(fmt.Sprintf("  ⏱️  执行时间: %s%v%s\n",
-			r.getColor("magenta"), executionTime, r.getColor("reset")))
+		output.WriteString(fmt.Sprintf("  ⏱️  %s%s%s\n",
+			r.getColor("magenta"), r.localizer.T("performance.execution_time", executionTime), r.getColor("reset")))
 
 		// Show average time per spec if meaningful
 		if report.Summary.Total > 0 {
 			avgTime := time.Duration(report.Summary.AverageExecutionTime)
-			output.WriteString(fmt.Sprintf("  平均处理时间: %s%v%s/spec\n",
-				r.getColor("magenta"), avgTime, r.getColor("reset")))
+			output.WriteString(fmt.Sprintf("  %s%s%s\n",
+				r.getColor("magenta"), r.localizer.T("performance.average_time", avgTime), r.getColor("reset")))
 		}
 	}
 
@@ -212,7 +292,7 @@ func (r *DefaultReportRenderer) RenderHuman(report *models.AlignmentReport) (str
 		switch result.Status {
 		case models.StatusSuccess:
 			successResults = append(successResults, result)
-		case models.StatusFailed:
+		case models.StatusFailed, models.StatusTimeout:
 			failedResults = append(failedResults, result)
 		case models.StatusSkipped:
 			skippedResults = append(skippedResults, result)
@@ -221,7 +301,7 @@ func (r *DefaultReportRenderer) RenderHuman(report *models.AlignmentReport) (str
 
 	// Render failed results first (most important)
 	if len(failedResults) > 0 {
-		r.writeColoredSubsection(&output, fmt.Sprintf("❌ 失败的验证 (%d 个)", len(failedResults)))
+		r.writeColoredSubsection(&output, r.localizer.T("results.failed", len(failedResults)))
 		for i, result := range failedResults {
 			r.renderResultHuman(&output, result, i+1, len(failedResults))
 			if i < len(failedResults)-1 {
@@ -233,7 +313,7 @@ func (r *DefaultReportRenderer) RenderHuman(report *models.AlignmentReport) (str
 
 	// Render successful results
 	if len(successResults) > 0 {
-		r.writeColoredSubsection(&output, fmt.Sprintf("%s 成功的验证 (%d 个)", IconSuccess, len(successResults)))
+		r.writeColoredSubsection(&output, r.localizer.T("results.success", len(successResults)))
 		for i, result := range successResults {
 			r.renderResultHuman(&output, result, i+1, len(successResults))
 			if i < len(successResults)-1 {
@@ -245,7 +325,7 @@ func (r *DefaultReportRenderer) RenderHuman(report *models.AlignmentReport) (str
 
 	// Render skipped results last
 	if len(skippedResults) > 0 {
-		r.writeColoredSubsection(&output, fmt.Sprintf("⏭️  跳过的验证 (%d 个)", len(skippedResults)))
+		r.writeColoredSubsection(&output, r.localizer.T("results.skipped", len(skippedResults)))
 		for i, result := range skippedResults {
 			r.renderResultHuman(&output, result, i+1, len(skippedResults))
 			if i < len(skippedResults)-1 {
@@ -255,33 +335,218 @@ func (r *DefaultReportRenderer) RenderHuman(report *models.AlignmentReport) (str
 		output.WriteString("\n")
 	}
 
+	// Spans that matched no spec, when requested
+	if report.Unmatched != nil {
+		r.renderUnmatchedHuman(&output, report.Unmatched)
+	}
+
 	// Final summary with enhanced styling
 	output.WriteString("==================================================\n")
 	if report.HasFailures() {
-		output.WriteString(fmt.Sprintf("%s验证结果: ❌ 失败%s (%s%d%s 个断言失败)\n",
-			r.getColor("red"), r.getColor("reset"),
-			r.getColor("bold"), report.Summary.FailedAssertions, r.getColor("reset")))
+		output.WriteString(fmt.Sprintf("%s%s%s\n",
+			r.getColor("red"), r.localizer.T("status.failed", report.Summary.FailedAssertions), r.getColor("reset")))
 
 		// Provide actionable summary for failures
 		if report.Summary.FailedAssertions > 0 {
-			output.WriteString(fmt.Sprintf("\n%s💡 建议:%s\n", r.getColor("yellow"), r.getColor("reset")))
-			output.WriteString("  • 检查失败的断言是否反映了实际的服务行为变化\n")
-			output.WriteString("  • 验证轨迹数据是否包含预期的 span 属性和状态\n")
-			output.WriteString("  • 考虑更新 ServiceSpec 规约以匹配新的服务行为\n")
+			output.WriteString(fmt.Sprintf("\n%s%s%s\n", r.getColor("yellow"), r.localizer.T("status.suggestions"), r.getColor("reset")))
+			for _, key := range []string{"status.suggestion.check_failed", "status.suggestion.verify_trace", "status.suggestion.update_specs"} {
+				output.WriteString("  " + r.localizer.T(key) + "\n")
+			}
 		}
+	} else if r.coverageBelowThreshold(report) {
+		output.WriteString(fmt.Sprintf("%s%s%s\n",
+			r.getColor("red"), r.localizer.T("coverage.below_threshold",
+				report.Coverage.Ratio*100, r.config.MinCoverage*100), r.getColor("reset")))
+	} else if r.exitPolicy().FailOnSkipped && report.Summary.Skipped > 0 {
+		output.WriteString(fmt.Sprintf("%s%s%s\n",
+			r.getColor("red"), r.localizer.T("exit.skipped_not_allowed", report.Summary.Skipped), r.getColor("reset")))
+	} else if r.exitPolicy().FailOnWarnings && report.HasWarnings() {
+		output.WriteString(fmt.Sprintf("%s%s%s\n",
+			r.getColor("red"), r.localizer.T("exit.warnings_not_allowed", report.Summary.Warnings), r.getColor("reset")))
 	} else {
-		output.WriteString(fmt.Sprintf("%s验证结果: %s 成功%s (所有断言通过)\n",
-			r.getColor("green"), IconSuccess, r.getColor("reset")))
+		output.WriteString(fmt.Sprintf("%s%s%s\n",
+			r.getColor("green"), r.localizer.T("status.success"), r.getColor("reset")))
 
 		if report.Summary.Total > 0 {
-			output.WriteString(fmt.Sprintf("\n%s🎉 恭喜！%s 所有 %d 个 ServiceSpec 都符合预期规约。\n",
-				r.getColor("green"), r.getColor("reset"), report.Summary.Total))
+			output.WriteString(fmt.Sprintf("\n%s%s%s\n",
+				r.getColor("green"), r.localizer.T("status.congratulations", report.Summary.Total), r.getColor("reset")))
 		}
 	}
 
+	// Problems that the exit policy lets through are called out, so a green exit is not misread
+	failing := report.HasFailures() || r.coverageBelowThreshold(report) ||
+		(r.exitPolicy().FailOnSkipped && report.Summary.Skipped > 0) ||
+		(r.exitPolicy().FailOnWarnings && report.HasWarnings())
+	if failing && r.GetExitCode(report) == ExitSuccess {
+		output.WriteString(fmt.Sprintf("\n%s⚠️  %s%s\n", r.getColor("yellow"), r.localizer.T("exit.not_enforced"), r.getColor("reset")))
+	}
+
 	return output.String(), nil
 }
 
+// renderCoverageHuman renders the contract coverage section with per-operation sample counts,
+// extrapolated to the requests they stand for when the traces were sampled
+func (r *DefaultReportRenderer) renderCoverageHuman(output *strings.Builder, coverage *models.CoverageReport, sampling *models.Sampling) {
+	coverageColor := r.getColor("green")
+	if coverage.CoveredOperations < coverage.TotalOperations {
+		coverageColor = r.getColor("yellow")
+	}
+	if r.config.MinCoverage > 0 && !coverage.MeetsThreshold(r.config.MinCoverage) {
+		coverageColor = r.getColor("red")
+	}
+
+	output.WriteString(fmt.Sprintf("  %s📈 %s%s\n", coverageColor,
+		r.localizer.T("summary.coverage", coverage.CoveredOperations, coverage.TotalOperations, coverage.Ratio*100),
+		r.getColor("reset")))
+	if sampling != nil {
+		output.WriteString(fmt.Sprintf("     %s🎲 %s%s\n", r.getColor("dim"),
+			r.localizer.T("sampling.note", sampling.Rate*100, r.localizer.T("sampling.source."+sampling.Source), sampling.Confidence*100),
+			r.getColor("reset")))
+	}
+
+	for _, operation := range coverage.Operations {
+		if operation.Covered {
+			samples := r.localizer.T("coverage.samples", operation.SampleCount)
+			if operation.EstimatedRequests > 0 {
+				samples += ", " + r.localizer.T("coverage.estimated", operation.EstimatedRequests)
+			}
+			output.WriteString(fmt.Sprintf("     %s %s %s(%s)%s\n",
+				IconSuccess, operation.Operation,
+				r.getColor("dim"), samples, r.getColor("reset")))
+		} else {
+			notExercised := r.localizer.T("coverage.not_exercised")
+			if operation.UnseenRequests > 0 {
+				notExercised += ", " + r.localizer.T("coverage.sampled_out", operation.UnseenRequests)
+			}
+			output.WriteString(fmt.Sprintf("     %s⚪ %s (%s)%s\n",
+				r.getColor("yellow"), operation.Operation, notExercised, r.getColor("reset")))
+		}
+	}
+}
+
+// renderServicesHuman renders the per-service result breakdown
+func (r *DefaultReportRenderer) renderServicesHuman(output *strings.Builder, services []models.ServiceSummary) {
+	output.WriteString(fmt.Sprintf("  %s\n", r.localizer.T("services.title", len(services))))
+	for _, service := range services {
+		icon, color := IconSuccess, r.getColor("green")
+		if service.Failed > 0 {
+			icon, color = IconFailed, r.getColor("red")
+		}
+		output.WriteString(fmt.Sprintf("     %s %s%s%s: %s\n", icon, color, service.Service, r.getColor("reset"),
+			r.localizer.T("services.line", service.Success, service.Total, service.Failed,
+				service.CoveredOperations, service.TotalOperations)))
+	}
+}
+
+// renderTagsHuman renders the per-tag breakdown of operations
+func (r *DefaultReportRenderer) renderTagsHuman(output *strings.Builder, tags []models.TagSummary) {
+	output.WriteString(fmt.Sprintf("  %s\n", r.localizer.T("tags.title", len(tags))))
+	for _, tag := range tags {
+		icon, color := IconSuccess, r.getColor("green")
+		if tag.FailedOperations > 0 {
+			icon, color = IconFailed, r.getColor("red")
+		}
+		output.WriteString(fmt.Sprintf("     %s %s%s%s: %s\n", icon, color, tag.Tag, r.getColor("reset"),
+			r.localizer.T("tags.line", tag.SuccessOperations, tag.TotalOperations, tag.FailedOperations,
+				tag.CoveredOperations, tag.TotalOperations)))
+	}
+}
+
+// renderTracesHuman renders the per-trace breakdown, listing only the traces that failed so
+// runs over hundreds of traces stay readable
+func (r *DefaultReportRenderer) renderTracesHuman(output *strings.Builder, traces []models.TraceSummary) {
+	failed := 0
+	for _, trace := range traces {
+		if trace.Failed > 0 || trace.Error != "" {
+			failed++
+		}
+	}
+	output.WriteString(fmt.Sprintf("  %s\n", r.localizer.T("traces.title", len(traces), failed)))
+	for _, trace := range traces {
+		switch {
+		case trace.Error != "":
+			output.WriteString(fmt.Sprintf("     %s %s%s%s: %s\n", IconFailed, r.getColor("red"), trace.TraceID, r.getColor("reset"),
+				r.localizer.T("traces.error", trace.Error)))
+		case trace.Failed > 0:
+			output.WriteString(fmt.Sprintf("     %s %s%s%s: %s\n", IconFailed, r.getColor("red"), trace.TraceID, r.getColor("reset"),
+				r.localizer.T("traces.line", trace.Success, trace.Total, trace.Failed, trace.Spans)))
+		}
+	}
+}
+
+// renderCompletenessHuman renders the structural issues of the trace, listing at most
+// maxListedTraceIssues of them
+func (r *DefaultReportRenderer) renderCompletenessHuman(output *strings.Builder, completeness *models.TraceCompleteness) {
+	output.WriteString(fmt.Sprintf("  %s⚠️ %s%s\n", r.getColor("yellow"),
+		r.localizer.T("completeness.title", len(completeness.Issues), completeness.Spans), r.getColor("reset")))
+	for i, issue := range completeness.Issues {
+		if i == maxListedTraceIssues {
+			output.WriteString(fmt.Sprintf("     %s%s%s\n", r.getColor("dim"),
+				r.localizer.T("completeness.more", len(completeness.Issues)-i), r.getColor("reset")))
+			break
+		}
+		output.WriteString(fmt.Sprintf("     %s[%s]%s %s\n", r.getColor("dim"), issue.Kind, r.getColor("reset"), issue.Message))
+	}
+	output.WriteString(fmt.Sprintf("     %s%s%s\n", r.getColor("dim"), r.localizer.T("completeness.hint"), r.getColor("reset")))
+}
+
+// renderAmbiguitiesHuman renders the spans that matched several operations of a spec, listing
+// at most maxListedTraceIssues of them
+func (r *DefaultReportRenderer) renderAmbiguitiesHuman(output *strings.Builder, report *models.AlignmentReport) {
+	output.WriteString(fmt.Sprintf("  %s⚠️ %s%s\n", r.getColor("yellow"),
+		r.localizer.T("ambiguity.title", report.Summary.AmbiguousSpans), r.getColor("reset")))
+	listed := 0
+	for _, result := range report.Results {
+		for _, ambiguity := range result.Ambiguities {
+			if listed == maxListedTraceIssues {
+				output.WriteString(fmt.Sprintf("     %s%s%s\n", r.getColor("dim"),
+					r.localizer.T("ambiguity.more", report.Summary.AmbiguousSpans-listed), r.getColor("reset")))
+				output.WriteString(fmt.Sprintf("     %s%s%s\n", r.getColor("dim"), r.localizer.T("ambiguity.hint"), r.getColor("reset")))
+				return
+			}
+			outcome := r.localizer.T("ambiguity.unresolved", ambiguity.Policy)
+			if ambiguity.ResolvedTo != "" {
+				outcome = r.localizer.T("ambiguity.resolved", ambiguity.ResolvedTo)
+			}
+			output.WriteString(fmt.Sprintf("     %s (%s): %s %s→ %s%s\n", ambiguity.SpanID, ambiguity.SpanName,
+				strings.Join(ambiguity.Operations, ", "), r.getColor("dim"), outcome, r.getColor("reset")))
+			listed++
+		}
+	}
+	output.WriteString(fmt.Sprintf("     %s%s%s\n", r.getColor("dim"), r.localizer.T("ambiguity.hint"), r.getColor("reset")))
+}
+
+// renderUnmatchedHuman renders grouped spans that matched no spec
+func (r *DefaultReportRenderer) renderUnmatchedHuman(output *strings.Builder, unmatched *models.UnmatchedSpanReport) {
+	r.writeColoredSubsection(output, r.localizer.T("unmatched.title", unmatched.TotalSpans))
+	if len(unmatched.Groups) == 0 {
+		output.WriteString(fmt.Sprintf("  %s%s%s\n\n", r.getColor("dim"), r.localizer.T("unmatched.none"), r.getColor("reset")))
+		return
+	}
+
+	for _, group := range unmatched.Groups {
+		label := group.Name
+		if group.Method != "" && group.Route != "" {
+			label = group.Method + " " + group.Route
+		}
+		if group.Status != "" {
+			label += " [" + group.Status + "]"
+		}
+		output.WriteString(fmt.Sprintf("  %s%4d×%s %s %s(%s)%s\n",
+			r.getColor("yellow"), group.Count, r.getColor("reset"), label,
+			r.getColor("dim"), strings.Join(group.SampleSpanIDs, ", "), r.getColor("reset")))
+	}
+	output.WriteString("\n")
+}
+
+// coverageBelowThreshold returns true if a minimum coverage is configured and the report does not meet it
+func (r *DefaultReportRenderer) coverageBelowThreshold(report *models.AlignmentReport) bool {
+	if r.config.MinCoverage <= 0 || report.Coverage == nil {
+		return false
+	}
+	return !report.Coverage.MeetsThreshold(r.config.MinCoverage)
+}
+
 // renderResultHuman renders a single alignment result in human format with enhanced styling
 func (r *DefaultReportRenderer) renderResultHuman(output *strings.Builder, result models.AlignmentResult, index, total int) {
 	// Status icon and operation ID with color coding
@@ -294,51 +559,163 @@ func (r *DefaultReportRenderer) renderResultHuman(output *strings.Builder, resul
 		r.getColor("bold"), result.SpecOperationID, r.getColor("reset"),
 		statusColor, result.Status, r.getColor("reset")))
 
+	if result.TraceID != "" {
+		output.WriteString(fmt.Sprintf("   🧵 %s%s%s\n",
+			r.getColor("dim"), r.localizer.T("result.trace", result.TraceID), r.getColor("reset")))
+	}
+
 	// Execution time with formatting
 	if r.config.ShowTimestamps {
 		executionTime := time.Duration(result.ExecutionTime)
-		output.WriteString(fmt.Sprintf("   ⏱️  执行时间: %s%v%s\n",
-			r.getColor("dim"), executionTime, r.getColor("reset")))
+		output.WriteString(fmt.Sprintf("   ⏱️  %s%s%s\n",
+			r.getColor("dim"), r.localizer.T("result.execution_time", executionTime), r.getColor("reset")))
 	}
 
 	// Matched spans with enhanced formatting
 	if len(result.MatchedSpans) > 0 {
-		output.WriteString(fmt.Sprintf("   🎯 匹配的 Span: %s%s%s\n",
-			r.getColor("cyan"), strings.Join(result.MatchedSpans, ", "), r.getColor("reset")))
+		output.WriteString(fmt.Sprintf("   🎯 %s%s%s\n",
+			r.getColor("cyan"), r.localizer.T("result.matched_span", strings.Join(result.MatchedSpans, ", ")), r.getColor("reset")))
 	} else if result.Status == models.StatusSkipped {
-		output.WriteString(fmt.Sprintf("   %s🔍 未找到匹配的 Span%s\n",
-			r.getColor("yellow"), r.getColor("reset")))
+		output.WriteString(fmt.Sprintf("   %s🔍 %s%s\n",
+			r.getColor("yellow"), r.localizer.T("result.no_matching_spans"), r.getColor("reset")))
 	}
 
 	// Assertion summary with color coding
 	if result.AssertionsTotal > 0 {
-		passedColor := r.getColor("green")
-		failedColor := r.getColor("red")
-		if result.AssertionsPassed == 0 {
-			passedColor = r.getColor("dim")
-		}
-		if result.AssertionsFailed == 0 {
-			failedColor = r.getColor("dim")
+		statsColor := r.getColor("green")
+		if result.AssertionsFailed > 0 {
+			statsColor = r.getColor("red")
 		}
 
-		output.WriteString(fmt.Sprintf("   📊 断言统计: %s%d%s 总计, %s%d%s 通过, %s%d%s 失败\n",
-			r.getColor("bold"), result.AssertionsTotal, r.getColor("reset"),
-			passedColor, result.AssertionsPassed, r.getColor("reset"),
-			failedColor, result.AssertionsFailed, r.getColor("reset")))
+		output.WriteString(fmt.Sprintf("   📊 %s%s%s\n", statsColor,
+			r.localizer.T("result.assertion_stats", result.AssertionsTotal, result.AssertionsPassed, result.AssertionsFailed),
+			r.getColor("reset")))
 	}
 
 	// Error message for failed results with enhanced formatting
-	if result.Status == models.StatusFailed && result.ErrorMessage != "" {
-		output.WriteString(fmt.Sprintf("   %s⚠️  错误信息:%s %s\n",
-			r.getColor("red"), r.getColor("reset"), result.ErrorMessage))
+	if (result.Status == models.StatusFailed || result.Status == models.StatusTimeout) && result.ErrorMessage != "" {
+		output.WriteString(fmt.Sprintf("   %s⚠️  %s:%s %s\n",
+			r.getColor("red"), r.localizer.T("result.error"), r.getColor("reset"), result.ErrorMessage))
+	}
+
+	// Non-fatal warnings, e.g. traffic to deprecated operations
+	for _, warning := range result.Warnings {
+		output.WriteString(fmt.Sprintf("   %s⚠️  %s:%s %s\n",
+			r.getColor("yellow"), r.localizer.T("result.warning"), r.getColor("reset"), warning))
 	}
 
+	// Repeated failures are listed once per group, with their count
+	r.renderFailureGroupsHuman(output, result.FailureGroups)
+
 	// Detailed validation results with improved readability
 	if r.config.ShowDetailedErrors && len(result.Details) > 0 {
-		r.renderValidationDetailsHuman(output, result.Details)
+		r.renderValidationDetailsHuman(output, collapseGroupedFailures(result.Details, result.FailureGroups))
+	}
+
+	// Matching decisions recorded in explain mode
+	r.renderExplanationsHuman(output, result)
+}
+
+// renderExplanationsHuman renders matching decisions for a result and its operations
+func (r *DefaultReportRenderer) renderExplanationsHuman(output *strings.Builder, result models.AlignmentResult) {
+	if len(result.Explanations) > 0 {
+		output.WriteString(fmt.Sprintf("   %s🔬 %s%s\n", r.getColor("cyan"), r.localizer.T("explain.title"), r.getColor("reset")))
+		r.renderExplanationListHuman(output, result.Explanations, "      ")
+	}
+
+	operationKeys := make([]string, 0, len(result.OperationResults))
+	for key, operation := range result.OperationResults {
+		if len(operation.Explanations) > 0 {
+			operationKeys = append(operationKeys, key)
+		}
+	}
+	sort.Strings(operationKeys)
+
+	for _, key := range operationKeys {
+		output.WriteString(fmt.Sprintf("   %s🔬 %s: %s%s\n",
+			r.getColor("cyan"), r.localizer.T("explain.title"), key, r.getColor("reset")))
+		r.renderExplanationListHuman(output, result.OperationResults[key].Explanations, "      ")
 	}
 }
 
+// renderExplanationListHuman renders the decisions for each candidate span
+func (r *DefaultReportRenderer) renderExplanationListHuman(output *strings.Builder, explanations []models.MatchExplanation, indent string) {
+	for _, explanation := range explanations {
+		if explanation.Accepted {
+			output.WriteString(fmt.Sprintf("%s%s✔ %s (%s): %s%s\n", indent, r.getColor("green"),
+				explanation.SpanID, explanation.SpanName,
+				r.localizer.T("explain.accepted_by", explanation.AcceptedBy), r.getColor("reset")))
+		} else {
+			output.WriteString(fmt.Sprintf("%s%s✘ %s (%s): %s%s\n", indent, r.getColor("dim"),
+				explanation.SpanID, explanation.SpanName, r.localizer.T("explain.rejected"), r.getColor("reset")))
+		}
+
+		for _, decision := range explanation.Decisions {
+			marker := "-"
+			switch decision.Outcome {
+			case models.MatchOutcomeAccepted:
+				marker = "+"
+			case models.MatchOutcomeInfo:
+				marker = "·"
+			}
+			output.WriteString(fmt.Sprintf("%s  %s %s[%s]%s %s\n", indent, marker,
+				r.getColor("dim"), decision.Matcher, r.getColor("reset"), decision.Reason))
+		}
+	}
+}
+
+// renderFailureGroupsHuman lists failure groups when at least one failure repeats
+func (r *DefaultReportRenderer) renderFailureGroupsHuman(output *strings.Builder, groups []models.FailureGroup) {
+	repeated := false
+	for _, group := range groups {
+		if group.Count > 1 {
+			repeated = true
+			break
+		}
+	}
+	if !repeated {
+		return
+	}
+
+	output.WriteString(fmt.Sprintf("   %s🧮 %s%s\n", r.getColor("red"), r.localizer.T("groups.title", len(groups)), r.getColor("reset")))
+	for _, group := range groups {
+		location := ""
+		if group.Operation != "" {
+			location = group.Operation + ": "
+		}
+		output.WriteString(fmt.Sprintf("      %s×%d%s %s%s\n",
+			r.getColor("bold"), group.Count, r.getColor("reset"), location, group.Representative.Message))
+		if span := group.Representative.SpanContext; span != nil {
+			output.WriteString(fmt.Sprintf("         %s%s%s\n",
+				r.getColor("dim"), r.localizer.T("groups.representative", span.SpanID, span.TraceID), r.getColor("reset")))
+			if link := r.traceLink(span); link != "" {
+				output.WriteString(fmt.Sprintf("         🔗 %s\n", link))
+			}
+		}
+	}
+}
+
+// collapseGroupedFailures keeps passed details and the first failure of each group
+func collapseGroupedFailures(details []models.ValidationDetail, groups []models.FailureGroup) []models.ValidationDetail {
+	if len(groups) == 0 {
+		return details
+	}
+
+	seen := make(map[string]bool)
+	collapsed := make([]models.ValidationDetail, 0, len(details))
+	for _, detail := range details {
+		if detail.Type != "matching" && !detail.IsPassed() {
+			key := detail.GroupKey()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		collapsed = append(collapsed, detail)
+	}
+	return collapsed
+}
+
 // renderValidationDetailsHuman renders validation details in human format with enhanced styling
 func (r *DefaultReportRenderer) renderValidationDetailsHuman(output *strings.Builder, details []models.ValidationDetail) {
 	preconditions := []models.ValidationDetail{}
@@ -358,8 +735,8 @@ func (r *DefaultReportRenderer) renderValidationDetailsHuman(output *strings.Bui
 
 	// Render matching details first (if any)
 	if len(matchingDetails) > 0 {
-		output.WriteString(fmt.Sprintf("   %s🔗 Span 匹配:%s\n",
-			r.getColor("cyan"), r.getColor("reset")))
+		output.WriteString(fmt.Sprintf("   %s🔗 %s%s\n",
+			r.getColor("cyan"), r.localizer.T("result.span_matching"), r.getColor("reset")))
 		for _, detail := range matchingDetails {
 			r.renderValidationDetailHuman(output, detail, "     ")
 		}
@@ -381,9 +758,8 @@ func (r *DefaultReportRenderer) renderValidationDetailsHuman(output *strings.Bui
 			statusColor = r.getColor("red")
 		}
 
-		output.WriteString(fmt.Sprintf("   %s%s 前置条件:%s %s(%d/%d 通过)%s\n",
-			statusColor, statusIcon, r.getColor("reset"),
-			r.getColor("dim"), passedCount, len(preconditions), r.getColor("reset")))
+		output.WriteString(fmt.Sprintf("   %s%s %s%s\n",
+			statusColor, statusIcon, r.localizer.T("result.preconditions", passedCount, len(preconditions)), r.getColor("reset")))
 
 		for _, detail := range preconditions {
 			r.renderValidationDetailHuman(output, detail, "     ")
@@ -406,9 +782,8 @@ func (r *DefaultReportRenderer) renderValidationDetailsHuman(output *strings.Bui
 			statusColor = r.getColor("red")
 		}
 
-		output.WriteString(fmt.Sprintf("   %s%s 后置条件:%s %s(%d/%d 通过)%s\n",
-			statusColor, statusIcon, r.getColor("reset"),
-			r.getColor("dim"), passedCount, len(postconditions), r.getColor("reset")))
+		output.WriteString(fmt.Sprintf("   %s%s %s%s\n",
+			statusColor, statusIcon, r.localizer.T("result.postconditions", passedCount, len(postconditions)), r.getColor("reset")))
 
 		for _, detail := range postconditions {
 			r.renderValidationDetailHuman(output, detail, "     ")
@@ -420,9 +795,16 @@ func (r *DefaultReportRenderer) renderValidationDetailsHuman(output *strings.Bui
 func (r *DefaultReportRenderer) renderValidationDetailHuman(output *strings.Builder, detail models.ValidationDetail, indent string) {
 	icon := IconSuccess
 	iconColor := r.getColor("green")
-	if !detail.IsPassed() {
+	switch {
+	case detail.IsBlocking():
 		icon = IconFailed
 		iconColor = r.getColor("red")
+	case !detail.IsPassed() && detail.Severity == models.SeverityWarning:
+		icon = IconWarning
+		iconColor = r.getColor("yellow")
+	case !detail.IsPassed():
+		icon = IconInfo
+		iconColor = r.getColor("dim")
 	}
 
 	// Render the main message with color coding
@@ -433,38 +815,38 @@ func (r *DefaultReportRenderer) renderValidationDetailHuman(output *strings.Buil
 	if !detail.IsPassed() && r.config.ShowDetailedErrors {
 		// Expression details
 		if detail.Expression != "" {
-			output.WriteString(fmt.Sprintf("%s   %s表达式:%s %s%s%s\n",
-				indent, r.getColor("dim"), r.getColor("reset"),
+			output.WriteString(fmt.Sprintf("%s   %s%s:%s %s%s%s\n",
+				indent, r.getColor("dim"), r.localizer.T("detail.expression"), r.getColor("reset"),
 				r.getColor("cyan"), detail.Expression, r.getColor("reset")))
 		}
 
 		// Expected vs Actual with enhanced formatting
-		output.WriteString(fmt.Sprintf("%s   %s期望:%s %s%v%s %s(%T)%s\n",
-			indent, r.getColor("green"), r.getColor("reset"),
+		output.WriteString(fmt.Sprintf("%s   %s%s:%s %s%v%s %s(%T)%s\n",
+			indent, r.getColor("green"), r.localizer.T("detail.expected"), r.getColor("reset"),
 			r.getColor("bold"), detail.Expected, r.getColor("reset"),
 			r.getColor("dim"), detail.Expected, r.getColor("reset")))
 
-		output.WriteString(fmt.Sprintf("%s   %s实际:%s %s%v%s %s(%T)%s\n",
-			indent, r.getColor("red"), r.getColor("reset"),
+		output.WriteString(fmt.Sprintf("%s   %s%s:%s %s%v%s %s(%T)%s\n",
+			indent, r.getColor("red"), r.localizer.T("detail.actual"), r.getColor("reset"),
 			r.getColor("bold"), detail.Actual, r.getColor("reset"),
 			r.getColor("dim"), detail.Actual, r.getColor("reset")))
 
 		// Failure reason with enhanced formatting
 		if detail.FailureReason != "" {
-			output.WriteString(fmt.Sprintf("%s   %s💡 失败原因:%s %s\n",
-				indent, r.getColor("yellow"), r.getColor("reset"), detail.FailureReason))
+			output.WriteString(fmt.Sprintf("%s   %s💡 %s:%s %s\n",
+				indent, r.getColor("yellow"), r.localizer.T("detail.failure_reason"), r.getColor("reset"), detail.FailureReason))
 		}
 
 		// Context information (if available)
 		if len(detail.ContextInfo) > 0 {
-			output.WriteString(fmt.Sprintf("%s   %s🔍 上下文信息:%s\n",
-				indent, r.getColor("cyan"), r.getColor("reset")))
+			output.WriteString(fmt.Sprintf("%s   %s🔍 %s:%s\n",
+				indent, r.getColor("cyan"), r.localizer.T("detail.context"), r.getColor("reset")))
 
 			// Show relevant span information
 			if spanInfo, ok := detail.ContextInfo["span"].(map[string]interface{}); ok {
 				if spanName, ok := spanInfo["name"].(string); ok {
-					output.WriteString(fmt.Sprintf("%s     Span 名称: %s%s%s\n",
-						indent, r.getColor("cyan"), spanName, r.getColor("reset")))
+					output.WriteString(fmt.Sprintf("%s     %s: %s%s%s\n",
+						indent, r.localizer.T("detail.span_name"), r.getColor("cyan"), spanName, r.getColor("reset")))
 				}
 				if spanID, ok := spanInfo["id"].(string); ok {
 					output.WriteString(fmt.Sprintf("%s     Span ID: %s%s%s\n",
@@ -475,8 +857,8 @@ func (r *DefaultReportRenderer) renderValidationDetailHuman(output *strings.Buil
 					if status.Code == "ERROR" {
 						statusColor = r.getColor("red")
 					}
-					output.WriteString(fmt.Sprintf("%s     状态: %s%s%s",
-						indent, statusColor, status.Code, r.getColor("reset")))
+					output.WriteString(fmt.Sprintf("%s     %s: %s%s%s",
+						indent, r.localizer.T("detail.span_status"), statusColor, status.Code, r.getColor("reset")))
 					if status.Message != "" {
 						output.WriteString(fmt.Sprintf(" - %s", status.Message))
 					}
@@ -485,10 +867,16 @@ func (r *DefaultReportRenderer) renderValidationDetailHuman(output *strings.Buil
 			}
 		}
 
+		// Deep link to the offending trace
+		if link := r.traceLink(detail.SpanContext); link != "" {
+			output.WriteString(fmt.Sprintf("%s   %s🔗 %s:%s %s\n",
+				indent, r.getColor("cyan"), r.localizer.T("trace.link"), r.getColor("reset"), link))
+		}
+
 		// Actionable suggestions with enhanced formatting
 		if len(detail.Suggestions) > 0 {
-			output.WriteString(fmt.Sprintf("%s   %s💡 建议:%s\n",
-				indent, r.getColor("yellow"), r.getColor("reset")))
+			output.WriteString(fmt.Sprintf("%s   %s💡 %s:%s\n",
+				indent, r.getColor("yellow"), r.localizer.T("detail.suggestions"), r.getColor("reset")))
 			for i, suggestion := range detail.Suggestions {
 				output.WriteString(fmt.Sprintf("%s     %s%d.%s %s\n",
 					indent, r.getColor("dim"), i+1, r.getColor("reset"), suggestion))
@@ -512,6 +900,10 @@ func (r *DefaultReportRenderer) getStatusIcon(status models.AlignmentStatus) str
 		return IconFailed
 	case models.StatusSkipped:
 		return IconSkipped
+	case models.StatusTimeout:
+		return IconTimeout
+	case models.StatusFlaky:
+		return IconFlaky
 	default:
 		return "❓"
 	}
@@ -582,7 +974,7 @@ func (r *DefaultReportRenderer) validateReportCompleteness(report *models.Alignm
 		switch result.Status {
 		case models.StatusSuccess:
 			actualSuccess++
-		case models.StatusFailed:
+		case models.StatusFailed, models.StatusTimeout:
 			actualFailed++
 		case models.StatusSkipped:
 			actualSkipped++
@@ -624,82 +1016,7 @@ func (r *DefaultReportRenderer) validateReportCompleteness(report *models.Alignm
 
 // GetJSONSchema returns the JSON schema for the alignment report
 func (r *DefaultReportRenderer) GetJSONSchema() string {
-	return `{
-  "$schema": "http://json-schema.org/draft-07/schema#",
-  "$id": "https://flowspec.dev/schemas/alignment-report.json",
-  "title": "FlowSpec Alignment Report",
-  "description": "Schema for FlowSpec alignment verification reports",
-  "type": "object",
-  "required": ["summary", "results", "executionTime", "startTime", "endTime"],
-  "properties": {
-    "summary": {
-      "type": "object",
-      "required": ["total", "success", "failed", "skipped", "successRate", "failureRate", "skipRate"],
-      "properties": {
-        "total": {"type": "integer", "minimum": 0},
-        "success": {"type": "integer", "minimum": 0},
-        "failed": {"type": "integer", "minimum": 0},
-        "skipped": {"type": "integer", "minimum": 0},
-        "successRate": {"type": "number", "minimum": 0, "maximum": 1},
-        "failureRate": {"type": "number", "minimum": 0, "maximum": 1},
-        "skipRate": {"type": "number", "minimum": 0, "maximum": 1},
-        "averageExecutionTime": {"type": "integer", "minimum": 0},
-        "totalAssertions": {"type": "integer", "minimum": 0},
-        "failedAssertions": {"type": "integer", "minimum": 0}
-      }
-    },
-    "results": {
-      "type": "array",
-      "items": {
-        "type": "object",
-        "required": ["specOperationId", "status", "details", "executionTime"],
-        "properties": {
-          "specOperationId": {"type": "string", "minLength": 1},
-          "status": {"type": "string", "enum": ["SUCCESS", "FAILED", "SKIPPED"]},
-          "details": {
-            "type": "array",
-            "items": {
-              "type": "object",
-              "required": ["type", "expression", "expected", "actual", "message"],
-              "properties": {
-                "type": {"type": "string", "enum": ["precondition", "postcondition", "matching"]},
-                "expression": {"type": "string"},
-                "expected": {},
-                "actual": {},
-                "message": {"type": "string"},
-                "failureReason": {"type": "string"},
-                "suggestions": {"type": "array", "items": {"type": "string"}},
-                "contextInfo": {"type": "object"}
-              }
-            }
-          },
-          "executionTime": {"type": "integer", "minimum": 0},
-          "startTime": {"type": "integer", "minimum": 0},
-          "endTime": {"type": "integer", "minimum": 0},
-          "matchedSpans": {"type": "array", "items": {"type": "string"}},
-          "assertionsTotal": {"type": "integer", "minimum": 0},
-          "assertionsPassed": {"type": "integer", "minimum": 0},
-          "assertionsFailed": {"type": "integer", "minimum": 0},
-          "errorMessage": {"type": "string"}
-        }
-      }
-    },
-    "executionTime": {"type": "integer", "minimum": 0},
-    "startTime": {"type": "integer", "minimum": 0},
-    "endTime": {"type": "integer", "minimum": 0},
-    "performanceInfo": {
-      "type": "object",
-      "properties": {
-        "specsProcessed": {"type": "integer", "minimum": 0},
-        "spansMatched": {"type": "integer", "minimum": 0},
-        "assertionsEvaluated": {"type": "integer", "minimum": 0},
-        "concurrentWorkers": {"type": "integer", "minimum": 1},
-        "memoryUsageMB": {"type": "number", "minimum": 0},
-        "processingRate": {"type": "number", "minimum": 0}
-      }
-    }
-  }
-}`
+	return reportSchemaJSON
 }
 
 // ValidateJSONOutput validates that the JSON output conforms to the schema
@@ -756,7 +1073,7 @@ func (r *DefaultReportRenderer) RenderJSONWithSchema(report *models.AlignmentRep
 
 	// Create a wrapper object that includes both the schema and the report
 	wrapper := map[string]interface{}{
-		"$schema": "https://flowspec.dev/schemas/alignment-report.json",
+		"$schema": models.ReportSchemaURL,
 		"report":  report,
 	}
 
@@ -774,11 +1091,16 @@ func (r *DefaultReportRenderer) GetExitCode(report *models.AlignmentReport) int
 		return 2 // System error
 	}
 
-	if report.HasFailures() {
-		return 1 // Validation failures
+	// An interrupted run is never reported as passing, even in warn-only mode
+	if report.Incomplete {
+		return ExitInterrupted
+	}
+
+	if r.exitPolicy().ExitZero {
+		return ExitSuccess
 	}
 
-	return 0 // Success
+	return r.policyExitCode(report)
 }
 
 // Color support methods
@@ -813,9 +1135,9 @@ func (r *DefaultReportRenderer) getStatusColor(status models.AlignmentStatus) st
 	switch status {
 	case models.StatusSuccess:
 		return r.getColor("green")
-	case models.StatusFailed:
+	case models.StatusFailed, models.StatusTimeout:
 		return r.getColor("red")
-	case models.StatusSkipped:
+	case models.StatusSkipped, models.StatusFlaky:
 		return r.getColor("yellow")
 	default:
 		return r.getColor("reset")
@@ -866,6 +1188,8 @@ func (r *DefaultReportRenderer) GetExitCodeDescription(exitCode int) string {
 		return "Parse error"
 	case ExitSystemError:
 		return "System error"
+	case ExitInterrupted:
+		return "Interrupted, results are incomplete"
 	case ExitUsageError:
 		return "Usage error"
 	default:
@@ -873,10 +1197,41 @@ func (r *DefaultReportRenderer) GetExitCodeDescription(exitCode int) string {
 	}
 }
 
-// WriteArtifacts writes machine-readable artifacts for CI/CD integration
+// WriteArtifacts writes machine-readable artifacts for CI/CD integration, up to Concurrency
+// at once. When several fail, the error of the first in the order below is returned.
 func (r *DefaultReportRenderer) WriteArtifacts(report *models.AlignmentReport) error {
-	// For now, this is a placeholder implementation
-	// In a full implementation, this would write JSON summaries and JUnit XML files
-	// to an artifacts directory for CI/CD systems to consume
+	var writers []func() error
+	add := func(path string, write func(*models.AlignmentReport, string) error) {
+		if path != "" {
+			writers = append(writers, func() error { return write(report, path) })
+		}
+	}
+	add(r.config.HTMLReportPath, r.writeHTMLReport)
+	add(r.config.SARIFReportPath, r.writeSARIFReport)
+	add(r.config.JUnitReportPath, r.writeJUnitReport)
+	add(r.config.CodeQualityReportPath, r.writeCodeQualityReport)
+	add(r.config.CSVReportPath, r.writeCSVReport)
+	add(r.config.BadgeDir, r.writeBadges)
+	add(r.config.PrometheusReportPath, r.writePrometheusReport)
+	add(r.config.SummaryReportPath, r.writeSummaryReport)
+
+	errs := make([]error, len(writers))
+	slots := make(chan struct{}, concurrency.Bounded(r.config.Concurrency, len(writers)))
+	var wg sync.WaitGroup
+	for i, write := range writers {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, write func() error) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			errs[i] = write()
+		}(i, write)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
\ No newline at end of file
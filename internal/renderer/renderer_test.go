@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/flowspec/flowspec-cli/internal/i18n"
 	"github.com/flowspec/flowspec-cli/internal/models"
 
 	"github.com/stretchr/testify/assert"
@@ -530,6 +531,29 @@ func TestGetStatusIcon(t *testing.T) {
 }
 
 // Helper function to create test reports
+func TestRenderHuman_English(t *testing.T) {
+	config := DefaultRendererConfig()
+	config.ColorOutput = false
+	config.ShowPerformance = true
+	renderer := NewReportRendererWithConfigAndLanguage(config, i18n.LanguageEnglish)
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusFailed, models.StatusSuccess, models.StatusSkipped})
+	report.PerformanceInfo = models.PerformanceInfo{SpecsProcessed: 3, ProcessingRate: 10.5, MemoryUsageMB: 25.3}
+
+	output, err := renderer.RenderHuman(report)
+
+	require.NoError(t, err)
+	assert.Contains(t, output, "Processing Rate: 10.50 specs/sec")
+	assert.Contains(t, output, "❌ Failed Validations (1)")
+	assert.Contains(t, output, "Expected: 200")
+	assert.Contains(t, output, "Failure reason: Expected 200 but got 500")
+	assert.Contains(t, output, "Validation result: ❌ Failed")
+	for _, r := range output {
+		if r >= 0x4e00 && r <= 0x9fff {
+			t.Fatalf("English report contains Chinese text: %s", output)
+		}
+	}
+}
+
 func createTestReport(t *testing.T, statuses []models.AlignmentStatus) *models.AlignmentReport {
 	report := models.NewAlignmentReport()
 	report.ExecutionTime = int64(time.Second)
@@ -570,3 +594,344 @@ func createTestReport(t *testing.T, statuses []models.AlignmentStatus) *models.A
 
 	return report
 }
+
+func createCoverageReport(t *testing.T) *models.AlignmentReport {
+	report := models.NewAlignmentReport()
+	result := models.NewAlignmentResult("user-service-v1")
+	result.AddValidationDetail(models.ValidationDetail{Type: "status_code", Expected: true, Actual: true})
+	result.OperationResults = map[string]*models.OperationResult{
+		"GET /users":  {Path: "/users", Method: "GET", Status: models.StatusSuccess, SampleCount: 2},
+		"POST /users": {Path: "/users", Method: "POST", Status: models.StatusSkipped},
+	}
+	report.AddResult(*result)
+	return report
+}
+
+func TestGetExitCode_MinCoverage(t *testing.T) {
+	report := createCoverageReport(t)
+
+	config := DefaultRendererConfig()
+	renderer := NewReportRendererWithConfig(config)
+	assert.Equal(t, ExitSuccess, renderer.GetExitCode(report), "no gate configured")
+
+	config.MinCoverage = 0.5
+	assert.Equal(t, ExitSuccess, renderer.GetExitCode(report))
+
+	config.MinCoverage = 0.8
+	assert.Equal(t, ExitValidationFailed, renderer.GetExitCode(report))
+}
+
+func TestRenderHuman_Coverage(t *testing.T) {
+	report := createCoverageReport(t)
+
+	config := DefaultRendererConfig()
+	config.ColorOutput = false
+	config.MinCoverage = 0.8
+	renderer := NewReportRendererWithConfigAndLanguage(config, "en")
+
+	output, err := renderer.RenderHuman(report)
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "Coverage: 1/2 operations exercised (50.0%)")
+	assert.Contains(t, output, "GET /users (2 samples)")
+	assert.Contains(t, output, "POST /users (not exercised)")
+	assert.Contains(t, output, "below the required minimum of 80.0%")
+}
+
+func TestRenderHuman_UnmatchedSpans(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess})
+	report.Unmatched = &models.UnmatchedSpanReport{
+		TotalSpans: 3,
+		Groups: []models.UnmatchedSpanGroup{
+			{Name: "POST /orders", Method: "POST", Route: "/orders", Status: "201", Count: 2, SampleSpanIDs: []string{"s1", "s2"}},
+			{Name: "db.query", Status: "OK", Count: 1, SampleSpanIDs: []string{"s3"}},
+		},
+	}
+
+	config := DefaultRendererConfig()
+	config.ColorOutput = false
+	renderer := NewReportRendererWithConfigAndLanguage(config, "en")
+
+	output, err := renderer.RenderHuman(report)
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "Unmatched Spans (3)")
+	assert.Contains(t, output, "2× POST /orders [201] (s1, s2)")
+	assert.Contains(t, output, "1× db.query [OK] (s3)")
+
+	jsonOutput, err := renderer.RenderJSON(report)
+	require.NoError(t, err)
+	assert.Contains(t, jsonOutput, `"unmatched"`)
+	assert.Contains(t, jsonOutput, `"sampleSpanIds"`)
+}
+
+func TestRenderHuman_TraceCompleteness(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess})
+	report.Completeness = &models.TraceCompleteness{Spans: 14, Traces: 1}
+	for i := 0; i < 12; i++ {
+		report.Completeness.Issues = append(report.Completeness.Issues, models.TraceIssue{
+			Kind: models.TraceIssueOrphanSpan, SpanID: fmt.Sprintf("s%02d", i), ParentID: "lost",
+			Message: fmt.Sprintf("parent lost of span s%02d is not in the trace", i),
+		})
+	}
+
+	config := DefaultRendererConfig()
+	config.ColorOutput = false
+	renderer := NewReportRendererWithConfigAndLanguage(config, "en")
+
+	output, err := renderer.RenderHuman(report)
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "Trace completeness: 12 structural issue(s) in 14 spans")
+	assert.Contains(t, output, "[orphan_span] parent lost of span s09 is not in the trace")
+	assert.NotContains(t, output, "span s10 ")
+	assert.Contains(t, output, "... and 2 more")
+
+	jsonOutput, err := renderer.RenderJSON(report)
+	require.NoError(t, err)
+	assert.Contains(t, jsonOutput, `"completeness"`)
+	assert.Contains(t, jsonOutput, `"kind": "orphan_span"`)
+}
+
+func TestRenderHuman_ClockSkewCorrection(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess})
+	report.ClockSkew = &models.ClockSkewCorrection{Spans: 3, MaxShift: int64(42 * time.Millisecond)}
+
+	config := DefaultRendererConfig()
+	config.ColorOutput = false
+	renderer := NewReportRendererWithConfigAndLanguage(config, "en")
+
+	output, err := renderer.RenderHuman(report)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Clock skew corrected: 3 span(s) moved, by up to 42ms")
+
+	jsonOutput, err := renderer.RenderJSON(report)
+	require.NoError(t, err)
+	assert.Contains(t, jsonOutput, `"clockSkew"`)
+}
+
+func TestRenderHuman_DuplicateSpans(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess})
+	report.Summary.DuplicateSpans = 2
+
+	config := DefaultRendererConfig()
+	config.ColorOutput = false
+	renderer := NewReportRendererWithConfigAndLanguage(config, "en")
+
+	output, err := renderer.RenderHuman(report)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Duplicate spans ignored: 2 span(s) shared the ID of a matched span; only the last span with an ID was evaluated")
+
+	report.Summary.DuplicateSpans = 0
+	output, err = renderer.RenderHuman(report)
+	require.NoError(t, err)
+	assert.NotContains(t, output, "Duplicate spans ignored")
+}
+
+func TestRenderHuman_Ambiguities(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess, models.StatusSuccess})
+	report.Results[0].Ambiguities = []models.MatchAmbiguity{{
+		SpanID: "span-1", SpanName: "GET /users/profile", Operations: []string{"GET /users/{id}", "GET /users/profile"},
+		ResolvedTo: "GET /users/profile", Policy: "most-specific-wins",
+	}}
+	report.Results[1].Ambiguities = []models.MatchAmbiguity{{
+		SpanID: "span-2", SpanName: "GET /users/42", Operations: []string{"GET /users/{id}", "GET /users/{name}"}, Policy: "all",
+	}}
+	report.Summary.AmbiguousSpans = 2
+
+	config := DefaultRendererConfig()
+	config.ColorOutput = false
+	renderer := NewReportRendererWithConfigAndLanguage(config, "en")
+
+	output, err := renderer.RenderHuman(report)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Ambiguous matches: 2 span(s) matched several operations")
+	assert.Contains(t, output, "span-1 (GET /users/profile): GET /users/{id}, GET /users/profile → evaluated by GET /users/profile")
+	assert.Contains(t, output, "span-2 (GET /users/42): GET /users/{id}, GET /users/{name} → evaluated by all of them (all)")
+	assert.Contains(t, output, "Set matcher.ambiguity to most-specific-wins, fail or all")
+
+	jsonOutput, err := renderer.RenderJSON(report)
+	require.NoError(t, err)
+	assert.Contains(t, jsonOutput, `"ambiguousSpans": 2`)
+	assert.Contains(t, jsonOutput, `"resolvedTo": "GET /users/profile"`)
+}
+
+func TestRenderHuman_FailFast(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusFailed})
+	report.FailFast = &models.FailFastStop{Spec: "checkout-service-v1", Operation: "POST /checkout", NotAligned: 4}
+
+	config := DefaultRendererConfig()
+	config.ColorOutput = false
+	renderer := NewReportRendererWithConfigAndLanguage(config, "en")
+
+	output, err := renderer.RenderHuman(report)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Stopped at the first critical failure in POST /checkout (--fail-fast), 4 spec(s) not aligned")
+	assert.Equal(t, 1, renderer.GetExitCode(report), "a failure, not an interruption")
+
+	report.FailFast.Operation = ""
+	output, err = renderer.RenderHuman(report)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Stopped at the first critical failure in checkout-service-v1")
+}
+
+func TestRenderHuman_Sampling(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess})
+	report.Coverage = &models.CoverageReport{
+		TotalOperations: 2, CoveredOperations: 1, Ratio: 0.5,
+		Operations: []models.OperationCoverage{
+			{Operation: "GET /orders", Path: "/orders", Method: "GET", SampleCount: 3, Covered: true, EstimatedRequests: 30},
+			{Operation: "POST /orders", Path: "/orders", Method: "POST", UnseenRequests: 28},
+		},
+	}
+	report.Sampling = &models.Sampling{Rate: 0.1, Source: models.SamplingSourceConfig, Confidence: 0.95}
+
+	config := DefaultRendererConfig()
+	config.ColorOutput = false
+	renderer := NewReportRendererWithConfigAndLanguage(config, "en")
+
+	output, err := renderer.RenderHuman(report)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Traces sampled at 10.0% (--trace-sample-rate): request counts are extrapolated, intervals and bounds hold at 95% confidence")
+	assert.Contains(t, output, "GET /orders (3 samples, ≈30 requests)")
+	assert.Contains(t, output, "POST /orders (not exercised, up to 28 request(s) may have been sampled out)")
+
+	report.Sampling = nil
+	report.Coverage.Operations[0].EstimatedRequests = 0
+	report.Coverage.Operations[1].UnseenRequests = 0
+	output, err = renderer.RenderHuman(report)
+	require.NoError(t, err)
+	assert.NotContains(t, output, "sampled")
+	assert.Contains(t, output, "GET /orders (3 samples)")
+}
+
+func TestRenderHuman_Services(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess})
+	report.Services = []models.ServiceSummary{
+		{Service: "order-service", Total: 1, Failed: 1, TotalOperations: 2, FailedOperations: 1, CoveredOperations: 1},
+		{Service: "user-service", Total: 2, Success: 2, TotalOperations: 3, CoveredOperations: 3},
+	}
+
+	config := DefaultRendererConfig()
+	config.ColorOutput = false
+	renderer := NewReportRendererWithConfigAndLanguage(config, "en")
+
+	output, err := renderer.RenderHuman(report)
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "Services (2)")
+	assert.Contains(t, output, "❌ order-service: 0/1 specs passed, 1 failed, 1/2 operations exercised")
+	assert.Contains(t, output, "✅ user-service: 2/2 specs passed, 0 failed, 3/3 operations exercised")
+
+	jsonOutput, err := renderer.RenderJSON(report)
+	require.NoError(t, err)
+	assert.Contains(t, jsonOutput, `"services"`)
+}
+
+func TestRenderHuman_Tags(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess})
+	report.Tags = []models.TagSummary{
+		{Tag: "critical", TotalOperations: 3, SuccessOperations: 2, FailedOperations: 1, CoveredOperations: 3},
+		{Tag: "payments", TotalOperations: 2, SuccessOperations: 1, SkippedOperations: 1, CoveredOperations: 1},
+	}
+
+	config := DefaultRendererConfig()
+	config.ColorOutput = false
+	renderer := NewReportRendererWithConfigAndLanguage(config, "en")
+
+	output, err := renderer.RenderHuman(report)
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "Tags (2)")
+	assert.Contains(t, output, "❌ critical: 2/3 operations passed, 1 failed, 3/3 operations exercised")
+	assert.Contains(t, output, "✅ payments: 1/2 operations passed, 0 failed, 1/2 operations exercised")
+
+	jsonOutput, err := renderer.RenderJSON(report)
+	require.NoError(t, err)
+	assert.Contains(t, jsonOutput, `"tags"`)
+}
+
+func TestRenderHuman_Traces(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess})
+	report.Results[0].TraceID = "trace-a"
+	report.Traces = []models.TraceSummary{
+		{TraceID: "trace-a", Spans: 4, Total: 1, Success: 1},
+		{TraceID: "trace-b", Spans: 3, Total: 2, Success: 1, Failed: 1},
+		{TraceID: "trace-c", Total: 1, Failed: 1, Error: "trace data is empty or nil"},
+	}
+
+	config := DefaultRendererConfig()
+	config.ColorOutput = false
+	renderer := NewReportRendererWithConfigAndLanguage(config, "en")
+
+	output, err := renderer.RenderHuman(report)
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "Traces (3, 2 failed)")
+	assert.NotContains(t, output, "trace-a:", "passing traces are not listed")
+	assert.Contains(t, output, "❌ trace-b: 1/2 specs passed, 1 failed (3 spans)")
+	assert.Contains(t, output, "❌ trace-c: not aligned: trace data is empty or nil")
+	assert.Contains(t, output, "Trace: trace-a")
+
+	var ndjson strings.Builder
+	require.NoError(t, NewNDJSONWriter(&ndjson, renderer).WriteSummary(report))
+	assert.Contains(t, ndjson.String(), `"traces":[{"traceId":"trace-a"`)
+}
+
+func TestRenderHuman_Explanations(t *testing.T) {
+	report := models.NewAlignmentReport()
+	result := models.NewAlignmentResult("user-service-v1")
+	accepted := models.MatchExplanation{SpanID: "s1", SpanName: "GET /users/{id}"}
+	accepted.Accept("http_target", "http.target /users/1 matches /users/{id}")
+	rejected := models.MatchExplanation{SpanID: "s2", SpanName: "POST /orders"}
+	rejected.Reject("http_method", "method mismatch: span has POST, operation expects GET")
+	result.OperationResults = map[string]*models.OperationResult{
+		"GET /users/{id}": {Path: "/users/{id}", Method: "GET", SampleCount: 1,
+			Explanations: []models.MatchExplanation{accepted, rejected}},
+	}
+	report.AddResult(*result)
+
+	config := DefaultRendererConfig()
+	config.ColorOutput = false
+	renderer := NewReportRendererWithConfigAndLanguage(config, "en")
+
+	output, err := renderer.RenderHuman(report)
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "Match explanation: GET /users/{id}")
+	assert.Contains(t, output, "✔ s1 (GET /users/{id}): accepted by http_target")
+	assert.Contains(t, output, "✘ s2 (POST /orders): rejected")
+	assert.Contains(t, output, "- [http_method] method mismatch: span has POST, operation expects GET")
+}
+
+func TestRenderHuman_FailureGroups(t *testing.T) {
+	report := models.NewAlignmentReport()
+	result := models.NewAlignmentResult("order-service-v1")
+	result.Status = models.StatusFailed
+	for _, spanID := range []string{"span-1", "span-2", "span-3"} {
+		result.AddValidationDetail(models.ValidationDetail{
+			Type:        "postcondition",
+			Operation:   "GET /orders",
+			Expression:  "status == 200",
+			Expected:    200,
+			Actual:      500,
+			Message:     "Response status check failed",
+			SpanContext: &models.Span{SpanID: spanID, TraceID: "trace-1"},
+		})
+	}
+	result.FailureGroups = models.GroupFailures(result.Details)
+	report.AddResult(*result)
+
+	config := DefaultRendererConfig()
+	config.ColorOutput = false
+	renderer := NewReportRendererWithConfigAndLanguage(config, "en")
+
+	output, err := renderer.RenderHuman(report)
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "Failure groups (1)")
+	assert.Contains(t, output, "×3 GET /orders: Response status check failed")
+	assert.Contains(t, output, "representative span span-1 (trace trace-1)")
+	// The detailed list shows the group once instead of three identical failures
+	assert.Equal(t, 2, strings.Count(output, "Response status check failed"))
+}
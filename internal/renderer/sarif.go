@@ -0,0 +1,290 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// SARIF constants
+const (
+	SARIFVersion = "2.1.0"
+	SARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// sarifRuleDescriptions describes the rule reported for each failing validation type
+var sarifRuleDescriptions = map[string]string{
+	"status_code":     "Response status code does not match the contract",
+	"required_header": "Required request header is missing",
+	"required_query":  "Required query parameter is missing",
+	"precondition":    "Precondition assertion failed",
+	"postcondition":   "Postcondition assertion failed",
+	"matching":        "No spans matched the contract operation",
+	"deprecation":     "Deprecated operation received traffic after its sunset date",
+	"uncovered_span":  "Request span is not covered by any contract operation",
+	"timeout":         "Contract verification timed out",
+}
+
+// sarifLog is the root object of a SARIF 2.1.0 file
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// RenderSARIF renders verification failures as SARIF 2.1.0 for code scanning tools. Each failing
+// validation type of a failed operation becomes one result located at the contract line declaring it.
+func (r *DefaultReportRenderer) RenderSARIF(report *models.AlignmentReport) (string, error) {
+	if report == nil {
+		return "", fmt.Errorf("report cannot be nil")
+	}
+
+	results := []sarifResult{}
+	usedRules := make(map[string]bool)
+	for _, failure := range collectFailures(report) {
+		usedRules[failure.ruleType] = true
+		result := sarifResult{
+			RuleID:  "flowspec/" + failure.ruleType,
+			Level:   "error",
+			Message: sarifMessage{Text: fmt.Sprintf("%s: %s", failure.key, failure.message())},
+		}
+		if failure.file != "" {
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: r.sarifURI(failure.file)},
+			}}
+			if failure.line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: failure.line}
+			}
+			result.Locations = []sarifLocation{location}
+		}
+		results = append(results, result)
+	}
+
+	ruleTypes := make([]string, 0, len(usedRules))
+	for ruleType := range usedRules {
+		ruleTypes = append(ruleTypes, ruleType)
+	}
+	sort.Strings(ruleTypes)
+	rules := make([]sarifRule, 0, len(ruleTypes))
+	for _, ruleType := range ruleTypes {
+		description, ok := sarifRuleDescriptions[ruleType]
+		if !ok {
+			description = fmt.Sprintf("%s validation failed", ruleType)
+		}
+		rules = append(rules, sarifRule{ID: "flowspec/" + ruleType, ShortDescription: sarifMessage{Text: description}})
+	}
+
+	log := sarifLog{
+		Schema:  SARIFSchema,
+		Version: SARIFVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "flowspec-cli",
+				InformationURI: "https://github.com/flowspec/flowspec-cli",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal SARIF report: %w", err)
+	}
+	return string(data), nil
+}
+
+// reportFailure is the group of failed validations of one type for a spec or operation,
+// located at the contract line that declares it
+type reportFailure struct {
+	ruleType string
+	key      string
+	first    string
+	count    int
+	file     string
+	line     int
+}
+
+// message describes the first failure and how many more occurred
+func (f reportFailure) message() string {
+	if f.count > 1 {
+		return fmt.Sprintf("%s (%d failures)", f.first, f.count)
+	}
+	return f.first
+}
+
+// FailureSummary is one failing check of a failed spec or operation, grouped like the SARIF output
+type FailureSummary struct {
+	Key     string `json:"key"`  // Operation key ("GET /users") or spec ID
+	Type    string `json:"type"` // Validation type, e.g. status_code
+	Message string `json:"message"`
+	Count   int    `json:"count"` // Number of failed validations of this type
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+}
+
+// FailureSummaries lists the failing checks of the report, in report order
+func FailureSummaries(report *models.AlignmentReport) []FailureSummary {
+	if report == nil {
+		return nil
+	}
+	failures := collectFailures(report)
+	summaries := make([]FailureSummary, 0, len(failures))
+	for _, failure := range failures {
+		summaries = append(summaries, FailureSummary{
+			Key:     failure.key,
+			Type:    failure.ruleType,
+			Message: failure.first,
+			Count:   failure.count,
+			File:    failure.file,
+			Line:    failure.line,
+		})
+	}
+	return summaries
+}
+
+// collectFailures groups the failures of every failed spec and operation, in report order
+func collectFailures(report *models.AlignmentReport) []reportFailure {
+	var failures []reportFailure
+	for _, result := range report.Results {
+		if result.Status == models.StatusTimeout {
+			failures = append(failures, reportFailure{
+				ruleType: "timeout", key: result.SpecOperationID, first: result.ErrorMessage, count: 1,
+				file: result.SourceFile, line: result.LineNumber,
+			})
+			continue
+		}
+
+		if len(result.OperationResults) == 0 {
+			failures = append(failures, groupFailures(result.SpecOperationID, result.Details, result.SourceFile, result.LineNumber)...)
+			continue
+		}
+
+		keys := make([]string, 0, len(result.OperationResults))
+		for key := range result.OperationResults {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			operation := result.OperationResults[key]
+			if operation.Status != models.StatusFailed {
+				continue
+			}
+			file, line := operation.SourceFile, operation.LineNumber
+			if file == "" {
+				file, line = result.SourceFile, result.LineNumber
+			}
+			failures = append(failures, groupFailures(key, operation.Details, file, line)...)
+		}
+	}
+	return failures
+}
+
+// groupFailures groups failed validation details by type, in order of first occurrence
+func groupFailures(key string, details []models.ValidationDetail, file string, line int) []reportFailure {
+	var failures []reportFailure
+	index := make(map[string]int)
+	for _, detail := range details {
+		if detail.IsPassed() {
+			continue
+		}
+		if i, ok := index[detail.Type]; ok {
+			failures[i].count++
+			continue
+		}
+		index[detail.Type] = len(failures)
+		failures = append(failures, reportFailure{ruleType: detail.Type, key: key, first: detail.Message, count: 1, file: file, line: line})
+	}
+	return failures
+}
+
+// sarifURI returns a repository-relative, forward-slash path when possible, as code scanning expects
+func (r *DefaultReportRenderer) sarifURI(file string) string {
+	if filepath.IsAbs(file) {
+		if wd, err := os.Getwd(); err == nil {
+			if relative, err := filepath.Rel(wd, file); err == nil && !strings.HasPrefix(relative, "..") {
+				file = relative
+			}
+		}
+	}
+	return filepath.ToSlash(file)
+}
+
+// writeSARIFReport renders the report as SARIF and writes it to path
+func (r *DefaultReportRenderer) writeSARIFReport(report *models.AlignmentReport, path string) error {
+	log, err := r.RenderSARIF(report)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(log), 0644); err != nil {
+		return fmt.Errorf("failed to write SARIF report %s: %w", path, err)
+	}
+	return nil
+}
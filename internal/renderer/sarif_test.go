@@ -0,0 +1,93 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderSARIF(t *testing.T) {
+	statusFailure := models.ValidationDetail{Type: "status_code", Expected: 200, Actual: 500, Message: "Status code 500 does not match any expected values"}
+
+	yamlResult := models.NewAlignmentResult("user-service-v1")
+	yamlResult.SourceFile = "contracts/service-spec.yaml"
+	yamlResult.LineNumber = 1
+	yamlResult.Status = models.StatusFailed
+	yamlResult.OperationResults = map[string]*models.OperationResult{
+		"GET /users": {
+			Status:     models.StatusFailed,
+			SourceFile: "contracts/service-spec.yaml",
+			LineNumber: 12,
+			Details: []models.ValidationDetail{
+				statusFailure,
+				statusFailure,
+				{Type: "required_header", Expected: "present", Actual: "missing", Message: "Required header authorization is missing"},
+			},
+		},
+		"POST /users": {Status: models.StatusSuccess, SourceFile: "contracts/service-spec.yaml", LineNumber: 20},
+	}
+
+	legacyResult := models.NewAlignmentResult("createUser")
+	legacyResult.SourceFile = "src/UserController.java"
+	legacyResult.LineNumber = 42
+	legacyResult.AddValidationDetail(models.ValidationDetail{Type: "postcondition", Expected: true, Actual: false, Message: "Postcondition failed"})
+
+	report := models.NewAlignmentReport()
+	report.AddResult(*yamlResult)
+	report.AddResult(*legacyResult)
+
+	output, err := NewReportRenderer().RenderSARIF(report)
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal([]byte(output), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+
+	rules := log.Runs[0].Tool.Driver.Rules
+	require.Len(t, rules, 3)
+	assert.Equal(t, "flowspec/postcondition", rules[0].ID)
+
+	results := log.Runs[0].Results
+	require.Len(t, results, 3)
+	assert.Equal(t, "flowspec/status_code", results[0].RuleID)
+	assert.Equal(t, "error", results[0].Level)
+	assert.Equal(t, "GET /users: Status code 500 does not match any expected values (2 failures)", results[0].Message.Text)
+	assert.Equal(t, "contracts/service-spec.yaml", results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 12, results[0].Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, "flowspec/required_header", results[1].RuleID)
+
+	assert.Equal(t, "createUser: Postcondition failed", results[2].Message.Text)
+	assert.Equal(t, 42, results[2].Locations[0].PhysicalLocation.Region.StartLine)
+}
+
+func TestWriteArtifacts_SARIFReport(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess})
+
+	config := DefaultRendererConfig()
+	config.SARIFReportPath = filepath.Join(t.TempDir(), "results.sarif")
+	require.NoError(t, NewReportRendererWithConfig(config).WriteArtifacts(report))
+
+	data, err := os.ReadFile(config.SARIFReportPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"results": []`)
+}
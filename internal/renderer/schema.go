@@ -0,0 +1,215 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// reportSchemaJSON is the published JSON Schema of reports written with --output json
+//
+//go:embed schemas/alignment-report.v1.schema.json
+var reportSchemaJSON string
+
+// summarySchemaJSON is the published JSON Schema of summaries written with --summary-json
+//
+//go:embed schemas/summary.v1.schema.json
+var summarySchemaJSON string
+
+// Kinds of report artifacts that have a schema
+const (
+	ArtifactReport  = "report"
+	ArtifactSummary = "summary"
+)
+
+// Schemas of the artifacts with their #/definitions references inlined, for ValidateAgainstSchema
+var (
+	reportSchema  = mustLoadSchema(reportSchemaJSON)
+	summarySchema = mustLoadSchema(summarySchemaJSON)
+)
+
+// CheckSchemaVersion checks that an artifact of the given schemaVersion can be read: its major
+// version must be the one of ReportSchemaVersion, while newer minor versions only add
+// properties. Artifacts written before schemaVersion existed have none and are accepted.
+func CheckSchemaVersion(version string) error {
+	if version == "" {
+		return nil
+	}
+	major, minor, ok := strings.Cut(version, ".")
+	if !ok || !isDigits(major) || !isDigits(minor) {
+		return fmt.Errorf("invalid schemaVersion %q, expected <major>.<minor>", version)
+	}
+	supported, _, _ := strings.Cut(models.ReportSchemaVersion, ".")
+	if major != supported {
+		return fmt.Errorf("schemaVersion %s is not supported, this version of flowspec-cli reads %s.x", version, supported)
+	}
+	return nil
+}
+
+// ArtifactValidation is the outcome of validating one artifact file against its schema
+type ArtifactValidation struct {
+	Path          string `json:"path"`
+	Kind          string `json:"kind,omitempty"` // ArtifactReport or ArtifactSummary
+	SchemaVersion string `json:"schemaVersion,omitempty"`
+	Valid         bool   `json:"valid"`
+	Pointer       string `json:"pointer,omitempty"` // JSON Pointer of the first violation
+	Message       string `json:"message,omitempty"`
+}
+
+// SchemaValidationResult is the outcome of the report validate command
+type SchemaValidationResult struct {
+	Valid     bool                 `json:"valid"`
+	Artifacts []ArtifactValidation `json:"artifacts"`
+}
+
+// ExitCode returns the exit code for CI gating: validation failed when any artifact is invalid
+func (r *SchemaValidationResult) ExitCode() int {
+	if !r.Valid {
+		return ExitValidationFailed
+	}
+	return ExitSuccess
+}
+
+// FormatHuman renders the validation as human-readable text
+func (r *SchemaValidationResult) FormatHuman() string {
+	var output strings.Builder
+	invalid := 0
+	for _, artifact := range r.Artifacts {
+		if artifact.Valid {
+			output.WriteString(fmt.Sprintf("✅ %s: valid %s (schemaVersion %s)\n", artifact.Path, artifact.Kind, artifact.SchemaVersion))
+			continue
+		}
+		invalid++
+		location := ""
+		if artifact.Pointer != "" {
+			location = " at " + artifact.Pointer
+		}
+		kind := artifact.Kind
+		if kind == "" {
+			kind = "artifact"
+		}
+		output.WriteString(fmt.Sprintf("❌ %s: invalid %s%s: %s\n", artifact.Path, kind, location, artifact.Message))
+	}
+	output.WriteString(fmt.Sprintf("\n%d artifact(s) checked, %d invalid\n", len(r.Artifacts), invalid))
+	return output.String()
+}
+
+// ValidateArtifactFiles validates reports written with --output json and summaries written
+// with --summary-json against their published schemas; the kind of each file is recognized
+// from its content. It fails only when a file cannot be read.
+func ValidateArtifactFiles(paths ...string) (*SchemaValidationResult, error) {
+	result := &SchemaValidationResult{Valid: true, Artifacts: []ArtifactValidation{}}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		artifact := ValidateArtifact(data)
+		artifact.Path = path
+		result.Valid = result.Valid && artifact.Valid
+		result.Artifacts = append(result.Artifacts, artifact)
+	}
+	return result, nil
+}
+
+// ValidateArtifact validates the JSON of a report or of a summary against its schema and
+// checks that its schemaVersion is supported
+func ValidateArtifact(data []byte) ArtifactValidation {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return ArtifactValidation{Message: fmt.Sprintf("not valid JSON: %v", err)}
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return ArtifactValidation{Message: "expected a JSON object"}
+	}
+
+	artifact := ArtifactValidation{Kind: ArtifactReport}
+	schema := reportSchema
+	if _, isSummary := object["checks"]; isSummary {
+		if _, isReport := object["results"]; !isReport {
+			artifact.Kind, schema = ArtifactSummary, summarySchema
+		}
+	}
+	artifact.SchemaVersion, _ = object["schemaVersion"].(string)
+
+	if pointer, message := models.ValidateAgainstSchema(schema, value); message != "" {
+		artifact.Pointer, artifact.Message = pointer, message
+		return artifact
+	}
+	if err := CheckSchemaVersion(artifact.SchemaVersion); err != nil {
+		artifact.Pointer, artifact.Message = "/schemaVersion", err.Error()
+		return artifact
+	}
+	artifact.Valid = true
+	return artifact
+}
+
+// mustLoadSchema decodes an embedded schema and inlines its references
+func mustLoadSchema(text string) map[string]interface{} {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &schema); err != nil {
+		panic(fmt.Sprintf("invalid embedded schema: %v", err))
+	}
+	definitions, _ := schema["definitions"].(map[string]interface{})
+	return inlineReferences(schema, definitions).(map[string]interface{})
+}
+
+// inlineReferences replaces every {"$ref": "#/definitions/<name>"} with its definition; the
+// embedded schemas have no recursive definitions
+func inlineReferences(node interface{}, definitions map[string]interface{}) interface{} {
+	switch typed := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := typed["$ref"].(string); ok {
+			name, found := strings.CutPrefix(ref, "#/definitions/")
+			definition, defined := definitions[name]
+			if !found || !defined {
+				panic(fmt.Sprintf("unresolved schema reference %s", ref))
+			}
+			return inlineReferences(definition, definitions)
+		}
+		inlined := make(map[string]interface{}, len(typed))
+		for key, value := range typed {
+			inlined[key] = inlineReferences(value, definitions)
+		}
+		return inlined
+	case []interface{}:
+		inlined := make([]interface{}, len(typed))
+		for i, value := range typed {
+			inlined[i] = inlineReferences(value, definitions)
+		}
+		return inlined
+	}
+	return node
+}
+
+// isDigits reports whether value is a non-empty string of ASCII digits
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,147 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateArtifact_RenderedReport(t *testing.T) {
+	report := createGranularReport()
+	report.Coverage = &models.CoverageReport{TotalOperations: 3, CoveredOperations: 1, Ratio: 1.0 / 3,
+		Operations: []models.OperationCoverage{{Operation: "GET /users", Path: "/users", Method: "GET", SampleCount: 1, Covered: true}}}
+	report.Unmatched = &models.UnmatchedSpanReport{TotalSpans: 1, Groups: []models.UnmatchedSpanGroup{{Name: "GET /health", Count: 1}}}
+	report.Results[1].FailureGroups = []models.FailureGroup{{Type: "postcondition", Expected: true, Count: 1,
+		Representative: report.Results[1].Details[1]}}
+
+	output, err := NewReportRenderer().RenderJSON(report)
+	require.NoError(t, err)
+
+	artifact := ValidateArtifact([]byte(output))
+	assert.True(t, artifact.Valid, "%s: %s", artifact.Pointer, artifact.Message)
+	assert.Equal(t, ArtifactReport, artifact.Kind)
+	assert.Equal(t, models.ReportSchemaVersion, artifact.SchemaVersion)
+}
+
+func TestValidateArtifact_Summary(t *testing.T) {
+	output, err := NewReportRenderer().RenderSummaryJSON(createGranularReport())
+	require.NoError(t, err)
+
+	artifact := ValidateArtifact([]byte(output))
+	assert.True(t, artifact.Valid, "%s: %s", artifact.Pointer, artifact.Message)
+	assert.Equal(t, ArtifactSummary, artifact.Kind)
+}
+
+func TestValidateArtifact_Violations(t *testing.T) {
+	testCases := []struct {
+		name            string
+		body            string
+		expectedKind    string
+		expectedPointer string
+		expectedMessage string
+	}{
+		{name: "not JSON", body: `{`, expectedMessage: "not valid JSON: unexpected end of JSON input"},
+		{name: "not an object", body: `[]`, expectedMessage: "expected a JSON object"},
+		{
+			name:            "missing schemaVersion",
+			body:            `{"summary": {}, "results": [], "executionTime": 0, "startTime": 0, "endTime": 0}`,
+			expectedKind:    ArtifactReport,
+			expectedMessage: `missing required property "schemaVersion"`,
+		},
+		{
+			name: "unknown status",
+			body: `{"schemaVersion": "1.0", "executionTime": 0, "startTime": 0, "endTime": 0,
+				"summary": {"total": 1, "success": 0, "failed": 0, "skipped": 0, "successRate": 0, "failureRate": 0, "skipRate": 0},
+				"results": [{"specOperationId": "op", "status": "BROKEN", "details": null, "executionTime": 0}]}`,
+			expectedKind:    ArtifactReport,
+			expectedPointer: "/results/0/status",
+			expectedMessage: "value BROKEN is not one of the allowed values",
+		},
+		{
+			name:            "summary with a wrong type",
+			body:            `{"schemaVersion": "1.0", "result": "passed", "checks": "2", "passed": 2, "failed": 0, "skipped": 0, "assertions": 4, "failedAssertions": 0, "duration": "1s", "durationMs": 1000, "exitCode": 0}`,
+			expectedKind:    ArtifactSummary,
+			expectedPointer: "/checks",
+			expectedMessage: "expected integer, got string",
+		},
+		{
+			name:            "unsupported major version",
+			body:            `{"schemaVersion": "2.0", "result": "passed", "checks": 2, "passed": 2, "failed": 0, "skipped": 0, "assertions": 4, "failedAssertions": 0, "duration": "1s", "durationMs": 1000, "exitCode": 0}`,
+			expectedKind:    ArtifactSummary,
+			expectedPointer: "/schemaVersion",
+			expectedMessage: "schemaVersion 2.0 is not supported, this version of flowspec-cli reads 1.x",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			artifact := ValidateArtifact([]byte(tc.body))
+			assert.False(t, artifact.Valid)
+			assert.Equal(t, tc.expectedKind, artifact.Kind)
+			assert.Equal(t, tc.expectedPointer, artifact.Pointer)
+			assert.Equal(t, tc.expectedMessage, artifact.Message)
+		})
+	}
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	assert.NoError(t, CheckSchemaVersion(""), "reports written before schemaVersion existed")
+	assert.NoError(t, CheckSchemaVersion("1.0"))
+	assert.NoError(t, CheckSchemaVersion("1.7"), "newer minor versions only add properties")
+	assert.EqualError(t, CheckSchemaVersion("2.0"), "schemaVersion 2.0 is not supported, this version of flowspec-cli reads 1.x")
+	assert.EqualError(t, CheckSchemaVersion("v1"), `invalid schemaVersion "v1", expected <major>.<minor>`)
+}
+
+func TestValidateArtifactFiles(t *testing.T) {
+	dir := t.TempDir()
+	renderer := NewReportRenderer()
+	report, err := renderer.RenderJSON(createGranularReport())
+	require.NoError(t, err)
+	reportPath := filepath.Join(dir, "report.json")
+	require.NoError(t, os.WriteFile(reportPath, []byte(report), 0644))
+	brokenPath := filepath.Join(dir, "broken.json")
+	require.NoError(t, os.WriteFile(brokenPath, []byte(`{"schemaVersion": "1.0", "checks": 1}`), 0644))
+
+	result, err := ValidateArtifactFiles(reportPath, brokenPath)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, ExitValidationFailed, result.ExitCode())
+	require.Len(t, result.Artifacts, 2)
+	assert.True(t, result.Artifacts[0].Valid)
+	assert.Equal(t, `missing required property "result"`, result.Artifacts[1].Message)
+
+	human := result.FormatHuman()
+	assert.Contains(t, human, "✅ "+reportPath+": valid report (schemaVersion 1.0)")
+	assert.Contains(t, human, "❌ "+brokenPath+`: invalid summary: missing required property "result"`)
+	assert.Contains(t, human, "2 artifact(s) checked, 1 invalid")
+
+	_, err = ValidateArtifactFiles(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestEmbeddedSchemasAreVersioned(t *testing.T) {
+	for text, id := range map[string]string{reportSchemaJSON: models.ReportSchemaURL, summarySchemaJSON: models.SummarySchemaURL} {
+		var schema map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(text), &schema))
+		assert.Equal(t, id, schema["$id"])
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// Outcomes of a run recorded in its summary
+const (
+	SummaryPassed     = "passed"
+	SummaryFailed     = "failed"
+	SummaryIncomplete = "incomplete"
+)
+
+// Summary is the compact outcome of a run written to flowspec-summary.json, for CI comments
+// and dashboards that do not need the full report
+type Summary struct {
+	SchemaVersion    string   `json:"schemaVersion"`
+	Result           string   `json:"result"` // passed, failed or incomplete
+	Checks           int      `json:"checks"`
+	Passed           int      `json:"passed"`
+	Failed           int      `json:"failed"`
+	Skipped          int      `json:"skipped"`
+	Assertions       int      `json:"assertions"`
+	FailedAssertions int      `json:"failedAssertions"`
+	Warnings         int      `json:"warnings,omitempty"`
+	Coverage         *float64 `json:"coverage,omitempty"` // Covered operations ratio, for YAML contracts
+	Duration         string   `json:"duration"`           // Human-readable, e.g. "1.25s"
+	DurationMs       int64    `json:"durationMs"`
+	ExitCode         int      `json:"exitCode"`
+}
+
+// Summarize returns the summary of a report; the result follows the exit code of the run
+func (r *DefaultReportRenderer) Summarize(report *models.AlignmentReport) *Summary {
+	duration := time.Duration(report.ExecutionTime)
+	summary := &Summary{
+		SchemaVersion:    models.ReportSchemaVersion,
+		Result:           SummaryPassed,
+		Checks:           report.Summary.Total,
+		Passed:           report.Summary.Success,
+		Failed:           report.Summary.Failed,
+		Skipped:          report.Summary.Skipped,
+		Assertions:       report.Summary.TotalAssertions,
+		FailedAssertions: report.Summary.FailedAssertions,
+		Warnings:         report.Summary.Warnings,
+		Duration:         duration.Round(time.Millisecond).String(),
+		DurationMs:       duration.Milliseconds(),
+		ExitCode:         r.GetExitCode(report),
+	}
+	if report.Coverage != nil {
+		ratio := report.Coverage.Ratio
+		summary.Coverage = &ratio
+	}
+	switch {
+	case report.Incomplete:
+		summary.Result = SummaryIncomplete
+	case summary.ExitCode != ExitSuccess:
+		summary.Result = SummaryFailed
+	}
+	return summary
+}
+
+// RenderSummaryJSON renders the summary of a report as indented JSON
+func (r *DefaultReportRenderer) RenderSummaryJSON(report *models.AlignmentReport) (string, error) {
+	if report == nil {
+		return "", fmt.Errorf("report cannot be nil")
+	}
+	data, err := json.MarshalIndent(r.Summarize(report), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal summary: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// writeSummaryReport renders the summary and writes it to path
+func (r *DefaultReportRenderer) writeSummaryReport(report *models.AlignmentReport, path string) error {
+	data, err := r.RenderSummaryJSON(report)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		return fmt.Errorf("failed to write summary %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarize(t *testing.T) {
+	report := createGranularReport()
+	report.ExecutionTime = int64(1250 * time.Millisecond)
+	report.Coverage = &models.CoverageReport{TotalOperations: 3, CoveredOperations: 1, Ratio: 0.5}
+
+	summary := NewReportRenderer().Summarize(report)
+	assert.Equal(t, models.ReportSchemaVersion, summary.SchemaVersion)
+	assert.Equal(t, SummaryFailed, summary.Result)
+	assert.Equal(t, 2, summary.Checks)
+	assert.Equal(t, 0, summary.Passed)
+	assert.Equal(t, 2, summary.Failed)
+	assert.Equal(t, "1.25s", summary.Duration)
+	assert.Equal(t, int64(1250), summary.DurationMs)
+	assert.Equal(t, ExitValidationFailed, summary.ExitCode)
+	require.NotNil(t, summary.Coverage)
+	assert.Equal(t, 0.5, *summary.Coverage)
+
+	passing := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess})
+	assert.Equal(t, SummaryPassed, NewReportRenderer().Summarize(passing).Result)
+
+	passing.Incomplete = true
+	assert.Equal(t, SummaryIncomplete, NewReportRenderer().Summarize(passing).Result)
+}
+
+func TestWriteArtifacts_Summary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flowspec-summary.json")
+	config := DefaultRendererConfig()
+	config.SummaryReportPath = path
+	require.NoError(t, NewReportRendererWithConfig(config).WriteArtifacts(createTestReport(t, []models.AlignmentStatus{models.StatusSuccess})))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var summary map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &summary))
+	assert.Equal(t, "passed", summary["result"])
+	assert.Equal(t, float64(1), summary["checks"])
+	assert.NotContains(t, summary, "coverage")
+}
+
+func TestWriteArtifacts_Concurrency(t *testing.T) {
+	report := createTestReport(t, []models.AlignmentStatus{models.StatusSuccess, models.StatusFailed})
+
+	dir := t.TempDir()
+	config := DefaultRendererConfig()
+	config.Concurrency = 2
+	config.HTMLReportPath = filepath.Join(dir, "report.html")
+	config.SARIFReportPath = filepath.Join(dir, "report.sarif")
+	config.JUnitReportPath = filepath.Join(dir, "junit.xml")
+	config.CSVReportPath = filepath.Join(dir, "report.csv")
+	config.PrometheusReportPath = filepath.Join(dir, "metrics.prom")
+	config.SummaryReportPath = filepath.Join(dir, "summary.json")
+	require.NoError(t, NewReportRendererWithConfig(config).WriteArtifacts(report))
+	for _, path := range []string{config.HTMLReportPath, config.SARIFReportPath, config.JUnitReportPath, config.CSVReportPath, config.PrometheusReportPath, config.SummaryReportPath} {
+		assert.FileExists(t, path)
+	}
+
+	config.HTMLReportPath = filepath.Join(dir, "missing", "report.html")
+	config.CSVReportPath = filepath.Join(dir, "missing", "report.csv")
+	err := NewReportRendererWithConfig(config).WriteArtifacts(report)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "HTML report", "the first failing artifact is reported")
+	assert.FileExists(t, config.SummaryReportPath, "other artifacts are still written")
+}
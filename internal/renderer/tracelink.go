@@ -0,0 +1,58 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// Placeholders supported by trace UI URL templates
+const (
+	TraceIDPlaceholder = "{traceId}"
+	SpanIDPlaceholder  = "{spanId}"
+)
+
+// ValidateTraceUIURLTemplate checks that a trace UI URL template is an absolute
+// http(s) URL referencing the trace ID
+func ValidateTraceUIURLTemplate(template string) error {
+	if !strings.Contains(template, TraceIDPlaceholder) {
+		return fmt.Errorf("trace UI URL template must contain %s", TraceIDPlaceholder)
+	}
+	sample := strings.NewReplacer(TraceIDPlaceholder, "trace", SpanIDPlaceholder, "span").Replace(template)
+	parsed, err := url.Parse(sample)
+	if err != nil {
+		return fmt.Errorf("invalid trace UI URL template: %w", err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("trace UI URL template must be an absolute http(s) URL")
+	}
+	return nil
+}
+
+// traceLink returns the trace UI link of a span, or an empty string when no template
+// is configured or the span carries no trace ID
+func (r *DefaultReportRenderer) traceLink(span *models.Span) string {
+	if r.config.TraceUIURLTemplate == "" || span == nil || span.TraceID == "" {
+		return ""
+	}
+	return strings.NewReplacer(
+		TraceIDPlaceholder, url.PathEscape(span.TraceID),
+		SpanIDPlaceholder, url.PathEscape(span.SpanID),
+	).Replace(r.config.TraceUIURLTemplate)
+}
@@ -0,0 +1,97 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderer
+
+import (
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTraceUIURLTemplate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template string
+		wantErr  bool
+	}{
+		{name: "jaeger", template: "https://jaeger.example.com/trace/{traceId}"},
+		{name: "span anchor", template: "http://tempo:3200/trace/{traceId}?spanId={spanId}"},
+		{name: "missing trace placeholder", template: "https://jaeger.example.com/search", wantErr: true},
+		{name: "relative", template: "/trace/{traceId}", wantErr: true},
+		{name: "unsupported scheme", template: "javascript:alert('{traceId}')", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTraceUIURLTemplate(tc.template)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTraceLink(t *testing.T) {
+	config := DefaultRendererConfig()
+	renderer := NewReportRendererWithConfig(config)
+	span := &models.Span{SpanID: "b7ad6b7169203331", TraceID: "0af7651916cd43dd8448eb211c80319c"}
+
+	assert.Empty(t, renderer.traceLink(span), "no link without a template")
+
+	config.TraceUIURLTemplate = "https://jaeger/trace/{traceId}?uiFind={spanId}"
+	assert.Equal(t, "https://jaeger/trace/0af7651916cd43dd8448eb211c80319c?uiFind=b7ad6b7169203331", renderer.traceLink(span))
+	assert.Equal(t, "https://jaeger/trace/a%2Fb?uiFind=", renderer.traceLink(&models.Span{TraceID: "a/b"}))
+	assert.Empty(t, renderer.traceLink(nil))
+	assert.Empty(t, renderer.traceLink(&models.Span{SpanID: "s1"}))
+}
+
+func TestTraceLinks_InOutputs(t *testing.T) {
+	report := models.NewAlignmentReport()
+	result := models.NewAlignmentResult("order-service-v1")
+	result.Status = models.StatusFailed
+	detail := models.ValidationDetail{
+		Type:        "postcondition",
+		Operation:   "GET /orders",
+		Expression:  "status == 200",
+		Expected:    200,
+		Actual:      500,
+		Message:     "Response status check failed",
+		SpanContext: &models.Span{SpanID: "span-1", TraceID: "trace-1"},
+	}
+	result.AddValidationDetail(detail)
+	report.AddResult(*result)
+
+	config := DefaultRendererConfig()
+	config.ColorOutput = false
+	config.TraceUIURLTemplate = "https://jaeger/trace/{traceId}"
+	renderer := NewReportRendererWithConfigAndLanguage(config, "en")
+	link := "https://jaeger/trace/trace-1"
+
+	human, err := renderer.RenderHuman(report)
+	require.NoError(t, err)
+	assert.Contains(t, human, "🔗 Trace: "+link)
+
+	page, err := renderer.RenderHTML(report)
+	require.NoError(t, err)
+	assert.Contains(t, page, `<a href="`+link+`" target="_blank" rel="noopener">`)
+
+	summary, err := renderer.RenderMarkdown(report)
+	require.NoError(t, err)
+	assert.Contains(t, summary, "- **order-service-v1**: Response status check failed ([Trace]("+link+"))")
+}
@@ -0,0 +1,218 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scaffold detects the layout of a project and writes a starter configuration
+// and contract for it (the init command)
+package scaffold
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxDetectDepth limits how deep Detect descends into the project
+const maxDetectDepth = 4
+
+// maxInspectBytes limits how much of each candidate file is read
+const maxInspectBytes = 64 * 1024
+
+// skippedDirs are never searched
+var skippedDirs = map[string]bool{
+	".git": true, "node_modules": true, "vendor": true, "dist": true, "build": true, "target": true,
+}
+
+// accessLogDirective matches an nginx access_log directive and captures its path
+var accessLogDirective = regexp.MustCompile(`^\s*access_log\s+([^\s;]+)`)
+
+// Detection describes what was found in a project; paths are relative to the project directory
+type Detection struct {
+	OpenAPI      []string // OpenAPI or Swagger documents
+	NginxConfigs []string // nginx configuration files
+	AccessLogs   []string // Access logs referenced by nginx configs or found in the project
+	Kubernetes   []string // Kubernetes manifests
+	ServiceName  string   // Best guess of the service name
+	ServiceFrom  string   // Where the service name came from
+}
+
+// Detect searches dir for OpenAPI documents, nginx configuration and access logs, and
+// Kubernetes manifests
+func Detect(dir string) (*Detection, error) {
+	detection := &Detection{}
+	var kubernetesName, openAPITitle string
+	logs := make(map[string]bool)
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, _ := filepath.Rel(dir, path)
+		if entry.IsDir() {
+			if path != dir && (skippedDirs[entry.Name()] || strings.Count(relative, string(filepath.Separator)) >= maxDetectDepth) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name := strings.ToLower(entry.Name())
+		switch ext := filepath.Ext(name); {
+		case ext == ".yaml" || ext == ".yml" || ext == ".json":
+			content := readHead(path)
+			if title, ok := openAPIDocument(content); ok {
+				detection.OpenAPI = append(detection.OpenAPI, relative)
+				if openAPITitle == "" {
+					openAPITitle = title
+				}
+			} else if workload, ok := kubernetesManifest(content); ok {
+				detection.Kubernetes = append(detection.Kubernetes, relative)
+				if kubernetesName == "" {
+					kubernetesName = workload
+				}
+			}
+		case ext == ".conf" || name == "nginx.conf":
+			content := readHead(path)
+			if isNginxConfig(content) {
+				detection.NginxConfigs = append(detection.NginxConfigs, relative)
+				for _, log := range accessLogs(content) {
+					logs[log] = true
+				}
+			}
+		case ext == ".log" && strings.Contains(name, "access"):
+			logs[relative] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for log := range logs {
+		detection.AccessLogs = append(detection.AccessLogs, log)
+	}
+	sort.Strings(detection.AccessLogs)
+
+	switch {
+	case kubernetesName != "":
+		detection.ServiceName, detection.ServiceFrom = slug(kubernetesName), "Kubernetes manifest"
+	case openAPITitle != "":
+		detection.ServiceName, detection.ServiceFrom = slug(openAPITitle), "OpenAPI title"
+	default:
+		absolute, _ := filepath.Abs(dir)
+		detection.ServiceName, detection.ServiceFrom = slug(filepath.Base(absolute)), "directory name"
+	}
+	if detection.ServiceName == "" {
+		detection.ServiceName = "my-service"
+	}
+	return detection, nil
+}
+
+// readHead returns the beginning of a file, or nil if it cannot be read
+func readHead(path string) []byte {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+	buffer := make([]byte, maxInspectBytes)
+	n, _ := file.Read(buffer)
+	return buffer[:n]
+}
+
+// openAPIDocument reports whether content is an OpenAPI or Swagger document and returns its title
+func openAPIDocument(content []byte) (string, bool) {
+	var document struct {
+		OpenAPI string `yaml:"openapi"`
+		Swagger string `yaml:"swagger"`
+		Info    struct {
+			Title string `yaml:"title"`
+		} `yaml:"info"`
+	}
+	// JSON is valid YAML, so one decoder handles both
+	if yaml.Unmarshal(content, &document) != nil || (document.OpenAPI == "" && document.Swagger == "") {
+		return "", false
+	}
+	return document.Info.Title, true
+}
+
+// kubernetesManifest reports whether content holds Kubernetes objects and returns the name
+// of the first workload or service
+func kubernetesManifest(content []byte) (string, bool) {
+	decoder := yaml.NewDecoder(strings.NewReader(string(content)))
+	found, name := false, ""
+	for {
+		var object struct {
+			APIVersion string `yaml:"apiVersion"`
+			Kind       string `yaml:"kind"`
+			Metadata   struct {
+				Name string `yaml:"name"`
+			} `yaml:"metadata"`
+		}
+		if decoder.Decode(&object) != nil {
+			break
+		}
+		// FlowSpec contracts also have apiVersion and kind
+		if object.APIVersion == "" || object.Kind == "" || strings.HasPrefix(object.APIVersion, "flowspec/") {
+			continue
+		}
+		found = true
+		switch object.Kind {
+		case "Deployment", "StatefulSet", "DaemonSet", "Service":
+			if name == "" {
+				name = object.Metadata.Name
+			}
+		}
+	}
+	return name, found
+}
+
+// isNginxConfig reports whether content looks like an nginx configuration
+func isNginxConfig(content []byte) bool {
+	text := string(content)
+	return strings.Contains(text, "server {") || strings.Contains(text, "http {") ||
+		strings.Contains(text, "log_format") || strings.Contains(text, "access_log")
+}
+
+// accessLogs returns the access log paths configured in an nginx configuration
+func accessLogs(content []byte) []string {
+	var logs []string
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for scanner.Scan() {
+		if match := accessLogDirective.FindStringSubmatch(scanner.Text()); match != nil && match[1] != "off" {
+			logs = append(logs, match[1])
+		}
+	}
+	return logs
+}
+
+// slug turns a name into a lowercase, dash-separated identifier
+func slug(name string) string {
+	var builder strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			builder.WriteRune(r)
+			dash = false
+		} else if !dash && builder.Len() > 0 {
+			builder.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(builder.String(), "-")
+}
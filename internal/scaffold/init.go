@@ -0,0 +1,211 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Files written by Init
+const (
+	ConfigFile = ".flowspec.yaml"
+	SpecFile   = "service-spec.yaml"
+)
+
+// defaultTracePath is where the starter configuration expects the trace
+const defaultTracePath = "traces/trace.json"
+
+// Options configures Init
+type Options struct {
+	Dir         string // Project directory
+	ServiceName string // Overrides the detected service name
+	Force       bool   // Overwrite existing files
+}
+
+// Result describes what Init did
+type Result struct {
+	Detection *Detection
+	Written   []string // Files created or overwritten
+	Skipped   []string // Existing files that were kept
+	NextSteps []string // Commands to run next
+}
+
+// Init detects the project in options.Dir and writes a starter configuration and contract
+func Init(options Options) (*Result, error) {
+	dir := options.Dir
+	if dir == "" {
+		dir = "."
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access project directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	detection, err := Detect(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect project: %w", err)
+	}
+	if options.ServiceName != "" {
+		detection.ServiceName, detection.ServiceFrom = options.ServiceName, "command line"
+	}
+
+	result := &Result{Detection: detection}
+	files := []struct {
+		name    string
+		content string
+	}{
+		{ConfigFile, renderConfig(detection)},
+		{SpecFile, renderSpec(detection.ServiceName)},
+	}
+	for _, file := range files {
+		path := filepath.Join(dir, file.name)
+		if _, err := os.Stat(path); err == nil && !options.Force {
+			result.Skipped = append(result.Skipped, file.name)
+			continue
+		}
+		if err := os.WriteFile(path, []byte(file.content), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		result.Written = append(result.Written, file.name)
+	}
+
+	result.NextSteps = nextSteps(detection)
+	return result, nil
+}
+
+// renderConfig returns the starter .flowspec.yaml
+func renderConfig(detection *Detection) string {
+	var output strings.Builder
+	output.WriteString("# FlowSpec project configuration; command-line flags override these values.\n")
+	output.WriteString("path: " + SpecFile + "\n")
+	output.WriteString("# OTLP JSON trace captured from an integration test run\n")
+	output.WriteString("trace: " + defaultTracePath + "\n")
+	output.WriteString("report:\n")
+	output.WriteString("  output: human\n")
+	output.WriteString("  # minCoverage: 80%\n")
+	output.WriteString("  # junit: artifacts/flowspec-junit.xml\n")
+	output.WriteString("  # traceUIURLTemplate: https://jaeger.example.com/trace/{traceId}\n")
+	output.WriteString("explore:\n")
+	if len(detection.AccessLogs) > 0 {
+		output.WriteString(fmt.Sprintf("  traffic: %s\n", quoteYAML(detection.AccessLogs[0])))
+	} else {
+		output.WriteString("  # traffic: /var/log/nginx/access.log\n")
+	}
+	output.WriteString("  out: " + SpecFile + "\n")
+	output.WriteString(fmt.Sprintf("  serviceName: %s\n", quoteYAML(detection.ServiceName)))
+	output.WriteString("profiles:\n")
+	output.WriteString("  ci:\n")
+	output.WriteString("    report:\n")
+	output.WriteString("      junit: artifacts/flowspec-junit.xml\n")
+	return output.String()
+}
+
+// renderSpec returns an example contract with a health check operation
+func renderSpec(serviceName string) string {
+	return fmt.Sprintf(`# Example contract: replace it with operations of your service, or generate one with
+# "flowspec-cli explore" from access logs.
+apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: %s
+  version: v1.0.0
+spec:
+  endpoints:
+    - path: /health
+      operations:
+        - method: GET
+          responses:
+            statusCodes: [200]
+            aggregation: "exact"
+`, quoteYAML(serviceName))
+}
+
+// nextSteps suggests commands based on what was detected
+func nextSteps(detection *Detection) []string {
+	var steps []string
+	if len(detection.AccessLogs) > 0 {
+		steps = append(steps, fmt.Sprintf("flowspec-cli explore --traffic=%s --out=%s --service-name=%s",
+			detection.AccessLogs[0], SpecFile, detection.ServiceName))
+	} else if len(detection.NginxConfigs) > 0 {
+		steps = append(steps, fmt.Sprintf("# Point explore at the access log written by %s:", detection.NginxConfigs[0]),
+			fmt.Sprintf("flowspec-cli explore --traffic=<access.log> --out=%s", SpecFile))
+	}
+	if len(detection.OpenAPI) > 0 {
+		steps = append(steps, fmt.Sprintf("# Add the operations described in %s to %s", detection.OpenAPI[0], SpecFile))
+	}
+	steps = append(steps, fmt.Sprintf("flowspec-cli lint --path=%s", SpecFile))
+	if len(detection.Kubernetes) > 0 {
+		steps = append(steps, "# Export traces from the cluster's OpenTelemetry collector as OTLP JSON to "+defaultTracePath)
+	} else {
+		steps = append(steps, "# Capture an OTLP JSON trace of your integration tests to "+defaultTracePath)
+	}
+	steps = append(steps, "flowspec-cli verify")
+	return steps
+}
+
+// quoteYAML quotes a scalar when YAML would otherwise misread it
+func quoteYAML(value string) string {
+	if value == "" || strings.ContainsAny(value, ":#{}[],&*!|>'\"%@`") || strings.TrimSpace(value) != value {
+		return fmt.Sprintf("%q", value)
+	}
+	return value
+}
+
+// FormatHuman renders the result of Init for the terminal
+func (r *Result) FormatHuman() string {
+	var output strings.Builder
+	detection := r.Detection
+
+	output.WriteString("Detected:\n")
+	found := false
+	for _, group := range []struct {
+		title string
+		paths []string
+	}{
+		{"OpenAPI documents", detection.OpenAPI},
+		{"nginx configuration", detection.NginxConfigs},
+		{"Access logs", detection.AccessLogs},
+		{"Kubernetes manifests", detection.Kubernetes},
+	} {
+		if len(group.paths) == 0 {
+			continue
+		}
+		found = true
+		output.WriteString(fmt.Sprintf("  %s: %s\n", group.title, strings.Join(group.paths, ", ")))
+	}
+	if !found {
+		output.WriteString("  nothing specific; using generic defaults\n")
+	}
+	output.WriteString(fmt.Sprintf("  Service name: %s (from %s)\n\n", detection.ServiceName, detection.ServiceFrom))
+
+	for _, name := range r.Written {
+		output.WriteString(fmt.Sprintf("✅ Wrote %s\n", name))
+	}
+	for _, name := range r.Skipped {
+		output.WriteString(fmt.Sprintf("⏭️  Kept existing %s (use --force to overwrite)\n", name))
+	}
+
+	output.WriteString("\nNext steps:\n")
+	for _, step := range r.NextSteps {
+		output.WriteString("  " + step + "\n")
+	}
+	return output.String()
+}
@@ -0,0 +1,114 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/config"
+	"github.com/flowspec/flowspec-cli/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestDetect(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"api/openapi.yaml":             "openapi: 3.0.3\ninfo:\n  title: Order API\n  version: 1.0.0\npaths: {}\n",
+		"api/swagger.json":             `{"swagger": "2.0", "info": {"title": "Legacy"}}`,
+		"deploy/nginx/nginx.conf":      "http {\n  access_log /var/log/nginx/access.log main;\n  server {\n    access_log off;\n  }\n}\n",
+		"deploy/k8s/app.yaml":          "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: orders-api\n",
+		"logs/access.log":              "127.0.0.1 - - [10/Aug/2025:10:00:00 +0000] \"GET /health HTTP/1.1\" 200 2\n",
+		"contracts/orders.yaml":        "apiVersion: flowspec/v1alpha1\nkind: ServiceSpec\nmetadata:\n  name: orders\n",
+		"node_modules/pkg/openapi.yml": "openapi: 3.1.0\n",
+		"README.md":                    "# Orders\n",
+	})
+
+	detection, err := Detect(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{filepath.Join("api", "openapi.yaml"), filepath.Join("api", "swagger.json")}, detection.OpenAPI)
+	assert.Equal(t, []string{filepath.Join("deploy", "nginx", "nginx.conf")}, detection.NginxConfigs)
+	assert.Equal(t, []string{"/var/log/nginx/access.log", filepath.Join("logs", "access.log")}, detection.AccessLogs)
+	assert.Equal(t, []string{filepath.Join("deploy", "k8s", "app.yaml")}, detection.Kubernetes, "contracts are not Kubernetes manifests")
+	assert.Equal(t, "orders-api", detection.ServiceName)
+	assert.Equal(t, "Kubernetes manifest", detection.ServiceFrom)
+}
+
+func TestDetect_ServiceNameFallbacks(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "Payment Gateway")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+
+	detection, err := Detect(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "payment-gateway", detection.ServiceName)
+	assert.Equal(t, "directory name", detection.ServiceFrom)
+
+	writeFiles(t, dir, map[string]string{"openapi.json": `{"openapi": "3.0.0", "info": {"title": "Payments API v2"}}`})
+	detection, err = Detect(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "payments-api-v2", detection.ServiceName)
+}
+
+func TestInit(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"nginx.conf": "server {\n  access_log logs/access.log;\n}\n",
+	})
+
+	result, err := Init(Options{Dir: dir, ServiceName: "orders"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{ConfigFile, SpecFile}, result.Written)
+	assert.Contains(t, result.NextSteps, "flowspec-cli explore --traffic=logs/access.log --out=service-spec.yaml --service-name=orders")
+	assert.Contains(t, result.FormatHuman(), "Service name: orders (from command line)")
+
+	// The generated files are valid configuration and contracts
+	loaded, err := config.Load(filepath.Join(dir, ConfigFile))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, SpecFile), loaded.Path)
+	assert.Equal(t, filepath.Join(dir, "logs", "access.log"), loaded.Explore.Traffic)
+	assert.Equal(t, []string{"ci"}, loaded.ProfileNames())
+
+	specs, errs := parser.NewYAMLFileParser().ParseFile(filepath.Join(dir, SpecFile))
+	require.Empty(t, errs)
+	require.NotEmpty(t, specs)
+
+	// Existing files are kept unless forced
+	require.NoError(t, os.WriteFile(filepath.Join(dir, SpecFile), []byte("# mine\n"), 0644))
+	result, err = Init(Options{Dir: dir})
+	require.NoError(t, err)
+	assert.Empty(t, result.Written)
+	assert.Equal(t, []string{ConfigFile, SpecFile}, result.Skipped)
+	content, err := os.ReadFile(filepath.Join(dir, SpecFile))
+	require.NoError(t, err)
+	assert.Equal(t, "# mine\n", string(content))
+
+	result, err = Init(Options{Dir: dir, Force: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{ConfigFile, SpecFile}, result.Written)
+
+	_, err = Init(Options{Dir: filepath.Join(dir, SpecFile)})
+	assert.Error(t, err)
+}
@@ -0,0 +1,171 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scrub
+
+import "github.com/flowspec/flowspec-cli/internal/models"
+
+// Report returns a copy of a verification report with the trace data it quotes redacted: the
+// actual values, messages and span contexts of validation details, warnings and errors, match
+// explanations and unmatched span groups. Expected values come from the contracts and are kept.
+// The stats count the values redacted.
+func (s *Scrubber) Report(report *models.AlignmentReport) (*models.AlignmentReport, Stats) {
+	stats := Stats{}
+	if report == nil {
+		return nil, stats
+	}
+
+	scrubbed := *report
+	scrubbed.Results = make([]models.AlignmentResult, len(report.Results))
+	for i, result := range report.Results {
+		scrubbed.Results[i] = s.scrubResult(result, stats)
+	}
+	if report.Unmatched != nil {
+		unmatched := *report.Unmatched
+		unmatched.Groups = make([]models.UnmatchedSpanGroup, len(report.Unmatched.Groups))
+		for i, group := range report.Unmatched.Groups {
+			group.Name = s.scrubText(group.Name, stats)
+			group.Route = s.scrubText(group.Route, stats)
+			unmatched.Groups[i] = group
+		}
+		scrubbed.Unmatched = &unmatched
+	}
+	return &scrubbed, stats
+}
+
+// scrubResult redacts the trace data quoted by an alignment result
+func (s *Scrubber) scrubResult(result models.AlignmentResult, stats Stats) models.AlignmentResult {
+	result.Details = s.scrubDetails(result.Details, stats)
+	result.ErrorMessage = s.scrubText(result.ErrorMessage, stats)
+	result.Warnings = s.scrubTexts(result.Warnings, stats)
+
+	if result.OperationResults != nil {
+		operations := make(map[string]*models.OperationResult, len(result.OperationResults))
+		for key, operation := range result.OperationResults {
+			if operation == nil {
+				operations[key] = nil
+				continue
+			}
+			copied := *operation
+			copied.Details = s.scrubDetails(operation.Details, stats)
+			operations[key] = &copied
+		}
+		result.OperationResults = operations
+	}
+
+	if result.FailureGroups != nil {
+		groups := make([]models.FailureGroup, len(result.FailureGroups))
+		for i, group := range result.FailureGroups {
+			group.Representative = s.scrubDetail(group.Representative, stats)
+			groups[i] = group
+		}
+		result.FailureGroups = groups
+	}
+
+	if result.Explanations != nil {
+		explanations := make([]models.MatchExplanation, len(result.Explanations))
+		for i, explanation := range result.Explanations {
+			explanation.SpanName = s.scrubText(explanation.SpanName, stats)
+			decisions := make([]models.MatchDecision, len(explanation.Decisions))
+			for j, decision := range explanation.Decisions {
+				decision.Reason = s.scrubText(decision.Reason, stats)
+				decisions[j] = decision
+			}
+			explanation.Decisions = decisions
+			explanations[i] = explanation
+		}
+		result.Explanations = explanations
+	}
+	return result
+}
+
+// scrubDetails returns redacted copies of validation details
+func (s *Scrubber) scrubDetails(details []models.ValidationDetail, stats Stats) []models.ValidationDetail {
+	if details == nil {
+		return nil
+	}
+	scrubbed := make([]models.ValidationDetail, len(details))
+	for i, detail := range details {
+		scrubbed[i] = s.scrubDetail(detail, stats)
+	}
+	return scrubbed
+}
+
+// scrubDetail redacts the actual value, messages, context and span of a validation detail
+func (s *Scrubber) scrubDetail(detail models.ValidationDetail, stats Stats) models.ValidationDetail {
+	detail.Actual, _ = s.scrubValue("", detail.Actual, stats)
+	detail.Message = s.scrubText(detail.Message, stats)
+	detail.FailureReason = s.scrubText(detail.FailureReason, stats)
+	detail.ContextInfo = s.scrubAttributes(detail.ContextInfo, stats)
+	detail.Suggestions = s.scrubTexts(detail.Suggestions, stats)
+	detail.SpanContext = s.scrubSpan(detail.SpanContext, stats)
+	return detail
+}
+
+// scrubSpan returns a redacted copy of a span
+func (s *Scrubber) scrubSpan(span *models.Span, stats Stats) *models.Span {
+	if span == nil {
+		return nil
+	}
+	scrubbed := *span
+	scrubbed.Name = s.scrubText(span.Name, stats)
+	scrubbed.Status.Message = s.scrubText(span.Status.Message, stats)
+	scrubbed.Attributes = s.scrubAttributes(span.Attributes, stats)
+	if span.Events != nil {
+		scrubbed.Events = make([]models.SpanEvent, len(span.Events))
+		for i, event := range span.Events {
+			event.Name = s.scrubText(event.Name, stats)
+			event.Attributes = s.scrubAttributes(event.Attributes, stats)
+			scrubbed.Events[i] = event
+		}
+	}
+	return &scrubbed
+}
+
+// scrubTexts returns redacted copies of strings
+func (s *Scrubber) scrubTexts(values []string, stats Stats) []string {
+	if values == nil {
+		return nil
+	}
+	scrubbed := make([]string, len(values))
+	for i, value := range values {
+		scrubbed[i] = s.scrubText(value, stats)
+	}
+	return scrubbed
+}
+
+// Span returns a redacted copy of a span, for quoting it in a report
+func (s *Scrubber) Span(span *models.Span) *models.Span {
+	return s.scrubSpan(span, Stats{})
+}
+
+// Attributes returns a redacted copy of an attribute map
+func (s *Scrubber) Attributes(attributes map[string]interface{}) map[string]interface{} {
+	return s.scrubAttributes(attributes, Stats{})
+}
+
+// Value returns a redacted copy of a value whose attribute key is unknown, such as the actual
+// value of an assertion; a value a rule would drop is masked
+func (s *Scrubber) Value(value interface{}) interface{} {
+	if text, ok := value.(string); ok {
+		return s.scrubText(text, Stats{})
+	}
+	scrubbed, _ := s.scrubValue("", value, Stats{})
+	return scrubbed
+}
+
+// Text redacts a message
+func (s *Scrubber) Text(value string) string {
+	return s.scrubText(value, Stats{})
+}
@@ -0,0 +1,327 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scrub redacts personal data and secrets from traces, so production traces can be
+// shared and committed as verification fixtures, and from verification reports. Rules redact
+// the whole value of attributes whose key matches, or the parts of string values matching a
+// pattern, such as emails, tokens and IP addresses.
+package scrub
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Action is what a rule does with the values it matches
+type Action string
+
+const (
+	ActionMask Action = "mask" // Replace with the replacement of the rule, <name> by default
+	ActionHash Action = "hash" // Replace with <name:hash>, so equal values stay equal across spans
+	ActionDrop Action = "drop" // Remove the attribute
+)
+
+// Rule redacts the whole value of the attributes whose key matches one of Keys, and the parts
+// of string values matching Pattern. A rule naming a built-in rule without keys or a pattern
+// takes those of the built-in rule, e.g. {name: email, action: hash}.
+type Rule struct {
+	Name        string   `json:"name" yaml:"name"`
+	Keys        []string `json:"keys,omitempty" yaml:"keys,omitempty"`               // Case-insensitive glob patterns on attribute keys, e.g. "*token*"
+	Pattern     string   `json:"pattern,omitempty" yaml:"pattern,omitempty"`         // Regular expression searched in string values
+	Action      Action   `json:"action,omitempty" yaml:"action,omitempty"`           // mask, hash or drop; mask when empty
+	Replacement string   `json:"replacement,omitempty" yaml:"replacement,omitempty"` // Replacement of masked values; <name> when empty
+}
+
+// Rules lists redaction rules; the first rule whose keys match an attribute redacts its
+// value, otherwise every pattern is applied in turn
+type Rules []Rule
+
+// DefaultRules returns the built-in rules, redacting emails, tokens and credentials, and IPv4
+// and IPv6 addresses
+func DefaultRules() Rules {
+	return Rules{
+		{Name: "email", Pattern: `[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`},
+		{
+			Name: "token",
+			Keys: []string{"*authorization*", "*cookie*", "*token*", "*secret*", "*password*",
+				"*api_key*", "*api-key*", "*apikey*"},
+			Pattern: `(?i)\bbearer\s+[A-Za-z0-9._~+/-]+=*|\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`,
+		},
+		{Name: "ipv4", Pattern: `\b\d{1,3}(?:\.\d{1,3}){3}\b`},
+		{Name: "ipv6", Pattern: `[0-9A-Fa-f]{0,4}(?::[0-9A-Fa-f]{0,4}){2,7}`},
+	}
+}
+
+// builtinChecks confirm the matches of built-in patterns that are wider than what they redact
+var builtinChecks = map[string]func(string) bool{
+	"ipv4": isIP,
+	"ipv6": func(match string) bool { return strings.Contains(match, ":") && isIP(match) },
+}
+
+// LoadRules reads a rules file, a YAML document with a rules list
+func LoadRules(path string) (Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scrub rules %s: %w", path, err)
+	}
+
+	var file struct {
+		Rules Rules `yaml:"rules"`
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse scrub rules %s: %w", path, err)
+	}
+	if len(file.Rules) == 0 {
+		return nil, fmt.Errorf("scrub rules %s lists no rules", path)
+	}
+	if err := file.Rules.Validate(); err != nil {
+		return nil, fmt.Errorf("scrub rules %s: %w", path, err)
+	}
+	return file.Rules, nil
+}
+
+// Validate checks that every rule has a name, keys or a pattern, a known action and valid
+// patterns
+func (r Rules) Validate() error {
+	_, err := New(r)
+	return err
+}
+
+// Scrubber applies compiled redaction rules; it is safe for concurrent use
+type Scrubber struct {
+	rules []compiledRule
+	allow []string // Lowered key patterns of the attributes that are never redacted
+}
+
+// compiledRule is a rule with its key patterns lowered and its pattern compiled
+type compiledRule struct {
+	Rule
+	pattern *regexp.Regexp
+	check   func(string) bool // Confirms a pattern match, for built-in rules
+}
+
+// New compiles rules into a Scrubber; no rules means DefaultRules
+func New(rules Rules) (*Scrubber, error) {
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+	builtins := make(map[string]Rule)
+	for _, rule := range DefaultRules() {
+		builtins[rule.Name] = rule
+	}
+
+	scrubber := &Scrubber{}
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule %d: name is required", i+1)
+		}
+		compiled := compiledRule{Rule: rule}
+		if builtin, ok := builtins[rule.Name]; ok && len(rule.Keys) == 0 && rule.Pattern == "" {
+			compiled.Keys, compiled.Pattern = builtin.Keys, builtin.Pattern
+		}
+		if builtin, ok := builtins[rule.Name]; ok && compiled.Pattern == builtin.Pattern {
+			compiled.check = builtinChecks[rule.Name]
+		}
+		if len(compiled.Keys) == 0 && compiled.Pattern == "" {
+			return nil, fmt.Errorf("rule %d (%s): keys or a pattern is required", i+1, rule.Name)
+		}
+		switch compiled.Action {
+		case "":
+			compiled.Action = ActionMask
+		case ActionMask, ActionHash, ActionDrop:
+		default:
+			return nil, fmt.Errorf("rule %d (%s): action must be one of %s, %s, %s, got '%s'", i+1, rule.Name, ActionMask, ActionHash, ActionDrop, rule.Action)
+		}
+		lowered := make([]string, len(compiled.Keys))
+		for j, key := range compiled.Keys {
+			lowered[j] = strings.ToLower(key)
+			if _, err := path.Match(lowered[j], ""); err != nil {
+				return nil, fmt.Errorf("rule %d (%s): invalid key pattern %q: %w", i+1, rule.Name, key, err)
+			}
+		}
+		compiled.Keys = lowered
+		if compiled.Pattern != "" {
+			pattern, err := regexp.Compile(compiled.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d (%s): invalid pattern: %w", i+1, rule.Name, err)
+			}
+			compiled.pattern = pattern
+		}
+		scrubber.rules = append(scrubber.rules, compiled)
+	}
+	return scrubber, nil
+}
+
+// Stats counts the values redacted by each rule
+type Stats map[string]int
+
+// keyRule returns the first rule whose keys match an attribute key
+func (s *Scrubber) keyRule(key string) *compiledRule {
+	if key == "" {
+		return nil
+	}
+	key = strings.ToLower(key)
+	for i := range s.rules {
+		for _, pattern := range s.rules[i].Keys {
+			if matched, _ := path.Match(pattern, key); matched {
+				return &s.rules[i]
+			}
+		}
+	}
+	return nil
+}
+
+// allowed reports whether an attribute key matches the allow list
+func (s *Scrubber) allowed(key string) bool {
+	if key == "" || len(s.allow) == 0 {
+		return false
+	}
+	key = strings.ToLower(key)
+	for _, pattern := range s.allow {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue redacts the whole value of an attribute matched by a key rule; a value the rule
+// already redacted is kept, so scrubbing twice changes nothing
+func (rule *compiledRule) redactValue(value interface{}, stats Stats) interface{} {
+	text := fmt.Sprint(value)
+	if rule.redacted(text) {
+		return text
+	}
+	stats[rule.Name]++
+	return rule.replacement(text)
+}
+
+// redacted reports whether a value is a replacement of the rule
+func (rule *compiledRule) redacted(value string) bool {
+	if rule.Replacement != "" && value == rule.Replacement {
+		return true
+	}
+	return value == "<"+rule.Name+">" || strings.HasPrefix(value, "<"+rule.Name+":") && strings.HasSuffix(value, ">")
+}
+
+// replacement returns what a masked or hashed value is replaced with
+func (rule *compiledRule) replacement(value string) string {
+	if rule.Action == ActionHash {
+		sum := sha256.Sum256([]byte(value))
+		return "<" + rule.Name + ":" + hex.EncodeToString(sum[:4]) + ">"
+	}
+	if rule.Replacement != "" {
+		return rule.Replacement
+	}
+	return "<" + rule.Name + ">"
+}
+
+// scrubString redacts the parts of a string matching the patterns of the rules; drop is set when a
+// matching rule drops the value, in which case a value that cannot be dropped is masked
+func (s *Scrubber) scrubString(value string, stats Stats) (scrubbed string, drop bool) {
+	for i := range s.rules {
+		rule := &s.rules[i]
+		if rule.pattern == nil {
+			continue
+		}
+		value = rule.pattern.ReplaceAllStringFunc(value, func(match string) string {
+			if rule.check != nil && !rule.check(match) {
+				return match
+			}
+			stats[rule.Name]++
+			if rule.Action == ActionDrop {
+				drop = true
+				return "<" + rule.Name + ">"
+			}
+			return rule.replacement(match)
+		})
+	}
+	return value, drop
+}
+
+// scrubValue redacts a decoded attribute value: the whole value when a key rule matches its key,
+// otherwise the strings it holds, including those of nested maps and slices. drop is set when
+// the attribute should be removed.
+func (s *Scrubber) scrubValue(key string, value interface{}, stats Stats) (scrubbed interface{}, drop bool) {
+	if s.allowed(key) {
+		return value, false
+	}
+	if rule := s.keyRule(key); rule != nil {
+		if rule.Action == ActionDrop {
+			stats[rule.Name]++
+			return nil, true
+		}
+		return rule.redactValue(value, stats), false
+	}
+
+	switch typed := value.(type) {
+	case string:
+		return s.scrubString(typed, stats)
+	case map[string]interface{}:
+		return s.scrubAttributes(typed, stats), false
+	case []interface{}:
+		items := make([]interface{}, 0, len(typed))
+		for _, item := range typed {
+			if scrubbed, drop := s.scrubValue("", item, stats); !drop {
+				items = append(items, scrubbed)
+			}
+		}
+		return items, false
+	case []string:
+		items := make([]string, 0, len(typed))
+		for _, item := range typed {
+			if scrubbed, drop := s.scrubString(item, stats); !drop {
+				items = append(items, scrubbed)
+			}
+		}
+		return items, false
+	}
+	return value, false
+}
+
+// scrubAttributes returns a redacted copy of an attribute map
+func (s *Scrubber) scrubAttributes(attributes map[string]interface{}, stats Stats) map[string]interface{} {
+	if attributes == nil {
+		return nil
+	}
+	scrubbed := make(map[string]interface{}, len(attributes))
+	for key, value := range attributes {
+		if value, drop := s.scrubValue(key, value, stats); !drop {
+			scrubbed[key] = value
+		}
+	}
+	return scrubbed
+}
+
+// scrubText redacts a string that cannot be dropped, such as a span name or a message
+func (s *Scrubber) scrubText(value string, stats Stats) string {
+	scrubbed, _ := s.scrubString(value, stats)
+	return scrubbed
+}
+
+// isIP reports whether a match is an IP address
+func isIP(match string) bool {
+	return net.ParseIP(match) != nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scrub
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// NewSecretRedactor returns a Scrubber redacting credentials only, which verification reports
+// apply by default: the values of the attributes whose key matches the keys of the built-in
+// token rule (authorization, cookie, set-cookie, tokens, secrets, passwords and API keys) or
+// deny, and the bearer tokens and JWTs of any string. Attributes whose key matches allow are
+// kept verbatim. Keys are case-insensitive glob patterns, e.g. "*x-session-id".
+func NewSecretRedactor(allow, deny []string) (*Scrubber, error) {
+	var token Rule
+	for _, rule := range DefaultRules() {
+		if rule.Name == "token" {
+			token = rule
+		}
+	}
+	token.Keys = append(token.Keys, deny...)
+	scrubber, err := New(Rules{token})
+	if err != nil {
+		return nil, fmt.Errorf("deny: %w", err)
+	}
+
+	for _, pattern := range allow {
+		lowered := strings.ToLower(pattern)
+		if _, err := path.Match(lowered, ""); err != nil || lowered == "" {
+			return nil, fmt.Errorf("allow: invalid key pattern %q", pattern)
+		}
+		scrubber.allow = append(scrubber.allow, lowered)
+	}
+	return scrubber, nil
+}
+
+// DefaultSecretRedactor returns the Scrubber of NewSecretRedactor without allow or deny lists
+func DefaultSecretRedactor() *Scrubber {
+	scrubber, err := NewSecretRedactor(nil, nil)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in secret keys: %v", err))
+	}
+	return scrubber
+}
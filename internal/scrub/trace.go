@@ -0,0 +1,212 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scrub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Result summarizes a scrubbed trace
+type Result struct {
+	Spans    int   `json:"spans"`
+	Redacted Stats `json:"redacted"` // Values redacted by each rule
+}
+
+// Total returns the number of values redacted
+func (r *Result) Total() int {
+	total := 0
+	for _, count := range r.Redacted {
+		total += count
+	}
+	return total
+}
+
+// FormatHuman renders the summary of a scrubbed trace, e.g. "Scrubbed 12 spans: 3 email, 1 token"
+func (r *Result) FormatHuman() string {
+	if r.Total() == 0 {
+		return fmt.Sprintf("Scrubbed %d spans: nothing to redact\n", r.Spans)
+	}
+	names := make([]string, 0, len(r.Redacted))
+	for name := range r.Redacted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	counts := make([]string, len(names))
+	for i, name := range names {
+		counts[i] = fmt.Sprintf("%d %s", r.Redacted[name], name)
+	}
+	return fmt.Sprintf("Scrubbed %d spans: %s\n", r.Spans, strings.Join(counts, ", "))
+}
+
+// File scrubs a trace file into outPath, which may be the trace file itself
+func (s *Scrubber) File(tracePath, outPath string) (*Result, error) {
+	data, err := os.ReadFile(tracePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trace %s: %w", tracePath, err)
+	}
+	var scrubbed bytes.Buffer
+	result, err := s.Trace(bytes.NewReader(data), &scrubbed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrub trace %s: %w", tracePath, err)
+	}
+	if err := os.WriteFile(outPath, scrubbed.Bytes(), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write scrubbed trace: %w", err)
+	}
+	return result, nil
+}
+
+// Trace scrubs a JSON trace, in OTLP or flowspec format, keeping its structure: attributes of
+// resources, spans, events and links are redacted, as are span and event names and status
+// messages. Fields the scrubber does not know are written back unchanged.
+func (s *Scrubber) Trace(reader io.Reader, writer io.Writer) (*Result, error) {
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+	var trace interface{}
+	if err := decoder.Decode(&trace); err != nil {
+		return nil, fmt.Errorf("failed to parse trace JSON: %w", err)
+	}
+
+	result := &Result{Redacted: Stats{}}
+	trace = s.walk(trace, result)
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(trace); err != nil {
+		return nil, fmt.Errorf("failed to write scrubbed trace: %w", err)
+	}
+	return result, nil
+}
+
+// walk scrubs a decoded JSON node in place and returns it
+func (s *Scrubber) walk(node interface{}, result *Result) interface{} {
+	switch typed := node.(type) {
+	case map[string]interface{}:
+		_, isSpan := typed["spanId"]
+		_, hasAttributes := typed["attributes"]
+		for key, value := range typed {
+			switch {
+			case key == "attributes":
+				typed[key] = s.walkAttributes(value, result)
+			case key == "name" && (isSpan || hasAttributes):
+				if name, ok := value.(string); ok {
+					typed[key] = s.scrubText(name, result.Redacted)
+				}
+			case key == "status":
+				if status, ok := value.(map[string]interface{}); ok {
+					if message, ok := status["message"].(string); ok {
+						status["message"] = s.scrubText(message, result.Redacted)
+					}
+				}
+			default:
+				if spans, ok := value.([]interface{}); ok && key == "spans" {
+					result.Spans += len(spans)
+				}
+				typed[key] = s.walk(value, result)
+			}
+		}
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = s.walk(item, result)
+		}
+	}
+	return node
+}
+
+// walkAttributes scrubs OTLP attributes, a list of {key, value} with typed values, or a map
+// of attributes as in the flowspec format
+func (s *Scrubber) walkAttributes(attributes interface{}, result *Result) interface{} {
+	switch typed := attributes.(type) {
+	case []interface{}:
+		return s.scrubOTLPAttributes(typed, result.Redacted)
+	case map[string]interface{}:
+		return s.scrubAttributes(typed, result.Redacted)
+	}
+	return attributes
+}
+
+// scrubOTLPAttributes redacts a list of OTLP key-value attributes, removing dropped ones
+func (s *Scrubber) scrubOTLPAttributes(attributes []interface{}, stats Stats) []interface{} {
+	kept := make([]interface{}, 0, len(attributes))
+	for _, item := range attributes {
+		attribute, ok := item.(map[string]interface{})
+		if !ok {
+			kept = append(kept, item)
+			continue
+		}
+		key, _ := attribute["key"].(string)
+		if rule := s.keyRule(key); rule != nil {
+			if rule.Action == ActionDrop {
+				stats[rule.Name]++
+				continue
+			}
+			attribute["value"] = map[string]interface{}{"stringValue": rule.redactValue(anyValueString(attribute["value"]), stats)}
+			kept = append(kept, attribute)
+			continue
+		}
+		value, drop := s.scrubAnyValue(attribute["value"], stats)
+		if drop {
+			continue
+		}
+		attribute["value"] = value
+		kept = append(kept, attribute)
+	}
+	return kept
+}
+
+// scrubAnyValue redacts the strings of an OTLP AnyValue, including those of arrays and
+// key-value lists
+func (s *Scrubber) scrubAnyValue(value interface{}, stats Stats) (interface{}, bool) {
+	anyValue, ok := value.(map[string]interface{})
+	if !ok {
+		return s.scrubValue("", value, stats)
+	}
+	if text, ok := anyValue["stringValue"].(string); ok {
+		scrubbed, drop := s.scrubString(text, stats)
+		anyValue["stringValue"] = scrubbed
+		return anyValue, drop
+	}
+	if array, ok := anyValue["arrayValue"].(map[string]interface{}); ok {
+		values, _ := array["values"].([]interface{})
+		kept := make([]interface{}, 0, len(values))
+		for _, item := range values {
+			if scrubbed, drop := s.scrubAnyValue(item, stats); !drop {
+				kept = append(kept, scrubbed)
+			}
+		}
+		array["values"] = kept
+	}
+	if kvlist, ok := anyValue["kvlistValue"].(map[string]interface{}); ok {
+		values, _ := kvlist["values"].([]interface{})
+		kvlist["values"] = s.scrubOTLPAttributes(values, stats)
+	}
+	return anyValue, false
+}
+
+// anyValueString returns the value held by an OTLP AnyValue, e.g. "x" for {"stringValue": "x"}
+func anyValueString(value interface{}) string {
+	if anyValue, ok := value.(map[string]interface{}); ok && len(anyValue) == 1 {
+		for _, held := range anyValue {
+			return fmt.Sprint(held)
+		}
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,490 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package selfupdate replaces the running CLI with a release from GitHub, verifying the
+// archive against the checksums.txt of the release and, with a public key, the signature of
+// checksums.txt. It also checks the version constraint a configuration file pins.
+package selfupdate
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/httpclient"
+	"github.com/flowspec/flowspec-cli/internal/signing"
+)
+
+// Release channels
+const (
+	ChannelStable = "stable" // Releases only
+	ChannelBeta   = "beta"   // Prereleases too
+)
+
+// DefaultReleasesURL lists the releases of the CLI
+const DefaultReleasesURL = "https://api.github.com/repos/flowspec/flowspec-cli/releases"
+
+// DefaultTimeout bounds each request, the download of an archive included
+const DefaultTimeout = 5 * time.Minute
+
+// Assets of a release besides the archives
+const (
+	checksumsAsset = "checksums.txt"
+	signatureAsset = "checksums.txt.sig"
+)
+
+// Largest archive and binary that are downloaded or extracted
+const (
+	maxArchiveBytes = 256 << 20
+	maxMetadataSize = 8 << 20
+)
+
+// Errors matched by errors.Is to tell why an update failed
+var (
+	ErrReleaseNotFound = errors.New("release not found")           // No release or archive matches the channel, version or platform
+	ErrVerification    = errors.New("release verification failed") // A checksum or the signature does not match
+)
+
+// Options configures an update
+type Options struct {
+	CurrentVersion string             // Version of the running CLI
+	Channel        string             // ChannelStable (default) or ChannelBeta
+	Version        string             // Exact version to install instead of the newest of the channel; allows downgrades
+	PublicKey      string             // PEM file of the Ed25519 key signing checksums.txt; the signature is then required
+	CheckOnly      bool               // Report the newest version without installing it
+	Executable     string             // Binary to replace; the running executable when empty
+	ReleasesURL    string             // Releases API; DefaultReleasesURL when empty
+	Token          string             // GitHub token raising the API rate limit; GITHUB_TOKEN when empty
+	GOOS, GOARCH   string             // Platform of the binary; the running platform when empty
+	Timeout        time.Duration      // Per-request timeout; DefaultTimeout when zero
+	Client         *http.Client       // HTTP client; a client with Timeout and Network when nil
+	Network        *httpclient.Config // Proxy and TLS settings; httpclient.ConfigFromEnv() when nil
+}
+
+// Result describes an update
+type Result struct {
+	PreviousVersion string `json:"previousVersion"`
+	Version         string `json:"version"` // Installed version, or the newest available with CheckOnly
+	Channel         string `json:"channel"`
+	Available       bool   `json:"available"` // Version differs from the running version and would be installed
+	Updated         bool   `json:"updated"`
+	Asset           string `json:"asset,omitempty"`
+	Checksum        string `json:"checksum,omitempty"` // "sha256:<hex>" of the archive
+	Signed          bool   `json:"signed"`             // The signature of checksums.txt was verified
+	Path            string `json:"path,omitempty"`     // Binary replaced
+}
+
+// FormatHuman renders the update as human-readable text
+func (r *Result) FormatHuman() string {
+	switch {
+	case r.Updated:
+		verified := "checksum verified"
+		if r.Signed {
+			verified += ", signature verified"
+		}
+		return fmt.Sprintf("Updated flowspec-cli %s → %s (%s)\n", r.PreviousVersion, r.Version, verified)
+	case r.Available:
+		return fmt.Sprintf("flowspec-cli %s is available on the %s channel (running %s); run flowspec-cli self-update to install it\n",
+			r.Version, r.Channel, r.PreviousVersion)
+	}
+	return fmt.Sprintf("flowspec-cli %s is up to date (%s channel)\n", r.PreviousVersion, r.Channel)
+}
+
+// release is a GitHub release
+type release struct {
+	TagName    string  `json:"tag_name"`
+	Draft      bool    `json:"draft"`
+	Prerelease bool    `json:"prerelease"`
+	Assets     []asset `json:"assets"`
+	version    Version
+}
+
+// asset is a file of a GitHub release
+type asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Update installs the newest release of the channel, or the requested version, in place of
+// the executable. The archive must match its entry in checksums.txt, and with a public key
+// checksums.txt must match its signature; a failed verification leaves the executable
+// untouched and matches ErrVerification.
+func Update(ctx context.Context, options Options) (*Result, error) {
+	if options.Channel == "" {
+		options.Channel = ChannelStable
+	}
+	if options.Channel != ChannelStable && options.Channel != ChannelBeta {
+		return nil, fmt.Errorf("unknown release channel %q, expected %s or %s", options.Channel, ChannelStable, ChannelBeta)
+	}
+	var publicKey ed25519.PublicKey
+	if options.PublicKey != "" {
+		key, err := signing.LoadPublicKey(options.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		publicKey = key
+	}
+
+	if options.Client == nil && httpclient.IsOffline(options.Network) {
+		return nil, fmt.Errorf("cannot look for releases: %w", httpclient.ErrOffline)
+	}
+	client := options.Client
+	if client == nil {
+		timeout := options.Timeout
+		if timeout <= 0 {
+			timeout = DefaultTimeout
+		}
+		client = httpclient.New(options.Network, timeout)
+	}
+	target, err := findRelease(ctx, client, options)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{PreviousVersion: options.CurrentVersion, Version: target.version.String(), Channel: options.Channel}
+	result.Available = isNewer(target.version, options)
+	if !result.Available || options.CheckOnly {
+		return result, nil
+	}
+
+	goos, goarch := platform(options)
+	archive, ok := target.archive(goos, goarch)
+	if !ok {
+		return nil, fmt.Errorf("%w: release %s has no archive for %s/%s", ErrReleaseNotFound, target.TagName, goos, goarch)
+	}
+	result.Asset = archive.Name
+
+	checksums, err := target.download(ctx, client, checksumsAsset, maxMetadataSize)
+	if err != nil {
+		return nil, err
+	}
+	if publicKey != nil {
+		signature, err := target.download(ctx, client, signatureAsset, maxMetadataSize)
+		if err != nil {
+			return nil, err
+		}
+		if err := verifySignature(publicKey, checksums, signature); err != nil {
+			return nil, err
+		}
+		result.Signed = true
+	}
+	expected, err := lookupChecksum(checksums, archive.Name)
+	if err != nil {
+		return nil, err
+	}
+	content, err := get(ctx, client, archive.URL, "", maxArchiveBytes)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(content)
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return nil, fmt.Errorf("%w: %s has checksum %s, checksums.txt lists %s", ErrVerification, archive.Name, actual, expected)
+	}
+	result.Checksum = "sha256:" + expected
+
+	binary, err := extractBinary(content, goos)
+	if err != nil {
+		return nil, err
+	}
+	path, err := executablePath(options.Executable)
+	if err != nil {
+		return nil, err
+	}
+	if err := replaceExecutable(path, binary, goos); err != nil {
+		return nil, err
+	}
+	result.Path, result.Updated = path, true
+	return result, nil
+}
+
+// isNewer reports whether the target should be installed: a requested version whenever it
+// differs from the running one, otherwise a version newer than the running one. A
+// development build is older than every release.
+func isNewer(target Version, options Options) bool {
+	if IsDevelopmentBuild(options.CurrentVersion) {
+		return true
+	}
+	current, _ := ParseVersion(options.CurrentVersion)
+	if options.Version != "" {
+		return target.Compare(current) != 0
+	}
+	return target.Compare(current) > 0
+}
+
+// platform returns the operating system and architecture of the binary to install
+func platform(options Options) (string, string) {
+	goos, goarch := options.GOOS, options.GOARCH
+	if goos == "" {
+		goos = runtime.GOOS
+	}
+	if goarch == "" {
+		goarch = runtime.GOARCH
+	}
+	return goos, goarch
+}
+
+// findRelease returns the requested release, or the newest release of the channel
+func findRelease(ctx context.Context, client *http.Client, options Options) (*release, error) {
+	releasesURL := options.ReleasesURL
+	if releasesURL == "" {
+		releasesURL = DefaultReleasesURL
+	}
+	token := options.Token
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	var requested *Version
+	if options.Version != "" {
+		version, err := ParseVersion(options.Version)
+		if err != nil {
+			return nil, err
+		}
+		requested = &version
+	}
+
+	content, err := get(ctx, client, releasesURL+"?per_page=100", token, maxMetadataSize)
+	if err != nil {
+		return nil, err
+	}
+	var releases []release
+	if err := json.Unmarshal(content, &releases); err != nil {
+		return nil, fmt.Errorf("failed to decode releases: %w", err)
+	}
+
+	var newest *release
+	for i := range releases {
+		candidate := &releases[i]
+		version, err := ParseVersion(candidate.TagName)
+		if err != nil || candidate.Draft {
+			continue
+		}
+		candidate.version = version
+		if requested != nil {
+			if version.Compare(*requested) == 0 {
+				return candidate, nil
+			}
+			continue
+		}
+		if options.Channel == ChannelStable && (candidate.Prerelease || version.Prerelease != "") {
+			continue
+		}
+		if newest == nil || version.Compare(newest.version) > 0 {
+			newest = candidate
+		}
+	}
+	if requested != nil {
+		return nil, fmt.Errorf("%w: no release of version %s", ErrReleaseNotFound, requested)
+	}
+	if newest == nil {
+		return nil, fmt.Errorf("%w: no release on the %s channel", ErrReleaseNotFound, options.Channel)
+	}
+	return newest, nil
+}
+
+// archive returns the archive of a platform, named flowspec-cli-<version>-<os>-<arch>.tar.gz
+// as the release packages are, or flowspec-cli-<os>-<arch>.tar.gz. Windows archives of releases
+// packaged before the .exe suffix was dropped from their names are found as well.
+func (r *release) archive(goos, goarch string) (asset, bool) {
+	version := strings.TrimPrefix(r.TagName, "v")
+	names := []string{
+		fmt.Sprintf("flowspec-cli-%s-%s-%s.tar.gz", version, goos, goarch),
+		fmt.Sprintf("flowspec-cli-%s-%s.tar.gz", goos, goarch),
+	}
+	if goos == "windows" {
+		names = append(names, fmt.Sprintf("flowspec-cli-%s-%s-%s.exe.tar.gz", version, goos, goarch))
+	}
+	for _, name := range names {
+		for _, candidate := range r.Assets {
+			if candidate.Name == name {
+				return candidate, true
+			}
+		}
+	}
+	return asset{}, false
+}
+
+// download returns the content of an asset of the release
+func (r *release) download(ctx context.Context, client *http.Client, name string, limit int64) ([]byte, error) {
+	for _, candidate := range r.Assets {
+		if candidate.Name == name {
+			return get(ctx, client, candidate.URL, "", limit)
+		}
+	}
+	if name == signatureAsset {
+		return nil, fmt.Errorf("%w: release %s has no %s to verify with the public key", ErrVerification, r.TagName, name)
+	}
+	return nil, fmt.Errorf("%w: release %s has no %s", ErrReleaseNotFound, r.TagName, name)
+}
+
+// get returns the body of a successful response of at most limit bytes
+func get(ctx context.Context, client *http.Client, target, token string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", req.URL.Redacted(), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to download %s: %s", req.URL.Redacted(), resp.Status)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", req.URL.Redacted(), err)
+	}
+	if int64(len(content)) > limit {
+		return nil, fmt.Errorf("%s is larger than %d bytes", req.URL.Redacted(), limit)
+	}
+	return content, nil
+}
+
+// lookupChecksum returns the SHA-256 checksums.txt lists for a file, in sha256sum format
+func lookupChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%w: checksums.txt does not list %s", ErrVerification, name)
+}
+
+// verifySignature checks the Ed25519 signature of checksums.txt, given raw or base64 encoded
+func verifySignature(key ed25519.PublicKey, checksums, signature []byte) error {
+	if len(signature) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+		if err != nil {
+			return fmt.Errorf("%w: %s is neither a raw nor a base64 Ed25519 signature", ErrVerification, signatureAsset)
+		}
+		signature = decoded
+	}
+	if !ed25519.Verify(key, checksums, signature) {
+		return fmt.Errorf("%w: the signature of checksums.txt does not match the public key", ErrVerification)
+	}
+	return nil
+}
+
+// extractBinary returns the flowspec-cli binary of a release archive
+func extractBinary(archive []byte, goos string) ([]byte, error) {
+	name := "flowspec-cli"
+	if goos == "windows" {
+		name += ".exe"
+	}
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read release archive: %w", err)
+	}
+	defer gzipReader.Close()
+	reader := tar.NewReader(gzipReader)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: release archive has no %s", ErrReleaseNotFound, name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read release archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || filepath.Base(header.Name) != name {
+			continue
+		}
+		binary, err := io.ReadAll(io.LimitReader(reader, maxArchiveBytes+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read release archive: %w", err)
+		}
+		if len(binary) > maxArchiveBytes {
+			return nil, fmt.Errorf("%s of the release archive is larger than %d bytes", name, maxArchiveBytes)
+		}
+		return binary, nil
+	}
+}
+
+// executablePath returns the binary to replace, following symbolic links
+func executablePath(path string) (string, error) {
+	if path == "" {
+		executable, err := os.Executable()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate the running executable: %w", err)
+		}
+		path = executable
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the running executable: %w", err)
+	}
+	return resolved, nil
+}
+
+// replaceExecutable writes the new binary next to the old one and renames it over it, so an
+// interrupted update never leaves a partial binary. Windows cannot replace a running
+// executable, which is moved aside to <name>.old first and moved back when the new binary cannot
+// take its place.
+func replaceExecutable(path string, binary []byte, goos string) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), ".flowspec-cli-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to write the new binary next to %s: %w", path, err)
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(binary); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to write the new binary: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to write the new binary: %w", err)
+	}
+	if err := os.Chmod(temp.Name(), 0755); err != nil {
+		return fmt.Errorf("failed to make the new binary executable: %w", err)
+	}
+	old := ""
+	if goos == "windows" {
+		old = path + ".old"
+		os.Remove(old)
+		if err := rename(path, old); err != nil {
+			return fmt.Errorf("failed to move %s aside: %w", path, err)
+		}
+	}
+	if err := rename(temp.Name(), path); err != nil {
+		if old != "" {
+			if restoreErr := rename(old, path); restoreErr != nil {
+				return fmt.Errorf("failed to replace %s: %w; the previous binary is left at %s: %v", path, err, old, restoreErr)
+			}
+		}
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// rename moves files during an update; tests replace it to simulate failures
+var rename = os.Rename
@@ -0,0 +1,319 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// releaseServer serves a releases API and the assets of its releases
+type releaseServer struct {
+	*httptest.Server
+	assets   map[string][]byte
+	releases []map[string]interface{}
+}
+
+func newReleaseServer(t *testing.T) *releaseServer {
+	server := &releaseServer{assets: map[string][]byte{}}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/releases" {
+			json.NewEncoder(w).Encode(server.releases)
+			return
+		}
+		content, ok := server.assets[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(content)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// addRelease publishes a release whose linux/amd64 archive holds the binary, with
+// checksums.txt and, given a private key, checksums.txt.sig
+func (s *releaseServer) addRelease(t *testing.T, tag string, prerelease bool, binary string, key ed25519.PrivateKey) {
+	version := strings.TrimPrefix(tag, "v")
+	archiveName := fmt.Sprintf("flowspec-cli-%s-linux-amd64.tar.gz", version)
+	archive := buildArchive(t, "linux-amd64/flowspec-cli", binary)
+	sum := sha256.Sum256(archive)
+	checksums := []byte(fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), archiveName))
+
+	files := map[string][]byte{archiveName: archive, checksumsAsset: checksums}
+	if key != nil {
+		files[signatureAsset] = ed25519.Sign(key, checksums)
+	}
+	var assets []map[string]string
+	for name, content := range files {
+		path := "/download/" + tag + "/" + name
+		s.assets[path] = content
+		assets = append(assets, map[string]string{"name": name, "browser_download_url": s.URL + path})
+	}
+	s.releases = append(s.releases, map[string]interface{}{"tag_name": tag, "prerelease": prerelease, "assets": assets})
+}
+
+func buildArchive(t *testing.T, name, content string) []byte {
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+	_, err := tarWriter.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	return buffer.Bytes()
+}
+
+func writePublicKey(t *testing.T, key ed25519.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "flowspec-release.pub")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644))
+	return path
+}
+
+func writeExecutable(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "flowspec-cli")
+	require.NoError(t, os.WriteFile(path, []byte("old binary"), 0755))
+	return path
+}
+
+func (s *releaseServer) options(executable string) Options {
+	return Options{
+		CurrentVersion: "0.6.1",
+		ReleasesURL:    s.URL + "/releases",
+		Executable:     executable,
+		GOOS:           "linux",
+		GOARCH:         "amd64",
+		Client:         s.Client(),
+	}
+}
+
+func TestUpdateInstallsNewestStableRelease(t *testing.T) {
+	server := newReleaseServer(t)
+	server.addRelease(t, "v0.6.1", false, "0.6.1 binary", nil)
+	server.addRelease(t, "v0.7.0", false, "0.7.0 binary", nil)
+	server.addRelease(t, "v0.8.0-beta.1", true, "0.8.0-beta.1 binary", nil)
+	executable := writeExecutable(t)
+
+	result, err := Update(context.Background(), server.options(executable))
+	require.NoError(t, err)
+	assert.True(t, result.Updated)
+	assert.Equal(t, "0.7.0", result.Version)
+	assert.Equal(t, "flowspec-cli-0.7.0-linux-amd64.tar.gz", result.Asset)
+	assert.True(t, strings.HasPrefix(result.Checksum, "sha256:"))
+	assert.False(t, result.Signed)
+	assert.Equal(t, "Updated flowspec-cli 0.6.1 → 0.7.0 (checksum verified)\n", result.FormatHuman())
+
+	content, err := os.ReadFile(executable)
+	require.NoError(t, err)
+	assert.Equal(t, "0.7.0 binary", string(content))
+	info, err := os.Stat(executable)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+}
+
+func TestUpdateBetaChannelIncludesPrereleases(t *testing.T) {
+	server := newReleaseServer(t)
+	server.addRelease(t, "v0.7.0", false, "0.7.0 binary", nil)
+	server.addRelease(t, "v0.8.0-beta.1", true, "0.8.0-beta.1 binary", nil)
+
+	options := server.options(writeExecutable(t))
+	options.Channel = ChannelBeta
+	options.CheckOnly = true
+	result, err := Update(context.Background(), options)
+	require.NoError(t, err)
+	assert.Equal(t, "0.8.0-beta.1", result.Version)
+	assert.True(t, result.Available)
+	assert.False(t, result.Updated)
+	assert.Contains(t, result.FormatHuman(), "flowspec-cli 0.8.0-beta.1 is available on the beta channel")
+
+	options.Channel = "nightly"
+	_, err = Update(context.Background(), options)
+	assert.Error(t, err)
+}
+
+func TestUpdateUpToDate(t *testing.T) {
+	server := newReleaseServer(t)
+	server.addRelease(t, "v0.6.1", false, "0.6.1 binary", nil)
+	executable := writeExecutable(t)
+
+	result, err := Update(context.Background(), server.options(executable))
+	require.NoError(t, err)
+	assert.False(t, result.Available)
+	assert.False(t, result.Updated)
+	assert.Equal(t, "flowspec-cli 0.6.1 is up to date (stable channel)\n", result.FormatHuman())
+
+	content, err := os.ReadFile(executable)
+	require.NoError(t, err)
+	assert.Equal(t, "old binary", string(content))
+}
+
+func TestUpdatePinnedVersion(t *testing.T) {
+	server := newReleaseServer(t)
+	server.addRelease(t, "v0.5.0", false, "0.5.0 binary", nil)
+	server.addRelease(t, "v0.7.0", false, "0.7.0 binary", nil)
+	executable := writeExecutable(t)
+
+	options := server.options(executable)
+	options.Version = "0.5.0"
+	result, err := Update(context.Background(), options)
+	require.NoError(t, err)
+	assert.True(t, result.Updated, "an explicit version may downgrade")
+	content, err := os.ReadFile(executable)
+	require.NoError(t, err)
+	assert.Equal(t, "0.5.0 binary", string(content))
+
+	options.Version = "0.9.0"
+	_, err = Update(context.Background(), options)
+	assert.True(t, errors.Is(err, ErrReleaseNotFound))
+}
+
+func TestUpdateRejectsChecksumMismatch(t *testing.T) {
+	server := newReleaseServer(t)
+	server.addRelease(t, "v0.7.0", false, "0.7.0 binary", nil)
+	server.assets["/download/v0.7.0/flowspec-cli-0.7.0-linux-amd64.tar.gz"] = buildArchive(t, "linux-amd64/flowspec-cli", "tampered")
+	executable := writeExecutable(t)
+
+	_, err := Update(context.Background(), server.options(executable))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrVerification))
+	content, err := os.ReadFile(executable)
+	require.NoError(t, err)
+	assert.Equal(t, "old binary", string(content))
+}
+
+func TestUpdateVerifiesSignature(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	server := newReleaseServer(t)
+	server.addRelease(t, "v0.7.0", false, "0.7.0 binary", private)
+
+	options := server.options(writeExecutable(t))
+	options.PublicKey = writePublicKey(t, public)
+	result, err := Update(context.Background(), options)
+	require.NoError(t, err)
+	assert.True(t, result.Signed)
+	assert.Contains(t, result.FormatHuman(), "signature verified")
+
+	otherPublic, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	options = server.options(writeExecutable(t))
+	options.PublicKey = writePublicKey(t, otherPublic)
+	_, err = Update(context.Background(), options)
+	assert.True(t, errors.Is(err, ErrVerification))
+}
+
+func TestUpdateRequiresSignatureWithPublicKey(t *testing.T) {
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	server := newReleaseServer(t)
+	server.addRelease(t, "v0.7.0", false, "0.7.0 binary", nil)
+
+	options := server.options(writeExecutable(t))
+	options.PublicKey = writePublicKey(t, public)
+	_, err = Update(context.Background(), options)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrVerification))
+	assert.Contains(t, err.Error(), "no checksums.txt.sig")
+}
+
+func TestUpdateMissingPlatform(t *testing.T) {
+	server := newReleaseServer(t)
+	server.addRelease(t, "v0.7.0", false, "0.7.0 binary", nil)
+
+	options := server.options(writeExecutable(t))
+	options.GOARCH = "riscv64"
+	_, err := Update(context.Background(), options)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrReleaseNotFound))
+	assert.Contains(t, err.Error(), "linux/riscv64")
+}
+
+func TestReleaseArchiveWindows(t *testing.T) {
+	packaged := release{TagName: "v0.7.0", Assets: []asset{
+		{Name: "flowspec-cli-0.7.0-linux-amd64.tar.gz"},
+		{Name: "flowspec-cli-0.7.0-windows-amd64.tar.gz"},
+	}}
+	archive, ok := packaged.archive("windows", "amd64")
+	require.True(t, ok)
+	assert.Equal(t, "flowspec-cli-0.7.0-windows-amd64.tar.gz", archive.Name, "the name make package gives the archive")
+
+	legacy := release{TagName: "v0.6.0", Assets: []asset{{Name: "flowspec-cli-0.6.0-windows-amd64.exe.tar.gz"}}}
+	archive, ok = legacy.archive("windows", "amd64")
+	require.True(t, ok)
+	assert.Equal(t, "flowspec-cli-0.6.0-windows-amd64.exe.tar.gz", archive.Name)
+
+	_, ok = legacy.archive("linux", "amd64")
+	assert.False(t, ok)
+
+	binary, err := extractBinary(buildArchive(t, "windows-amd64/flowspec-cli.exe", "windows binary"), "windows")
+	require.NoError(t, err)
+	assert.Equal(t, "windows binary", string(binary))
+}
+
+func TestReplaceExecutableWindowsRestoresOnFailure(t *testing.T) {
+	path := writeExecutable(t)
+	t.Cleanup(func() { rename = os.Rename })
+	rename = func(from, to string) error {
+		if strings.Contains(filepath.Base(from), ".flowspec-cli-update-") {
+			return errors.New("access denied")
+		}
+		return os.Rename(from, to)
+	}
+
+	err := replaceExecutable(path, []byte("new binary"), "windows")
+	assert.ErrorContains(t, err, "access denied")
+	content, readErr := os.ReadFile(path)
+	require.NoError(t, readErr)
+	assert.Equal(t, "old binary", string(content), "the running binary is moved back")
+	assert.NoFileExists(t, path+".old")
+
+	rename = os.Rename
+	require.NoError(t, replaceExecutable(path, []byte("new binary"), "windows"))
+	content, readErr = os.ReadFile(path)
+	require.NoError(t, readErr)
+	assert.Equal(t, "new binary", string(content))
+	assert.FileExists(t, path+".old")
+}
+
+func TestUpdateOffline(t *testing.T) {
+	options := Options{CurrentVersion: "0.6.1", Network: &httpclient.Config{Offline: true}}
+	_, err := Update(context.Background(), options)
+	assert.ErrorIs(t, err, httpclient.ErrOffline)
+}
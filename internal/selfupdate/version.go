@@ -0,0 +1,219 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selfupdate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrVersionMismatch is matched by errors.Is when the running CLI does not satisfy the version
+// constraint of a configuration file
+var ErrVersionMismatch = errors.New("CLI version does not satisfy the required version")
+
+// Version is a semantic version; missing minor and patch numbers are zero
+type Version struct {
+	Major, Minor, Patch int
+	Prerelease          string // e.g. "beta.1"; a prerelease precedes its release
+}
+
+// ParseVersion parses versions such as 0.7.2, v1.0.0-beta.1 or 0.6; build metadata after a
+// "+" is ignored
+func ParseVersion(value string) (Version, error) {
+	text := strings.TrimPrefix(strings.TrimSpace(value), "v")
+	text, _, _ = strings.Cut(text, "+")
+	text, prerelease, _ := strings.Cut(text, "-")
+	parts := strings.Split(text, ".")
+	if len(parts) > 3 || text == "" {
+		return Version{}, fmt.Errorf("invalid version %q, expected <major>.<minor>.<patch>", value)
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 || part == "" || part[0] == '+' {
+			return Version{}, fmt.Errorf("invalid version %q, expected <major>.<minor>.<patch>", value)
+		}
+		numbers[i] = number
+	}
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2], Prerelease: prerelease}, nil
+}
+
+// String formats the version without a "v" prefix
+func (v Version) String() string {
+	text := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		text += "-" + v.Prerelease
+	}
+	return text
+}
+
+// Compare returns -1, 0 or 1 as v precedes, equals or follows other
+func (v Version) Compare(other Version) int {
+	for _, pair := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if pair[0] != pair[1] {
+			return compareInts(pair[0], pair[1])
+		}
+	}
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	}
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+// comparePrerelease compares dot-separated prerelease identifiers, numeric ones numerically
+func comparePrerelease(a, b string) int {
+	left, right := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(left) && i < len(right); i++ {
+		if left[i] == right[i] {
+			continue
+		}
+		leftNumber, leftErr := strconv.Atoi(left[i])
+		rightNumber, rightErr := strconv.Atoi(right[i])
+		switch {
+		case leftErr == nil && rightErr == nil:
+			return compareInts(leftNumber, rightNumber)
+		case leftErr == nil:
+			return -1
+		case rightErr == nil:
+			return 1
+		}
+		return strings.Compare(left[i], right[i])
+	}
+	return compareInts(len(left), len(right))
+}
+
+// compareInts returns -1, 0 or 1 as a is less than, equal to or greater than b
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// gitDescribeSuffix matches the suffix git describe adds to builds after a tag, e.g. -3-gabc1234
+var gitDescribeSuffix = regexp.MustCompile(`(^|-)[0-9]+-g[0-9a-f]+(-dirty)?$`)
+
+// IsDevelopmentBuild reports whether a version is not the one of a release: "dev", a
+// 0.1.0-dev fallback, or a git describe version of a commit after a tag or with local changes
+func IsDevelopmentBuild(value string) bool {
+	version, err := ParseVersion(value)
+	if err != nil {
+		return true
+	}
+	return version.Prerelease == "dev" || strings.HasSuffix(version.Prerelease, "dirty") ||
+		gitDescribeSuffix.MatchString(version.Prerelease)
+}
+
+// comparison is one term of a constraint, e.g. ">=0.6"
+type comparison struct {
+	operator string
+	version  Version
+}
+
+// Constraint is a set of comparisons a version must all satisfy, e.g. ">=0.6 <0.8"
+type Constraint struct {
+	text        string
+	comparisons []comparison
+}
+
+// constraintOperators are the supported operators, longest first so ">=" is not read as ">"
+var constraintOperators = []string{">=", "<=", "!=", ">", "<", "="}
+
+// ParseConstraint parses space- or comma-separated comparisons using >=, >, <=, <, = and !=;
+// a version without an operator must match exactly
+func ParseConstraint(text string) (*Constraint, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty version constraint")
+	}
+	constraint := &Constraint{text: strings.Join(fields, " ")}
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		operator := "="
+		for _, candidate := range constraintOperators {
+			if strings.HasPrefix(field, candidate) {
+				operator, field = candidate, strings.TrimPrefix(field, candidate)
+				break
+			}
+		}
+		// Allow a space between the operator and the version, e.g. ">= 0.6"
+		if field == "" && i+1 < len(fields) {
+			i++
+			field = fields[i]
+		}
+		version, err := ParseVersion(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", text, err)
+		}
+		constraint.comparisons = append(constraint.comparisons, comparison{operator: operator, version: version})
+	}
+	return constraint, nil
+}
+
+// String returns the constraint as written, normalized to single spaces
+func (c *Constraint) String() string {
+	return c.text
+}
+
+// Allows reports whether a version satisfies every comparison of the constraint
+func (c *Constraint) Allows(version Version) bool {
+	for _, term := range c.comparisons {
+		result := version.Compare(term.version)
+		var ok bool
+		switch term.operator {
+		case ">=":
+			ok = result >= 0
+		case ">":
+			ok = result > 0
+		case "<=":
+			ok = result <= 0
+		case "<":
+			ok = result < 0
+		case "!=":
+			ok = result != 0
+		default:
+			ok = result == 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Check returns an error matching ErrVersionMismatch when the running version does not satisfy
+// the constraint. Development builds always pass.
+func (c *Constraint) Check(current string) error {
+	if IsDevelopmentBuild(current) {
+		return nil
+	}
+	version, _ := ParseVersion(current)
+	if !c.Allows(version) {
+		return fmt.Errorf("%w: flowspec-cli %s does not satisfy %q; run flowspec-cli self-update --version <version> or install a matching release",
+			ErrVersionMismatch, version, c.text)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selfupdate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	version, err := ParseVersion("v0.7.2-beta.1")
+	require.NoError(t, err)
+	assert.Equal(t, Version{Major: 0, Minor: 7, Patch: 2, Prerelease: "beta.1"}, version)
+	assert.Equal(t, "0.7.2-beta.1", version.String())
+
+	version, err = ParseVersion("0.6")
+	require.NoError(t, err)
+	assert.Equal(t, "0.6.0", version.String())
+
+	_, err = ParseVersion("latest")
+	assert.Error(t, err)
+}
+
+func TestVersionCompare(t *testing.T) {
+	ordered := []string{"0.5.9", "0.6.0-beta.1", "0.6.0-beta.2", "0.6.0-beta.10", "0.6.0", "0.6.1", "1.0.0"}
+	for i := 1; i < len(ordered); i++ {
+		lower, err := ParseVersion(ordered[i-1])
+		require.NoError(t, err)
+		higher, err := ParseVersion(ordered[i])
+		require.NoError(t, err)
+		assert.Equal(t, -1, lower.Compare(higher), "%s < %s", ordered[i-1], ordered[i])
+		assert.Equal(t, 1, higher.Compare(lower), "%s > %s", ordered[i], ordered[i-1])
+	}
+}
+
+func TestIsDevelopmentBuild(t *testing.T) {
+	assert.True(t, IsDevelopmentBuild("0.1.0-dev"))
+	assert.True(t, IsDevelopmentBuild("v0.6.1-3-gabc1234"))
+	assert.True(t, IsDevelopmentBuild("v0.6.1-3-gabc1234-dirty"))
+	assert.True(t, IsDevelopmentBuild("abc1234"))
+	assert.False(t, IsDevelopmentBuild("0.6.1"))
+	assert.False(t, IsDevelopmentBuild("0.7.0-beta.1"))
+}
+
+func TestConstraint(t *testing.T) {
+	constraint, err := ParseConstraint(">=0.6 <0.8")
+	require.NoError(t, err)
+	assert.Equal(t, ">=0.6 <0.8", constraint.String())
+
+	testCases := map[string]bool{
+		"0.5.9": false,
+		"0.6.0": true,
+		"0.7.9": true,
+		"0.8.0": false,
+	}
+	for value, allowed := range testCases {
+		version, err := ParseVersion(value)
+		require.NoError(t, err)
+		assert.Equal(t, allowed, constraint.Allows(version), value)
+	}
+
+	constraint, err = ParseConstraint(">= 0.6, != 0.7.1")
+	require.NoError(t, err)
+	assert.False(t, constraint.Allows(Version{Minor: 7, Patch: 1}))
+	assert.True(t, constraint.Allows(Version{Minor: 7, Patch: 2}))
+
+	for _, invalid := range []string{"", "~0.6", ">=", ">=0.6 <latest"} {
+		_, err := ParseConstraint(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestConstraintCheck(t *testing.T) {
+	constraint, err := ParseConstraint(">=0.6 <0.8")
+	require.NoError(t, err)
+
+	assert.NoError(t, constraint.Check("0.7.3"))
+	assert.NoError(t, constraint.Check("0.1.0-dev"), "development builds are not pinned")
+
+	err = constraint.Check("0.9.0")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrVersionMismatch))
+	assert.Contains(t, err.Error(), `flowspec-cli 0.9.0 does not satisfy ">=0.6 <0.8"`)
+}
@@ -0,0 +1,354 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package server runs FlowSpec verification as a shared HTTP service (flowspec-cli serve)
+package server
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/engine"
+	"github.com/flowspec/flowspec-cli/internal/ingestor"
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/parser"
+	"github.com/flowspec/flowspec-cli/internal/renderer"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultMaxUploadBytes limits the size of a verification request
+const DefaultMaxUploadBytes = 64 << 20
+
+// Config holds the server settings
+type Config struct {
+	Addr           string // Listen address, e.g. ":8080"
+	DataDir        string // Directory holding stored contracts and results
+	Token          string // Bearer token required on API requests; empty disables authentication
+	MaxUploadBytes int64
+	Engine         *engine.EngineConfig // Engine settings for every verification; defaults when nil
+}
+
+// DefaultConfig returns a default server configuration
+func DefaultConfig() Config {
+	return Config{
+		Addr:           ":8080",
+		DataDir:        "flowspec-data",
+		MaxUploadBytes: DefaultMaxUploadBytes,
+	}
+}
+
+// Server serves the verification API
+type Server struct {
+	config Config
+	store  *Store
+	mux    *http.ServeMux
+}
+
+// NewServer creates a server storing its data in config.DataDir
+func NewServer(config Config) (*Server, error) {
+	if config.DataDir == "" {
+		return nil, fmt.Errorf("data directory is required")
+	}
+	if config.MaxUploadBytes <= 0 {
+		config.MaxUploadBytes = DefaultMaxUploadBytes
+	}
+	if config.Engine == nil {
+		config.Engine = engine.DefaultEngineConfig()
+	}
+	store, err := NewStore(config.DataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{config: config, store: store, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	s.mux.HandleFunc("GET /api/v1/contracts", s.authenticated(s.handleListContracts))
+	s.mux.HandleFunc("GET /api/v1/contracts/{name}", s.authenticated(s.handleGetContract))
+	s.mux.HandleFunc("PUT /api/v1/contracts/{name}", s.authenticated(s.handlePutContract))
+	s.mux.HandleFunc("DELETE /api/v1/contracts/{name}", s.authenticated(s.handleDeleteContract))
+	s.mux.HandleFunc("POST /api/v1/verify", s.authenticated(s.handleVerify))
+	s.mux.HandleFunc("GET /api/v1/results", s.authenticated(s.handleListResults))
+	s.mux.HandleFunc("GET /api/v1/results/{id}", s.authenticated(s.handleGetResult))
+	return s, nil
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServe serves the API on the configured address
+func (s *Server) ListenAndServe() error {
+	server := &http.Server{
+		Addr:              s.config.Addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	logrus.WithFields(logrus.Fields{"addr": s.config.Addr, "data": s.config.DataDir}).Info("FlowSpec server listening")
+	return server.ListenAndServe()
+}
+
+// authenticated requires the configured bearer token
+func (s *Server) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.config.Token != "" {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(token), []byte(s.config.Token)) != 1 {
+				writeError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid bearer token"))
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleListContracts(w http.ResponseWriter, r *http.Request) {
+	contracts, err := s.store.Contracts()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, contracts)
+}
+
+func (s *Server) handleGetContract(w http.ResponseWriter, r *http.Request) {
+	content, err := s.store.Contract(r.PathValue("name"))
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(content)
+}
+
+func (s *Server) handlePutContract(w http.ResponseWriter, r *http.Request) {
+	content, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.config.MaxUploadBytes))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("failed to read contract: %w", err))
+		return
+	}
+	// Only contracts that parse are stored, so verifications against them cannot fail on format
+	if _, err := parseContract(content); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	info, err := s.store.SaveContract(r.PathValue("name"), content)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, info)
+}
+
+func (s *Server) handleDeleteContract(w http.ResponseWriter, r *http.Request) {
+	if err := s.store.DeleteContract(r.PathValue("name")); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleVerify verifies a trace against an uploaded or stored contract. The request is a
+// multipart form with a "trace" file and either a "contract" file or a "contractName" field.
+func (s *Server) handleVerify(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.config.MaxUploadBytes)
+	if err := r.ParseMultipartForm(s.config.MaxUploadBytes); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("expected a multipart form: %w", err))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	name := r.FormValue("contractName")
+	var contract []byte
+	var err error
+	if name != "" {
+		contract, err = s.store.Contract(name)
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+	} else if contract, err = formFile(r, "contract"); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	trace, err := formFile(r, "trace")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	specs, err := parseContract(contract)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	traceData, err := parseTrace(trace)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	engineConfig := *s.config.Engine
+	alignmentEngine := engine.NewAlignmentEngineWithConfig(&engineConfig)
+	defer alignmentEngine.Close()
+	report, err := alignmentEngine.AlignSpecsWithTraceContext(r.Context(), specs, traceData)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("verification failed: %w", err))
+		return
+	}
+
+	result := Result{
+		Contract:  name,
+		CreatedAt: time.Now().UTC(),
+		ExitCode:  renderer.NewReportRenderer().GetExitCode(report),
+		Summary:   report.Summary,
+		Report:    report,
+	}
+	if r.FormValue("store") != "false" {
+		if result.ID, err = s.store.SaveResult(&result); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		w.Header().Set("Location", "/api/v1/results/"+result.ID)
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (s *Server) handleListResults(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("limit must be a positive integer"))
+			return
+		}
+		limit = parsed
+	}
+	results, err := s.store.Results(r.URL.Query().Get("contract"), limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+func (s *Server) handleGetResult(w http.ResponseWriter, r *http.Request) {
+	result, err := s.store.Result(r.PathValue("id"))
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// formFile reads an uploaded file of the multipart form
+func formFile(r *http.Request, field string) ([]byte, error) {
+	file, _, err := r.FormFile(field)
+	if err != nil {
+		return nil, fmt.Errorf("missing %q file: %w", field, err)
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// parseContract parses contract YAML with the same parser as the CLI, except that $ref and
+// include may not reach files on the server
+func parseContract(content []byte) ([]models.ServiceSpec, error) {
+	path, cleanup, err := tempFile("contract-*.yaml", content)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	specs, parseErrors := parser.NewSandboxedYAMLFileParser().ParseFile(path)
+	if len(parseErrors) > 0 {
+		messages := make([]string, 0, len(parseErrors))
+		for _, parseError := range parseErrors {
+			messages = append(messages, parseError.Message)
+		}
+		return nil, fmt.Errorf("invalid contract: %s", strings.Join(messages, "; "))
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("invalid contract: no ServiceSpec found")
+	}
+	return specs, nil
+}
+
+// parseTrace ingests OTLP trace JSON with the same ingestor as the CLI
+func parseTrace(content []byte) (*models.TraceData, error) {
+	traceData, err := ingestor.NewTraceIngestor().IngestFromReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid trace: %w", err)
+	}
+	return traceData, nil
+}
+
+// tempFile writes content to a temporary file for the file-based contract parser, and returns a function removing it
+func tempFile(pattern string, content []byte) (string, func(), error) {
+	file, err := os.CreateTemp("", "flowspec-"+pattern)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	path := file.Name()
+	cleanup := func() { os.Remove(path) }
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		cleanup()
+		return "", nil, fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	return path, cleanup, nil
+}
+
+// writeJSON writes value as a JSON response
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(value)
+}
+
+// writeError writes an error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// writeStoreError maps store errors to HTTP statuses
+func writeStoreError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, ErrInvalidName):
+		writeError(w, http.StatusBadRequest, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testContract = `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: health-service
+  version: v1.0.0
+spec:
+  endpoints:
+    - path: /health
+      operations:
+        - method: GET
+          responses:
+            statusCodes: [200]
+            aggregation: "exact"
+`
+
+const testTrace = `{
+  "resourceSpans": [{
+    "resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "health-service"}}]},
+    "scopeSpans": [{
+      "spans": [{
+        "traceId": "1234567890abcdef1234567890abcdef",
+        "spanId": "abcdef1234567890",
+        "name": "health",
+        "kind": "SPAN_KIND_SERVER",
+        "startTimeUnixNano": "1722508215000000000",
+        "endTimeUnixNano": "1722508215001000000",
+        "status": {"code": "STATUS_CODE_OK"},
+        "attributes": [
+          {"key": "http.method", "value": {"stringValue": "GET"}},
+          {"key": "http.target", "value": {"stringValue": "/health"}},
+          {"key": "http.status_code", "value": {"intValue": 200}}
+        ]
+      }]
+    }]
+  }]
+}`
+
+func newTestServer(t *testing.T, token string) *Server {
+	config := DefaultConfig()
+	config.DataDir = t.TempDir()
+	config.Token = token
+	server, err := NewServer(config)
+	require.NoError(t, err)
+	return server
+}
+
+// do sends a request to the server and returns the recorded response
+func do(server *Server, method, target string, body io.Reader, contentType string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, body)
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// verifyForm builds a multipart verification request
+func verifyForm(t *testing.T, fields map[string]string, files map[string]string) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+	for name, content := range files {
+		part, err := writer.CreateFormFile(name, name)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return &body, writer.FormDataContentType()
+}
+
+func TestServer_VerifyUploadedContract(t *testing.T) {
+	server := newTestServer(t, "")
+
+	body, contentType := verifyForm(t, nil, map[string]string{"contract": testContract, "trace": testTrace})
+	response := do(server, http.MethodPost, "/api/v1/verify", body, contentType)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	var report models.AlignmentReport
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &report))
+	assert.Equal(t, 1, report.Summary.Total)
+	require.NotNil(t, report.Summary.OperationSummary)
+	assert.Equal(t, 1, report.Summary.OperationSummary.SuccessOperations)
+
+	location := response.Header().Get("Location")
+	require.True(t, strings.HasPrefix(location, "/api/v1/results/"))
+
+	response = do(server, http.MethodGet, location, nil, "")
+	require.Equal(t, http.StatusOK, response.Code)
+	var result Result
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &result))
+	assert.Equal(t, report.Summary.Total, result.Summary.Total)
+	assert.NotNil(t, result.Report)
+}
+
+func TestServer_StoredContracts(t *testing.T) {
+	server := newTestServer(t, "")
+
+	response := do(server, http.MethodPut, "/api/v1/contracts/health", strings.NewReader("not: [valid"), "application/yaml")
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+
+	response = do(server, http.MethodPut, "/api/v1/contracts/health", strings.NewReader(testContract), "application/yaml")
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	response = do(server, http.MethodGet, "/api/v1/contracts", nil, "")
+	var contracts []ContractInfo
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &contracts))
+	require.Len(t, contracts, 1)
+	assert.Equal(t, "health", contracts[0].Name)
+
+	response = do(server, http.MethodGet, "/api/v1/contracts/health", nil, "")
+	assert.Equal(t, testContract, response.Body.String())
+
+	// Verify twice against the stored contract and once against an upload
+	for i := 0; i < 2; i++ {
+		body, contentType := verifyForm(t, map[string]string{"contractName": "health"}, map[string]string{"trace": testTrace})
+		response = do(server, http.MethodPost, "/api/v1/verify", body, contentType)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	}
+	body, contentType := verifyForm(t, nil, map[string]string{"contract": testContract, "trace": testTrace})
+	require.Equal(t, http.StatusOK, do(server, http.MethodPost, "/api/v1/verify", body, contentType).Code)
+
+	response = do(server, http.MethodGet, "/api/v1/results?contract=health", nil, "")
+	var results []Result
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &results))
+	require.Len(t, results, 2)
+	assert.Greater(t, results[0].ID, results[1].ID, "newest first")
+	assert.Nil(t, results[0].Report, "listings omit reports")
+
+	response = do(server, http.MethodGet, "/api/v1/results?limit=1", nil, "")
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &results))
+	assert.Len(t, results, 1)
+
+	assert.Equal(t, http.StatusNoContent, do(server, http.MethodDelete, "/api/v1/contracts/health", nil, "").Code)
+	assert.Equal(t, http.StatusNotFound, do(server, http.MethodGet, "/api/v1/contracts/health", nil, "").Code)
+}
+
+func TestServer_Errors(t *testing.T) {
+	server := newTestServer(t, "")
+
+	testCases := []struct {
+		name   string
+		method string
+		target string
+		status int
+	}{
+		{"unknown contract", http.MethodGet, "/api/v1/contracts/missing", http.StatusNotFound},
+		{"invalid contract name", http.MethodGet, "/api/v1/contracts/..secret", http.StatusBadRequest},
+		{"unknown result", http.MethodGet, "/api/v1/results/00000000000000000001", http.StatusNotFound},
+		{"invalid result id", http.MethodGet, "/api/v1/results/abc", http.StatusBadRequest},
+		{"invalid limit", http.MethodGet, "/api/v1/results?limit=0", http.StatusBadRequest},
+		{"verify without form", http.MethodPost, "/api/v1/verify", http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response := do(server, tc.method, tc.target, nil, "")
+			assert.Equal(t, tc.status, response.Code)
+			assert.Contains(t, response.Body.String(), `"error"`)
+		})
+	}
+
+	body, contentType := verifyForm(t, nil, map[string]string{"contract": testContract})
+	response := do(server, http.MethodPost, "/api/v1/verify", body, contentType)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Body.String(), "trace")
+}
+
+func TestServer_ContractFileReferences(t *testing.T) {
+	server := newTestServer(t, "")
+	secret := filepath.Join(t.TempDir(), "secret.yaml")
+	require.NoError(t, os.WriteFile(secret, []byte("responses:\n  ok:\n    statusCodes: [200]\n"), 0644))
+	contract := strings.Replace(testContract, "statusCodes: [200]\n            aggregation: \"exact\"",
+		"$ref: \""+secret+"#/responses/ok\"", 1)
+
+	response := do(server, http.MethodPut, "/api/v1/contracts/health", strings.NewReader(contract), "application/yaml")
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	assert.Contains(t, response.Body.String(), "points at another file")
+
+	body, contentType := verifyForm(t, nil, map[string]string{"contract": contract, "trace": testTrace})
+	response = do(server, http.MethodPost, "/api/v1/verify", body, contentType)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	assert.Contains(t, response.Body.String(), "points at another file")
+}
+
+func TestServer_VerifyCanceled(t *testing.T) {
+	server := newTestServer(t, "")
+	body, contentType := verifyForm(t, nil, map[string]string{"contract": testContract, "trace": testTrace})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/verify", body).WithContext(ctx)
+	request.Header.Set("Content-Type", contentType)
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	assert.Contains(t, response.Body.String(), "canceled")
+}
+
+func TestStore_ConcurrentSaves(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := store.SaveContract("health", []byte(strings.Repeat(strconv.Itoa(i), 1<<16)))
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	content, err := store.Contract("health")
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat(string(content[:1]), 1<<16), string(content), "one upload wins whole")
+	contracts, err := store.Contracts()
+	require.NoError(t, err)
+	assert.Len(t, contracts, 1)
+}
+
+func TestServer_Token(t *testing.T) {
+	server := newTestServer(t, "secret")
+
+	assert.Equal(t, http.StatusOK, do(server, http.MethodGet, "/healthz", nil, "").Code, "health checks need no token")
+	assert.Equal(t, http.StatusUnauthorized, do(server, http.MethodGet, "/api/v1/contracts", nil, "").Code)
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/contracts", nil)
+	request.Header.Set("Authorization", "Bearer secret")
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "[]\n", recorder.Body.String())
+}
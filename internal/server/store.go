@@ -0,0 +1,243 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// Store errors
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrInvalidName = errors.New("invalid name")
+)
+
+// validName restricts contract names and result IDs to safe file names
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// ContractInfo describes a stored contract
+type ContractInfo struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Result is a stored verification result
+type Result struct {
+	ID        string                  `json:"id"`
+	Contract  string                  `json:"contract,omitempty"` // Stored contract name; empty for uploaded contracts
+	CreatedAt time.Time               `json:"createdAt"`
+	ExitCode  int                     `json:"exitCode"`
+	Summary   models.AlignmentSummary `json:"summary"`
+	Report    *models.AlignmentReport `json:"report,omitempty"`
+}
+
+// Store keeps contracts and results in a directory
+type Store struct {
+	contractsDir string
+	resultsDir   string
+	mu           sync.Mutex
+	lastID       int64
+}
+
+// NewStore creates the store directories in dir
+func NewStore(dir string) (*Store, error) {
+	store := &Store{
+		contractsDir: filepath.Join(dir, "contracts"),
+		resultsDir:   filepath.Join(dir, "results"),
+	}
+	for _, path := range []string{store.contractsDir, store.resultsDir} {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create data directory %s: %w", path, err)
+		}
+	}
+	return store, nil
+}
+
+// contractPath returns the file of a contract, validating its name
+func (s *Store) contractPath(name string) (string, error) {
+	if !validName.MatchString(name) {
+		return "", fmt.Errorf("%w %q: use letters, digits, '.', '_' and '-'", ErrInvalidName, name)
+	}
+	return filepath.Join(s.contractsDir, name+".yaml"), nil
+}
+
+// Contracts lists the stored contracts by name
+func (s *Store) Contracts() ([]ContractInfo, error) {
+	entries, err := os.ReadDir(s.contractsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contracts: %w", err)
+	}
+	contracts := []ContractInfo{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		contracts = append(contracts, ContractInfo{
+			Name:      strings.TrimSuffix(entry.Name(), ".yaml"),
+			Size:      info.Size(),
+			UpdatedAt: info.ModTime().UTC(),
+		})
+	}
+	return contracts, nil
+}
+
+// Contract returns the content of a stored contract
+func (s *Store) Contract(name string) ([]byte, error) {
+	path, err := s.contractPath(name)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("contract %q %w", name, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contract %q: %w", name, err)
+	}
+	return content, nil
+}
+
+// SaveContract stores or replaces a contract
+func (s *Store) SaveContract(name string, content []byte) (ContractInfo, error) {
+	path, err := s.contractPath(name)
+	if err != nil {
+		return ContractInfo{}, err
+	}
+	// Write a private temporary file then rename, so concurrent verifications never read a
+	// partial contract and concurrent uploads never write the same file
+	temporary, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return ContractInfo{}, fmt.Errorf("failed to write contract %q: %w", name, err)
+	}
+	_, err = temporary.Write(content)
+	if closeErr := temporary.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(temporary.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(temporary.Name(), path)
+	}
+	if err != nil {
+		os.Remove(temporary.Name())
+		return ContractInfo{}, fmt.Errorf("failed to write contract %q: %w", name, err)
+	}
+	return ContractInfo{Name: name, Size: int64(len(content)), UpdatedAt: time.Now().UTC()}, nil
+}
+
+// DeleteContract removes a stored contract; its results are kept
+func (s *Store) DeleteContract(name string) error {
+	path, err := s.contractPath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); os.IsNotExist(err) {
+		return fmt.Errorf("contract %q %w", name, ErrNotFound)
+	} else if err != nil {
+		return fmt.Errorf("failed to delete contract %q: %w", name, err)
+	}
+	return nil
+}
+
+// SaveResult stores a result under a new, time-ordered ID and returns the ID
+func (s *Store) SaveResult(result *Result) (string, error) {
+	s.mu.Lock()
+	id := result.CreatedAt.UnixNano()
+	if id <= s.lastID {
+		id = s.lastID + 1
+	}
+	s.lastID = id
+	s.mu.Unlock()
+
+	result.ID = fmt.Sprintf("%020d", id)
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal result: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.resultsDir, result.ID+".json"), data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write result: %w", err)
+	}
+	return result.ID, nil
+}
+
+// Result loads a stored result with its report
+func (s *Store) Result(id string) (*Result, error) {
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return nil, fmt.Errorf("%w %q", ErrInvalidName, id)
+	}
+	data, err := os.ReadFile(filepath.Join(s.resultsDir, id+".json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("result %q %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read result %q: %w", id, err)
+	}
+	var result Result
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse result %q: %w", id, err)
+	}
+	return &result, nil
+}
+
+// Results lists up to limit results, newest first, without their reports. A non-empty
+// contract keeps only the results of that stored contract.
+func (s *Store) Results(contract string, limit int) ([]Result, error) {
+	entries, err := os.ReadDir(s.resultsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list results: %w", err)
+	}
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			ids = append(ids, strings.TrimSuffix(entry.Name(), ".json"))
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+
+	results := []Result{}
+	for _, id := range ids {
+		if limit > 0 && len(results) >= limit {
+			break
+		}
+		result, err := s.Result(id)
+		if err != nil {
+			continue // Skip files that are not results
+		}
+		if contract != "" && result.Contract != contract {
+			continue
+		}
+		result.Report = nil
+		results = append(results, *result)
+	}
+	return results, nil
+}
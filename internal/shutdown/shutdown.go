@@ -0,0 +1,92 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shutdown turns SIGINT and SIGTERM into context cancellation, so long explore and
+// verify runs can stop their workers and write partial results instead of losing them
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// ErrInterrupted matches the cancellation cause of a context cancelled by a signal
+var ErrInterrupted = errors.New("interrupted")
+
+// SignalError is the cancellation cause recorded when a signal stops the run
+type SignalError struct {
+	Signal os.Signal
+}
+
+func (e *SignalError) Error() string {
+	return "interrupted by " + signalName(e.Signal)
+}
+
+// Is makes errors.Is(err, ErrInterrupted) hold
+func (e *SignalError) Is(target error) bool {
+	return target == ErrInterrupted
+}
+
+// NotifyContext returns a context cancelled on the first SIGINT or SIGTERM, with a SignalError
+// as its cause. The default handling is restored afterwards, so a second signal terminates the
+// process immediately when flushing partial results takes too long.
+func NotifyContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case received := <-signals:
+			signal.Stop(signals)
+			cancel(&SignalError{Signal: received})
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel(context.Canceled)
+	}
+}
+
+// Interrupted reports whether ctx was cancelled by a signal
+func Interrupted(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrInterrupted)
+}
+
+// WriteIncompleteNotice writes the YAML comment that marks a contract generated from part of
+// the traffic
+func WriteIncompleteNotice(w io.Writer, reason string, records int, at time.Time) error {
+	_, err := fmt.Fprintf(w, "# INCOMPLETE: %s at %s after %d records; regenerate from the full traffic\n",
+		reason, at.UTC().Format(time.RFC3339), records)
+	return err
+}
+
+func signalName(received os.Signal) string {
+	switch received {
+	case os.Interrupt:
+		return "SIGINT"
+	case syscall.SIGTERM:
+		return "SIGTERM"
+	default:
+		return received.String()
+	}
+}
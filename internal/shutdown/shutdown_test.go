@@ -0,0 +1,63 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shutdown
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyContext_Signal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sending SIGINT to the own process is not supported on Windows")
+	}
+	ctx, stop := NotifyContext(context.Background())
+	defer stop()
+
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, process.Signal(os.Interrupt))
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context not cancelled by SIGINT")
+	}
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	assert.True(t, Interrupted(ctx))
+	assert.EqualError(t, context.Cause(ctx), "interrupted by SIGINT")
+}
+
+func TestNotifyContext_Stop(t *testing.T) {
+	ctx, stop := NotifyContext(context.Background())
+	stop()
+
+	<-ctx.Done()
+	assert.False(t, Interrupted(ctx))
+}
+
+func TestWriteIncompleteNotice(t *testing.T) {
+	var output strings.Builder
+	at := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, WriteIncompleteNotice(&output, "interrupted by SIGTERM", 1500, at))
+	assert.Equal(t, "# INCOMPLETE: interrupted by SIGTERM at 2025-08-10T12:00:00Z after 1500 records; regenerate from the full traffic\n", output.String())
+}
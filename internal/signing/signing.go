@@ -0,0 +1,342 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package signing signs contract files with Ed25519 and verifies their detached signatures, so
+// CI can check that verification runs against a reviewed, untampered contract.
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/parser"
+)
+
+// Algorithm is the signature algorithm of contract signatures
+const Algorithm = "ed25519"
+
+// SignatureSuffix is appended to a contract file name to name its detached signature
+const SignatureSuffix = ".sig"
+
+var (
+	// ErrMissingSignature is returned when a contract has no signature file
+	ErrMissingSignature = errors.New("contract is not signed")
+	// ErrKeyMismatch is returned when a contract was signed with another key
+	ErrKeyMismatch = errors.New("contract was signed with another key")
+	// ErrInvalidSignature is returned when a contract changed after it was signed
+	ErrInvalidSignature = errors.New("contract signature does not match its content")
+)
+
+// Signature is the detached signature of a contract file, stored next to it as <file>.sig
+type Signature struct {
+	Algorithm string `json:"algorithm"`
+	KeyID     string `json:"keyId"`     // Fingerprint of the public key, see KeyID
+	Digest    string `json:"digest"`    // sha256:<hex> of the signed contract, to tell changed content from a wrong key
+	Signature string `json:"signature"` // Base64 Ed25519 signature of the contract bytes
+}
+
+// GenerateKeyPair returns a new key pair as PEM: the public key as PKIX and the private key as
+// PKCS #8
+func GenerateKeyPair() (publicPEM, privatePEM []byte, err error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode public key: %w", err)
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode private key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), nil
+}
+
+// ParsePublicKey parses a PEM encoded Ed25519 public key
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("not a PEM encoded public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	public, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is not an %s key", Algorithm)
+	}
+	return public, nil
+}
+
+// ParsePrivateKey parses a PEM encoded Ed25519 private key
+func ParsePrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("not a PEM encoded private key")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	private, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an %s key", Algorithm)
+	}
+	return private, nil
+}
+
+// LoadPublicKey reads a PEM encoded Ed25519 public key file
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+	key, err := ParsePublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// LoadPrivateKey reads a PEM encoded Ed25519 private key file
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+	key, err := ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// KeyID returns the fingerprint of a public key: the first 16 hex digits of its SHA-256
+func KeyID(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// Sign signs the content of a contract
+func Sign(contract []byte, key ed25519.PrivateKey) *Signature {
+	return &Signature{
+		Algorithm: Algorithm,
+		KeyID:     KeyID(key.Public().(ed25519.PublicKey)),
+		Digest:    digest(contract),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, contract)),
+	}
+}
+
+// Verify checks that signature was made with key over the content of a contract
+func Verify(contract []byte, signature *Signature, key ed25519.PublicKey) error {
+	if signature.Algorithm != Algorithm {
+		return fmt.Errorf("unsupported signature algorithm %q", signature.Algorithm)
+	}
+	if signature.KeyID != KeyID(key) {
+		return fmt.Errorf("%w: signed with %s, verified with %s", ErrKeyMismatch, signature.KeyID, KeyID(key))
+	}
+	raw, err := base64.StdEncoding.DecodeString(signature.Signature)
+	if err != nil {
+		return fmt.Errorf("malformed signature: %w", err)
+	}
+	if !ed25519.Verify(key, contract, raw) {
+		if signature.Digest != digest(contract) {
+			return fmt.Errorf("%w: the contract changed after it was signed", ErrInvalidSignature)
+		}
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// SignaturePath returns the path of the detached signature of a contract file
+func SignaturePath(path string) string {
+	return path + SignatureSuffix
+}
+
+// SignFile signs a contract file and writes its detached signature next to it, returning the
+// signature path
+func SignFile(path string, key ed25519.PrivateKey) (string, error) {
+	contract, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read contract: %w", err)
+	}
+	data, err := json.MarshalIndent(Sign(contract, key), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode signature: %w", err)
+	}
+	signaturePath := SignaturePath(path)
+	if err := os.WriteFile(signaturePath, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write signature: %w", err)
+	}
+	return signaturePath, nil
+}
+
+// VerifyFile checks the detached signature of a contract file
+func VerifyFile(path string, key ed25519.PublicKey) error {
+	contract, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%s: failed to read contract: %w", path, err)
+	}
+	data, err := os.ReadFile(SignaturePath(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s: %w, expected %s", path, ErrMissingSignature, SignaturePath(path))
+	}
+	if err != nil {
+		return fmt.Errorf("%s: failed to read signature: %w", path, err)
+	}
+	var signature Signature
+	if err := json.Unmarshal(data, &signature); err != nil {
+		return fmt.Errorf("%s: malformed signature file: %w", path, err)
+	}
+	if err := Verify(contract, &signature, key); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// ContractFiles returns the contract files of a path: the file itself, or every YAML and JSON
+// file of a directory and its subdirectories, followed by the files they pull in with $ref and
+// include wherever those are, so contracts composed of several files are covered too
+func ContractFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return withReferencedFiles([]string{path})
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if file != path && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".yaml", ".yml", ".json":
+			files = append(files, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return withReferencedFiles(files)
+}
+
+// withReferencedFiles appends the files the YAML contracts of files reference that are not
+// listed yet, sorted
+func withReferencedFiles(files []string) ([]string, error) {
+	listed := make(map[string]bool, len(files))
+	for _, file := range files {
+		absFile, err := filepath.Abs(file)
+		if err != nil {
+			return nil, err
+		}
+		listed[absFile] = true
+	}
+
+	var referenced []string
+	for _, file := range files {
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".yaml", ".yml":
+		default:
+			continue
+		}
+		// A file whose references do not resolve is signed on its own; parsing the contract
+		// reports the broken reference
+		targets, err := parser.ReferencedFiles(file)
+		if err != nil {
+			continue
+		}
+		for _, target := range targets {
+			if !listed[target] {
+				listed[target] = true
+				referenced = append(referenced, target)
+			}
+		}
+	}
+	sort.Strings(referenced)
+	return append(files, referenced...), nil
+}
+
+// SignPath signs the contract files of a path and returns the signature paths
+func SignPath(path string, key ed25519.PrivateKey) ([]string, error) {
+	files, err := ContractFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no contract files found in %s", path)
+	}
+	signatures := make([]string, 0, len(files))
+	for _, file := range files {
+		signaturePath, err := SignFile(file, key)
+		if err != nil {
+			return signatures, fmt.Errorf("%s: %w", file, err)
+		}
+		signatures = append(signatures, signaturePath)
+	}
+	return signatures, nil
+}
+
+// VerifyPath checks the signatures of every contract file of a path and returns the verified
+// files; all failures are reported together
+func VerifyPath(path string, key ed25519.PublicKey) ([]string, error) {
+	files, err := ContractFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no contract files found in %s", path)
+	}
+	var verified []string
+	var errs []error
+	for _, file := range files {
+		if err := VerifyFile(file, key); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		verified = append(verified, file)
+	}
+	return verified, errors.Join(errs...)
+}
+
+// digest returns the SHA-256 digest of content as sha256:<hex>
+func digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
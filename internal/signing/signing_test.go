@@ -0,0 +1,181 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeKeyPair writes a new key pair to dir and returns the public and private key paths
+func writeKeyPair(t *testing.T, dir string) (string, string) {
+	publicPEM, privatePEM, err := GenerateKeyPair()
+	require.NoError(t, err)
+	publicPath, privatePath := filepath.Join(dir, "flowspec.pub"), filepath.Join(dir, "flowspec.key")
+	require.NoError(t, os.WriteFile(publicPath, publicPEM, 0644))
+	require.NoError(t, os.WriteFile(privatePath, privatePEM, 0600))
+	return publicPath, privatePath
+}
+
+func TestSignAndVerify(t *testing.T) {
+	dir := t.TempDir()
+	publicPath, privatePath := writeKeyPair(t, t.TempDir())
+	private, err := LoadPrivateKey(privatePath)
+	require.NoError(t, err)
+	public, err := LoadPublicKey(publicPath)
+	require.NoError(t, err)
+
+	contract := filepath.Join(dir, "service-spec.yaml")
+	require.NoError(t, os.WriteFile(contract, []byte("apiVersion: flowspec/v1alpha1\nkind: ServiceSpec\n"), 0644))
+	signaturePath, err := SignFile(contract, private)
+	require.NoError(t, err)
+	assert.Equal(t, contract+".sig", signaturePath)
+	assert.NoError(t, VerifyFile(contract, public))
+
+	require.NoError(t, os.WriteFile(contract, []byte("apiVersion: flowspec/v1alpha1\nkind: ServiceSpec\n# edited\n"), 0644))
+	err = VerifyFile(contract, public)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	assert.Contains(t, err.Error(), "changed after it was signed")
+
+	otherPublic, _ := writeKeyPair(t, t.TempDir())
+	other, err := LoadPublicKey(otherPublic)
+	require.NoError(t, err)
+	assert.ErrorIs(t, VerifyFile(contract, other), ErrKeyMismatch)
+
+	require.NoError(t, os.Remove(signaturePath))
+	assert.ErrorIs(t, VerifyFile(contract, public), ErrMissingSignature)
+}
+
+func TestVerify_TamperedSignature(t *testing.T) {
+	publicPEM, privatePEM, err := GenerateKeyPair()
+	require.NoError(t, err)
+	public, err := ParsePublicKey(publicPEM)
+	require.NoError(t, err)
+	private, err := ParsePrivateKey(privatePEM)
+	require.NoError(t, err)
+
+	signature := Sign([]byte("contract"), private)
+	assert.Equal(t, Algorithm, signature.Algorithm)
+	assert.Equal(t, KeyID(public), signature.KeyID)
+	assert.NoError(t, Verify([]byte("contract"), signature, public))
+
+	forged := *Sign([]byte("other contract"), private)
+	forged.Digest = signature.Digest
+	assert.ErrorIs(t, Verify([]byte("contract"), &forged, public), ErrInvalidSignature)
+
+	unsupported := *signature
+	unsupported.Algorithm = "rsa"
+	assert.Error(t, Verify([]byte("contract"), &unsupported, public))
+}
+
+func TestParseKeys_Invalid(t *testing.T) {
+	publicPEM, privatePEM, err := GenerateKeyPair()
+	require.NoError(t, err)
+
+	_, err = ParsePublicKey(privatePEM)
+	assert.Error(t, err, "a private key is not a public key")
+	_, err = ParsePrivateKey(publicPEM)
+	assert.Error(t, err)
+	_, err = ParsePublicKey([]byte("not a key"))
+	assert.Error(t, err)
+	_, err = LoadPublicKey(filepath.Join(t.TempDir(), "missing.pub"))
+	assert.Error(t, err)
+}
+
+func TestSignAndVerifyPath(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"orders.yaml":            "kind: ServiceSpec\n",
+		"shared/responses.yml":   "errors: {}\n",
+		"users/index.json":       "{}\n",
+		"README.md":              "not a contract\n",
+		".git/config.yaml":       "hidden\n",
+		"users/endpoint.json.bk": "backup\n",
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	publicPath, privatePath := writeKeyPair(t, t.TempDir())
+	private, err := LoadPrivateKey(privatePath)
+	require.NoError(t, err)
+	public, err := LoadPublicKey(publicPath)
+	require.NoError(t, err)
+
+	signatures, err := SignPath(dir, private)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "orders.yaml.sig"),
+		filepath.Join(dir, "shared/responses.yml.sig"),
+		filepath.Join(dir, "users/index.json.sig"),
+	}, signatures)
+
+	verified, err := VerifyPath(dir, public)
+	require.NoError(t, err)
+	assert.Len(t, verified, 3)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shared/responses.yml"), []byte("errors: {changed: true}\n"), 0644))
+	require.NoError(t, os.Remove(filepath.Join(dir, "users/index.json.sig")))
+	verified, err = VerifyPath(dir, public)
+	assert.Equal(t, []string{filepath.Join(dir, "orders.yaml")}, verified)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	assert.ErrorIs(t, err, ErrMissingSignature, "every failure is reported")
+
+	_, err = VerifyPath(t.TempDir(), public)
+	assert.Error(t, err, "a directory without contracts")
+}
+
+func TestSignAndVerifyPath_References(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"contracts/orders.yaml":   "kind: ServiceSpec\nspec:\n  endpoints:\n    - $ref: \"../shared/endpoints.yaml#/orders\"\n",
+		"shared/endpoints.yaml":   "orders:\n  path: /orders\n  operations:\n    - include: \"operations.yaml#/list\"\n",
+		"shared/operations.yaml":  "list:\n  method: GET\n",
+		"shared/unreferenced.yml": "other: {}\n",
+	} {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	publicPath, privatePath := writeKeyPair(t, t.TempDir())
+	private, err := LoadPrivateKey(privatePath)
+	require.NoError(t, err)
+	public, err := LoadPublicKey(publicPath)
+	require.NoError(t, err)
+
+	for _, path := range []string{filepath.Join(root, "contracts"), filepath.Join(root, "contracts", "orders.yaml")} {
+		files, err := ContractFiles(path)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			filepath.Join(root, "contracts/orders.yaml"),
+			filepath.Join(root, "shared/endpoints.yaml"),
+			filepath.Join(root, "shared/operations.yaml"),
+		}, files, "files referenced outside the signed path are covered, transitively")
+	}
+
+	_, err = SignPath(filepath.Join(root, "contracts", "orders.yaml"), private)
+	require.NoError(t, err)
+	verified, err := VerifyPath(filepath.Join(root, "contracts"), public)
+	require.NoError(t, err)
+	assert.Len(t, verified, 3)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "shared/operations.yaml"), []byte("list:\n  method: DELETE\n"), 0644))
+	_, err = VerifyPath(filepath.Join(root, "contracts", "orders.yaml"), public)
+	assert.ErrorIs(t, err, ErrInvalidSignature, "a referenced file changed after signing")
+}
@@ -0,0 +1,171 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testgen
+
+import (
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+)
+
+// generateGo renders a table-driven Go test running every operation against an httptest server
+func generateGo(cases []testCase, options Options) ([]byte, error) {
+	packageName := options.Package
+	if packageName == "" {
+		packageName = "contract"
+	}
+
+	var output strings.Builder
+	output.WriteString("// Code generated by flowspec-cli generate tests. Edit freely; it is not regenerated.\n\n")
+	output.WriteString(fmt.Sprintf("package %s\n\n", packageName))
+	output.WriteString(`import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// newHandler returns the service under test.
+func newHandler(t *testing.T) http.Handler {
+	t.Helper()
+	// TODO: return the service's HTTP handler.
+	t.Skip("newHandler is not implemented yet")
+	return nil
+}
+
+func TestContract(t *testing.T) {
+	server := httptest.NewServer(newHandler(t))
+	defer server.Close()
+
+	testCases := []struct {
+		name     string
+		method   string
+		path     string
+		query    url.Values
+		headers  map[string]string
+		statuses []int // Contracted status codes
+		classes  []int // Contracted status classes, e.g. 4 for "4xx"
+	}{
+`)
+	for _, c := range cases {
+		output.WriteString("\t\t{\n")
+		output.WriteString(fmt.Sprintf("\t\t\tname:   %s,\n", strconv.Quote(c.Name())))
+		output.WriteString(fmt.Sprintf("\t\t\tmethod: %s,\n", goMethod(c.Method)))
+		if parameters := pathParameters(c.Path); len(parameters) > 0 {
+			output.WriteString(fmt.Sprintf("\t\t\t// TODO: use existing values for %s\n", strings.Join(parameters, ", ")))
+		}
+		output.WriteString(fmt.Sprintf("\t\t\tpath: %s,\n", strconv.Quote(replaceParameters(c.Path, func(string) string { return "1" }))))
+		if len(c.Query) > 0 {
+			output.WriteString("\t\t\tquery: url.Values{\n")
+			for _, name := range c.Query {
+				output.WriteString(fmt.Sprintf("\t\t\t\t%s: {\"TODO\"},\n", strconv.Quote(name)))
+			}
+			output.WriteString("\t\t\t},\n")
+		}
+		if len(c.Headers) > 0 {
+			output.WriteString("\t\t\theaders: map[string]string{\n")
+			for _, name := range c.Headers {
+				output.WriteString(fmt.Sprintf("\t\t\t\t%s: \"TODO\",\n", strconv.Quote(name)))
+			}
+			output.WriteString("\t\t\t},\n")
+		}
+		if len(c.Statuses) > 0 {
+			output.WriteString(fmt.Sprintf("\t\t\tstatuses: %s,\n", goInts(c.Statuses)))
+		}
+		if classes := rangeClasses(c.Ranges); len(classes) > 0 {
+			output.WriteString(fmt.Sprintf("\t\t\tclasses: %s,\n", goInts(classes)))
+		}
+		output.WriteString("\t\t},\n")
+	}
+	output.WriteString(`	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			target := server.URL + tc.path
+			if len(tc.query) > 0 {
+				target += "?" + tc.query.Encode()
+			}
+			request, err := http.NewRequest(tc.method, target, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, value := range tc.headers {
+				request.Header.Set(name, value)
+			}
+
+			response, err := server.Client().Do(request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+
+			if !contracted(response.StatusCode, tc.statuses, tc.classes) {
+				t.Errorf("status %d is not contracted (statuses %v, classes %v)", response.StatusCode, tc.statuses, tc.classes)
+			}
+			// TODO: assert on the response body.
+		})
+	}
+}
+
+// contracted reports whether status is one of the contracted codes or classes.
+func contracted(status int, statuses, classes []int) bool {
+	if len(statuses) == 0 && len(classes) == 0 {
+		return true
+	}
+	for _, expected := range statuses {
+		if status == expected {
+			return true
+		}
+	}
+	for _, class := range classes {
+		if status/100 == class {
+			return true
+		}
+	}
+	return false
+}
+`)
+
+	formatted, err := format.Source([]byte(output.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated Go tests: %w", err)
+	}
+	return formatted, nil
+}
+
+// goMethods maps methods to their net/http constants
+var goMethods = map[string]string{
+	"GET": "http.MethodGet", "HEAD": "http.MethodHead", "POST": "http.MethodPost", "PUT": "http.MethodPut",
+	"PATCH": "http.MethodPatch", "DELETE": "http.MethodDelete", "OPTIONS": "http.MethodOptions",
+	"CONNECT": "http.MethodConnect", "TRACE": "http.MethodTrace",
+}
+
+// goMethod returns the net/http constant of method, or a string literal for extension methods
+func goMethod(method string) string {
+	if constant, ok := goMethods[method]; ok {
+		return constant
+	}
+	return strconv.Quote(method)
+}
+
+// goInts renders an int slice literal
+func goInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = strconv.Itoa(value)
+	}
+	return "[]int{" + strings.Join(parts, ", ") + "}"
+}
@@ -0,0 +1,108 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// generateK6 renders a k6 script requesting every operation once per iteration
+func generateK6(cases []testCase) []byte {
+	var output strings.Builder
+	output.WriteString(`// Generated by flowspec-cli generate tests. Edit freely; it is not regenerated.
+// Run with: k6 run -e BASE_URL=http://localhost:8080 contract.k6.js
+import http from 'k6/http';
+import { check, group } from 'k6';
+
+const BASE_URL = __ENV.BASE_URL || 'http://localhost:8080';
+
+export const options = {
+  vus: 1,
+  iterations: 1,
+  thresholds: {
+    checks: ['rate==1.0'],
+  },
+};
+
+// contracted reports whether status is one of the contracted codes or classes.
+function contracted(status, statuses, classes) {
+  if (statuses.length === 0 && classes.length === 0) {
+    return true;
+  }
+  return statuses.includes(status) || classes.includes(Math.floor(status / 100));
+}
+
+const operations = [
+`)
+	for _, c := range cases {
+		headers := make(map[string]string, len(c.Headers))
+		for _, name := range c.Headers {
+			headers[name] = "TODO"
+		}
+		var query []string
+		for _, name := range c.Query {
+			query = append(query, name+"=TODO")
+		}
+		path := replaceParameters(c.Path, func(string) string { return "1" })
+		if len(query) > 0 {
+			path += "?" + strings.Join(query, "&")
+		}
+
+		output.WriteString("  {\n")
+		output.WriteString(fmt.Sprintf("    name: %s,\n", jsValue(c.Name())))
+		output.WriteString(fmt.Sprintf("    method: %s,\n", jsValue(c.Method)))
+		if parameters := pathParameters(c.Path); len(parameters) > 0 {
+			output.WriteString(fmt.Sprintf("    // TODO: use existing values for %s\n", strings.Join(parameters, ", ")))
+		}
+		output.WriteString(fmt.Sprintf("    path: %s,\n", jsValue(path)))
+		output.WriteString(fmt.Sprintf("    headers: %s,\n", jsValue(headers)))
+		output.WriteString(fmt.Sprintf("    statuses: %s,\n", jsValue(nonNilInts(c.Statuses))))
+		output.WriteString(fmt.Sprintf("    classes: %s,\n", jsValue(nonNilInts(rangeClasses(c.Ranges)))))
+		output.WriteString("  },\n")
+	}
+	output.WriteString(`];
+
+export default function () {
+  for (const operation of operations) {
+    group(operation.name, () => {
+      const response = http.request(operation.method, BASE_URL + operation.path, null, {
+        headers: operation.headers,
+        tags: { name: operation.name },
+      });
+      check(response, {
+        'status is contracted': (r) => contracted(r.status, operation.statuses, operation.classes),
+      });
+    });
+  }
+}
+`)
+	return []byte(output.String())
+}
+
+// jsValue renders value as a JavaScript literal; JSON is valid JavaScript
+func jsValue(value interface{}) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// nonNilInts renders nil slices as empty arrays rather than null
+func nonNilInts(values []int) []int {
+	if values == nil {
+		return []int{}
+	}
+	return values
+}
@@ -0,0 +1,165 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// postmanSchema is the Postman collection format generated
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Variable []postmanVariable `json:"variable"`
+	Item     []postmanItem     `json:"item"`
+}
+
+type postmanInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+type postmanVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type postmanItem struct {
+	Name    string         `json:"name"`
+	Request postmanRequest `json:"request"`
+	Event   []postmanEvent `json:"event"`
+}
+
+type postmanRequest struct {
+	Method string          `json:"method"`
+	Header []postmanHeader `json:"header"`
+	URL    postmanURL      `json:"url"`
+}
+
+type postmanHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type postmanURL struct {
+	Raw   string            `json:"raw"`
+	Host  []string          `json:"host"`
+	Path  []string          `json:"path"`
+	Query []postmanVariable `json:"query,omitempty"`
+}
+
+type postmanEvent struct {
+	Listen string        `json:"listen"`
+	Script postmanScript `json:"script"`
+}
+
+type postmanScript struct {
+	Type string   `json:"type"`
+	Exec []string `json:"exec"`
+}
+
+// generatePostman renders a Postman collection with a status test per operation. Path
+// parameters become collection variables, so they are filled in once.
+func generatePostman(cases []testCase, options Options) ([]byte, error) {
+	name := options.Name
+	if name == "" {
+		name = collectionName(cases)
+	}
+	collection := postmanCollection{
+		Info:     postmanInfo{Name: name, Schema: postmanSchema},
+		Variable: []postmanVariable{{Key: "baseUrl", Value: "http://localhost:8080"}},
+	}
+
+	parameters := make(map[string]bool)
+	for _, c := range cases {
+		for _, parameter := range pathParameters(c.Path) {
+			parameters[parameter] = true
+		}
+
+		path := replaceParameters(c.Path, func(name string) string { return "{{" + name + "}}" })
+		segments := strings.Split(strings.Trim(path, "/"), "/")
+		raw := "{{baseUrl}}" + path
+		var query []postmanVariable
+		var rawQuery []string
+		for _, parameter := range c.Query {
+			query = append(query, postmanVariable{Key: parameter, Value: "TODO"})
+			rawQuery = append(rawQuery, parameter+"=TODO")
+		}
+		if len(rawQuery) > 0 {
+			raw += "?" + strings.Join(rawQuery, "&")
+		}
+		headers := []postmanHeader{}
+		for _, header := range c.Headers {
+			headers = append(headers, postmanHeader{Key: header, Value: "TODO"})
+		}
+
+		collection.Item = append(collection.Item, postmanItem{
+			Name: c.Name(),
+			Request: postmanRequest{
+				Method: c.Method,
+				Header: headers,
+				URL:    postmanURL{Raw: raw, Host: []string{"{{baseUrl}}"}, Path: segments, Query: query},
+			},
+			Event: []postmanEvent{{
+				Listen: "test",
+				Script: postmanScript{Type: "text/javascript", Exec: []string{
+					fmt.Sprintf("const statuses = %s;", jsValue(nonNilInts(c.Statuses))),
+					fmt.Sprintf("const classes = %s;", jsValue(nonNilInts(rangeClasses(c.Ranges)))),
+					fmt.Sprintf("pm.test(%s, function () {", jsValue("status is "+c.StatusDescription())),
+					"    const status = pm.response.code;",
+					"    const contracted = (statuses.length === 0 && classes.length === 0) ||",
+					"        statuses.includes(status) || classes.includes(Math.floor(status / 100));",
+					"    pm.expect(contracted, 'status ' + status + ' is not contracted').to.be.true;",
+					"});",
+				}},
+			}},
+		})
+	}
+
+	names := make([]string, 0, len(parameters))
+	for parameter := range parameters {
+		names = append(names, parameter)
+	}
+	sort.Strings(names)
+	for _, parameter := range names {
+		collection.Variable = append(collection.Variable, postmanVariable{Key: parameter, Value: "1"})
+	}
+
+	data, err := json.MarshalIndent(collection, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Postman collection: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// collectionName names a collection after the services of its operations
+func collectionName(cases []testCase) string {
+	seen := make(map[string]bool)
+	var services []string
+	for _, c := range cases {
+		if c.Service != "" && !seen[c.Service] {
+			seen[c.Service] = true
+			services = append(services, c.Service)
+		}
+	}
+	if len(services) == 0 {
+		return "FlowSpec contract tests"
+	}
+	return strings.Join(services, ", ") + " contract tests"
+}
@@ -0,0 +1,194 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package testgen generates skeleton integration tests for the operations of a contract
+// (flowspec-cli generate tests)
+package testgen
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// Format is a test skeleton format
+type Format string
+
+// Supported formats
+const (
+	FormatGo      Format = "go"
+	FormatK6      Format = "k6"
+	FormatPostman Format = "postman"
+)
+
+// Formats lists the supported formats
+var Formats = []Format{FormatGo, FormatK6, FormatPostman}
+
+// ParseFormat parses a --lang value
+func ParseFormat(value string) (Format, error) {
+	for _, format := range Formats {
+		if strings.EqualFold(value, string(format)) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported test format %q (supported: go, k6, postman)", value)
+}
+
+// DefaultFileName returns the file the generated tests are written to by default
+func (f Format) DefaultFileName() string {
+	switch f {
+	case FormatK6:
+		return "contract.k6.js"
+	case FormatPostman:
+		return "contract.postman_collection.json"
+	default:
+		return "contract_test.go"
+	}
+}
+
+// Options configures generation
+type Options struct {
+	Package string // Go package name; "contract" when empty
+	Name    string // Collection name for Postman; derived from the contracts when empty
+}
+
+// testCase is one contracted operation to exercise
+type testCase struct {
+	Service  string
+	Method   string
+	Path     string
+	Statuses []int
+	Ranges   []string // Status classes such as "4xx"
+	Headers  []string
+	Query    []string
+}
+
+// Name returns a readable test name, e.g. "GET /users/{id}"
+func (c testCase) Name() string {
+	return c.Method + " " + c.Path
+}
+
+// StatusDescription lists the contracted statuses, e.g. "200, 404 or 5xx"
+func (c testCase) StatusDescription() string {
+	var parts []string
+	for _, status := range c.Statuses {
+		parts = append(parts, strconv.Itoa(status))
+	}
+	parts = append(parts, c.Ranges...)
+	if len(parts) == 0 {
+		return "any status"
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " or " + parts[len(parts)-1]
+}
+
+// Generate renders test skeletons for every operation of specs
+func Generate(specs []models.ServiceSpec, format Format, options Options) ([]byte, error) {
+	cases := collectCases(specs)
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no operations found in contracts; test generation needs YAML contracts with spec.endpoints")
+	}
+
+	switch format {
+	case FormatGo:
+		return generateGo(cases, options)
+	case FormatK6:
+		return generateK6(cases), nil
+	case FormatPostman:
+		return generatePostman(cases, options)
+	default:
+		return nil, fmt.Errorf("unsupported test format %q", format)
+	}
+}
+
+// collectCases lists the operations of specs sorted by path and method
+func collectCases(specs []models.ServiceSpec) []testCase {
+	var cases []testCase
+	for _, spec := range specs {
+		if spec.Spec == nil {
+			continue
+		}
+		service := ""
+		if spec.Metadata != nil {
+			service = spec.Metadata.Name
+		}
+		for _, endpoint := range spec.Spec.Endpoints {
+			for _, operation := range endpoint.Operations {
+				statuses := append([]int(nil), operation.Responses.StatusCodes...)
+				sort.Ints(statuses)
+				ranges := make([]string, 0, len(operation.Responses.StatusRanges))
+				for _, statusRange := range operation.Responses.StatusRanges {
+					ranges = append(ranges, strings.ToLower(statusRange))
+				}
+				sort.Strings(ranges)
+				cases = append(cases, testCase{
+					Service:  service,
+					Method:   strings.ToUpper(operation.Method),
+					Path:     endpoint.Path,
+					Statuses: statuses,
+					Ranges:   ranges,
+					Headers:  operation.Required.Headers,
+					Query:    operation.Required.Query,
+				})
+			}
+		}
+	}
+	sort.SliceStable(cases, func(i, j int) bool {
+		if cases[i].Path != cases[j].Path {
+			return cases[i].Path < cases[j].Path
+		}
+		return cases[i].Method < cases[j].Method
+	})
+	return cases
+}
+
+// pathParameters lists the parameter names of a path pattern in order
+func pathParameters(path string) []string {
+	var parameters []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			parameters = append(parameters, strings.Trim(segment, "{}"))
+		}
+	}
+	return parameters
+}
+
+// replaceParameters rewrites the path parameters of a pattern with replace
+func replaceParameters(path string, replace func(name string) string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = replace(strings.Trim(segment, "{}"))
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// rangeClasses returns the leading digits of status classes, e.g. "4xx" → 4
+func rangeClasses(ranges []string) []int {
+	var classes []int
+	for _, statusRange := range ranges {
+		if len(statusRange) == 3 && strings.HasSuffix(statusRange, "xx") {
+			if class, err := strconv.Atoi(statusRange[:1]); err == nil {
+				classes = append(classes, class)
+			}
+		}
+	}
+	return classes
+}
@@ -0,0 +1,118 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testgen
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSpecs() []models.ServiceSpec {
+	return []models.ServiceSpec{{
+		Metadata: &models.ServiceSpecMetadata{Name: "user-service", Version: "v1"},
+		Spec: &models.ServiceSpecDefinition{Endpoints: []models.EndpointSpec{
+			{Path: "/users/{id}", Operations: []models.OperationSpec{
+				{Method: "get", Responses: models.ResponseSpec{StatusCodes: []int{404, 200}, StatusRanges: []string{"5XX"}}},
+				{Method: "PURGE"},
+			}},
+			{Path: "/users", Operations: []models.OperationSpec{
+				{Method: "POST", Responses: models.ResponseSpec{StatusCodes: []int{201}},
+					Required: models.RequiredFieldsSpec{Query: []string{"dryRun"}, Headers: []string{"Authorization"}}},
+			}},
+		}},
+	}}
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("K6")
+	require.NoError(t, err)
+	assert.Equal(t, FormatK6, format)
+	assert.Equal(t, "contract.k6.js", format.DefaultFileName())
+
+	_, err = ParseFormat("jest")
+	assert.Error(t, err)
+}
+
+func TestGenerate_Go(t *testing.T) {
+	output, err := Generate(newSpecs(), FormatGo, Options{Package: "users"})
+	require.NoError(t, err)
+	source := string(output)
+
+	assert.Contains(t, source, "package users")
+	assert.Contains(t, source, `name:   "POST /users",`)
+	assert.Contains(t, source, `"dryRun": {"TODO"},`)
+	assert.Contains(t, source, `"Authorization": "TODO",`)
+	assert.Contains(t, source, "// TODO: use existing values for id")
+	assert.Contains(t, source, `path:     "/users/1",`)
+	assert.Contains(t, source, "statuses: []int{200, 404},")
+	assert.Contains(t, source, "classes:  []int{5},")
+	assert.Contains(t, source, `method: "PURGE",`)
+
+	// The skeleton compiles as it is
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "contract_test.go", output, parser.ParseComments)
+	require.NoError(t, err)
+	config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = config.Check("users", fset, []*ast.File{file}, nil)
+	assert.NoError(t, err)
+}
+
+func TestGenerate_K6(t *testing.T) {
+	output, err := Generate(newSpecs(), FormatK6, Options{})
+	require.NoError(t, err)
+	script := string(output)
+
+	assert.Contains(t, script, "import http from 'k6/http';")
+	assert.Contains(t, script, `path: "/users?dryRun=TODO",`)
+	assert.Contains(t, script, `headers: {"Authorization":"TODO"},`)
+	assert.Contains(t, script, `statuses: [200,404],`)
+	assert.Contains(t, script, `classes: [5],`)
+	assert.Contains(t, script, `statuses: [],`)
+}
+
+func TestGenerate_Postman(t *testing.T) {
+	output, err := Generate(newSpecs(), FormatPostman, Options{})
+	require.NoError(t, err)
+
+	var collection postmanCollection
+	require.NoError(t, json.Unmarshal(output, &collection))
+	assert.Equal(t, "user-service contract tests", collection.Info.Name)
+	assert.Equal(t, []postmanVariable{{Key: "baseUrl", Value: "http://localhost:8080"}, {Key: "id", Value: "1"}}, collection.Variable)
+	require.Len(t, collection.Item, 3)
+
+	create := collection.Item[0]
+	assert.Equal(t, "POST /users", create.Name)
+	assert.Equal(t, "{{baseUrl}}/users?dryRun=TODO", create.Request.URL.Raw)
+	assert.Equal(t, []postmanHeader{{Key: "Authorization", Value: "TODO"}}, create.Request.Header)
+
+	get := collection.Item[1]
+	assert.Equal(t, "{{baseUrl}}/users/{{id}}", get.Request.URL.Raw)
+	assert.Equal(t, []string{"users", "{{id}}"}, get.Request.URL.Path)
+	assert.Contains(t, get.Event[0].Script.Exec, `pm.test("status is 200, 404 or 5xx", function () {`)
+}
+
+func TestGenerate_NoOperations(t *testing.T) {
+	_, err := Generate([]models.ServiceSpec{{OperationID: "legacy"}}, FormatGo, Options{})
+	assert.Error(t, err)
+}
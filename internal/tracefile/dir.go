@@ -0,0 +1,98 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracefile
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/flowspec/flowspec-cli/internal/concurrency"
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// LoadDir reads every JSON trace file of a directory, at most workers files at a time
+// (GOMAXPROCS when workers is not positive), and returns one trace per trace ID, ordered by
+// trace ID. Spans of one trace spread over several files, such as recorded fixtures, are
+// merged, each span being loaded once.
+func LoadDir(dir string, workers int) ([]*models.TraceData, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trace files: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("trace directory %s has no JSON trace files", dir)
+	}
+	sort.Strings(paths)
+	workers = concurrency.Bounded(workers, len(paths))
+
+	files := make([]*models.TraceData, len(paths))
+	errs := make([]error, len(paths))
+	slots := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, path string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if files[i], errs[i] = Load(path); errs[i] != nil {
+				errs[i] = fmt.Errorf("failed to load %s: %w", filepath.Base(path), errs[i])
+			}
+		}(i, path)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Files are merged in path order so the result does not depend on loading order
+	traces := make(map[string]*models.TraceData)
+	for _, file := range files {
+		ids := make([]string, 0, len(file.Spans))
+		for id := range file.Spans {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			span := file.Spans[id]
+			traceID := span.TraceID
+			if traceID == "" {
+				traceID = file.TraceID
+			}
+			trace, exists := traces[traceID]
+			if !exists {
+				trace = &models.TraceData{TraceID: traceID, Spans: make(map[string]*models.Span)}
+				traces[traceID] = trace
+			}
+			if _, loaded := trace.Spans[id]; !loaded {
+				trace.AddSpan(span)
+			}
+		}
+	}
+
+	result := make([]*models.TraceData, 0, len(traces))
+	for _, trace := range traces {
+		if err := trace.BuildSpanTree(); err != nil {
+			return nil, fmt.Errorf("failed to build span tree of trace %s: %w", trace.TraceID, err)
+		}
+		result = append(result, trace)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].TraceID < result[j].TraceID })
+	return result, nil
+}
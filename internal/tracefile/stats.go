@@ -0,0 +1,265 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracefile
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// unknownService groups the spans without a service.name attribute
+const unknownService = "(unknown)"
+
+// StatsOptions configures Stats
+type StatsOptions struct {
+	Top int // Rows of the name and attribute key tables; all when zero
+}
+
+// Count is the number of spans sharing a value
+type Count struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// DepthCount is the number of spans at a depth of the span tree, roots being at depth 0
+type DepthCount struct {
+	Depth int `json:"depth"`
+	Count int `json:"count"`
+}
+
+// Durations summarizes span durations in milliseconds
+type Durations struct {
+	MinMs float64 `json:"minMs"`
+	P50Ms float64 `json:"p50Ms"`
+	P90Ms float64 `json:"p90Ms"`
+	P95Ms float64 `json:"p95Ms"`
+	P99Ms float64 `json:"p99Ms"`
+	MaxMs float64 `json:"maxMs"`
+}
+
+// NameStats describes the spans of a service sharing a name
+type NameStats struct {
+	Service   string    `json:"service"`
+	Name      string    `json:"name"`
+	Count     int       `json:"count"`
+	Errors    int       `json:"errors"` // Spans with an ERROR status
+	Durations Durations `json:"durations"`
+}
+
+// Stats describes the shape of a trace, to author contracts whose matchers select its spans
+// and to find out why they do not
+type Stats struct {
+	TraceID       string       `json:"traceId"`
+	Spans         int          `json:"spans"`
+	Orphans       int          `json:"orphans"` // Spans whose parent is not in the trace
+	Services      []Count      `json:"services"`
+	Statuses      []Count      `json:"statuses"`
+	Names         []NameStats  `json:"names"` // Ordered by descending count
+	Depths        []DepthCount `json:"depths"`
+	Durations     Durations    `json:"durations"`
+	AttributeKeys []Count      `json:"attributeKeys"` // Spans carrying each key, by descending count
+}
+
+// StatsFile computes the statistics of a trace file
+func StatsFile(tracePath string, options StatsOptions) (*Stats, error) {
+	trace, err := Load(tracePath)
+	if err != nil {
+		return nil, err
+	}
+	return ComputeStats(trace, options), nil
+}
+
+// ComputeStats counts the spans of a trace by service, name and status, and summarizes their
+// depths, durations and attribute keys. The service of a span is its service.name attribute.
+func ComputeStats(trace *models.TraceData, options StatsOptions) *Stats {
+	stats := &Stats{TraceID: trace.TraceID, Spans: len(trace.Spans)}
+	services := make(map[string]int)
+	statuses := make(map[string]int)
+	keys := make(map[string]int)
+	names := make(map[[2]string]*NameStats)
+	nameDurations := make(map[[2]string][]int64)
+	depths := make(map[int]int)
+	durations := make([]int64, 0, len(trace.Spans))
+
+	depthOf := spanDepths(trace)
+	for _, span := range trace.Spans {
+		service := unknownService
+		if value, ok := span.GetAttribute("service.name").(string); ok && value != "" {
+			service = value
+		}
+		services[service]++
+		statuses[span.Status.Code]++
+		for key := range span.Attributes {
+			keys[key]++
+		}
+		if span.ParentID != "" && trace.Spans[span.ParentID] == nil {
+			stats.Orphans++
+		}
+		depths[depthOf(span.SpanID)]++
+
+		key := [2]string{service, span.Name}
+		name, ok := names[key]
+		if !ok {
+			name = &NameStats{Service: service, Name: span.Name}
+			names[key] = name
+		}
+		name.Count++
+		if span.HasError() {
+			name.Errors++
+		}
+		nameDurations[key] = append(nameDurations[key], span.GetDuration())
+		durations = append(durations, span.GetDuration())
+	}
+
+	stats.Services = sortedCounts(services, 0)
+	stats.Statuses = sortedCounts(statuses, 0)
+	stats.AttributeKeys = sortedCounts(keys, options.Top)
+	stats.Durations = summarizeDurations(durations)
+	for key, name := range names {
+		name.Durations = summarizeDurations(nameDurations[key])
+		stats.Names = append(stats.Names, *name)
+	}
+	sort.Slice(stats.Names, func(i, j int) bool {
+		a, b := stats.Names[i], stats.Names[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		return a.Name < b.Name
+	})
+	if options.Top > 0 && len(stats.Names) > options.Top {
+		stats.Names = stats.Names[:options.Top]
+	}
+	for depth, count := range depths {
+		stats.Depths = append(stats.Depths, DepthCount{Depth: depth, Count: count})
+	}
+	sort.Slice(stats.Depths, func(i, j int) bool { return stats.Depths[i].Depth < stats.Depths[j].Depth })
+	return stats
+}
+
+// spanDepths returns the depth of a span of the trace; spans whose parent is missing are at
+// depth 0 like roots, and a parent cycle stops where it closes
+func spanDepths(trace *models.TraceData) func(spanID string) int {
+	depths := make(map[string]int, len(trace.Spans))
+	var depthOf func(spanID string, visiting map[string]bool) int
+	depthOf = func(spanID string, visiting map[string]bool) int {
+		if depth, ok := depths[spanID]; ok {
+			return depth
+		}
+		span := trace.Spans[spanID]
+		depth := 0
+		if parent := trace.Spans[span.ParentID]; span.ParentID != "" && parent != nil && !visiting[span.ParentID] {
+			visiting[spanID] = true
+			depth = depthOf(span.ParentID, visiting) + 1
+		}
+		depths[spanID] = depth
+		return depth
+	}
+	return func(spanID string) int {
+		return depthOf(spanID, make(map[string]bool))
+	}
+}
+
+// sortedCounts orders counts by descending count then value, keeping the top ones when top is
+// positive
+func sortedCounts(counts map[string]int, top int) []Count {
+	sorted := make([]Count, 0, len(counts))
+	for value, count := range counts {
+		sorted = append(sorted, Count{Value: value, Count: count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Value < sorted[j].Value
+	})
+	if top > 0 && len(sorted) > top {
+		sorted = sorted[:top]
+	}
+	return sorted
+}
+
+// summarizeDurations returns the nearest-rank percentiles of durations in nanoseconds
+func summarizeDurations(durations []int64) Durations {
+	if len(durations) == 0 {
+		return Durations{}
+	}
+	sorted := append([]int64(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+		return milliseconds(sorted[max(rank, 0)])
+	}
+	return Durations{
+		MinMs: milliseconds(sorted[0]),
+		P50Ms: percentile(50),
+		P90Ms: percentile(90),
+		P95Ms: percentile(95),
+		P99Ms: percentile(99),
+		MaxMs: milliseconds(sorted[len(sorted)-1]),
+	}
+}
+
+// milliseconds converts nanoseconds to milliseconds, to the microsecond
+func milliseconds(nanoseconds int64) float64 {
+	return float64(nanoseconds/1000) / 1000
+}
+
+// FormatHuman renders the statistics as tables
+func (s *Stats) FormatHuman() string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Trace %s: %d span(s)", s.TraceID, s.Spans))
+	if s.Orphans > 0 {
+		output.WriteString(fmt.Sprintf(", %d whose parent is missing", s.Orphans))
+	}
+	output.WriteString("\n")
+	if s.Spans == 0 {
+		return output.String()
+	}
+
+	writeCounts := func(title string, counts []Count) {
+		output.WriteString(fmt.Sprintf("\n%s\n", title))
+		for _, count := range counts {
+			output.WriteString(fmt.Sprintf("  %7d  %s\n", count.Count, count.Value))
+		}
+	}
+	writeCounts("Services", s.Services)
+	writeCounts("Statuses", s.Statuses)
+
+	output.WriteString("\nSpan names\n")
+	output.WriteString(fmt.Sprintf("  %7s  %6s  %9s  %9s  %9s  %s\n", "count", "errors", "p50 ms", "p95 ms", "p99 ms", "service / name"))
+	for _, name := range s.Names {
+		output.WriteString(fmt.Sprintf("  %7d  %6d  %9.3f  %9.3f  %9.3f  %s / %s\n", name.Count, name.Errors,
+			name.Durations.P50Ms, name.Durations.P95Ms, name.Durations.P99Ms, name.Service, name.Name))
+	}
+
+	output.WriteString("\nDepths\n")
+	for _, depth := range s.Depths {
+		output.WriteString(fmt.Sprintf("  %7d  depth %d\n", depth.Count, depth.Depth))
+	}
+
+	d := s.Durations
+	output.WriteString(fmt.Sprintf("\nDurations (ms)\n  min %.3f  p50 %.3f  p90 %.3f  p95 %.3f  p99 %.3f  max %.3f\n",
+		d.MinMs, d.P50Ms, d.P90Ms, d.P95Ms, d.P99Ms, d.MaxMs))
+
+	writeCounts("Attribute keys (spans carrying them)", s.AttributeKeys)
+	return output.String()
+}
@@ -0,0 +1,79 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracefile
+
+import (
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeStats(t *testing.T) {
+	spans := testSpans()
+	spans[2].Status.Code = "ERROR"
+	spans[2].Attributes["http.route"] = "/payments"
+	spans = append(spans, &models.Span{SpanID: "orphan", TraceID: "t1", ParentID: "gone", Name: "GET /orders",
+		StartTime: 3000, EndTime: 3000 + 4_000_000, Status: models.SpanStatus{Code: "OK"}})
+	trace := &models.TraceData{TraceID: "t1", Spans: map[string]*models.Span{}}
+	for _, span := range spans {
+		trace.Spans[span.SpanID] = span
+	}
+
+	stats := ComputeStats(trace, StatsOptions{})
+	assert.Equal(t, 5, stats.Spans)
+	assert.Equal(t, 1, stats.Orphans)
+	assert.Equal(t, []Count{{Value: "orders", Count: 4}, {Value: unknownService, Count: 1}}, stats.Services)
+	assert.Equal(t, []Count{{Value: "OK", Count: 4}, {Value: "ERROR", Count: 1}}, stats.Statuses)
+	assert.Equal(t, []DepthCount{{Depth: 0, Count: 2}, {Depth: 1, Count: 2}, {Depth: 2, Count: 1}}, stats.Depths)
+	assert.Equal(t, []Count{{Value: "service.name", Count: 4}, {Value: "http.route", Count: 1}}, stats.AttributeKeys)
+
+	require.Len(t, stats.Names, 5)
+	assert.Equal(t, NameStats{Service: "orders", Name: "POST /payments", Count: 1, Errors: 1},
+		stats.Names[3], "sub-microsecond durations round to zero")
+	assert.Equal(t, 4.0, stats.Durations.MaxMs)
+	assert.Equal(t, 0.0, stats.Durations.P50Ms)
+
+	top := ComputeStats(trace, StatsOptions{Top: 1})
+	assert.Len(t, top.Names, 1)
+	assert.Len(t, top.AttributeKeys, 1)
+	assert.Len(t, top.Services, 2, "services are never cut")
+
+	output := stats.FormatHuman()
+	assert.Contains(t, output, "Trace t1: 5 span(s), 1 whose parent is missing")
+	assert.Contains(t, output, "orders / POST /payments")
+	assert.Contains(t, output, "depth 2")
+	assert.Contains(t, output, "max 4.000")
+}
+
+func TestSummarizeDurations(t *testing.T) {
+	durations := make([]int64, 100)
+	for i := range durations {
+		durations[i] = int64(100-i) * 1_000_000
+	}
+	assert.Equal(t, Durations{MinMs: 1, P50Ms: 50, P90Ms: 90, P95Ms: 95, P99Ms: 99, MaxMs: 100}, summarizeDurations(durations))
+	assert.Equal(t, Durations{}, summarizeDurations(nil))
+}
+
+func TestStatsFile(t *testing.T) {
+	stats, err := StatsFile(writeOTLP(t, testSpans()), StatsOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 4, stats.Spans)
+	assert.Equal(t, []DepthCount{{Depth: 0, Count: 1}, {Depth: 1, Count: 2}, {Depth: 2, Count: 1}}, stats.Depths)
+
+	_, err = StatsFile("missing.json", StatsOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracefile implements the trace commands, which inspect and cut down trace files
+// without contracts.
+package tracefile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/flowspec/flowspec-cli/internal/ingestor"
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/parser"
+)
+
+// Load reads an OTLP JSON or FlowSpec trace file. Unlike the ingestor used by verify, it does
+// not limit the size of OTLP files, as huge traces are what the trace commands are for.
+func Load(path string) (*models.TraceData, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trace file: %w", err)
+	}
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid trace JSON in %s: %w", path, err)
+	}
+	if _, ok := document["resourceSpans"]; ok {
+		return ingestor.NewTraceIngestor().IngestFromReader(bytes.NewReader(data))
+	}
+	return parser.NewTraceFileParser().ParseFile(path)
+}
+
+// ExtractOptions configures Extract
+type ExtractOptions struct {
+	RootSpanID    string // Span whose subtree is extracted
+	WithAncestors bool   // Keep the ancestors of the span instead of making it the root
+}
+
+// ExtractResult describes an extracted subtree
+type ExtractResult struct {
+	TraceID    string `json:"traceId"`
+	RootSpanID string `json:"rootSpanId"`
+	Spans      int    `json:"spans"`      // Spans written
+	TotalSpans int    `json:"totalSpans"` // Spans of the input trace
+}
+
+// Extract writes a span of a trace file and its descendants to out as an OTLP JSON trace,
+// which verify and the other trace commands read like any trace file
+func Extract(tracePath string, out io.Writer, options ExtractOptions) (*ExtractResult, error) {
+	if options.RootSpanID == "" {
+		return nil, fmt.Errorf("a root span ID is required")
+	}
+	trace, err := Load(tracePath)
+	if err != nil {
+		return nil, err
+	}
+	subtree, err := trace.Subtree(options.RootSpanID, options.WithAncestors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract from %s: %w", tracePath, err)
+	}
+	if err := ingestor.WriteOTLP(out, subtree.GetAllSpans()); err != nil {
+		return nil, err
+	}
+	return &ExtractResult{
+		TraceID:    subtree.TraceID,
+		RootSpanID: options.RootSpanID,
+		Spans:      len(subtree.Spans),
+		TotalSpans: len(trace.Spans),
+	}, nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracefile
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/internal/ingestor"
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSpans() []*models.Span {
+	span := func(id, parentID, name string, start, end int64) *models.Span {
+		return &models.Span{SpanID: id, TraceID: "t1", ParentID: parentID, Name: name, StartTime: start, EndTime: end,
+			Status: models.SpanStatus{Code: "OK"}, Attributes: map[string]interface{}{"service.name": "orders"}}
+	}
+	return []*models.Span{
+		span("root", "", "gateway", 1000, 2000),
+		span("checkout", "root", "POST /checkout", 1100, 1900),
+		span("pay", "checkout", "POST /payments", 1200, 1500),
+		span("list", "root", "GET /orders", 1600, 1700),
+	}
+}
+
+func writeOTLP(t *testing.T, spans []*models.Span) string {
+	var content bytes.Buffer
+	require.NoError(t, ingestor.WriteOTLP(&content, spans))
+	path := filepath.Join(t.TempDir(), "trace.json")
+	require.NoError(t, os.WriteFile(path, content.Bytes(), 0644))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	otlp, err := Load(writeOTLP(t, testSpans()))
+	require.NoError(t, err)
+	assert.Len(t, otlp.Spans, 4)
+
+	compat := &models.TraceDataCompat{TraceID: "t1", Spans: testSpans()}
+	data, err := json.Marshal(compat)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "trace.json")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	flowspec, err := Load(path)
+	require.NoError(t, err)
+	assert.Len(t, flowspec.Spans, 4)
+	assert.Equal(t, "root", flowspec.RootSpan.SpanID)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0644))
+	_, err = Load(path)
+	assert.ErrorContains(t, err, "invalid trace JSON")
+	_, err = Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorContains(t, err, "failed to read trace file")
+}
+
+func TestExtract(t *testing.T) {
+	tracePath := writeOTLP(t, testSpans())
+
+	var out bytes.Buffer
+	result, err := Extract(tracePath, &out, ExtractOptions{RootSpanID: "checkout"})
+	require.NoError(t, err)
+	assert.Equal(t, &ExtractResult{TraceID: "t1", RootSpanID: "checkout", Spans: 2, TotalSpans: 4}, result)
+
+	extracted, err := ingestor.NewTraceIngestor().IngestFromReader(&out)
+	require.NoError(t, err)
+	assert.Len(t, extracted.Spans, 2)
+	assert.Equal(t, "checkout", extracted.RootSpan.SpanID)
+	assert.Empty(t, extracted.Spans["checkout"].ParentID, "the span becomes the root")
+	assert.Equal(t, "orders", extracted.Spans["pay"].Attributes["service.name"])
+
+	out.Reset()
+	result, err = Extract(tracePath, &out, ExtractOptions{RootSpanID: "pay", WithAncestors: true})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Spans)
+
+	_, err = Extract(tracePath, &out, ExtractOptions{RootSpanID: "nope"})
+	assert.ErrorContains(t, err, "span nope is not in the trace")
+	_, err = Extract(tracePath, &out, ExtractOptions{})
+	assert.ErrorContains(t, err, "a root span ID is required")
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, spans []*models.Span) {
+		var content bytes.Buffer
+		require.NoError(t, ingestor.WriteOTLP(&content, spans))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), content.Bytes(), 0644))
+	}
+	spans := testSpans()
+	other := &models.Span{SpanID: "solo", TraceID: "t0", Name: "GET /health", StartTime: 1, EndTime: 2,
+		Status: models.SpanStatus{Code: "OK"}}
+	// t1 is split over two files sharing its root, as recorded fixtures are
+	write("a.json", spans[:3])
+	write("b.json", []*models.Span{spans[0], spans[3]})
+	write("c.json", []*models.Span{other})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0644))
+
+	for _, workers := range []int{0, 1, 4} {
+		traces, err := LoadDir(dir, workers)
+		require.NoError(t, err)
+		require.Len(t, traces, 2)
+		assert.Equal(t, "t0", traces[0].TraceID)
+		assert.Len(t, traces[0].Spans, 1)
+		assert.Equal(t, "t1", traces[1].TraceID)
+		assert.Len(t, traces[1].Spans, 4)
+		assert.Empty(t, traces[1].DuplicateSpanIDs, "a span in several files is loaded once")
+		require.NotNil(t, traces[1].RootSpan)
+		assert.Equal(t, "root", traces[1].RootSpan.SpanID)
+	}
+
+	_, err := LoadDir(t.TempDir(), 2)
+	assert.ErrorContains(t, err, "has no JSON trace files")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "d.json"), []byte("{"), 0644))
+	_, err = LoadDir(dir, 2)
+	assert.ErrorContains(t, err, "failed to load d.json")
+}
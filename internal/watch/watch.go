@@ -0,0 +1,240 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package watch re-runs verification when contract or trace files change (verify --watch)
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/history"
+	"github.com/flowspec/flowspec-cli/internal/models"
+)
+
+// Default timings of the watch loop
+const (
+	DefaultInterval = 300 * time.Millisecond
+	DefaultDebounce = 200 * time.Millisecond
+)
+
+// fileState is what a change is detected from
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watcher detects changes to files by polling their modification time and size, which
+// works the same on every platform and inside containers with mounted volumes
+type Watcher struct {
+	paths    []string
+	interval time.Duration
+	debounce time.Duration
+	state    map[string]fileState
+}
+
+// NewWatcher creates a watcher for files and directories; directories are watched recursively
+func NewWatcher(paths []string, interval, debounce time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if debounce < 0 {
+		debounce = 0
+	}
+	w := &Watcher{paths: paths, interval: interval, debounce: debounce}
+	w.state = w.snapshot()
+	return w
+}
+
+// snapshot records the state of every watched file
+func (w *Watcher) snapshot() map[string]fileState {
+	state := make(map[string]fileState)
+	for _, path := range w.paths {
+		_ = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return nil // Files may disappear while an editor saves them
+			}
+			if entry.IsDir() {
+				if file != path && strings.HasPrefix(entry.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if info, err := entry.Info(); err == nil {
+				state[file] = fileState{modTime: info.ModTime(), size: info.Size()}
+			}
+			return nil
+		})
+	}
+	return state
+}
+
+// changes returns the files that were added, modified or removed since the last poll
+func (w *Watcher) changes() []string {
+	current := w.snapshot()
+	var changed []string
+	for file, state := range current {
+		if previous, ok := w.state[file]; !ok || previous != state {
+			changed = append(changed, file)
+		}
+	}
+	for file := range w.state {
+		if _, ok := current[file]; !ok {
+			changed = append(changed, file)
+		}
+	}
+	w.state = current
+	sort.Strings(changed)
+	return changed
+}
+
+// Wait blocks until watched files change and stay unchanged for the debounce period, and
+// returns the changed files
+func (w *Watcher) Wait(ctx context.Context) ([]string, error) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	pending := make(map[string]bool)
+	var settleAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case now := <-ticker.C:
+			if changed := w.changes(); len(changed) > 0 {
+				for _, file := range changed {
+					pending[file] = true
+				}
+				settleAt = now.Add(w.debounce)
+			}
+			if len(pending) > 0 && !now.Before(settleAt) {
+				files := make([]string, 0, len(pending))
+				for file := range pending {
+					files = append(files, file)
+				}
+				sort.Strings(files)
+				return files, nil
+			}
+		}
+	}
+}
+
+// VerifyFunc runs one verification
+type VerifyFunc func(ctx context.Context) (*models.AlignmentReport, error)
+
+// Run verifies once, then again after every change until ctx is cancelled, writing a compact
+// summary and the outcomes that changed since the previous run to out
+func Run(ctx context.Context, watcher *Watcher, verify VerifyFunc, out io.Writer) error {
+	var previous *models.AlignmentReport
+	runOnce := func(changed []string) {
+		if len(changed) > 0 {
+			fmt.Fprintf(out, "\n↻ %s changed\n", describeChanges(changed))
+		}
+		report, err := verify(ctx)
+		if err != nil {
+			// Broken contracts are expected while editing; keep watching
+			fmt.Fprintf(out, "[%s] ❌ %v\n", time.Now().Format("15:04:05"), err)
+			return
+		}
+		fmt.Fprint(out, FormatRun(previous, report))
+		previous = report
+	}
+
+	runOnce(nil)
+	for {
+		changed, err := watcher.Wait(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		runOnce(changed)
+	}
+}
+
+// describeChanges names the changed files, abbreviating long lists
+func describeChanges(files []string) string {
+	if len(files) <= 3 {
+		return strings.Join(files, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(files[:3], ", "), len(files)-3)
+}
+
+// FormatRun renders the summary line of a run and, after the first run, the outcomes that changed
+func FormatRun(previous, current *models.AlignmentReport) string {
+	var output strings.Builder
+	summary := current.Summary
+	icon := "✅"
+	if current.HasFailures() {
+		icon = "❌"
+	}
+	output.WriteString(fmt.Sprintf("[%s] %s %d passed, %d failed, %d skipped (%s)\n",
+		time.Now().Format("15:04:05"), icon, summary.Success, summary.Failed, summary.Skipped,
+		time.Duration(current.ExecutionTime).Round(time.Millisecond)))
+
+	if previous == nil {
+		return output.String()
+	}
+
+	comparison := history.Compare(previous, current)
+	changes := 0
+	for _, change := range comparison.Changes {
+		var symbol string
+		switch change.Kind {
+		case history.NewlyFailing:
+			symbol = "✗"
+		case history.Fixed:
+			symbol = "✓"
+		case history.Added:
+			symbol = "+"
+		case history.Removed:
+			symbol = "-"
+		default:
+			continue
+		}
+		changes++
+		if change.Kind == history.Added || change.Kind == history.Removed {
+			output.WriteString(fmt.Sprintf("  %s %s\n", symbol, change.Key))
+		} else {
+			output.WriteString(fmt.Sprintf("  %s %s: %s → %s\n", symbol, change.Key, change.Previous, change.Current))
+		}
+	}
+	if changes == 0 {
+		output.WriteString("  no outcome changes\n")
+	}
+	return output.String()
+}
+
+// Paths returns the files and directories to watch for a contract path and trace file,
+// skipping empty values and paths that do not exist
+func Paths(paths ...string) []string {
+	var watched []string
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			watched = append(watched, path)
+		}
+	}
+	return watched
+}
@@ -0,0 +1,126 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watch
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newReport builds a report with one YAML spec whose operations have the given statuses
+func newReport(operations map[string]models.AlignmentStatus) *models.AlignmentReport {
+	result := models.NewAlignmentResult("user-service-v1")
+	result.Status = models.StatusSuccess
+	result.OperationResults = make(map[string]*models.OperationResult)
+	for key, status := range operations {
+		result.OperationResults[key] = &models.OperationResult{Status: status}
+		if status == models.StatusFailed {
+			result.Status = models.StatusFailed
+		}
+	}
+	report := models.NewAlignmentReport()
+	report.AddResult(*result)
+	return report
+}
+
+func TestWatcher_Wait(t *testing.T) {
+	dir := t.TempDir()
+	contracts := filepath.Join(dir, "contracts")
+	require.NoError(t, os.MkdirAll(filepath.Join(contracts, ".git"), 0755))
+	spec := filepath.Join(contracts, "users.yaml")
+	require.NoError(t, os.WriteFile(spec, []byte("a"), 0644))
+	trace := filepath.Join(dir, "trace.json")
+	require.NoError(t, os.WriteFile(trace, []byte("{}"), 0644))
+
+	watcher := NewWatcher(Paths(contracts, trace, "", filepath.Join(dir, "missing")), 5*time.Millisecond, 20*time.Millisecond)
+	assert.Len(t, watcher.paths, 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	go func() {
+		// Several saves in a row are reported once
+		time.Sleep(20 * time.Millisecond)
+		_ = os.WriteFile(spec, []byte("ab"), 0644)
+		_ = os.WriteFile(filepath.Join(contracts, ".git", "index"), []byte("ignored"), 0644)
+		time.Sleep(5 * time.Millisecond)
+		_ = os.WriteFile(filepath.Join(contracts, "orders.yaml"), []byte("c"), 0644)
+	}()
+
+	changed, err := watcher.Wait(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(contracts, "orders.yaml"), spec}, changed)
+
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	_, err = watcher.Wait(canceled)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "users.yaml")
+	require.NoError(t, os.WriteFile(spec, []byte("v1"), 0644))
+
+	reports := []*models.AlignmentReport{
+		newReport(map[string]models.AlignmentStatus{"GET /users": models.StatusSuccess, "POST /users": models.StatusFailed}),
+		newReport(map[string]models.AlignmentStatus{"GET /users": models.StatusFailed, "POST /users": models.StatusSuccess, "DELETE /users": models.StatusSuccess}),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	runs := 0
+	verify := func(context.Context) (*models.AlignmentReport, error) {
+		runs++
+		switch runs {
+		case 1:
+			// Edit the contract once the first run is done
+			go func() {
+				time.Sleep(20 * time.Millisecond)
+				_ = os.WriteFile(spec, []byte("v2"), 0644)
+			}()
+			return reports[0], nil
+		case 2:
+			cancel()
+			return reports[1], nil
+		}
+		return nil, nil
+	}
+
+	var output bytes.Buffer
+	watcher := NewWatcher([]string{spec}, 5*time.Millisecond, 10*time.Millisecond)
+	require.NoError(t, Run(ctx, watcher, verify, &output))
+
+	text := output.String()
+	assert.Equal(t, 2, runs)
+	assert.Contains(t, text, "❌ 0 passed, 1 failed, 0 skipped")
+	assert.Contains(t, text, "↻ "+spec+" changed")
+	assert.Contains(t, text, "✗ user-service-v1 / GET /users: SUCCESS → FAILED")
+	assert.Contains(t, text, "✓ user-service-v1 / POST /users: FAILED → SUCCESS")
+	assert.Contains(t, text, "+ user-service-v1 / DELETE /users")
+}
+
+func TestFormatRun_NoChanges(t *testing.T) {
+	report := newReport(map[string]models.AlignmentStatus{"GET /users": models.StatusSuccess})
+	assert.Contains(t, FormatRun(report, report), "no outcome changes")
+	assert.NotContains(t, FormatRun(nil, report), "no outcome changes")
+}
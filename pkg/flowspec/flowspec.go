@@ -0,0 +1,286 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package flowspec is the public Go API of FlowSpec. It lets other tools and test harnesses
+// verify traces against ServiceSpec contracts in-process instead of running the CLI binary.
+//
+// The functions and types of this package follow semantic versioning; everything under
+// internal/ may change between releases.
+//
+//	specs, err := flowspec.LoadSpec("contracts/")
+//	trace, err := flowspec.LoadTrace("trace.json")
+//	report, err := flowspec.NewEngine(flowspec.DefaultConfig()).Align(ctx, specs, trace)
+//	if !flowspec.Passed(report) { ... }
+package flowspec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/flowspec/flowspec-cli/internal/engine"
+	"github.com/flowspec/flowspec-cli/internal/errcode"
+	"github.com/flowspec/flowspec-cli/internal/fixture"
+	"github.com/flowspec/flowspec-cli/internal/ingestor"
+	"github.com/flowspec/flowspec-cli/internal/models"
+	"github.com/flowspec/flowspec-cli/internal/parser"
+	"github.com/flowspec/flowspec-cli/internal/tracefile"
+)
+
+// Contract, trace and report types
+type (
+	ServiceSpec       = models.ServiceSpec
+	ParseError        = models.ParseError
+	TraceData         = models.TraceData
+	Span              = models.Span
+	AlignmentReport   = models.AlignmentReport
+	AlignmentResult   = models.AlignmentResult
+	AlignmentSummary  = models.AlignmentSummary
+	AlignmentStatus   = models.AlignmentStatus
+	ValidationDetail  = models.ValidationDetail
+	TraceCompleteness = models.TraceCompleteness
+	TraceSummary      = models.TraceSummary
+	SuggestionRule    = models.SuggestionRule
+	Fixture           = fixture.Fixture
+)
+
+// Alignment statuses
+const (
+	StatusSuccess = models.StatusSuccess
+	StatusFailed  = models.StatusFailed
+	StatusSkipped = models.StatusSkipped
+	StatusTimeout = models.StatusTimeout
+)
+
+// ErrIncompleteTrace is matched by errors.Is when Config.FailOnIncompleteTrace refuses a
+// structurally broken trace
+var ErrIncompleteTrace = models.ErrIncompleteTrace
+
+// ErrorCode is the stable code of a failure, e.g. FS-TRACE-004; the errors of LoadSpec,
+// LoadTrace and Verify carry one
+type ErrorCode = errcode.Code
+
+// ErrorCodeOf returns the code of a failure, or "" for a nil error
+func ErrorCodeOf(err error) ErrorCode {
+	return errcode.Of(err)
+}
+
+// SpecError reports the contracts that failed to parse
+type SpecError struct {
+	Errors []ParseError
+}
+
+// Error implements the error interface
+func (e *SpecError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, parseError := range e.Errors {
+		messages = append(messages, parseError.Error())
+	}
+	return fmt.Sprintf("%d contract error(s): %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+// LoadSpec loads the contracts in a YAML file, a source file with annotations, or a
+// directory, the same way as the CLI's --path. Parse errors are returned as *SpecError.
+func LoadSpec(path string) ([]ServiceSpec, error) {
+	result, err := parser.NewSpecParser().ParseFromSource(path)
+	if err != nil {
+		return nil, errcode.Wrap(err, errcode.ContractUnreadable)
+	}
+	if len(result.Errors) > 0 {
+		return nil, errcode.Wrap(&SpecError{Errors: result.Errors}, errcode.ContractInvalid)
+	}
+	return result.Specs, nil
+}
+
+// LoadTrace loads an OTLP JSON or FlowSpec trace file, or a directory of fixtures written by
+// RecordFixtures
+func LoadTrace(path string) (*TraceData, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		trace, err := fixture.Load(path)
+		return trace, traceError(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errcode.Wrap(fmt.Errorf("failed to read trace file: %w", err), errcode.TraceUnreadable)
+	}
+	if !isOTLP(data) {
+		trace, err := parser.NewTraceFileParser().ParseFile(path)
+		return trace, traceError(err)
+	}
+	return ReadTrace(bytes.NewReader(data))
+}
+
+// LoadTraces loads a trace file, or every JSON trace file of a directory as one trace per trace
+// ID, reading at most workers files at a time. Fixture files recorded from one trace by
+// RecordFixtures load as that trace.
+func LoadTraces(path string, workers int) ([]*TraceData, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		traces, err := tracefile.LoadDir(path, workers)
+		return traces, traceError(err)
+	}
+	trace, err := LoadTrace(path)
+	if err != nil {
+		return nil, err
+	}
+	return []*TraceData{trace}, nil
+}
+
+// ReadTrace reads OTLP JSON trace data, e.g. from an in-memory exporter
+func ReadTrace(reader io.Reader) (*TraceData, error) {
+	trace, err := ingestor.NewTraceIngestor().IngestFromReader(reader)
+	return trace, traceError(err)
+}
+
+// traceError gives a failure to load a trace its code: files that cannot be read are
+// TraceUnreadable, and other failures TraceMalformed unless they are recognized
+func traceError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+		return errcode.Wrap(err, errcode.TraceUnreadable)
+	}
+	return errcode.Wrap(err, errcode.TraceMalformed)
+}
+
+// isOTLP reports whether trace JSON is in the OTLP format
+func isOTLP(data []byte) bool {
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(data, &document); err != nil {
+		return true // Let the ingestor report the syntax error
+	}
+	_, ok := document["resourceSpans"]
+	return ok
+}
+
+// Config configures an Engine
+type Config struct {
+	MaxConcurrency        int                    // Specs aligned in parallel; GOMAXPROCS when zero
+	TraceConcurrency      int                    // Traces aligned in parallel when verifying a directory of trace files; GOMAXPROCS when zero
+	Timeout               time.Duration          // Timeout for aligning a single spec
+	Strict                bool                   // Treat warnings as failures
+	SkipMissingSpans      bool                   // Report specs without matching spans as skipped instead of failed
+	EnforceSunset         bool                   // Fail deprecated operations that receive traffic after their sunset date
+	Variables             map[string]interface{} // External variables exposed to assertions as vars.*
+	ReportUnmatched       bool                   // List spans that matched no spec in the report
+	Explain               bool                   // Record why each candidate span was accepted or rejected
+	ClockSkew             time.Duration          // How much earlier than its parent a child span may start before the trace is reported skewed
+	FailOnIncompleteTrace bool                   // Refuse traces with orphan spans, missing roots, duplicate span IDs or clock skew
+	CorrectClockSkew      bool                   // Move child spans starting before their parent or ending after their root by more than ClockSkew
+	Tags                  []string               // Only verify operations carrying one of these tags; all when empty
+	KeepSecrets           bool                   // Quote authorization headers, cookies, tokens and API keys in reports instead of redacting them
+	DetailLevel           string                 // Span data quoted by failure messages: "minimal", "normal" (default) or "full"
+	SuggestionRules       []SuggestionRule       // Organization-specific remediation added to the failures the rules match
+}
+
+// DefaultConfig returns the configuration the CLI uses by default
+func DefaultConfig() Config {
+	defaults := engine.DefaultEngineConfig()
+	return Config{
+		MaxConcurrency:   defaults.MaxConcurrency,
+		TraceConcurrency: defaults.TraceConcurrency,
+		Timeout:          defaults.Timeout,
+		Strict:           defaults.StrictMode,
+		SkipMissingSpans: defaults.SkipMissingSpans,
+	}
+}
+
+// Engine aligns traces with contracts. It is safe for concurrent use.
+type Engine struct {
+	engine *engine.DefaultAlignmentEngine
+}
+
+// NewEngine creates an engine with config
+func NewEngine(config Config) *Engine {
+	engineConfig := engine.DefaultEngineConfig()
+	if config.MaxConcurrency > 0 {
+		engineConfig.MaxConcurrency = config.MaxConcurrency
+	}
+	if config.TraceConcurrency > 0 {
+		engineConfig.TraceConcurrency = config.TraceConcurrency
+	}
+	if config.Timeout > 0 {
+		engineConfig.Timeout = config.Timeout
+	}
+	engineConfig.StrictMode = config.Strict
+	engineConfig.SkipMissingSpans = config.SkipMissingSpans
+	engineConfig.EnforceSunset = config.EnforceSunset
+	engineConfig.Variables = config.Variables
+	engineConfig.ReportUnmatched = config.ReportUnmatched
+	engineConfig.Explain = config.Explain
+	engineConfig.ClockSkew = config.ClockSkew
+	engineConfig.FailOnIncomplete = config.FailOnIncompleteTrace
+	engineConfig.CorrectClockSkew = config.CorrectClockSkew
+	engineConfig.Tags = config.Tags
+	engineConfig.DetailLevel = config.DetailLevel
+	engineConfig.SuggestionRules = config.SuggestionRules
+	if config.KeepSecrets {
+		engineConfig.Redactor = nil
+	}
+	return &Engine{engine: engine.NewAlignmentEngineWithConfig(engineConfig)}
+}
+
+// Align verifies trace against specs, stopping early when ctx is cancelled
+func (e *Engine) Align(ctx context.Context, specs []ServiceSpec, trace *TraceData) (*AlignmentReport, error) {
+	if trace == nil {
+		return nil, fmt.Errorf("trace data is required")
+	}
+	return e.engine.AlignSpecsWithTraceContext(ctx, specs, trace)
+}
+
+// AlignTraces verifies each of several traces against specs, in parallel, and combines the
+// reports into one whose results carry their trace ID and whose Traces summarize each trace
+func (e *Engine) AlignTraces(ctx context.Context, specs []ServiceSpec, traces []*TraceData) (*AlignmentReport, error) {
+	return e.engine.AlignSpecsWithTraces(ctx, specs, traces)
+}
+
+// Close stops the engine's workers. An engine may be reused for any number of Align calls
+// before it is closed.
+func (e *Engine) Close() {
+	e.engine.Close()
+}
+
+// Verify loads the contracts at specPath and the trace file or directory of trace files at
+// tracePath and aligns them; the traces of a directory are aligned with AlignTraces
+func Verify(ctx context.Context, specPath, tracePath string, config Config) (*AlignmentReport, error) {
+	specs, err := LoadSpec(specPath)
+	if err != nil {
+		return nil, err
+	}
+	traces, err := LoadTraces(tracePath, config.TraceConcurrency)
+	if err != nil {
+		return nil, err
+	}
+	alignmentEngine := NewEngine(config)
+	defer alignmentEngine.Close()
+	if len(traces) == 1 {
+		return alignmentEngine.Align(ctx, specs, traces[0])
+	}
+	return alignmentEngine.AlignTraces(ctx, specs, traces)
+}
+
+// RecordFixtures writes the spans each operation of report matched in trace, with their
+// ancestors, as one fixture trace per operation into dir; LoadTrace(dir) reads them back
+func RecordFixtures(report *AlignmentReport, trace *TraceData, dir string) ([]Fixture, error) {
+	return fixture.Record(report, trace, dir)
+}
+
+// Passed reports whether no spec in report failed
+func Passed(report *AlignmentReport) bool {
+	return report != nil && !report.HasFailures()
+}
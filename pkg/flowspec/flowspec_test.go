@@ -0,0 +1,238 @@
+// Copyright 2024-2025 FlowSpec
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowspec_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flowspec/flowspec-cli/pkg/flowspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const contract = `apiVersion: flowspec/v1alpha1
+kind: ServiceSpec
+metadata:
+  name: health-service
+  version: v1.0.0
+spec:
+  endpoints:
+    - path: /health
+      operations:
+        - method: GET
+          responses:
+            statusCodes: [200]
+`
+
+const otlpTrace = `{
+  "resourceSpans": [{
+    "resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "health-service"}}]},
+    "scopeSpans": [{
+      "spans": [{
+        "traceId": "1234567890abcdef1234567890abcdef",
+        "spanId": "abcdef1234567890",
+        "name": "health",
+        "startTimeUnixNano": "1722508215000000000",
+        "endTimeUnixNano": "1722508215001000000",
+        "status": {"code": "STATUS_CODE_OK"},
+        "attributes": [
+          {"key": "http.method", "value": {"stringValue": "GET"}},
+          {"key": "http.target", "value": {"stringValue": "/health"}},
+          {"key": "http.status_code", "value": {"intValue": 200}}
+        ]
+      }]
+    }]
+  }]
+}`
+
+const flowspecTrace = `{
+  "traceId": "1234567890abcdef1234567890abcdef",
+  "spans": [{
+    "spanId": "abcdef1234567890",
+    "traceId": "1234567890abcdef1234567890abcdef",
+    "name": "health",
+    "startTime": 1722508215000000000,
+    "endTime": 1722508215001000000,
+    "status": {"code": "OK"},
+    "attributes": {"http.method": "GET", "http.target": "/health", "http.status_code": 200}
+  }]
+}`
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "service-spec.yaml", contract)
+
+	testCases := []struct {
+		name  string
+		trace string
+	}{
+		{"OTLP trace", otlpTrace},
+		{"FlowSpec trace", flowspecTrace},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tracePath := writeFile(t, dir, "trace.json", tc.trace)
+
+			report, err := flowspec.Verify(context.Background(), specPath, tracePath, flowspec.DefaultConfig())
+			require.NoError(t, err)
+			require.Len(t, report.Results, 1)
+			operation := report.Results[0].OperationResults["GET /health"]
+			require.NotNil(t, operation)
+			assert.Equal(t, flowspec.StatusSuccess, operation.Status)
+			assert.Equal(t, 1, operation.SampleCount)
+		})
+	}
+}
+
+func TestVerify_TraceDirectory(t *testing.T) {
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "service-spec.yaml", contract)
+	traceDir := filepath.Join(dir, "traces")
+	require.NoError(t, os.Mkdir(traceDir, 0755))
+	writeFile(t, traceDir, "a.json", otlpTrace)
+	failing := strings.Replace(flowspecTrace, `"http.status_code": 200`, `"http.status_code": 500`, 1)
+	writeFile(t, traceDir, "b.json", strings.ReplaceAll(failing, "1234567890abcdef1234567890abcdef", "ffff567890abcdef1234567890abcdef"))
+
+	traces, err := flowspec.LoadTraces(traceDir, 2)
+	require.NoError(t, err)
+	require.Len(t, traces, 2)
+
+	config := flowspec.DefaultConfig()
+	config.TraceConcurrency = 2
+	report, err := flowspec.Verify(context.Background(), specPath, traceDir, config)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 2)
+	assert.Equal(t, []flowspec.TraceSummary{
+		{TraceID: "1234567890abcdef1234567890abcdef", Spans: 1, Total: 1, Success: 1},
+		{TraceID: "ffff567890abcdef1234567890abcdef", Spans: 1, Total: 1, Failed: 1},
+	}, report.Traces)
+	assert.Equal(t, "ffff567890abcdef1234567890abcdef", report.Results[1].TraceID)
+	assert.False(t, flowspec.Passed(report))
+}
+
+func TestLoadSpec_Errors(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "broken.yaml", "apiVersion: flowspec/v1alpha1\nkind: ServiceSpec\nspec: [\n")
+
+	_, err := flowspec.LoadSpec(path)
+	var specError *flowspec.SpecError
+	require.ErrorAs(t, err, &specError)
+	assert.NotEmpty(t, specError.Errors)
+	assert.Equal(t, flowspec.ErrorCode("FS-PARSE-002"), flowspec.ErrorCodeOf(err))
+
+	_, err = flowspec.LoadSpec(filepath.Join(dir, "missing.yaml"))
+	assert.Equal(t, flowspec.ErrorCode("FS-PARSE-001"), flowspec.ErrorCodeOf(err))
+}
+
+func TestLoadTrace_ErrorCodes(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := flowspec.LoadTrace(filepath.Join(dir, "missing.json"))
+	assert.Equal(t, flowspec.ErrorCode("FS-TRACE-001"), flowspec.ErrorCodeOf(err))
+
+	_, err = flowspec.LoadTrace(writeFile(t, dir, "broken.json", `{"resourceSpans": [`))
+	assert.Equal(t, flowspec.ErrorCode("FS-TRACE-003"), flowspec.ErrorCodeOf(err))
+
+	assert.Equal(t, flowspec.ErrorCode(""), flowspec.ErrorCodeOf(nil))
+}
+
+func TestEngine_Align(t *testing.T) {
+	dir := t.TempDir()
+	specs, err := flowspec.LoadSpec(writeFile(t, dir, "service-spec.yaml", contract))
+	require.NoError(t, err)
+
+	engine := flowspec.NewEngine(flowspec.DefaultConfig())
+	defer engine.Close()
+	_, err = engine.Align(context.Background(), specs, nil)
+	assert.Error(t, err)
+
+	trace, err := flowspec.LoadTrace(writeFile(t, dir, "trace.json", strings.Replace(flowspecTrace, `"http.status_code": 200`, `"http.status_code": 500`, 1)))
+	require.NoError(t, err)
+	report, err := engine.Align(context.Background(), specs, trace)
+	require.NoError(t, err)
+	assert.Equal(t, flowspec.StatusFailed, report.Results[0].OperationResults["GET /health"].Status)
+	assert.False(t, flowspec.Passed(report))
+	assert.False(t, flowspec.Passed(nil))
+}
+
+func TestRecordFixtures(t *testing.T) {
+	dir := t.TempDir()
+	specPath := writeFile(t, dir, "service-spec.yaml", contract)
+	tracePath := writeFile(t, dir, "trace.json", otlpTrace)
+
+	specs, err := flowspec.LoadSpec(specPath)
+	require.NoError(t, err)
+	trace, err := flowspec.LoadTrace(tracePath)
+	require.NoError(t, err)
+	engine := flowspec.NewEngine(flowspec.DefaultConfig())
+	defer engine.Close()
+	report, err := engine.Align(context.Background(), specs, trace)
+	require.NoError(t, err)
+
+	fixtureDir := filepath.Join(dir, "fixtures")
+	fixtures, err := flowspec.RecordFixtures(report, trace, fixtureDir)
+	require.NoError(t, err)
+	assert.Equal(t, []flowspec.Fixture{{File: "health_service_GET_health.trace.json", Service: "health-service", Operation: "GET /health", Spans: 1}}, fixtures)
+
+	replay, err := flowspec.Verify(context.Background(), specPath, fixtureDir, flowspec.DefaultConfig())
+	require.NoError(t, err)
+	operation := replay.Results[0].OperationResults["GET /health"]
+	require.NotNil(t, operation)
+	assert.Equal(t, flowspec.StatusSuccess, operation.Status)
+	assert.Equal(t, 1, operation.SampleCount)
+}
+
+func Example() {
+	dir, _ := os.MkdirTemp("", "flowspec-example")
+	defer os.RemoveAll(dir)
+	specPath := filepath.Join(dir, "service-spec.yaml")
+	tracePath := filepath.Join(dir, "trace.json")
+	_ = os.WriteFile(specPath, []byte(contract), 0644)
+	_ = os.WriteFile(tracePath, []byte(otlpTrace), 0644)
+
+	specs, err := flowspec.LoadSpec(specPath)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	trace, err := flowspec.LoadTrace(tracePath)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	engine := flowspec.NewEngine(flowspec.DefaultConfig())
+	defer engine.Close()
+	report, err := engine.Align(context.Background(), specs, trace)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for key, operation := range report.Results[0].OperationResults {
+		fmt.Println(key, operation.Status)
+	}
+	// Output: GET /health SUCCESS
+}